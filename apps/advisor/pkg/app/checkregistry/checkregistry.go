@@ -2,15 +2,29 @@ package checkregistry
 
 import (
 	"github.com/grafana/grafana/apps/advisor/pkg/app/checks"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks/alertrulecheck"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks/apikeycheck"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks/dashboardcheck"
 	"github.com/grafana/grafana/apps/advisor/pkg/app/checks/datasourcecheck"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks/librarypanelcheck"
 	"github.com/grafana/grafana/apps/advisor/pkg/app/checks/plugincheck"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks/securitycheck"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/repo"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/ngalert"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/managedplugins"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugininstaller"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type CheckService interface {
@@ -25,11 +39,23 @@ type Service struct {
 	pluginRepo            repo.Service
 	pluginPreinstall      plugininstaller.Preinstall
 	managedPlugins        managedplugins.Manager
+	cfg                   *setting.Cfg
+	userService           user.Service
+	apiKeyService         apikey.Service
+	serviceAccountService serviceaccounts.Service
+	ruleStore             *ngstore.DBstore
+	alertNG               *ngalert.AlertNG
+	dashboardService      dashboards.DashboardService
+	orgService            org.Service
+	libraryElementService libraryelements.Service
 }
 
 func ProvideService(datasourceSvc datasources.DataSourceService, pluginStore pluginstore.Store,
 	pluginContextProvider *plugincontext.Provider, pluginClient plugins.Client,
-	pluginRepo repo.Service, pluginPreinstall plugininstaller.Preinstall, managedPlugins managedplugins.Manager) *Service {
+	pluginRepo repo.Service, pluginPreinstall plugininstaller.Preinstall, managedPlugins managedplugins.Manager,
+	cfg *setting.Cfg, userService user.Service, apiKeyService apikey.Service, serviceAccountService serviceaccounts.Service,
+	ruleStore *ngstore.DBstore, alertNG *ngalert.AlertNG, dashboardService dashboards.DashboardService,
+	orgService org.Service, libraryElementService libraryelements.Service) *Service {
 	return &Service{
 		datasourceSvc:         datasourceSvc,
 		pluginStore:           pluginStore,
@@ -38,6 +64,15 @@ func ProvideService(datasourceSvc datasources.DataSourceService, pluginStore plu
 		pluginRepo:            pluginRepo,
 		pluginPreinstall:      pluginPreinstall,
 		managedPlugins:        managedPlugins,
+		cfg:                   cfg,
+		userService:           userService,
+		apiKeyService:         apiKeyService,
+		serviceAccountService: serviceAccountService,
+		ruleStore:             ruleStore,
+		alertNG:               alertNG,
+		dashboardService:      dashboardService,
+		orgService:            orgService,
+		libraryElementService: libraryElementService,
 	}
 }
 
@@ -55,6 +90,26 @@ func (s *Service) Checks() []checks.Check {
 			s.pluginPreinstall,
 			s.managedPlugins,
 		),
+		securitycheck.New(
+			s.cfg,
+			s.userService,
+		),
+		apikeycheck.New(
+			s.apiKeyService,
+			s.serviceAccountService,
+		),
+		alertrulecheck.New(
+			s.ruleStore,
+			s.alertNG,
+		),
+		dashboardcheck.New(
+			s.dashboardService,
+			s.datasourceSvc,
+		),
+		librarypanelcheck.New(
+			s.orgService,
+			s.libraryElementService,
+		),
 	}
 }
 
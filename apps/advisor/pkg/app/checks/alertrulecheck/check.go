@@ -0,0 +1,113 @@
+package alertrulecheck
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func New(
+	ruleStore ruleStore,
+	stateReader stateReader,
+) checks.Check {
+	return &check{
+		RuleStore:   ruleStore,
+		StateReader: stateReader,
+	}
+}
+
+type check struct {
+	RuleStore   ruleStore
+	StateReader stateReader
+}
+
+func (c *check) ID() string {
+	return "alertrule"
+}
+
+func (c *check) Items(ctx context.Context) ([]any, error) {
+	// A negative OrgID lists the rules of every organization
+	rules, err := c.RuleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: -1})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]any, 0, len(rules))
+	for _, r := range rules {
+		if r.IsPaused {
+			continue
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+func (c *check) Steps() []checks.Step {
+	return []checks.Step{
+		&evaluationStep{StateReader: c.StateReader},
+	}
+}
+
+type evaluationStep struct {
+	StateReader stateReader
+}
+
+func (s *evaluationStep) ID() string {
+	return "evaluation"
+}
+
+func (s *evaluationStep) Title() string {
+	return "Evaluation errors"
+}
+
+func (s *evaluationStep) Description() string {
+	return "Check if alert rules are evaluated without errors."
+}
+
+func (s *evaluationStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	rule, ok := i.(*models.AlertRule)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+
+	for _, st := range s.StateReader.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+		if !failsToEvaluate(st) {
+			continue
+		}
+		reason := fmt.Sprintf("Alert rule %s fails to evaluate", rule.Title)
+		if st.Error != nil {
+			reason = fmt.Sprintf("%s: %s", reason, st.Error)
+		}
+		return checks.NewCheckReportFailure(
+			advisor.CheckReportFailureSeverityHigh,
+			reason,
+			fmt.Sprintf("Go to the <a href='/alerting/grafana/%s/view'>alert rule</a> and fix its queries or data sources.", rule.UID),
+			s.ID(),
+			rule.UID,
+		), nil
+	}
+	return nil, nil
+}
+
+// failsToEvaluate returns true if the last evaluation of the alert failed. Depending on the error handling of the
+// rule, the alert is then in the Error state, or in another state with the Error reason.
+func failsToEvaluate(st *state.State) bool {
+	if st.State == eval.Error || st.Error != nil {
+		return true
+	}
+	return slices.Contains(strings.Split(st.StateReason, ", "), models.StateReasonError)
+}
+
+type ruleStore interface {
+	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
+}
+
+type stateReader interface {
+	GetStatesForRuleUID(orgID int64, ruleUID string) []*state.State
+}
@@ -0,0 +1,76 @@
+package alertrulecheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck_Run(t *testing.T) {
+	rules := models.RulesGroup{
+		{OrgID: 1, UID: "healthy", Title: "Healthy"},
+		{OrgID: 1, UID: "broken", Title: "Broken"},
+		{OrgID: 1, UID: "alerting-on-error", Title: "Alerting on error"},
+		{OrgID: 1, UID: "keep-last-on-error", Title: "Keep last on error"},
+		{OrgID: 2, UID: "paused", Title: "Paused", IsPaused: true},
+	}
+	states := map[string][]*state.State{
+		"healthy": {{State: eval.Normal}, {State: eval.Alerting}},
+		"broken":  {{State: eval.Normal}, {State: eval.Error, Error: errors.New("data source not found")}},
+		"paused":  {{State: eval.Error}},
+		"alerting-on-error": {
+			{State: eval.Alerting, StateReason: models.StateReasonError, Error: errors.New("query timeout")},
+		},
+		"keep-last-on-error": {
+			{State: eval.Normal, StateReason: models.ConcatReasons(models.StateReasonError, models.StateReasonKeepLast)},
+		},
+	}
+
+	c := New(&fakeRuleStore{rules: rules}, &fakeStateReader{states: states})
+
+	ctx := context.Background()
+	items, err := c.Items(ctx)
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+
+	failures := []advisor.CheckReportFailure{}
+	for _, step := range c.Steps() {
+		for _, item := range items {
+			failure, err := step.Run(ctx, &advisor.CheckSpec{}, item)
+			require.NoError(t, err)
+			if failure != nil {
+				failures = append(failures, *failure)
+			}
+		}
+	}
+	require.Len(t, failures, 3)
+	assert.Equal(t, "broken", failures[0].ItemID)
+	assert.Equal(t, "Alert rule Broken fails to evaluate: data source not found", failures[0].Reason)
+	assert.Equal(t, advisor.CheckReportFailureSeverityHigh, failures[0].Severity)
+	assert.Equal(t, "alerting-on-error", failures[1].ItemID)
+	assert.Equal(t, "Alert rule Alerting on error fails to evaluate: query timeout", failures[1].Reason)
+	assert.Equal(t, "keep-last-on-error", failures[2].ItemID)
+}
+
+type fakeRuleStore struct {
+	rules models.RulesGroup
+}
+
+func (f *fakeRuleStore) ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error) {
+	return f.rules, nil
+}
+
+type fakeStateReader struct {
+	states map[string][]*state.State
+}
+
+func (f *fakeStateReader) GetStatesForRuleUID(orgID int64, ruleUID string) []*state.State {
+	return f.states[ruleUID]
+}
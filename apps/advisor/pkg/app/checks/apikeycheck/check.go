@@ -0,0 +1,158 @@
+package apikeycheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+// unusedGracePeriod is how long a new key can stay unused before being reported
+const unusedGracePeriod = 30 * 24 * time.Hour
+
+func New(
+	apiKeyService apikey.Service,
+	serviceAccountService serviceaccounts.Service,
+) checks.Check {
+	return &check{
+		APIKeyService:         apiKeyService,
+		ServiceAccountService: serviceAccountService,
+		now:                   time.Now,
+	}
+}
+
+type check struct {
+	APIKeyService         apikey.Service
+	ServiceAccountService serviceaccounts.Service
+
+	now func() time.Time
+}
+
+func (c *check) ID() string {
+	return "apikey"
+}
+
+// Items returns the API keys and service account tokens of every organization that can still be used.
+func (c *check) Items(ctx context.Context) ([]any, error) {
+	keys, err := c.APIKeyService.GetAllAPIKeys(ctx, -1)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := c.ServiceAccountService.ListTokens(ctx, &serviceaccounts.GetSATokensQuery{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range tokens {
+		keys = append(keys, &tokens[i])
+	}
+
+	now := c.now()
+	res := make([]any, 0, len(keys))
+	for _, k := range keys {
+		if (k.IsRevoked != nil && *k.IsRevoked) || (k.Expires != nil && time.Unix(*k.Expires, 0).Before(now)) {
+			continue
+		}
+		res = append(res, k)
+	}
+	return res, nil
+}
+
+func (c *check) Steps() []checks.Step {
+	return []checks.Step{
+		&unusedStep{now: c.now},
+		&expiryStep{},
+	}
+}
+
+type unusedStep struct {
+	now func() time.Time
+}
+
+func (s *unusedStep) ID() string {
+	return "unused"
+}
+
+func (s *unusedStep) Title() string {
+	return "Unused keys"
+}
+
+func (s *unusedStep) Description() string {
+	return "Check if API keys and service account tokens have been used since they were created."
+}
+
+func (s *unusedStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	k, ok := i.(*apikey.APIKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+	if k.LastUsedAt != nil || s.now().Sub(k.Created) < unusedGracePeriod {
+		return nil, nil
+	}
+	return checks.NewCheckReportFailure(
+		advisor.CheckReportFailureSeverityLow,
+		fmt.Sprintf("%s has never been used since %s", describe(k), k.Created.Format(time.DateOnly)),
+		fmt.Sprintf("Delete it from the %s if it isn't needed anymore.", manageLink(k)),
+		s.ID(),
+		itemID(k),
+	), nil
+}
+
+type expiryStep struct{}
+
+func (s *expiryStep) ID() string {
+	return "expiry"
+}
+
+func (s *expiryStep) Title() string {
+	return "Expiration"
+}
+
+func (s *expiryStep) Description() string {
+	return "Check if API keys and service account tokens have an expiration date."
+}
+
+func (s *expiryStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	k, ok := i.(*apikey.APIKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+	if k.Expires != nil {
+		return nil, nil
+	}
+	severity := advisor.CheckReportFailureSeverityLow
+	action := fmt.Sprintf("Replace it with a token that expires from the %s.", manageLink(k))
+	if k.ServiceAccountId == nil {
+		// API keys are deprecated, they should be migrated rather than rotated.
+		severity = advisor.CheckReportFailureSeverityHigh
+		action = fmt.Sprintf("Migrate it to a service account from the %s, and give the new token an expiration date.", manageLink(k))
+	}
+	return checks.NewCheckReportFailure(
+		severity,
+		fmt.Sprintf("%s never expires", describe(k)),
+		action,
+		s.ID(),
+		itemID(k),
+	), nil
+}
+
+func describe(k *apikey.APIKey) string {
+	if k.ServiceAccountId != nil {
+		return fmt.Sprintf("Service account token %s (organization %d)", k.Name, k.OrgID)
+	}
+	return fmt.Sprintf("API key %s (organization %d)", k.Name, k.OrgID)
+}
+
+func manageLink(k *apikey.APIKey) string {
+	if k.ServiceAccountId != nil {
+		return fmt.Sprintf("<a href='/org/serviceaccounts/%d'>service account page</a>", *k.ServiceAccountId)
+	}
+	return "<a href='/org/apikeys'>API keys page</a>"
+}
+
+func itemID(k *apikey.APIKey) string {
+	return fmt.Sprintf("%d", k.ID)
+}
@@ -0,0 +1,82 @@
+package apikeycheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck_Run(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Hour)
+	old := now.Add(-60 * 24 * time.Hour)
+	expires := now.Add(24 * time.Hour).Unix()
+	expired := now.Add(-time.Hour).Unix()
+	revoked := true
+	saID := int64(10)
+
+	keys := []*apikey.APIKey{
+		{ID: 1, OrgID: 1, Name: "used", Created: old, LastUsedAt: &recent, Expires: &expires},
+		{ID: 2, OrgID: 1, Name: "new", Created: recent, Expires: &expires},
+		{ID: 3, OrgID: 2, Name: "forever", Created: old, LastUsedAt: &recent},
+		{ID: 4, OrgID: 1, Name: "expired", Created: old, Expires: &expired},
+		{ID: 5, OrgID: 1, Name: "revoked", Created: old, IsRevoked: &revoked},
+	}
+	tokens := []apikey.APIKey{
+		{ID: 6, OrgID: 1, Name: "sa-token", Created: old, Expires: &expires, ServiceAccountId: &saID},
+	}
+
+	c := &check{
+		APIKeyService:         &fakeAPIKeyService{keys: keys},
+		ServiceAccountService: &fakeServiceAccountService{tokens: tokens},
+		now:                   func() time.Time { return now },
+	}
+
+	ctx := context.Background()
+	items, err := c.Items(ctx)
+	require.NoError(t, err)
+	assert.Len(t, items, 4)
+
+	failures := map[string][]string{}
+	for _, step := range c.Steps() {
+		for _, item := range items {
+			failure, err := step.Run(ctx, &advisor.CheckSpec{}, item)
+			require.NoError(t, err)
+			if failure != nil {
+				failures[step.ID()] = append(failures[step.ID()], failure.ItemID)
+				if failure.ItemID == "3" {
+					assert.Equal(t, advisor.CheckReportFailureSeverityHigh, failure.Severity)
+					assert.Equal(t, "API key forever (organization 2) never expires", failure.Reason)
+				}
+				if failure.ItemID == "6" {
+					assert.Contains(t, failure.Action, "/org/serviceaccounts/10")
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string][]string{"unused": {"6"}, "expiry": {"3"}}, failures)
+}
+
+type fakeAPIKeyService struct {
+	apikey.Service
+	keys []*apikey.APIKey
+}
+
+func (f *fakeAPIKeyService) GetAllAPIKeys(ctx context.Context, orgID int64) ([]*apikey.APIKey, error) {
+	return f.keys, nil
+}
+
+type fakeServiceAccountService struct {
+	serviceaccounts.Service
+	tokens []apikey.APIKey
+}
+
+func (f *fakeServiceAccountService) ListTokens(ctx context.Context, query *serviceaccounts.GetSATokensQuery) ([]apikey.APIKey, error) {
+	return f.tokens, nil
+}
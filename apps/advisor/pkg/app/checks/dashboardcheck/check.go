@@ -0,0 +1,174 @@
+package dashboardcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// builtInDatasources are the data sources that don't exist in the database
+var builtInDatasources = map[string]bool{
+	"grafana":         true,
+	"-- Grafana --":   true,
+	"-- Mixed --":     true,
+	"-- Dashboard --": true,
+	"__expr__":        true,
+	"-100":            true,
+}
+
+func New(
+	dashboardService dashboards.DashboardService,
+	datasourceSvc datasources.DataSourceService,
+) checks.Check {
+	return &check{
+		DashboardService: dashboardService,
+		DatasourceSvc:    datasourceSvc,
+	}
+}
+
+type check struct {
+	DashboardService dashboards.DashboardService
+	DatasourceSvc    datasources.DataSourceService
+}
+
+func (c *check) ID() string {
+	return "dashboard"
+}
+
+func (c *check) Items(ctx context.Context) ([]any, error) {
+	dashs, err := c.DashboardService.GetAllDashboards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]any, 0, len(dashs))
+	for _, d := range dashs {
+		if d.IsFolder {
+			continue
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+func (c *check) Steps() []checks.Step {
+	return []checks.Step{
+		&missingDatasourceStep{DatasourceSvc: c.DatasourceSvc},
+	}
+}
+
+type missingDatasourceStep struct {
+	DatasourceSvc datasources.DataSourceService
+}
+
+func (s *missingDatasourceStep) ID() string {
+	return "missing-datasource"
+}
+
+func (s *missingDatasourceStep) Title() string {
+	return "Missing data sources"
+}
+
+func (s *missingDatasourceStep) Description() string {
+	return "Check if dashboards use data sources that don't exist."
+}
+
+func (s *missingDatasourceStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	dash, ok := i.(*dashboards.Dashboard)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+	if dash.Data == nil {
+		return nil, nil
+	}
+
+	refs := map[datasourceRef]bool{}
+	collectDatasourceRefs(dash.Data.Interface(), refs)
+
+	missing := []string{}
+	for ref := range refs {
+		found, err := s.exists(ctx, dash.OrgID, ref)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			missing = append(missing, ref.value)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	sort.Strings(missing)
+
+	return checks.NewCheckReportFailure(
+		advisor.CheckReportFailureSeverityHigh,
+		fmt.Sprintf("Dashboard %s uses missing data sources: %s", dash.Title, strings.Join(missing, ", ")),
+		fmt.Sprintf("Go to the <a href='/d/%s'>dashboard</a> and select existing data sources in the affected panels and variables.", dash.UID),
+		s.ID(),
+		dash.UID,
+	), nil
+}
+
+func (s *missingDatasourceStep) exists(ctx context.Context, orgID int64, ref datasourceRef) (bool, error) {
+	_, err := s.DatasourceSvc.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: ref.value, OrgID: orgID})
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, datasources.ErrDataSourceNotFound) {
+		return false, err
+	}
+	if !ref.legacy {
+		return false, nil
+	}
+	// Old dashboards reference data sources by name
+	_, err = s.DatasourceSvc.GetDataSource(ctx, &datasources.GetDataSourceQuery{Name: ref.value, OrgID: orgID})
+	if errors.Is(err, datasources.ErrDataSourceNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+type datasourceRef struct {
+	value string
+	// legacy is set for references that are plain strings, they may be a name or a UID
+	legacy bool
+}
+
+// collectDatasourceRefs walks the dashboard model and collects the data sources of the panels,
+// queries, variables and annotations. Template variables and built-in data sources are skipped.
+func collectDatasourceRefs(v any, refs map[datasourceRef]bool) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if k != "datasource" {
+				collectDatasourceRefs(child, refs)
+				continue
+			}
+			switch ds := child.(type) {
+			case string:
+				addRef(datasourceRef{value: ds, legacy: true}, refs)
+			case map[string]any:
+				if uid, ok := ds["uid"].(string); ok {
+					addRef(datasourceRef{value: uid}, refs)
+				}
+			}
+		}
+	case []any:
+		for _, child := range val {
+			collectDatasourceRefs(child, refs)
+		}
+	}
+}
+
+func addRef(ref datasourceRef, refs map[datasourceRef]bool) {
+	if ref.value == "" || strings.HasPrefix(ref.value, "$") || builtInDatasources[ref.value] {
+		return
+	}
+	refs[ref] = true
+}
@@ -0,0 +1,67 @@
+package dashboardcheck
+
+import (
+	"context"
+	"testing"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck_Run(t *testing.T) {
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetAllDashboards", context.Background()).Return([]*dashboards.Dashboard{
+		{UID: "folder", Title: "Folder", IsFolder: true},
+		{UID: "healthy", Title: "Healthy", OrgID: 1, Data: simplejson.NewFromAny(map[string]any{
+			"panels": []any{
+				map[string]any{"datasource": map[string]any{"uid": "prom", "type": "prometheus"}},
+				map[string]any{"datasource": "Loki"},
+				map[string]any{"datasource": map[string]any{"uid": "${ds}"}},
+				map[string]any{"datasource": map[string]any{"uid": "-- Mixed --"}, "targets": []any{
+					map[string]any{"datasource": map[string]any{"uid": "__expr__"}},
+				}},
+			},
+		})},
+		{UID: "broken", Title: "Broken", OrgID: 1, Data: simplejson.NewFromAny(map[string]any{
+			"panels": []any{
+				map[string]any{"type": "row", "panels": []any{
+					map[string]any{"targets": []any{map[string]any{"datasource": map[string]any{"uid": "deleted"}}}},
+				}},
+			},
+			"templating": map[string]any{"list": []any{
+				map[string]any{"datasource": "Old InfluxDB"},
+			}},
+		})},
+	}, nil)
+
+	datasourceSvc := &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		{UID: "prom", Name: "Prometheus", OrgID: 1},
+		{UID: "loki", Name: "Loki", OrgID: 1},
+	}}
+
+	c := New(dashboardService, datasourceSvc)
+
+	ctx := context.Background()
+	items, err := c.Items(ctx)
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	failures := []advisor.CheckReportFailure{}
+	for _, step := range c.Steps() {
+		for _, item := range items {
+			failure, err := step.Run(ctx, &advisor.CheckSpec{}, item)
+			require.NoError(t, err)
+			if failure != nil {
+				failures = append(failures, *failure)
+			}
+		}
+	}
+	require.Len(t, failures, 1)
+	assert.Equal(t, "broken", failures[0].ItemID)
+	assert.Equal(t, "Dashboard Broken uses missing data sources: Old InfluxDB, deleted", failures[0].Reason)
+}
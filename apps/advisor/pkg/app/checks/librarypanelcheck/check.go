@@ -0,0 +1,105 @@
+package librarypanelcheck
+
+import (
+	"context"
+	"fmt"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const pageSize = 100
+
+func New(
+	orgService org.Service,
+	libraryElementService libraryelements.Service,
+) checks.Check {
+	return &check{
+		OrgService:            orgService,
+		LibraryElementService: libraryElementService,
+	}
+}
+
+type check struct {
+	OrgService            org.Service
+	LibraryElementService libraryelements.Service
+}
+
+func (c *check) ID() string {
+	return "librarypanel"
+}
+
+// Items returns the library panels of every organization.
+func (c *check) Items(ctx context.Context) ([]any, error) {
+	orgs, err := c.OrgService.Search(ctx, &org.SearchOrgsQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	res := []any{}
+	for _, o := range orgs {
+		requester := accesscontrol.BackgroundUser("advisor_library_panels", o.ID, org.RoleNone, []accesscontrol.Permission{
+			{Action: libraryelements.ActionLibraryPanelsRead, Scope: libraryelements.ScopeLibraryPanelsAll},
+			{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+		})
+		for page := 1; ; page++ {
+			result, err := c.LibraryElementService.GetAllElements(ctx, requester, model.SearchLibraryElementsQuery{
+				PerPage: pageSize,
+				Page:    page,
+				Kind:    int(model.PanelElement),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list library panels of organization %d: %w", o.ID, err)
+			}
+			for i := range result.Elements {
+				res = append(res, &result.Elements[i])
+			}
+			if len(result.Elements) < pageSize {
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+func (c *check) Steps() []checks.Step {
+	return []checks.Step{
+		&orphanedStep{},
+	}
+}
+
+type orphanedStep struct{}
+
+func (s *orphanedStep) ID() string {
+	return "orphaned"
+}
+
+func (s *orphanedStep) Title() string {
+	return "Unused library panels"
+}
+
+func (s *orphanedStep) Description() string {
+	return "Check if library panels are used by at least one dashboard."
+}
+
+func (s *orphanedStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	panel, ok := i.(*model.LibraryElementDTO)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+	if panel.Meta.ConnectedDashboards > 0 {
+		return nil, nil
+	}
+	return checks.NewCheckReportFailure(
+		advisor.CheckReportFailureSeverityLow,
+		fmt.Sprintf("Library panel %s (organization %d) isn't used by any dashboard", panel.Name, panel.OrgID),
+		"Delete it from the <a href='/library-panels'>library panels page</a> if it isn't needed anymore.",
+		s.ID(),
+		panel.UID,
+	), nil
+}
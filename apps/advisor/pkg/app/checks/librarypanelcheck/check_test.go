@@ -0,0 +1,64 @@
+package librarypanelcheck
+
+import (
+	"context"
+	"testing"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck_Run(t *testing.T) {
+	orgService := &orgtest.FakeOrgService{ExpectedOrgs: []*org.OrgDTO{{ID: 1}, {ID: 2}}}
+	elements := map[int64][]model.LibraryElementDTO{
+		1: make([]model.LibraryElementDTO, pageSize),
+		2: {{UID: "orphan", Name: "Orphan", OrgID: 2}},
+	}
+	for i := range elements[1] {
+		elements[1][i] = model.LibraryElementDTO{UID: "used", OrgID: 1, Meta: model.LibraryElementDTOMeta{ConnectedDashboards: 2}}
+	}
+	elementService := &fakeLibraryElementService{elements: elements}
+
+	c := New(orgService, elementService)
+
+	ctx := context.Background()
+	items, err := c.Items(ctx)
+	require.NoError(t, err)
+	assert.Len(t, items, pageSize+1)
+	// The first organization has a full page, the next one is requested
+	assert.Equal(t, []int{1, 2, 1}, elementService.pages)
+
+	failures := []advisor.CheckReportFailure{}
+	for _, step := range c.Steps() {
+		for _, item := range items {
+			failure, err := step.Run(ctx, &advisor.CheckSpec{}, item)
+			require.NoError(t, err)
+			if failure != nil {
+				failures = append(failures, *failure)
+			}
+		}
+	}
+	require.Len(t, failures, 1)
+	assert.Equal(t, "orphan", failures[0].ItemID)
+	assert.Equal(t, "Library panel Orphan (organization 2) isn't used by any dashboard", failures[0].Reason)
+}
+
+type fakeLibraryElementService struct {
+	libraryelements.Service
+	elements map[int64][]model.LibraryElementDTO
+	pages    []int
+}
+
+func (f *fakeLibraryElementService) GetAllElements(c context.Context, signedInUser identity.Requester, query model.SearchLibraryElementsQuery) (model.LibraryElementSearchResult, error) {
+	f.pages = append(f.pages, query.Page)
+	if query.Page > 1 {
+		return model.LibraryElementSearchResult{}, nil
+	}
+	return model.LibraryElementSearchResult{Elements: f.elements[signedInUser.GetOrgID()]}, nil
+}
@@ -0,0 +1,211 @@
+package securitycheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/apps/advisor/pkg/app/checks"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// defaultSecretKey is the secret_key shipped in defaults.ini
+	defaultSecretKey = "SW2YcwTIb9zpOOhoPsMm"
+	// minSecretKeyLength is the length under which a secret_key is considered weak
+	minSecretKeyLength = 16
+	// defaultAdminPassword is the password of the admin user created on first start
+	defaultAdminPassword = "admin"
+)
+
+func New(cfg *setting.Cfg, userService user.Service) checks.Check {
+	return &check{
+		Cfg:         cfg,
+		UserService: userService,
+	}
+}
+
+type check struct {
+	Cfg         *setting.Cfg
+	UserService user.Service
+}
+
+func (c *check) ID() string {
+	return "security"
+}
+
+// Items returns the configuration of the instance, every step inspects a different part of it.
+func (c *check) Items(ctx context.Context) ([]any, error) {
+	return []any{c.Cfg}, nil
+}
+
+func (c *check) Steps() []checks.Step {
+	return []checks.Step{
+		&anonymousAdminStep{},
+		&defaultAdminPasswordStep{UserService: c.UserService},
+		&cookieSecureStep{},
+		&secretKeyStep{},
+	}
+}
+
+type anonymousAdminStep struct{}
+
+func (s *anonymousAdminStep) ID() string {
+	return "anonymous-admin"
+}
+
+func (s *anonymousAdminStep) Title() string {
+	return "Anonymous admin access"
+}
+
+func (s *anonymousAdminStep) Description() string {
+	return "Check if anonymous users are granted the Admin role."
+}
+
+func (s *anonymousAdminStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	cfg, ok := i.(*setting.Cfg)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+	if !cfg.Anonymous.Enabled || org.RoleType(cfg.Anonymous.OrgRole) != org.RoleAdmin {
+		return nil, nil
+	}
+	return checks.NewCheckReportFailure(
+		advisor.CheckReportFailureSeverityHigh,
+		fmt.Sprintf("Anonymous users are administrators of the organization %s", cfg.Anonymous.OrgName),
+		"Set <code>org_role</code> to <code>Viewer</code> in the <code>[auth.anonymous]</code> section of the configuration, or disable anonymous access.",
+		s.ID(),
+		"auth.anonymous.org_role",
+	), nil
+}
+
+type defaultAdminPasswordStep struct {
+	UserService user.Service
+}
+
+func (s *defaultAdminPasswordStep) ID() string {
+	return "default-admin-password"
+}
+
+func (s *defaultAdminPasswordStep) Title() string {
+	return "Default admin password"
+}
+
+func (s *defaultAdminPasswordStep) Description() string {
+	return "Check if the admin user still has the default password."
+}
+
+func (s *defaultAdminPasswordStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	cfg, ok := i.(*setting.Cfg)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+	if cfg.DisableLoginForm || cfg.AdminUser == "" {
+		return nil, nil
+	}
+
+	admin, err := s.UserService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: cfg.AdminUser})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if admin.Password == "" || admin.IsDisabled {
+		return nil, nil
+	}
+	hashed, err := util.EncodePassword(defaultAdminPassword, admin.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if hashed != string(admin.Password) {
+		return nil, nil
+	}
+	return checks.NewCheckReportFailure(
+		advisor.CheckReportFailureSeverityHigh,
+		fmt.Sprintf("The user %s has the default password", admin.Login),
+		fmt.Sprintf("<a href='/admin/users/edit/%s'>Change the password</a> of the user.", admin.UID),
+		s.ID(),
+		admin.Login,
+	), nil
+}
+
+type cookieSecureStep struct{}
+
+func (s *cookieSecureStep) ID() string {
+	return "cookie-secure"
+}
+
+func (s *cookieSecureStep) Title() string {
+	return "Secure cookies"
+}
+
+func (s *cookieSecureStep) Description() string {
+	return "Check if cookies are only sent over HTTPS."
+}
+
+func (s *cookieSecureStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	cfg, ok := i.(*setting.Cfg)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+	if cfg.CookieSecure {
+		return nil, nil
+	}
+	severity := advisor.CheckReportFailureSeverityLow
+	if strings.HasPrefix(cfg.AppURL, "https://") {
+		// Served over HTTPS, secure cookies can be enabled right away.
+		severity = advisor.CheckReportFailureSeverityHigh
+	}
+	return checks.NewCheckReportFailure(
+		severity,
+		"Cookies can be sent over unencrypted connections",
+		"Serve Grafana over HTTPS and set <code>cookie_secure</code> to <code>true</code> in the <code>[security]</code> section of the configuration.",
+		s.ID(),
+		"security.cookie_secure",
+	), nil
+}
+
+type secretKeyStep struct{}
+
+func (s *secretKeyStep) ID() string {
+	return "secret-key"
+}
+
+func (s *secretKeyStep) Title() string {
+	return "Secret key"
+}
+
+func (s *secretKeyStep) Description() string {
+	return "Check if the key used to encrypt secrets is strong."
+}
+
+func (s *secretKeyStep) Run(ctx context.Context, obj *advisor.CheckSpec, i any) (*advisor.CheckReportFailure, error) {
+	cfg, ok := i.(*setting.Cfg)
+	if !ok {
+		return nil, fmt.Errorf("invalid item type %T", i)
+	}
+	var reason string
+	switch {
+	case cfg.SecretKey == defaultSecretKey:
+		reason = "The secret key is the default one"
+	case len(cfg.SecretKey) < minSecretKeyLength:
+		reason = fmt.Sprintf("The secret key is shorter than %d characters", minSecretKeyLength)
+	default:
+		return nil, nil
+	}
+	return checks.NewCheckReportFailure(
+		advisor.CheckReportFailureSeverityHigh,
+		reason,
+		"Rotate the <code>secret_key</code> of the <code>[security]</code> section following the "+
+			"<a href='https://grafana.com/docs/grafana/latest/setup-grafana/configure-security/configure-database-encryption/' target=_blank>documentation</a>, "+
+			"secrets encrypted with the current key must be re-encrypted.",
+		s.ID(),
+		"security.secret_key",
+	), nil
+}
@@ -0,0 +1,92 @@
+package securitycheck
+
+import (
+	"context"
+	"testing"
+
+	advisor "github.com/grafana/grafana/apps/advisor/pkg/apis/advisor/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck_Run(t *testing.T) {
+	t.Run("should return no failures when the configuration is secure", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.AdminUser = "admin"
+		cfg.SecretKey = "a-long-and-random-secret-key"
+		cfg.CookieSecure = true
+		cfg.Anonymous.Enabled = true
+		cfg.Anonymous.OrgRole = "Viewer"
+
+		failures := runCheck(t, cfg, adminUser(t, "s3cr3t!"))
+		assert.Empty(t, failures)
+	})
+
+	t.Run("should return failures for insecure settings", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.AdminUser = "admin"
+		cfg.AppURL = "https://grafana.example.com/"
+		cfg.SecretKey = defaultSecretKey
+		cfg.Anonymous.Enabled = true
+		cfg.Anonymous.OrgRole = "Admin"
+
+		failures := runCheck(t, cfg, adminUser(t, defaultAdminPassword))
+		steps := map[string]advisor.CheckReportFailure{}
+		for _, f := range failures {
+			steps[f.StepID] = f
+		}
+		require.Len(t, steps, 4)
+		assert.Equal(t, "auth.anonymous.org_role", steps["anonymous-admin"].ItemID)
+		assert.Equal(t, "admin", steps["default-admin-password"].ItemID)
+		assert.Equal(t, advisor.CheckReportFailureSeverityHigh, steps["cookie-secure"].Severity)
+		assert.Equal(t, "The secret key is the default one", steps["secret-key"].Reason)
+	})
+
+	t.Run("should report short secret keys", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.SecretKey = "short"
+		cfg.CookieSecure = true
+
+		failures := runCheck(t, cfg, nil)
+		require.Len(t, failures, 1)
+		assert.Equal(t, "secret-key", failures[0].StepID)
+		assert.Equal(t, "The secret key is shorter than 16 characters", failures[0].Reason)
+	})
+}
+
+func runCheck(t *testing.T, cfg *setting.Cfg, admin *user.User) []advisor.CheckReportFailure {
+	t.Helper()
+
+	userService := &usertest.FakeUserService{ExpectedUser: admin}
+	if admin == nil {
+		userService.ExpectedError = user.ErrUserNotFound
+	}
+	check := New(cfg, userService)
+
+	ctx := identity.WithRequester(context.Background(), &user.SignedInUser{})
+	items, err := check.Items(ctx)
+	require.NoError(t, err)
+	failures := []advisor.CheckReportFailure{}
+	for _, step := range check.Steps() {
+		for _, item := range items {
+			stepFailures, err := step.Run(ctx, &advisor.CheckSpec{}, item)
+			require.NoError(t, err)
+			if stepFailures != nil {
+				failures = append(failures, *stepFailures)
+			}
+		}
+	}
+	return failures
+}
+
+func adminUser(t *testing.T, password string) *user.User {
+	t.Helper()
+	hashed, err := util.EncodePassword(password, "salt")
+	require.NoError(t, err)
+	return &user.User{ID: 1, UID: "admin-uid", Login: "admin", Salt: "salt", Password: user.Password(hashed)}
+}
@@ -79,8 +79,42 @@ investigation: {
 					to:   number
 				}
 
+				// Snapshot holds the frozen result of an investigation item.
+				#Snapshot: {
+					// itemID is the ID of the item the snapshot was taken for.
+					itemID: string
+					// queryHash identifies the query and time range that produced the snapshot.
+					queryHash: string
+					// takenAt is the RFC3339 time the snapshot was taken.
+					takenAt: string
+					// state is the outcome of the query.
+					state: "ok" | "error"
+					// error (optional) is the reason the query failed.
+					error?: string
+					// frames (optional) are the data frames returned by the query.
+					frames?: [..._]
+				}
+
+				// TimelineEvent is something that happened during an investigation.
+				#TimelineEvent: {
+					// time is the RFC3339 time of the event.
+					time: string
+					// type is the type of the event, e.g. "item-added", "snapshot-taken" or "status-changed".
+					type: string
+					// itemID (optional) is the item the event relates to.
+					itemID?: string
+					// message is a human readable description of the event.
+					message: string
+				}
+
 				// spec is the schema of our resource. The spec should include all the user-ediable information for the kind.
 				spec: #InvestigationSpec
+				status: {
+					// snapshots holds the frozen results of the items, so they stay viewable after the data ages out.
+					snapshots?: [...#Snapshot]
+					// timeline records the history of the investigation.
+					timeline?: [...#TimelineEvent]
+				}
 			}
 		}
 	}
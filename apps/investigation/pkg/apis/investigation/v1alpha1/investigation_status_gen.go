@@ -2,6 +2,46 @@
 
 package v1alpha1
 
+// Snapshot holds the frozen result of an investigation item.
+// +k8s:openapi-gen=true
+type InvestigationSnapshot struct {
+	// itemID is the ID of the item the snapshot was taken for.
+	ItemID string `json:"itemID"`
+	// queryHash identifies the query and time range that produced the snapshot.
+	QueryHash string `json:"queryHash"`
+	// takenAt is the RFC3339 time the snapshot was taken.
+	TakenAt string `json:"takenAt"`
+	// state is the outcome of the query.
+	State InvestigationSnapshotState `json:"state"`
+	// error (optional) is the reason the query failed.
+	Error *string `json:"error,omitempty"`
+	// frames (optional) are the data frames returned by the query.
+	Frames []interface{} `json:"frames,omitempty"`
+}
+
+// NewInvestigationSnapshot creates a new InvestigationSnapshot object.
+func NewInvestigationSnapshot() *InvestigationSnapshot {
+	return &InvestigationSnapshot{}
+}
+
+// TimelineEvent is something that happened during an investigation.
+// +k8s:openapi-gen=true
+type InvestigationTimelineEvent struct {
+	// time is the RFC3339 time of the event.
+	Time string `json:"time"`
+	// type is the type of the event, e.g. "item-added", "snapshot-taken" or "status-changed".
+	Type string `json:"type"`
+	// itemID (optional) is the item the event relates to.
+	ItemID *string `json:"itemID,omitempty"`
+	// message is a human readable description of the event.
+	Message string `json:"message"`
+}
+
+// NewInvestigationTimelineEvent creates a new InvestigationTimelineEvent object.
+func NewInvestigationTimelineEvent() *InvestigationTimelineEvent {
+	return &InvestigationTimelineEvent{}
+}
+
 // +k8s:openapi-gen=true
 type InvestigationstatusOperatorState struct {
 	// lastEvaluation is the ResourceVersion last evaluated
@@ -22,6 +62,10 @@ func NewInvestigationstatusOperatorState() *InvestigationstatusOperatorState {
 
 // +k8s:openapi-gen=true
 type InvestigationStatus struct {
+	// snapshots holds the frozen results of the items, so they stay viewable after the data ages out.
+	Snapshots []InvestigationSnapshot `json:"snapshots,omitempty"`
+	// timeline records the history of the investigation.
+	Timeline []InvestigationTimelineEvent `json:"timeline,omitempty"`
 	// operatorStates is a map of operator ID to operator state evaluations.
 	// Any operator which consumes this kind SHOULD add its state evaluation information to this field.
 	OperatorStates map[string]InvestigationstatusOperatorState `json:"operatorStates,omitempty"`
@@ -34,6 +78,14 @@ func NewInvestigationStatus() *InvestigationStatus {
 	return &InvestigationStatus{}
 }
 
+// +k8s:openapi-gen=true
+type InvestigationSnapshotState string
+
+const (
+	InvestigationSnapshotStateOk    InvestigationSnapshotState = "ok"
+	InvestigationSnapshotStateError InvestigationSnapshotState = "error"
+)
+
 // +k8s:openapi-gen=true
 type InvestigationStatusOperatorStateState string
 
@@ -20,8 +20,10 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationDatasourceRef":       schema_pkg_apis_investigation_v1alpha1_InvestigationDatasourceRef(ref),
 		"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationInvestigationItem":   schema_pkg_apis_investigation_v1alpha1_InvestigationInvestigationItem(ref),
 		"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationList":                schema_pkg_apis_investigation_v1alpha1_InvestigationList(ref),
+		"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationSnapshot":            schema_pkg_apis_investigation_v1alpha1_InvestigationSnapshot(ref),
 		"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationSpec":                schema_pkg_apis_investigation_v1alpha1_InvestigationSpec(ref),
 		"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationStatus":              schema_pkg_apis_investigation_v1alpha1_InvestigationStatus(ref),
+		"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationTimelineEvent":       schema_pkg_apis_investigation_v1alpha1_InvestigationTimelineEvent(ref),
 		"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationstatusOperatorState": schema_pkg_apis_investigation_v1alpha1_InvestigationstatusOperatorState(ref),
 	}
 }
@@ -398,6 +400,73 @@ func schema_pkg_apis_investigation_v1alpha1_InvestigationList(ref common.Referen
 	}
 }
 
+func schema_pkg_apis_investigation_v1alpha1_InvestigationSnapshot(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Snapshot holds the frozen result of an investigation item.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"itemID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "itemID is the ID of the item the snapshot was taken for.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"queryHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "queryHash identifies the query and time range that produced the snapshot.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"takenAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "takenAt is the RFC3339 time the snapshot was taken.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "state is the outcome of the query.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "error (optional) is the reason the query failed.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"frames": {
+						SchemaProps: spec.SchemaProps{
+							Description: "frames (optional) are the data frames returned by the query.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"object"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"itemID", "queryHash", "takenAt", "state"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_investigation_v1alpha1_InvestigationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -447,6 +516,34 @@ func schema_pkg_apis_investigation_v1alpha1_InvestigationStatus(ref common.Refer
 			SchemaProps: spec.SchemaProps{
 				Type: []string{"object"},
 				Properties: map[string]spec.Schema{
+					"snapshots": {
+						SchemaProps: spec.SchemaProps{
+							Description: "snapshots holds the frozen results of the items, so they stay viewable after the data ages out.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationSnapshot"),
+									},
+								},
+							},
+						},
+					},
+					"timeline": {
+						SchemaProps: spec.SchemaProps{
+							Description: "timeline records the history of the investigation.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationTimelineEvent"),
+									},
+								},
+							},
+						},
+					},
 					"operatorStates": {
 						SchemaProps: spec.SchemaProps{
 							Description: "operatorStates is a map of operator ID to operator state evaluations. Any operator which consumes this kind SHOULD add its state evaluation information to this field.",
@@ -481,7 +578,52 @@ func schema_pkg_apis_investigation_v1alpha1_InvestigationStatus(ref common.Refer
 			},
 		},
 		Dependencies: []string{
-			"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationstatusOperatorState"},
+			"github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationSnapshot", "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationTimelineEvent", "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1.InvestigationstatusOperatorState"},
+	}
+}
+
+func schema_pkg_apis_investigation_v1alpha1_InvestigationTimelineEvent(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "TimelineEvent is something that happened during an investigation.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Description: "time is the RFC3339 time of the event.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "type is the type of the event, e.g. \"item-added\", \"snapshot-taken\" or \"status-changed\".",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"itemID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "itemID (optional) is the item the event relates to.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "message is a human readable description of the event.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"time", "type", "message"},
+			},
+		},
 	}
 }
 
@@ -2,8 +2,10 @@ package app
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-app-sdk/app"
+	"github.com/grafana/grafana-app-sdk/k8s"
 	"github.com/grafana/grafana-app-sdk/resource"
 	"github.com/grafana/grafana-app-sdk/simple"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -12,8 +14,36 @@ import (
 	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
 )
 
+// InvestigationAppConfig is the app specific configuration of the investigation app.
+type InvestigationAppConfig struct {
+	// QueryRunner is used to snapshot the items of investigations. Snapshots are disabled when it's nil.
+	QueryRunner QueryRunner
+}
+
 func New(cfg app.Config) (app.App, error) {
 	var err error
+	investigationKind := simple.AppManagedKind{
+		Kind: investigationv1alpha1.InvestigationKind(),
+	}
+
+	specificConfig, _ := cfg.SpecificConfig.(InvestigationAppConfig)
+	if specificConfig.QueryRunner != nil {
+		clientGenerator := k8s.NewClientRegistry(cfg.KubeConfig, k8s.ClientConfig{})
+		client, err := clientGenerator.ClientFor(investigationv1alpha1.InvestigationKind())
+		if err != nil {
+			return nil, err
+		}
+		r := &reconciler{client: client, runner: specificConfig.QueryRunner, now: time.Now}
+		investigationKind.Watcher = &simple.Watcher{
+			AddFunc: func(ctx context.Context, obj resource.Object) error {
+				return r.reconcile(ctx, obj, nil)
+			},
+			UpdateFunc: func(ctx context.Context, src resource.Object, tgt resource.Object) error {
+				return r.reconcile(ctx, tgt, src)
+			},
+		}
+	}
+
 	simpleConfig := simple.AppConfig{
 		Name:       "investigation",
 		KubeConfig: cfg.KubeConfig,
@@ -23,9 +53,7 @@ func New(cfg app.Config) (app.App, error) {
 			},
 		},
 		ManagedKinds: []simple.AppManagedKind{
-			{
-				Kind: investigationv1alpha1.InvestigationKind(),
-			},
+			investigationKind,
 		},
 	}
 
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
+)
+
+// maxPostmortemRows is the number of rows of each frame rendered in a postmortem.
+const maxPostmortemRows = 10
+
+// frameJSON is the subset of the JSON encoding of a data frame needed to render it.
+type frameJSON struct {
+	Schema struct {
+		Name   string `json:"name"`
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	} `json:"schema"`
+	Data struct {
+		Values [][]any `json:"values"`
+	} `json:"data"`
+}
+
+// RenderPostmortem renders an investigation, its timeline and the frozen evidence as a markdown document.
+func RenderPostmortem(inv *investigationv1alpha1.Investigation) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", inv.Spec.Title)
+	fmt.Fprintf(&b, "- **Status:** %s\n", inv.Spec.Status)
+	if created := inv.GetCreationTimestamp(); !created.IsZero() {
+		fmt.Fprintf(&b, "- **Created:** %s\n", created.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "- **Items:** %d\n", len(inv.Spec.Items))
+
+	if len(inv.InvestigationStatus.Timeline) > 0 {
+		b.WriteString("\n## Timeline\n\n| Time | Event |\n| --- | --- |\n")
+		for _, e := range inv.InvestigationStatus.Timeline {
+			fmt.Fprintf(&b, "| %s | %s |\n", e.Time, escapeCell(e.Message))
+		}
+	}
+
+	snapshots := make(map[string]investigationv1alpha1.InvestigationSnapshot, len(inv.InvestigationStatus.Snapshots))
+	for _, s := range inv.InvestigationStatus.Snapshots {
+		snapshots[s.ItemID] = s
+	}
+
+	if len(inv.Spec.Items) > 0 {
+		b.WriteString("\n## Evidence\n")
+	}
+	for _, item := range inv.Spec.Items {
+		fmt.Fprintf(&b, "\n### %s\n\n", item.Title)
+		fmt.Fprintf(&b, "- **Type:** %s (%s)\n", item.Type, item.Origin)
+		fmt.Fprintf(&b, "- **Time range:** %s to %s\n", formatMillis(item.TimeRange.From), formatMillis(item.TimeRange.To))
+		if expr, datasource := describeQuery(item.DataQuery); expr != "" {
+			fmt.Fprintf(&b, "- **Query:** `%s`", expr)
+			if datasource != "" {
+				fmt.Fprintf(&b, " on %s", datasource)
+			}
+			b.WriteString("\n")
+		}
+		if item.Url != "" {
+			fmt.Fprintf(&b, "- **Link:** %s\n", item.Url)
+		}
+
+		for _, note := range item.Note {
+			fmt.Fprintf(&b, "\n> %s\n>\n> — %s\n", strings.ReplaceAll(note.BodyMarkdown, "\n", "\n> "), note.AuthorUserID)
+		}
+
+		snapshot, ok := snapshots[item.Id]
+		switch {
+		case !ok:
+			b.WriteString("\n_No snapshot was taken for this item._\n")
+		case snapshot.State == investigationv1alpha1.InvestigationSnapshotStateError:
+			msg := ""
+			if snapshot.Error != nil {
+				msg = *snapshot.Error
+			}
+			fmt.Fprintf(&b, "\n_Snapshot failed at %s: %s_\n", snapshot.TakenAt, msg)
+		default:
+			fmt.Fprintf(&b, "\nSnapshot taken at %s.\n", snapshot.TakenAt)
+			renderFrames(&b, snapshot.Frames)
+		}
+	}
+
+	return b.String()
+}
+
+func renderFrames(b *strings.Builder, frames []any) {
+	for _, raw := range frames {
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			continue
+		}
+		var frame frameJSON
+		decoder := json.NewDecoder(bytes.NewReader(encoded))
+		// Keep numbers as they are, timestamps would be rendered in scientific notation otherwise
+		decoder.UseNumber()
+		if err := decoder.Decode(&frame); err != nil || len(frame.Schema.Fields) == 0 {
+			continue
+		}
+
+		rows := 0
+		if len(frame.Data.Values) > 0 {
+			rows = len(frame.Data.Values[0])
+		}
+		name := frame.Schema.Name
+		if name == "" {
+			name = "Frame"
+		}
+		fmt.Fprintf(b, "\n**%s** (%d rows)\n\n", escapeCell(name), rows)
+
+		header := make([]string, len(frame.Schema.Fields))
+		separator := make([]string, len(frame.Schema.Fields))
+		for i, f := range frame.Schema.Fields {
+			header[i] = escapeCell(f.Name)
+			separator[i] = "---"
+		}
+		fmt.Fprintf(b, "| %s |\n| %s |\n", strings.Join(header, " | "), strings.Join(separator, " | "))
+		for row := 0; row < rows && row < maxPostmortemRows; row++ {
+			cells := make([]string, len(frame.Schema.Fields))
+			for col := range frame.Schema.Fields {
+				if col < len(frame.Data.Values) && row < len(frame.Data.Values[col]) {
+					cells[col] = escapeCell(fmt.Sprint(frame.Data.Values[col][row]))
+				}
+			}
+			fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+		}
+		if rows > maxPostmortemRows {
+			fmt.Fprintf(b, "\n_%d more rows are not shown._\n", rows-maxPostmortemRows)
+		}
+	}
+}
+
+// describeQuery returns the expression and data source name of a data query.
+func describeQuery(dataQuery any) (string, string) {
+	encoded, err := json.Marshal(dataQuery)
+	if err != nil {
+		return "", ""
+	}
+	var q investigationv1alpha1.InvestigationDataQueryMetrics
+	if err := json.Unmarshal(encoded, &q); err != nil {
+		return "", ""
+	}
+	datasource := q.Datasource.Name
+	if datasource == "" {
+		datasource = q.Datasource.Uid
+	}
+	return q.Expr, datasource
+}
+
+func formatMillis(ms float64) string {
+	return time.UnixMilli(int64(ms)).UTC().Format(time.RFC3339)
+}
+
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
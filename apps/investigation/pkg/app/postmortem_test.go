@@ -0,0 +1,60 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
+)
+
+func TestRenderPostmortem(t *testing.T) {
+	errMsg := "data source not found"
+	itemID := "a"
+	inv := newInvestigation(item("a", "sum(rate(errors[5m]))"), item("b", "up"), item("c", "down"))
+	inv.Spec.Status = investigationv1alpha1.InvestigationSpecStatusClosed
+	inv.Spec.Items[0].Note = []investigationv1alpha1.InvestigationComment{{AuthorUserID: "jdoe", BodyMarkdown: "Errors started\nright after the deploy"}}
+	inv.InvestigationStatus = investigationv1alpha1.InvestigationStatus{
+		Timeline: []investigationv1alpha1.InvestigationTimelineEvent{
+			{Time: "2025-02-01T10:00:00Z", Type: EventItemAdded, ItemID: &itemID, Message: `Item "Item a" was added`},
+		},
+		Snapshots: []investigationv1alpha1.InvestigationSnapshot{
+			{ItemID: "a", TakenAt: "2025-02-01T10:00:00Z", State: investigationv1alpha1.InvestigationSnapshotStateOk, Frames: []any{
+				map[string]any{
+					"schema": map[string]any{"name": "errors", "fields": []any{map[string]any{"name": "time"}, map[string]any{"name": "value|count"}}},
+					"data":   map[string]any{"values": []any{[]any{1738400000000, 1738400060000}, []any{3, 12}}},
+				},
+			}},
+			{ItemID: "b", TakenAt: "2025-02-01T10:00:00Z", State: investigationv1alpha1.InvestigationSnapshotStateError, Error: &errMsg},
+		},
+	}
+
+	expected := "# Checkout errors\n\n" +
+		"- **Status:** closed\n" +
+		"- **Items:** 3\n" +
+		"\n## Timeline\n\n| Time | Event |\n| --- | --- |\n" +
+		"| 2025-02-01T10:00:00Z | Item \"Item a\" was added |\n" +
+		"\n## Evidence\n" +
+		"\n### Item a\n\n" +
+		"- **Type:** timeseries (explore-metrics)\n" +
+		"- **Time range:** 2025-02-01T08:53:20Z to 2025-02-01T09:53:20Z\n" +
+		"- **Query:** `sum(rate(errors[5m]))` on Prometheus\n" +
+		"\n> Errors started\n> right after the deploy\n>\n> — jdoe\n" +
+		"\nSnapshot taken at 2025-02-01T10:00:00Z.\n" +
+		"\n**errors** (2 rows)\n\n" +
+		"| time | value\\|count |\n| --- | --- |\n" +
+		"| 1738400000000 | 3 |\n" +
+		"| 1738400060000 | 12 |\n" +
+		"\n### Item b\n\n" +
+		"- **Type:** timeseries (explore-metrics)\n" +
+		"- **Time range:** 2025-02-01T08:53:20Z to 2025-02-01T09:53:20Z\n" +
+		"- **Query:** `up` on Prometheus\n" +
+		"\n_Snapshot failed at 2025-02-01T10:00:00Z: data source not found_\n" +
+		"\n### Item c\n\n" +
+		"- **Type:** timeseries (explore-metrics)\n" +
+		"- **Time range:** 2025-02-01T08:53:20Z to 2025-02-01T09:53:20Z\n" +
+		"- **Query:** `down` on Prometheus\n" +
+		"\n_No snapshot was taken for this item._\n"
+
+	assert.Equal(t, expected, RenderPostmortem(inv))
+}
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-app-sdk/resource"
+
+	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
+)
+
+const (
+	// maxSnapshotSize is the maximum size of the encoded frames of a single item, and maxSnapshotsSize
+	// the maximum size of the frames of all the items of an investigation. Snapshots are stored in the
+	// status of the investigation, bigger results are recorded as failed snapshots so it stays storable.
+	maxSnapshotSize  = 512 * 1024
+	maxSnapshotsSize = 1024 * 1024
+	// maxTimelineEvents is the number of events kept in the timeline, the oldest ones are dropped.
+	maxTimelineEvents = 1000
+
+	EventInvestigationCreated = "investigation-created"
+	EventStatusChanged        = "status-changed"
+	EventItemAdded            = "item-added"
+	EventItemRemoved          = "item-removed"
+	EventSnapshotTaken        = "snapshot-taken"
+	EventSnapshotFailed       = "snapshot-failed"
+)
+
+// QueryRunner executes the query of an investigation item and returns the resulting data frames.
+type QueryRunner interface {
+	RunQuery(ctx context.Context, investigation *investigationv1alpha1.Investigation, item investigationv1alpha1.InvestigationInvestigationItem) ([]any, error)
+}
+
+// reconciler freezes the results of the investigation items in the status of the investigation
+// and records what happened to it in a timeline.
+type reconciler struct {
+	client resource.Client
+	runner QueryRunner
+	now    func() time.Time
+}
+
+func (r *reconciler) reconcile(ctx context.Context, obj resource.Object, previous resource.Object) error {
+	inv, ok := obj.(*investigationv1alpha1.Investigation)
+	if !ok {
+		return fmt.Errorf("invalid object type %T", obj)
+	}
+	var prev *investigationv1alpha1.Investigation
+	if previous != nil {
+		prev, ok = previous.(*investigationv1alpha1.Investigation)
+		if !ok {
+			return fmt.Errorf("invalid object type %T", previous)
+		}
+	}
+
+	status, changed := r.computeStatus(ctx, inv, prev)
+	if !changed {
+		// Nothing to do, this also stops the update triggered by our own patch
+		return nil
+	}
+	return r.client.PatchInto(ctx, inv.GetStaticMetadata().Identifier(), resource.PatchRequest{
+		Operations: []resource.PatchOperation{{
+			Operation: resource.PatchOpAdd,
+			Path:      "/status/snapshots",
+			Value:     status.Snapshots,
+		}, {
+			Operation: resource.PatchOpAdd,
+			Path:      "/status/timeline",
+			Value:     status.Timeline,
+		}},
+	}, resource.PatchOptions{}, inv)
+}
+
+// computeStatus returns the status the investigation should have, and whether it differs from the current one.
+// Items are only queried when they are new or their query or time range changed.
+func (r *reconciler) computeStatus(ctx context.Context, inv *investigationv1alpha1.Investigation, prev *investigationv1alpha1.Investigation) (investigationv1alpha1.InvestigationStatus, bool) {
+	now := r.now().UTC().Format(time.RFC3339)
+	changed := false
+
+	timeline := append([]investigationv1alpha1.InvestigationTimelineEvent{}, inv.InvestigationStatus.Timeline...)
+	addEvent := func(eventType string, itemID string, message string) {
+		event := investigationv1alpha1.InvestigationTimelineEvent{Time: now, Type: eventType, Message: message}
+		if itemID != "" {
+			event.ItemID = &itemID
+		}
+		timeline = append(timeline, event)
+		changed = true
+	}
+
+	if len(timeline) == 0 {
+		addEvent(EventInvestigationCreated, "", fmt.Sprintf("Investigation %q was created", inv.Spec.Title))
+	}
+	if prev != nil && prev.Spec.Status != inv.Spec.Status {
+		message := "Investigation was reopened"
+		if inv.Spec.Status == investigationv1alpha1.InvestigationSpecStatusClosed {
+			message = "Investigation was closed"
+		}
+		addEvent(EventStatusChanged, "", message)
+	}
+
+	existing := make(map[string]investigationv1alpha1.InvestigationSnapshot, len(inv.InvestigationStatus.Snapshots))
+	for _, s := range inv.InvestigationStatus.Snapshots {
+		existing[s.ItemID] = s
+	}
+
+	hashes := make(map[string]string, len(inv.Spec.Items))
+	budget := maxSnapshotsSize
+	for _, item := range inv.Spec.Items {
+		hash, err := itemHash(item)
+		if err != nil {
+			hash = ""
+		}
+		hashes[item.Id] = hash
+		if current, found := existing[item.Id]; found && current.QueryHash == hash {
+			budget -= framesSize(current.Frames)
+		}
+	}
+
+	snapshots := make([]investigationv1alpha1.InvestigationSnapshot, 0, len(inv.Spec.Items))
+	inSpec := make(map[string]bool, len(inv.Spec.Items))
+	for _, item := range inv.Spec.Items {
+		inSpec[item.Id] = true
+		hash := hashes[item.Id]
+		current, found := existing[item.Id]
+		if found && current.QueryHash == hash {
+			// Failed snapshots are not retried until the item changes, to avoid a patch loop
+			snapshots = append(snapshots, current)
+			continue
+		}
+		if !found {
+			addEvent(EventItemAdded, item.Id, fmt.Sprintf("Item %q was added", item.Title))
+		}
+
+		snapshot := r.takeSnapshot(ctx, inv, item, hash, now, budget)
+		budget -= framesSize(snapshot.Frames)
+		if snapshot.State == investigationv1alpha1.InvestigationSnapshotStateOk {
+			addEvent(EventSnapshotTaken, item.Id, fmt.Sprintf("Captured %d frames for %q", len(snapshot.Frames), item.Title))
+		} else {
+			addEvent(EventSnapshotFailed, item.Id, fmt.Sprintf("Failed to capture %q: %s", item.Title, *snapshot.Error))
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	for _, s := range inv.InvestigationStatus.Snapshots {
+		if !inSpec[s.ItemID] {
+			addEvent(EventItemRemoved, s.ItemID, fmt.Sprintf("Item %s was removed", s.ItemID))
+		}
+	}
+
+	if len(timeline) > maxTimelineEvents {
+		timeline = timeline[len(timeline)-maxTimelineEvents:]
+	}
+
+	status := inv.InvestigationStatus
+	status.Snapshots = snapshots
+	status.Timeline = timeline
+	return status, changed
+}
+
+// takeSnapshot queries the item and records its frames, if they fit in the remaining budget of the investigation.
+func (r *reconciler) takeSnapshot(ctx context.Context, inv *investigationv1alpha1.Investigation, item investigationv1alpha1.InvestigationInvestigationItem, hash string, now string, budget int) investigationv1alpha1.InvestigationSnapshot {
+	snapshot := investigationv1alpha1.InvestigationSnapshot{
+		ItemID:    item.Id,
+		QueryHash: hash,
+		TakenAt:   now,
+		State:     investigationv1alpha1.InvestigationSnapshotStateOk,
+	}
+	fail := func(err error) investigationv1alpha1.InvestigationSnapshot {
+		msg := err.Error()
+		snapshot.State = investigationv1alpha1.InvestigationSnapshotStateError
+		snapshot.Error = &msg
+		snapshot.Frames = nil
+		return snapshot
+	}
+
+	if hash == "" {
+		return fail(fmt.Errorf("invalid data query"))
+	}
+	frames, err := r.runner.RunQuery(ctx, inv, item)
+	if err != nil {
+		return fail(err)
+	}
+	encoded, err := json.Marshal(frames)
+	if err != nil {
+		return fail(err)
+	}
+	if len(encoded) > maxSnapshotSize {
+		return fail(fmt.Errorf("result is too large (%d bytes, limit is %d)", len(encoded), maxSnapshotSize))
+	}
+	if len(encoded) > budget {
+		return fail(fmt.Errorf("the snapshots of the investigation would exceed %d bytes, remove items to capture this one", maxSnapshotsSize))
+	}
+	snapshot.Frames = frames
+	return snapshot
+}
+
+// framesSize returns the size of the encoded frames of a snapshot.
+func framesSize(frames []any) int {
+	if len(frames) == 0 {
+		return 0
+	}
+	encoded, err := json.Marshal(frames)
+	if err != nil {
+		return 0
+	}
+	return len(encoded)
+}
+
+// itemHash identifies what has to be queried for an item, so edits of the title or notes don't trigger new snapshots.
+func itemHash(item investigationv1alpha1.InvestigationInvestigationItem) (string, error) {
+	b, err := json.Marshal(struct {
+		QueryType string                                               `json:"queryType"`
+		DataQuery any                                                  `json:"dataQuery"`
+		TimeRange investigationv1alpha1.InvestigationAbsoluteTimeRange `json:"timeRange"`
+	}{
+		QueryType: string(item.QueryType),
+		DataQuery: item.DataQuery,
+		TimeRange: item.TimeRange,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-app-sdk/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
+)
+
+func TestReconciler(t *testing.T) {
+	now := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+	newReconciler := func(runner *fakeQueryRunner) (*reconciler, *mockClient) {
+		client := &mockClient{}
+		return &reconciler{client: client, runner: runner, now: func() time.Time { return now }}, client
+	}
+
+	t.Run("should snapshot new items and record the timeline", func(t *testing.T) {
+		runner := &fakeQueryRunner{frames: []any{map[string]any{"schema": map[string]any{"name": "A"}}}}
+		r, client := newReconciler(runner)
+		inv := newInvestigation(item("a", "up"))
+
+		require.NoError(t, r.reconcile(context.Background(), inv, nil))
+
+		status := client.status()
+		require.Len(t, status.Snapshots, 1)
+		assert.Equal(t, "a", status.Snapshots[0].ItemID)
+		assert.Equal(t, investigationv1alpha1.InvestigationSnapshotStateOk, status.Snapshots[0].State)
+		assert.Equal(t, "2025-02-01T10:00:00Z", status.Snapshots[0].TakenAt)
+		assert.Len(t, status.Snapshots[0].Frames, 1)
+		assert.Equal(t, []string{EventInvestigationCreated, EventItemAdded, EventSnapshotTaken}, eventTypes(status))
+		assert.Equal(t, 1, runner.calls)
+	})
+
+	t.Run("should not query or patch unchanged items", func(t *testing.T) {
+		runner := &fakeQueryRunner{}
+		r, client := newReconciler(runner)
+		inv := newInvestigation(item("a", "up"))
+		require.NoError(t, r.reconcile(context.Background(), inv, nil))
+		inv.InvestigationStatus = client.status()
+		client.operations = nil
+
+		updated := inv.Copy().(*investigationv1alpha1.Investigation)
+		updated.Spec.Items[0].Title = "Renamed"
+		require.NoError(t, r.reconcile(context.Background(), updated, inv))
+
+		assert.Nil(t, client.operations)
+		assert.Equal(t, 1, runner.calls)
+	})
+
+	t.Run("should re-snapshot changed items and forget removed ones", func(t *testing.T) {
+		runner := &fakeQueryRunner{}
+		r, client := newReconciler(runner)
+		inv := newInvestigation(item("a", "up"), item("b", "rate(errors[5m])"))
+		require.NoError(t, r.reconcile(context.Background(), inv, nil))
+		inv.InvestigationStatus = client.status()
+
+		updated := inv.Copy().(*investigationv1alpha1.Investigation)
+		updated.Spec.Items = updated.Spec.Items[:1]
+		updated.Spec.Items[0].TimeRange.To += 60000
+		updated.Spec.Status = investigationv1alpha1.InvestigationSpecStatusClosed
+		require.NoError(t, r.reconcile(context.Background(), updated, inv))
+
+		status := client.status()
+		require.Len(t, status.Snapshots, 1)
+		assert.Equal(t, "a", status.Snapshots[0].ItemID)
+		assert.Equal(t, []string{EventStatusChanged, EventSnapshotTaken, EventItemRemoved}, eventTypes(status)[5:])
+		assert.Equal(t, "Investigation was closed", status.Timeline[5].Message)
+		assert.Equal(t, 3, runner.calls)
+	})
+
+	t.Run("should record failed snapshots without retrying them", func(t *testing.T) {
+		runner := &fakeQueryRunner{err: errors.New("data source not found")}
+		r, client := newReconciler(runner)
+		inv := newInvestigation(item("a", "up"))
+		require.NoError(t, r.reconcile(context.Background(), inv, nil))
+
+		status := client.status()
+		require.Len(t, status.Snapshots, 1)
+		assert.Equal(t, investigationv1alpha1.InvestigationSnapshotStateError, status.Snapshots[0].State)
+		assert.Equal(t, "data source not found", *status.Snapshots[0].Error)
+		assert.Equal(t, `Failed to capture "Item a": data source not found`, status.Timeline[2].Message)
+
+		inv.InvestigationStatus = status
+		client.operations = nil
+		require.NoError(t, r.reconcile(context.Background(), inv, inv))
+		assert.Nil(t, client.operations)
+		assert.Equal(t, 1, runner.calls)
+	})
+
+	t.Run("should reject results that are too large", func(t *testing.T) {
+		runner := &fakeQueryRunner{frames: []any{strings.Repeat("x", maxSnapshotSize)}}
+		r, client := newReconciler(runner)
+		require.NoError(t, r.reconcile(context.Background(), newInvestigation(item("a", "up")), nil))
+
+		status := client.status()
+		assert.Equal(t, investigationv1alpha1.InvestigationSnapshotStateError, status.Snapshots[0].State)
+		assert.Contains(t, *status.Snapshots[0].Error, "result is too large")
+		assert.Empty(t, status.Snapshots[0].Frames)
+	})
+
+	t.Run("should cap the size of the snapshots of the investigation", func(t *testing.T) {
+		runner := &fakeQueryRunner{frames: []any{strings.Repeat("x", maxSnapshotsSize/3)}}
+		r, client := newReconciler(runner)
+		inv := newInvestigation(item("a", "up"), item("b", "rate(errors[5m])"))
+		require.NoError(t, r.reconcile(context.Background(), inv, nil))
+		inv.InvestigationStatus = client.status()
+
+		updated := inv.Copy().(*investigationv1alpha1.Investigation)
+		updated.Spec.Items = append(updated.Spec.Items, item("c", "sum(up)"))
+		require.NoError(t, r.reconcile(context.Background(), updated, inv))
+
+		status := client.status()
+		require.Len(t, status.Snapshots, 3)
+		assert.Equal(t, investigationv1alpha1.InvestigationSnapshotStateOk, status.Snapshots[0].State)
+		assert.Equal(t, investigationv1alpha1.InvestigationSnapshotStateOk, status.Snapshots[1].State)
+		assert.Equal(t, investigationv1alpha1.InvestigationSnapshotStateError, status.Snapshots[2].State)
+		assert.Contains(t, *status.Snapshots[2].Error, "would exceed")
+		assert.Empty(t, status.Snapshots[2].Frames)
+	})
+
+	t.Run("should drop the oldest timeline events", func(t *testing.T) {
+		r, client := newReconciler(&fakeQueryRunner{})
+		inv := newInvestigation()
+		for i := 0; i < maxTimelineEvents; i++ {
+			inv.InvestigationStatus.Timeline = append(inv.InvestigationStatus.Timeline, investigationv1alpha1.InvestigationTimelineEvent{Type: EventStatusChanged})
+		}
+
+		updated := inv.Copy().(*investigationv1alpha1.Investigation)
+		updated.Spec.Items = append(updated.Spec.Items, item("a", "up"))
+		require.NoError(t, r.reconcile(context.Background(), updated, inv))
+
+		status := client.status()
+		assert.Len(t, status.Timeline, maxTimelineEvents)
+		assert.Equal(t, EventSnapshotTaken, status.Timeline[len(status.Timeline)-1].Type)
+	})
+}
+
+func newInvestigation(items ...investigationv1alpha1.InvestigationInvestigationItem) *investigationv1alpha1.Investigation {
+	inv := &investigationv1alpha1.Investigation{}
+	inv.SetName("inv")
+	inv.SetNamespace("default")
+	inv.Spec = investigationv1alpha1.InvestigationSpec{
+		Title:  "Checkout errors",
+		Status: investigationv1alpha1.InvestigationSpecStatusOpen,
+		Items:  items,
+	}
+	return inv
+}
+
+func item(id string, expr string) investigationv1alpha1.InvestigationInvestigationItem {
+	return investigationv1alpha1.InvestigationInvestigationItem{
+		Id:        id,
+		Title:     "Item " + id,
+		Type:      "timeseries",
+		Origin:    "explore-metrics",
+		QueryType: investigationv1alpha1.InvestigationInvestigationItemQueryTypeMetrics,
+		TimeRange: investigationv1alpha1.InvestigationAbsoluteTimeRange{From: 1738400000000, To: 1738403600000},
+		DataQuery: investigationv1alpha1.InvestigationDataQueryMetrics{
+			RefId:      "A",
+			Datasource: investigationv1alpha1.InvestigationDatasourceRef{Uid: "prom", Type: "prometheus", Name: "Prometheus"},
+			Expr:       expr,
+		},
+	}
+}
+
+func eventTypes(status investigationv1alpha1.InvestigationStatus) []string {
+	types := make([]string, 0, len(status.Timeline))
+	for _, e := range status.Timeline {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+type fakeQueryRunner struct {
+	frames []any
+	err    error
+	calls  int
+}
+
+func (f *fakeQueryRunner) RunQuery(ctx context.Context, investigation *investigationv1alpha1.Investigation, item investigationv1alpha1.InvestigationInvestigationItem) ([]any, error) {
+	f.calls++
+	return f.frames, f.err
+}
+
+type mockClient struct {
+	resource.Client
+	operations []resource.PatchOperation
+}
+
+func (m *mockClient) PatchInto(ctx context.Context, id resource.Identifier, req resource.PatchRequest, opts resource.PatchOptions, obj resource.Object) error {
+	m.operations = req.Operations
+	return nil
+}
+
+func (m *mockClient) status() investigationv1alpha1.InvestigationStatus {
+	status := investigationv1alpha1.InvestigationStatus{}
+	for _, op := range m.operations {
+		switch op.Path {
+		case "/status/snapshots":
+			status.Snapshots = op.Value.([]investigationv1alpha1.InvestigationSnapshot)
+		case "/status/timeline":
+			status.Timeline = op.Value.([]investigationv1alpha1.InvestigationTimelineEvent)
+		}
+	}
+	return status
+}
@@ -374,6 +374,44 @@ func (hs *HTTPServer) AdminLogoutUser(c *contextmodel.ReqContext) response.Respo
 	return hs.logoutUserFromAllDevicesInternal(c.Req.Context(), userID)
 }
 
+// swagger:route DELETE /admin/users/{user_id}/mfa admin_users adminResetUserMFA
+//
+// Reset the second factors of a user.
+//
+// Removes every second factor and recovery code enrolled by the user, for example after a lost device.
+// The user will be asked to enroll again on the next login if a multi-factor policy applies to them.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users.mfa:reset` and scope `global.users:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) AdminResetUserMFA(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	if _, err := hs.userService.GetByID(c.Req.Context(), &user.GetUserByIDQuery{ID: userID}); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.Error(http.StatusNotFound, user.ErrUserNotFound.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Could not read user from database", err)
+	}
+
+	if err := hs.mfaService.Reset(c.Req.Context(), userID); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reset second factors", err)
+	}
+
+	return response.Success("Second factors reset")
+}
+
 // swagger:route GET /admin/users/{user_id}/auth-tokens admin_users adminGetUserAuthTokens
 //
 // Return a list of all auth tokens (devices) that the user currently have logged in from.
@@ -469,6 +507,13 @@ type AdminLogoutUserParams struct {
 	UserID int64 `json:"user_id"`
 }
 
+// swagger:parameters adminResetUserMFA
+type AdminResetUserMFAParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+}
+
 // swagger:parameters adminRevokeUserAuthToken
 type AdminRevokeUserAuthTokenParams struct {
 	// in:body
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -15,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
 	"github.com/grafana/grafana/pkg/login/social/socialtest"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	"github.com/grafana/grafana/pkg/services/authn"
@@ -22,10 +24,12 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/login/authinfotest"
+	"github.com/grafana/grafana/pkg/services/mfa/mfatest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 const (
@@ -384,6 +388,50 @@ func Test_AdminUpdateUserPermissions(t *testing.T) {
 	}
 }
 
+func Test_AdminResetUserMFA(t *testing.T) {
+	testcases := []struct {
+		name             string
+		userService      *usertest.FakeUserService
+		expectedRespCode int
+		expectedResets   []int64
+	}{
+		{
+			name:             "Should reset the second factors of a user",
+			userService:      &usertest.FakeUserService{ExpectedUser: &user.User{ID: 1}},
+			expectedRespCode: http.StatusOK,
+			expectedResets:   []int64{1},
+		},
+		{
+			name:             "Should return not found for an unknown user",
+			userService:      &usertest.FakeUserService{ExpectedError: user.ErrUserNotFound},
+			expectedRespCode: http.StatusNotFound,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			mfaService := &mfatest.FakeService{}
+			hs := &HTTPServer{
+				Cfg:         setting.NewCfg(),
+				userService: tc.userService,
+				mfaService:  mfaService,
+			}
+
+			sc := setupScenarioContext(t, "/api/admin/users/1/mfa")
+			sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+				sc.context = c
+				return hs.AdminResetUserMFA(c)
+			})
+
+			sc.m.Delete("/api/admin/users/:id/mfa", sc.defaultHandler)
+
+			sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
+
+			assert.Equal(t, tc.expectedRespCode, sc.resp.Code)
+			assert.Equal(t, tc.expectedResets, mfaService.ResetUserIDs)
+		})
+	}
+}
+
 func putAdminScenario(t *testing.T, desc string, url string, routePattern string, role org.RoleType,
 	cmd dtos.AdminUpdateUserPermissionsForm, fn scenarioFunc, sqlStore db.DB, userSvc user.Service) {
 	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
@@ -570,3 +618,43 @@ func adminCreateUserScenario(t *testing.T, desc string, url string, routePattern
 		fn(sc)
 	})
 }
+
+func TestAdminResetUserMFA_AccessControl(t *testing.T) {
+	tests := []struct {
+		desc         string
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}{
+		{
+			desc:         "should reset the second factors with the dedicated action",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersMFAReset, Scope: "global.users:id:1"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not reset the second factors with the permission to update passwords",
+			permissions:  []accesscontrol.Permission{{Action: accesscontrol.ActionUsersPasswordUpdate, Scope: "global.users:*"}},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			mfaService := &mfatest.FakeService{}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.userService = &usertest.FakeUserService{ExpectedUser: &user.User{ID: 1}}
+				hs.mfaService = mfaService
+				hs.authnService = &authntest.FakeService{
+					ExpectedIdentity: &authn.Identity{
+						OrgID:       accesscontrol.GlobalOrgID,
+						Permissions: map[int64]map[string][]string{accesscontrol.GlobalOrgID: accesscontrol.GroupScopesByActionContext(context.Background(), tt.permissions)},
+					},
+				}
+			})
+
+			res, err := server.Send(webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/admin/users/1/mfa", nil), authedUserWithPermissions(2, 1, tt.permissions)))
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+		})
+	}
+}
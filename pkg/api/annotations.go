@@ -582,6 +582,55 @@ func (hs *HTTPServer) GetAnnotationTags(c *contextmodel.ReqContext) response.Res
 	return response.JSON(http.StatusOK, annotations.GetAnnotationTagsResponse{Result: result})
 }
 
+// swagger:route GET /annotations/summary annotations getAnnotationsSummary
+//
+// Summarize Annotations.
+//
+// Returns the number of annotations in each time bucket of the time range, with counts per type and tag, and the merged time spans of region annotations.
+// Use it instead of finding annotations when the time range is too wide to display every annotation.
+//
+// Responses:
+// 200: getAnnotationsSummaryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) GetAnnotationsSummary(c *contextmodel.ReqContext) response.Response {
+	query := &annotations.SummaryQuery{
+		ItemQuery: annotations.ItemQuery{
+			From:         c.QueryInt64("from"),
+			To:           c.QueryInt64("to"),
+			OrgID:        c.SignedInUser.GetOrgID(),
+			UserID:       c.QueryInt64("userId"),
+			AlertID:      c.QueryInt64("alertId"),
+			DashboardID:  c.QueryInt64("dashboardId"),
+			DashboardUID: c.Query("dashboardUID"),
+			PanelID:      c.QueryInt64("panelId"),
+			Tags:         c.QueryStrings("tags"),
+			Type:         c.Query("type"),
+			MatchAny:     c.QueryBool("matchAny"),
+			SignedInUser: c.SignedInUser,
+		},
+		Buckets: c.QueryInt64("buckets"),
+	}
+
+	// When dashboard UID present in the request, we ignore dashboard ID
+	if query.DashboardUID != "" {
+		dq := dashboards.GetDashboardQuery{UID: query.DashboardUID, OrgID: c.SignedInUser.GetOrgID()}
+		dqResult, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dq)
+		if err != nil {
+			return response.Error(http.StatusBadRequest, "Invalid dashboard UID in annotation request", err)
+		}
+		query.DashboardID = dqResult.ID
+	}
+
+	summary, err := hs.annotationsRepo.Summarize(c.Req.Context(), query)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to summarize annotations", err)
+	}
+
+	return response.JSON(http.StatusOK, summary)
+}
+
 // AnnotationTypeScopeResolver provides an ScopeAttributeResolver able to
 // resolve annotation types. Scope "annotations:id:<id>" will be translated to "annotations:type:<type>,
 // where <type> is the type of annotation with id <id>.
@@ -784,6 +833,17 @@ type GetAnnotationsParams struct {
 	MatchAny bool `json:"matchAny"`
 }
 
+// swagger:parameters getAnnotationsSummary
+type GetAnnotationsSummaryParams struct {
+	GetAnnotationsParams
+	// Number of time buckets the time range is split into.
+	// in:query
+	// required:false
+	// default: 100
+	// maximum: 1000
+	Buckets int64 `json:"buckets"`
+}
+
 // swagger:parameters getAnnotationTags
 type GetAnnotationTagsParams struct {
 	// Tag is a string that you can use to filter tags.
@@ -868,6 +928,13 @@ type PostAnnotationResponse struct {
 	} `json:"body"`
 }
 
+// swagger:response getAnnotationsSummaryResponse
+type GetAnnotationsSummaryResponse struct {
+	// The response message
+	// in: body
+	Body annotations.Summary `json:"body"`
+}
+
 // swagger:response getAnnotationTagsResponse
 type GetAnnotationTagsResponse struct {
 	// The response message
@@ -894,6 +894,8 @@ func (hs *HTTPServer) GetDashboardVersions(c *contextmodel.ReqContext) response.
 			Created:       version.Created,
 			Message:       msg,
 			CreatedBy:     creator,
+			Name:          version.Name,
+			Tags:          version.Tags,
 		})
 	}
 
@@ -992,11 +994,91 @@ func (hs *HTTPServer) GetDashboardVersion(c *contextmodel.ReqContext) response.R
 		Created:       res.Created,
 		Message:       res.Message,
 		CreatedBy:     creator,
+		Name:          res.Name,
+		Tags:          res.Tags,
 	}
 
 	return response.JSON(http.StatusOK, dashVersionMeta)
 }
 
+// swagger:route PATCH /dashboards/uid/{uid}/versions/{DashboardVersionID} dashboard_versions labelDashboardVersionByUID
+//
+// Name and tag a dashboard version.
+//
+// Labels replace the previous name and tags of the version. Versions tagged with one of the tags kept by the
+// retention policy of their folder are never deleted.
+//
+// Responses:
+// 200: dashboardVersionResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) LabelDashboardVersion(c *contextmodel.ReqContext) response.Response {
+	ctx, span := tracer.Start(c.Req.Context(), "api.LabelDashboardVersion")
+	defer span.End()
+	c.Req = c.Req.WithContext(ctx)
+
+	apiCmd := dtos.LabelDashboardVersionCommand{}
+	if err := web.Bind(c.Req, &apiCmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	version, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "version is invalid", err)
+	}
+
+	res, err := hs.dashboardVersionService.Label(c.Req.Context(), &dashver.LabelDashboardVersionCommand{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		DashboardID:  dash.ID,
+		DashboardUID: dash.UID,
+		Version:      version,
+		Name:         apiCmd.Name,
+		Tags:         apiCmd.Tags,
+	})
+	if err != nil {
+		if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+			return response.Error(http.StatusNotFound, fmt.Sprintf("Dashboard version %d not found for dashboard %s", version, dash.UID), err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to label dashboard version", err)
+	}
+
+	creator := anonString
+	if res.CreatedBy > 0 {
+		creator = hs.getIdentityName(c.Req.Context(), dash.OrgID, res.CreatedBy)
+	}
+
+	return response.JSON(http.StatusOK, &dashver.DashboardVersionMeta{
+		ID:            res.ID,
+		DashboardID:   res.DashboardID,
+		DashboardUID:  dash.UID,
+		Data:          res.Data,
+		ParentVersion: res.ParentVersion,
+		RestoredFrom:  res.RestoredFrom,
+		Version:       res.Version,
+		Created:       res.Created,
+		Message:       res.Message,
+		CreatedBy:     creator,
+		Name:          res.Name,
+		Tags:          res.Tags,
+	})
+}
+
 // swagger:route POST /dashboards/calculate-diff dashboards calculateDashboardDiff
 //
 // Perform diff on two dashboards.
@@ -1171,6 +1253,30 @@ func (hs *HTTPServer) RestoreDashboardVersion(c *contextmodel.ReqContext) respon
 		userID = id
 	}
 
+	if apiCmd.AsCopy {
+		title := apiCmd.Title
+		if title == "" {
+			title = fmt.Sprintf("%s (version %d)", dash.Title, version.Version)
+		}
+		folderUID := apiCmd.FolderUID
+		if folderUID == "" {
+			folderUID = dash.FolderUID
+		}
+
+		// The copy is a new dashboard, the permissions to create it in the folder are checked when it is saved.
+		version.Data.Del("id")
+		version.Data.Set("uid", "")
+		version.Data.Set("version", 0)
+		version.Data.Set("title", title)
+		return hs.postDashboard(c, dashboards.SaveDashboardCommand{
+			OrgID:     c.SignedInUser.GetOrgID(),
+			UserID:    userID,
+			Dashboard: version.Data,
+			FolderUID: folderUID,
+			Message:   fmt.Sprintf("Copied from version %d of dashboard %s", version.Version, dash.UID),
+		})
+	}
+
 	saveCmd := dashboards.SaveDashboardCommand{}
 	saveCmd.RestoredFrom = version.Version
 	saveCmd.OrgID = c.SignedInUser.GetOrgID()
@@ -1267,6 +1373,18 @@ type RestoreDashboardVersionByUIDParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters labelDashboardVersionByUID
+type LabelDashboardVersionByUIDParams struct {
+	// in:body
+	// required:true
+	Body dtos.LabelDashboardVersionCommand
+	// in:path
+	DashboardVersionID int64
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
 // swagger:parameters getDashboardVersionByID
 type GetDashboardVersionByIDParams struct {
 	// in:path
@@ -634,6 +634,54 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 			}, mockSQLStore)
 	})
 
+	t.Run("Given dashboard version being restored as a copy should save a new dashboard", func(t *testing.T) {
+		fakeDash := dashboards.NewDashboard("Child dash")
+		fakeDash.ID = 2
+		fakeDash.UID = "child"
+		fakeDash.FolderUID = "folder"
+
+		versionData := simplejson.NewFromAny(map[string]any{"id": 2, "uid": "child", "title": "Child dash", "version": 3})
+		var saved *dashboards.SaveDashboardDTO
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(fakeDash, nil)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*dashboards.SaveDashboardDTO)
+		}).Return(&dashboards.Dashboard{ID: 3, UID: "copy", Title: "Child dash (version 1)", Slug: "copy", Version: 1}, nil)
+
+		fakeDashboardVersionService := dashvertest.NewDashboardVersionServiceFake()
+		fakeDashboardVersionService.ExpectedDashboardVersions = []*dashver.DashboardVersionDTO{
+			{
+				DashboardID: 2,
+				Version:     1,
+				Data:        versionData,
+			},
+		}
+
+		cmd := dtos.RestoreDashboardVersionCommand{
+			Version: 1,
+			AsCopy:  true,
+		}
+		mockSQLStore := dbtest.NewFakeDB()
+		origNewGuardian := guardian.New
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+		t.Cleanup(func() {
+			guardian.New = origNewGuardian
+		})
+
+		restoreDashboardVersionScenario(t, "When calling POST on", "/api/dashboards/id/1/restore",
+			"/api/dashboards/id/:dashboardId/restore", dashboardService, fakeDashboardVersionService, cmd, func(sc *scenarioContext) {
+				callRestoreDashboardVersion(sc)
+				require.Equal(t, http.StatusOK, sc.resp.Code)
+				require.NotNil(t, saved)
+				assert.Zero(t, saved.Dashboard.ID)
+				assert.Empty(t, saved.Dashboard.UID)
+				assert.Equal(t, "Child dash (version 1)", saved.Dashboard.Title)
+				assert.Equal(t, "folder", saved.Dashboard.FolderUID)
+				assert.Equal(t, "Copied from version 1 of dashboard child", saved.Message)
+				assert.False(t, saved.Overwrite)
+			}, mockSQLStore)
+	})
+
 	t.Run("Given provisioned dashboard", func(t *testing.T) {
 		mockSQLStore := dbtest.NewFakeDB()
 		dashboardStore := dashboards.NewFakeDashboardStore(t)
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/version-retention dashboard_versions getDashboardVersionRetentionPolicies
+//
+// Get the dashboard version retention policies of the folders the user can read.
+//
+// Responses:
+// 200: getDashboardVersionRetentionPoliciesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardVersionRetentionPolicies(c *contextmodel.ReqContext) response.Response {
+	policies, err := hs.dashboardVersionService.GetRetentionPolicies(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard version retention policies", err)
+	}
+
+	readable := make([]*dashver.RetentionPolicy, 0, len(policies))
+	for _, policy := range policies {
+		folderUID := policy.FolderUID
+		if folderUID == folder.RootFolderUID {
+			folderUID = folder.GeneralFolderUID
+		}
+		ok, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, ac.EvalPermission(dashboards.ActionFoldersRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)))
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+		}
+		if ok {
+			readable = append(readable, policy)
+		}
+	}
+	return response.JSON(http.StatusOK, readable)
+}
+
+// swagger:route PUT /dashboards/version-retention/{folder_uid} dashboard_versions setDashboardVersionRetentionPolicy
+//
+// Set the dashboard version retention policy of a folder.
+//
+// The policy applies to the dashboards directly in the folder, use `general` for the dashboards at the root.
+// It replaces the global `versions_to_keep` setting for these dashboards and the latest version of a dashboard
+// is always kept.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) SetDashboardVersionRetentionPolicy(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.SetDashboardVersionRetentionCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	folderUID, rsp := hs.getRetentionFolderUID(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	err := hs.dashboardVersionService.SetRetentionPolicy(c.Req.Context(), &dashver.RetentionPolicy{
+		OrgID:        c.SignedInUser.GetOrgID(),
+		FolderUID:    folderUID,
+		KeepVersions: cmd.KeepVersions,
+		KeepDays:     cmd.KeepDays,
+		KeepTags:     cmd.KeepTags,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to set dashboard version retention policy", err)
+	}
+	return response.Success("Dashboard version retention policy set")
+}
+
+// swagger:route DELETE /dashboards/version-retention/{folder_uid} dashboard_versions deleteDashboardVersionRetentionPolicy
+//
+// Delete the dashboard version retention policy of a folder.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteDashboardVersionRetentionPolicy(c *contextmodel.ReqContext) response.Response {
+	folderUID, rsp := hs.getRetentionFolderUID(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	if err := hs.dashboardVersionService.DeleteRetentionPolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), folderUID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete dashboard version retention policy", err)
+	}
+	return response.Success("Dashboard version retention policy deleted")
+}
+
+// getRetentionFolderUID returns the folder of the request, checking that it exists. The general folder is
+// stored as the root folder.
+func (hs *HTTPServer) getRetentionFolderUID(c *contextmodel.ReqContext) (string, response.Response) {
+	uid := web.Params(c.Req)[":folderUid"]
+	if uid == folder.GeneralFolderUID {
+		return folder.RootFolderUID, nil
+	}
+
+	f, err := hs.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{OrgID: c.SignedInUser.GetOrgID(), UID: &uid, SignedInUser: c.SignedInUser})
+	if err != nil {
+		return "", apierrors.ToFolderErrorResponse(err)
+	}
+	return f.UID, nil
+}
+
+// swagger:parameters setDashboardVersionRetentionPolicy
+type SetDashboardVersionRetentionPolicyParams struct {
+	// in:body
+	// required:true
+	Body dtos.SetDashboardVersionRetentionCommand
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:parameters deleteDashboardVersionRetentionPolicy
+type DeleteDashboardVersionRetentionPolicyParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:response getDashboardVersionRetentionPoliciesResponse
+type GetDashboardVersionRetentionPoliciesResponse struct {
+	// in: body
+	Body []*dashver.RetentionPolicy `json:"body"`
+}
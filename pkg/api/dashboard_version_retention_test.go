@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestDashboardVersionRetentionAPI(t *testing.T) {
+	versionService := dashvertest.NewDashboardVersionServiceFake()
+	versionService.ExpectedRetentionPolicies = []*dashver.RetentionPolicy{
+		{OrgID: 1, FolderUID: "", KeepVersions: 5},
+		{OrgID: 1, FolderUID: "readable", KeepVersions: 10},
+		{OrgID: 1, FolderUID: "hidden", KeepDays: 30},
+	}
+	folderService := foldertest.NewFakeService()
+	folderService.ExpectedError = dashboards.ErrFolderNotFound
+
+	srv := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.dashboardVersionService = versionService
+		hs.folderService = folderService
+	})
+
+	t.Run("only lists the policies of the folders the user can read", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(srv.NewGetRequest("/api/dashboards/version-retention"), userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("general")},
+			{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("readable")},
+		}))
+		resp, err := srv.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var policies []*dashver.RetentionPolicy
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&policies))
+		require.NoError(t, resp.Body.Close())
+		require.Len(t, policies, 2)
+		assert.Equal(t, "", policies[0].FolderUID)
+		assert.Equal(t, "readable", policies[1].FolderUID)
+	})
+
+	t.Run("deleting the policy of a folder that doesn't exist returns 404", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(srv.NewRequest(http.MethodDelete, "/api/dashboards/version-retention/missing", nil), userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("missing")},
+		}))
+		resp, err := srv.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
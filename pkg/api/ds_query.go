@@ -17,6 +17,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/util/errhttp"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -76,14 +77,34 @@ func (hs *HTTPServer) QueryMetricsV2(c *contextmodel.ReqContext) response.Respon
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
-	resp, err := hs.queryDataService.QueryData(c.Req.Context(), c.SignedInUser, c.SkipDSCache, reqDTO)
+	ctx := c.Req.Context()
+	var meta *query.ResponseMeta
+	if reqDTO.IncludeMeta {
+		ctx, meta = query.WithResponseMeta(ctx)
+	}
+
+	resp, err := hs.queryDataService.QueryData(ctx, c.SignedInUser, c.SkipDSCache, reqDTO)
 	if err != nil {
 		return hs.handleQueryMetricsError(err)
 	}
-	return hs.toJsonStreamingResponse(c.Req.Context(), resp)
+	if meta != nil {
+		return response.JSONStreaming(queryDataStatusCode(ctx, resp), queryDataResponseWithMeta{Results: resp.Responses, Meta: meta})
+	}
+	return hs.toJsonStreamingResponse(ctx, resp)
+}
+
+// queryDataResponseWithMeta is the response of /api/ds/query when the request includes the meta section.
+// The meta section isn't part of the SDK's QueryDataResponse, hence it must be requested explicitly.
+type queryDataResponseWithMeta struct {
+	Results backend.Responses   `json:"results"`
+	Meta    *query.ResponseMeta `json:"meta"`
 }
 
 func (hs *HTTPServer) toJsonStreamingResponse(ctx context.Context, qdr *backend.QueryDataResponse) response.Response {
+	return response.JSONStreaming(queryDataStatusCode(ctx, qdr), qdr)
+}
+
+func queryDataStatusCode(ctx context.Context, qdr *backend.QueryDataResponse) int {
 	statusCode := http.StatusOK
 	for _, res := range qdr.Responses {
 		if res.Error != nil {
@@ -97,7 +118,7 @@ func (hs *HTTPServer) toJsonStreamingResponse(ctx context.Context, qdr *backend.
 		requestmeta.WithDownstreamStatusSource(ctx)
 	}
 
-	return response.JSONStreaming(statusCode, qdr)
+	return statusCode
 }
 
 // swagger:parameters queryMetricsWithExpressions
@@ -91,6 +91,25 @@ func TestAPIEndpoint_Metrics_QueryMetricsV2(t *testing.T) {
 		require.NoError(t, resp.Body.Close())
 		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
+
+	t.Run("Response includes the meta section when requested", func(t *testing.T) {
+		body := strings.Replace(reqValid, `"to": "",`, `"to": "", "includeMeta": true,`, 1)
+		req := server.NewPostRequest("/api/ds/query", strings.NewReader(body))
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, Permissions: map[int64]map[string][]string{1: {datasources.ActionQuery: []string{datasources.ScopeAll}}}})
+		resp, err := server.SendJSON(req)
+		require.NoError(t, err)
+
+		var res struct {
+			Results map[string]any     `json:"results"`
+			Meta    query.ResponseMeta `json:"meta"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		require.NoError(t, resp.Body.Close())
+		require.Contains(t, res.Results, "A")
+		require.Len(t, res.Meta.Datasources, 1)
+		require.Equal(t, "grafana", res.Meta.Datasources[0].UID)
+		require.Equal(t, query.DatasourceStatusError, res.Meta.Datasources[0].Status)
+	})
 }
 
 func TestAPIEndpoint_Metrics_PluginDecryptionFailure(t *testing.T) {
@@ -60,4 +60,21 @@ type CalculateDiffTarget struct {
 
 type RestoreDashboardVersionCommand struct {
 	Version int64 `json:"version" binding:"Required"`
+	// AsCopy restores the version as a new dashboard instead of overwriting the dashboard.
+	AsCopy bool `json:"asCopy"`
+	// Title of the copy, defaults to the title of the dashboard followed by the version.
+	Title string `json:"title"`
+	// FolderUID of the copy, defaults to the folder of the dashboard.
+	FolderUID string `json:"folderUid"`
+}
+
+type LabelDashboardVersionCommand struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+type SetDashboardVersionRetentionCommand struct {
+	KeepVersions int      `json:"keepVersions"`
+	KeepDays     int      `json:"keepDays"`
+	KeepTags     []string `json:"keepTags"`
 }
@@ -79,6 +79,13 @@ type MetricRequest struct {
 	Queries []*simplejson.Json `json:"queries"`
 	// required: false
 	Debug bool `json:"debug"`
+	// Timeout Maximum time to wait for the data sources, as a duration such as 10s. Data sources that don't respond in time are reported as timed out, the results of the others are still returned.
+	// required: false
+	// example: 10s
+	Timeout string `json:"timeout,omitempty"`
+	// IncludeMeta Adds a meta section to the response with the latency and status of each queried data source.
+	// required: false
+	IncludeMeta bool `json:"includeMeta,omitempty"`
 }
 
 func (mr *MetricRequest) GetUniqueDatasourceTypes() []string {
@@ -102,10 +109,12 @@ func (mr *MetricRequest) GetUniqueDatasourceTypes() []string {
 
 func (mr *MetricRequest) CloneWithQueries(queries []*simplejson.Json) MetricRequest {
 	return MetricRequest{
-		From:    mr.From,
-		To:      mr.To,
-		Queries: queries,
-		Debug:   mr.Debug,
+		From:        mr.From,
+		To:          mr.To,
+		Queries:     queries,
+		Debug:       mr.Debug,
+		Timeout:     mr.Timeout,
+		IncludeMeta: mr.IncludeMeta,
 	}
 }
 
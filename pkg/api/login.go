@@ -288,14 +288,6 @@ func (hs *HTTPServer) loginUserWithUser(user *user.User, c *contextmodel.ReqCont
 }
 
 func (hs *HTTPServer) Logout(c *contextmodel.ReqContext) {
-	// FIXME: restructure saml client to implement authn.LogoutClient
-	if hs.samlSingleLogoutEnabled() {
-		if c.SignedInUser.GetAuthenticatedBy() == loginservice.SAMLAuthModule {
-			c.Redirect(hs.Cfg.AppSubURL + "/logout/saml")
-			return
-		}
-	}
-
 	redirect, err := hs.authnService.Logout(c.Req.Context(), c.SignedInUser, c.UserToken)
 	authn.DeleteSessionCookie(c.Resp, hs.Cfg)
 
@@ -387,14 +379,6 @@ func (hs *HTTPServer) samlName() string {
 	return config.GetDisplayName()
 }
 
-func (hs *HTTPServer) samlSingleLogoutEnabled() bool {
-	config, ok := hs.authnService.GetClientConfig(authn.ClientSAML)
-	if !ok {
-		return false
-	}
-	return hs.samlEnabled() && config.IsSingleLogoutEnabled()
-}
-
 func (hs *HTTPServer) samlAutoLoginEnabled() bool {
 	config, ok := hs.authnService.GetClientConfig(authn.ClientSAML)
 	if !ok {
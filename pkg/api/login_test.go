@@ -664,7 +664,9 @@ func TestLogoutSaml(t *testing.T) {
 			ExpectedClientConfig: &authntest.FakeSSOClientConfig{
 				ExpectedIsSingleLogoutEnabled: true,
 			},
+			ExpectedRedirect: &authn.Redirect{URL: "https://idp.example.com/slo?SAMLRequest=request"},
 		},
+		log:              log.NewNopLogger(),
 		Cfg:              sc.cfg,
 		SettingsProvider: &setting.OSSImpl{Cfg: sc.cfg},
 		License:          license,
@@ -675,7 +677,6 @@ func TestLogoutSaml(t *testing.T) {
 		},
 	}
 
-	assert.Equal(t, true, hs.samlSingleLogoutEnabled())
 	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
 		c.SignedInUser = &user.SignedInUser{
 			UserID:          1,
@@ -687,6 +688,7 @@ func TestLogoutSaml(t *testing.T) {
 	sc.m.Get(sc.url, sc.defaultHandler)
 	sc.fakeReqNoAssertions("GET", sc.url).exec()
 	require.Equal(t, 302, sc.resp.Code)
+	require.Equal(t, "https://idp.example.com/slo?SAMLRequest=request", sc.resp.Header().Get("Location"))
 }
 
 func TestIsExternallySynced(t *testing.T) {
@@ -68,6 +68,8 @@ type DashboardHit struct {
 	Score float64 `json:"score,omitempty"`
 	// Explain the score (if possible)
 	Explain *common.Unstructured `json:"explain,omitempty"`
+	// The matches of the field scoped terms, like `query:http_requests_total`, and the ids of the matching panels
+	Highlight *common.Unstructured `json:"highlight,omitempty"`
 }
 
 type FacetResult struct {
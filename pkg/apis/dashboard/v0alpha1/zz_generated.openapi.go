@@ -281,6 +281,12 @@ func schema_pkg_apis_dashboard_v0alpha1_DashboardHit(ref common.ReferenceCallbac
 							Ref:         ref("github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1.Unstructured"),
 						},
 					},
+					"highlight": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The matches of the field scoped terms, like `query:http_requests_total`, and the ids of the matching panels",
+							Ref:         ref("github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1.Unstructured"),
+						},
+					},
 				},
 				Required: []string{"resource", "name", "title"},
 			},
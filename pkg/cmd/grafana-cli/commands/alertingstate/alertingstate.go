@@ -0,0 +1,97 @@
+package alertingstate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/server"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ExportAlertState writes the saved state of the alert instances of the organization to a file, or to stdout if no file is specified.
+func ExportAlertState(c utils.CommandLine, runner server.Runner) error {
+	ctx := context.Background()
+	orgID := int64(c.Int("org-id"))
+	logger := log.New("cli.alerting-state")
+
+	reader := state.NewMultiInstanceReader(logger,
+		store.ProtoInstanceDBStore{SQLStore: runner.SQLStore, Logger: logger, FeatureToggles: runner.Features},
+		store.InstanceDBStore{SQLStore: runner.SQLStore, Logger: logger},
+	)
+	instances, err := reader.ListAlertInstances(ctx, &ngmodels.ListAlertInstancesQuery{RuleOrgID: orgID})
+	if err != nil {
+		return fmt.Errorf("failed to list alert instances: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if path := c.String("file"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create file %s: %w", path, err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(state.SnapshotFromInstances(orgID, instances)); err != nil {
+		return fmt.Errorf("failed to write the snapshot: %w", err)
+	}
+	return nil
+}
+
+// ImportAlertState replaces the saved state of the alert instances of the organization with the state from a snapshot file.
+// Grafana should not be running because the running instances would overwrite the imported state.
+func ImportAlertState(c utils.CommandLine, runner server.Runner) error {
+	ctx := context.Background()
+	orgID := int64(c.Int("org-id"))
+	logger := log.New("cli.alerting-state")
+
+	path := c.String("file")
+	if path == "" {
+		return fmt.Errorf("the snapshot file must be specified with --file")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+	var snapshot ngmodels.AlertStateSnapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse the snapshot: %w", err)
+	}
+
+	ruleStore := store.DBstore{SQLStore: runner.SQLStore, Logger: logger, FeatureToggles: runner.Features}
+	rules, err := ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return fmt.Errorf("failed to list alert rules: %w", err)
+	}
+
+	var instanceStore state.InstanceStore
+	var persister state.StatePersister
+	if runner.Features.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStateCompressed) {
+		instanceStore = store.ProtoInstanceDBStore{SQLStore: runner.SQLStore, Logger: logger, FeatureToggles: runner.Features}
+		persister = state.NewSyncRuleStatePersisiter(logger, state.ManagerCfg{InstanceStore: instanceStore, MaxStateSaveConcurrency: 1})
+	} else {
+		instanceStore = store.InstanceDBStore{SQLStore: runner.SQLStore, Logger: logger}
+		persister = state.NewSyncStatePersisiter(logger, state.ManagerCfg{InstanceStore: instanceStore, MaxStateSaveConcurrency: 1})
+	}
+
+	result, err := state.ImportSnapshot(ctx, logger, instanceStore, persister, runner.SQLStore, orgID, snapshot, rules)
+	if err != nil {
+		return err
+	}
+	logger.Info("Imported alert state", "instances", result.Imported)
+	for _, uid := range result.MissingRules {
+		logger.Warn("Skipped the state of a rule that does not exist", "ruleUID", uid)
+	}
+	return nil
+}
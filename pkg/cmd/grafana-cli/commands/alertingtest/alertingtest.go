@@ -0,0 +1,93 @@
+package alertingtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/ruletest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// TestRules runs the unit tests of Grafana-managed alert rules in the test files given as arguments. It returns an
+// error if a test fails.
+func TestRules(c utils.CommandLine) error {
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		return fmt.Errorf("at least one test file must be specified")
+	}
+	return runTestFiles(context.Background(), os.Stdout, paths)
+}
+
+func runTestFiles(ctx context.Context, out io.Writer, paths []string) error {
+	cfg := setting.NewCfg()
+	cfg.ExpressionsEnabled = true
+	engine := ruletest.NewEngine(nil, cfg, featuremgmt.WithFeatures(), tracing.NewNoopTracerService())
+
+	failed := 0
+	for _, path := range paths {
+		file, err := loadTestFile(path)
+		if err != nil {
+			return err
+		}
+		results, err := engine.Run(ctx, 1, file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, result := range results.Tests {
+			if result.Passed {
+				_, _ = fmt.Fprintf(out, "%s %s: %s\n", color.GreenString("PASS"), path, result.Name)
+				continue
+			}
+			failed++
+			_, _ = fmt.Fprintf(out, "%s %s: %s\n", color.RedString("FAIL"), path, result.Name)
+			for _, failure := range result.Failures {
+				_, _ = fmt.Fprintf(out, "    %s\n", failure)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d tests failed", failed)
+	}
+	return nil
+}
+
+// loadTestFile reads a test file and adds the rule groups of its rule files, which are relative to the test file.
+func loadTestFile(path string) (definitions.RuleUnitTestFile, error) {
+	var file definitions.RuleUnitTestFile
+	if err := readYAML(path, &file); err != nil {
+		return file, err
+	}
+	for _, ruleFile := range file.RuleFiles {
+		if !filepath.IsAbs(ruleFile) {
+			ruleFile = filepath.Join(filepath.Dir(path), ruleFile)
+		}
+		var export definitions.AlertingFileExport
+		if err := readYAML(ruleFile, &export); err != nil {
+			return file, err
+		}
+		file.Groups = append(file.Groups, export.Groups...)
+	}
+	file.RuleFiles = nil
+	return file, nil
+}
+
+func readYAML(path string, v any) error {
+	raw, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("failed to parse file %s: %w", path, err)
+	}
+	return nil
+}
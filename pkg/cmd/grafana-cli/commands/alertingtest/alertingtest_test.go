@@ -0,0 +1,109 @@
+package alertingtest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const rulesFile = `apiVersion: 1
+groups:
+  - orgId: 1
+    name: api
+    folder: Services
+    interval: 1m
+    rules:
+      - uid: high-error-rate
+        title: HighErrorRate
+        condition: C
+        for: 1m
+        data:
+          - refId: A
+            relativeTimeRange:
+              from: 600
+              to: 0
+            datasourceUid: prometheus
+            model:
+              expr: rate(errors_total[5m])
+          - refId: B
+            datasourceUid: __expr__
+            model:
+              type: reduce
+              expression: A
+              reducer: last
+          - refId: C
+            datasourceUid: __expr__
+            model:
+              type: threshold
+              expression: B
+              conditions:
+                - evaluator:
+                    type: gt
+                    params: [5]
+`
+
+func writeFiles(t *testing.T, tests string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(rulesFile), 0o600))
+	path := filepath.Join(dir, "rules_test.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(tests), 0o600))
+	return path
+}
+
+func TestRunTestFiles(t *testing.T) {
+	t.Run("loads the rule files relative to the test file", func(t *testing.T) {
+		path := writeFiles(t, `rule_files:
+  - rules.yaml
+tests:
+  - name: errors
+    input_series:
+      - ref_id: A
+        values: '1 10x3'
+    alert_rule_test:
+      - eval_time: 2m
+        rule_title: HighErrorRate
+        exp_alerts:
+          - state: Alerting
+            labels:
+              alertname: HighErrorRate
+              grafana_folder: Services
+`)
+		var out bytes.Buffer
+		require.NoError(t, runTestFiles(context.Background(), &out, []string{path}))
+		require.Contains(t, out.String(), "PASS")
+		require.Contains(t, out.String(), "errors")
+	})
+
+	t.Run("returns an error if a test fails", func(t *testing.T) {
+		path := writeFiles(t, `rule_files:
+  - rules.yaml
+tests:
+  - name: errors
+    input_series:
+      - ref_id: A
+        values: '1x3'
+    alert_rule_test:
+      - eval_time: 2m
+        rule_uid: high-error-rate
+        exp_alerts:
+          - state: Alerting
+`)
+		var out bytes.Buffer
+		require.EqualError(t, runTestFiles(context.Background(), &out, []string{path}), "1 tests failed")
+		require.Contains(t, out.String(), "FAIL")
+		require.Contains(t, out.String(), "expected alert Alerting")
+	})
+
+	t.Run("returns an error if a rule file does not exist", func(t *testing.T) {
+		path := writeFiles(t, `rule_files:
+  - missing.yaml
+tests: []
+`)
+		require.ErrorContains(t, runTestFiles(context.Background(), &bytes.Buffer{}, []string{path}), "failed to read file")
+	})
+}
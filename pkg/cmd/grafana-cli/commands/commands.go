@@ -6,6 +6,8 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/alertingstate"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/alertingtest"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/datamigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
@@ -172,6 +174,53 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "alerting-state",
+		Usage: "Exports or imports the state of Grafana-managed alerts",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "Exports the saved state of the alert instances of an organization as JSON to a file or stdout.",
+				Action: runRunnerCommand(alertingstate.ExportAlertState),
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "org-id",
+						Usage: "The ID of the organization",
+						Value: 1,
+					},
+					&cli.StringFlag{
+						Name:  "file",
+						Usage: "The file to write the snapshot to. Defaults to stdout",
+					},
+				},
+			},
+			{
+				Name:   "import",
+				Usage:  "Replaces the saved state of the alert instances of an organization with the state from a snapshot file. Stop Grafana before importing.",
+				Action: runRunnerCommand(alertingstate.ImportAlertState),
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "org-id",
+						Usage: "The ID of the organization",
+						Value: 1,
+					},
+					&cli.StringFlag{
+						Name:  "file",
+						Usage: "The snapshot file to import",
+					},
+				},
+			},
+		},
+	},
+}
+
+var alertingCommands = []*cli.Command{
+	{
+		Name:      "test-rules",
+		Usage:     "Runs unit tests of Grafana-managed alert rules. Returns an error if a test fails.",
+		ArgsUsage: "<test file> [<test file>...]",
+		Action:    runPluginCommand(alertingtest.TestRules),
+	},
 }
 
 var Commands = []*cli.Command{
@@ -185,4 +234,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana Alerting commands",
+		Subcommands: alertingCommands,
+	},
 }
@@ -0,0 +1,69 @@
+package expr
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ErrOfflineQuery is returned for data source queries executed by an offline service that are not answered by the
+// context.
+var ErrOfflineQuery = errors.New("data source queries are not executed offline")
+
+// NewOfflineService returns a service that executes expressions without data sources. The data source queries must be
+// answered with WithQueryResponses, other queries fail with ErrOfflineQuery. It is used to test alert rules with fixed
+// input data.
+func NewOfflineService(cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer) *Service {
+	return &Service{
+		cfg:           cfg,
+		dataService:   offlineHandler{},
+		pCtxProvider:  offlinePluginContextProvider{},
+		features:      features,
+		tracer:        tracer,
+		metrics:       newMetrics(nil),
+		pluginsClient: offlineHandler{},
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracer,
+		},
+	}
+}
+
+type offlineHandler struct{}
+
+func (offlineHandler) QueryData(_ context.Context, _ *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	return nil, ErrOfflineQuery
+}
+
+func (offlineHandler) CallResource(_ context.Context, _ *backend.CallResourceRequest, _ backend.CallResourceResponseSender) error {
+	return ErrOfflineQuery
+}
+
+type offlinePluginContextProvider struct{}
+
+func (offlinePluginContextProvider) Get(_ context.Context, pluginID string, _ identity.Requester, orgID int64) (backend.PluginContext, error) {
+	return backend.PluginContext{OrgID: orgID, PluginID: pluginID}, nil
+}
+
+func (offlinePluginContextProvider) GetWithDataSource(_ context.Context, pluginID string, user identity.Requester, ds *datasources.DataSource) (backend.PluginContext, error) {
+	pCtx := backend.PluginContext{PluginID: pluginID}
+	if user != nil {
+		pCtx.OrgID = user.GetOrgID()
+	}
+	if ds != nil {
+		pCtx.DataSourceInstanceSettings = &backend.DataSourceInstanceSettings{
+			ID:   ds.ID,
+			UID:  ds.UID,
+			Type: ds.Type,
+			Name: ds.Name,
+		}
+	}
+	return pCtx, nil
+}
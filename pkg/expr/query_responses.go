@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+type queryResponsesKey struct{}
+
+// WithQueryResponses returns a context that answers the data source queries of the given RefIDs with the given
+// responses instead of querying the data source. It is used to feed the output of a rule to the queries of another
+// rule evaluated in the same tick.
+func WithQueryResponses(ctx context.Context, responses map[string]backend.DataResponse) context.Context {
+	if len(responses) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, queryResponsesKey{}, responses)
+}
+
+func queryResponsesFromContext(ctx context.Context) map[string]backend.DataResponse {
+	responses, _ := ctx.Value(queryResponsesKey{}).(map[string]backend.DataResponse)
+	return responses
+}
+
+// queryData queries the data source for the queries of the request that are not answered by the context.
+func (s *Service) queryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	responses := queryResponsesFromContext(ctx)
+	if len(responses) == 0 {
+		return s.dataService.QueryData(ctx, req)
+	}
+
+	result := backend.NewQueryDataResponse()
+	queries := make([]backend.DataQuery, 0, len(req.Queries))
+	for _, q := range req.Queries {
+		if r, ok := responses[q.RefID]; ok {
+			result.Responses[q.RefID] = r
+			continue
+		}
+		queries = append(queries, q)
+	}
+	if len(queries) == 0 {
+		return result, nil
+	}
+
+	remaining := *req
+	remaining.Queries = queries
+	resp, err := s.dataService.QueryData(ctx, &remaining)
+	if err != nil {
+		return nil, err
+	}
+	for refID, r := range resp.Responses {
+		result.Responses[refID] = r
+	}
+	return result, nil
+}
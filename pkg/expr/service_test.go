@@ -146,6 +146,54 @@ func TestDSQueryError(t *testing.T) {
 	require.Equal(t, fp(42), res.Responses["C"].Frames[0].Fields[0].At(0))
 }
 
+func TestServiceWithQueryResponses(t *testing.T) {
+	resp := map[string]backend.DataResponse{
+		"A": {Error: fmt.Errorf("should not be queried")},
+		"B": {Frames: data.Frames{data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("value", nil, []*float64{fp(3)}),
+		)}},
+	}
+	dsQuery := func(refID string) Query {
+		return Query{
+			RefID: refID,
+			DataSource: &datasources.DataSource{
+				OrgID: 1,
+				UID:   "test",
+				Type:  "test",
+			},
+			JSON:      json.RawMessage(`{ "datasource": { "uid": "1" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{},
+		}
+	}
+	queries := []Query{
+		dsQuery("A"),
+		dsQuery("B"),
+		{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A + $B" }`),
+		},
+	}
+
+	s, req := newMockQueryService(resp, queries)
+	pl, err := s.BuildPipeline(req)
+	require.NoError(t, err)
+
+	ctx := WithQueryResponses(context.Background(), map[string]backend.DataResponse{
+		"A": {Frames: data.Frames{data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("value", nil, []*float64{fp(2)}),
+		)}},
+	})
+	res, err := s.ExecutePipeline(ctx, time.Now(), pl)
+	require.NoError(t, err)
+
+	require.NoError(t, res.Responses["A"].Error)
+	require.NoError(t, res.Responses["C"].Error)
+	require.Equal(t, fp(5), res.Responses["C"].Frames[0].Fields[1].At(0))
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
@@ -0,0 +1,57 @@
+package investigation
+
+import (
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
+	investigationapp "github.com/grafana/grafana/apps/investigation/pkg/app"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/apiserver"
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type postmortemHandler struct {
+	namespacer           request.NamespaceMapper
+	gvr                  schema.GroupVersionResource
+	clientConfigProvider apiserver.DirectRestConfigProvider
+}
+
+func (h *postmortemHandler) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Get("/api/investigations/:name/postmortem", middleware.ReqSignedIn, routing.Wrap(h.getPostmortem))
+}
+
+// GET /api/investigations/:name/postmortem
+// Reads the investigation through the API server, so the access of the signed in user is enforced there.
+func (h *postmortemHandler) getPostmortem(c *contextmodel.ReqContext) response.Response {
+	dyn, err := dynamic.NewForConfig(h.clientConfigProvider.GetDirectRestConfig(c))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "client", err)
+	}
+	name := web.Params(c.Req)[":name"]
+	out, err := dyn.Resource(h.gvr).Namespace(h.namespacer(c.SignedInUser.GetOrgID())).Get(c.Req.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return response.Error(http.StatusNotFound, "investigation not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "failed to get investigation", err)
+	}
+
+	inv := &investigationv1alpha1.Investigation{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(out.Object, inv); err != nil {
+		return response.Error(http.StatusInternalServerError, "failed to read investigation", err)
+	}
+
+	return response.Respond(http.StatusOK, investigationapp.RenderPostmortem(inv)).
+		SetHeader("Content-Type", "text/markdown; charset=utf-8").
+		SetHeader("Content-Disposition", `attachment; filename="`+name+`-postmortem.md"`)
+}
@@ -0,0 +1,117 @@
+package investigation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	claims "github.com/grafana/authlib/types"
+
+	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// queryRunner runs the queries of investigation items on behalf of the last user who edited the investigation,
+// so snapshots never contain data that user couldn't see.
+type queryRunner struct {
+	authnService authn.Service
+	userService  user.Service
+	queryService query.Service
+}
+
+func (r *queryRunner) RunQuery(ctx context.Context, inv *investigationv1alpha1.Investigation, item investigationv1alpha1.InvestigationInvestigationItem) ([]any, error) {
+	info, err := claims.ParseNamespace(inv.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	meta, err := utils.MetaAccessor(inv)
+	if err != nil {
+		return nil, err
+	}
+	owner := meta.GetUpdatedBy()
+	if owner == "" {
+		owner = meta.GetCreatedBy()
+	}
+	if owner == "" {
+		return nil, fmt.Errorf("investigation has no owner to run queries as")
+	}
+
+	requester, err := r.resolveOwner(ctx, info.OrgID, owner)
+	if err != nil {
+		return nil, err
+	}
+	ctx = identity.WithRequester(ctx, requester)
+
+	raw, err := json.Marshal(item.DataQuery)
+	if err != nil {
+		return nil, err
+	}
+	q, err := simplejson.NewJson(raw)
+	if err != nil {
+		return nil, err
+	}
+	if q.Get("refId").MustString() == "" {
+		q.Set("refId", "A")
+	}
+
+	resp, err := r.queryService.QueryData(ctx, requester, true, dtos.MetricRequest{
+		From:    strconv.FormatInt(int64(item.TimeRange.From), 10),
+		To:      strconv.FormatInt(int64(item.TimeRange.To), 10),
+		Queries: []*simplejson.Json{q},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	frames := []any{}
+	for _, res := range resp.Responses {
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		for _, frame := range res.Frames {
+			b, err := frame.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			frames = append(frames, json.RawMessage(b))
+		}
+	}
+	return frames, nil
+}
+
+// resolveOwner returns the identity of the owner of an investigation in the organization. Owners are
+// recorded with their UID, while identities are resolved from the internal ID of users.
+func (r *queryRunner) resolveOwner(ctx context.Context, orgID int64, owner string) (identity.Requester, error) {
+	typ, uid, err := claims.ParseTypeID(owner)
+	if err != nil {
+		return nil, fmt.Errorf("invalid investigation owner %s: %w", owner, err)
+	}
+	if !claims.IsIdentityType(typ, claims.TypeUser, claims.TypeServiceAccount) {
+		return nil, fmt.Errorf("investigation owner %s can't run queries", owner)
+	}
+
+	usr, err := r.userService.GetByUID(ctx, &user.GetUserByUIDQuery{UID: uid})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, fmt.Errorf("investigation owner %s not found", owner)
+		}
+		return nil, fmt.Errorf("failed to get investigation owner %s: %w", owner, err)
+	}
+	if usr.IsDisabled {
+		return nil, fmt.Errorf("investigation owner %s is disabled", owner)
+	}
+
+	requester, err := r.authnService.ResolveIdentity(ctx, orgID, claims.NewTypeID(typ, strconv.FormatInt(usr.ID, 10)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve investigation owner %s: %w", owner, err)
+	}
+	return requester, nil
+}
@@ -0,0 +1,138 @@
+package investigation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	claims "github.com/grafana/authlib/types"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/apimachinery/utils"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+func TestQueryRunner_RunQuery(t *testing.T) {
+	owner := &user.User{ID: 7, UID: "ae3ntm8xcqz9cd", Login: "editor"}
+	disabled := &user.User{ID: 8, UID: "be3ntm8xcqz9cd", Login: "former", IsDisabled: true}
+	users := map[string]*user.User{owner.UID: owner, disabled.UID: disabled}
+	userService := &usertest.FakeUserService{}
+	userService.GetByUIDFn = func(_ context.Context, q *user.GetUserByUIDQuery) (*user.User, error) {
+		if u, ok := users[q.UID]; ok {
+			return u, nil
+		}
+		return nil, user.ErrUserNotFound
+	}
+
+	newRunner := func() (*queryRunner, *identity.Requester) {
+		var requester identity.Requester
+		queryService := &query.FakeQueryService{}
+		queryService.On("QueryData", mock.Anything, mock.Anything, true, mock.Anything).Run(func(args mock.Arguments) {
+			requester = args.Get(1).(identity.Requester)
+		}).Return(&backend.QueryDataResponse{Responses: backend.Responses{
+			"A": {Frames: data.Frames{data.NewFrame("up", data.NewField("value", nil, []float64{1}))}},
+		}}, nil).Maybe()
+		return &queryRunner{authnService: &fakeAuthnService{users: map[int64]*user.User{owner.ID: owner}}, userService: userService, queryService: queryService}, &requester
+	}
+
+	t.Run("queries run as the owner of the investigation", func(t *testing.T) {
+		runner, requester := newRunner()
+		inv := newTestInvestigation(t, "org-2", "user:"+owner.UID)
+
+		frames, err := runner.RunQuery(context.Background(), inv, testItem())
+		require.NoError(t, err)
+		assert.Len(t, frames, 1)
+
+		require.NotNil(t, *requester)
+		id, err := (*requester).GetInternalID()
+		require.NoError(t, err)
+		assert.Equal(t, owner.ID, id)
+		assert.Equal(t, int64(2), (*requester).GetOrgID())
+		assert.Equal(t, org.RoleEditor, (*requester).GetOrgRole())
+		assert.Equal(t, []string{"datasources:uid:prom"}, (*requester).GetPermissions()[datasources.ActionQuery])
+	})
+
+	t.Run("queries fail when the owner no longer exists", func(t *testing.T) {
+		runner, _ := newRunner()
+		_, err := runner.RunQuery(context.Background(), newTestInvestigation(t, "org-2", "user:unknown"), testItem())
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("queries fail when the owner is disabled", func(t *testing.T) {
+		runner, _ := newRunner()
+		_, err := runner.RunQuery(context.Background(), newTestInvestigation(t, "org-2", "user:"+disabled.UID), testItem())
+		assert.ErrorContains(t, err, "disabled")
+	})
+
+	t.Run("queries fail without an owner", func(t *testing.T) {
+		runner, _ := newRunner()
+		_, err := runner.RunQuery(context.Background(), newTestInvestigation(t, "org-2", ""), testItem())
+		assert.Error(t, err)
+	})
+}
+
+// fakeAuthnService resolves users from their internal ID only, like the hook that fetches the synced
+// user during the resolution of an identity.
+type fakeAuthnService struct {
+	authntest.FakeService
+	users map[int64]*user.User
+}
+
+func (f *fakeAuthnService) ResolveIdentity(_ context.Context, orgID int64, typedID string) (*authn.Identity, error) {
+	typ, id, err := claims.ParseTypeID(typedID)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch user with id %s: %w", id, err)
+	}
+	u, ok := f.users[userID]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	return &authn.Identity{
+		ID:          id,
+		Type:        typ,
+		OrgID:       orgID,
+		OrgRoles:    map[int64]org.RoleType{orgID: org.RoleEditor},
+		Login:       u.Login,
+		Permissions: map[int64]map[string][]string{orgID: {datasources.ActionQuery: {"datasources:uid:prom"}}},
+	}, nil
+}
+
+func newTestInvestigation(t *testing.T, namespace, owner string) *investigationv1alpha1.Investigation {
+	t.Helper()
+	inv := &investigationv1alpha1.Investigation{}
+	inv.SetName("inv")
+	inv.SetNamespace(namespace)
+	meta, err := utils.MetaAccessor(inv)
+	require.NoError(t, err)
+	meta.SetUpdatedBy(owner)
+	return inv
+}
+
+func testItem() investigationv1alpha1.InvestigationInvestigationItem {
+	return investigationv1alpha1.InvestigationInvestigationItem{
+		Id:        "a",
+		TimeRange: investigationv1alpha1.InvestigationAbsoluteTimeRange{From: 1738400000000, To: 1738403600000},
+		DataQuery: map[string]any{
+			"refId":      "A",
+			"datasource": map[string]any{"uid": "prom", "type": "prometheus"},
+			"expr":       "up",
+		},
+	}
+}
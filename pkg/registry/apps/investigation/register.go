@@ -3,10 +3,19 @@ package investigation
 import (
 	"github.com/grafana/grafana-app-sdk/app"
 	"github.com/grafana/grafana-app-sdk/simple"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
 	"github.com/grafana/grafana/apps/investigation/pkg/apis"
 	investigationv1alpha1 "github.com/grafana/grafana/apps/investigation/pkg/apis/investigation/v1alpha1"
 	investigationapp "github.com/grafana/grafana/apps/investigation/pkg/app"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/services/apiserver"
 	"github.com/grafana/grafana/pkg/services/apiserver/builder/runner"
+	"github.com/grafana/grafana/pkg/services/apiserver/endpoints/request"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -17,14 +26,34 @@ type InvestigationAppProvider struct {
 
 func RegisterApp(
 	cfg *setting.Cfg,
+	features featuremgmt.FeatureToggles,
+	routeRegister routing.RouteRegister,
+	clientConfigProvider apiserver.DirectRestConfigProvider,
+	authnService authn.Service,
+	queryService query.Service,
+	userService user.Service,
 ) *InvestigationAppProvider {
 	provider := &InvestigationAppProvider{
 		cfg: cfg,
 	}
+	specificConfig := investigationapp.InvestigationAppConfig{
+		QueryRunner: &queryRunner{authnService: authnService, userService: userService, queryService: queryService},
+	}
 	appCfg := &runner.AppBuilderConfig{
 		OpenAPIDefGetter: investigationv1alpha1.GetOpenAPIDefinitions,
 		ManagedKinds:     investigationapp.GetKinds(),
+		CustomConfig:     any(specificConfig),
 	}
 	provider.Provider = simple.NewAppProvider(apis.LocalManifest(), appCfg, investigationapp.New)
+
+	if features.IsEnabledGlobally(featuremgmt.FlagInvestigationsBackend) {
+		kind := investigationv1alpha1.InvestigationKind()
+		handler := &postmortemHandler{
+			namespacer:           request.GetNamespaceMapper(cfg),
+			gvr:                  schema.GroupVersionResource{Group: kind.Group(), Version: kind.Version(), Resource: kind.Plural()},
+			clientConfigProvider: clientConfigProvider,
+		}
+		handler.registerAPIEndpoints(routeRegister)
+	}
 	return provider
 }
@@ -0,0 +1,74 @@
+package accessgrant
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Group("/api/access-control/grants", func(r routing.RouteRegister) {
+		r.Get("/", routing.Wrap(s.searchHandler))
+		r.Post("/", routing.Wrap(s.createHandler))
+		r.Get("/:grantUID", routing.Wrap(s.getHandler))
+		r.Post("/:grantUID/approve", routing.Wrap(s.reviewHandler(s.Approve)))
+		r.Post("/:grantUID/deny", routing.Wrap(s.reviewHandler(s.Deny)))
+		r.Post("/:grantUID/revoke", routing.Wrap(s.reviewHandler(s.Revoke)))
+	}, middleware.ReqSignedInNoAnonymous)
+}
+
+// GET /api/access-control/grants
+func (s *Service) searchHandler(c *contextmodel.ReqContext) response.Response {
+	status := Status(c.Query("status"))
+	switch status {
+	case "", StatusPending, StatusActive, StatusDenied, StatusExpired, StatusRevoked:
+	default:
+		return response.Err(ErrInvalidGrant.Errorf("invalid status %q", status))
+	}
+
+	grants, err := s.Search(c.Req.Context(), c.SignedInUser, status)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, grants)
+}
+
+// POST /api/access-control/grants
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := CreateGrantCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	grant, err := s.Create(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, grant)
+}
+
+// GET /api/access-control/grants/:grantUID
+func (s *Service) getHandler(c *contextmodel.ReqContext) response.Response {
+	grant, err := s.Get(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":grantUID"])
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, grant)
+}
+
+// POST /api/access-control/grants/:grantUID/(approve|deny|revoke)
+func (s *Service) reviewHandler(review func(context.Context, identity.Requester, string) (*Grant, error)) func(c *contextmodel.ReqContext) response.Response {
+	return func(c *contextmodel.ReqContext) response.Response {
+		grant, err := review(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":grantUID"])
+		if err != nil {
+			return response.Err(err)
+		}
+		return response.JSON(http.StatusOK, grant)
+	}
+}
@@ -0,0 +1,92 @@
+package accessgrant
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+var (
+	ErrGrantNotFound = errutil.NotFound("accesscontrol.grant-not-found", errutil.WithPublicMessage("Access grant not found"))
+	ErrInvalidGrant  = errutil.BadRequest("accesscontrol.grant-invalid")
+	ErrGrantConflict = errutil.Conflict("accesscontrol.grant-conflict")
+	ErrForbidden     = errutil.Forbidden("accesscontrol.grant-forbidden", errutil.WithPublicMessage("You are not allowed to review this access grant"))
+)
+
+// Kind is what a grant gives to its principal.
+type Kind string
+
+const (
+	// KindOrgRole elevates the organization role of a user.
+	KindOrgRole Kind = "orgRole"
+	// KindPermission gives a managed permission on a folder or a dashboard.
+	KindPermission Kind = "permission"
+	// KindRole assigns an RBAC role, such as a custom role, to a user, a team or a basic role.
+	KindRole Kind = "role"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusActive  Status = "active"
+	StatusDenied  Status = "denied"
+	StatusExpired Status = "expired"
+	StatusRevoked Status = "revoked"
+)
+
+// Grant is a time-bound role or permission assignment. Grants are either created active by someone
+// allowed to make the change, or requested and activated once approved. The state the principal had
+// before the grant is kept in Previous and restored when the grant expires or is revoked.
+type Grant struct {
+	ID          int64        `json:"-" xorm:"pk autoincr 'id'"`
+	UID         string       `json:"uid" xorm:"uid"`
+	OrgID       int64        `json:"orgId" xorm:"org_id"`
+	UserID      int64        `json:"userId,omitempty" xorm:"user_id"`
+	TeamID      int64        `json:"teamId,omitempty" xorm:"team_id"`
+	BuiltInRole string       `json:"builtInRole,omitempty" xorm:"builtin_role"`
+	Kind        Kind         `json:"kind" xorm:"kind"`
+	Role        org.RoleType `json:"role,omitempty" xorm:"role"`
+	RoleUID     string       `json:"roleUid,omitempty" xorm:"role_uid"`
+	Resource    string       `json:"resource,omitempty" xorm:"resource"`
+	ResourceID  string       `json:"resourceId,omitempty" xorm:"resource_id"`
+	Permission  string       `json:"permission,omitempty" xorm:"permission"`
+	Previous    string       `json:"-" xorm:"previous"`
+	Reason      string       `json:"reason" xorm:"reason"`
+	Status      Status       `json:"status" xorm:"status"`
+	// Duration of the grant in seconds, counted from its activation.
+	Duration    int64      `json:"duration" xorm:"duration"`
+	RequestedBy int64      `json:"requestedBy" xorm:"requested_by"`
+	ReviewedBy  int64      `json:"reviewedBy,omitempty" xorm:"reviewed_by"`
+	RevokedBy   int64      `json:"revokedBy,omitempty" xorm:"revoked_by"`
+	Created     time.Time  `json:"created" xorm:"'created'"`
+	Updated     time.Time  `json:"updated" xorm:"'updated'"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty" xorm:"expires_at"`
+}
+
+func (Grant) TableName() string {
+	return "access_grant"
+}
+
+// CreateGrantCommand requests a grant for exactly one of a user, a team or a basic role.
+// Set Role to elevate the organization role of a user, RoleUID to assign an RBAC role, or Resource,
+// ResourceID and Permission to give a managed permission.
+type CreateGrantCommand struct {
+	UserID      int64        `json:"userId"`
+	TeamID      int64        `json:"teamId"`
+	BuiltInRole string       `json:"builtInRole"`
+	Role        org.RoleType `json:"role"`
+	RoleUID     string       `json:"roleUid"`
+	Resource    string       `json:"resource"`
+	ResourceID  string       `json:"resourceId"`
+	Permission  string       `json:"permission"`
+	// Duration of the grant, for example "4h".
+	Duration string `json:"duration"`
+	Reason   string `json:"reason"`
+}
+
+type SearchGrantsQuery struct {
+	OrgID  int64
+	Status Status
+}
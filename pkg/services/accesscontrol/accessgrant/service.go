@@ -0,0 +1,716 @@
+package accessgrant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// permissionLevels orders the managed permissions of folders and dashboards.
+var permissionLevels = map[string]int{"View": 1, "Edit": 2, "Admin": 3}
+
+type resource struct {
+	permissions accesscontrol.PermissionsService
+	readAction  string
+	writeAction string
+	scopePrefix string
+}
+
+type Service struct {
+	cfg   *setting.Cfg
+	log   log.Logger
+	audit log.Logger
+	store store
+
+	accessControl accesscontrol.AccessControl
+	acService     accesscontrol.Service
+	orgService    org.Service
+	teamService   team.Service
+	resources     map[string]resource
+
+	now func() time.Time
+}
+
+func ProvideService(
+	cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl accesscontrol.AccessControl,
+	acService accesscontrol.Service, authnService authn.Service, orgService org.Service, teamService team.Service,
+	folderPermissions accesscontrol.FolderPermissionsService, dashboardPermissions accesscontrol.DashboardPermissionsService,
+) *Service {
+	s := &Service{
+		cfg:           cfg,
+		log:           log.New("accesscontrol.grants"),
+		audit:         log.New("accesscontrol.grants.audit"),
+		store:         &xormStore{db: sqlStore},
+		accessControl: accessControl,
+		acService:     acService,
+		orgService:    orgService,
+		teamService:   teamService,
+		resources: map[string]resource{
+			"folders": {
+				permissions: folderPermissions,
+				readAction:  dashboards.ActionFoldersPermissionsRead,
+				writeAction: dashboards.ActionFoldersPermissionsWrite,
+				scopePrefix: dashboards.ScopeFoldersPrefix,
+			},
+			"dashboards": {
+				permissions: dashboardPermissions,
+				readAction:  dashboards.ActionDashboardsPermissionsRead,
+				writeAction: dashboards.ActionDashboardsPermissionsWrite,
+				scopePrefix: dashboards.ScopeDashboardsPrefix,
+			},
+		},
+		now: time.Now,
+	}
+
+	orgService.RegisterDelete("DELETE FROM access_grant WHERE org_id = ?")
+	teamService.RegisterDelete("DELETE FROM access_grant WHERE org_id = ? AND team_id = ?")
+
+	// Run before the signed in user is fetched so that an expired org role grant is already reverted.
+	authnService.RegisterPostAuthHook(s.expireGrantsHook, 90)
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+// Create creates a grant for the command. Grants are activated right away when the requester is
+// allowed to make the change permanently, otherwise they wait for an approval. Users without that
+// permission can only request grants for themselves.
+func (s *Service) Create(ctx context.Context, requester identity.Requester, cmd CreateGrantCommand) (*Grant, error) {
+	requesterID, err := userID(requester)
+	if err != nil {
+		return nil, err
+	}
+
+	grant, err := s.grantFromCommand(requester.GetOrgID(), cmd)
+	if err != nil {
+		return nil, err
+	}
+	grant.RequestedBy = requesterID
+
+	if err := s.validatePrincipal(ctx, grant); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.store.FindActive(ctx, grant)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrGrantConflict.Errorf("access grant %s is already %s for this principal and target", existing.UID, existing.Status)
+	}
+
+	canReview, err := s.canReview(ctx, requester, grant)
+	if err != nil {
+		return nil, err
+	}
+	if !canReview && grant.UserID != requesterID {
+		return nil, ErrForbidden.Errorf("user %d can only request access grants for themselves", requesterID)
+	}
+
+	if err := s.store.Create(ctx, grant); err != nil {
+		return nil, err
+	}
+
+	if !canReview {
+		s.auditLog(ctx, "requested", grant, requesterID)
+		return grant, nil
+	}
+
+	if err := s.activate(ctx, grant, requesterID); err != nil {
+		// The grant couldn't be applied, don't leave a request behind.
+		grant.Status = StatusDenied
+		if _, tErr := s.store.Transition(ctx, grant, StatusPending); tErr != nil {
+			s.log.FromContext(ctx).Error("Failed to discard access grant", "grant", grant.UID, "error", tErr)
+		}
+		return nil, err
+	}
+	return grant, nil
+}
+
+// Get returns the grant if the requester is allowed to see it.
+func (s *Service) Get(ctx context.Context, requester identity.Requester, uid string) (*Grant, error) {
+	grant, err := s.store.Get(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+	visible, err := s.canSee(ctx, requester, grant)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, ErrGrantNotFound.Errorf("access grant %s not found", uid)
+	}
+	return grant, nil
+}
+
+// Search returns the grants of the organization the requester is involved in or can review.
+func (s *Service) Search(ctx context.Context, requester identity.Requester, status Status) ([]*Grant, error) {
+	grants, err := s.store.Search(ctx, SearchGrantsQuery{OrgID: requester.GetOrgID(), Status: status})
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*Grant, 0, len(grants))
+	for _, g := range grants {
+		ok, err := s.canSee(ctx, requester, g)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visible = append(visible, g)
+		}
+	}
+	return visible, nil
+}
+
+// Approve activates a pending grant.
+func (s *Service) Approve(ctx context.Context, requester identity.Requester, uid string) (*Grant, error) {
+	grant, reviewerID, err := s.getForReview(ctx, requester, uid, StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.activate(ctx, grant, reviewerID); err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+// Deny rejects a pending grant, requesters can also withdraw their own requests.
+func (s *Service) Deny(ctx context.Context, requester identity.Requester, uid string) (*Grant, error) {
+	reviewerID, err := userID(requester)
+	if err != nil {
+		return nil, err
+	}
+	grant, err := s.store.Get(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if grant.RequestedBy != reviewerID {
+		if grant, reviewerID, err = s.getForReview(ctx, requester, uid, StatusPending); err != nil {
+			return nil, err
+		}
+	}
+	if grant.Status != StatusPending {
+		return nil, ErrGrantConflict.Errorf("access grant %s is %s", grant.UID, grant.Status)
+	}
+
+	grant.Status = StatusDenied
+	grant.ReviewedBy = reviewerID
+	if err := s.transition(ctx, grant, StatusPending); err != nil {
+		return nil, err
+	}
+	s.auditLog(ctx, "denied", grant, reviewerID)
+	return grant, nil
+}
+
+// Revoke ends an active grant before it expires and restores the previous access of the principal.
+// Users can also give up the grants they received.
+func (s *Service) Revoke(ctx context.Context, requester identity.Requester, uid string) (*Grant, error) {
+	revokerID, err := userID(requester)
+	if err != nil {
+		return nil, err
+	}
+	grant, err := s.store.Get(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if grant.UserID != revokerID {
+		if grant, revokerID, err = s.getForReview(ctx, requester, uid, StatusActive); err != nil {
+			return nil, err
+		}
+	}
+	if grant.Status != StatusActive {
+		return nil, ErrGrantConflict.Errorf("access grant %s is %s", grant.UID, grant.Status)
+	}
+
+	if err := s.revert(ctx, grant); err != nil {
+		return nil, err
+	}
+	grant.Status = StatusRevoked
+	grant.RevokedBy = revokerID
+	if err := s.transition(ctx, grant, StatusActive); err != nil {
+		return nil, err
+	}
+	s.auditLog(ctx, "revoked", grant, revokerID)
+	return grant, nil
+}
+
+// ExpireGrants reverts the grants past their expiry and expires the requests nobody reviewed.
+// It is run periodically by the cleanup service.
+func (s *Service) ExpireGrants(ctx context.Context) (int, error) {
+	now := s.now()
+
+	count, err := s.expireActive(ctx, now)
+	if err != nil {
+		return count, err
+	}
+
+	stale, err := s.store.ListStaleRequests(ctx, now.Add(-s.cfg.RBAC.AccessGrantRequestTTL))
+	if err != nil {
+		return count, err
+	}
+	for _, grant := range stale {
+		grant.Status = StatusExpired
+		updated, err := s.store.Transition(ctx, grant, StatusPending)
+		if err != nil {
+			return count, err
+		}
+		if updated {
+			s.auditLog(ctx, "request expired", grant, 0)
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// expireGrantsHook reverts the grants past their expiry before the permissions of the identity are
+// loaded, so that expired grants stop applying right away instead of at the next cleanup.
+func (s *Service) expireGrantsHook(ctx context.Context, id *authn.Identity, _ *authn.Request) error {
+	if !id.IsIdentityType(claims.TypeUser, claims.TypeServiceAccount) {
+		return nil
+	}
+
+	count, err := s.expireActive(ctx, s.now())
+	if err != nil {
+		s.log.FromContext(ctx).Error("Failed to expire access grants", "error", err)
+		return nil
+	}
+	if count > 0 {
+		s.acService.ClearUserPermissionCache(id)
+	}
+	return nil
+}
+
+// expireActive reverts the active grants past their expiry.
+func (s *Service) expireActive(ctx context.Context, now time.Time) (int, error) {
+	logger := s.log.FromContext(ctx)
+
+	expired, err := s.store.ListExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, grant := range expired {
+		if err := s.revert(ctx, grant); err != nil {
+			// Keep the grant active so the next run tries again.
+			logger.Error("Failed to revert expired access grant", "grant", grant.UID, "orgID", grant.OrgID, "error", err)
+			continue
+		}
+		grant.Status = StatusExpired
+		updated, err := s.store.Transition(ctx, grant, StatusActive)
+		if err != nil {
+			logger.Error("Failed to expire access grant", "grant", grant.UID, "orgID", grant.OrgID, "error", err)
+			continue
+		}
+		if updated {
+			s.auditLog(ctx, "expired", grant, 0)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *Service) grantFromCommand(orgID int64, cmd CreateGrantCommand) (*Grant, error) {
+	principals := 0
+	for _, set := range []bool{cmd.UserID != 0, cmd.TeamID != 0, cmd.BuiltInRole != ""} {
+		if set {
+			principals++
+		}
+	}
+	if principals != 1 {
+		return nil, ErrInvalidGrant.Errorf("exactly one of userId, teamId and builtInRole is required")
+	}
+	if cmd.BuiltInRole != "" && !org.RoleType(cmd.BuiltInRole).IsValid() {
+		return nil, ErrInvalidGrant.Errorf("invalid builtInRole %q", cmd.BuiltInRole)
+	}
+
+	duration, err := time.ParseDuration(cmd.Duration)
+	if err != nil || duration < time.Minute {
+		return nil, ErrInvalidGrant.Errorf("duration must be at least 1m, got %q", cmd.Duration)
+	}
+	if duration > s.cfg.RBAC.AccessGrantMaxDuration {
+		return nil, ErrInvalidGrant.Errorf("duration can't exceed %s", s.cfg.RBAC.AccessGrantMaxDuration)
+	}
+
+	now := s.now()
+	grant := &Grant{
+		UID:         util.GenerateShortUID(),
+		OrgID:       orgID,
+		UserID:      cmd.UserID,
+		TeamID:      cmd.TeamID,
+		BuiltInRole: cmd.BuiltInRole,
+		Reason:      cmd.Reason,
+		Status:      StatusPending,
+		Duration:    int64(duration / time.Second),
+		Created:     now,
+		Updated:     now,
+	}
+
+	targets := 0
+	for _, set := range []bool{cmd.Role != "", cmd.RoleUID != "", cmd.Resource != ""} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return nil, ErrInvalidGrant.Errorf("exactly one of role, roleUid and resource is required")
+	}
+
+	switch {
+	case cmd.Role != "":
+		if cmd.UserID == 0 {
+			return nil, ErrInvalidGrant.Errorf("organization roles can only be granted to users")
+		}
+		if !cmd.Role.IsValid() || cmd.Role == org.RoleNone {
+			return nil, ErrInvalidGrant.Errorf("invalid role %q", cmd.Role)
+		}
+		grant.Kind = KindOrgRole
+		grant.Role = cmd.Role
+	case cmd.RoleUID != "":
+		grant.Kind = KindRole
+		grant.RoleUID = cmd.RoleUID
+	default:
+		if _, ok := s.resources[cmd.Resource]; !ok {
+			return nil, ErrInvalidGrant.Errorf("unsupported resource %q", cmd.Resource)
+		}
+		if cmd.ResourceID == "" {
+			return nil, ErrInvalidGrant.Errorf("resourceId is required")
+		}
+		if _, ok := permissionLevels[cmd.Permission]; !ok {
+			return nil, ErrInvalidGrant.Errorf("invalid permission %q", cmd.Permission)
+		}
+		grant.Kind = KindPermission
+		grant.Resource = cmd.Resource
+		grant.ResourceID = cmd.ResourceID
+		grant.Permission = cmd.Permission
+	}
+
+	return grant, nil
+}
+
+// validatePrincipal checks that the user or the team of the grant belongs to the organization.
+func (s *Service) validatePrincipal(ctx context.Context, grant *Grant) error {
+	switch {
+	case grant.UserID != 0:
+		_, err := s.orgRole(ctx, grant)
+		return err
+	case grant.TeamID != 0:
+		_, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{OrgID: grant.OrgID, ID: grant.TeamID})
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return ErrInvalidGrant.Errorf("team %d not found in organization %d", grant.TeamID, grant.OrgID)
+		}
+		return err
+	}
+	return nil
+}
+
+// orgRole returns the organization role of the user of the grant.
+func (s *Service) orgRole(ctx context.Context, grant *Grant) (org.RoleType, error) {
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: grant.UserID})
+	if err != nil {
+		return "", err
+	}
+	for _, o := range orgs {
+		if o.OrgID == grant.OrgID {
+			return o.Role, nil
+		}
+	}
+	return "", ErrInvalidGrant.Errorf("user %d is not a member of organization %d", grant.UserID, grant.OrgID)
+}
+
+func (s *Service) getForReview(ctx context.Context, requester identity.Requester, uid string, status Status) (*Grant, int64, error) {
+	reviewerID, err := userID(requester)
+	if err != nil {
+		return nil, 0, err
+	}
+	grant, err := s.store.Get(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, 0, err
+	}
+	ok, err := s.canReview(ctx, requester, grant)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return nil, 0, ErrForbidden.Errorf("user %d can't review access grant %s", reviewerID, uid)
+	}
+	if grant.Status != status {
+		return nil, 0, ErrGrantConflict.Errorf("access grant %s is %s", grant.UID, grant.Status)
+	}
+	return grant, reviewerID, nil
+}
+
+// activate applies a pending grant and starts its validity period.
+func (s *Service) activate(ctx context.Context, grant *Grant, reviewerID int64) error {
+	previous, err := s.currentAccess(ctx, grant)
+	if err != nil {
+		return err
+	}
+	if includes(grant, previous) {
+		return ErrGrantConflict.Errorf("the principal already has this access")
+	}
+
+	expiresAt := s.now().Add(time.Duration(grant.Duration) * time.Second)
+	grant.Status = StatusActive
+	grant.ReviewedBy = reviewerID
+	grant.Previous = previous
+	grant.ExpiresAt = &expiresAt
+	grant.Updated = s.now()
+
+	// Claim the grant first so concurrent reviews can't both apply it.
+	if err := s.transition(ctx, grant, StatusPending); err != nil {
+		return err
+	}
+
+	if err := s.apply(ctx, grant, granted(grant)); err != nil {
+		grant.Status = StatusPending
+		grant.ReviewedBy = 0
+		grant.Previous = ""
+		grant.ExpiresAt = nil
+		if _, tErr := s.store.Transition(ctx, grant, StatusActive); tErr != nil {
+			s.log.FromContext(ctx).Error("Failed to reset access grant", "grant", grant.UID, "error", tErr)
+		}
+		return err
+	}
+
+	s.auditLog(ctx, "granted", grant, reviewerID)
+	return nil
+}
+
+// revert restores the access the principal had before the grant, unless it was changed since.
+func (s *Service) revert(ctx context.Context, grant *Grant) error {
+	current, err := s.currentAccess(ctx, grant)
+	if err != nil {
+		return err
+	}
+	if current != granted(grant) {
+		s.log.FromContext(ctx).Warn("Access changed since it was granted, leaving it as is", "grant", grant.UID, "orgID", grant.OrgID, "current", current)
+		return nil
+	}
+	return s.apply(ctx, grant, grant.Previous)
+}
+
+// currentAccess returns the organization role, the UID of the assigned role or the managed permission
+// the principal has on the target of the grant, excluding inherited permissions.
+func (s *Service) currentAccess(ctx context.Context, grant *Grant) (string, error) {
+	if grant.Kind == KindOrgRole {
+		role, err := s.orgRole(ctx, grant)
+		return string(role), err
+	}
+	if grant.Kind == KindRole {
+		role, _, err := s.store.GetRole(ctx, grant.OrgID, grant.RoleUID)
+		if errors.Is(err, accesscontrol.ErrRoleNotFound) {
+			// The role was deleted along with its assignments.
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		assigned, err := s.store.IsRoleAssigned(ctx, grant, role.ID)
+		if err != nil || !assigned {
+			return "", err
+		}
+		return role.UID, nil
+	}
+
+	res := s.resources[grant.Resource]
+	requester := accesscontrol.BackgroundUser("access_grant", grant.OrgID, org.RoleNone, []accesscontrol.Permission{
+		{Action: res.readAction, Scope: res.scopePrefix + grant.ResourceID},
+	})
+	permissions, err := res.permissions.GetPermissions(ctx, requester, grant.ResourceID)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range permissions {
+		if !p.IsManaged || p.IsInherited {
+			continue
+		}
+		if (grant.UserID != 0 && p.UserID == grant.UserID) ||
+			(grant.TeamID != 0 && p.TeamID == grant.TeamID) ||
+			(grant.BuiltInRole != "" && p.BuiltInRole == grant.BuiltInRole) {
+			return res.permissions.MapActions(p), nil
+		}
+	}
+	return "", nil
+}
+
+func (s *Service) apply(ctx context.Context, grant *Grant, value string) error {
+	if grant.Kind == KindOrgRole {
+		return s.orgService.UpdateOrgUser(ctx, &org.UpdateOrgUserCommand{Role: org.RoleType(value), OrgID: grant.OrgID, UserID: grant.UserID})
+	}
+	if grant.Kind == KindRole {
+		role, _, err := s.store.GetRole(ctx, grant.OrgID, grant.RoleUID)
+		if err != nil {
+			if value == "" && errors.Is(err, accesscontrol.ErrRoleNotFound) {
+				return nil
+			}
+			return err
+		}
+		if value == "" {
+			return s.store.UnassignRole(ctx, grant, role.ID)
+		}
+		return s.store.AssignRole(ctx, grant, role.ID)
+	}
+
+	res := s.resources[grant.Resource]
+	var err error
+	switch {
+	case grant.UserID != 0:
+		_, err = res.permissions.SetUserPermission(ctx, grant.OrgID, accesscontrol.User{ID: grant.UserID}, grant.ResourceID, value)
+	case grant.TeamID != 0:
+		_, err = res.permissions.SetTeamPermission(ctx, grant.OrgID, grant.TeamID, grant.ResourceID, value)
+	default:
+		_, err = res.permissions.SetBuiltInRolePermission(ctx, grant.OrgID, grant.BuiltInRole, grant.ResourceID, value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set %s permission on %s %s: %w", value, grant.Resource, grant.ResourceID, err)
+	}
+	return nil
+}
+
+func (s *Service) transition(ctx context.Context, grant *Grant, from Status) error {
+	updated, err := s.store.Transition(ctx, grant, from)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrGrantConflict.Errorf("access grant %s is no longer %s", grant.UID, from)
+	}
+	return nil
+}
+
+// canReview tells if the requester could make the change of the grant permanently.
+func (s *Service) canReview(ctx context.Context, requester identity.Requester, grant *Grant) (bool, error) {
+	var evaluator accesscontrol.Evaluator
+	if grant.Kind == KindOrgRole {
+		// Like when updating org users, roles higher than the requester's own can't be assigned.
+		if !requester.GetOrgRole().Includes(grant.Role) && !requester.GetIsGrafanaAdmin() {
+			return false, nil
+		}
+		evaluator = accesscontrol.EvalPermission(accesscontrol.ActionOrgUsersWrite, accesscontrol.ScopeUsersPrefix+strconv.FormatInt(grant.UserID, 10))
+	} else if grant.Kind == KindRole {
+		var err error
+		if evaluator, err = s.roleEvaluator(ctx, grant); err != nil {
+			return false, err
+		}
+	} else {
+		res := s.resources[grant.Resource]
+		evaluator = accesscontrol.EvalPermission(res.writeAction, res.scopePrefix+grant.ResourceID)
+	}
+	return s.accessControl.Evaluate(ctx, requester, evaluator)
+}
+
+// roleEvaluator requires the permission to delegate roles to the kind of principal of the grant, and
+// the permissions of the role so that reviewers can't give more than they have.
+func (s *Service) roleEvaluator(ctx context.Context, grant *Grant) (accesscontrol.Evaluator, error) {
+	role, permissions, err := s.store.GetRole(ctx, grant.OrgID, grant.RoleUID)
+	if errors.Is(err, accesscontrol.ErrRoleNotFound) {
+		return nil, ErrInvalidGrant.Errorf("role %s not found", grant.RoleUID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if role.IsBasic() || strings.HasPrefix(role.Name, accesscontrol.ManagedRolePrefix) || strings.HasPrefix(role.Name, accesscontrol.ExternalServiceRolePrefix) {
+		return nil, ErrInvalidGrant.Errorf("role %s can't be granted", grant.RoleUID)
+	}
+
+	// Adding a role to a basic role changes the basic role, like updating its permissions.
+	action := accesscontrol.ActionRolesWrite
+	switch {
+	case grant.UserID != 0:
+		action = accesscontrol.ActionUsersRolesAdd
+	case grant.TeamID != 0:
+		action = accesscontrol.ActionTeamsRolesAdd
+	}
+	evaluators := make([]accesscontrol.Evaluator, 0, len(permissions)+1)
+	evaluators = append(evaluators, accesscontrol.EvalPermission(action, accesscontrol.ScopePermissionsDelegate))
+	for _, p := range permissions {
+		evaluators = append(evaluators, accesscontrol.EvalPermission(p.Action, p.Scope))
+	}
+	return accesscontrol.EvalAll(evaluators...), nil
+}
+
+func (s *Service) canSee(ctx context.Context, requester identity.Requester, grant *Grant) (bool, error) {
+	id, err := requester.GetInternalID()
+	if err == nil && (grant.RequestedBy == id || grant.UserID == id) {
+		return true, nil
+	}
+	return s.canReview(ctx, requester, grant)
+}
+
+func (s *Service) auditLog(ctx context.Context, action string, grant *Grant, actorID int64) {
+	args := []any{"grant", grant.UID, "orgID", grant.OrgID, "actorID", actorID, "kind", grant.Kind, "requestedBy", grant.RequestedBy}
+	switch {
+	case grant.UserID != 0:
+		args = append(args, "userID", grant.UserID)
+	case grant.TeamID != 0:
+		args = append(args, "teamID", grant.TeamID)
+	default:
+		args = append(args, "builtInRole", grant.BuiltInRole)
+	}
+	switch grant.Kind {
+	case KindOrgRole:
+		args = append(args, "role", grant.Role)
+	case KindRole:
+		args = append(args, "roleUID", grant.RoleUID)
+	default:
+		args = append(args, "resource", grant.Resource, "resourceID", grant.ResourceID, "permission", grant.Permission)
+	}
+	if grant.ExpiresAt != nil {
+		args = append(args, "expiresAt", grant.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	s.audit.FromContext(ctx).Info("Access grant "+action, append(args, "reason", grant.Reason)...)
+}
+
+// granted returns the role or permission given by the grant.
+func granted(grant *Grant) string {
+	switch grant.Kind {
+	case KindOrgRole:
+		return string(grant.Role)
+	case KindRole:
+		return grant.RoleUID
+	}
+	return grant.Permission
+}
+
+// includes tells if the access the principal already has covers the grant.
+func includes(grant *Grant, current string) bool {
+	switch grant.Kind {
+	case KindOrgRole:
+		return org.RoleType(current).Includes(grant.Role)
+	case KindRole:
+		return current == grant.RoleUID
+	}
+	return permissionLevels[current] >= permissionLevels[grant.Permission]
+}
+
+func userID(requester identity.Requester) (int64, error) {
+	if !requester.IsIdentityType(claims.TypeUser) {
+		return 0, ErrForbidden.Errorf("access grants can only be managed by users")
+	}
+	id, err := requester.GetInternalID()
+	if err != nil {
+		return 0, errors.Join(ErrForbidden.Errorf("access grants can only be managed by users"), err)
+	}
+	return id, nil
+}
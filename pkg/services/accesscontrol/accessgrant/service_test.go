@@ -0,0 +1,463 @@
+package accessgrant
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	claims "github.com/grafana/authlib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationAccessGrant_OrgRole(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s, orgService, _ := setupTestService(t)
+	s.now = func() time.Time { return now }
+	orgService.roles[2] = org.RoleViewer
+
+	requester := testUser(2)
+	admin := testUser(1, accesscontrol.ActionOrgUsersWrite, accesscontrol.ScopeUsersAll)
+	admin.OrgRole = org.RoleAdmin
+
+	t.Run("users can only request grants for themselves", func(t *testing.T) {
+		_, err := s.Create(ctx, requester, CreateGrantCommand{UserID: 3, Role: org.RoleEditor, Duration: "4h"})
+		requireGrantError(t, err, "accesscontrol.grant-forbidden")
+	})
+
+	grant, err := s.Create(ctx, requester, CreateGrantCommand{UserID: 2, Role: org.RoleEditor, Duration: "4h", Reason: "incident"})
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, grant.Status)
+	assert.Equal(t, org.RoleViewer, orgService.roles[2])
+
+	_, err = s.Create(ctx, requester, CreateGrantCommand{UserID: 2, Role: org.RoleAdmin, Duration: "1h"})
+	requireGrantError(t, err, "accesscontrol.grant-conflict")
+
+	_, err = s.Approve(ctx, requester, grant.UID)
+	requireGrantError(t, err, "accesscontrol.grant-forbidden")
+
+	grant, err = s.Approve(ctx, admin, grant.UID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusActive, grant.Status)
+	assert.Equal(t, int64(1), grant.ReviewedBy)
+	assert.Equal(t, now.Add(4*time.Hour), *grant.ExpiresAt)
+	assert.Equal(t, org.RoleEditor, orgService.roles[2])
+
+	// Nothing to do before the expiry.
+	count, err := s.ExpireGrants(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	s.now = func() time.Time { return now.Add(5 * time.Hour) }
+	count, err = s.ExpireGrants(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, org.RoleViewer, orgService.roles[2])
+
+	grant, err = s.Get(ctx, requester, grant.UID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusExpired, grant.Status)
+
+	t.Run("reviewers can't grant roles higher than their own", func(t *testing.T) {
+		editor := testUser(4, accesscontrol.ActionOrgUsersWrite, accesscontrol.ScopeUsersAll)
+		editor.OrgRole = org.RoleEditor
+
+		grant, err := s.Create(ctx, requester, CreateGrantCommand{UserID: 2, Role: org.RoleAdmin, Duration: "1h"})
+		require.NoError(t, err)
+
+		_, err = s.Approve(ctx, editor, grant.UID)
+		requireGrantError(t, err, "accesscontrol.grant-forbidden")
+
+		_, err = s.Create(ctx, editor, CreateGrantCommand{UserID: 3, Role: org.RoleAdmin, Duration: "1h"})
+		requireGrantError(t, err, "accesscontrol.grant-forbidden")
+		assert.Equal(t, org.RoleViewer, orgService.roles[2])
+	})
+}
+
+func TestIntegrationAccessGrant_Permission(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s, _, folders := setupTestService(t)
+	admin := testUser(1, dashboards.ActionFoldersPermissionsWrite, dashboards.ScopeFoldersPrefix+"ops")
+
+	t.Run("reviewers activate the grants they create", func(t *testing.T) {
+		grant, err := s.Create(ctx, admin, CreateGrantCommand{TeamID: 7, Resource: "folders", ResourceID: "ops", Permission: "Edit", Duration: "4h"})
+		require.NoError(t, err)
+		assert.Equal(t, StatusActive, grant.Status)
+		assert.Equal(t, "Edit", folders.team[7])
+
+		grant, err = s.Revoke(ctx, admin, grant.UID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusRevoked, grant.Status)
+		assert.Equal(t, int64(1), grant.RevokedBy)
+		assert.Equal(t, "", folders.team[7])
+	})
+
+	t.Run("restores the previous permission", func(t *testing.T) {
+		folders.users[2] = "View"
+		grant, err := s.Create(ctx, admin, CreateGrantCommand{UserID: 2, Resource: "folders", ResourceID: "ops", Permission: "Admin", Duration: "1h"})
+		require.NoError(t, err)
+		assert.Equal(t, "Admin", folders.users[2])
+
+		// The grantee can give up the grant.
+		_, err = s.Revoke(ctx, testUser(2), grant.UID)
+		require.NoError(t, err)
+		assert.Equal(t, "View", folders.users[2])
+	})
+
+	t.Run("does not override permissions changed since the grant", func(t *testing.T) {
+		grant, err := s.Create(ctx, admin, CreateGrantCommand{UserID: 3, Resource: "folders", ResourceID: "ops", Permission: "Edit", Duration: "1h"})
+		require.NoError(t, err)
+
+		folders.users[3] = "Admin"
+		_, err = s.Revoke(ctx, admin, grant.UID)
+		require.NoError(t, err)
+		assert.Equal(t, "Admin", folders.users[3])
+	})
+
+	t.Run("rejects grants of access the principal already has", func(t *testing.T) {
+		folders.users[4] = "Admin"
+		_, err := s.Create(ctx, admin, CreateGrantCommand{UserID: 4, Resource: "folders", ResourceID: "ops", Permission: "Edit", Duration: "1h"})
+		requireGrantError(t, err, "accesscontrol.grant-conflict")
+	})
+
+	t.Run("requesters can withdraw their requests", func(t *testing.T) {
+		grant, err := s.Create(ctx, testUser(5), CreateGrantCommand{UserID: 5, Resource: "folders", ResourceID: "ops", Permission: "Edit", Duration: "1h"})
+		require.NoError(t, err)
+
+		grant, err = s.Deny(ctx, testUser(5), grant.UID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusDenied, grant.Status)
+		assert.Empty(t, folders.users[5])
+	})
+
+	t.Run("only shows grants to the people involved and reviewers", func(t *testing.T) {
+		grants, err := s.Search(ctx, testUser(5), "")
+		require.NoError(t, err)
+		require.Len(t, grants, 1)
+
+		grants, err = s.Search(ctx, testUser(6), "")
+		require.NoError(t, err)
+		assert.Empty(t, grants)
+
+		grants, err = s.Search(ctx, admin, StatusRevoked)
+		require.NoError(t, err)
+		assert.Len(t, grants, 3)
+	})
+}
+
+func TestIntegrationAccessGrant_Role(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s, _, _ := setupTestService(t)
+	s.now = func() time.Time { return now }
+	sqlStore := s.store.(*xormStore)
+
+	var roleID int64
+	err := sqlStore.db.WithDbSession(ctx, func(sess *db.Session) error {
+		role := &accesscontrol.Role{OrgID: 1, UID: "oncall", Name: "custom:oncall", Version: 1, Created: now, Updated: now}
+		if _, err := sess.Insert(role); err != nil {
+			return err
+		}
+		roleID = role.ID
+		_, err := sess.Insert(&accesscontrol.Permission{RoleID: role.ID, Action: "alert.rules:write", Scope: "folders:*", Created: now, Updated: now})
+		return err
+	})
+	require.NoError(t, err)
+
+	isAssigned := func(t *testing.T, grant *Grant) bool {
+		t.Helper()
+		assigned, err := sqlStore.IsRoleAssigned(ctx, grant, roleID)
+		require.NoError(t, err)
+		return assigned
+	}
+
+	t.Run("reviewers need the permissions of the role", func(t *testing.T) {
+		grant, err := s.Create(ctx, testUser(2), CreateGrantCommand{UserID: 2, RoleUID: "oncall", Duration: "4h"})
+		require.NoError(t, err)
+		assert.Equal(t, StatusPending, grant.Status)
+
+		_, err = s.Approve(ctx, testUser(1, accesscontrol.ActionUsersRolesAdd, accesscontrol.ScopePermissionsDelegate), grant.UID)
+		requireGrantError(t, err, "accesscontrol.grant-forbidden")
+
+		reviewer := testUser(1, accesscontrol.ActionUsersRolesAdd, accesscontrol.ScopePermissionsDelegate, "alert.rules:write", "folders:*")
+		grant, err = s.Approve(ctx, reviewer, grant.UID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusActive, grant.Status)
+		assert.True(t, isAssigned(t, grant))
+
+		s.now = func() time.Time { return now.Add(5 * time.Hour) }
+		t.Cleanup(func() { s.now = func() time.Time { return now } })
+		count, err := s.ExpireGrants(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.False(t, isAssigned(t, grant))
+	})
+
+	t.Run("assigns roles to teams and basic roles until revoked", func(t *testing.T) {
+		reviewer := testUser(1,
+			accesscontrol.ActionTeamsRolesAdd, accesscontrol.ScopePermissionsDelegate,
+			accesscontrol.ActionRolesWrite, accesscontrol.ScopePermissionsDelegate,
+			"alert.rules:write", "folders:*",
+		)
+		for _, cmd := range []CreateGrantCommand{
+			{TeamID: 7, RoleUID: "oncall", Duration: "1h"},
+			{BuiltInRole: string(org.RoleEditor), RoleUID: "oncall", Duration: "1h"},
+		} {
+			grant, err := s.Create(ctx, reviewer, cmd)
+			require.NoError(t, err)
+			assert.Equal(t, StatusActive, grant.Status)
+			assert.True(t, isAssigned(t, grant))
+
+			_, err = s.Create(ctx, reviewer, cmd)
+			requireGrantError(t, err, "accesscontrol.grant-conflict")
+
+			_, err = s.Revoke(ctx, reviewer, grant.UID)
+			require.NoError(t, err)
+			assert.False(t, isAssigned(t, grant))
+		}
+	})
+
+	t.Run("rejects unknown and basic roles", func(t *testing.T) {
+		_, err := s.Create(ctx, testUser(2), CreateGrantCommand{UserID: 2, RoleUID: "unknown", Duration: "1h"})
+		requireGrantError(t, err, "accesscontrol.grant-invalid")
+
+		err = sqlStore.db.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Insert(&accesscontrol.Role{OrgID: 1, UID: "basic_editor", Name: "basic:editor", Version: 1, Created: now, Updated: now})
+			return err
+		})
+		require.NoError(t, err)
+		_, err = s.Create(ctx, testUser(2), CreateGrantCommand{UserID: 2, RoleUID: "basic_editor", Duration: "1h"})
+		requireGrantError(t, err, "accesscontrol.grant-invalid")
+	})
+}
+
+func TestIntegrationAccessGrant_Principal(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s, _, _ := setupTestService(t)
+	admin := testUser(1, dashboards.ActionFoldersPermissionsWrite, dashboards.ScopeFoldersPrefix+"ops")
+
+	_, err := s.Create(ctx, admin, CreateGrantCommand{UserID: 42, Resource: "folders", ResourceID: "ops", Permission: "Edit", Duration: "1h"})
+	requireGrantError(t, err, "accesscontrol.grant-invalid")
+
+	s.teamService = &teamtest.FakeService{ExpectedError: team.ErrTeamNotFound}
+	_, err = s.Create(ctx, admin, CreateGrantCommand{TeamID: 42, Resource: "folders", ResourceID: "ops", Permission: "Edit", Duration: "1h"})
+	requireGrantError(t, err, "accesscontrol.grant-invalid")
+}
+
+func TestIntegrationAccessGrant_ExpireOnAuthentication(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s, _, folders := setupTestService(t)
+	s.now = func() time.Time { return now }
+	admin := testUser(1, dashboards.ActionFoldersPermissionsWrite, dashboards.ScopeFoldersPrefix+"ops")
+
+	_, err := s.Create(ctx, admin, CreateGrantCommand{UserID: 2, Resource: "folders", ResourceID: "ops", Permission: "Edit", Duration: "1h"})
+	require.NoError(t, err)
+
+	id := &authn.Identity{ID: "2", Type: claims.TypeUser, OrgID: 1}
+	require.NoError(t, s.expireGrantsHook(ctx, id, &authn.Request{}))
+	assert.Equal(t, "Edit", folders.users[2])
+
+	// The grant is reverted by the next authentication, before the cleanup runs.
+	s.now = func() time.Time { return now.Add(time.Hour) }
+	require.NoError(t, s.expireGrantsHook(ctx, id, &authn.Request{}))
+	assert.Equal(t, "", folders.users[2])
+}
+
+func TestIntegrationAccessGrant_ExpireStaleRequests(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s, _, _ := setupTestService(t)
+	s.now = func() time.Time { return now }
+
+	grant, err := s.Create(ctx, testUser(2), CreateGrantCommand{UserID: 2, Resource: "dashboards", ResourceID: "abc", Permission: "View", Duration: "1h"})
+	require.NoError(t, err)
+
+	s.now = func() time.Time { return now.Add(25 * time.Hour) }
+	count, err := s.ExpireGrants(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	grant, err = s.Get(ctx, testUser(2), grant.UID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusExpired, grant.Status)
+	assert.Nil(t, grant.ExpiresAt)
+}
+
+func TestService_GrantFromCommand(t *testing.T) {
+	s := &Service{cfg: setting.NewCfg(), resources: map[string]resource{"folders": {}}, now: time.Now}
+	s.cfg.RBAC.AccessGrantMaxDuration = 8 * time.Hour
+
+	tests := []struct {
+		desc string
+		cmd  CreateGrantCommand
+		err  string
+	}{
+		{desc: "no principal", cmd: CreateGrantCommand{Role: org.RoleEditor, Duration: "1h"}, err: "exactly one of userId, teamId and builtInRole is required"},
+		{desc: "several principals", cmd: CreateGrantCommand{UserID: 1, TeamID: 1, Role: org.RoleEditor, Duration: "1h"}, err: "exactly one of userId, teamId and builtInRole is required"},
+		{desc: "invalid basic role", cmd: CreateGrantCommand{BuiltInRole: "Owner", Resource: "folders", ResourceID: "a", Permission: "View", Duration: "1h"}, err: `invalid builtInRole "Owner"`},
+		{desc: "role for a team", cmd: CreateGrantCommand{TeamID: 1, Role: org.RoleEditor, Duration: "1h"}, err: "organization roles can only be granted to users"},
+		{desc: "invalid role", cmd: CreateGrantCommand{UserID: 1, Role: "Owner", Duration: "1h"}, err: `invalid role "Owner"`},
+		{desc: "several targets", cmd: CreateGrantCommand{UserID: 1, Role: org.RoleEditor, RoleUID: "a", Duration: "1h"}, err: "exactly one of role, roleUid and resource is required"},
+		{desc: "unknown resource", cmd: CreateGrantCommand{UserID: 1, Resource: "datasources", ResourceID: "a", Permission: "View", Duration: "1h"}, err: `unsupported resource "datasources"`},
+		{desc: "invalid permission", cmd: CreateGrantCommand{UserID: 1, Resource: "folders", ResourceID: "a", Permission: "Write", Duration: "1h"}, err: `invalid permission "Write"`},
+		{desc: "invalid duration", cmd: CreateGrantCommand{UserID: 1, Role: org.RoleEditor, Duration: "forever"}, err: `duration must be at least 1m, got "forever"`},
+		{desc: "duration too long", cmd: CreateGrantCommand{UserID: 1, Role: org.RoleEditor, Duration: "9h"}, err: "duration can't exceed 8h0m0s"},
+		{desc: "valid", cmd: CreateGrantCommand{UserID: 1, Resource: "folders", ResourceID: "a", Permission: "Edit", Duration: "8h"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			grant, err := s.grantFromCommand(1, tt.cmd)
+			if tt.err != "" {
+				require.ErrorIs(t, err, ErrInvalidGrant)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, KindPermission, grant.Kind)
+			assert.Equal(t, int64(8*3600), grant.Duration)
+		})
+	}
+}
+
+func setupTestService(t *testing.T) (*Service, *fakeOrgService, *fakePermissionsService) {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.RBAC.AccessGrantMaxDuration = 24 * time.Hour
+	cfg.RBAC.AccessGrantRequestTTL = 24 * time.Hour
+
+	orgService := &fakeOrgService{roles: map[int64]org.RoleType{}}
+	for id := int64(1); id <= 6; id++ {
+		orgService.roles[id] = org.RoleViewer
+	}
+	folders := newFakePermissionsService()
+	s := ProvideService(
+		cfg, db.InitTestDB(t), routing.NewRouteRegister(), acimpl.ProvideAccessControlTest(),
+		actest.FakeService{}, &authntest.FakeService{}, orgService, teamtest.NewFakeService(), folders, newFakePermissionsService(),
+	)
+	return s, orgService, folders
+}
+
+func testUser(id int64, permissions ...string) *user.SignedInUser {
+	u := &user.SignedInUser{UserID: id, OrgID: 1, OrgRole: org.RoleViewer, Permissions: map[int64]map[string][]string{1: {}}}
+	for i := 0; i+1 < len(permissions); i += 2 {
+		u.Permissions[1][permissions[i]] = append(u.Permissions[1][permissions[i]], permissions[i+1])
+	}
+	return u
+}
+
+func requireGrantError(t *testing.T, err error, messageID string) {
+	t.Helper()
+	var grantErr errutil.Error
+	require.True(t, errors.As(err, &grantErr), "expected a grant error, got %v", err)
+	assert.Equal(t, messageID, grantErr.MessageID)
+}
+
+// fakeOrgService keeps the organization role of the users of org 1, users 1 to 6 are Viewers.
+type fakeOrgService struct {
+	orgtest.FakeOrgService
+	roles map[int64]org.RoleType
+}
+
+func (f *fakeOrgService) GetUserOrgList(_ context.Context, query *org.GetUserOrgListQuery) ([]*org.UserOrgDTO, error) {
+	role, ok := f.roles[query.UserID]
+	if !ok {
+		return nil, nil
+	}
+	return []*org.UserOrgDTO{{OrgID: 1, Role: role}}, nil
+}
+
+func (f *fakeOrgService) UpdateOrgUser(_ context.Context, cmd *org.UpdateOrgUserCommand) error {
+	f.roles[cmd.UserID] = cmd.Role
+	return nil
+}
+
+// fakePermissionsService keeps the managed permissions of a single resource.
+type fakePermissionsService struct {
+	actest.FakePermissionsService
+	users map[int64]string
+	team  map[int64]string
+}
+
+func newFakePermissionsService() *fakePermissionsService {
+	return &fakePermissionsService{users: map[int64]string{}, team: map[int64]string{}}
+}
+
+func (f *fakePermissionsService) GetPermissions(context.Context, identity.Requester, string) ([]accesscontrol.ResourcePermission, error) {
+	var permissions []accesscontrol.ResourcePermission
+	for id, p := range f.users {
+		if p != "" {
+			permissions = append(permissions, accesscontrol.ResourcePermission{UserID: id, Actions: []string{p}, IsManaged: true})
+		}
+	}
+	for id, p := range f.team {
+		if p != "" {
+			permissions = append(permissions, accesscontrol.ResourcePermission{TeamID: id, Actions: []string{p}, IsManaged: true})
+		}
+	}
+	return permissions, nil
+}
+
+func (f *fakePermissionsService) SetUserPermission(_ context.Context, _ int64, u accesscontrol.User, _, permission string) (*accesscontrol.ResourcePermission, error) {
+	f.users[u.ID] = permission
+	return nil, nil
+}
+
+func (f *fakePermissionsService) SetTeamPermission(_ context.Context, _, teamID int64, _, permission string) (*accesscontrol.ResourcePermission, error) {
+	f.team[teamID] = permission
+	return nil, nil
+}
+
+func (f *fakePermissionsService) MapActions(permission accesscontrol.ResourcePermission) string {
+	return permission.Actions[0]
+}
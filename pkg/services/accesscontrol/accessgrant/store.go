@@ -0,0 +1,183 @@
+package accessgrant
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const searchLimit = 1000
+
+type store interface {
+	Create(ctx context.Context, grant *Grant) error
+	Get(ctx context.Context, orgID int64, uid string) (*Grant, error)
+	Search(ctx context.Context, query SearchGrantsQuery) ([]*Grant, error)
+	// FindActive returns the active or pending grant of the principal on the same target if any.
+	FindActive(ctx context.Context, grant *Grant) (*Grant, error)
+	// Transition saves the grant if its status is still the given one and returns false otherwise.
+	Transition(ctx context.Context, grant *Grant, from Status) (bool, error)
+	ListExpired(ctx context.Context, now time.Time) ([]*Grant, error)
+	ListStaleRequests(ctx context.Context, olderThan time.Time) ([]*Grant, error)
+
+	// GetRole returns the role with the UID if it is global or belongs to the org, and its permissions.
+	GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.Role, []accesscontrol.Permission, error)
+	// IsRoleAssigned tells if the role is assigned to the principal of the grant.
+	IsRoleAssigned(ctx context.Context, grant *Grant, roleID int64) (bool, error)
+	AssignRole(ctx context.Context, grant *Grant, roleID int64) error
+	UnassignRole(ctx context.Context, grant *Grant, roleID int64) error
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) Create(ctx context.Context, grant *Grant) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(grant)
+		return err
+	})
+}
+
+func (s *xormStore) Get(ctx context.Context, orgID int64, uid string) (*Grant, error) {
+	grant := &Grant{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(grant)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrGrantNotFound.Errorf("access grant %s not found", uid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+func (s *xormStore) Search(ctx context.Context, query SearchGrantsQuery) ([]*Grant, error) {
+	grants := make([]*Grant, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Status != "" {
+			q = q.And("status = ?", query.Status)
+		}
+		return q.Desc("id").Limit(searchLimit).Find(&grants)
+	})
+	return grants, err
+}
+
+func (s *xormStore) FindActive(ctx context.Context, grant *Grant) (*Grant, error) {
+	existing := &Grant{}
+	var has bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		has, err = sess.Where("org_id = ? AND user_id = ? AND team_id = ? AND builtin_role = ? AND kind = ? AND role_uid = ? AND resource = ? AND resource_id = ?",
+			grant.OrgID, grant.UserID, grant.TeamID, grant.BuiltInRole, grant.Kind, grant.RoleUID, grant.Resource, grant.ResourceID).
+			In("status", StatusPending, StatusActive).
+			Get(existing)
+		return err
+	})
+	if err != nil || !has {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (s *xormStore) Transition(ctx context.Context, grant *Grant, from Status) (bool, error) {
+	var updated bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.ID(grant.ID).Where("status = ?", from).
+			Cols("status", "previous", "reviewed_by", "revoked_by", "expires_at", "updated").
+			Update(grant)
+		updated = rows > 0
+		return err
+	})
+	return updated, err
+}
+
+func (s *xormStore) ListExpired(ctx context.Context, now time.Time) ([]*Grant, error) {
+	grants := make([]*Grant, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("status = ? AND expires_at <= ?", StatusActive, now).Asc("id").Find(&grants)
+	})
+	return grants, err
+}
+
+func (s *xormStore) ListStaleRequests(ctx context.Context, olderThan time.Time) ([]*Grant, error) {
+	grants := make([]*Grant, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("status = ? AND created <= ?", StatusPending, olderThan).Asc("id").Find(&grants)
+	})
+	return grants, err
+}
+
+func (s *xormStore) GetRole(ctx context.Context, orgID int64, uid string) (*accesscontrol.Role, []accesscontrol.Permission, error) {
+	role := &accesscontrol.Role{}
+	permissions := make([]accesscontrol.Permission, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("uid = ?", uid).In("org_id", accesscontrol.GlobalOrgID, orgID).Get(role)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return accesscontrol.ErrRoleNotFound
+		}
+		return sess.Where("role_id = ?", role.ID).Find(&permissions)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return role, permissions, nil
+}
+
+func (s *xormStore) IsRoleAssigned(ctx context.Context, grant *Grant, roleID int64) (bool, error) {
+	var has bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		switch {
+		case grant.UserID != 0:
+			has, err = sess.Where("org_id = ? AND user_id = ? AND role_id = ?", grant.OrgID, grant.UserID, roleID).Exist(&accesscontrol.UserRole{})
+		case grant.TeamID != 0:
+			has, err = sess.Where("org_id = ? AND team_id = ? AND role_id = ?", grant.OrgID, grant.TeamID, roleID).Exist(&accesscontrol.TeamRole{})
+		default:
+			has, err = sess.Where("org_id = ? AND role = ? AND role_id = ?", grant.OrgID, grant.BuiltInRole, roleID).Exist(&accesscontrol.BuiltinRole{})
+		}
+		return err
+	})
+	return has, err
+}
+
+func (s *xormStore) AssignRole(ctx context.Context, grant *Grant, roleID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		now := time.Now()
+		var err error
+		switch {
+		case grant.UserID != 0:
+			_, err = sess.Insert(&accesscontrol.UserRole{OrgID: grant.OrgID, UserID: grant.UserID, RoleID: roleID, Created: now})
+		case grant.TeamID != 0:
+			_, err = sess.Insert(&accesscontrol.TeamRole{OrgID: grant.OrgID, TeamID: grant.TeamID, RoleID: roleID, Created: now})
+		default:
+			_, err = sess.Insert(&accesscontrol.BuiltinRole{OrgID: grant.OrgID, Role: grant.BuiltInRole, RoleID: roleID, Created: now, Updated: now})
+		}
+		return err
+	})
+}
+
+func (s *xormStore) UnassignRole(ctx context.Context, grant *Grant, roleID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		switch {
+		case grant.UserID != 0:
+			_, err = sess.Exec("DELETE FROM user_role WHERE org_id = ? AND user_id = ? AND role_id = ?", grant.OrgID, grant.UserID, roleID)
+		case grant.TeamID != 0:
+			_, err = sess.Exec("DELETE FROM team_role WHERE org_id = ? AND team_id = ? AND role_id = ?", grant.OrgID, grant.TeamID, roleID)
+		default:
+			_, err = sess.Exec("DELETE FROM builtin_role WHERE org_id = ? AND role = ? AND role_id = ?", grant.OrgID, grant.BuiltInRole, roleID)
+		}
+		return err
+	})
+}
@@ -347,6 +347,7 @@ const (
 	// We can ignore gosec G101 since this does not contain any credentials.
 	// nolint:gosec
 	ActionUsersPasswordUpdate    = "users.password:write"
+	ActionUsersMFAReset          = "users.mfa:reset"
 	ActionUsersDelete            = "users:delete"
 	ActionUsersCreate            = "users:create"
 	ActionUsersEnable            = "users:enable"
@@ -356,6 +357,7 @@ const (
 	ActionUsersQuotasList        = "users.quotas:read"
 	ActionUsersQuotasUpdate      = "users.quotas:write"
 	ActionUsersPermissionsRead   = "users.permissions:read"
+	ActionUsersRolesAdd          = "users.roles:add"
 
 	// Org actions
 	ActionOrgsRead             = "orgs:read"
@@ -388,6 +390,12 @@ const (
 	// Datasources actions
 	ActionDatasourcesExplore = "datasources:explore"
 
+	// Roles actions
+	ActionRolesWrite = "roles:write"
+
+	// Permissions scope, limits role management to the permissions of the requester
+	ScopePermissionsDelegate = "permissions:type:delegate"
+
 	// Global Scopes
 	ScopeGlobalUsersAll = "global.users:*"
 
@@ -409,6 +417,7 @@ const (
 	ActionTeamsWrite            = "teams:write"
 	ActionTeamsPermissionsRead  = "teams.permissions:read"
 	ActionTeamsPermissionsWrite = "teams.permissions:write"
+	ActionTeamsRolesAdd         = "teams.roles:add"
 
 	// Team related scopes
 	ScopeTeamsAll = "teams:*"
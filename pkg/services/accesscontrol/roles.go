@@ -158,13 +158,17 @@ var (
 	usersWriterRole = RoleDTO{
 		Name:        "fixed:users:writer",
 		DisplayName: "User writer",
-		Description: "Read and update all attributes and settings for all users in Grafana: update user information, read user information, create or enable or disable a user, make a user a Grafana administrator, sign out a user, update a user’s authentication token, or update quotas for all users.",
+		Description: "Read and update all attributes and settings for all users in Grafana: update user information, read user information, create or enable or disable a user, make a user a Grafana administrator, sign out a user, update a user’s authentication token, reset a user’s second factors, or update quotas for all users.",
 		Group:       "User administration (global)",
 		Permissions: ConcatPermissions(usersReaderRole.Permissions, []Permission{
 			{
 				Action: ActionUsersPasswordUpdate,
 				Scope:  ScopeGlobalUsersAll,
 			},
+			{
+				Action: ActionUsersMFAReset,
+				Scope:  ScopeGlobalUsersAll,
+			},
 			{
 				Action: ActionUsersCreate,
 			},
@@ -293,6 +297,27 @@ var (
 		},
 	}
 
+	rolesAssignerRole = RoleDTO{
+		Name:        "fixed:roles:assigner",
+		DisplayName: "Role assigner",
+		Description: "Assign roles to users and teams, and add roles to basic roles, with time-bound access grants. Only roles with permissions you have can be assigned.",
+		Group:       "Access control",
+		Permissions: []Permission{
+			{
+				Action: ActionUsersRolesAdd,
+				Scope:  ScopePermissionsDelegate,
+			},
+			{
+				Action: ActionTeamsRolesAdd,
+				Scope:  ScopePermissionsDelegate,
+			},
+			{
+				Action: ActionRolesWrite,
+				Scope:  ScopePermissionsDelegate,
+			},
+		},
+	}
+
 	usagestatsReaderRole = RoleDTO{
 		Name:        "fixed:usagestats:reader",
 		DisplayName: "Usage stats report reader",
@@ -353,10 +378,15 @@ func DeclareFixedRoles(service Service, cfg *setting.Cfg) error {
 		Grants: []string{RoleGrafanaAdmin},
 	}
 
+	rolesAssigner := RoleRegistration{
+		Role:   rolesAssignerRole,
+		Grants: []string{RoleGrafanaAdmin},
+	}
+
 	return service.DeclareFixedRoles(
 		ldapReader, ldapWriter, orgUsersReader, orgUsersWriter,
 		settingsReader, statsReader, usersReader, usersWriter,
-		authenticationConfigWriter, generalAuthConfigWriter, usageStatsReader,
+		authenticationConfigWriter, generalAuthConfigWriter, usageStatsReader, rolesAssigner,
 	)
 }
 
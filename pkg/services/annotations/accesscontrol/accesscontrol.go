@@ -14,6 +14,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 )
 
+// DashboardsPageSize is the number of dashboards returned by Authorize for each page of the query.
+const DashboardsPageSize = 1000
+
 var (
 	ErrReadForbidden = errutil.NewBase(
 		errutil.StatusForbidden,
@@ -142,7 +145,7 @@ func (authz *AuthService) dashboardsWithVisibleAnnotations(ctx context.Context,
 		SignedInUser: query.SignedInUser,
 		Page:         query.Page,
 		Type:         filterType,
-		Limit:        1000,
+		Limit:        DashboardsPageSize,
 	})
 	if err != nil {
 		return nil, err
@@ -100,6 +100,29 @@ func (_m *FakeAnnotationsRepo) SaveMany(ctx context.Context, items []Item) error
 	return r0
 }
 
+// Summarize provides a mock function with given fields: ctx, query
+func (_m *FakeAnnotationsRepo) Summarize(ctx context.Context, query *SummaryQuery) (*Summary, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *Summary
+	if rf, ok := ret.Get(0).(func(context.Context, *SummaryQuery) *Summary); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Summary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *SummaryQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, item
 func (_m *FakeAnnotationsRepo) Update(ctx context.Context, item *Item) error {
 	ret := _m.Called(ctx, item)
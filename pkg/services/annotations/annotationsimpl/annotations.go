@@ -120,6 +120,36 @@ func (r *RepositoryImpl) Find(ctx context.Context, query *annotations.ItemQuery)
 	return results, nil
 }
 
+// Summarize returns the annotations matching the query bucketed over time,
+// for zoom levels where returning every annotation would be too expensive.
+func (r *RepositoryImpl) Summarize(ctx context.Context, query *annotations.SummaryQuery) (*annotations.Summary, error) {
+	if query.Buckets == 0 {
+		query.Buckets = annotations.DefaultSummaryBuckets
+	}
+
+	// Unlike Find, the summary isn't limited, so it needs all the dashboards the user can access at once
+	var resources *accesscontrol.AccessResources
+	itemQuery := query.ItemQuery
+	for itemQuery.Page = 1; ; itemQuery.Page++ {
+		page, err := r.authZ.Authorize(ctx, itemQuery)
+		if err != nil {
+			return nil, err
+		}
+		if resources == nil {
+			resources = page
+		} else {
+			for uid, id := range page.Dashboards {
+				resources.Dashboards[uid] = id
+			}
+		}
+		if len(page.Dashboards) < accesscontrol.DashboardsPageSize {
+			break
+		}
+	}
+
+	return r.reader.GetSummary(ctx, *query, resources)
+}
+
 func (r *RepositoryImpl) Delete(ctx context.Context, params *annotations.DeleteParams) error {
 	return r.writer.Delete(ctx, params)
 }
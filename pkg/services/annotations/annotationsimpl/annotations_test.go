@@ -191,6 +191,18 @@ func TestIntegrationAnnotationListingWithRBAC(t *testing.T) {
 			for _, r := range results {
 				assert.Contains(t, tc.expectedAnnotationIds, r.ID)
 			}
+
+			summary, err := repo.Summarize(context.Background(), &annotations.SummaryQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:        1,
+					From:         1,
+					To:           101,
+					SignedInUser: u,
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.expectedAnnotationIds)), summary.Total)
+			assert.Len(t, summary.Buckets, annotations.DefaultSummaryBuckets)
 		})
 	}
 }
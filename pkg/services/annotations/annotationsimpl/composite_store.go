@@ -81,6 +81,35 @@ func (c *CompositeStore) GetTags(ctx context.Context, query annotations.TagsQuer
 	return annotations.FindTagsResult{Tags: res}, nil
 }
 
+// GetSummary returns summaries from all stores, and merges them.
+func (c *CompositeStore) GetSummary(ctx context.Context, query annotations.SummaryQuery, accessResources *accesscontrol.AccessResources) (*annotations.Summary, error) {
+	res, err := annotations.NewSummary(query)
+	if err != nil {
+		return nil, err
+	}
+	resCh := make(chan *annotations.Summary, len(c.readers))
+
+	err = concurrency.ForEachJob(ctx, len(c.readers), len(c.readers), func(ctx context.Context, i int) (err error) {
+		defer handleJobPanic(c.logger, c.readers[i].Type(), &err)
+
+		summary, err := c.readers[i].GetSummary(ctx, query, accessResources)
+		resCh <- summary
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	close(resCh)
+	for summary := range resCh {
+		if summary != nil {
+			res.Merge(summary)
+		}
+	}
+
+	return res, nil
+}
+
 // handleJobPanic is a helper function that recovers from a panic in a concurrent job.,
 // It will log the error and set the job error if it is not nil.
 func handleJobPanic(logger log.Logger, storeType string, jobErr *error) {
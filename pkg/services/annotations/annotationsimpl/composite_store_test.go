@@ -15,8 +15,9 @@ import (
 )
 
 var (
-	errGet     = errors.New("get error")
-	errGetTags = errors.New("get tags error")
+	errGet        = errors.New("get error")
+	errGetTags    = errors.New("get tags error")
+	errGetSummary = errors.New("get summary error")
 )
 
 func TestCompositeStore(t *testing.T) {
@@ -59,6 +60,12 @@ func TestCompositeStore(t *testing.T) {
 				f:   func() (any, error) { return store.GetTags(context.Background(), annotations.TagsQuery{}) },
 				err: errGetTags,
 			},
+			{
+				f: func() (any, error) {
+					return store.GetSummary(context.Background(), annotations.SummaryQuery{ItemQuery: annotations.ItemQuery{From: 1, To: 2}, Buckets: 1}, nil)
+				},
+				err: errGetSummary,
+			},
 		}
 
 		for _, tt := range tc {
@@ -127,6 +134,40 @@ func TestCompositeStore(t *testing.T) {
 		require.Equal(t, expected, res.Tags)
 	})
 
+	t.Run("should merge summaries from GetSummary", func(t *testing.T) {
+		r1 := newFakeReader(withItems([]*annotations.ItemDTO{
+			{Time: 1, TimeEnd: 4, Tags: []string{"deploy"}},
+			{Time: 12, TimeEnd: 12},
+		}))
+		r2 := newFakeReader(withItems([]*annotations.ItemDTO{
+			{Time: 3, TimeEnd: 6, AlertID: 1},
+			{Time: 8, TimeEnd: 9, AlertID: 1},
+		}))
+
+		store := &CompositeStore{
+			log.NewNopLogger(),
+			[]readStore{r1, r2},
+		}
+
+		query := annotations.SummaryQuery{ItemQuery: annotations.ItemQuery{From: 1, To: 21}, Buckets: 2}
+		res, err := store.GetSummary(context.Background(), query, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, int64(4), res.Total)
+		require.Equal(t, &annotations.SummaryBucket{
+			From:  1,
+			To:    11,
+			Count: 3,
+			Types: map[string]int64{"annotation": 1, "alert": 2},
+			Tags:  map[string]int64{"deploy": 1},
+		}, res.Buckets[0])
+		require.Equal(t, int64(1), res.Buckets[1].Count)
+		require.Equal(t, []*annotations.SummaryRegion{
+			{Time: 1, TimeEnd: 6, Count: 2},
+			{Time: 8, TimeEnd: 9, Count: 1},
+		}, res.Regions)
+	})
+
 	// Check if reader is not modifying query since it might cause a race condition in case of composite store
 	t.Run("should not modify query", func(t *testing.T) {
 		getFn1 := func(ctx context.Context, query annotations.ItemQuery, resources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error) {
@@ -199,6 +240,31 @@ func (f *fakeReader) GetTags(ctx context.Context, query annotations.TagsQuery) (
 	return f.tagRes, nil
 }
 
+func (f *fakeReader) GetSummary(ctx context.Context, query annotations.SummaryQuery, accessResources *accesscontrol.AccessResources) (*annotations.Summary, error) {
+	if f.wait > 0 {
+		time.Sleep(f.wait)
+	}
+
+	if f.err != nil {
+		err := fmt.Errorf("%w: %w", errGetSummary, f.err)
+		return nil, err
+	}
+
+	summary, err := annotations.NewSummary(query)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range f.items {
+		annotationType := annotations.TypeAnnotation
+		if item.AlertID != 0 {
+			annotationType = annotations.TypeAlert
+		}
+		summary.Add(item.Time, item.TimeEnd, annotationType, item.Tags)
+	}
+	summary.Compact()
+	return summary, nil
+}
+
 func withWait(wait time.Duration) func(*fakeReader) {
 	return func(f *fakeReader) {
 		f.wait = wait
@@ -34,6 +34,8 @@ import (
 const (
 	subsystem         = "annotations"
 	defaultQueryRange = 6 * time.Hour // from grafana/pkg/services/ngalert/state/historian/loki.go
+	// summaryQueryLimit is the maximum number of state transitions counted in a summary, it's the largest page Loki returns.
+	summaryQueryLimit = 5000
 )
 
 var (
@@ -81,14 +83,20 @@ func (r *LokiHistorianStore) Type() string {
 }
 
 func (r *LokiHistorianStore) Get(ctx context.Context, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error) {
+	items, _, err := r.get(ctx, query, accessResources)
+	return items, err
+}
+
+// get returns the state transitions matching the query, and whether Loki returned only the first query.Limit of them.
+func (r *LokiHistorianStore) get(ctx context.Context, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, bool, error) {
 	if query.Type == "annotation" {
-		return make([]*annotations.ItemDTO, 0), nil
+		return make([]*annotations.ItemDTO, 0), false, nil
 	}
 
 	// if the query is filtering on tags, but not on a specific dashboard, we shouldn't query loki
 	// since state history won't have tags for annotations
 	if len(query.Tags) > 0 && query.DashboardID == 0 && query.DashboardUID == "" {
-		return make([]*annotations.ItemDTO, 0), nil
+		return make([]*annotations.ItemDTO, 0), false, nil
 	}
 
 	rule := &ngmodels.AlertRule{}
@@ -97,9 +105,9 @@ func (r *LokiHistorianStore) Get(ctx context.Context, query annotations.ItemQuer
 		rule, err = r.ruleStore.GetRuleByID(ctx, ngmodels.GetAlertRuleByIDQuery{OrgID: query.OrgID, ID: query.AlertID})
 		if err != nil {
 			if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
-				return make([]*annotations.ItemDTO, 0), ErrLokiStoreNotFound.Errorf("rule with ID %d does not exist", query.AlertID)
+				return make([]*annotations.ItemDTO, 0), false, ErrLokiStoreNotFound.Errorf("rule with ID %d does not exist", query.AlertID)
 			}
-			return make([]*annotations.ItemDTO, 0), ErrLokiStoreInternal.Errorf("failed to query rule: %w", err)
+			return make([]*annotations.ItemDTO, 0), false, ErrLokiStoreInternal.Errorf("failed to query rule: %w", err)
 		}
 	}
 
@@ -108,9 +116,9 @@ func (r *LokiHistorianStore) Get(ctx context.Context, query annotations.ItemQuer
 	if err != nil {
 		grafanaErr := errutil.Error{}
 		if errors.As(err, &grafanaErr) {
-			return make([]*annotations.ItemDTO, 0), err
+			return make([]*annotations.ItemDTO, 0), false, err
 		}
-		return make([]*annotations.ItemDTO, 0), ErrLokiStoreInternal.Errorf("failed to build loki query: %w", err)
+		return make([]*annotations.ItemDTO, 0), false, ErrLokiStoreInternal.Errorf("failed to build loki query: %w", err)
 	}
 	if len(logQL) > 1 {
 		r.log.FromContext(ctx).Info("Execute query in multiple batches", "batches", logQL, "maxQueryLimit", r.client.MaxQuerySize())
@@ -128,17 +136,24 @@ func (r *LokiHistorianStore) Get(ctx context.Context, query annotations.ItemQuer
 	from := query.From * 1e6
 	to := query.To * 1e6
 	items := make([]*annotations.ItemDTO, 0)
+	truncated := false
 	for _, q := range logQL {
 		res, err := r.client.RangeQuery(ctx, q, from, to, query.Limit)
 		if err != nil {
-			return make([]*annotations.ItemDTO, 0), ErrLokiStoreInternal.Errorf("failed to query loki: %w", err)
+			return make([]*annotations.ItemDTO, 0), false, ErrLokiStoreInternal.Errorf("failed to query loki: %w", err)
 		}
+		// The limit applies to the entries Loki returns, before the ones the user can't access are skipped.
+		entries := 0
 		for _, stream := range res.Data.Result {
+			entries += len(stream.Values)
 			items = append(items, r.annotationsFromStream(stream, *accessResources)...)
 		}
+		if query.Limit > 0 && int64(entries) >= query.Limit {
+			truncated = true
+		}
 	}
 	sort.Sort(annotations.SortedItems(items))
-	return items, err
+	return items, truncated, err
 }
 
 func (r *LokiHistorianStore) annotationsFromStream(stream historian.Stream, ac accesscontrol.AccessResources) []*annotations.ItemDTO {
@@ -196,6 +211,28 @@ func (r *LokiHistorianStore) GetTags(ctx context.Context, query annotations.Tags
 	return annotations.FindTagsResult{Tags: []*annotations.TagsDTO{}}, nil
 }
 
+// GetSummary counts the alert state transitions in Loki. State history doesn't have tags, so only the types are counted.
+// Loki returns at most summaryQueryLimit transitions per query, the summary is marked as truncated when it does.
+func (r *LokiHistorianStore) GetSummary(ctx context.Context, query annotations.SummaryQuery, accessResources *accesscontrol.AccessResources) (*annotations.Summary, error) {
+	summary, err := annotations.NewSummary(query)
+	if err != nil {
+		return nil, err
+	}
+
+	itemQuery := query.ItemQuery
+	itemQuery.Limit = summaryQueryLimit
+	items, truncated, err := r.get(ctx, itemQuery, accessResources)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		summary.Add(item.Time, item.TimeEnd, annotations.TypeAlert, item.Tags)
+	}
+	summary.Truncated = truncated
+	summary.Compact()
+	return summary, nil
+}
+
 // util
 
 func hasAccess(entry historian.LokiEntry, resources accesscontrol.AccessResources) bool {
@@ -163,6 +163,70 @@ func TestIntegrationAlertStateHistoryStore(t *testing.T) {
 			require.Len(t, res, 2*numTransitions)
 		})
 
+		t.Run("can summarize history by dashboard id", func(t *testing.T) {
+			fakeLokiClient.rangeQueryRes = []historian.Stream{
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), transitions, map[string]string{}, log.NewNopLogger()),
+				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][1]), transitions, map[string]string{}, log.NewNopLogger()),
+			}
+
+			query := annotations.SummaryQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:       1,
+					DashboardID: dashboard1.ID,
+					From:        start.UnixMilli(),
+					To:          start.Add(time.Second * time.Duration(numTransitions+1)).UnixMilli(),
+				},
+				Buckets: 1,
+			}
+			res, err := store.GetSummary(
+				context.Background(),
+				query,
+				&annotation_ac.AccessResources{
+					Dashboards: map[string]int64{
+						dashboard1.UID: dashboard1.ID,
+					},
+					CanAccessDashAnnotations: true,
+				},
+			)
+			require.NoError(t, err)
+			require.Equal(t, int64(2*numTransitions), res.Total)
+			require.Equal(t, map[string]int64{annotations.TypeAlert: int64(2 * numTransitions)}, res.Buckets[0].Types)
+			require.Empty(t, res.Buckets[0].Tags)
+			require.False(t, res.Truncated)
+		})
+
+		t.Run("marks the summary as truncated when loki returns the maximum number of transitions", func(t *testing.T) {
+			stream := historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), genStateTransitions(t, 1, start), map[string]string{}, log.NewNopLogger())
+			require.Len(t, stream.Values, 1)
+			for len(stream.Values) < summaryQueryLimit {
+				stream.Values = append(stream.Values, stream.Values[0])
+			}
+			fakeLokiClient.rangeQueryRes = []historian.Stream{stream}
+
+			query := annotations.SummaryQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:       1,
+					DashboardID: dashboard1.ID,
+					From:        start.UnixMilli(),
+					To:          start.Add(time.Second).UnixMilli(),
+				},
+				Buckets: 1,
+			}
+			res, err := store.GetSummary(
+				context.Background(),
+				query,
+				&annotation_ac.AccessResources{
+					Dashboards: map[string]int64{
+						dashboard1.UID: dashboard1.ID,
+					},
+					CanAccessDashAnnotations: true,
+				},
+			)
+			require.NoError(t, err)
+			require.Equal(t, int64(summaryQueryLimit), res.Total)
+			require.True(t, res.Truncated)
+		})
+
 		t.Run("should return empty results when type is annotation", func(t *testing.T) {
 			fakeLokiClient.rangeQueryRes = []historian.Stream{
 				historian.StatesToStream(ruleMetaFromRule(t, dashboardRules[dashboard1.UID][0]), transitions, map[string]string{}, log.NewNopLogger()),
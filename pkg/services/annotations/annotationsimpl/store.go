@@ -22,6 +22,7 @@ type readStore interface {
 	commonStore
 	Get(ctx context.Context, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]*annotations.ItemDTO, error)
 	GetTags(ctx context.Context, query annotations.TagsQuery) (annotations.FindTagsResult, error)
+	GetSummary(ctx context.Context, query annotations.SummaryQuery, accessResources *accesscontrol.AccessResources) (*annotations.Summary, error)
 }
 
 type writeStore interface {
@@ -275,102 +275,198 @@ func (r *xormRepositoryImpl) Get(ctx context.Context, query annotations.ItemQuer
 				SELECT a.id from annotation a
 			`)
 
-		sql.WriteString(`WHERE a.org_id = ?`)
-		params = append(params, query.OrgID)
-
-		if query.AnnotationID != 0 {
-			// fmt.Print("annotation query")
-			sql.WriteString(` AND a.id = ?`)
-			params = append(params, query.AnnotationID)
+		var err error
+		params, err = r.writeFilters(&sql, params, query, accessResources)
+		if err != nil {
+			return err
 		}
 
-		if query.AlertID != 0 {
-			sql.WriteString(` AND a.alert_id = ?`)
-			params = append(params, query.AlertID)
+		// order of ORDER BY arguments match the order of a sql index for performance
+		orderBy := " ORDER BY a.org_id, a.epoch_end DESC, a.epoch DESC"
+		if query.Limit > 0 {
+			orderBy += r.db.GetDialect().Limit(query.Limit)
 		}
+		sql.WriteString(orderBy + " ) dt on dt.id = annotation.id")
 
-		if query.DashboardID != 0 {
-			sql.WriteString(` AND a.dashboard_id = ?`)
-			params = append(params, query.DashboardID)
+		if err := sess.SQL(sql.String(), params...).Find(&items); err != nil {
+			items = nil
+			return err
 		}
+		return nil
+	},
+	)
 
-		if query.PanelID != 0 {
-			sql.WriteString(` AND a.panel_id = ?`)
-			params = append(params, query.PanelID)
-		}
+	return items, err
+}
 
-		if query.UserID != 0 {
-			sql.WriteString(` AND a.user_id = ?`)
-			params = append(params, query.UserID)
-		}
+// GetSummary counts the annotations matching the query in the database, grouped by bucket, so it isn't limited
+// like Get. Only the time ranges of region annotations are loaded, to merge the overlapping ones.
+func (r *xormRepositoryImpl) GetSummary(ctx context.Context, query annotations.SummaryQuery, accessResources *accesscontrol.AccessResources) (*annotations.Summary, error) {
+	summary, err := annotations.NewSummary(query)
+	if err != nil {
+		return nil, err
+	}
 
-		if query.From > 0 && query.To > 0 {
-			sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
-			params = append(params, query.To, query.From)
-		}
+	// The bucket of an annotation is identified by its start, epoch - (epoch - from) % width. The modulo keeps the
+	// arithmetic in integers on all databases, unlike a division. Annotations starting before the time range get a
+	// start before it and are counted in the first bucket.
+	bucket := fmt.Sprintf("(a.epoch - ((a.epoch - %d) %% %d))", query.From, summary.BucketWidth())
+	annotationType := fmt.Sprintf("CASE WHEN a.alert_id > 0 THEN '%s' ELSE '%s' END", annotations.TypeAlert, annotations.TypeAnnotation)
+	dialect := r.db.GetDialect()
+
+	type typeCount struct {
+		Bucket int64  `xorm:"bucket"`
+		Type   string `xorm:"annotation_type"`
+		Total  int64  `xorm:"total"`
+	}
+	type tagCount struct {
+		Bucket int64  `xorm:"bucket"`
+		Key    string `xorm:"tag_key"`
+		Value  string `xorm:"tag_value"`
+		Total  int64  `xorm:"total"`
+	}
+	type region struct {
+		Time    int64 `xorm:"time"`
+		TimeEnd int64 `xorm:"time_end"`
+	}
+	types := make([]*typeCount, 0)
+	tags := make([]*tagCount, 0)
+	regions := make([]*region, 0)
 
-		if query.Type == "alert" {
-			sql.WriteString(` AND a.alert_id > 0`)
-		} else if query.Type == "annotation" {
-			sql.WriteString(` AND a.alert_id = 0`)
+	err = r.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var sql bytes.Buffer
+		sql.WriteString(fmt.Sprintf(`SELECT %s AS bucket, %s AS annotation_type, COUNT(*) AS total FROM annotation a `, bucket, annotationType))
+		params, err := r.writeFilters(&sql, make([]any, 0), query.ItemQuery, accessResources)
+		if err != nil {
+			return err
 		}
-
-		if len(query.Tags) > 0 {
-			keyValueFilters := []string{}
-
-			tags := tag.ParseTagPairs(query.Tags)
-			for _, tag := range tags {
-				if tag.Value == "" {
-					keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ?)")
-					params = append(params, tag.Key)
-				} else {
-					keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ? AND tag."+r.db.GetDialect().Quote("value")+" = ?)")
-					params = append(params, tag.Key, tag.Value)
-				}
-			}
-
-			if len(tags) > 0 {
-				tagsSubQuery := fmt.Sprintf(`
-			SELECT SUM(1) FROM annotation_tag at
-			INNER JOIN tag on tag.id = at.tag_id
-			WHERE at.annotation_id = a.id
-				AND (
-				%s
-				)
-		`, strings.Join(keyValueFilters, " OR "))
-
-				if query.MatchAny {
-					sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
-				} else {
-					sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
-				}
-			}
+		sql.WriteString(fmt.Sprintf(` GROUP BY %s, %s`, bucket, annotationType))
+		if err := sess.SQL(sql.String(), params...).Find(&types); err != nil {
+			return err
 		}
 
-		acFilter, err := r.getAccessControlFilter(query.SignedInUser, accessResources)
+		sql.Reset()
+		sql.WriteString(fmt.Sprintf(`SELECT %s AS bucket, st.%s AS tag_key, st.%s AS tag_value, COUNT(*) AS total FROM annotation a
+			INNER JOIN annotation_tag sat ON sat.annotation_id = a.id
+			INNER JOIN tag st ON st.id = sat.tag_id `, bucket, dialect.Quote("key"), dialect.Quote("value")))
+		params, err = r.writeFilters(&sql, make([]any, 0), query.ItemQuery, accessResources)
 		if err != nil {
 			return err
 		}
-		if acFilter != "" {
-			sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
+		sql.WriteString(fmt.Sprintf(` GROUP BY %s, st.%s, st.%s`, bucket, dialect.Quote("key"), dialect.Quote("value")))
+		if err := sess.SQL(sql.String(), params...).Find(&tags); err != nil {
+			return err
 		}
 
-		// order of ORDER BY arguments match the order of a sql index for performance
-		orderBy := " ORDER BY a.org_id, a.epoch_end DESC, a.epoch DESC"
-		if query.Limit > 0 {
-			orderBy += r.db.GetDialect().Limit(query.Limit)
+		sql.Reset()
+		sql.WriteString(`SELECT a.epoch AS time, a.epoch_end AS time_end FROM annotation a `)
+		params, err = r.writeFilters(&sql, make([]any, 0), query.ItemQuery, accessResources)
+		if err != nil {
+			return err
 		}
-		sql.WriteString(orderBy + " ) dt on dt.id = annotation.id")
+		sql.WriteString(` AND a.epoch_end > a.epoch`)
+		return sess.SQL(sql.String(), params...).Find(&regions)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		if err := sess.SQL(sql.String(), params...).Find(&items); err != nil {
-			items = nil
-			return err
+	for _, c := range types {
+		summary.AddCount(c.Bucket, c.Type, c.Total)
+	}
+	for _, c := range tags {
+		summary.AddTagCount(c.Bucket, tag.JoinTagPairs([]*tag.Tag{{Key: c.Key, Value: c.Value}})[0], c.Total)
+	}
+	for _, r := range regions {
+		summary.AddRegion(r.Time, r.TimeEnd)
+	}
+	summary.Compact()
+	return summary, nil
+}
+
+// writeFilters writes the WHERE clause of the annotation queries for the table aliased as "a".
+func (r *xormRepositoryImpl) writeFilters(sql *bytes.Buffer, params []any, query annotations.ItemQuery, accessResources *accesscontrol.AccessResources) ([]any, error) {
+	sql.WriteString(`WHERE a.org_id = ?`)
+	params = append(params, query.OrgID)
+
+	if query.AnnotationID != 0 {
+		// fmt.Print("annotation query")
+		sql.WriteString(` AND a.id = ?`)
+		params = append(params, query.AnnotationID)
+	}
+
+	if query.AlertID != 0 {
+		sql.WriteString(` AND a.alert_id = ?`)
+		params = append(params, query.AlertID)
+	}
+
+	if query.DashboardID != 0 {
+		sql.WriteString(` AND a.dashboard_id = ?`)
+		params = append(params, query.DashboardID)
+	}
+
+	if query.PanelID != 0 {
+		sql.WriteString(` AND a.panel_id = ?`)
+		params = append(params, query.PanelID)
+	}
+
+	if query.UserID != 0 {
+		sql.WriteString(` AND a.user_id = ?`)
+		params = append(params, query.UserID)
+	}
+
+	if query.From > 0 && query.To > 0 {
+		sql.WriteString(` AND a.epoch <= ? AND a.epoch_end >= ?`)
+		params = append(params, query.To, query.From)
+	}
+
+	if query.Type == "alert" {
+		sql.WriteString(` AND a.alert_id > 0`)
+	} else if query.Type == "annotation" {
+		sql.WriteString(` AND a.alert_id = 0`)
+	}
+
+	if len(query.Tags) > 0 {
+		keyValueFilters := []string{}
+
+		tags := tag.ParseTagPairs(query.Tags)
+		for _, tag := range tags {
+			if tag.Value == "" {
+				keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ?)")
+				params = append(params, tag.Key)
+			} else {
+				keyValueFilters = append(keyValueFilters, "(tag."+r.db.GetDialect().Quote("key")+" = ? AND tag."+r.db.GetDialect().Quote("value")+" = ?)")
+				params = append(params, tag.Key, tag.Value)
+			}
 		}
-		return nil
-	},
-	)
 
-	return items, err
+		if len(tags) > 0 {
+			tagsSubQuery := fmt.Sprintf(`
+		SELECT SUM(1) FROM annotation_tag at
+		INNER JOIN tag on tag.id = at.tag_id
+		WHERE at.annotation_id = a.id
+			AND (
+			%s
+			)
+	`, strings.Join(keyValueFilters, " OR "))
+
+			if query.MatchAny {
+				sql.WriteString(fmt.Sprintf(" AND (%s) > 0 ", tagsSubQuery))
+			} else {
+				sql.WriteString(fmt.Sprintf(" AND (%s) = %d ", tagsSubQuery, len(tags)))
+			}
+		}
+	}
+
+	acFilter, err := r.getAccessControlFilter(query.SignedInUser, accessResources)
+	if err != nil {
+		return nil, err
+	}
+	if acFilter != "" {
+		sql.WriteString(fmt.Sprintf(" AND (%s)", acFilter))
+	}
+
+	return params, nil
 }
 
 func (r *xormRepositoryImpl) getAccessControlFilter(user identity.Requester, accessResources *accesscontrol.AccessResources) (string, error) {
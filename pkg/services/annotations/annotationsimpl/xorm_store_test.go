@@ -156,6 +156,57 @@ func TestIntegrationAnnotations(t *testing.T) {
 			assert.Equal(t, items[0].Updated, items[0].Created)
 		})
 
+		t.Run("Can summarize annotations", func(t *testing.T) {
+			summary, err := store.GetSummary(context.Background(), annotations.SummaryQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:        1,
+					From:         1,
+					To:           31,
+					SignedInUser: testUser,
+				},
+				Buckets: 3,
+			}, &annotation_ac.AccessResources{
+				Dashboards: map[string]int64{
+					dashboard.UID:  dashboard.ID,
+					dashboard2.UID: dashboard2.ID,
+				},
+				CanAccessDashAnnotations: true,
+				CanAccessOrgAnnotations:  true,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(4), summary.Total)
+			require.Len(t, summary.Buckets, 3)
+			assert.Equal(t, []int64{1, 3, 0}, []int64{summary.Buckets[0].Count, summary.Buckets[1].Count, summary.Buckets[2].Count})
+			assert.Equal(t, map[string]int64{"annotation": 3}, summary.Buckets[1].Types)
+			assert.Equal(t, int64(1), summary.Buckets[1].Tags["deploy"])
+			assert.Equal(t, int64(1), summary.Buckets[0].Tags["outage"])
+			assert.Equal(t, int64(1), summary.Buckets[0].Tags["type:outage"])
+			assert.Equal(t, []*annotations.SummaryRegion{{Time: 20, TimeEnd: 21, Count: 1}}, summary.Regions)
+		})
+
+		t.Run("Should only summarize annotations the user can access", func(t *testing.T) {
+			summary, err := store.GetSummary(context.Background(), annotations.SummaryQuery{
+				ItemQuery: annotations.ItemQuery{
+					OrgID:        1,
+					From:         1,
+					To:           31,
+					Tags:         []string{"outage"},
+					SignedInUser: testUser,
+				},
+				Buckets: 3,
+			}, &annotation_ac.AccessResources{
+				Dashboards: map[string]int64{
+					dashboard.UID: dashboard.ID,
+				},
+				CanAccessDashAnnotations: true,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(1), summary.Total)
+			assert.Empty(t, summary.Regions)
+		})
+
 		badAnnotation := &annotations.Item{
 			OrgID:  1,
 			UserID: 1,
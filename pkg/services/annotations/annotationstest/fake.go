@@ -83,6 +83,23 @@ func (repo *fakeAnnotationsRepo) FindTags(_ context.Context, query *annotations.
 	return result, nil
 }
 
+func (repo *fakeAnnotationsRepo) Summarize(_ context.Context, query *annotations.SummaryQuery) (*annotations.Summary, error) {
+	repo.mtx.Lock()
+	defer repo.mtx.Unlock()
+
+	summary, err := annotations.NewSummary(*query)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range repo.annotations {
+		if item.Epoch <= query.To && item.EpochEnd >= query.From {
+			summary.Add(item.Epoch, item.EpochEnd, annotations.TypeAnnotation, item.Tags)
+		}
+	}
+	summary.Compact()
+	return summary, nil
+}
+
 func (repo *fakeAnnotationsRepo) Len() int {
 	repo.mtx.Lock()
 	defer repo.mtx.Unlock()
@@ -0,0 +1,134 @@
+package annotationwebhook
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	// TokenHeader carries the token of events. Tokens aren't accepted in the query string, where they
+	// would end up in access logs and proxy logs.
+	TokenHeader = "X-Grafana-Webhook-Token"
+	// SignatureHeader carries the HMAC-SHA256 signature of events keyed with the token, as sent by
+	// GitHub and the services mimicking it. It authenticates events without a token header.
+	SignatureHeader = "X-Hub-Signature-256"
+
+	maxEventSize = 1 << 20
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+	canCreate := authorize(accesscontrol.EvalPermission(accesscontrol.ActionAnnotationsCreate))
+
+	routeRegister.Group("/api/annotations/webhooks", func(r routing.RouteRegister) {
+		r.Get("/", canCreate, routing.Wrap(s.listHandler))
+		r.Post("/", canCreate, routing.Wrap(s.createHandler))
+		r.Get("/:webhookUID", canCreate, routing.Wrap(s.getHandler))
+		r.Put("/:webhookUID", canCreate, routing.Wrap(s.updateHandler))
+		r.Delete("/:webhookUID", canCreate, routing.Wrap(s.deleteHandler))
+		r.Post("/:webhookUID/token", canCreate, routing.Wrap(s.rotateTokenHandler))
+	}, middleware.ReqSignedIn)
+
+	// Events are authenticated with the token of the webhook, or a signature keyed with it, rather
+	// than a Grafana identity.
+	routeRegister.Post("/api/annotations/webhooks/:webhookUID/events", routing.Wrap(s.ingestHandler))
+}
+
+// GET /api/annotations/webhooks
+func (s *Service) listHandler(c *contextmodel.ReqContext) response.Response {
+	webhooks, err := s.List(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list annotation webhooks", err)
+	}
+	return response.JSON(http.StatusOK, webhooks)
+}
+
+// POST /api/annotations/webhooks
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := CreateWebhookCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	webhook, err := s.Create(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create annotation webhook", err)
+	}
+	return response.JSON(http.StatusOK, webhook)
+}
+
+// GET /api/annotations/webhooks/:webhookUID
+func (s *Service) getHandler(c *contextmodel.ReqContext) response.Response {
+	webhook, err := s.Get(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":webhookUID"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get annotation webhook", err)
+	}
+	return response.JSON(http.StatusOK, webhook)
+}
+
+// PUT /api/annotations/webhooks/:webhookUID
+func (s *Service) updateHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := UpdateWebhookCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	webhook, err := s.Update(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":webhookUID"], cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update annotation webhook", err)
+	}
+	return response.JSON(http.StatusOK, webhook)
+}
+
+// DELETE /api/annotations/webhooks/:webhookUID
+func (s *Service) deleteHandler(c *contextmodel.ReqContext) response.Response {
+	if err := s.Delete(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":webhookUID"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete annotation webhook", err)
+	}
+	return response.Success("Annotation webhook deleted")
+}
+
+// POST /api/annotations/webhooks/:webhookUID/token
+func (s *Service) rotateTokenHandler(c *contextmodel.ReqContext) response.Response {
+	webhook, err := s.RotateToken(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":webhookUID"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to rotate annotation webhook token", err)
+	}
+	return response.JSON(http.StatusOK, webhook)
+}
+
+// POST /api/annotations/webhooks/:webhookUID/events
+func (s *Service) ingestHandler(c *contextmodel.ReqContext) response.Response {
+	token := c.Req.Header.Get(TokenHeader)
+	signature := c.Req.Header.Get(SignatureHeader)
+
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Resp, c.Req.Body, maxEventSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return response.Error(http.StatusRequestEntityTooLarge, "Event too large", err)
+		}
+		return response.Error(http.StatusBadRequest, "Failed to read event", err)
+	}
+
+	item, err := s.Ingest(c.Req.Context(), web.Params(c.Req)[":webhookUID"], token, signature, payload)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save annotation", err)
+	}
+	if item == nil {
+		return response.Success("Event filtered out")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message": "Annotation added",
+		"id":      item.ID,
+	})
+}
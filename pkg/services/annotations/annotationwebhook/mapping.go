@@ -0,0 +1,197 @@
+package annotationwebhook
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jmespath-community/go-jmespath"
+)
+
+// event is an annotation mapped from a webhook payload.
+type event struct {
+	Time    int64
+	TimeEnd int64
+	Text    string
+	Tags    []string
+}
+
+type compiledMapping struct {
+	time    jmespath.JMESPath
+	timeEnd jmespath.JMESPath
+	filter  jmespath.JMESPath
+	text    *template.Template
+	tags    []jmespath.JMESPath
+}
+
+func compileMapping(m Mapping) (*compiledMapping, error) {
+	var err error
+	c := &compiledMapping{}
+	if c.time, err = compileExpression("time", m.Time); err != nil {
+		return nil, err
+	}
+	if c.timeEnd, err = compileExpression("timeEnd", m.TimeEnd); err != nil {
+		return nil, err
+	}
+	if c.filter, err = compileExpression("filter", m.Filter); err != nil {
+		return nil, err
+	}
+	for i, expr := range m.Tags {
+		if strings.TrimSpace(expr) == "" {
+			return nil, ErrInvalidWebhook.Errorf("tag expression %d is empty", i)
+		}
+		tag, err := compileExpression(fmt.Sprintf("tags[%d]", i), expr)
+		if err != nil {
+			return nil, err
+		}
+		c.tags = append(c.tags, tag)
+	}
+
+	if strings.TrimSpace(m.Text) == "" {
+		return nil, ErrInvalidWebhook.Errorf("mapping of the text is required")
+	}
+	if c.text, err = template.New("text").Parse(m.Text); err != nil {
+		return nil, ErrInvalidWebhook.Errorf("invalid text template: %w", err)
+	}
+	return c, nil
+}
+
+func compileExpression(field, expr string) (jmespath.JMESPath, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	compiled, err := jmespath.Compile(expr)
+	if err != nil {
+		return nil, ErrInvalidWebhook.Errorf("invalid %s expression %q: %w", field, expr, err)
+	}
+	return compiled, nil
+}
+
+// apply maps the payload to an annotation. It returns nil when the payload doesn't pass the filter.
+func (c *compiledMapping) apply(payload any, received time.Time) (*event, error) {
+	if c.filter != nil {
+		match, err := c.filter.Search(payload)
+		if err != nil {
+			return nil, ErrInvalidEvent.Errorf("failed to evaluate filter: %w", err)
+		}
+		if !isTruthy(match) {
+			return nil, nil
+		}
+	}
+
+	e := &event{Time: received.UnixMilli()}
+	if c.time != nil {
+		epoch, found, err := searchTime(c.time, payload)
+		if err != nil {
+			return nil, ErrInvalidEvent.Errorf("invalid time: %w", err)
+		}
+		if found {
+			e.Time = epoch
+		}
+	}
+	e.TimeEnd = e.Time
+	if c.timeEnd != nil {
+		epoch, found, err := searchTime(c.timeEnd, payload)
+		if err != nil {
+			return nil, ErrInvalidEvent.Errorf("invalid timeEnd: %w", err)
+		}
+		if found {
+			e.TimeEnd = epoch
+		}
+	}
+	if e.TimeEnd < e.Time {
+		return nil, ErrInvalidEvent.Errorf("timeEnd %d is before time %d", e.TimeEnd, e.Time)
+	}
+
+	var text bytes.Buffer
+	if err := c.text.Execute(&text, payload); err != nil {
+		return nil, ErrInvalidEvent.Errorf("failed to render text: %w", err)
+	}
+	// Fields missing from the payload render as "<no value>", drop them rather than failing the event.
+	e.Text = strings.TrimSpace(strings.ReplaceAll(text.String(), "<no value>", ""))
+	if e.Text == "" {
+		return nil, ErrInvalidEvent.Errorf("the text of the annotation is empty")
+	}
+
+	seen := map[string]bool{}
+	for _, expr := range c.tags {
+		value, err := expr.Search(payload)
+		if err != nil {
+			return nil, ErrInvalidEvent.Errorf("failed to evaluate tags: %w", err)
+		}
+		for _, tag := range tagValues(value) {
+			if tag = strings.TrimSpace(tag); tag != "" && !seen[tag] {
+				seen[tag] = true
+				e.Tags = append(e.Tags, tag)
+			}
+		}
+	}
+	return e, nil
+}
+
+// searchTime evaluates a time expression to epoch milliseconds. Numbers are taken as milliseconds,
+// strings either as milliseconds or as RFC 3339 timestamps.
+func searchTime(expr jmespath.JMESPath, payload any) (int64, bool, error) {
+	value, err := expr.Search(payload)
+	if err != nil {
+		return 0, false, err
+	}
+	switch v := value.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return int64(v), true, nil
+	case string:
+		if v == "" {
+			return 0, false, nil
+		}
+		if epoch, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return epoch, true, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return 0, false, fmt.Errorf("%q is neither epoch milliseconds nor an RFC 3339 timestamp", v)
+		}
+		return t.UnixMilli(), true, nil
+	default:
+		return 0, false, fmt.Errorf("unsupported value of type %T", value)
+	}
+}
+
+func tagValues(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case float64, bool:
+		return []string{fmt.Sprint(v)}
+	case []any:
+		tags := make([]string, 0, len(v))
+		for _, item := range v {
+			tags = append(tags, tagValues(item)...)
+		}
+		return tags
+	default:
+		return nil
+	}
+}
+
+// isTruthy follows the JMESPath definition: false, null and empty strings, arrays and objects are false.
+func isTruthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,128 @@
+package annotationwebhook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const githubDeployment = `{
+	"action": "created",
+	"deployment_status": {"state": "success", "created_at": "2025-02-01T10:00:00Z", "updated_at": "2025-02-01T10:05:00Z"},
+	"deployment": {"ref": "v1.2.3", "environment": "production", "task": "deploy"},
+	"repository": {"full_name": "grafana/checkout", "topics": ["payments", "go"]}
+}`
+
+func TestMapping(t *testing.T) {
+	received := time.UnixMilli(1738400000000)
+	payload := decode(t, githubDeployment)
+
+	testCases := []struct {
+		desc     string
+		mapping  Mapping
+		expected *event
+		err      string
+	}{
+		{
+			desc: "maps every field",
+			mapping: Mapping{
+				Time:    "deployment_status.created_at",
+				TimeEnd: "deployment_status.updated_at",
+				Text:    "Deployed {{.deployment.ref}} of {{.repository.full_name}} to {{.deployment.environment}}",
+				Tags:    []string{"'deploy'", "deployment.environment", "repository.topics"},
+				Filter:  "deployment_status.state == 'success'",
+			},
+			expected: &event{
+				Time:    1738404000000,
+				TimeEnd: 1738404300000,
+				Text:    "Deployed v1.2.3 of grafana/checkout to production",
+				Tags:    []string{"deploy", "production", "payments", "go"},
+			},
+		},
+		{
+			desc:     "defaults to the time the event was received",
+			mapping:  Mapping{Text: "{{.action}}", Tags: []string{"'deploy'", "'deploy'"}},
+			expected: &event{Time: received.UnixMilli(), TimeEnd: received.UnixMilli(), Text: "created", Tags: []string{"deploy"}},
+		},
+		{
+			desc:     "missing fields render empty",
+			mapping:  Mapping{Time: "missing", Text: "{{.action}} {{.missing}}", Tags: []string{"missing"}},
+			expected: &event{Time: received.UnixMilli(), TimeEnd: received.UnixMilli(), Text: "created"},
+		},
+		{
+			desc:    "events not matching the filter are dropped",
+			mapping: Mapping{Text: "{{.action}}", Filter: "deployment_status.state == 'failure'"},
+		},
+		{
+			desc:    "time must be a timestamp",
+			mapping: Mapping{Time: "deployment.ref", Text: "{{.action}}"},
+			err:     "invalid time",
+		},
+		{
+			desc:    "timeEnd can't be before time",
+			mapping: Mapping{Time: "deployment_status.updated_at", TimeEnd: "deployment_status.created_at", Text: "{{.action}}"},
+			err:     "is before time",
+		},
+		{
+			desc:    "text can't be empty",
+			mapping: Mapping{Text: "{{.missing}}"},
+			err:     "text of the annotation is empty",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			m, err := compileMapping(tc.mapping)
+			require.NoError(t, err)
+
+			e, err := m.apply(payload, received)
+			if tc.err != "" {
+				require.ErrorIs(t, err, ErrInvalidEvent)
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, e)
+		})
+	}
+}
+
+func TestMapping_Time(t *testing.T) {
+	m, err := compileMapping(Mapping{Time: "at", Text: "event"})
+	require.NoError(t, err)
+
+	for _, at := range []string{`1738400000000`, `"1738400000000"`, `"2025-02-01T08:53:20Z"`, `"2025-02-01T09:53:20+01:00"`} {
+		e, err := m.apply(decode(t, `{"at": `+at+`}`), time.Now())
+		require.NoError(t, err, at)
+		assert.Equal(t, int64(1738400000000), e.Time, at)
+	}
+}
+
+func TestCompileMapping(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		mapping Mapping
+	}{
+		{desc: "text is required", mapping: Mapping{Time: "at"}},
+		{desc: "text must be a template", mapping: Mapping{Text: "{{.action"}},
+		{desc: "expressions must be valid", mapping: Mapping{Text: "event", Time: "a.[b"}},
+		{desc: "tags can't be empty", mapping: Mapping{Text: "event", Tags: []string{" "}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, err := compileMapping(tc.mapping)
+			assert.ErrorIs(t, err, ErrInvalidWebhook)
+		})
+	}
+}
+
+func decode(t *testing.T, payload string) any {
+	t.Helper()
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(payload), &v))
+	return v
+}
@@ -0,0 +1,81 @@
+package annotationwebhook
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrWebhookNotFound  = errutil.NotFound("annotations.webhook-not-found", errutil.WithPublicMessage("Annotation webhook not found"))
+	ErrInvalidWebhook   = errutil.BadRequest("annotations.webhook-invalid")
+	ErrWebhookConflict  = errutil.Conflict("annotations.webhook-conflict")
+	ErrForbidden        = errutil.Forbidden("annotations.webhook-forbidden", errutil.WithPublicMessage("You are not allowed to create annotations for this webhook"))
+	ErrCreatorForbidden = errutil.Forbidden("annotations.webhook-creator-forbidden", errutil.WithPublicMessage("The creator of the webhook is no longer allowed to create its annotations"))
+	ErrInvalidToken     = errutil.Unauthorized("annotations.webhook-invalid-token", errutil.WithPublicMessage("Unknown webhook, invalid token or invalid signature"))
+	ErrInvalidEvent     = errutil.BadRequest("annotations.webhook-invalid-event")
+)
+
+// Mapping turns the JSON payload of an event into an annotation. Expressions are JMESPath
+// expressions evaluated against the payload, use a raw string literal such as 'deploy' for
+// constant values.
+type Mapping struct {
+	// Time is an expression for the start of the annotation, either epoch milliseconds or an
+	// RFC 3339 timestamp. The time the event was received is used when it is empty or yields null.
+	Time string `json:"time,omitempty"`
+	// TimeEnd is an expression for the end of the annotation, the annotation is a point in time
+	// when it is empty or yields null.
+	TimeEnd string `json:"timeEnd,omitempty"`
+	// Text is a Go template rendered with the payload, for example "Deployed {{.deployment.ref}}".
+	Text string `json:"text"`
+	// Tags are expressions that yield a string or a list of strings each.
+	Tags []string `json:"tags,omitempty"`
+	// Filter is an optional expression, events for which it isn't truthy are acknowledged and dropped.
+	Filter string `json:"filter,omitempty"`
+}
+
+// Webhook is an inbound endpoint turning the events posted to it into annotations of its
+// organization, or of a dashboard and panel. Its token is stored hashed, to check the token of
+// events, and encrypted, to check their signature.
+type Webhook struct {
+	ID           int64   `json:"-" xorm:"pk autoincr 'id'"`
+	UID          string  `json:"uid" xorm:"uid"`
+	OrgID        int64   `json:"orgId" xorm:"org_id"`
+	Name         string  `json:"name" xorm:"name"`
+	DashboardUID string  `json:"dashboardUid,omitempty" xorm:"dashboard_uid"`
+	PanelID      int64   `json:"panelId,omitempty" xorm:"panel_id"`
+	Mapping      Mapping `json:"mapping" xorm:"-"`
+	RawMapping   string  `json:"-" xorm:"mapping"`
+	TokenHash    string  `json:"-" xorm:"token_hash"`
+	// EncryptedToken is the encrypted and base64 encoded token, it is empty for webhooks whose token
+	// was generated before events could be signed.
+	EncryptedToken string    `json:"-" xorm:"encrypted_token"`
+	CreatedBy      int64     `json:"createdBy" xorm:"created_by"`
+	Created        time.Time `json:"created" xorm:"'created'"`
+	Updated        time.Time `json:"updated" xorm:"'updated'"`
+}
+
+func (Webhook) TableName() string {
+	return "annotation_webhook"
+}
+
+// WebhookWithToken is returned when a webhook is created or its token rotated, the token can't be
+// retrieved afterwards.
+type WebhookWithToken struct {
+	*Webhook
+	Token string `json:"token"`
+}
+
+type CreateWebhookCommand struct {
+	Name         string  `json:"name"`
+	DashboardUID string  `json:"dashboardUid"`
+	PanelID      int64   `json:"panelId"`
+	Mapping      Mapping `json:"mapping"`
+}
+
+type UpdateWebhookCommand struct {
+	Name         string  `json:"name"`
+	DashboardUID string  `json:"dashboardUid"`
+	PanelID      int64   `json:"panelId"`
+	Mapping      Mapping `json:"mapping"`
+}
@@ -0,0 +1,397 @@
+package annotationwebhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	tokenLength   = 32
+	maxNameLength = 190
+
+	// signaturePrefix prefixes the hex encoded HMAC-SHA256 of signed events, as GitHub does.
+	signaturePrefix = "sha256="
+)
+
+type Service struct {
+	log   log.Logger
+	store store
+
+	accessControl    accesscontrol.AccessControl
+	acService        accesscontrol.Service
+	userService      user.Service
+	features         featuremgmt.FeatureToggles
+	dashboardService dashboards.DashboardService
+	annotationsRepo  annotations.Repository
+	secrets          secrets.Service
+
+	now func() time.Time
+}
+
+func ProvideService(
+	sqlStore db.DB, routeRegister routing.RouteRegister, accessControl accesscontrol.AccessControl,
+	acService accesscontrol.Service, userService user.Service, features featuremgmt.FeatureToggles, orgService org.Service, dashboardService dashboards.DashboardService,
+	annotationsRepo annotations.Repository, secretsService secrets.Service,
+) *Service {
+	s := &Service{
+		log:              log.New("annotations.webhooks"),
+		store:            &xormStore{db: sqlStore},
+		accessControl:    accessControl,
+		acService:        acService,
+		userService:      userService,
+		features:         features,
+		dashboardService: dashboardService,
+		annotationsRepo:  annotationsRepo,
+		secrets:          secretsService,
+		now:              time.Now,
+	}
+
+	orgService.RegisterDelete("DELETE FROM annotation_webhook WHERE org_id = ?")
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+// List returns the webhooks of the organization whose annotations the requester is allowed to create.
+func (s *Service) List(ctx context.Context, requester identity.Requester) ([]*Webhook, error) {
+	webhooks, err := s.store.List(ctx, requester.GetOrgID())
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*Webhook, 0, len(webhooks))
+	for _, w := range webhooks {
+		ok, err := s.canManage(ctx, requester, w.DashboardUID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visible = append(visible, w)
+		}
+	}
+	return visible, nil
+}
+
+// Get returns the webhook if the requester is allowed to create its annotations.
+func (s *Service) Get(ctx context.Context, requester identity.Requester, uid string) (*Webhook, error) {
+	webhook, err := s.store.Get(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.canManage(ctx, requester, webhook.DashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrWebhookNotFound.Errorf("annotation webhook %s not found", uid)
+	}
+	return webhook, nil
+}
+
+// Create creates a webhook and returns it with its token, which can't be retrieved afterwards.
+func (s *Service) Create(ctx context.Context, requester identity.Requester, cmd CreateWebhookCommand) (*WebhookWithToken, error) {
+	userID, err := requester.GetInternalID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	webhook := &Webhook{
+		UID:          util.GenerateShortUID(),
+		OrgID:        requester.GetOrgID(),
+		Name:         strings.TrimSpace(cmd.Name),
+		DashboardUID: cmd.DashboardUID,
+		PanelID:      cmd.PanelID,
+		Mapping:      cmd.Mapping,
+		CreatedBy:    userID,
+		Created:      now,
+		Updated:      now,
+	}
+	if err := s.validate(ctx, requester, webhook); err != nil {
+		return nil, err
+	}
+
+	token, err := s.newToken(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return &WebhookWithToken{Webhook: webhook, Token: token}, nil
+}
+
+// Update changes the name, the target and the mapping of a webhook, its token is kept.
+func (s *Service) Update(ctx context.Context, requester identity.Requester, uid string, cmd UpdateWebhookCommand) (*Webhook, error) {
+	webhook, err := s.Get(ctx, requester, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.Name = strings.TrimSpace(cmd.Name)
+	webhook.DashboardUID = cmd.DashboardUID
+	webhook.PanelID = cmd.PanelID
+	webhook.Mapping = cmd.Mapping
+	webhook.Updated = s.now()
+	if err := s.validate(ctx, requester, webhook); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// RotateToken replaces the token of a webhook, events sent with the previous token are rejected.
+func (s *Service) RotateToken(ctx context.Context, requester identity.Requester, uid string) (*WebhookWithToken, error) {
+	webhook, err := s.Get(ctx, requester, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.newToken(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Updated = s.now()
+	if err := s.store.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return &WebhookWithToken{Webhook: webhook, Token: token}, nil
+}
+
+func (s *Service) Delete(ctx context.Context, requester identity.Requester, uid string) error {
+	if _, err := s.Get(ctx, requester, uid); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, requester.GetOrgID(), uid)
+}
+
+// Ingest authenticates an event posted to a webhook and saves the annotation it maps to. Events are
+// authenticated either with the token of the webhook or with the HMAC-SHA256 signature of their
+// payload keyed with the token, formatted as in the X-Hub-Signature-256 header of GitHub. It returns
+// nil without saving anything when the event doesn't pass the filter of the webhook. Events are
+// rejected once the creator of the webhook is no longer allowed to create its annotations.
+func (s *Service) Ingest(ctx context.Context, uid, token, signature string, payload []byte) (*annotations.Item, error) {
+	webhook, err := s.store.GetByUID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, ErrWebhookNotFound) {
+			return nil, ErrInvalidToken.Errorf("annotation webhook %s not found", uid)
+		}
+		return nil, err
+	}
+	if err := s.authenticate(ctx, webhook, token, signature, payload); err != nil {
+		return nil, err
+	}
+
+	creator, err := s.creator(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.canManage(ctx, creator, webhook.DashboardUID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCreatorForbidden.Errorf("creator of annotation webhook %s can't create its annotations", uid)
+	}
+
+	var body any
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, ErrInvalidEvent.Errorf("the event isn't valid JSON: %w", err)
+	}
+
+	mapping, err := compileMapping(webhook.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	e, err := mapping.apply(body, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		s.log.FromContext(ctx).Debug("Annotation webhook event filtered out", "webhook", uid)
+		return nil, nil
+	}
+
+	item := &annotations.Item{
+		OrgID:    webhook.OrgID,
+		PanelID:  webhook.PanelID,
+		Epoch:    e.Time,
+		EpochEnd: e.TimeEnd,
+		Text:     e.Text,
+		Tags:     e.Tags,
+		Data:     simplejson.NewFromAny(map[string]any{"webhookUid": webhook.UID}),
+	}
+	if webhook.DashboardUID != "" {
+		dash, err := s.dashboardService.GetDashboard(identity.WithServiceIdentityContext(ctx, webhook.OrgID),
+			&dashboards.GetDashboardQuery{OrgID: webhook.OrgID, UID: webhook.DashboardUID})
+		if err != nil {
+			return nil, err
+		}
+		item.DashboardID = dash.ID
+	}
+
+	if err := s.annotationsRepo.Save(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// authenticate checks the token of an event, or the signature of its payload when it has no token.
+func (s *Service) authenticate(ctx context.Context, webhook *Webhook, token, signature string, payload []byte) error {
+	if token != "" || signature == "" {
+		if subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(webhook.TokenHash)) != 1 {
+			return ErrInvalidToken.Errorf("invalid token for annotation webhook %s", webhook.UID)
+		}
+		return nil
+	}
+
+	sum, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil || !strings.HasPrefix(signature, signaturePrefix) {
+		return ErrInvalidToken.Errorf("malformed signature for annotation webhook %s", webhook.UID)
+	}
+	if webhook.EncryptedToken == "" {
+		return ErrInvalidToken.Errorf("annotation webhook %s can't check signatures until its token is rotated", webhook.UID)
+	}
+	key, err := s.decryptToken(ctx, webhook.EncryptedToken)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt the token of annotation webhook %s: %w", webhook.UID, err)
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(payload)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		return ErrInvalidToken.Errorf("invalid signature for annotation webhook %s", webhook.UID)
+	}
+	return nil
+}
+
+func (s *Service) validate(ctx context.Context, requester identity.Requester, webhook *Webhook) error {
+	if webhook.Name == "" || len(webhook.Name) > maxNameLength {
+		return ErrInvalidWebhook.Errorf("the name is required and can have at most %d characters", maxNameLength)
+	}
+	if webhook.PanelID != 0 && webhook.DashboardUID == "" {
+		return ErrInvalidWebhook.Errorf("a panel requires a dashboard")
+	}
+	if _, err := compileMapping(webhook.Mapping); err != nil {
+		return err
+	}
+
+	if webhook.DashboardUID != "" {
+		_, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: webhook.OrgID, UID: webhook.DashboardUID})
+		if err != nil {
+			if errors.Is(err, dashboards.ErrDashboardNotFound) {
+				return ErrInvalidWebhook.Errorf("dashboard %s not found", webhook.DashboardUID)
+			}
+			return err
+		}
+	}
+
+	ok, err := s.canManage(ctx, requester, webhook.DashboardUID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden.Errorf("requester can't create annotations for the webhook")
+	}
+	return nil
+}
+
+// creator returns the creator of the webhook with their permissions in the organization of the webhook.
+func (s *Service) creator(ctx context.Context, webhook *Webhook) (*user.SignedInUser, error) {
+	creator, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: webhook.CreatedBy, OrgID: webhook.OrgID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, ErrCreatorForbidden.Errorf("creator of annotation webhook %s not found", webhook.UID)
+		}
+		return nil, fmt.Errorf("failed to get the creator of annotation webhook %s: %w", webhook.UID, err)
+	}
+	if creator.IsDisabled {
+		return nil, ErrCreatorForbidden.Errorf("creator of annotation webhook %s is disabled", webhook.UID)
+	}
+
+	permissions, err := s.acService.GetUserPermissions(ctx, creator, accesscontrol.Options{ReloadCache: false})
+	if err != nil {
+		return nil, err
+	}
+	if creator.Permissions == nil {
+		creator.Permissions = map[int64]map[string][]string{}
+	}
+	creator.Permissions[webhook.OrgID] = accesscontrol.GroupScopesByActionContext(ctx, permissions)
+	return creator, nil
+}
+
+// canManage checks that the requester can create the annotations the webhook would create, with the
+// same rules as the annotations API.
+func (s *Service) canManage(ctx context.Context, requester identity.Requester, dashboardUID string) (bool, error) {
+	if dashboardUID == "" {
+		evaluator := accesscontrol.EvalPermission(accesscontrol.ActionAnnotationsCreate, accesscontrol.ScopeAnnotationsTypeOrganization)
+		return s.accessControl.Evaluate(ctx, requester, evaluator)
+	}
+
+	dashboardScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID)
+	if s.features.IsEnabled(ctx, featuremgmt.FlagAnnotationPermissionUpdate) {
+		return s.accessControl.Evaluate(ctx, requester, accesscontrol.EvalPermission(accesscontrol.ActionAnnotationsCreate, dashboardScope))
+	}
+	return s.accessControl.Evaluate(ctx, requester, accesscontrol.EvalAll(
+		accesscontrol.EvalPermission(accesscontrol.ActionAnnotationsCreate, accesscontrol.ScopeAnnotationsTypeDashboard),
+		accesscontrol.EvalPermission(dashboards.ActionDashboardsWrite, dashboardScope),
+	))
+}
+
+// newToken generates a token for the webhook and sets its hash and encrypted value.
+func (s *Service) newToken(ctx context.Context, webhook *Webhook) (string, error) {
+	token, err := util.GetRandomString(tokenLength)
+	if err != nil {
+		return "", err
+	}
+	encrypted, err := s.secrets.Encrypt(ctx, []byte(token), secrets.WithoutScope())
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt the token: %w", err)
+	}
+	webhook.TokenHash = hashToken(token)
+	webhook.EncryptedToken = base64.StdEncoding.EncodeToString(encrypted)
+	return token, nil
+}
+
+func (s *Service) decryptToken(ctx context.Context, token string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := s.secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,280 @@
+package annotationwebhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/annotationstest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationAnnotationWebhook(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.UnixMilli(1738400000000)
+	s, repo, users, server := setupTestService(t)
+	s.now = func() time.Time { return now }
+
+	admin := testUser(1,
+		accesscontrol.ActionAnnotationsCreate, accesscontrol.ScopeAnnotationsTypeOrganization,
+		accesscontrol.ActionAnnotationsCreate, dashboards.ScopeDashboardsProvider.GetResourceScopeUID("checkout"),
+	)
+	dashboardEditor := testUser(2, accesscontrol.ActionAnnotationsCreate, dashboards.ScopeDashboardsProvider.GetResourceScopeUID("checkout"))
+	users.add(admin, dashboardEditor)
+	cmd := CreateWebhookCommand{
+		Name: "GitHub deployments",
+		Mapping: Mapping{
+			Time: "deployment_status.created_at",
+			Text: "Deployed {{.deployment.ref}} to {{.deployment.environment}}",
+			Tags: []string{"'deploy'", "deployment.environment"},
+		},
+	}
+
+	t.Run("organization webhooks require the permission to create organization annotations", func(t *testing.T) {
+		_, err := s.Create(ctx, dashboardEditor, cmd)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("the mapping is validated", func(t *testing.T) {
+		_, err := s.Create(ctx, admin, CreateWebhookCommand{Name: "invalid", Mapping: Mapping{Time: "at"}})
+		assert.ErrorIs(t, err, ErrInvalidWebhook)
+	})
+
+	created, err := s.Create(ctx, admin, cmd)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.Token)
+
+	_, err = s.Create(ctx, admin, cmd)
+	assert.ErrorIs(t, err, ErrWebhookConflict)
+
+	dashboardCmd := cmd
+	dashboardCmd.Name = "Checkout deployments"
+	dashboardCmd.DashboardUID = "checkout"
+	dashboardCmd.PanelID = 2
+	dashboardWebhook, err := s.Create(ctx, dashboardEditor, dashboardCmd)
+	require.NoError(t, err)
+
+	webhooks, err := s.List(ctx, dashboardEditor)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, dashboardWebhook.UID, webhooks[0].UID)
+	assert.Equal(t, dashboardCmd.Mapping, webhooks[0].Mapping)
+
+	_, err = s.Get(ctx, dashboardEditor, created.UID)
+	assert.ErrorIs(t, err, ErrWebhookNotFound)
+
+	t.Run("events require the token of the webhook", func(t *testing.T) {
+		_, err := s.Ingest(ctx, created.UID, dashboardWebhook.Token, "", []byte(githubDeployment))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = s.Ingest(ctx, "unknown", created.Token, "", []byte(githubDeployment))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		assert.Zero(t, repo.Len())
+	})
+
+	t.Run("events must be JSON", func(t *testing.T) {
+		_, err := s.Ingest(ctx, created.UID, created.Token, "", []byte("deployed"))
+		assert.ErrorIs(t, err, ErrInvalidEvent)
+	})
+
+	item, err := s.Ingest(ctx, created.UID, created.Token, "", []byte(githubDeployment))
+	require.NoError(t, err)
+	saved := repo.Items()[item.ID]
+	assert.Equal(t, int64(1), saved.OrgID)
+	assert.Zero(t, saved.DashboardID)
+	assert.Equal(t, int64(1738404000000), saved.Epoch)
+	assert.Equal(t, "Deployed v1.2.3 to production", saved.Text)
+	assert.Equal(t, []string{"deploy", "production"}, saved.Tags)
+
+	item, err = s.Ingest(ctx, dashboardWebhook.UID, dashboardWebhook.Token, "", []byte(githubDeployment))
+	require.NoError(t, err)
+	saved = repo.Items()[item.ID]
+	assert.Equal(t, int64(42), saved.DashboardID)
+	assert.Equal(t, int64(2), saved.PanelID)
+
+	t.Run("rotating the token revokes the previous one", func(t *testing.T) {
+		rotated, err := s.RotateToken(ctx, admin, created.UID)
+		require.NoError(t, err)
+		_, err = s.Ingest(ctx, created.UID, created.Token, "", []byte(githubDeployment))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = s.Ingest(ctx, created.UID, rotated.Token, "", []byte(githubDeployment))
+		assert.NoError(t, err)
+	})
+
+	t.Run("events signed like GitHub are accepted", func(t *testing.T) {
+		rotated, err := s.RotateToken(ctx, admin, created.UID)
+		require.NoError(t, err)
+
+		send := func(signature string) *http.Response {
+			req := server.NewPostRequest("/api/annotations/webhooks/"+created.UID+"/events", strings.NewReader(githubDeployment))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-GitHub-Event", "deployment_status")
+			req.Header.Set("X-Hub-Signature-256", signature)
+			resp, err := server.Send(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			return resp
+		}
+
+		count := repo.Len()
+		resp := send(sign(rotated.Token, githubDeployment))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, count+1, repo.Len())
+
+		resp = send(sign(created.Token, githubDeployment))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		resp = send(strings.TrimPrefix(sign(rotated.Token, githubDeployment), "sha256="))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, count+1, repo.Len())
+
+		_, err = s.Ingest(ctx, created.UID, "", sign(rotated.Token, `{"tampered": true}`), []byte(githubDeployment))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = s.Ingest(ctx, created.UID, created.Token, sign(rotated.Token, githubDeployment), []byte(githubDeployment))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("updates keep the token", func(t *testing.T) {
+		updateCmd := UpdateWebhookCommand(dashboardCmd)
+		updateCmd.Mapping.Filter = "deployment.environment == 'staging'"
+		_, err := s.Update(ctx, dashboardEditor, dashboardWebhook.UID, updateCmd)
+		require.NoError(t, err)
+
+		count := repo.Len()
+		item, err := s.Ingest(ctx, dashboardWebhook.UID, dashboardWebhook.Token, "", []byte(githubDeployment))
+		require.NoError(t, err)
+		assert.Nil(t, item)
+		assert.Equal(t, count, repo.Len())
+	})
+
+	t.Run("events are rejected once the creator can't create the annotations", func(t *testing.T) {
+		users.add(testUser(2))
+		defer users.add(dashboardEditor)
+
+		count := repo.Len()
+		_, err := s.Ingest(ctx, dashboardWebhook.UID, dashboardWebhook.Token, "", []byte(githubDeployment))
+		assert.ErrorIs(t, err, ErrCreatorForbidden)
+		assert.Equal(t, count, repo.Len())
+	})
+
+	t.Run("events are rejected once the creator is deleted", func(t *testing.T) {
+		delete(users, dashboardEditor.UserID)
+		defer users.add(dashboardEditor)
+
+		_, err := s.Ingest(ctx, dashboardWebhook.UID, dashboardWebhook.Token, "", []byte(githubDeployment))
+		assert.ErrorIs(t, err, ErrCreatorForbidden)
+	})
+
+	require.NoError(t, s.Delete(ctx, dashboardEditor, dashboardWebhook.UID))
+	_, err = s.Ingest(ctx, dashboardWebhook.UID, dashboardWebhook.Token, "", []byte(githubDeployment))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func setupTestService(t *testing.T) (*Service, interface {
+	Len() int
+	Items() map[int64]annotations.Item
+}, testUsers, *webtest.Server) {
+	t.Helper()
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+		return q.UID == "checkout"
+	})).Return(&dashboards.Dashboard{ID: 42, UID: "checkout", OrgID: 1}, nil).Maybe()
+
+	users := testUsers{}
+	userService := &usertest.FakeUserService{
+		GetSignedInUserFn: func(_ context.Context, query *user.GetSignedInUserQuery) (*user.SignedInUser, error) {
+			u, ok := users[query.UserID]
+			if !ok {
+				return nil, user.ErrUserNotFound
+			}
+			return &user.SignedInUser{UserID: u.UserID, OrgID: u.OrgID, OrgRole: u.OrgRole}, nil
+		},
+	}
+
+	repo := annotationstest.NewFakeAnnotationsRepo()
+	routeRegister := routing.NewRouteRegister()
+	s := ProvideService(
+		db.InitTestDB(t), routeRegister, acimpl.ProvideAccessControlTest(), &fakeACService{users: users}, userService,
+		featuremgmt.WithFeatures(featuremgmt.FlagAnnotationPermissionUpdate), orgtest.NewOrgServiceFake(), dashboardService, repo,
+		fakes.NewFakeSecretsService(),
+	)
+	return s, repo, users, webtest.NewServer(t, routeRegister)
+}
+
+// sign returns the X-Hub-Signature-256 header GitHub sends for the payload.
+func sign(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// testUsers are the users known to the user service, with their permissions.
+type testUsers map[int64]*user.SignedInUser
+
+func (u testUsers) add(users ...*user.SignedInUser) {
+	for _, usr := range users {
+		u[usr.UserID] = usr
+	}
+}
+
+type fakeACService struct {
+	actest.FakeService
+	users testUsers
+}
+
+func (f *fakeACService) GetUserPermissions(_ context.Context, requester identity.Requester, _ accesscontrol.Options) ([]accesscontrol.Permission, error) {
+	id, err := requester.GetInternalID()
+	if err != nil {
+		return nil, err
+	}
+	u, ok := f.users[id]
+	if !ok {
+		return nil, nil
+	}
+	var permissions []accesscontrol.Permission
+	for action, scopes := range u.Permissions[u.OrgID] {
+		for _, scope := range scopes {
+			permissions = append(permissions, accesscontrol.Permission{Action: action, Scope: scope})
+		}
+	}
+	return permissions, nil
+}
+
+func testUser(id int64, permissions ...string) *user.SignedInUser {
+	u := &user.SignedInUser{UserID: id, OrgID: 1, OrgRole: org.RoleViewer, Permissions: map[int64]map[string][]string{1: {}}}
+	for i := 0; i+1 < len(permissions); i += 2 {
+		u.Permissions[1][permissions[i]] = append(u.Permissions[1][permissions[i]], permissions[i+1])
+	}
+	return u
+}
@@ -0,0 +1,127 @@
+package annotationwebhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store interface {
+	Create(ctx context.Context, webhook *Webhook) error
+	Update(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, orgID int64, uid string) error
+	Get(ctx context.Context, orgID int64, uid string) (*Webhook, error)
+	// GetByUID returns the webhook regardless of its organization, it is used to authenticate events.
+	GetByUID(ctx context.Context, uid string) (*Webhook, error)
+	List(ctx context.Context, orgID int64) ([]*Webhook, error)
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) Create(ctx context.Context, webhook *Webhook) error {
+	if err := encodeMapping(webhook); err != nil {
+		return err
+	}
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if err := s.checkName(sess, webhook); err != nil {
+			return err
+		}
+		_, err := sess.Insert(webhook)
+		return err
+	})
+}
+
+func (s *xormStore) Update(ctx context.Context, webhook *Webhook) error {
+	if err := encodeMapping(webhook); err != nil {
+		return err
+	}
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if err := s.checkName(sess, webhook); err != nil {
+			return err
+		}
+		_, err := sess.ID(webhook.ID).Cols("name", "dashboard_uid", "panel_id", "mapping", "token_hash", "encrypted_token", "updated").Update(webhook)
+		return err
+	})
+}
+
+func (s *xormStore) checkName(sess *db.Session, webhook *Webhook) error {
+	has, err := sess.Where("org_id = ? AND name = ? AND id <> ?", webhook.OrgID, webhook.Name, webhook.ID).Exist(&Webhook{})
+	if err != nil {
+		return err
+	}
+	if has {
+		return ErrWebhookConflict.Errorf("an annotation webhook named %q already exists", webhook.Name)
+	}
+	return nil
+}
+
+func (s *xormStore) Delete(ctx context.Context, orgID int64, uid string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&Webhook{})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrWebhookNotFound.Errorf("annotation webhook %s not found", uid)
+		}
+		return nil
+	})
+}
+
+func (s *xormStore) Get(ctx context.Context, orgID int64, uid string) (*Webhook, error) {
+	return s.get(ctx, "org_id = ? AND uid = ?", orgID, uid)
+}
+
+func (s *xormStore) GetByUID(ctx context.Context, uid string) (*Webhook, error) {
+	return s.get(ctx, "uid = ?", uid)
+}
+
+func (s *xormStore) get(ctx context.Context, where string, args ...any) (*Webhook, error) {
+	webhook := &Webhook{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where(where, args...).Get(webhook)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrWebhookNotFound.Errorf("annotation webhook %s not found", args[len(args)-1])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return webhook, decodeMapping(webhook)
+}
+
+func (s *xormStore) List(ctx context.Context, orgID int64) ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("name").Find(&webhooks)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range webhooks {
+		if err := decodeMapping(w); err != nil {
+			return nil, err
+		}
+	}
+	return webhooks, nil
+}
+
+func encodeMapping(webhook *Webhook) error {
+	raw, err := json.Marshal(webhook.Mapping)
+	if err != nil {
+		return err
+	}
+	webhook.RawMapping = string(raw)
+	return nil
+}
+
+func decodeMapping(webhook *Webhook) error {
+	return json.Unmarshal([]byte(webhook.RawMapping), &webhook.Mapping)
+}
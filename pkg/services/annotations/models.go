@@ -168,3 +168,36 @@ func (annotation *ItemDTO) GetType() annotationType {
 	}
 	return Organization
 }
+
+// SummaryQuery is the query for a summary of annotations bucketed over time.
+type SummaryQuery struct {
+	ItemQuery
+	// Buckets is the number of equally sized time buckets the time range is split into.
+	Buckets int64 `json:"buckets"`
+}
+
+// SummaryBucket counts the annotations starting within a time bucket.
+type SummaryBucket struct {
+	From  int64            `json:"from"`
+	To    int64            `json:"to"`
+	Count int64            `json:"count"`
+	Types map[string]int64 `json:"types"`
+	Tags  map[string]int64 `json:"tags"`
+}
+
+// SummaryRegion is a span of time covered by one or more overlapping region annotations.
+type SummaryRegion struct {
+	Time    int64 `json:"time"`
+	TimeEnd int64 `json:"timeEnd"`
+	Count   int64 `json:"count"`
+}
+
+// swagger:model AnnotationSummary
+type Summary struct {
+	Total   int64            `json:"total"`
+	Buckets []*SummaryBucket `json:"buckets"`
+	Regions []*SummaryRegion `json:"regions"`
+	// Truncated is true when the summary only counts part of the annotations, because a store limits the number
+	// of annotations it can count.
+	Truncated bool `json:"truncated"`
+}
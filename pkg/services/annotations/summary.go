@@ -0,0 +1,142 @@
+package annotations
+
+import (
+	"sort"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+const (
+	DefaultSummaryBuckets = 100
+	MaxSummaryBuckets     = 1000
+
+	// TypeAlert and TypeAnnotation are the annotation types, as used by the type filter of ItemQuery.
+	TypeAlert      = "alert"
+	TypeAnnotation = "annotation"
+)
+
+var ErrInvalidSummaryQuery = errutil.BadRequest("annotations.invalid-summary-query")
+
+// NewSummary returns an empty summary with the buckets of the query.
+func NewSummary(query SummaryQuery) (*Summary, error) {
+	if query.From <= 0 || query.To <= 0 {
+		return nil, ErrInvalidSummaryQuery.Errorf("%w", ErrTimerangeMissing)
+	}
+	if query.To <= query.From {
+		return nil, ErrInvalidSummaryQuery.Errorf("from must be before to")
+	}
+	if query.Buckets < 1 || query.Buckets > MaxSummaryBuckets {
+		return nil, ErrInvalidSummaryQuery.Errorf("buckets must be between 1 and %d", MaxSummaryBuckets)
+	}
+
+	span := query.To - query.From
+	width := (span + query.Buckets - 1) / query.Buckets
+	buckets := make([]*SummaryBucket, 0, query.Buckets)
+	for from := query.From; from < query.To; from += width {
+		buckets = append(buckets, &SummaryBucket{
+			From:  from,
+			To:    min(from+width, query.To),
+			Types: map[string]int64{},
+			Tags:  map[string]int64{},
+		})
+	}
+
+	return &Summary{Buckets: buckets, Regions: []*SummaryRegion{}}, nil
+}
+
+// BucketWidth returns the width of the buckets, only the last bucket may be narrower.
+func (s *Summary) BucketWidth() int64 {
+	if len(s.Buckets) == 0 {
+		return 0
+	}
+	return s.Buckets[0].To - s.Buckets[0].From
+}
+
+// bucket returns the bucket an annotation starting at time is counted in.
+// Annotations starting outside of the time range are counted in the first or last bucket.
+func (s *Summary) bucket(time int64) *SummaryBucket {
+	if len(s.Buckets) == 0 {
+		return nil
+	}
+	i := sort.Search(len(s.Buckets), func(i int) bool { return s.Buckets[i].To > time })
+	if i == len(s.Buckets) {
+		i--
+	}
+	return s.Buckets[i]
+}
+
+// Add counts an annotation in the bucket it starts in.
+func (s *Summary) Add(time, timeEnd int64, annotationType string, tags []string) {
+	s.AddCount(time, annotationType, 1)
+	for _, tag := range tags {
+		s.AddTagCount(time, tag, 1)
+	}
+	if timeEnd > time {
+		s.AddRegion(time, timeEnd)
+	}
+}
+
+// AddCount counts annotations of a type in the bucket of time.
+func (s *Summary) AddCount(time int64, annotationType string, count int64) {
+	bucket := s.bucket(time)
+	if bucket == nil {
+		return
+	}
+	bucket.Count += count
+	bucket.Types[annotationType] += count
+	s.Total += count
+}
+
+// AddTagCount counts the annotations with a tag in the bucket of time.
+func (s *Summary) AddTagCount(time int64, tag string, count int64) {
+	if bucket := s.bucket(time); bucket != nil {
+		bucket.Tags[tag] += count
+	}
+}
+
+// AddRegion adds the time range of a region annotation.
+func (s *Summary) AddRegion(time, timeEnd int64) {
+	s.Regions = append(s.Regions, &SummaryRegion{Time: time, TimeEnd: timeEnd, Count: 1})
+}
+
+// Merge adds the counts of another summary of the same query.
+func (s *Summary) Merge(other *Summary) {
+	for i, b := range other.Buckets {
+		if i >= len(s.Buckets) {
+			break
+		}
+		s.Buckets[i].Count += b.Count
+		for k, v := range b.Types {
+			s.Buckets[i].Types[k] += v
+		}
+		for k, v := range b.Tags {
+			s.Buckets[i].Tags[k] += v
+		}
+	}
+	s.Total += other.Total
+	s.Truncated = s.Truncated || other.Truncated
+	s.Regions = append(s.Regions, other.Regions...)
+	s.Compact()
+}
+
+// Compact merges overlapping regions, it should be called once all annotations are added.
+func (s *Summary) Compact() {
+	if len(s.Regions) < 2 {
+		return
+	}
+	sort.Slice(s.Regions, func(i, j int) bool {
+		return s.Regions[i].Time < s.Regions[j].Time
+	})
+
+	merged := []*SummaryRegion{s.Regions[0]}
+	for _, r := range s.Regions[1:] {
+		last := merged[len(merged)-1]
+		if r.Time <= last.TimeEnd {
+			last.TimeEnd = max(last.TimeEnd, r.TimeEnd)
+			last.Count += r.Count
+			continue
+		}
+		merged = append(merged, r)
+	}
+	s.Regions = merged
+}
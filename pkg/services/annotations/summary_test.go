@@ -0,0 +1,84 @@
+package annotations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSummary(t *testing.T) {
+	t.Run("should split the time range in buckets", func(t *testing.T) {
+		s, err := NewSummary(SummaryQuery{ItemQuery: ItemQuery{From: 100, To: 200}, Buckets: 3})
+		require.NoError(t, err)
+		require.Len(t, s.Buckets, 3)
+		assert.Equal(t, []int64{100, 134, 168}, []int64{s.Buckets[0].From, s.Buckets[1].From, s.Buckets[2].From})
+		assert.Equal(t, int64(200), s.Buckets[2].To)
+	})
+
+	t.Run("should not create empty buckets for short time ranges", func(t *testing.T) {
+		s, err := NewSummary(SummaryQuery{ItemQuery: ItemQuery{From: 100, To: 103}, Buckets: 10})
+		require.NoError(t, err)
+		assert.Len(t, s.Buckets, 3)
+	})
+
+	t.Run("should validate the query", func(t *testing.T) {
+		for _, q := range []SummaryQuery{
+			{ItemQuery: ItemQuery{To: 200}, Buckets: 3},
+			{ItemQuery: ItemQuery{From: 200, To: 100}, Buckets: 3},
+			{ItemQuery: ItemQuery{From: 100, To: 200}, Buckets: 0},
+			{ItemQuery: ItemQuery{From: 100, To: 200}, Buckets: MaxSummaryBuckets + 1},
+		} {
+			_, err := NewSummary(q)
+			assert.ErrorIs(t, err, ErrInvalidSummaryQuery)
+		}
+	})
+}
+
+func TestSummary_Add(t *testing.T) {
+	s, err := NewSummary(SummaryQuery{ItemQuery: ItemQuery{From: 100, To: 200}, Buckets: 2})
+	require.NoError(t, err)
+
+	s.Add(50, 120, TypeAlert, nil)
+	s.Add(149, 149, TypeAnnotation, []string{"deploy", "env:prod"})
+	s.Add(150, 150, TypeAnnotation, []string{"deploy"})
+	s.Add(110, 130, TypeAnnotation, nil)
+	s.Add(300, 300, TypeAlert, nil)
+	s.Compact()
+
+	assert.Equal(t, int64(5), s.Total)
+	assert.Equal(t, &SummaryBucket{
+		From:  100,
+		To:    150,
+		Count: 3,
+		Types: map[string]int64{TypeAlert: 1, TypeAnnotation: 2},
+		Tags:  map[string]int64{"deploy": 1, "env:prod": 1},
+	}, s.Buckets[0])
+	assert.Equal(t, &SummaryBucket{
+		From:  150,
+		To:    200,
+		Count: 2,
+		Types: map[string]int64{TypeAlert: 1, TypeAnnotation: 1},
+		Tags:  map[string]int64{"deploy": 1},
+	}, s.Buckets[1])
+	assert.Equal(t, []*SummaryRegion{{Time: 50, TimeEnd: 130, Count: 2}}, s.Regions)
+}
+
+func TestSummary_Merge(t *testing.T) {
+	s, err := NewSummary(SummaryQuery{ItemQuery: ItemQuery{From: 100, To: 200}, Buckets: 2})
+	require.NoError(t, err)
+	other, err := NewSummary(SummaryQuery{ItemQuery: ItemQuery{From: 100, To: 200}, Buckets: 2})
+	require.NoError(t, err)
+
+	s.AddCount(100, TypeAnnotation, 2)
+	s.AddTagCount(100, "deploy", 2)
+	other.AddCount(150, TypeAlert, 3)
+	other.Truncated = true
+	s.Merge(other)
+
+	assert.Equal(t, int64(5), s.Total)
+	assert.Equal(t, int64(2), s.Buckets[0].Count)
+	assert.Equal(t, map[string]int64{"deploy": 2}, s.Buckets[0].Tags)
+	assert.Equal(t, map[string]int64{TypeAlert: 3}, s.Buckets[1].Types)
+	assert.True(t, s.Truncated)
+}
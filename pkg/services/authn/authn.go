@@ -40,6 +40,19 @@ const (
 	defaultRedirectToCookieKey = "redirect_to"
 )
 
+const (
+	// MetaKeyPasswordVerified is set once a password client has verified the credentials of a request
+	MetaKeyPasswordVerified = "passwordVerified"
+	// MetaKeyOTP is a one-time code from an authenticator app sent along with a password
+	MetaKeyOTP = "otp"
+	// MetaKeyRecoveryCode is a single-use recovery code sent along with a password
+	MetaKeyRecoveryCode = "recoveryCode"
+	// MetaKeyWebAuthnAssertion is a JSON encoded WebAuthn assertion sent along with a password
+	MetaKeyWebAuthnAssertion = "webauthnAssertion"
+	// MetaKeyMFAEnrollmentToken references a second factor enrollment started during login
+	MetaKeyMFAEnrollmentToken = "mfaEnrollmentToken"
+)
+
 // ClientParams are hints to the auth service about how to handle the identity management
 // from the authenticating client.
 type ClientParams struct {
@@ -238,8 +251,9 @@ func (r *Request) GetMeta(k string) string {
 }
 
 const (
-	KeyOAuthPKCE  = "pkce"
-	KeyOAuthState = "state"
+	KeyOAuthPKCE     = "pkce"
+	KeyOAuthState    = "state"
+	KeySAMLRequestID = "saml_request_id"
 )
 
 type Redirect struct {
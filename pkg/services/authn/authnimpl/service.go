@@ -310,6 +310,9 @@ func (s *Service) Logout(ctx context.Context, user identity.Requester, sessionTo
 
 	if authModule := user.GetAuthenticatedBy(); authModule != "" {
 		client := authn.ClientWithPrefix(strings.TrimPrefix(authModule, "oauth_"))
+		if authModule == login.SAMLAuthModule {
+			client = authn.ClientSAML
+		}
 
 		c, ok := s.clients[client]
 		if !ok {
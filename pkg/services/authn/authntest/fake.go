@@ -126,7 +126,7 @@ func (f *FakeService) RedirectURL(ctx context.Context, client string, r *authn.R
 }
 
 func (f *FakeService) Logout(_ context.Context, _ identity.Requester, _ *usertoken.UserToken) (*authn.Redirect, error) {
-	panic("unimplemented")
+	return f.ExpectedRedirect, f.ExpectedErr
 }
 
 func (f *FakeService) ResolveIdentity(ctx context.Context, orgID int64, typedID string) (*authn.Identity, error) {
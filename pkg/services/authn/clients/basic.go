@@ -7,6 +7,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/authn"
 )
 
+// otpHeaderName carries a one-time code for users that have a second factor enrolled
+const otpHeaderName = "X-Grafana-OTP"
+
 var errDecodingBasicAuthHeader = errutil.BadRequest("basic-auth.invalid-header", errutil.WithPublicMessage("Invalid Basic Auth Header"))
 
 var _ authn.ContextAwareClient = new(Basic)
@@ -33,6 +36,8 @@ func (c *Basic) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 		return nil, errDecodingBasicAuthHeader.Errorf("failed to decode basic auth header")
 	}
 
+	setMetaIfPresent(r, authn.MetaKeyOTP, r.HTTPRequest.Header.Get(otpHeaderName))
+
 	return c.client.AuthenticatePassword(ctx, r, username, password)
 }
 
@@ -2,6 +2,7 @@ package clients
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/services/authn"
@@ -23,6 +24,11 @@ type Form struct {
 type loginForm struct {
 	Username string `json:"user" binding:"Required"`
 	Password string `json:"password" binding:"Required"`
+	// Optional second factor, only checked when multi-factor authentication applies to the user
+	OTP                string          `json:"otp"`
+	RecoveryCode       string          `json:"recoveryCode"`
+	WebAuthn           json.RawMessage `json:"webauthn"`
+	MFAEnrollmentToken string          `json:"mfaEnrollmentToken"`
 }
 
 func (c *Form) Name() string {
@@ -34,9 +40,21 @@ func (c *Form) Authenticate(ctx context.Context, r *authn.Request) (*authn.Ident
 	if err := web.Bind(r.HTTPRequest, &form); err != nil {
 		return nil, errBadForm.Errorf("failed to parse request: %w", err)
 	}
+
+	setMetaIfPresent(r, authn.MetaKeyOTP, form.OTP)
+	setMetaIfPresent(r, authn.MetaKeyRecoveryCode, form.RecoveryCode)
+	setMetaIfPresent(r, authn.MetaKeyWebAuthnAssertion, string(form.WebAuthn))
+	setMetaIfPresent(r, authn.MetaKeyMFAEnrollmentToken, form.MFAEnrollmentToken)
+
 	return c.client.AuthenticatePassword(ctx, r, form.Username, form.Password)
 }
 
 func (c *Form) IsEnabled() bool {
 	return true
 }
+
+func setMetaIfPresent(r *authn.Request, key, value string) {
+	if value != "" {
+		r.SetMeta(key, value)
+	}
+}
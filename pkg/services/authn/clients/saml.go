@@ -0,0 +1,718 @@
+package clients
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/mitchellh/mapstructure"
+	dsig "github.com/russellhaering/goxmldsig"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/login/social/connectors"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/ssosettings"
+	"github.com/grafana/grafana/pkg/services/ssosettings/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// SAMLRequestIDCookieName holds the ID of the pending SP-initiated authentication request.
+	SAMLRequestIDCookieName = "saml_request_id"
+
+	samlMetadataFetchTimeout = 10 * time.Second
+
+	// samlDefaultMaxIssueDelay is the longest time allowed between the issuance of an assertion and its processing.
+	samlDefaultMaxIssueDelay = 90 * time.Second
+	// samlMaxIssueDelayLimit is the longest max_issue_delay a provider can be configured with.
+	samlMaxIssueDelayLimit = time.Hour
+
+	samlAssertionKeyPrefix = "saml-assertion-%s"
+)
+
+// samlLibraryDelayOnce raises the issue delay that the SAML library checks for all providers, once, to the highest
+// delay a provider can be configured with. The delay of each provider is then checked on the assertion it returned.
+var samlLibraryDelayOnce sync.Once
+
+var (
+	errSAMLClientDisabled   = errutil.BadRequest("auth.saml.disabled", errutil.WithPublicMessage("SAML client is disabled"))
+	errSAMLInternal         = errutil.Internal("auth.saml.internal", errutil.WithPublicMessage("An internal error occurred in the SAML client"))
+	errSAMLMissingRequest   = errutil.Unauthorized("auth.saml.request.missing", errutil.WithPublicMessage("No pending SAML authentication request found"))
+	errSAMLInvalidResponse  = errutil.Unauthorized("auth.saml.response.invalid", errutil.WithPublicMessage("Invalid SAML response"))
+	errSAMLMissingLogin     = errutil.Unauthorized("auth.saml.login.missing", errutil.WithPublicMessage("Identity provider didn't return a login or email"))
+	errSAMLOrgNotAllowed    = errutil.Unauthorized("auth.saml.org.not-allowed", errutil.WithPublicMessage("User is not a member of an allowed organization"))
+	errSAMLInvalidSettings  = errutil.ValidationFailed("auth.saml.settings.invalid", errutil.WithPublicMessage("SAML settings are invalid"))
+	errSAMLInvalidLogoutMsg = errutil.BadRequest("auth.saml.logout.invalid", errutil.WithPublicMessage("Invalid SAML logout message"))
+)
+
+var (
+	_ authn.LogoutClient           = new(SAML)
+	_ authn.RedirectClient         = new(SAML)
+	_ authn.SSOSettingsAwareClient = new(SAML)
+	_ ssosettings.Reloadable       = new(SAML)
+)
+
+// SAMLInfo holds the settings of the SAML client as stored in SSO settings.
+type SAMLInfo struct {
+	Enabled               bool          `mapstructure:"enabled"`
+	Name                  string        `mapstructure:"name"`
+	EntityID              string        `mapstructure:"entity_id"`
+	SingleLogout          bool          `mapstructure:"single_logout"`
+	AllowSignUp           bool          `mapstructure:"allow_sign_up"`
+	AutoLogin             bool          `mapstructure:"auto_login"`
+	Certificate           string        `mapstructure:"certificate"`
+	CertificatePath       string        `mapstructure:"certificate_path"`
+	PrivateKey            string        `mapstructure:"private_key"`
+	PrivateKeyPath        string        `mapstructure:"private_key_path"`
+	SignatureAlgorithm    string        `mapstructure:"signature_algorithm"`
+	IDPMetadata           string        `mapstructure:"idp_metadata"`
+	IDPMetadataPath       string        `mapstructure:"idp_metadata_path"`
+	IDPMetadataURL        string        `mapstructure:"idp_metadata_url"`
+	MaxIssueDelay         time.Duration `mapstructure:"max_issue_delay"`
+	MetadataValidDuration time.Duration `mapstructure:"metadata_valid_duration"`
+	AllowIDPInitiated     bool          `mapstructure:"allow_idp_initiated"`
+	RelayState            string        `mapstructure:"relay_state"`
+	NameIDFormat          string        `mapstructure:"name_id_format"`
+	SkipOrgRoleSync       bool          `mapstructure:"skip_org_role_sync"`
+
+	AttributeName   string `mapstructure:"assertion_attribute_name"`
+	AttributeLogin  string `mapstructure:"assertion_attribute_login"`
+	AttributeEmail  string `mapstructure:"assertion_attribute_email"`
+	AttributeGroups string `mapstructure:"assertion_attribute_groups"`
+	AttributeRole   string `mapstructure:"assertion_attribute_role"`
+	AttributeOrg    string `mapstructure:"assertion_attribute_org"`
+
+	AllowedOrganizations   []string `mapstructure:"allowed_organizations"`
+	OrgMapping             []string `mapstructure:"org_mapping"`
+	TeamMapping            []string `mapstructure:"team_mapping"`
+	RoleValuesNone         []string `mapstructure:"role_values_none"`
+	RoleValuesViewer       []string `mapstructure:"role_values_viewer"`
+	RoleValuesEditor       []string `mapstructure:"role_values_editor"`
+	RoleValuesAdmin        []string `mapstructure:"role_values_admin"`
+	RoleValuesGrafanaAdmin []string `mapstructure:"role_values_grafana_admin"`
+}
+
+func (i *SAMLInfo) GetDisplayName() string {
+	return i.Name
+}
+
+func (i *SAMLInfo) IsAutoLoginEnabled() bool {
+	return i.AutoLogin
+}
+
+func (i *SAMLInfo) IsSingleLogoutEnabled() bool {
+	return i.SingleLogout
+}
+
+func (i *SAMLInfo) IsSkipOrgRoleSyncEnabled() bool {
+	return i.SkipOrgRoleSync
+}
+
+func (i *SAMLInfo) IsAllowAssignGrafanaAdminEnabled() bool {
+	return i.AttributeRole != "" && len(i.RoleValuesGrafanaAdmin) > 0
+}
+
+// CreateSAMLInfoFromKeyValues decodes the SSO settings of the SAML provider.
+func CreateSAMLInfoFromKeyValues(settings map[string]any) (*SAMLInfo, error) {
+	info := &SAMLInfo{Name: "SAML"}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			func(from reflect.Type, to reflect.Type, data any) (any, error) {
+				if from.Kind() == reflect.String && to.Kind() == reflect.Slice {
+					return util.SplitString(data.(string)), nil
+				}
+				return data, nil
+			},
+		),
+		Result:           info,
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(settings); err != nil {
+		return nil, err
+	}
+	if info.Name == "" {
+		info.Name = "SAML"
+	}
+	return info, nil
+}
+
+func ProvideSAML(cfg *setting.Cfg, orgRoleMapper *connectors.OrgRoleMapper, cache remotecache.CacheStorage) *SAML {
+	samlLibraryDelayOnce.Do(func() {
+		saml.MaxIssueDelay = samlMaxIssueDelayLimit
+	})
+	return &SAML{
+		cfg:           cfg,
+		log:           log.New(authn.ClientSAML),
+		orgRoleMapper: orgRoleMapper,
+		cache:         cache,
+		httpClient:    &http.Client{Timeout: samlMetadataFetchTimeout},
+	}
+}
+
+// SAML authenticates users against a SAML 2.0 identity provider, acting as the service provider.
+type SAML struct {
+	cfg           *setting.Cfg
+	log           log.Logger
+	orgRoleMapper *connectors.OrgRoleMapper
+	// cache keeps the IDs of the assertions already used until they expire.
+	cache      remotecache.CacheStorage
+	httpClient *http.Client
+
+	mu            sync.RWMutex
+	info          *SAMLInfo
+	sp            *saml.ServiceProvider
+	orgMappingCfg connectors.MappingConfiguration
+}
+
+func (c *SAML) Name() string {
+	return authn.ClientSAML
+}
+
+func (c *SAML) IsEnabled() bool {
+	info, sp := c.current()
+	return info != nil && info.Enabled && sp != nil
+}
+
+func (c *SAML) GetConfig() authn.SSOClientConfig {
+	info, _ := c.current()
+	if info == nil {
+		return &SAMLInfo{Name: "SAML"}
+	}
+	return info
+}
+
+// Info returns the current settings of the client, or nil if none have been loaded.
+func (c *SAML) Info() *SAMLInfo {
+	info, _ := c.current()
+	return info
+}
+
+func (c *SAML) current() (*SAMLInfo, *saml.ServiceProvider) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.info, c.sp
+}
+
+func (c *SAML) enabledProvider() (*SAMLInfo, *saml.ServiceProvider, error) {
+	info, sp := c.current()
+	if info == nil || !info.Enabled || sp == nil {
+		return nil, nil, errSAMLClientDisabled.Errorf("saml client is disabled")
+	}
+	return info, sp, nil
+}
+
+func (c *SAML) RedirectURL(ctx context.Context, r *authn.Request) (*authn.Redirect, error) {
+	_, sp, err := c.enabledProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return nil, errSAMLInternal.Errorf("failed to create authentication request: %w", err)
+	}
+
+	redirectURL, err := req.Redirect("", sp)
+	if err != nil {
+		return nil, errSAMLInternal.Errorf("failed to sign authentication request: %w", err)
+	}
+
+	return &authn.Redirect{
+		URL:   redirectURL.String(),
+		Extra: map[string]string{authn.KeySAMLRequestID: req.ID},
+	}, nil
+}
+
+func (c *SAML) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	r.SetMeta(authn.MetaKeyAuthModule, login.SAMLAuthModule)
+
+	info, sp, err := c.enabledProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	var possibleRequestIDs []string
+	if cookie, err := r.HTTPRequest.Cookie(SAMLRequestIDCookieName); err == nil && cookie.Value != "" {
+		possibleRequestIDs = append(possibleRequestIDs, cookie.Value)
+	}
+	if len(possibleRequestIDs) == 0 && !info.AllowIDPInitiated {
+		return nil, errSAMLMissingRequest.Errorf("no request id cookie and idp initiated login is not allowed")
+	}
+
+	if err := r.HTTPRequest.ParseForm(); err != nil {
+		return nil, errSAMLInvalidResponse.Errorf("failed to parse form: %w", err)
+	}
+
+	assertion, err := sp.ParseResponse(r.HTTPRequest, possibleRequestIDs)
+	if err != nil {
+		var invalidErr *saml.InvalidResponseError
+		if errors.As(err, &invalidErr) {
+			c.log.FromContext(ctx).Warn("Invalid SAML response", "error", invalidErr.PrivateErr)
+		}
+		return nil, errSAMLInvalidResponse.Errorf("failed to validate saml response: %w", err)
+	}
+	if err := checkSAMLIssueDelay(info, assertion, saml.TimeNow()); err != nil {
+		return nil, errSAMLInvalidResponse.Errorf("failed to validate saml response: %w", err)
+	}
+	if err := c.checkAssertionReplay(ctx, info, assertion, saml.TimeNow()); err != nil {
+		return nil, err
+	}
+
+	return c.identityFromAssertion(ctx, info, assertion)
+}
+
+func (c *SAML) identityFromAssertion(ctx context.Context, info *SAMLInfo, assertion *saml.Assertion) (*authn.Identity, error) {
+	attrs := samlAttributes(assertion)
+
+	var nameID string
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		nameID = assertion.Subject.NameID.Value
+	}
+
+	userLogin := attrs.first(info.AttributeLogin)
+	email := attrs.first(info.AttributeEmail)
+	if userLogin == "" {
+		userLogin = email
+	}
+	if userLogin == "" {
+		userLogin = nameID
+	}
+	if email == "" && strings.Contains(userLogin, "@") {
+		email = userLogin
+	}
+	if userLogin == "" {
+		return nil, errSAMLMissingLogin.Errorf("assertion contains neither a login nor an email")
+	}
+
+	orgs := attrs.values(info.AttributeOrg)
+	if len(info.AllowedOrganizations) > 0 && !slices.ContainsFunc(orgs, func(o string) bool {
+		return slices.Contains(info.AllowedOrganizations, o)
+	}) {
+		return nil, errSAMLOrgNotAllowed.Errorf("user is not a member of any of the allowed organizations")
+	}
+
+	id := &authn.Identity{
+		Login:           userLogin,
+		Name:            attrs.first(info.AttributeName),
+		Email:           email,
+		AuthenticatedBy: login.SAMLAuthModule,
+		AuthID:          nameID,
+		Groups:          attrs.values(info.AttributeGroups),
+		ClientParams: authn.ClientParams{
+			SyncUser:        true,
+			SyncTeams:       true,
+			FetchSyncedUser: true,
+			SyncPermissions: true,
+			AllowSignUp:     info.AllowSignUp,
+			LookUpParams: login.UserLookupParams{
+				Login: &userLogin,
+				Email: &email,
+			},
+		},
+	}
+
+	if !info.SkipOrgRoleSync {
+		role, isGrafanaAdmin := mapSAMLRole(info, attrs.values(info.AttributeRole))
+		c.mu.RLock()
+		mappingCfg := c.orgMappingCfg
+		c.mu.RUnlock()
+
+		id.OrgRoles = c.orgRoleMapper.MapOrgRoles(mappingCfg, orgs, role)
+		id.IsGrafanaAdmin = isGrafanaAdmin
+		id.ClientParams.SyncOrgRoles = len(id.OrgRoles) > 0
+	}
+
+	c.log.FromContext(ctx).Debug("Mapped SAML assertion", "login", userLogin, "groups", id.Groups, "orgRoles", id.OrgRoles)
+	return id, nil
+}
+
+// mapSAMLRole resolves the basic role from the role attribute values; the highest matching role wins.
+// isGrafanaAdmin is only returned when the grafana admin role values are configured.
+func mapSAMLRole(info *SAMLInfo, values []string) (org.RoleType, *bool) {
+	if info.AttributeRole == "" {
+		return "", nil
+	}
+
+	matches := func(candidates []string) bool {
+		return slices.ContainsFunc(values, func(v string) bool {
+			return slices.ContainsFunc(candidates, func(c string) bool { return strings.EqualFold(c, v) })
+		})
+	}
+
+	var isGrafanaAdmin *bool
+	if len(info.RoleValuesGrafanaAdmin) > 0 {
+		isAdmin := matches(info.RoleValuesGrafanaAdmin)
+		isGrafanaAdmin = &isAdmin
+		if isAdmin {
+			return org.RoleAdmin, isGrafanaAdmin
+		}
+	}
+
+	switch {
+	case matches(info.RoleValuesAdmin):
+		return org.RoleAdmin, isGrafanaAdmin
+	case matches(info.RoleValuesEditor):
+		return org.RoleEditor, isGrafanaAdmin
+	case matches(info.RoleValuesViewer):
+		return org.RoleViewer, isGrafanaAdmin
+	case matches(info.RoleValuesNone):
+		return org.RoleNone, isGrafanaAdmin
+	}
+	return "", isGrafanaAdmin
+}
+
+func (c *SAML) Logout(ctx context.Context, user identity.Requester, sessionToken *auth.UserToken) (*authn.Redirect, bool) {
+	info, sp := c.current()
+	if info == nil || !info.Enabled || !info.SingleLogout || sp == nil {
+		return nil, false
+	}
+
+	if sp.GetSLOBindingLocation(saml.HTTPRedirectBinding) == "" {
+		c.log.FromContext(ctx).Debug("Identity provider has no single logout endpoint")
+		return nil, false
+	}
+
+	nameID := user.GetAuthID()
+	if nameID == "" {
+		c.log.FromContext(ctx).Debug("No name id found for user, skipping single logout", "id", user.GetID())
+		return nil, false
+	}
+
+	logoutURL, err := sp.MakeRedirectLogoutRequest(nameID, "", "")
+	if err != nil {
+		c.log.FromContext(ctx).Error("Failed to create logout request", "error", err)
+		return nil, false
+	}
+
+	return &authn.Redirect{URL: logoutURL.String()}, true
+}
+
+// Metadata returns the service provider metadata document.
+func (c *SAML) Metadata() ([]byte, error) {
+	_, sp, err := c.enabledProvider()
+	if err != nil {
+		return nil, err
+	}
+	return xml.MarshalIndent(sp.Metadata(), "", "  ")
+}
+
+// ValidateLogoutResponse validates the logout response sent by the identity provider
+// at the end of a single logout.
+func (c *SAML) ValidateLogoutResponse(r *http.Request) error {
+	_, sp, err := c.enabledProvider()
+	if err != nil {
+		return err
+	}
+	if err := sp.ValidateLogoutResponseRequest(r); err != nil {
+		return errSAMLInvalidLogoutMsg.Errorf("failed to validate logout response: %w", err)
+	}
+	return nil
+}
+
+func (c *SAML) Reload(ctx context.Context, settings models.SSOSettings) error {
+	info, err := CreateSAMLInfoFromKeyValues(settings.Settings)
+	if err != nil {
+		return errSAMLInvalidSettings.Errorf("failed to decode saml settings: %w", err)
+	}
+
+	var sp *saml.ServiceProvider
+	if info.Enabled {
+		if sp, err = c.newServiceProvider(ctx, info); err != nil {
+			return err
+		}
+	}
+
+	mappingCfg := c.orgRoleMapper.ParseOrgMappingSettings(ctx, info.OrgMapping, false)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.info = info
+	c.sp = sp
+	c.orgMappingCfg = mappingCfg
+	return nil
+}
+
+// checkSAMLIssueDelay rejects the assertions issued longer than the max issue delay of the provider ago.
+func checkSAMLIssueDelay(info *SAMLInfo, assertion *saml.Assertion, now time.Time) error {
+	maxIssueDelay := info.MaxIssueDelay
+	if maxIssueDelay <= 0 {
+		maxIssueDelay = samlDefaultMaxIssueDelay
+	}
+	if expiresAt := assertion.IssueInstant.Add(maxIssueDelay); expiresAt.Before(now) {
+		return fmt.Errorf("assertion expired on %s", expiresAt)
+	}
+	return nil
+}
+
+// checkAssertionReplay rejects assertions that were already used. IdP-initiated responses aren't bound to an
+// authentication request, so a captured response could otherwise be posted again for as long as it is valid.
+func (c *SAML) checkAssertionReplay(ctx context.Context, info *SAMLInfo, assertion *saml.Assertion, now time.Time) error {
+	if assertion.ID == "" {
+		return errSAMLInvalidResponse.Errorf("assertion has no ID")
+	}
+
+	key := fmt.Sprintf(samlAssertionKeyPrefix, assertion.ID)
+	_, err := c.cache.Get(ctx, key)
+	if err == nil {
+		return errSAMLInvalidResponse.Errorf("assertion %s was already used", assertion.ID)
+	}
+	if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		return errSAMLInternal.Errorf("failed to check saml assertion: %w", err)
+	}
+
+	if err := c.cache.Set(ctx, key, []byte{1}, samlAssertionTTL(info, assertion, now)); err != nil {
+		return errSAMLInternal.Errorf("failed to store saml assertion: %w", err)
+	}
+	return nil
+}
+
+// samlAssertionTTL returns how long the assertion is still accepted: until the earliest of its NotOnOrAfter
+// condition and the end of its issue delay, plus the clock skew tolerated by the SAML library.
+func samlAssertionTTL(info *SAMLInfo, assertion *saml.Assertion, now time.Time) time.Duration {
+	maxIssueDelay := info.MaxIssueDelay
+	if maxIssueDelay <= 0 {
+		maxIssueDelay = samlDefaultMaxIssueDelay
+	}
+	expiresAt := assertion.IssueInstant.Add(maxIssueDelay)
+	if assertion.Conditions != nil && !assertion.Conditions.NotOnOrAfter.IsZero() && assertion.Conditions.NotOnOrAfter.Before(expiresAt) {
+		expiresAt = assertion.Conditions.NotOnOrAfter
+	}
+	// The remote cache keeps items without an expiry for a day, always set one.
+	return max(expiresAt.Add(saml.MaxClockSkew).Sub(now), time.Second)
+}
+
+func (c *SAML) Validate(ctx context.Context, settings models.SSOSettings, _ models.SSOSettings, _ identity.Requester) error {
+	info, err := CreateSAMLInfoFromKeyValues(settings.Settings)
+	if err != nil {
+		return errSAMLInvalidSettings.Errorf("failed to decode saml settings: %w", err)
+	}
+	if !info.Enabled {
+		return nil
+	}
+	_, err = c.newServiceProvider(ctx, info)
+	return err
+}
+
+func (c *SAML) newServiceProvider(ctx context.Context, info *SAMLInfo) (*saml.ServiceProvider, error) {
+	if info.MaxIssueDelay > samlMaxIssueDelayLimit {
+		return nil, errSAMLInvalidSettings.Errorf("max issue delay must not exceed %s", samlMaxIssueDelayLimit)
+	}
+
+	keyPair, err := loadSAMLKeyPair(info)
+	if err != nil {
+		return nil, errSAMLInvalidSettings.Errorf("failed to load certificate and private key: %w", err)
+	}
+	if len(keyPair.Certificate) == 0 {
+		return nil, errSAMLInvalidSettings.Errorf("certificate is missing")
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, errSAMLInvalidSettings.Errorf("failed to parse certificate: %w", err)
+	}
+	key, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errSAMLInvalidSettings.Errorf("private key must be an RSA key")
+	}
+
+	signatureMethod, err := samlSignatureMethod(info.SignatureAlgorithm)
+	if err != nil {
+		return nil, errSAMLInvalidSettings.Errorf("%w", err)
+	}
+
+	idpMetadata, err := c.loadIDPMetadata(ctx, info)
+	if err != nil {
+		return nil, errSAMLInvalidSettings.Errorf("failed to load identity provider metadata: %w", err)
+	}
+
+	appURL, err := url.Parse(strings.TrimSuffix(c.cfg.AppURL, "/"))
+	if err != nil {
+		return nil, errSAMLInternal.Errorf("invalid app url: %w", err)
+	}
+
+	sp := &saml.ServiceProvider{
+		EntityID:              info.EntityID,
+		Key:                   key,
+		Certificate:           cert,
+		HTTPClient:            c.httpClient,
+		MetadataURL:           *appURL.JoinPath("saml", "metadata"),
+		AcsURL:                *appURL.JoinPath("saml", "acs"),
+		SloURL:                *appURL.JoinPath("saml", "slo"),
+		IDPMetadata:           idpMetadata,
+		AuthnNameIDFormat:     saml.NameIDFormat(info.NameIDFormat),
+		MetadataValidDuration: info.MetadataValidDuration,
+		AllowIDPInitiated:     info.AllowIDPInitiated,
+		SignatureMethod:       signatureMethod,
+		LogoutBindings:        []string{saml.HTTPRedirectBinding, saml.HTTPPostBinding},
+	}
+	if info.NameIDFormat == "" {
+		sp.AuthnNameIDFormat = saml.TransientNameIDFormat
+	}
+	if sp.GetSSOBindingLocation(saml.HTTPRedirectBinding) == "" {
+		return nil, errSAMLInvalidSettings.Errorf("identity provider metadata has no HTTP-Redirect single sign-on endpoint")
+	}
+	return sp, nil
+}
+
+func samlSignatureMethod(algorithm string) (string, error) {
+	switch algorithm {
+	case "":
+		return "", nil
+	case "rsa-sha1":
+		return dsig.RSASHA1SignatureMethod, nil
+	case "rsa-sha256":
+		return dsig.RSASHA256SignatureMethod, nil
+	case "rsa-sha512":
+		return dsig.RSASHA512SignatureMethod, nil
+	}
+	return "", fmt.Errorf("unsupported signature algorithm %q", algorithm)
+}
+
+// loadSAMLKeyPair loads the service provider key pair; inline values are base64 encoded PEM.
+func loadSAMLKeyPair(info *SAMLInfo) (tls.Certificate, error) {
+	certPEM, err := readSAMLValue(info.Certificate, info.CertificatePath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := readSAMLValue(info.PrivateKey, info.PrivateKeyPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return tls.Certificate{}, errors.New("certificate and private key are required")
+	}
+	if block, _ := pem.Decode(certPEM); block == nil {
+		return tls.Certificate{}, errors.New("certificate is not PEM encoded")
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+func (c *SAML) loadIDPMetadata(ctx context.Context, info *SAMLInfo) (*saml.EntityDescriptor, error) {
+	data, err := readSAMLValue(info.IDPMetadata, info.IDPMetadataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 && info.IDPMetadataURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, info.IDPMetadataURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				c.log.Warn("Failed to close response body", "error", err)
+			}
+		}()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, info.IDPMetadataURL)
+		}
+		if data, err = io.ReadAll(io.LimitReader(resp.Body, 10<<20)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("one of idp_metadata, idp_metadata_path or idp_metadata_url is required")
+	}
+	return parseIDPMetadata(data)
+}
+
+// parseIDPMetadata parses identity provider metadata, which is sometimes wrapped in an EntitiesDescriptor.
+func parseIDPMetadata(data []byte) (*saml.EntityDescriptor, error) {
+	entity := &saml.EntityDescriptor{}
+	err := xml.Unmarshal(data, entity)
+	if err == nil {
+		return entity, nil
+	}
+
+	entities := &saml.EntitiesDescriptor{}
+	if xml.Unmarshal(data, entities) != nil {
+		return nil, err
+	}
+	for i, e := range entities.EntityDescriptors {
+		if len(e.IDPSSODescriptors) > 0 {
+			return &entities.EntityDescriptors[i], nil
+		}
+	}
+	return nil, errors.New("no entity found with an IDPSSODescriptor")
+}
+
+// readSAMLValue returns the base64 decoded inline value, or the content of the file at path.
+func readSAMLValue(inline, path string) ([]byte, error) {
+	if inline != "" {
+		decoded, err := base64.StdEncoding.DecodeString(inline)
+		if err != nil {
+			return nil, fmt.Errorf("value is not base64 encoded: %w", err)
+		}
+		return decoded, nil
+	}
+	if path != "" {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning since the path comes from the server configuration
+		return os.ReadFile(path)
+	}
+	return nil, nil
+}
+
+type samlAttributeValues map[string][]string
+
+func samlAttributes(assertion *saml.Assertion) samlAttributeValues {
+	attrs := samlAttributeValues{}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			var values []string
+			for _, v := range attr.Values {
+				values = append(values, v.Value)
+			}
+			attrs[attr.Name] = append(attrs[attr.Name], values...)
+			if attr.FriendlyName != "" && attr.FriendlyName != attr.Name {
+				attrs[attr.FriendlyName] = append(attrs[attr.FriendlyName], values...)
+			}
+		}
+	}
+	return attrs
+}
+
+func (a samlAttributeValues) values(name string) []string {
+	if name == "" {
+		return nil
+	}
+	return a[name]
+}
+
+func (a samlAttributeValues) first(name string) string {
+	if values := a.values(name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,300 @@
+package clients
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/login/social/connectors"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/ssosettings/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testIDPMetadata = `<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://idp.example.com/metadata">
+  <IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/slo"/>
+    <SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://idp.example.com/sso"/>
+  </IDPSSODescriptor>
+</EntityDescriptor>`
+
+func TestCreateSAMLInfoFromKeyValues(t *testing.T) {
+	info, err := CreateSAMLInfoFromKeyValues(map[string]any{
+		"enabled":                   true,
+		"name":                      "",
+		"max_issue_delay":           "2m",
+		"metadata_valid_duration":   48 * time.Hour,
+		"org_mapping":               "Engineering:2:Editor, Sales:3",
+		"role_values_admin":         "admin superuser",
+		"role_values_grafana_admin": "",
+		"skip_org_role_sync":        "true",
+	})
+	require.NoError(t, err)
+
+	assert.True(t, info.Enabled)
+	assert.Equal(t, "SAML", info.GetDisplayName())
+	assert.Equal(t, 2*time.Minute, info.MaxIssueDelay)
+	assert.Equal(t, 48*time.Hour, info.MetadataValidDuration)
+	assert.Equal(t, []string{"Engineering:2:Editor", "Sales:3"}, info.OrgMapping)
+	assert.Equal(t, []string{"admin", "superuser"}, info.RoleValuesAdmin)
+	assert.Empty(t, info.RoleValuesGrafanaAdmin)
+	assert.True(t, info.IsSkipOrgRoleSyncEnabled())
+}
+
+func TestMapSAMLRole(t *testing.T) {
+	info := &SAMLInfo{
+		AttributeRole:          "role",
+		RoleValuesNone:         []string{"guest"},
+		RoleValuesViewer:       []string{"viewer"},
+		RoleValuesEditor:       []string{"editor", "developer"},
+		RoleValuesAdmin:        []string{"admin"},
+		RoleValuesGrafanaAdmin: []string{"superadmin"},
+	}
+
+	tests := []struct {
+		desc           string
+		values         []string
+		role           org.RoleType
+		isGrafanaAdmin bool
+	}{
+		{desc: "highest role wins", values: []string{"viewer", "Developer"}, role: org.RoleEditor},
+		{desc: "grafana admin is an org admin", values: []string{"superadmin"}, role: org.RoleAdmin, isGrafanaAdmin: true},
+		{desc: "none role", values: []string{"guest"}, role: org.RoleNone},
+		{desc: "unknown values map to no role", values: []string{"other"}, role: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			role, isGrafanaAdmin := mapSAMLRole(info, tt.values)
+			assert.Equal(t, tt.role, role)
+			require.NotNil(t, isGrafanaAdmin)
+			assert.Equal(t, tt.isGrafanaAdmin, *isGrafanaAdmin)
+		})
+	}
+
+	t.Run("no role attribute configured", func(t *testing.T) {
+		role, isGrafanaAdmin := mapSAMLRole(&SAMLInfo{}, []string{"admin"})
+		assert.Empty(t, role)
+		assert.Nil(t, isGrafanaAdmin)
+	})
+}
+
+func TestSAML_IdentityFromAssertion(t *testing.T) {
+	client := setupSAMLClient(t, map[string]any{
+		"assertion_attribute_login":  "uid",
+		"assertion_attribute_email":  "mail",
+		"assertion_attribute_name":   "displayName",
+		"assertion_attribute_groups": "groups",
+		"assertion_attribute_role":   "role",
+		"assertion_attribute_org":    "org",
+		"role_values_editor":         "editor",
+		"org_mapping":                "Engineering:2:Viewer",
+		"allowed_organizations":      "Engineering Sales",
+	})
+	info := client.Info()
+
+	assertion := &saml.Assertion{
+		Subject: &saml.Subject{NameID: &saml.NameID{Value: "name-id-1"}},
+		AttributeStatements: []saml.AttributeStatement{{Attributes: []saml.Attribute{
+			samlAttribute("uid", "", "alice"),
+			samlAttribute("urn:oid:0.9.2342.19200300.100.1.3", "mail", "alice@example.com"),
+			samlAttribute("displayName", "", "Alice"),
+			samlAttribute("groups", "", "backend", "oncall"),
+			samlAttribute("role", "", "editor"),
+			samlAttribute("org", "", "Engineering"),
+		}}},
+	}
+
+	t.Run("maps attributes to the identity", func(t *testing.T) {
+		id, err := client.identityFromAssertion(context.Background(), info, assertion)
+		require.NoError(t, err)
+
+		assert.Equal(t, "alice", id.Login)
+		assert.Equal(t, "alice@example.com", id.Email)
+		assert.Equal(t, "Alice", id.Name)
+		assert.Equal(t, "name-id-1", id.AuthID)
+		assert.Equal(t, login.SAMLAuthModule, id.AuthenticatedBy)
+		assert.Equal(t, []string{"backend", "oncall"}, id.Groups)
+		assert.Equal(t, map[int64]org.RoleType{2: org.RoleEditor}, id.OrgRoles)
+		assert.True(t, id.ClientParams.SyncOrgRoles)
+		assert.True(t, id.ClientParams.SyncTeams)
+	})
+
+	t.Run("rejects users outside the allowed organizations", func(t *testing.T) {
+		other := *assertion
+		other.AttributeStatements = []saml.AttributeStatement{{Attributes: []saml.Attribute{
+			samlAttribute("uid", "", "bob"),
+			samlAttribute("org", "", "Marketing"),
+		}}}
+		_, err := client.identityFromAssertion(context.Background(), info, &other)
+		require.ErrorIs(t, err, errSAMLOrgNotAllowed)
+	})
+}
+
+func TestSAML_RedirectURL(t *testing.T) {
+	client := setupSAMLClient(t, map[string]any{"signature_algorithm": "rsa-sha256"})
+
+	redirect, err := client.RedirectURL(context.Background(), nil)
+	require.NoError(t, err)
+
+	u, err := url.Parse(redirect.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "idp.example.com", u.Host)
+	assert.Equal(t, "/sso", u.Path)
+	assert.NotEmpty(t, u.Query().Get("SAMLRequest"))
+	assert.NotEmpty(t, u.Query().Get("Signature"))
+	assert.NotEmpty(t, redirect.Extra["saml_request_id"])
+
+	metadata, err := client.Metadata()
+	require.NoError(t, err)
+	assert.Contains(t, string(metadata), "https://grafana.example.com/saml/acs")
+}
+
+func TestSAML_Logout(t *testing.T) {
+	usr := &user.SignedInUser{UserID: 1, AuthID: "name-id-1", AuthenticatedBy: login.SAMLAuthModule}
+
+	t.Run("single logout redirects to the identity provider", func(t *testing.T) {
+		client := setupSAMLClient(t, map[string]any{"single_logout": true})
+		redirect, ok := client.Logout(context.Background(), usr, nil)
+		require.True(t, ok)
+		assert.Contains(t, redirect.URL, "https://idp.example.com/slo?SAMLRequest=")
+	})
+
+	t.Run("single logout disabled", func(t *testing.T) {
+		client := setupSAMLClient(t, map[string]any{})
+		_, ok := client.Logout(context.Background(), usr, nil)
+		require.False(t, ok)
+	})
+}
+
+func TestSAML_Validate(t *testing.T) {
+	client := setupSAMLClient(t, map[string]any{})
+
+	err := client.Validate(context.Background(), models.SSOSettings{Settings: map[string]any{"enabled": false}}, models.SSOSettings{}, nil)
+	require.NoError(t, err)
+
+	err = client.Validate(context.Background(), models.SSOSettings{Settings: map[string]any{"enabled": true}}, models.SSOSettings{}, nil)
+	require.ErrorIs(t, err, errSAMLInvalidSettings)
+
+	settings := testSAMLSettings(t)
+	settings["signature_algorithm"] = "dsa-md5"
+	err = client.Validate(context.Background(), models.SSOSettings{Settings: settings}, models.SSOSettings{}, nil)
+	require.ErrorIs(t, err, errSAMLInvalidSettings)
+
+	settings = testSAMLSettings(t)
+	settings["max_issue_delay"] = "2h"
+	err = client.Validate(context.Background(), models.SSOSettings{Settings: settings}, models.SSOSettings{}, nil)
+	require.ErrorIs(t, err, errSAMLInvalidSettings)
+}
+
+func TestCheckSAMLIssueDelay(t *testing.T) {
+	now := time.Now()
+	assertion := &saml.Assertion{IssueInstant: now.Add(-2 * time.Minute)}
+
+	require.Error(t, checkSAMLIssueDelay(&SAMLInfo{}, assertion, now), "the default delay is 90s")
+	require.NoError(t, checkSAMLIssueDelay(&SAMLInfo{MaxIssueDelay: 5 * time.Minute}, assertion, now))
+	require.Error(t, checkSAMLIssueDelay(&SAMLInfo{MaxIssueDelay: time.Minute}, assertion, now))
+}
+
+func TestSAML_CheckAssertionReplay(t *testing.T) {
+	client := setupSAMLClient(t, nil)
+	info := &SAMLInfo{}
+	now := time.Now()
+	assertion := &saml.Assertion{ID: "id-1", IssueInstant: now}
+
+	require.NoError(t, client.checkAssertionReplay(context.Background(), info, assertion, now))
+	err := client.checkAssertionReplay(context.Background(), info, assertion, now)
+	require.ErrorIs(t, err, errSAMLInvalidResponse, "the same assertion can't be used twice")
+
+	require.NoError(t, client.checkAssertionReplay(context.Background(), info, &saml.Assertion{ID: "id-2", IssueInstant: now}, now))
+	require.ErrorIs(t, client.checkAssertionReplay(context.Background(), info, &saml.Assertion{IssueInstant: now}, now), errSAMLInvalidResponse)
+}
+
+func TestSAMLAssertionTTL(t *testing.T) {
+	now := time.Now()
+
+	assertion := &saml.Assertion{IssueInstant: now}
+	assert.Equal(t, samlDefaultMaxIssueDelay+saml.MaxClockSkew, samlAssertionTTL(&SAMLInfo{}, assertion, now))
+	assert.Equal(t, 10*time.Minute+saml.MaxClockSkew, samlAssertionTTL(&SAMLInfo{MaxIssueDelay: 10 * time.Minute}, assertion, now))
+
+	assertion.Conditions = &saml.Conditions{NotOnOrAfter: now.Add(time.Minute)}
+	assert.Equal(t, time.Minute+saml.MaxClockSkew, samlAssertionTTL(&SAMLInfo{MaxIssueDelay: 10 * time.Minute}, assertion, now), "NotOnOrAfter is earlier")
+
+	assert.Equal(t, time.Second, samlAssertionTTL(&SAMLInfo{}, assertion, now.Add(time.Hour)), "always set an expiry")
+}
+
+func TestSAML_ReloadDoesNotChangeOtherProviders(t *testing.T) {
+	strict := setupSAMLClient(t, map[string]any{"max_issue_delay": "30s"})
+	lenient := setupSAMLClient(t, map[string]any{"max_issue_delay": "10m"})
+
+	assertion := &saml.Assertion{IssueInstant: time.Now().Add(-time.Minute)}
+	require.Error(t, checkSAMLIssueDelay(strict.Info(), assertion, time.Now()))
+	require.NoError(t, checkSAMLIssueDelay(lenient.Info(), assertion, time.Now()))
+	require.Equal(t, samlMaxIssueDelayLimit, saml.MaxIssueDelay)
+}
+
+func setupSAMLClient(t *testing.T, extra map[string]any) *SAML {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "https://grafana.example.com/"
+	cfg.AutoAssignOrgRole = string(org.RoleViewer)
+
+	client := ProvideSAML(cfg, connectors.ProvideOrgRoleMapper(cfg, orgtest.NewOrgServiceFake()), remotecache.NewFakeCacheStorage())
+	settings := testSAMLSettings(t)
+	for k, v := range extra {
+		settings[k] = v
+	}
+	require.NoError(t, client.Reload(context.Background(), models.SSOSettings{Settings: settings}))
+	require.True(t, client.IsEnabled())
+	return client
+}
+
+func testSAMLSettings(t *testing.T) map[string]any {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "grafana"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	encode := func(typ string, b []byte) string {
+		return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}))
+	}
+
+	return map[string]any{
+		"enabled":      true,
+		"certificate":  encode("CERTIFICATE", der),
+		"private_key":  encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		"idp_metadata": base64.StdEncoding.EncodeToString([]byte(testIDPMetadata)),
+	}
+}
+
+func samlAttribute(name, friendlyName string, values ...string) saml.Attribute {
+	attr := saml.Attribute{Name: name, FriendlyName: friendlyName}
+	for _, v := range values {
+		attr.Values = append(attr.Values, saml.AttributeValue{Value: v})
+	}
+	return attr
+}
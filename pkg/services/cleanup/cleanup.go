@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/accessgrant"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
@@ -41,12 +42,14 @@ type CleanUpService struct {
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
 	dashboardService          dashboards.DashboardService
+	accessGrantService        *accessgrant.Service
 }
 
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner, dashboardService dashboards.DashboardService) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner, dashboardService dashboards.DashboardService,
+	accessGrantService *accessgrant.Service) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -61,6 +64,7 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
 		dashboardService:          dashboardService,
+		accessGrantService:        accessGrantService,
 	}
 	return s
 }
@@ -106,6 +110,7 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"delete stale query history", srv.deleteStaleQueryHistory},
 		{"expire old email verifications", srv.expireOldVerifications},
 		{"cleanup trash dashboards", srv.cleanUpTrashDashboards},
+		{"expire access grants", srv.expireAccessGrants},
 	}
 
 	if srv.Cfg.ShortLinkExpiration > 0 {
@@ -313,3 +318,13 @@ func (srv *CleanUpService) cleanUpTrashDashboards(ctx context.Context) {
 		logger.Debug("Cleaned up deleted dashboards", "dashboards affected", affected)
 	}
 }
+
+func (srv *CleanUpService) expireAccessGrants(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	affected, err := srv.accessGrantService.ExpireGrants(ctx)
+	if err != nil {
+		logger.Error("Problem expiring access grants", "error", err)
+	} else {
+		logger.Debug("Expired access grants", "grants affected", affected)
+	}
+}
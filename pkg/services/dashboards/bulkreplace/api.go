@@ -0,0 +1,95 @@
+package bulkreplace
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+	// Bulk replace can change any dashboard of the organization, each dashboard is also checked when it is saved.
+	canWriteAll := authorize(accesscontrol.EvalAll(
+		accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeFoldersAll),
+		accesscontrol.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeFoldersAll),
+	))
+
+	routeRegister.Group("/api/dashboards/bulk-replace", func(r routing.RouteRegister) {
+		r.Get("/", canWriteAll, routing.Wrap(s.listHandler))
+		r.Post("/", canWriteAll, routing.Wrap(s.applyHandler))
+		r.Post("/preview", canWriteAll, routing.Wrap(s.previewHandler))
+		r.Get("/:uid", canWriteAll, routing.Wrap(s.getHandler))
+		r.Post("/:uid/rollback", canWriteAll, routing.Wrap(s.rollbackHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// POST /api/dashboards/bulk-replace/preview
+func (s *Service) previewHandler(c *contextmodel.ReqContext) response.Response {
+	q := Query{}
+	if err := web.Bind(c.Req, &q); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	preview, err := s.Preview(c.Req.Context(), c.SignedInUser, q)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to preview bulk replace", err)
+	}
+	return response.JSON(http.StatusOK, preview)
+}
+
+// POST /api/dashboards/bulk-replace
+func (s *Service) applyHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := ApplyCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	batch, err := s.Apply(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return saveErrorResponse("Failed to apply bulk replace", err)
+	}
+	return response.JSON(http.StatusOK, batch)
+}
+
+// GET /api/dashboards/bulk-replace
+func (s *Service) listHandler(c *contextmodel.ReqContext) response.Response {
+	batches, err := s.List(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list bulk replaces", err)
+	}
+	return response.JSON(http.StatusOK, batches)
+}
+
+// GET /api/dashboards/bulk-replace/:uid
+func (s *Service) getHandler(c *contextmodel.ReqContext) response.Response {
+	batch, err := s.Get(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get bulk replace", err)
+	}
+	return response.JSON(http.StatusOK, batch)
+}
+
+// POST /api/dashboards/bulk-replace/:uid/rollback
+func (s *Service) rollbackHandler(c *contextmodel.ReqContext) response.Response {
+	batch, err := s.Rollback(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return saveErrorResponse("Failed to roll back bulk replace", err)
+	}
+	return response.JSON(http.StatusOK, batch)
+}
+
+// saveErrorResponse keeps the status of the errors returned when saving a dashboard fails.
+func saveErrorResponse(message string, err error) response.Response {
+	var dashboardErr dashboards.DashboardErr
+	if ok := errors.As(err, &dashboardErr); ok {
+		return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
+}
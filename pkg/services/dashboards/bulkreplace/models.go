@@ -0,0 +1,95 @@
+package bulkreplace
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrInvalidQuery      = errutil.BadRequest("dashboards.bulk-replace-invalid-query")
+	ErrNoChanges         = errutil.BadRequest("dashboards.bulk-replace-no-changes", errutil.WithPublicMessage("No dashboard matches the query"))
+	ErrBatchNotFound     = errutil.NotFound("dashboards.bulk-replace-not-found", errutil.WithPublicMessage("Bulk replace not found"))
+	ErrAlreadyRolledBack = errutil.Conflict("dashboards.bulk-replace-rolled-back", errutil.WithPublicMessage("Bulk replace already rolled back"))
+	ErrRollbackConflict  = errutil.Conflict("dashboards.bulk-replace-rollback-conflict",
+		errutil.WithPublicMessage("Dashboards changed or deleted since the bulk replace, they must be restored manually"))
+	ErrUnsupportedStorage = errutil.NotImplemented("dashboards.bulk-replace-unsupported",
+		errutil.WithPublicMessage("Bulk replace isn't supported for dashboards stored in unified storage"))
+)
+
+// Query selects the values to replace in the dashboards of an organization.
+type Query struct {
+	// Path is a JSONPath selecting the values to replace, every value of the dashboards when empty.
+	Path string `json:"path"`
+	// Pattern is a regular expression replaced in the selected string values. Without pattern, the
+	// selected string values are replaced entirely.
+	Pattern string `json:"pattern"`
+	// Replacement replaces the matches, `$1` and `${name}` expand to the groups of the pattern.
+	Replacement string `json:"replacement"`
+	// DashboardUIDs limits the replacement to these dashboards.
+	DashboardUIDs []string `json:"dashboardUids"`
+	// FolderUIDs limits the replacement to the dashboards directly in these folders, use `general`
+	// for the dashboards at the root.
+	FolderUIDs []string `json:"folderUids"`
+}
+
+type ApplyCommand struct {
+	Query
+	// Message is the commit message of the new dashboard versions.
+	Message string `json:"message"`
+}
+
+type Change struct {
+	Path   string `json:"path"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+type DashboardPreview struct {
+	DashboardUID string   `json:"dashboardUid"`
+	Title        string   `json:"title"`
+	FolderUID    string   `json:"folderUid"`
+	Version      int      `json:"version"`
+	Changes      []Change `json:"changes"`
+	// Skipped explains why the dashboard won't be changed, for example because it is provisioned.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+type Preview struct {
+	Dashboards []*DashboardPreview `json:"dashboards"`
+}
+
+// Batch is an applied bulk replace.
+type Batch struct {
+	ID           int64      `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID        int64      `json:"orgId" xorm:"org_id"`
+	UID          string     `json:"uid" xorm:"uid"`
+	Message      string     `json:"message" xorm:"message"`
+	Query        Query      `json:"query" xorm:"-"`
+	RawQuery     string     `json:"-" xorm:"query"`
+	CreatedBy    int64      `json:"createdBy" xorm:"created_by"`
+	Created      time.Time  `json:"created" xorm:"'created'"`
+	RolledBack   *time.Time `json:"rolledBack,omitempty" xorm:"rolled_back"`
+	RolledBackBy int64      `json:"rolledBackBy,omitempty" xorm:"rolled_back_by"`
+
+	Items []*BatchItem `json:"items,omitempty" xorm:"-"`
+}
+
+func (Batch) TableName() string {
+	return "dashboard_bulk_replace"
+}
+
+// BatchItem is a dashboard changed by a batch.
+type BatchItem struct {
+	ID            int64  `json:"-" xorm:"pk autoincr 'id'"`
+	BatchID       int64  `json:"-" xorm:"batch_id"`
+	DashboardUID  string `json:"dashboardUid" xorm:"dashboard_uid"`
+	Title         string `json:"title" xorm:"title"`
+	VersionBefore int    `json:"versionBefore" xorm:"version_before"`
+	VersionAfter  int    `json:"versionAfter" xorm:"version_after"`
+	Changes       int    `json:"changes" xorm:"changes"`
+}
+
+func (BatchItem) TableName() string {
+	return "dashboard_bulk_replace_item"
+}
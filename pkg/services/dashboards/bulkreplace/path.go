@@ -0,0 +1,185 @@
+package bulkreplace
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// segment is a step of a JSONPath. The supported syntax is a subset of JSONPath: `$`, child names with
+// `.name` or `['name']`, array indexes with `[0]`, wildcards with `.*` or `[*]` and recursive descent
+// with `..name` or `..*`.
+type segment struct {
+	name      string
+	index     int
+	isIndex   bool
+	wildcard  bool
+	recursive bool
+}
+
+// location is a value selected by a path, it can be replaced through its parent.
+type location struct {
+	parent any
+	key    string
+	index  int
+	path   string
+}
+
+func (l location) get() any {
+	if m, ok := l.parent.(map[string]any); ok {
+		return m[l.key]
+	}
+	return l.parent.([]any)[l.index]
+}
+
+func (l location) set(v any) {
+	if m, ok := l.parent.(map[string]any); ok {
+		m[l.key] = v
+		return
+	}
+	l.parent.([]any)[l.index] = v
+}
+
+var simpleName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+func parsePath(path string) ([]segment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path must start with $")
+	}
+	var segments []segment
+	rest := path[1:]
+	for rest != "" {
+		var seg segment
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			seg.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			rest = "." + rest
+			fallthrough
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			if name == "" {
+				return nil, fmt.Errorf("empty name in path %q", path)
+			}
+			if name == "*" {
+				seg.wildcard = true
+			} else {
+				seg.name = name
+			}
+			segments = append(segments, seg)
+			continue
+		}
+
+		if !strings.HasPrefix(rest, "[") {
+			return nil, fmt.Errorf("unexpected %q in path %q", rest, path)
+		}
+		end := strings.Index(rest, "]")
+		if end == -1 {
+			return nil, fmt.Errorf("unclosed bracket in path %q", path)
+		}
+		inner := rest[1:end]
+		rest = rest[end+1:]
+		switch {
+		case inner == "*":
+			seg.wildcard = true
+		case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+			seg.name = inner[1 : len(inner)-1]
+		default:
+			i, err := strconv.Atoi(inner)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid index %q in path %q", inner, path)
+			}
+			seg.index, seg.isIndex = i, true
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// selectPath returns the locations of the values matching the path in doc, in a stable order.
+func selectPath(doc any, segments []segment) []location {
+	current := []location{{parent: map[string]any{"$": doc}, key: "$", path: "$"}}
+	for _, seg := range segments {
+		var next []location
+		seen := map[string]bool{}
+		add := func(l location) {
+			if !seen[l.path] {
+				seen[l.path] = true
+				next = append(next, l)
+			}
+		}
+		for _, l := range current {
+			candidates := []location{l}
+			if seg.recursive {
+				candidates = descendants(l)
+			}
+			for _, c := range candidates {
+				for _, child := range children(c) {
+					if matches(seg, child) {
+						add(child)
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+func matches(seg segment, l location) bool {
+	if seg.wildcard {
+		return true
+	}
+	if _, ok := l.parent.(map[string]any); ok {
+		return !seg.isIndex && l.key == seg.name
+	}
+	return seg.isIndex && l.index == seg.index
+}
+
+// children returns the locations of the direct children of the value at l.
+func children(l location) []location {
+	var res []location
+	switch v := l.get().(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			res = append(res, location{parent: v, key: k, path: childPath(l.path, k)})
+		}
+	case []any:
+		for i := range v {
+			res = append(res, location{parent: v, index: i, path: fmt.Sprintf("%s[%d]", l.path, i)})
+		}
+	}
+	return res
+}
+
+// descendants returns l and all the locations nested in its value.
+func descendants(l location) []location {
+	res := []location{l}
+	for _, c := range children(l) {
+		res = append(res, descendants(c)...)
+	}
+	return res
+}
+
+func childPath(parent, key string) string {
+	if simpleName.MatchString(key) {
+		return parent + "." + key
+	}
+	return parent + "['" + strings.ReplaceAll(key, "'", `\'`) + "']"
+}
@@ -0,0 +1,77 @@
+package bulkreplace
+
+import (
+	"regexp"
+	"strings"
+)
+
+// protectedPaths are managed by Grafana and never replaced.
+var protectedPaths = map[string]bool{
+	"$.id":      true,
+	"$.uid":     true,
+	"$.version": true,
+}
+
+type replacer struct {
+	segments    []segment
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func compileQuery(q Query) (*replacer, error) {
+	if q.Path == "" && q.Pattern == "" {
+		return nil, ErrInvalidQuery.Errorf("a path or a pattern is required")
+	}
+
+	r := &replacer{replacement: q.Replacement}
+	path := q.Path
+	if path == "" {
+		path = "$..*"
+	}
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, ErrInvalidQuery.Errorf("invalid path: %w", err)
+	}
+	r.segments = segments
+
+	if q.Pattern != "" {
+		r.pattern, err = regexp.Compile(q.Pattern)
+		if err != nil {
+			return nil, ErrInvalidQuery.Errorf("invalid pattern: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// apply replaces the selected string values of the dashboard model in place and returns the changes.
+func (r *replacer) apply(model any) []Change {
+	changes := []Change{}
+	for _, l := range selectPath(model, r.segments) {
+		if protectedPaths[l.path] {
+			continue
+		}
+		before, ok := l.get().(string)
+		if !ok {
+			continue
+		}
+		after := r.replacement
+		if r.pattern != nil {
+			after = r.pattern.ReplaceAllString(before, r.replacement)
+		}
+		if after == before {
+			continue
+		}
+		l.set(after)
+		changes = append(changes, Change{Path: l.path, Before: before, After: after})
+	}
+	return changes
+}
+
+func containsFolder(folderUIDs []string, folderUID string) bool {
+	for _, uid := range folderUIDs {
+		if uid == folderUID || (folderUID == "" && strings.EqualFold(uid, "general")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package bulkreplace
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDashboard = `{
+	"id": 1,
+	"uid": "checkout",
+	"version": 3,
+	"title": "Checkout on prometheus",
+	"panels": [
+		{"title": "Latency", "datasource": {"uid": "prometheus-old", "type": "prometheus"}, "targets": [{"expr": "rate(http_requests_total[5m])"}]},
+		{"title": "Errors", "datasource": {"uid": "loki"}, "targets": [{"datasource": {"uid": "prometheus-old"}, "expr": "http_requests_total{code=\"500\"}"}]}
+	],
+	"templating": {"list": [{"name": "ds", "query": "prometheus"}]},
+	"links": [{"title": "Ops", "url": "/d/ops"}],
+	"owner team": "ops"
+}`
+
+func TestReplacer(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		query    Query
+		expected []Change
+	}{
+		{
+			desc:  "a path replaces the selected values",
+			query: Query{Path: "$..datasource.uid", Pattern: "^prometheus-old$", Replacement: "prometheus-new"},
+			expected: []Change{
+				{Path: "$.panels[0].datasource.uid", Before: "prometheus-old", After: "prometheus-new"},
+				{Path: "$.panels[1].targets[0].datasource.uid", Before: "prometheus-old", After: "prometheus-new"},
+			},
+		},
+		{
+			desc:  "a pattern without path replaces in every string value",
+			query: Query{Pattern: `http_requests_total`, Replacement: "http_server_requests_total"},
+			expected: []Change{
+				{Path: "$.panels[0].targets[0].expr", Before: "rate(http_requests_total[5m])", After: "rate(http_server_requests_total[5m])"},
+				{Path: "$.panels[1].targets[0].expr", Before: `http_requests_total{code="500"}`, After: `http_server_requests_total{code="500"}`},
+			},
+		},
+		{
+			desc:  "groups are expanded",
+			query: Query{Path: "$.panels[*].title", Pattern: `^(\w+)$`, Replacement: "Checkout ${1}"},
+			expected: []Change{
+				{Path: "$.panels[0].title", Before: "Latency", After: "Checkout Latency"},
+				{Path: "$.panels[1].title", Before: "Errors", After: "Checkout Errors"},
+			},
+		},
+		{
+			desc:     "a path without pattern replaces the whole values",
+			query:    Query{Path: "$.templating.list[0]['query']", Replacement: "prometheus-new"},
+			expected: []Change{{Path: "$.templating.list[0].query", Before: "prometheus", After: "prometheus-new"}},
+		},
+		{
+			desc:     "the uid of the dashboard is never replaced",
+			query:    Query{Pattern: "^checkout$", Replacement: "payments"},
+			expected: []Change{},
+		},
+		{
+			desc:     "names with special characters are quoted",
+			query:    Query{Pattern: "^ops$", Replacement: "sre"},
+			expected: []Change{{Path: "$['owner team']", Before: "ops", After: "sre"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			r, err := compileQuery(tc.query)
+			require.NoError(t, err)
+
+			var model any
+			require.NoError(t, json.Unmarshal([]byte(testDashboard), &model))
+			changes := r.apply(model)
+			assert.Equal(t, tc.expected, changes)
+
+			// Changes are applied to the model.
+			for _, c := range changes {
+				segments, err := parsePath(c.Path)
+				require.NoError(t, err)
+				locations := selectPath(model, segments)
+				require.Len(t, locations, 1)
+				assert.Equal(t, c.After, locations[0].get())
+			}
+		})
+	}
+}
+
+func TestCompileQuery(t *testing.T) {
+	for _, q := range []Query{
+		{},
+		{Path: "panels"},
+		{Path: "$.panels["},
+		{Path: "$.panels[-1]"},
+		{Path: "$..", Replacement: "x"},
+		{Pattern: "(unclosed"},
+	} {
+		_, err := compileQuery(q)
+		assert.ErrorIs(t, err, ErrInvalidQuery, q)
+	}
+}
@@ -0,0 +1,328 @@
+package bulkreplace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	defaultMessage = "Bulk replace"
+	listLimit      = 100
+)
+
+// Service finds and replaces values in the JSON of many dashboards at once. Replacements are saved
+// as new dashboard versions in a single transaction and can be rolled back as a whole.
+type Service struct {
+	log   log.Logger
+	db    db.DB
+	store store
+
+	accessControl           accesscontrol.AccessControl
+	features                featuremgmt.FeatureToggles
+	dashboardService        dashboards.DashboardService
+	dashboardVersionService dashver.Service
+
+	now func() time.Time
+}
+
+func ProvideService(
+	sqlStore db.DB, routeRegister routing.RouteRegister, accessControl accesscontrol.AccessControl,
+	features featuremgmt.FeatureToggles, dashboardService dashboards.DashboardService,
+	dashboardVersionService dashver.Service, orgService org.Service,
+) *Service {
+	s := &Service{
+		log:                     log.New("dashboards.bulkreplace"),
+		db:                      sqlStore,
+		store:                   &xormStore{db: sqlStore},
+		accessControl:           accessControl,
+		features:                features,
+		dashboardService:        dashboardService,
+		dashboardVersionService: dashboardVersionService,
+		now:                     time.Now,
+	}
+
+	orgService.RegisterDelete("DELETE FROM dashboard_bulk_replace_item WHERE batch_id IN (SELECT id FROM dashboard_bulk_replace WHERE org_id = ?)")
+	orgService.RegisterDelete("DELETE FROM dashboard_bulk_replace WHERE org_id = ?")
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+// planned is a dashboard with the replacements applied, not saved yet.
+type planned struct {
+	preview   *DashboardPreview
+	dashboard *dashboards.Dashboard
+}
+
+// Preview returns the changes the query makes to each dashboard, dashboards without changes are omitted.
+func (s *Service) Preview(ctx context.Context, requester identity.Requester, q Query) (*Preview, error) {
+	plan, err := s.plan(ctx, requester, q)
+	if err != nil {
+		return nil, err
+	}
+	res := &Preview{Dashboards: make([]*DashboardPreview, 0, len(plan))}
+	for _, p := range plan {
+		res.Dashboards = append(res.Dashboards, p.preview)
+	}
+	return res, nil
+}
+
+// Apply saves the changes of the query as new versions of the dashboards. Either all the dashboards are
+// saved or none, skipped dashboards are left unchanged.
+func (s *Service) Apply(ctx context.Context, requester identity.Requester, cmd ApplyCommand) (*Batch, error) {
+	userID, err := requester.GetInternalID()
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Message == "" {
+		cmd.Message = defaultMessage
+	}
+
+	plan, err := s.plan(ctx, requester, cmd.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &Batch{
+		OrgID:     requester.GetOrgID(),
+		UID:       util.GenerateShortUID(),
+		Message:   cmd.Message,
+		Query:     cmd.Query,
+		CreatedBy: userID,
+		Created:   s.now(),
+		Items:     []*BatchItem{},
+	}
+	err = s.db.InTransaction(ctx, func(ctx context.Context) error {
+		for _, p := range plan {
+			if p.preview.Skipped != "" {
+				continue
+			}
+			saved, err := s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+				OrgID:     requester.GetOrgID(),
+				User:      requester,
+				Message:   cmd.Message,
+				Dashboard: p.dashboard,
+			}, false)
+			if err != nil {
+				return fmt.Errorf("failed to save dashboard %s: %w", p.preview.DashboardUID, err)
+			}
+			batch.Items = append(batch.Items, &BatchItem{
+				DashboardUID:  p.preview.DashboardUID,
+				Title:         p.preview.Title,
+				VersionBefore: p.preview.Version,
+				VersionAfter:  saved.Version,
+				Changes:       len(p.preview.Changes),
+			})
+		}
+		if len(batch.Items) == 0 {
+			return ErrNoChanges.Errorf("no dashboard to change")
+		}
+		return s.store.Create(ctx, batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.FromContext(ctx).Info("Applied bulk replace", "uid", batch.UID, "dashboards", len(batch.Items))
+	return batch, nil
+}
+
+// Rollback restores the dashboards of a batch to their versions before the batch, as new versions. The
+// rollback is refused when any of the dashboards changed after the batch.
+func (s *Service) Rollback(ctx context.Context, requester identity.Requester, uid string) (*Batch, error) {
+	if err := s.checkStorage(); err != nil {
+		return nil, err
+	}
+	userID, err := requester.GetInternalID()
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := s.store.Get(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if batch.RolledBack != nil {
+		return nil, ErrAlreadyRolledBack.Errorf("bulk replace %s already rolled back", uid)
+	}
+
+	current := make([]*dashboards.Dashboard, len(batch.Items))
+	var changed []string
+	for i, item := range batch.Items {
+		dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: batch.OrgID, UID: item.DashboardUID})
+		if err != nil {
+			if errors.Is(err, dashboards.ErrDashboardNotFound) {
+				changed = append(changed, item.DashboardUID)
+				continue
+			}
+			return nil, err
+		}
+		if dash.Version != item.VersionAfter {
+			changed = append(changed, item.DashboardUID)
+		}
+		current[i] = dash
+	}
+	if len(changed) > 0 {
+		err := ErrRollbackConflict.Errorf("dashboards changed or deleted since the bulk replace: %s", strings.Join(changed, ", "))
+		err.PublicPayload = map[string]any{"dashboardUids": changed}
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Rollback of bulk replace %s", batch.UID)
+	err = s.db.InTransaction(ctx, func(ctx context.Context) error {
+		for i, item := range batch.Items {
+			dash := current[i]
+			version, err := s.dashboardVersionService.Get(ctx, &dashver.GetDashboardVersionQuery{
+				DashboardID:  dash.ID,
+				DashboardUID: dash.UID,
+				OrgID:        batch.OrgID,
+				Version:      int64(item.VersionBefore),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get version %d of dashboard %s: %w", item.VersionBefore, dash.UID, err)
+			}
+
+			version.Data.Set("id", dash.ID)
+			version.Data.Set("uid", dash.UID)
+			version.Data.Set("version", dash.Version)
+			_, err = s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+				OrgID:     batch.OrgID,
+				User:      requester,
+				Message:   message,
+				Dashboard: newDashboard(dash, version.Data, userID),
+			}, false)
+			if err != nil {
+				return fmt.Errorf("failed to restore dashboard %s: %w", dash.UID, err)
+			}
+		}
+		return s.store.MarkRolledBack(ctx, batch, userID, s.now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (s *Service) Get(ctx context.Context, orgID int64, uid string) (*Batch, error) {
+	return s.store.Get(ctx, orgID, uid)
+}
+
+// List returns the latest batches of an organization, without their items.
+func (s *Service) List(ctx context.Context, orgID int64) ([]*Batch, error) {
+	return s.store.List(ctx, orgID, listLimit)
+}
+
+func (s *Service) plan(ctx context.Context, requester identity.Requester, q Query) ([]*planned, error) {
+	if err := s.checkStorage(); err != nil {
+		return nil, err
+	}
+	r, err := compileQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := requester.GetInternalID()
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.dashboardService.GetAllDashboardsByOrgId(ctx, requester.GetOrgID())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].UID < all[j].UID })
+
+	plan := make([]*planned, 0)
+	for _, dash := range all {
+		if dash.IsFolder || dash.Data == nil {
+			continue
+		}
+		if len(q.DashboardUIDs) > 0 && !slices.Contains(q.DashboardUIDs, dash.UID) {
+			continue
+		}
+		if len(q.FolderUIDs) > 0 && !containsFolder(q.FolderUIDs, dash.FolderUID) {
+			continue
+		}
+
+		data, err := copyJSON(dash.Data)
+		if err != nil {
+			return nil, err
+		}
+		changes := r.apply(data.Interface())
+		if len(changes) == 0 {
+			continue
+		}
+
+		p := &planned{
+			preview: &DashboardPreview{
+				DashboardUID: dash.UID,
+				Title:        dash.Title,
+				FolderUID:    dash.FolderUID,
+				Version:      dash.Version,
+				Changes:      changes,
+			},
+			dashboard: newDashboard(dash, data, userID),
+		}
+		// The save is validated on a copy, validation updates the dashboard.
+		validated := newDashboard(dash, data, userID)
+		validated.Data, err = copyJSON(data)
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.dashboardService.BuildSaveDashboardCommand(ctx, &dashboards.SaveDashboardDTO{
+			OrgID:     requester.GetOrgID(),
+			User:      requester,
+			Dashboard: validated,
+		}, true)
+		if err != nil {
+			p.preview.Skipped = err.Error()
+		}
+		plan = append(plan, p)
+	}
+	return plan, nil
+}
+
+func (s *Service) checkStorage() error {
+	// Dashboards stored in unified storage can't be saved in a single SQL transaction.
+	if s.features.IsEnabledGlobally(featuremgmt.FlagKubernetesCliDashboards) {
+		return ErrUnsupportedStorage.Errorf("bulk replace isn't supported with unified storage")
+	}
+	return nil
+}
+
+// newDashboard returns the dashboard to save with the data of an existing dashboard.
+func newDashboard(dash *dashboards.Dashboard, data *simplejson.Json, userID int64) *dashboards.Dashboard {
+	cmd := dashboards.SaveDashboardCommand{
+		Dashboard: data,
+		OrgID:     dash.OrgID,
+		UserID:    userID,
+		FolderID:  dash.FolderID, // nolint:staticcheck
+		FolderUID: dash.FolderUID,
+	}
+	return cmd.GetDashboardModel()
+}
+
+func copyJSON(data *simplejson.Json) (*simplejson.Json, error) {
+	raw, err := data.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return simplejson.NewJson(raw)
+}
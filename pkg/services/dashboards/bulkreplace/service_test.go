@@ -0,0 +1,185 @@
+package bulkreplace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationBulkReplace(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	admin := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin}
+	dashboardService := newFakeDashboardService(t,
+		`{"id": 1, "uid": "checkout", "version": 1, "title": "Checkout", "panels": [{"datasource": {"uid": "prometheus-old"}}]}`,
+		`{"id": 2, "uid": "payments", "version": 4, "title": "Payments", "panels": [{"targets": [{"datasource": {"uid": "prometheus-old"}}]}]}`,
+		`{"id": 3, "uid": "infra", "version": 1, "title": "Infra", "panels": [{"datasource": {"uid": "prometheus-old"}}]}`,
+		`{"id": 4, "uid": "logs", "version": 1, "title": "Logs", "panels": [{"datasource": {"uid": "loki"}}]}`,
+	)
+	dashboardService.provisioned["infra"] = true
+	versionService := dashvertest.NewDashboardVersionServiceFake()
+	s := setupTestService(t, dashboardService, versionService)
+	q := Query{Path: "$..datasource.uid", Pattern: "^prometheus-old$", Replacement: "prometheus-new"}
+
+	preview, err := s.Preview(ctx, admin, q)
+	require.NoError(t, err)
+	require.Len(t, preview.Dashboards, 3)
+	assert.Equal(t, "checkout", preview.Dashboards[0].DashboardUID)
+	assert.Equal(t, []Change{{Path: "$.panels[0].datasource.uid", Before: "prometheus-old", After: "prometheus-new"}}, preview.Dashboards[0].Changes)
+	assert.Equal(t, "infra", preview.Dashboards[1].DashboardUID)
+	assert.NotEmpty(t, preview.Dashboards[1].Skipped)
+	assert.Equal(t, "payments", preview.Dashboards[2].DashboardUID)
+	assert.Empty(t, dashboardService.saved, "previews don't save dashboards")
+
+	t.Run("a failed save rolls back the whole batch", func(t *testing.T) {
+		dashboardService.failUID = "checkout"
+		_, err := s.Apply(ctx, admin, ApplyCommand{Query: q})
+		require.Error(t, err)
+		dashboardService.failUID = ""
+
+		batches, err := s.List(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, batches)
+	})
+
+	batch, err := s.Apply(ctx, admin, ApplyCommand{Query: q, Message: "Migrate to the new Prometheus"})
+	require.NoError(t, err)
+	assert.Equal(t, []*BatchItem{
+		{ID: 1, BatchID: batch.ID, DashboardUID: "checkout", Title: "Checkout", VersionBefore: 1, VersionAfter: 2, Changes: 1},
+		{ID: 2, BatchID: batch.ID, DashboardUID: "payments", Title: "Payments", VersionBefore: 4, VersionAfter: 5, Changes: 1},
+	}, batch.Items)
+	assert.Equal(t, "prometheus-new", dashboardService.dashboards["checkout"].Data.GetPath("panels").GetIndex(0).GetPath("datasource", "uid").MustString())
+	assert.Equal(t, "Migrate to the new Prometheus", dashboardService.messages["payments"])
+
+	stored, err := s.Get(ctx, 1, batch.UID)
+	require.NoError(t, err)
+	assert.Equal(t, q, stored.Query)
+	assert.Len(t, stored.Items, 2)
+
+	t.Run("rollback is refused when a dashboard changed since the batch", func(t *testing.T) {
+		dashboardService.dashboards["payments"].Version++
+		_, err := s.Rollback(ctx, admin, batch.UID)
+		assert.ErrorIs(t, err, ErrRollbackConflict)
+		dashboardService.dashboards["payments"].Version--
+	})
+
+	versionService.ExpectedDashboardVersions = []*dashver.DashboardVersionDTO{
+		{Version: 1, Data: simplejson.MustJson([]byte(`{"title": "Checkout", "panels": [{"datasource": {"uid": "prometheus-old"}}]}`))},
+		{Version: 4, Data: simplejson.MustJson([]byte(`{"title": "Payments", "panels": [{"targets": [{"datasource": {"uid": "prometheus-old"}}]}]}`))},
+	}
+	rolledBack, err := s.Rollback(ctx, admin, batch.UID)
+	require.NoError(t, err)
+	require.NotNil(t, rolledBack.RolledBack)
+	assert.Equal(t, "prometheus-old", dashboardService.dashboards["checkout"].Data.GetPath("panels").GetIndex(0).GetPath("datasource", "uid").MustString())
+	assert.Equal(t, 3, dashboardService.dashboards["checkout"].Version)
+	assert.Equal(t, "Rollback of bulk replace "+batch.UID, dashboardService.messages["checkout"])
+
+	_, err = s.Rollback(ctx, admin, batch.UID)
+	assert.ErrorIs(t, err, ErrAlreadyRolledBack)
+}
+
+func setupTestService(t *testing.T, dashboardService dashboards.DashboardService, versionService dashver.Service) *Service {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	return &Service{
+		log:                     log.NewNopLogger(),
+		db:                      sqlStore,
+		store:                   &xormStore{db: sqlStore},
+		features:                featuremgmt.WithFeatures(),
+		dashboardService:        dashboardService,
+		dashboardVersionService: versionService,
+		now:                     time.Now,
+	}
+}
+
+// fakeDashboardService keeps the dashboards in memory and saves them like the dashboard store, checking
+// their version.
+type fakeDashboardService struct {
+	dashboards.DashboardService
+
+	dashboards  map[string]*dashboards.Dashboard
+	provisioned map[string]bool
+	messages    map[string]string
+	saved       []string
+	failUID     string
+}
+
+func newFakeDashboardService(t *testing.T, models ...string) *fakeDashboardService {
+	t.Helper()
+	f := &fakeDashboardService{dashboards: map[string]*dashboards.Dashboard{}, provisioned: map[string]bool{}, messages: map[string]string{}}
+	for _, m := range models {
+		data, err := simplejson.NewJson([]byte(m))
+		require.NoError(t, err)
+		dash := dashboards.NewDashboardFromJson(data)
+		dash.OrgID = 1
+		f.dashboards[dash.UID] = dash
+	}
+	return f
+}
+
+func (f *fakeDashboardService) GetAllDashboardsByOrgId(_ context.Context, orgID int64) ([]*dashboards.Dashboard, error) {
+	res := make([]*dashboards.Dashboard, 0, len(f.dashboards))
+	for _, d := range f.dashboards {
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+func (f *fakeDashboardService) GetDashboard(_ context.Context, q *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+	if d, ok := f.dashboards[q.UID]; ok {
+		return d, nil
+	}
+	return nil, dashboards.ErrDashboardNotFound
+}
+
+func (f *fakeDashboardService) BuildSaveDashboardCommand(_ context.Context, dto *dashboards.SaveDashboardDTO, validateProvisionedDashboard bool) (*dashboards.SaveDashboardCommand, error) {
+	if validateProvisionedDashboard && f.provisioned[dto.Dashboard.UID] {
+		return nil, dashboards.ErrDashboardCannotSaveProvisionedDashboard
+	}
+	current, ok := f.dashboards[dto.Dashboard.UID]
+	if !ok {
+		return nil, dashboards.ErrDashboardNotFound
+	}
+	if current.Version != dto.Dashboard.Version {
+		return nil, dashboards.ErrDashboardVersionMismatch
+	}
+	return &dashboards.SaveDashboardCommand{Dashboard: dto.Dashboard.Data}, nil
+}
+
+func (f *fakeDashboardService) SaveDashboard(ctx context.Context, dto *dashboards.SaveDashboardDTO, allowUiUpdate bool) (*dashboards.Dashboard, error) {
+	if _, err := f.BuildSaveDashboardCommand(ctx, dto, !allowUiUpdate); err != nil {
+		return nil, err
+	}
+	if dto.Dashboard.UID == f.failUID {
+		return nil, errors.New("failed to save")
+	}
+	dash := dto.Dashboard
+	dash.Version++
+	dash.Data.Set("version", dash.Version)
+	f.dashboards[dash.UID] = dash
+	f.messages[dash.UID] = dto.Message
+	f.saved = append(f.saved, dash.UID)
+	return dash, nil
+}
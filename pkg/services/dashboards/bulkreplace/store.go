@@ -0,0 +1,92 @@
+package bulkreplace
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store interface {
+	Create(ctx context.Context, batch *Batch) error
+	Get(ctx context.Context, orgID int64, uid string) (*Batch, error)
+	List(ctx context.Context, orgID int64, limit int) ([]*Batch, error)
+	MarkRolledBack(ctx context.Context, batch *Batch, userID int64, at time.Time) error
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) Create(ctx context.Context, batch *Batch) error {
+	raw, err := json.Marshal(batch.Query)
+	if err != nil {
+		return err
+	}
+	batch.RawQuery = string(raw)
+
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(batch); err != nil {
+			return err
+		}
+		for _, item := range batch.Items {
+			item.BatchID = batch.ID
+			if _, err := sess.Insert(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *xormStore) Get(ctx context.Context, orgID int64, uid string) (*Batch, error) {
+	batch := &Batch{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(batch)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrBatchNotFound.Errorf("bulk replace %s not found", uid)
+		}
+		batch.Items = make([]*BatchItem, 0)
+		return sess.Where("batch_id = ?", batch.ID).Asc("id").Find(&batch.Items)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batch, json.Unmarshal([]byte(batch.RawQuery), &batch.Query)
+}
+
+func (s *xormStore) List(ctx context.Context, orgID int64, limit int) ([]*Batch, error) {
+	batches := make([]*Batch, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Desc("id").Limit(limit).Find(&batches)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range batches {
+		if err := json.Unmarshal([]byte(b.RawQuery), &b.Query); err != nil {
+			return nil, err
+		}
+	}
+	return batches, nil
+}
+
+func (s *xormStore) MarkRolledBack(ctx context.Context, batch *Batch, userID int64, at time.Time) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Table(&Batch{}).Where("id = ? AND rolled_back IS NULL", batch.ID).
+			Update(map[string]any{"rolled_back": at, "rolled_back_by": userID})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrAlreadyRolledBack.Errorf("bulk replace %s already rolled back", batch.UID)
+		}
+		batch.RolledBack = &at
+		batch.RolledBackBy = userID
+		return nil
+	})
+}
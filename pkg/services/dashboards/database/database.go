@@ -669,6 +669,8 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 			sqlStatements = append(sqlStatements, statement{SQL: "UPDATE dashboard SET deleted = ? WHERE org_id = ? AND folder_uid = ? AND is_folder = ? ", args: []any{time.Now(), dashboard.OrgID, dashboard.UID, d.store.GetDialect().BooleanStr(false)}})
 		}
 
+		sqlStatements = append(sqlStatements, statement{SQL: "DELETE FROM dashboard_version_retention WHERE org_id = ? AND folder_uid = ?", args: []any{dashboard.OrgID, dashboard.UID}})
+
 		// remove all access control permission with folder scope
 		err := d.deleteResourcePermissions(sess, dashboard.OrgID, dashboards.ScopeFoldersProvider.GetResourceScopeUID(dashboard.UID))
 		if err != nil {
@@ -430,6 +430,28 @@ func TestIntegrationDashboardDataAccess(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("Should delete the version retention policy of a deleted folder", func(t *testing.T) {
+		setup()
+		emptyFolder := insertTestDashboard(t, dashboardStore, "2 test dash folder", 1, 0, "", true, "prod", "webapp")
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("INSERT INTO dashboard_version_retention (org_id, folder_uid, keep_versions, keep_days, keep_tags, created, updated) VALUES (?, ?, ?, ?, ?, ?, ?)",
+				emptyFolder.OrgID, emptyFolder.UID, 5, 0, "[]", time.Now(), time.Now())
+			return err
+		})
+		require.NoError(t, err)
+
+		err = dashboardStore.DeleteDashboard(context.Background(), &dashboards.DeleteDashboardCommand{ID: emptyFolder.ID})
+		require.NoError(t, err)
+
+		var count int64
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			count, err = sess.Table("dashboard_version_retention").Where("org_id = ? AND folder_uid = ?", emptyFolder.OrgID, emptyFolder.UID).Count()
+			return err
+		})
+		require.NoError(t, err)
+		require.Zero(t, count)
+	})
+
 	t.Run("Should return error if no dashboard is found for update when dashboard id is greater than zero", func(t *testing.T) {
 		cmd := dashboards.SaveDashboardCommand{
 			OrgID:     1,
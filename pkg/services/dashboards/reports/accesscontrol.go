@@ -0,0 +1,67 @@
+package reports
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	ScopeReportsRoot = "reports"
+
+	ActionReportsCreate = "reports:create"
+	ActionReportsRead   = "reports:read"
+	ActionReportsWrite  = "reports:write"
+	ActionReportsDelete = "reports:delete"
+	ActionReportsSend   = "reports:send"
+)
+
+var (
+	ScopeReportsProvider = accesscontrol.NewScopeProvider(ScopeReportsRoot)
+
+	ScopeReportsAll = ScopeReportsProvider.GetResourceAllScope()
+)
+
+var (
+	reportsReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:reports:reader",
+		DisplayName: "Report reader",
+		Description: "List and view the reports of the dashboards you can view.",
+		Group:       "Reports",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionReportsRead, Scope: ScopeReportsAll},
+		},
+	}
+
+	reportsCreatorRole = accesscontrol.RoleDTO{
+		Name:        "fixed:reports:creator",
+		DisplayName: "Report creator",
+		Description: "Create reports, and update, delete and send the reports you own.",
+		Group:       "Reports",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionReportsRead, Scope: ScopeReportsAll},
+			{Action: ActionReportsCreate},
+		},
+	}
+
+	reportsWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:reports:writer",
+		DisplayName: "Report writer",
+		Description: "Create, update, delete and send all reports.",
+		Group:       "Reports",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionReportsRead, Scope: ScopeReportsAll},
+			{Action: ActionReportsCreate},
+			{Action: ActionReportsWrite, Scope: ScopeReportsAll},
+			{Action: ActionReportsDelete, Scope: ScopeReportsAll},
+			{Action: ActionReportsSend, Scope: ScopeReportsAll},
+		},
+	}
+)
+
+func declareFixedRoles(service accesscontrol.Service) error {
+	return service.DeclareFixedRoles(
+		accesscontrol.RoleRegistration{Role: reportsReaderRole, Grants: []string{string(org.RoleViewer)}},
+		accesscontrol.RoleRegistration{Role: reportsCreatorRole, Grants: []string{string(org.RoleEditor)}},
+		accesscontrol.RoleRegistration{Role: reportsWriterRole, Grants: []string{string(org.RoleAdmin)}},
+	)
+}
@@ -0,0 +1,97 @@
+package reports
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+	uidScope := ScopeReportsProvider.GetResourceScopeUID(accesscontrol.Parameter(":uid"))
+	// Owners can change their reports with the permission to create reports, the service checks the
+	// ownership or the permission on the report.
+	canChange := func(action string) web.Handler {
+		return authorize(accesscontrol.EvalAny(
+			accesscontrol.EvalPermission(action, uidScope),
+			accesscontrol.EvalPermission(ActionReportsCreate),
+		))
+	}
+
+	routeRegister.Group("/api/dashboards/reports", func(r routing.RouteRegister) {
+		r.Get("/", authorize(accesscontrol.EvalPermission(ActionReportsRead)), routing.Wrap(s.listHandler))
+		r.Post("/", authorize(accesscontrol.EvalPermission(ActionReportsCreate)), routing.Wrap(s.createHandler))
+		r.Get("/:uid", authorize(accesscontrol.EvalPermission(ActionReportsRead, uidScope)), routing.Wrap(s.getHandler))
+		r.Put("/:uid", canChange(ActionReportsWrite), routing.Wrap(s.updateHandler))
+		r.Delete("/:uid", canChange(ActionReportsDelete), routing.Wrap(s.deleteHandler))
+		r.Post("/:uid/send", canChange(ActionReportsSend), routing.Wrap(s.sendHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// GET /api/dashboards/reports
+func (s *Service) listHandler(c *contextmodel.ReqContext) response.Response {
+	reports, err := s.List(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list reports", err)
+	}
+	return response.JSON(http.StatusOK, reports)
+}
+
+// POST /api/dashboards/reports
+func (s *Service) createHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := SaveReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	report, err := s.Create(c.Req.Context(), c.SignedInUser, cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// GET /api/dashboards/reports/:uid
+func (s *Service) getHandler(c *contextmodel.ReqContext) response.Response {
+	report, err := s.Get(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// PUT /api/dashboards/reports/:uid
+func (s *Service) updateHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := SaveReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	report, err := s.Update(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// DELETE /api/dashboards/reports/:uid
+func (s *Service) deleteHandler(c *contextmodel.ReqContext) response.Response {
+	if err := s.Delete(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete report", err)
+	}
+	return response.Success("Report deleted")
+}
+
+// POST /api/dashboards/reports/:uid/send
+func (s *Service) sendHandler(c *contextmodel.ReqContext) response.Response {
+	report, err := s.Send(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to send report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
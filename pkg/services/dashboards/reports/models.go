@@ -0,0 +1,93 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrReportNotFound  = errutil.NotFound("dashboards.report-not-found", errutil.WithPublicMessage("Report not found"))
+	ErrInvalidReport   = errutil.BadRequest("dashboards.report-invalid")
+	ErrForbidden       = errutil.Forbidden("dashboards.report-forbidden", errutil.WithPublicMessage("You are not allowed to view the dashboard of the report"))
+	ErrReportForbidden = errutil.Forbidden("dashboards.report-change-forbidden", errutil.WithPublicMessage("Only the owner of the report and users with the permission to change it can do this"))
+	ErrOwnerForbidden  = errutil.Forbidden("dashboards.report-owner-forbidden", errutil.WithPublicMessage("The owner of the report is not allowed to view its dashboard"))
+)
+
+// Format is the way the dashboard is rendered in the emails of a report.
+type Format string
+
+const (
+	// FormatPDF attaches the dashboard rendered to a PDF document.
+	FormatPDF Format = "pdf"
+	// FormatPNG attaches the dashboard rendered to a PNG image.
+	FormatPNG Format = "png"
+	// FormatImage embeds the dashboard rendered to a PNG image in the body of the emails.
+	FormatImage Format = "image"
+)
+
+// TimeRange is the time range the dashboard is rendered with, relative times such as `now-7d`
+// are resolved when the report is sent.
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Report is a dashboard rendered and sent by email on a schedule. Reports are rendered with the
+// permissions of their owner, the user who created them.
+type Report struct {
+	ID           int64  `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID        int64  `json:"orgId" xorm:"org_id"`
+	UID          string `json:"uid" xorm:"uid"`
+	Name         string `json:"name" xorm:"name"`
+	DashboardUID string `json:"dashboardUid" xorm:"dashboard_uid"`
+	// Schedule is a cron expression with five fields, such as `0 8 * * 1` for every Monday at 8:00.
+	Schedule string `json:"schedule" xorm:"schedule"`
+	// Timezone is the IANA time zone of the schedule and of the rendered dashboard, UTC when empty.
+	Timezone   string   `json:"timezone" xorm:"timezone"`
+	Recipients []string `json:"recipients" xorm:"-"`
+	// ReplyTo is the address the recipients reply to, the owner can be set to receive the answers.
+	ReplyTo   string              `json:"replyTo,omitempty" xorm:"reply_to"`
+	Message   string              `json:"message,omitempty" xorm:"message"`
+	TimeRange TimeRange           `json:"timeRange" xorm:"-"`
+	Variables map[string][]string `json:"variables,omitempty" xorm:"-"`
+	Formats   []Format            `json:"formats" xorm:"-"`
+	Enabled   bool                `json:"enabled" xorm:"enabled"`
+	// RawOptions stores the recipients, time range, variables and formats.
+	RawOptions string    `json:"-" xorm:"options"`
+	OwnerID    int64     `json:"ownerId" xorm:"owner_id"`
+	Created    time.Time `json:"created" xorm:"'created'"`
+	Updated    time.Time `json:"updated" xorm:"'updated'"`
+	// NextRun is the next time the report is sent in epoch milliseconds, the instance sending the
+	// report claims the run by moving it forward.
+	NextRun   int64      `json:"nextRun,omitempty" xorm:"next_run"`
+	LastRun   *time.Time `json:"lastRun,omitempty" xorm:"last_run"`
+	LastError string     `json:"lastError,omitempty" xorm:"last_error"`
+}
+
+func (Report) TableName() string {
+	return "dashboard_report"
+}
+
+// options are the settings of a report stored as JSON.
+type options struct {
+	Recipients []string            `json:"recipients"`
+	TimeRange  TimeRange           `json:"timeRange"`
+	Variables  map[string][]string `json:"variables,omitempty"`
+	Formats    []Format            `json:"formats"`
+}
+
+type SaveReportCommand struct {
+	Name         string              `json:"name"`
+	DashboardUID string              `json:"dashboardUid"`
+	Schedule     string              `json:"schedule"`
+	Timezone     string              `json:"timezone"`
+	Recipients   []string            `json:"recipients"`
+	ReplyTo      string              `json:"replyTo"`
+	Message      string              `json:"message"`
+	TimeRange    TimeRange           `json:"timeRange"`
+	Variables    map[string][]string `json:"variables"`
+	// Formats defaults to an image embedded in the emails.
+	Formats []Format `json:"formats"`
+	Enabled bool     `json:"enabled"`
+}
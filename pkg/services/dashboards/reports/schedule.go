@@ -0,0 +1,31 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// nextRun returns the first time after t the report is due, in the time zone of the report.
+func nextRun(report *Report, t time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(report.Schedule)
+	if err != nil {
+		return time.Time{}, ErrInvalidReport.Errorf("invalid schedule %q: %w", report.Schedule, err)
+	}
+	loc, err := location(report.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(t.In(loc)).UTC(), nil
+}
+
+func location(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, ErrInvalidReport.Errorf("invalid timezone %q", timezone)
+	}
+	return loc, nil
+}
@@ -0,0 +1,54 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRun(t *testing.T) {
+	// Sunday 2 February 2025, 10:30 UTC
+	now := time.Date(2025, time.February, 2, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		report   Report
+		expected time.Time
+	}{
+		{
+			desc:     "schedules are in UTC by default",
+			report:   Report{Schedule: "0 8 * * 1"},
+			expected: time.Date(2025, time.February, 3, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "schedules are in the time zone of the report",
+			report:   Report{Schedule: "0 8 * * 1", Timezone: "America/New_York"},
+			expected: time.Date(2025, time.February, 3, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "the next run is strictly after the time",
+			report:   Report{Schedule: "30 10 * * *"},
+			expected: time.Date(2025, time.February, 3, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			desc:     "descriptors are supported",
+			report:   Report{Schedule: "@monthly"},
+			expected: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			next, err := nextRun(&tc.report, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, next)
+		})
+	}
+
+	for _, r := range []Report{{Schedule: "every monday"}, {Schedule: "0 8 * * 1", Timezone: "Mars/Olympus"}} {
+		_, err := nextRun(&r, now)
+		assert.ErrorIs(t, err, ErrInvalidReport)
+	}
+}
@@ -0,0 +1,221 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const emailTemplate = "dashboard_report"
+
+// sendDue sends the reports whose next run has passed. Each run is claimed before the report is sent
+// so that a report is sent once when several instances share the database.
+func (s *Service) sendDue(ctx context.Context) {
+	now := s.now()
+	reports, err := s.store.ListDue(ctx, now)
+	if err != nil {
+		s.log.Error("Failed to list the due reports", "error", err)
+		return
+	}
+
+	for _, report := range reports {
+		next, err := nextRun(report, now)
+		if err != nil {
+			// The schedule can't be fixed without changing the report, don't look at it again until then.
+			s.log.Error("Disabling report with an invalid schedule", "org", report.OrgID, "report", report.UID, "error", err)
+			if err := s.store.Disable(ctx, report, err.Error()); err != nil {
+				s.log.Error("Failed to disable the report", "org", report.OrgID, "report", report.UID, "error", err)
+			}
+			continue
+		}
+		claimed, err := s.store.Claim(ctx, report, next)
+		if err != nil {
+			s.log.Error("Failed to claim the report", "org", report.OrgID, "report", report.UID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.run(ctx, report); err != nil {
+			s.log.Warn("Failed to send the report", "org", report.OrgID, "report", report.UID, "error", err)
+		}
+	}
+}
+
+// run sends a report and records the outcome on it.
+func (s *Service) run(ctx context.Context, report *Report) error {
+	err := s.send(ctx, report)
+
+	now := s.now()
+	report.LastRun = &now
+	report.LastError = ""
+	if err != nil {
+		report.LastError = err.Error()
+	}
+	if err := s.store.SetResult(ctx, report); err != nil {
+		s.log.Error("Failed to save the outcome of the report", "org", report.OrgID, "report", report.UID, "error", err)
+	}
+	return err
+}
+
+// send renders the dashboard of the report with the permissions of its owner and emails it to the recipients.
+func (s *Service) send(ctx context.Context, report *Report) error {
+	owner, err := s.owner(ctx, report)
+	if err != nil {
+		return err
+	}
+	ok, err := s.canView(ctx, owner, report.DashboardUID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrOwnerForbidden.Errorf("owner of report %s can't view dashboard %s", report.UID, report.DashboardUID)
+	}
+	// Scheduled reports are sent in the background, get the dashboard as the owner.
+	ctx = identity.WithRequester(ctx, owner)
+
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: report.OrgID, UID: report.DashboardUID})
+	if err != nil {
+		return err
+	}
+
+	cmd := &notifications.SendEmailCommandSync{
+		SendEmailCommand: notifications.SendEmailCommand{
+			To:       report.Recipients,
+			Template: emailTemplate,
+			Data: map[string]any{
+				"Name":           report.Name,
+				"Message":        report.Message,
+				"DashboardTitle": dashboard.Title,
+				"DashboardURL":   s.cfg.AppURL + dashboardPath(report, dashboard),
+			},
+			AttachedFiles: []*notifications.SendEmailAttachFile{},
+		},
+	}
+	if report.ReplyTo != "" {
+		cmd.ReplyTo = []string{report.ReplyTo}
+	}
+
+	for _, format := range report.Formats {
+		renderType := rendering.RenderPNG
+		if format == FormatPDF {
+			renderType = rendering.RenderPDF
+		}
+		filePath, err := s.render(ctx, owner, report, dashboard, renderType)
+		if err != nil {
+			return err
+		}
+
+		if format == FormatImage {
+			cmd.EmbeddedFiles = append(cmd.EmbeddedFiles, filePath)
+			cmd.Data["EmbeddedImage"] = filepath.Base(filePath)
+			continue
+		}
+		content, err := os.ReadFile(filepath.Clean(filePath))
+		if err != nil {
+			return err
+		}
+		cmd.AttachedFiles = append(cmd.AttachedFiles, &notifications.SendEmailAttachFile{
+			Name:    fmt.Sprintf("%s.%s", dashboard.Slug, format),
+			Content: content,
+		})
+	}
+
+	return s.emailSender.SendEmailCommandHandlerSync(ctx, cmd)
+}
+
+// owner returns the owner of the report with their permissions in the organization of the report.
+func (s *Service) owner(ctx context.Context, report *Report) (*user.SignedInUser, error) {
+	owner, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: report.OwnerID, OrgID: report.OrgID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the owner of report %s: %w", report.UID, err)
+	}
+	if owner.IsDisabled {
+		return nil, ErrOwnerForbidden.Errorf("owner of report %s is disabled", report.UID)
+	}
+
+	permissions, err := s.acService.GetUserPermissions(ctx, owner, accesscontrol.Options{ReloadCache: false})
+	if err != nil {
+		return nil, err
+	}
+	if owner.Permissions == nil {
+		owner.Permissions = map[int64]map[string][]string{}
+	}
+	owner.Permissions[report.OrgID] = accesscontrol.GroupScopesByActionContext(ctx, permissions)
+	return owner, nil
+}
+
+// render renders the whole dashboard as the owner and returns the path of the rendered file.
+func (s *Service) render(ctx context.Context, owner *user.SignedInUser, report *Report, dashboard *dashboards.Dashboard, renderType rendering.RenderType) (string, error) {
+	renderPath := dashboardPath(report, dashboard) + "&kiosk"
+
+	height := s.cfg.RendererDefaultImageHeight
+	if s.renderService.IsCapabilitySupported(ctx, rendering.FullHeightImages) == nil {
+		height = -1
+	}
+
+	result, err := s.renderService.Render(ctx, renderType, rendering.Opts{
+		CommonOpts: rendering.CommonOpts{
+			AuthOpts: rendering.AuthOpts{
+				OrgID:   report.OrgID,
+				UserID:  owner.UserID,
+				OrgRole: owner.OrgRole,
+			},
+			TimeoutOpts: rendering.TimeoutOpts{
+				Timeout: s.cfg.DashboardReports.RenderTimeout,
+			},
+			Path:            renderPath,
+			Timezone:        report.Timezone,
+			ConcurrentLimit: s.cfg.RendererConcurrentRequestLimit,
+		},
+		ErrorOpts: rendering.ErrorOpts{
+			ErrorConcurrentLimitReached: true,
+			ErrorRenderUnavailable:      true,
+		},
+		Width:             s.cfg.RendererDefaultImageWidth,
+		Height:            height,
+		DeviceScaleFactor: s.cfg.RendererDefaultImageScale,
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to render dashboard %s: %w", dashboard.UID, err)
+	}
+	return result.FilePath, nil
+}
+
+// dashboardPath returns the path of the dashboard with the time range and the variables of the report.
+func dashboardPath(report *Report, dashboard *dashboards.Dashboard) string {
+	params := url.Values{}
+	params.Add("orgId", strconv.FormatInt(report.OrgID, 10))
+	if report.TimeRange.From != "" {
+		params.Add("from", report.TimeRange.From)
+		params.Add("to", report.TimeRange.To)
+	}
+	if report.Timezone != "" {
+		params.Add("timezone", report.Timezone)
+	}
+
+	names := make([]string, 0, len(report.Variables))
+	for name := range report.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range report.Variables[name] {
+			params.Add("var-"+name, value)
+		}
+	}
+
+	return path.Join("d", dashboard.UID, dashboard.Slug) + "?" + params.Encode()
+}
@@ -0,0 +1,296 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	maxNameLength = 190
+	// tick is how often due reports are looked for, schedules have a granularity of a minute.
+	tick = 15 * time.Second
+)
+
+// Service sends dashboards rendered by the image renderer by email on cron schedules.
+type Service struct {
+	cfg   *setting.Cfg
+	log   log.Logger
+	store store
+
+	accessControl    accesscontrol.AccessControl
+	acService        accesscontrol.Service
+	features         featuremgmt.FeatureToggles
+	dashboardService dashboards.DashboardService
+	userService      user.Service
+	renderService    rendering.Service
+	emailSender      notifications.EmailSender
+
+	now func() time.Time
+}
+
+func ProvideService(
+	cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, accessControl accesscontrol.AccessControl,
+	acService accesscontrol.Service, features featuremgmt.FeatureToggles, dashboardService dashboards.DashboardService,
+	userService user.Service, renderService rendering.Service, emailSender notifications.EmailSender, orgService org.Service,
+) (*Service, error) {
+	s := &Service{
+		cfg:              cfg,
+		log:              log.New("dashboards.reports"),
+		store:            &xormStore{db: sqlStore},
+		accessControl:    accessControl,
+		acService:        acService,
+		features:         features,
+		dashboardService: dashboardService,
+		userService:      userService,
+		renderService:    renderService,
+		emailSender:      emailSender,
+		now:              time.Now,
+	}
+
+	orgService.RegisterDelete("DELETE FROM dashboard_report WHERE org_id = ?")
+
+	if !cfg.DashboardReports.Enabled {
+		return s, nil
+	}
+	if err := declareFixedRoles(acService); err != nil {
+		return nil, err
+	}
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.DashboardReports.Enabled
+}
+
+// Run sends the due reports until the context is canceled.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.sendDue(ctx)
+		}
+	}
+}
+
+// List returns the reports of the organization the requester can read and whose dashboard they can view.
+func (s *Service) List(ctx context.Context, requester identity.Requester) ([]*Report, error) {
+	reports, err := s.store.List(ctx, requester.GetOrgID())
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]*Report, 0, len(reports))
+	for _, r := range reports {
+		ok, err := s.canRead(ctx, requester, r)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visible = append(visible, r)
+		}
+	}
+	return visible, nil
+}
+
+// Get returns a report, reports the requester can't read are reported as not found.
+func (s *Service) Get(ctx context.Context, requester identity.Requester, uid string) (*Report, error) {
+	report, err := s.store.Get(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.canRead(ctx, requester, report)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReportNotFound.Errorf("report %s not found", uid)
+	}
+	return report, nil
+}
+
+// Create saves a report owned by the requester.
+func (s *Service) Create(ctx context.Context, requester identity.Requester, cmd SaveReportCommand) (*Report, error) {
+	userID, err := requester.GetInternalID()
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{
+		OrgID:   requester.GetOrgID(),
+		UID:     util.GenerateShortUID(),
+		OwnerID: userID,
+		Created: s.now(),
+	}
+	if err := s.apply(ctx, requester, report, cmd); err != nil {
+		return nil, err
+	}
+	if err := s.store.Create(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Update replaces the settings of a report, the report keeps its owner.
+func (s *Service) Update(ctx context.Context, requester identity.Requester, uid string, cmd SaveReportCommand) (*Report, error) {
+	report, err := s.getForChange(ctx, requester, uid, ActionReportsWrite)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, requester, report, cmd); err != nil {
+		return nil, err
+	}
+	if err := s.store.Update(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Service) Delete(ctx context.Context, requester identity.Requester, uid string) error {
+	if _, err := s.getForChange(ctx, requester, uid, ActionReportsDelete); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, requester.GetOrgID(), uid)
+}
+
+// Send sends a report right away, regardless of its schedule.
+func (s *Service) Send(ctx context.Context, requester identity.Requester, uid string) (*Report, error) {
+	report, err := s.getForChange(ctx, requester, uid, ActionReportsSend)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.run(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// apply validates the command and sets it on the report.
+func (s *Service) apply(ctx context.Context, requester identity.Requester, report *Report, cmd SaveReportCommand) error {
+	cmd.Name = strings.TrimSpace(cmd.Name)
+	if cmd.Name == "" || len(cmd.Name) > maxNameLength {
+		return ErrInvalidReport.Errorf("the name is required and can't be longer than %d characters", maxNameLength)
+	}
+	if len(cmd.Recipients) == 0 || len(cmd.Recipients) > s.cfg.DashboardReports.MaxRecipients {
+		return ErrInvalidReport.Errorf("a report must have between 1 and %d recipients", s.cfg.DashboardReports.MaxRecipients)
+	}
+	addresses := cmd.Recipients
+	if cmd.ReplyTo != "" {
+		addresses = append(slices.Clone(addresses), cmd.ReplyTo)
+	}
+	for _, addr := range addresses {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return ErrInvalidReport.Errorf("invalid email address %q", addr)
+		}
+	}
+	if (cmd.TimeRange.From == "") != (cmd.TimeRange.To == "") {
+		return ErrInvalidReport.Errorf("the time range must have both a start and an end, or neither to use the time range of the dashboard")
+	}
+	for name := range cmd.Variables {
+		if name == "" {
+			return ErrInvalidReport.Errorf("variables must have a name")
+		}
+	}
+	if len(cmd.Formats) == 0 {
+		cmd.Formats = []Format{FormatImage}
+	}
+	for _, f := range cmd.Formats {
+		switch f {
+		case FormatPNG, FormatImage:
+		case FormatPDF:
+			if !s.features.IsEnabled(ctx, featuremgmt.FlagNewPDFRendering) {
+				return ErrInvalidReport.Errorf("PDF attachments require the %s feature", featuremgmt.FlagNewPDFRendering)
+			}
+		default:
+			return ErrInvalidReport.Errorf("unknown format %q", f)
+		}
+	}
+
+	if _, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: requester.GetOrgID(), UID: cmd.DashboardUID}); err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return ErrInvalidReport.Errorf("dashboard %s not found", cmd.DashboardUID)
+		}
+		return err
+	}
+	ok, err := s.canView(ctx, requester, cmd.DashboardUID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden.Errorf("requester can't view dashboard %s", cmd.DashboardUID)
+	}
+
+	report.Name = cmd.Name
+	report.DashboardUID = cmd.DashboardUID
+	report.Schedule = strings.TrimSpace(cmd.Schedule)
+	report.Timezone = cmd.Timezone
+	report.Recipients = cmd.Recipients
+	report.ReplyTo = cmd.ReplyTo
+	report.Message = cmd.Message
+	report.TimeRange = cmd.TimeRange
+	report.Variables = cmd.Variables
+	report.Formats = cmd.Formats
+	report.Enabled = cmd.Enabled
+	report.Updated = s.now()
+
+	next, err := nextRun(report, report.Updated)
+	if err != nil {
+		return err
+	}
+	report.NextRun = next.UnixMilli()
+	return nil
+}
+
+// getForChange returns a report the requester owns or has the permission to change with the action.
+func (s *Service) getForChange(ctx context.Context, requester identity.Requester, uid, action string) (*Report, error) {
+	report, err := s.Get(ctx, requester, uid)
+	if err != nil {
+		return nil, err
+	}
+	if id, err := requester.GetInternalID(); err == nil && id == report.OwnerID {
+		return report, nil
+	}
+	ok, err := s.accessControl.Evaluate(ctx, requester, accesscontrol.EvalPermission(action, ScopeReportsProvider.GetResourceScopeUID(uid)))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReportForbidden.Errorf("requester doesn't own report %s and can't %s it", uid, strings.TrimPrefix(action, "reports:"))
+	}
+	return report, nil
+}
+
+func (s *Service) canRead(ctx context.Context, requester identity.Requester, report *Report) (bool, error) {
+	ok, err := s.accessControl.Evaluate(ctx, requester, accesscontrol.EvalPermission(ActionReportsRead, ScopeReportsProvider.GetResourceScopeUID(report.UID)))
+	if err != nil || !ok {
+		return false, err
+	}
+	return s.canView(ctx, requester, report.DashboardUID)
+}
+
+func (s *Service) canView(ctx context.Context, requester identity.Requester, dashboardUID string) (bool, error) {
+	evaluator := accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID))
+	return s.accessControl.Evaluate(ctx, requester, evaluator)
+}
@@ -0,0 +1,254 @@
+package reports
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationDashboardReports(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	// Sunday 2 February 2025, 10:30 UTC
+	now := time.Date(2025, time.February, 2, 10, 30, 0, 0, time.UTC)
+	s, deps := setupTestService(t)
+	s.now = func() time.Time { return now }
+
+	checkout := dashboards.ScopeDashboardsProvider.GetResourceScopeUID("checkout")
+	editor := testUser(1, ActionReportsRead, ScopeReportsAll, dashboards.ActionDashboardsRead, checkout)
+	viewer := testUser(2, ActionReportsRead, ScopeReportsAll)
+	cmd := SaveReportCommand{
+		Name:         "Weekly checkout",
+		DashboardUID: "checkout",
+		Schedule:     "0 8 * * 1",
+		Timezone:     "Europe/Paris",
+		Recipients:   []string{"ops@example.com", "Jane <jane@example.com>"},
+		Message:      "The checkout of last week",
+		TimeRange:    TimeRange{From: "now-7d", To: "now"},
+		Variables:    map[string][]string{"region": {"eu", "us"}},
+		Formats:      []Format{FormatImage, FormatPNG},
+		Enabled:      true,
+	}
+
+	t.Run("reports are validated", func(t *testing.T) {
+		for _, invalid := range []func(c *SaveReportCommand){
+			func(c *SaveReportCommand) { c.Name = " " },
+			func(c *SaveReportCommand) { c.Recipients = nil },
+			func(c *SaveReportCommand) { c.Recipients = []string{"ops"} },
+			func(c *SaveReportCommand) { c.Schedule = "weekly" },
+			func(c *SaveReportCommand) { c.Timezone = "Mars/Olympus" },
+			func(c *SaveReportCommand) { c.TimeRange = TimeRange{From: "now-7d"} },
+			func(c *SaveReportCommand) { c.Formats = []Format{"docx"} },
+			func(c *SaveReportCommand) { c.Formats = []Format{FormatPDF} },
+			func(c *SaveReportCommand) { c.DashboardUID = "unknown" },
+		} {
+			invalidCmd := cmd
+			invalid(&invalidCmd)
+			_, err := s.Create(ctx, editor, invalidCmd)
+			assert.ErrorIs(t, err, ErrInvalidReport)
+		}
+	})
+
+	t.Run("reports require the permission to view the dashboard", func(t *testing.T) {
+		_, err := s.Create(ctx, viewer, cmd)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	report, err := s.Create(ctx, editor, cmd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.OwnerID)
+	// Monday 3 February 2025, 8:00 in Paris
+	assert.Equal(t, time.Date(2025, time.February, 3, 7, 0, 0, 0, time.UTC).UnixMilli(), report.NextRun)
+
+	reports, err := s.List(ctx, editor)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, cmd.Recipients, reports[0].Recipients)
+	assert.Equal(t, cmd.Variables, reports[0].Variables)
+
+	reports, err = s.List(ctx, viewer)
+	require.NoError(t, err)
+	assert.Empty(t, reports)
+	_, err = s.Get(ctx, viewer, report.UID)
+	assert.ErrorIs(t, err, ErrReportNotFound)
+
+	t.Run("reports aren't sent before they are due", func(t *testing.T) {
+		s.sendDue(ctx)
+		assert.Empty(t, deps.email.EmailSync.To)
+	})
+
+	now = time.Date(2025, time.February, 3, 7, 0, 20, 0, time.UTC)
+	s.sendDue(ctx)
+	sent := deps.email.EmailSync
+	assert.Equal(t, cmd.Recipients, sent.To)
+	assert.Equal(t, emailTemplate, sent.Template)
+	assert.Equal(t, "http://localhost:3000/d/checkout/checkout?from=now-7d&orgId=1&timezone=Europe%2FParis&to=now&var-region=eu&var-region=us", sent.Data["DashboardURL"])
+	assert.Equal(t, "Checkout", sent.Data["DashboardTitle"])
+	assert.Equal(t, filepath.Base(deps.rendered), sent.Data["EmbeddedImage"])
+	assert.Equal(t, []string{deps.rendered}, sent.EmbeddedFiles)
+	require.Len(t, sent.AttachedFiles, 1)
+	assert.Equal(t, "checkout.png", sent.AttachedFiles[0].Name)
+	assert.Equal(t, []byte("png"), sent.AttachedFiles[0].Content)
+	require.NotNil(t, deps.dashboardRequester)
+	assert.Equal(t, "user:1", deps.dashboardRequester.GetID())
+
+	stored, err := s.Get(ctx, editor, report.UID)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.February, 10, 7, 0, 0, 0, time.UTC).UnixMilli(), stored.NextRun)
+	require.NotNil(t, stored.LastRun)
+	assert.Empty(t, stored.LastError)
+
+	t.Run("only the owner and users with the permission can change a report", func(t *testing.T) {
+		other := testUser(3, ActionReportsRead, ScopeReportsAll, dashboards.ActionDashboardsRead, checkout)
+		_, err := s.Update(ctx, other, report.UID, cmd)
+		assert.ErrorIs(t, err, ErrReportForbidden)
+		_, err = s.Send(ctx, other, report.UID)
+		assert.ErrorIs(t, err, ErrReportForbidden)
+		assert.ErrorIs(t, s.Delete(ctx, other, report.UID), ErrReportForbidden)
+
+		// The report can't be read without the permission to view its dashboard.
+		_, err = s.Update(ctx, testUser(3, ActionReportsWrite, ScopeReportsAll), report.UID, cmd)
+		assert.ErrorIs(t, err, ErrReportNotFound)
+
+		sender := testUser(3, ActionReportsRead, ScopeReportsAll, dashboards.ActionDashboardsRead, checkout, ActionReportsSend, ScopeReportsProvider.GetResourceScopeUID(report.UID))
+		_, err = s.Send(ctx, sender, report.UID)
+		require.NoError(t, err)
+
+		writer := testUser(4, ActionReportsRead, ScopeReportsAll, dashboards.ActionDashboardsRead, checkout, ActionReportsWrite, ScopeReportsAll)
+		updated, err := s.Update(ctx, writer, report.UID, cmd)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), updated.OwnerID)
+	})
+
+	t.Run("a run is claimed once", func(t *testing.T) {
+		claimed, err := s.store.Claim(ctx, report, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.False(t, claimed)
+
+		deps.email.EmailSync = notifications.SendEmailCommandSync{}
+		s.sendDue(ctx)
+		assert.Empty(t, deps.email.EmailSync.To)
+	})
+
+	t.Run("reports are sent with the permissions of their owner", func(t *testing.T) {
+		deps.acService.ExpectedPermissions = nil
+		_, err := s.Send(ctx, editor, report.UID)
+		assert.ErrorIs(t, err, ErrOwnerForbidden)
+
+		stored, err := s.Get(ctx, editor, report.UID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, stored.LastError)
+	})
+
+	t.Run("reports with an invalid schedule are disabled", func(t *testing.T) {
+		invalid := &Report{
+			OrgID: 1, UID: "invalid", Name: "Invalid", DashboardUID: "checkout", Schedule: "weekly", Recipients: cmd.Recipients,
+			Enabled: true, OwnerID: 1, Created: now, Updated: now, NextRun: now.UnixMilli(),
+		}
+		require.NoError(t, s.store.Create(ctx, invalid))
+
+		deps.email.EmailSync = notifications.SendEmailCommandSync{}
+		s.sendDue(ctx)
+		assert.Empty(t, deps.email.EmailSync.To)
+
+		stored, err := s.Get(ctx, editor, invalid.UID)
+		require.NoError(t, err)
+		assert.False(t, stored.Enabled)
+		assert.Contains(t, stored.LastError, "invalid schedule")
+	})
+
+	require.NoError(t, s.Delete(ctx, editor, report.UID))
+	_, err = s.Get(ctx, editor, report.UID)
+	assert.ErrorIs(t, err, ErrReportNotFound)
+}
+
+type testDependencies struct {
+	acService *actest.FakeService
+	// dashboardRequester is the identity the dashboard of the report was last fetched with.
+	dashboardRequester identity.Requester
+	email              *notifications.NotificationServiceMock
+	rendered           string
+}
+
+func setupTestService(t *testing.T) (*Service, *testDependencies) {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "http://localhost:3000/"
+	cfg.DashboardReports = setting.DashboardReportsSettings{Enabled: true, RenderTimeout: time.Minute, MaxRecipients: 10}
+
+	deps := &testDependencies{}
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+		return q.UID == "checkout"
+	})).Run(func(args mock.Arguments) {
+		if requester, err := identity.GetRequester(args.Get(0).(context.Context)); err == nil {
+			deps.dashboardRequester = requester
+		}
+	}).Return(&dashboards.Dashboard{ID: 42, UID: "checkout", Slug: "checkout", Title: "Checkout", OrgID: 1}, nil).Maybe()
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+
+	deps.acService = &actest.FakeService{ExpectedPermissions: []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeDashboardsProvider.GetResourceScopeUID("checkout")},
+	}}
+	deps.email = &notifications.NotificationServiceMock{}
+	deps.rendered = filepath.Join(t.TempDir(), "rendered.png")
+	require.NoError(t, os.WriteFile(deps.rendered, []byte("png"), 0600))
+
+	renderService := rendering.NewMockService(gomock.NewController(t))
+	renderService.EXPECT().IsCapabilitySupported(gomock.Any(), rendering.FullHeightImages).Return(nil).AnyTimes()
+	renderService.EXPECT().Render(gomock.Any(), rendering.RenderPNG, gomock.Any(), nil).DoAndReturn(
+		func(_ context.Context, _ rendering.RenderType, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+			assert.Equal(t, int64(1), opts.UserID)
+			assert.Equal(t, -1, opts.Height)
+			return &rendering.RenderResult{FilePath: deps.rendered}, nil
+		}).AnyTimes()
+
+	userService := &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor}}
+
+	s, err := ProvideService(
+		cfg, db.InitTestDB(t), routing.NewRouteRegister(), acimpl.ProvideAccessControlTest(), deps.acService,
+		featuremgmt.WithFeatures(), dashboardService, userService, renderService, deps.email, orgtest.NewOrgServiceFake(),
+	)
+	require.NoError(t, err)
+	return s, deps
+}
+
+func testUser(id int64, permissions ...string) *user.SignedInUser {
+	u := &user.SignedInUser{UserID: id, OrgID: 1, OrgRole: org.RoleEditor, Permissions: map[int64]map[string][]string{1: {}}}
+	for i := 0; i+1 < len(permissions); i += 2 {
+		u.Permissions[1][permissions[i]] = append(u.Permissions[1][permissions[i]], permissions[i+1])
+	}
+	return u
+}
@@ -0,0 +1,164 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store interface {
+	Create(ctx context.Context, report *Report) error
+	Update(ctx context.Context, report *Report) error
+	Delete(ctx context.Context, orgID int64, uid string) error
+	Get(ctx context.Context, orgID int64, uid string) (*Report, error)
+	List(ctx context.Context, orgID int64) ([]*Report, error)
+	// ListDue returns the enabled reports of every organization due at t.
+	ListDue(ctx context.Context, t time.Time) ([]*Report, error)
+	// Claim moves the next run of a due report to next, it returns false when another instance
+	// claimed the run first.
+	Claim(ctx context.Context, report *Report, next time.Time) (bool, error)
+	// SetResult records the outcome of the last run of a report.
+	SetResult(ctx context.Context, report *Report) error
+	// Disable disables a due report and records why, unless another instance claimed its run first.
+	Disable(ctx context.Context, report *Report, reason string) error
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) Create(ctx context.Context, report *Report) error {
+	if err := encodeOptions(report); err != nil {
+		return err
+	}
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(report)
+		return err
+	})
+}
+
+func (s *xormStore) Update(ctx context.Context, report *Report) error {
+	if err := encodeOptions(report); err != nil {
+		return err
+	}
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(report.ID).
+			Cols("name", "dashboard_uid", "schedule", "timezone", "reply_to", "message", "options", "enabled", "updated", "next_run").
+			Update(report)
+		return err
+	})
+}
+
+func (s *xormStore) Delete(ctx context.Context, orgID int64, uid string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&Report{})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrReportNotFound.Errorf("report %s not found", uid)
+		}
+		return nil
+	})
+}
+
+func (s *xormStore) Get(ctx context.Context, orgID int64, uid string) (*Report, error) {
+	report := &Report{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(report)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ErrReportNotFound.Errorf("report %s not found", uid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, decodeOptions(report)
+}
+
+func (s *xormStore) List(ctx context.Context, orgID int64) ([]*Report, error) {
+	return s.find(ctx, "org_id = ?", orgID)
+}
+
+func (s *xormStore) ListDue(ctx context.Context, t time.Time) ([]*Report, error) {
+	return s.find(ctx, "enabled = ? AND next_run <= ?", true, t.UnixMilli())
+}
+
+func (s *xormStore) find(ctx context.Context, query string, args ...any) ([]*Report, error) {
+	reports := make([]*Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where(query, args...).Asc("name").Find(&reports)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reports {
+		if err := decodeOptions(r); err != nil {
+			return nil, err
+		}
+	}
+	return reports, nil
+}
+
+func (s *xormStore) Claim(ctx context.Context, report *Report, next time.Time) (bool, error) {
+	var claimed bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE dashboard_report SET next_run = ? WHERE id = ? AND next_run = ?", next.UnixMilli(), report.ID, report.NextRun)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		claimed = rows == 1
+		return err
+	})
+	if claimed {
+		report.NextRun = next.UnixMilli()
+	}
+	return claimed, err
+}
+
+func (s *xormStore) SetResult(ctx context.Context, report *Report) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(report.ID).Cols("last_run", "last_error").Update(report)
+		return err
+	})
+}
+
+func (s *xormStore) Disable(ctx context.Context, report *Report, reason string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard_report SET enabled = ?, last_error = ? WHERE id = ? AND next_run = ?", false, reason, report.ID, report.NextRun)
+		return err
+	})
+}
+
+func encodeOptions(report *Report) error {
+	raw, err := json.Marshal(options{
+		Recipients: report.Recipients,
+		TimeRange:  report.TimeRange,
+		Variables:  report.Variables,
+		Formats:    report.Formats,
+	})
+	if err != nil {
+		return err
+	}
+	report.RawOptions = string(raw)
+	return nil
+}
+
+func decodeOptions(report *Report) error {
+	opts := options{}
+	if err := json.Unmarshal([]byte(report.RawOptions), &opts); err != nil {
+		return err
+	}
+	report.Recipients = opts.Recipients
+	report.TimeRange = opts.TimeRange
+	report.Variables = opts.Variables
+	report.Formats = opts.Formats
+	return nil
+}
@@ -13,11 +13,12 @@ import (
 
 var (
 	excludedFields = map[string]string{
-		resource.SEARCH_FIELD_EXPLAIN: "",
-		resource.SEARCH_FIELD_SCORE:   "",
-		resource.SEARCH_FIELD_TITLE:   "",
-		resource.SEARCH_FIELD_FOLDER:  "",
-		resource.SEARCH_FIELD_TAGS:    "",
+		resource.SEARCH_FIELD_EXPLAIN:   "",
+		resource.SEARCH_FIELD_HIGHLIGHT: "",
+		resource.SEARCH_FIELD_SCORE:     "",
+		resource.SEARCH_FIELD_TITLE:     "",
+		resource.SEARCH_FIELD_FOLDER:    "",
+		resource.SEARCH_FIELD_TAGS:      "",
 	}
 
 	IncludeFields = []string{
@@ -50,11 +51,14 @@ func ParseResults(result *resource.ResourceSearchResponse, offset int64) (v0alph
 	tagsIDX := -1
 	scoreIDX := 0
 	explainIDX := 0
+	highlightIDX := 0
 
 	for i, v := range result.Results.Columns {
 		switch v.Name {
 		case resource.SEARCH_FIELD_EXPLAIN:
 			explainIDX = i
+		case resource.SEARCH_FIELD_HIGHLIGHT:
+			highlightIDX = i
 		case resource.SEARCH_FIELD_SCORE:
 			scoreIDX = i
 		case resource.SEARCH_FIELD_TITLE:
@@ -106,6 +110,9 @@ func ParseResults(result *resource.ResourceSearchResponse, offset int64) (v0alph
 		if explainIDX > 0 && row.Cells[explainIDX] != nil {
 			_ = json.Unmarshal(row.Cells[explainIDX], &hit.Explain)
 		}
+		if highlightIDX > 0 && row.Cells[highlightIDX] != nil {
+			_ = json.Unmarshal(row.Cells[highlightIDX], &hit.Highlight)
+		}
 		if scoreIDX > 0 && row.Cells[scoreIDX] != nil {
 			_, _ = binary.Decode(row.Cells[scoreIDX], binary.BigEndian, &hit.Score)
 		}
@@ -8,4 +8,8 @@ type Service interface {
 	Get(context.Context, *GetDashboardVersionQuery) (*DashboardVersionDTO, error)
 	DeleteExpired(context.Context, *DeleteExpiredVersionsCommand) error
 	List(context.Context, *ListDashboardVersionsQuery) (*DashboardVersionResponse, error)
+	Label(context.Context, *LabelDashboardVersionCommand) (*DashboardVersionDTO, error)
+	GetRetentionPolicies(ctx context.Context, orgID int64) ([]*RetentionPolicy, error)
+	SetRetentionPolicy(context.Context, *RetentionPolicy) error
+	DeleteRetentionPolicy(ctx context.Context, orgID int64, folderUID string) error
 }
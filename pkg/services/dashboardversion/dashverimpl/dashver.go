@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -29,6 +31,8 @@ import (
 const (
 	maxVersionsToDeletePerBatch = 100
 	maxVersionDeletionBatches   = 50
+	maxVersionNameLength        = 190
+	maxVersionTagLength         = 50
 )
 
 type Service struct {
@@ -113,7 +117,7 @@ func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredV
 		}
 
 		if len(versionIdsToDelete) < 1 {
-			return nil
+			break
 		}
 
 		deleted, err := s.store.DeleteBatch(ctx, cmd, versionIdsToDelete)
@@ -127,9 +131,65 @@ func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredV
 			break
 		}
 	}
+
+	return s.deleteExpiredByPolicy(ctx, cmd, time.Now())
+}
+
+// deleteExpiredByPolicy trims the versions of the dashboards in folders with a retention policy,
+// which are left alone by the global number of versions to keep.
+func (s *Service) deleteExpiredByPolicy(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, now time.Time) error {
+	policies, err := s.store.ListRetentionPolicies(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		dashboardIDs, err := s.store.ListRetainedDashboards(ctx, policy)
+		if err != nil {
+			return err
+		}
+		for _, dashboardID := range dashboardIDs {
+			candidates, err := s.store.ListRetentionCandidates(ctx, dashboardID)
+			if err != nil {
+				return err
+			}
+			expired := expiredVersions(policy, candidates, now)
+			for len(expired) > 0 {
+				batch := expired[:min(len(expired), maxVersionsToDeletePerBatch)]
+				expired = expired[len(batch):]
+				deleted, err := s.store.DeleteBatch(ctx, cmd, batch)
+				if err != nil {
+					return err
+				}
+				cmd.DeletedRows += deleted
+			}
+		}
+	}
 	return nil
 }
 
+// expiredVersions returns the IDs of the versions, ordered latest first, that none of the rules of
+// the policy keeps. The latest version is always kept.
+func expiredVersions(policy *dashver.RetentionPolicy, candidates []*retentionCandidate, now time.Time) []any {
+	keepAfter := now.AddDate(0, 0, -policy.KeepDays)
+	keepTags := make(map[string]bool, len(policy.KeepTags))
+	for _, tag := range policy.KeepTags {
+		keepTags[tag] = true
+	}
+
+	var expired []any
+	for i, v := range candidates {
+		if i == 0 || i < policy.KeepVersions || (policy.KeepDays > 0 && v.Created.After(keepAfter)) {
+			continue
+		}
+		if slices.ContainsFunc(v.Tags, func(tag string) bool { return keepTags[tag] }) {
+			continue
+		}
+		expired = append(expired, v.ID)
+	}
+	return expired
+}
+
 // List all dashboard versions for the given dashboard ID.
 func (s *Service) List(ctx context.Context, query *dashver.ListDashboardVersionsQuery) (*dashver.DashboardVersionResponse, error) {
 	// Get the DashboardUID if not populated
@@ -182,6 +242,94 @@ func (s *Service) List(ctx context.Context, query *dashver.ListDashboardVersions
 	}, nil
 }
 
+// Label names and tags a version, replacing its previous labels.
+func (s *Service) Label(ctx context.Context, cmd *dashver.LabelDashboardVersionCommand) (*dashver.DashboardVersionDTO, error) {
+	if s.features.IsEnabledGlobally(featuremgmt.FlagKubernetesCliDashboards) {
+		return nil, dashver.ErrUnsupportedWithUnified.Errorf("dashboard versions stored in unified storage can't be labelled")
+	}
+
+	name := strings.TrimSpace(cmd.Name)
+	if len(name) > maxVersionNameLength {
+		return nil, dashver.ErrInvalidVersionLabel.Errorf("version names can have at most %d characters", maxVersionNameLength)
+	}
+	tags, err := cleanTags(cmd.Tags)
+	if err != nil {
+		return nil, dashver.ErrInvalidVersionLabel.Errorf("%w", err)
+	}
+
+	if cmd.DashboardUID == "" {
+		u, err := s.getDashUIDMaybeEmpty(ctx, cmd.DashboardID)
+		if err != nil {
+			return nil, err
+		}
+		cmd.DashboardUID = u
+	}
+	if cmd.DashboardID == 0 {
+		id, err := s.getDashIDMaybeEmpty(ctx, cmd.DashboardUID, cmd.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		cmd.DashboardID = id
+	}
+
+	version, err := s.store.Get(ctx, &dashver.GetDashboardVersionQuery{DashboardID: cmd.DashboardID, OrgID: cmd.OrgID, Version: cmd.Version})
+	if err != nil {
+		return nil, err
+	}
+	version.Name = name
+	version.Tags = tags
+	if err := s.store.Label(ctx, version); err != nil {
+		return nil, err
+	}
+	return version.ToDTO(cmd.DashboardUID), nil
+}
+
+func (s *Service) GetRetentionPolicies(ctx context.Context, orgID int64) ([]*dashver.RetentionPolicy, error) {
+	return s.store.ListRetentionPolicies(ctx, orgID)
+}
+
+// SetRetentionPolicy creates or replaces the retention policy of a folder.
+func (s *Service) SetRetentionPolicy(ctx context.Context, policy *dashver.RetentionPolicy) error {
+	if s.features.IsEnabledGlobally(featuremgmt.FlagKubernetesCliDashboards) {
+		return dashver.ErrUnsupportedWithUnified.Errorf("versions of dashboards stored in unified storage have no retention policies")
+	}
+	if policy.KeepVersions < 0 || policy.KeepDays < 0 {
+		return dashver.ErrInvalidRetentionPolicy.Errorf("keepVersions and keepDays can't be negative")
+	}
+	if policy.KeepVersions == 0 && policy.KeepDays == 0 {
+		return dashver.ErrInvalidRetentionPolicy.Errorf("at least one of keepVersions and keepDays is required")
+	}
+	tags, err := cleanTags(policy.KeepTags)
+	if err != nil {
+		return dashver.ErrInvalidRetentionPolicy.Errorf("%w", err)
+	}
+	policy.KeepTags = tags
+
+	now := time.Now()
+	policy.Created = now
+	policy.Updated = now
+	return s.store.UpsertRetentionPolicy(ctx, policy)
+}
+
+func (s *Service) DeleteRetentionPolicy(ctx context.Context, orgID int64, folderUID string) error {
+	return s.store.DeleteRetentionPolicy(ctx, orgID, folderUID)
+}
+
+func cleanTags(tags []string) ([]string, error) {
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || slices.Contains(cleaned, tag) {
+			continue
+		}
+		if len(tag) > maxVersionTagLength {
+			return nil, fmt.Errorf("tags can have at most %d characters", maxVersionTagLength)
+		}
+		cleaned = append(cleaned, tag)
+	}
+	return cleaned, nil
+}
+
 // getDashUIDMaybeEmpty is a helper function which takes a dashboardID and
 // returns the UID. If the dashboard is not found, it will return an empty
 // string.
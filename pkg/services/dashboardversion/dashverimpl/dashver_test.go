@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -138,6 +140,19 @@ func TestDeleteExpiredVersions(t *testing.T) {
 		require.Nil(t, err)
 	})
 
+	t.Run("Clean up the versions of dashboards with a retention policy", func(t *testing.T) {
+		dashboardVersionStore.ExpectedVersions = nil
+		dashboardVersionStore.DeletedVersions = nil
+		dashboardVersionStore.ExpectedRetentionPolicies = []*dashver.RetentionPolicy{{OrgID: 1, FolderUID: "prod", KeepVersions: 2}}
+		dashboardVersionStore.ExpectedRetainedDashboards = []int64{42}
+		dashboardVersionStore.ExpectedRetentionCandidates = []*retentionCandidate{
+			{ID: 14, Version: 4}, {ID: 13, Version: 3}, {ID: 12, Version: 2}, {ID: 11, Version: 1},
+		}
+		err := dashboardVersionService.DeleteExpired(context.Background(), &dashver.DeleteExpiredVersionsCommand{})
+		require.Nil(t, err)
+		require.Equal(t, []any{int64(12), int64(11)}, dashboardVersionStore.DeletedVersions)
+	})
+
 	t.Run("Clean up old dashboard versions with error", func(t *testing.T) {
 		dashboardVersionStore.ExpectedError = errors.New("some error")
 		err := dashboardVersionService.DeleteExpired(context.Background(), &dashver.DeleteExpiredVersionsCommand{DeletedRows: 4})
@@ -269,11 +284,16 @@ func TestListDashboardVersions(t *testing.T) {
 }
 
 type FakeDashboardVersionStore struct {
-	ExpectedDashboardVersion *dashver.DashboardVersion
-	ExptectedDeletedVersions int64
-	ExpectedVersions         []any
-	ExpectedListVersions     []*dashver.DashboardVersion
-	ExpectedError            error
+	ExpectedDashboardVersion    *dashver.DashboardVersion
+	ExptectedDeletedVersions    int64
+	ExpectedVersions            []any
+	ExpectedListVersions        []*dashver.DashboardVersion
+	ExpectedRetentionPolicies   []*dashver.RetentionPolicy
+	ExpectedRetainedDashboards  []int64
+	ExpectedRetentionCandidates []*retentionCandidate
+	ExpectedError               error
+
+	DeletedVersions []any
 }
 
 func newDashboardVersionStoreFake() *FakeDashboardVersionStore {
@@ -289,9 +309,83 @@ func (f *FakeDashboardVersionStore) GetBatch(ctx context.Context, cmd *dashver.D
 }
 
 func (f *FakeDashboardVersionStore) DeleteBatch(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand, versionIdsToDelete []any) (int64, error) {
+	f.DeletedVersions = append(f.DeletedVersions, versionIdsToDelete...)
 	return f.ExptectedDeletedVersions, f.ExpectedError
 }
 
 func (f *FakeDashboardVersionStore) List(ctx context.Context, query *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersion, error) {
 	return f.ExpectedListVersions, f.ExpectedError
 }
+
+func (f *FakeDashboardVersionStore) Label(ctx context.Context, version *dashver.DashboardVersion) error {
+	return f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) ListRetentionPolicies(ctx context.Context, orgID int64) ([]*dashver.RetentionPolicy, error) {
+	return f.ExpectedRetentionPolicies, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) UpsertRetentionPolicy(ctx context.Context, policy *dashver.RetentionPolicy) error {
+	return f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) DeleteRetentionPolicy(ctx context.Context, orgID int64, folderUID string) error {
+	return f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) ListRetainedDashboards(ctx context.Context, policy *dashver.RetentionPolicy) ([]int64, error) {
+	return f.ExpectedRetainedDashboards, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) ListRetentionCandidates(ctx context.Context, dashboardID int64) ([]*retentionCandidate, error) {
+	return f.ExpectedRetentionCandidates, f.ExpectedError
+}
+
+func TestExpiredVersions(t *testing.T) {
+	now := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	candidates := []*retentionCandidate{
+		{ID: 5, Version: 5, Created: now.AddDate(0, 0, -1)},
+		{ID: 4, Version: 4, Created: now.AddDate(0, 0, -10)},
+		{ID: 3, Version: 3, Created: now.AddDate(0, 0, -40), Tags: []string{"release"}},
+		{ID: 2, Version: 2, Created: now.AddDate(0, 0, -50)},
+		{ID: 1, Version: 1, Created: now.AddDate(0, 0, -60)},
+	}
+
+	testCases := []struct {
+		desc     string
+		policy   *dashver.RetentionPolicy
+		expected []any
+	}{
+		{
+			desc:     "keeps the latest versions",
+			policy:   &dashver.RetentionPolicy{KeepVersions: 2},
+			expected: []any{int64(3), int64(2), int64(1)},
+		},
+		{
+			desc:     "keeps the recent versions",
+			policy:   &dashver.RetentionPolicy{KeepDays: 30},
+			expected: []any{int64(3), int64(2), int64(1)},
+		},
+		{
+			desc:     "keeps versions matching any rule",
+			policy:   &dashver.RetentionPolicy{KeepVersions: 1, KeepDays: 45, KeepTags: []string{"release"}},
+			expected: []any{int64(2), int64(1)},
+		},
+		{
+			desc:     "keeps tagged versions",
+			policy:   &dashver.RetentionPolicy{KeepVersions: 1, KeepTags: []string{"release", "audit"}},
+			expected: []any{int64(4), int64(2), int64(1)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, expiredVersions(tc.policy, candidates, now))
+		})
+	}
+
+	t.Run("never deletes the latest version", func(t *testing.T) {
+		old := []*retentionCandidate{{ID: 2, Created: now.AddDate(-1, 0, 0)}, {ID: 1, Created: now.AddDate(-2, 0, 0)}}
+		assert.Equal(t, []any{int64(1)}, expiredVersions(&dashver.RetentionPolicy{KeepDays: 1}, old, now))
+	})
+}
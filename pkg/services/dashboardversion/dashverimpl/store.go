@@ -11,4 +11,10 @@ type store interface {
 	GetBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, int, int) ([]any, error)
 	DeleteBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, []any) (int64, error)
 	List(context.Context, *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersion, error)
+	Label(context.Context, *dashver.DashboardVersion) error
+	ListRetentionPolicies(ctx context.Context, orgID int64) ([]*dashver.RetentionPolicy, error)
+	UpsertRetentionPolicy(context.Context, *dashver.RetentionPolicy) error
+	DeleteRetentionPolicy(ctx context.Context, orgID int64, folderUID string) error
+	ListRetainedDashboards(context.Context, *dashver.RetentionPolicy) ([]int64, error)
+	ListRetentionCandidates(ctx context.Context, dashboardID int64) ([]*retentionCandidate, error)
 }
@@ -116,6 +116,62 @@ func testIntegrationGetDashboardVersion(t *testing.T, fn getStore) {
 		require.Nil(t, err)
 		assert.Equal(t, 2, len(res))
 	})
+
+	t.Run("Label a version", func(t *testing.T) {
+		version, err := dashVerStore.Get(context.Background(), &dashver.GetDashboardVersionQuery{DashboardID: savedDash.ID, Version: 1, OrgID: 1})
+		require.NoError(t, err)
+		version.Name = "v1.0.0"
+		version.Tags = []string{"release"}
+		require.NoError(t, dashVerStore.Label(context.Background(), version))
+
+		res, err := dashVerStore.List(context.Background(), &dashver.ListDashboardVersionsQuery{DashboardID: savedDash.ID, OrgID: 1, Limit: 1000})
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		assert.Empty(t, res[0].Name)
+		assert.Equal(t, "v1.0.0", res[1].Name)
+		assert.Equal(t, []string{"release"}, res[1].Tags)
+	})
+
+	t.Run("Retention policies", func(t *testing.T) {
+		ctx := context.Background()
+		retained := insertTestDashboard(t, ss, "test dash in prod", 1, "prod", false)
+		updateTestDashboard(t, ss, retained, map[string]any{"title": "test dash in prod"})
+		other := insertTestDashboard(t, ss, "test dash in scratch", 1, "scratch", false)
+		updateTestDashboard(t, ss, other, map[string]any{"title": "test dash in scratch"})
+
+		policy := &dashver.RetentionPolicy{OrgID: 1, FolderUID: "prod", KeepVersions: 10, Created: time.Now(), Updated: time.Now()}
+		require.NoError(t, dashVerStore.UpsertRetentionPolicy(ctx, policy))
+		policy = &dashver.RetentionPolicy{OrgID: 1, FolderUID: "prod", KeepDays: 30, KeepTags: []string{"release"}, Created: time.Now(), Updated: time.Now()}
+		require.NoError(t, dashVerStore.UpsertRetentionPolicy(ctx, policy))
+
+		policies, err := dashVerStore.ListRetentionPolicies(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		assert.Equal(t, 0, policies[0].KeepVersions)
+		assert.Equal(t, 30, policies[0].KeepDays)
+		assert.Equal(t, []string{"release"}, policies[0].KeepTags)
+
+		dashboardIDs, err := dashVerStore.ListRetainedDashboards(ctx, policies[0])
+		require.NoError(t, err)
+		assert.Equal(t, []int64{retained.ID}, dashboardIDs)
+
+		candidates, err := dashVerStore.ListRetentionCandidates(ctx, retained.ID)
+		require.NoError(t, err)
+		require.Len(t, candidates, 2)
+		assert.Equal(t, 2, candidates[0].Version)
+
+		// The global number of versions to keep doesn't apply to dashboards with a policy.
+		otherCandidates, err := dashVerStore.ListRetentionCandidates(ctx, other.ID)
+		require.NoError(t, err)
+		ids, err := dashVerStore.GetBatch(ctx, &dashver.DeleteExpiredVersionsCommand{}, 100, 1)
+		require.NoError(t, err)
+		assert.Contains(t, ids, otherCandidates[1].ID)
+		assert.NotContains(t, ids, candidates[1].ID)
+
+		require.NoError(t, dashVerStore.DeleteRetentionPolicy(ctx, 1, "prod"))
+		err = dashVerStore.DeleteRetentionPolicy(ctx, 1, "prod")
+		assert.ErrorIs(t, err, dashver.ErrRetentionPolicyNotFound)
+	})
 }
 
 func getDashboard(t *testing.T, sqlStore db.DB, dashboard *dashboards.Dashboard) error {
@@ -3,6 +3,7 @@ package dashverimpl
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
@@ -47,6 +48,7 @@ func (ss *sqlStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVers
 			) AS vtd
 			WHERE dashboard_version.dashboard_id=vtd.dashboard_id
 			AND version < vtd.min + vtd.count - ?
+			AND dashboard_version.dashboard_id NOT IN (` + ss.retainedDashboardsQuery() + `)
 			LIMIT ?`
 
 		err := sess.SQL(versionIdsToDeleteQuery, versionsToKeep, perBatch).Find(&versionIds)
@@ -83,7 +85,9 @@ func (ss *sqlStore) List(ctx context.Context, query *dashver.ListDashboardVersio
 				dashboard_version.created,
 				dashboard_version.created_by,
 				dashboard_version.message,
-				dashboard_version.data`).
+				dashboard_version.data,
+				dashboard_version.name,
+				dashboard_version.tags`).
 			Join("LEFT", "dashboard", `dashboard.id = dashboard_version.dashboard_id`).
 			Where("dashboard_version.dashboard_id=? AND dashboard.org_id=?", query.DashboardID, query.OrgID).
 			OrderBy("dashboard_version.version DESC").
@@ -103,3 +107,95 @@ func (ss *sqlStore) List(ctx context.Context, query *dashver.ListDashboardVersio
 	}
 	return dashboardVersion, nil
 }
+
+// retainedDashboardsQuery selects the dashboards whose versions are trimmed by a retention policy
+// rather than by the global number of versions to keep.
+func (ss *sqlStore) retainedDashboardsQuery() string {
+	return `SELECT dashboard.id
+		FROM dashboard
+		INNER JOIN dashboard_version_retention
+		ON dashboard_version_retention.org_id = dashboard.org_id
+		AND dashboard_version_retention.folder_uid = COALESCE(dashboard.folder_uid, '')
+		WHERE dashboard.is_folder = ` + ss.dialect.BooleanStr(false)
+}
+
+// retentionCandidate is the part of a version needed to apply a retention policy.
+type retentionCandidate struct {
+	ID      int64     `xorm:"'id'"`
+	Version int       `xorm:"'version'"`
+	Created time.Time `xorm:"'created'"`
+	Tags    []string  `xorm:"'tags'"`
+}
+
+func (ss *sqlStore) Label(ctx context.Context, version *dashver.DashboardVersion) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(version.ID).Cols("name", "tags").Update(version)
+		return err
+	})
+}
+
+func (ss *sqlStore) ListRetentionPolicies(ctx context.Context, orgID int64) ([]*dashver.RetentionPolicy, error) {
+	policies := make([]*dashver.RetentionPolicy, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Asc("org_id", "folder_uid")
+		if orgID != 0 {
+			q = q.Where("org_id = ?", orgID)
+		}
+		return q.Find(&policies)
+	})
+	return policies, err
+}
+
+func (ss *sqlStore) UpsertRetentionPolicy(ctx context.Context, policy *dashver.RetentionPolicy) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := &dashver.RetentionPolicy{}
+		has, err := sess.Where("org_id = ? AND folder_uid = ?", policy.OrgID, policy.FolderUID).Get(existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			_, err = sess.Insert(policy)
+			return err
+		}
+		policy.ID = existing.ID
+		policy.Created = existing.Created
+		_, err = sess.ID(policy.ID).Cols("keep_versions", "keep_days", "keep_tags", "updated").Update(policy)
+		return err
+	})
+}
+
+func (ss *sqlStore) DeleteRetentionPolicy(ctx context.Context, orgID int64, folderUID string) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("org_id = ? AND folder_uid = ?", orgID, folderUID).Delete(&dashver.RetentionPolicy{})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return dashver.ErrRetentionPolicyNotFound.Errorf("no version retention policy for folder %q", folderUID)
+		}
+		return nil
+	})
+}
+
+// ListRetainedDashboards returns the IDs of the dashboards directly in the folder of the policy.
+func (ss *sqlStore) ListRetainedDashboards(ctx context.Context, policy *dashver.RetentionPolicy) ([]int64, error) {
+	var ids []int64
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("dashboard").Cols("id").
+			Where("org_id = ? AND COALESCE(folder_uid, '') = ? AND is_folder = "+ss.dialect.BooleanStr(false), policy.OrgID, policy.FolderUID).
+			Find(&ids)
+	})
+	return ids, err
+}
+
+// ListRetentionCandidates returns the versions of a dashboard, latest first.
+func (ss *sqlStore) ListRetentionCandidates(ctx context.Context, dashboardID int64) ([]*retentionCandidate, error) {
+	candidates := make([]*retentionCandidate, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("dashboard_version").Cols("id", "version", "created", "tags").
+			Where("dashboard_id = ?", dashboardID).
+			Desc("version").
+			Find(&candidates)
+	})
+	return candidates, err
+}
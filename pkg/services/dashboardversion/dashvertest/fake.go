@@ -11,6 +11,7 @@ type FakeDashboardVersionService struct {
 	ExpectedDashboardVersions    []*dashver.DashboardVersionDTO
 	ExpectedListDashboarVersions []*dashver.DashboardVersionDTO
 	ExpectedContinueToken        string
+	ExpectedRetentionPolicies    []*dashver.RetentionPolicy
 	counter                      int
 	ExpectedError                error
 }
@@ -37,3 +38,19 @@ func (f *FakeDashboardVersionService) List(ctx context.Context, query *dashver.L
 		Versions:      f.ExpectedListDashboarVersions,
 	}, f.ExpectedError
 }
+
+func (f *FakeDashboardVersionService) Label(ctx context.Context, cmd *dashver.LabelDashboardVersionCommand) (*dashver.DashboardVersionDTO, error) {
+	return f.ExpectedDashboardVersion, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionService) GetRetentionPolicies(ctx context.Context, orgID int64) ([]*dashver.RetentionPolicy, error) {
+	return f.ExpectedRetentionPolicies, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionService) SetRetentionPolicy(ctx context.Context, policy *dashver.RetentionPolicy) error {
+	return f.ExpectedError
+}
+
+func (f *FakeDashboardVersionService) DeleteRetentionPolicy(ctx context.Context, orgID int64, folderUID string) error {
+	return f.ExpectedError
+}
@@ -4,12 +4,19 @@ import (
 	"errors"
 	"time"
 
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
 var (
 	ErrDashboardVersionNotFound = errors.New("dashboard version not found")
 	ErrNoVersionsForDashboardID = errors.New("no dashboard versions found for the given DashboardId")
+
+	ErrInvalidVersionLabel     = errutil.BadRequest("dashboards.version-label-invalid")
+	ErrInvalidRetentionPolicy  = errutil.BadRequest("dashboards.version-retention-invalid")
+	ErrRetentionPolicyNotFound = errutil.NotFound("dashboards.version-retention-not-found", errutil.WithPublicMessage("Version retention policy not found"))
+	ErrUnsupportedWithUnified  = errutil.NotImplemented("dashboards.version-unified-unsupported",
+		errutil.WithPublicMessage("Labels and retention policies aren't supported for dashboards stored in unified storage"))
 )
 
 // DashboardVersion represents a dashboard version in the database. Ideally this
@@ -28,6 +35,10 @@ type DashboardVersion struct {
 
 	Message string           `json:"message" db:"message"`
 	Data    *simplejson.Json `json:"data" db:"data"`
+
+	// Name and Tags label a version after it was saved, for example as a release.
+	Name string   `json:"name" db:"name"`
+	Tags []string `json:"tags" db:"tags"`
 }
 
 // ToDTO converts a DashboardVersion to a DashboardVersionDTO.
@@ -43,6 +54,8 @@ func (v *DashboardVersion) ToDTO(dashUid string) *DashboardVersionDTO {
 		CreatedBy:     v.CreatedBy,
 		Message:       v.Message,
 		Data:          v.Data,
+		Name:          v.Name,
+		Tags:          v.Tags,
 	}
 }
 
@@ -59,6 +72,38 @@ type DeleteExpiredVersionsCommand struct {
 	DeletedRows int64
 }
 
+// LabelDashboardVersionCommand names and tags a version. Only one of DashboardID and DashboardUID
+// are required.
+type LabelDashboardVersionCommand struct {
+	DashboardID  int64
+	DashboardUID string
+	OrgID        int64
+	Version      int64
+	Name         string
+	Tags         []string
+}
+
+// RetentionPolicy replaces the global number of versions to keep for the dashboards directly in a
+// folder, an empty FolderUID is the root folder. A version is kept when any of the rules keeps it,
+// and the latest version of a dashboard is always kept.
+type RetentionPolicy struct {
+	ID        int64  `json:"-" xorm:"pk autoincr 'id'"`
+	OrgID     int64  `json:"orgId" xorm:"org_id"`
+	FolderUID string `json:"folderUid" xorm:"folder_uid"`
+	// KeepVersions keeps the latest versions of each dashboard.
+	KeepVersions int `json:"keepVersions" xorm:"keep_versions"`
+	// KeepDays keeps the versions created in the last days.
+	KeepDays int `json:"keepDays" xorm:"keep_days"`
+	// KeepTags keeps the versions tagged with any of these tags, regardless of their age.
+	KeepTags []string  `json:"keepTags" xorm:"keep_tags"`
+	Created  time.Time `json:"created" xorm:"'created'"`
+	Updated  time.Time `json:"updated" xorm:"'updated'"`
+}
+
+func (RetentionPolicy) TableName() string {
+	return "dashboard_version_retention"
+}
+
 type ListDashboardVersionsQuery struct {
 	DashboardID   int64
 	DashboardUID  string
@@ -84,6 +129,8 @@ type DashboardVersionDTO struct {
 	CreatedBy     int64            `json:"createdBy"`
 	Message       string           `json:"message"`
 	Data          *simplejson.Json `json:"data" db:"data"`
+	Name          string           `json:"name,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
 }
 
 // DashboardVersionMeta extends the DashboardVersionDTO with the names
@@ -100,6 +147,8 @@ type DashboardVersionMeta struct {
 	Message       string           `json:"message"`
 	Data          *simplejson.Json `json:"data"`
 	CreatedBy     string           `json:"createdBy"`
+	Name          string           `json:"name,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
 }
 
 type DashboardVersionResponseMeta struct {
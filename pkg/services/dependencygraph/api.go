@@ -0,0 +1,48 @@
+package dependencygraph
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := accesscontrol.Middleware(s.accessControl)
+	// The graph covers every resource of the organization, it is only available to users that can read them all.
+	canReadAll := authorize(accesscontrol.EvalAll(
+		accesscontrol.EvalPermission(datasources.ActionRead, datasources.ScopeAll),
+		accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeFoldersAll),
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleRead, dashboards.ScopeFoldersAll),
+	))
+
+	routeRegister.Group("/api/dependencies", func(r routing.RouteRegister) {
+		r.Get("/", canReadAll, routing.Wrap(s.getGraphHandler))
+		r.Get("/:kind/:uid", canReadAll, routing.Wrap(s.getImpactHandler))
+	}, middleware.ReqSignedIn)
+}
+
+// GET /api/dependencies
+func (s *Service) getGraphHandler(c *contextmodel.ReqContext) response.Response {
+	g, err := s.GetGraph(c.Req.Context(), c.SignedInUser.GetOrgID(), c.QueryBool("refresh"))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to build dependency graph", err)
+	}
+	return response.JSON(http.StatusOK, g)
+}
+
+// GET /api/dependencies/:kind/:uid
+func (s *Service) getImpactHandler(c *contextmodel.ReqContext) response.Response {
+	params := web.Params(c.Req)
+	impact, err := s.GetImpact(c.Req.Context(), c.SignedInUser.GetOrgID(), Kind(params[":kind"]), params[":uid"], c.QueryBool("refresh"))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dependencies", err)
+	}
+	return response.JSON(http.StatusOK, impact)
+}
@@ -0,0 +1,340 @@
+package dependencygraph
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// builtInDatasources are the data sources that don't exist in the database
+var builtInDatasources = map[string]bool{
+	"grafana":         true,
+	"-- Grafana --":   true,
+	"-- Mixed --":     true,
+	"-- Dashboard --": true,
+	"__expr__":        true,
+	"__ml__":          true,
+	"-100":            true,
+}
+
+// dashboardURL matches the UID of links to dashboards, both relative and absolute.
+var dashboardURL = regexp.MustCompile(`(?:^|/)d/([a-zA-Z0-9_-]{1,40})(?:[/?#]|$)`)
+
+type libraryPanel struct {
+	ID    int64           `xorm:"'id'"`
+	UID   string          `xorm:"'uid'"`
+	Name  string          `xorm:"'name'"`
+	Model json.RawMessage `xorm:"'model'"`
+	// DashboardIDs are the dashboards the panel is connected to.
+	DashboardIDs []int64 `xorm:"-"`
+}
+
+// sources are the stored resources the graph of an organization is built from.
+type sources struct {
+	datasources   []*datasources.DataSource
+	dashboards    []*dashboards.Dashboard
+	libraryPanels []*libraryPanel
+	rules         []*ngmodels.AlertRule
+	correlations  []correlations.Correlation
+}
+
+type builder struct {
+	graph *Graph
+	// datasourceNames maps the names of the data sources to their UIDs, old dashboards reference
+	// data sources by name.
+	datasourceNames map[string]string
+	edges           map[Edge]bool
+}
+
+func buildGraph(orgID int64, src sources, now time.Time) *Graph {
+	b := &builder{
+		graph:           &Graph{OrgID: orgID, Built: now, nodes: map[string]*Node{}},
+		datasourceNames: map[string]string{},
+		edges:           map[Edge]bool{},
+	}
+
+	for _, ds := range src.datasources {
+		b.addNode(KindDatasource, ds.UID, ds.Name)
+		b.datasourceNames[ds.Name] = ds.UID
+	}
+	dashboardsByID := map[int64]*dashboards.Dashboard{}
+	for _, dash := range src.dashboards {
+		if dash.IsFolder {
+			continue
+		}
+		b.addNode(KindDashboard, dash.UID, dash.Title)
+		dashboardsByID[dash.ID] = dash
+	}
+	for _, p := range src.libraryPanels {
+		b.addNode(KindLibraryPanel, p.UID, p.Name)
+	}
+	for _, r := range src.rules {
+		b.addNode(KindAlertRule, r.UID, r.Title)
+	}
+	for _, c := range src.correlations {
+		b.addNode(KindCorrelation, c.UID, c.Label)
+	}
+
+	for _, dash := range dashboardsByID {
+		b.addDashboardEdges(dash, src.dashboards)
+	}
+	for _, p := range src.libraryPanels {
+		from := nodeID(KindLibraryPanel, p.UID)
+		var model any
+		if err := json.Unmarshal(p.Model, &model); err == nil {
+			b.addDatasourceEdges(from, model)
+		}
+		for _, id := range p.DashboardIDs {
+			if dash, ok := dashboardsByID[id]; ok {
+				b.addEdge(nodeID(KindDashboard, dash.UID), from, EdgeUses)
+			}
+		}
+	}
+	for _, r := range src.rules {
+		from := nodeID(KindAlertRule, r.UID)
+		for _, q := range r.Data {
+			if isExpr, err := q.IsExpression(); err != nil || isExpr {
+				continue
+			}
+			b.addEdge(from, b.datasource(datasourceRef{value: q.DatasourceUID}), EdgeUses)
+		}
+		if r.DashboardUID != nil && *r.DashboardUID != "" {
+			b.addEdge(from, b.node(KindDashboard, *r.DashboardUID), EdgeLinks)
+		}
+	}
+	for _, c := range src.correlations {
+		from := nodeID(KindCorrelation, c.UID)
+		b.addEdge(from, b.datasource(datasourceRef{value: c.SourceUID}), EdgeUses)
+		if c.TargetUID != nil && *c.TargetUID != "" {
+			b.addEdge(from, b.datasource(datasourceRef{value: *c.TargetUID}), EdgeUses)
+		}
+	}
+
+	return b.finish()
+}
+
+func (b *builder) addDashboardEdges(dash *dashboards.Dashboard, all []*dashboards.Dashboard) {
+	if dash.Data == nil {
+		return
+	}
+	from := nodeID(KindDashboard, dash.UID)
+	model := dash.Data.Interface()
+	b.addDatasourceEdges(from, model)
+
+	walk(model, func(key string, value any) {
+		switch key {
+		case "libraryPanel":
+			if ref, ok := value.(map[string]any); ok {
+				if uid, ok := ref["uid"].(string); ok && uid != "" {
+					b.addEdge(from, b.node(KindLibraryPanel, uid), EdgeUses)
+				}
+			}
+		case "url":
+			if url, ok := value.(string); ok {
+				if m := dashboardURL.FindStringSubmatch(url); m != nil && m[1] != dash.UID {
+					b.addEdge(from, b.node(KindDashboard, m[1]), EdgeLinks)
+				}
+			}
+		}
+	})
+
+	// Links of the dashboards type list the dashboards having all the tags of the link.
+	for _, link := range dash.Data.Get("links").MustArray() {
+		l, ok := link.(map[string]any)
+		if !ok || l["type"] != "dashboards" {
+			continue
+		}
+		tags := toStrings(l["tags"])
+		if len(tags) == 0 {
+			continue
+		}
+		for _, other := range all {
+			if other.IsFolder || other.UID == dash.UID || other.Data == nil {
+				continue
+			}
+			if containsAll(other.Data.Get("tags").MustStringArray(), tags) {
+				b.addEdge(from, nodeID(KindDashboard, other.UID), EdgeLinks)
+			}
+		}
+	}
+}
+
+// addDatasourceEdges adds the data sources of the panels, queries, variables and annotations of a
+// model. Template variables and built-in data sources are skipped.
+func (b *builder) addDatasourceEdges(from string, model any) {
+	walk(model, func(key string, value any) {
+		if key != "datasource" {
+			return
+		}
+		var ref datasourceRef
+		switch ds := value.(type) {
+		case string:
+			ref = datasourceRef{value: ds, legacy: true}
+		case map[string]any:
+			ref.value, _ = ds["uid"].(string)
+		}
+		if ref.value == "" || strings.HasPrefix(ref.value, "$") || builtInDatasources[ref.value] {
+			return
+		}
+		b.addEdge(from, b.datasource(ref), EdgeUses)
+	})
+}
+
+type datasourceRef struct {
+	value string
+	// legacy is set for references that are plain strings, they may be a name or a UID
+	legacy bool
+}
+
+// datasource returns the node of a data source reference.
+func (b *builder) datasource(ref datasourceRef) string {
+	id := nodeID(KindDatasource, ref.value)
+	if _, ok := b.graph.nodes[id]; ok || !ref.legacy {
+		return b.node(KindDatasource, ref.value)
+	}
+	if uid, ok := b.datasourceNames[ref.value]; ok {
+		return nodeID(KindDatasource, uid)
+	}
+	return b.node(KindDatasource, ref.value)
+}
+
+// node returns the node of a resource, adding a missing node when it doesn't exist.
+func (b *builder) node(kind Kind, uid string) string {
+	id := nodeID(kind, uid)
+	if _, ok := b.graph.nodes[id]; !ok {
+		b.graph.nodes[id] = &Node{ID: id, Kind: kind, UID: uid, Title: uid, Missing: true}
+	}
+	return id
+}
+
+func (b *builder) addNode(kind Kind, uid, title string) {
+	id := nodeID(kind, uid)
+	b.graph.nodes[id] = &Node{ID: id, Kind: kind, UID: uid, Title: title}
+}
+
+func (b *builder) addEdge(from, to string, edgeType EdgeType) {
+	e := Edge{From: from, To: to, Type: edgeType}
+	// A resource both using and linking to another one only uses it.
+	if b.edges[e] || (edgeType == EdgeLinks && b.edges[Edge{From: from, To: to, Type: EdgeUses}]) {
+		return
+	}
+	if edgeType == EdgeUses {
+		delete(b.edges, Edge{From: from, To: to, Type: EdgeLinks})
+	}
+	b.edges[e] = true
+}
+
+func (b *builder) finish() *Graph {
+	g := b.graph
+	g.Nodes = make([]*Node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		g.Nodes = append(g.Nodes, n)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+
+	g.Edges = make([]*Edge, 0, len(b.edges))
+	for e := range b.edges {
+		g.Edges = append(g.Edges, &e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	g.dependencies = map[string][]*Edge{}
+	g.dependents = map[string][]*Edge{}
+	for _, e := range g.Edges {
+		g.dependencies[e.From] = append(g.dependencies[e.From], e)
+		g.dependents[e.To] = append(g.dependents[e.To], e)
+	}
+	return g
+}
+
+// Impact returns the dependencies and the dependents of a resource. Dependents are followed through
+// the resources they break, resources only linking to a deleted resource keep working so their own
+// dependents aren't impacted.
+func (g *Graph) Impact(kind Kind, uid string) (*Impact, error) {
+	if !kind.valid() {
+		return nil, ErrUnknownKind.Errorf("unknown kind %q", kind)
+	}
+	node, ok := g.nodes[nodeID(kind, uid)]
+	if !ok {
+		return nil, ErrNodeNotFound.Errorf("%s %s not found", kind, uid)
+	}
+
+	impact := &Impact{Node: node, Dependencies: []*Node{}, Dependents: []*Node{}, Impacted: []*Node{}}
+	for _, e := range g.dependencies[node.ID] {
+		impact.Dependencies = append(impact.Dependencies, g.nodes[e.To])
+	}
+	for _, e := range g.dependents[node.ID] {
+		impact.Dependents = append(impact.Dependents, g.nodes[e.From])
+	}
+
+	visited := map[string]bool{node.ID: true}
+	queue := []string{node.ID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range g.dependents[current] {
+			if visited[e.From] {
+				continue
+			}
+			visited[e.From] = true
+			impact.Impacted = append(impact.Impacted, g.nodes[e.From])
+			if e.Type == EdgeUses {
+				queue = append(queue, e.From)
+			}
+		}
+	}
+	sort.Slice(impact.Impacted, func(i, j int) bool { return impact.Impacted[i].ID < impact.Impacted[j].ID })
+
+	return impact, nil
+}
+
+// walk calls fn with every key and value of the objects nested in v.
+func walk(v any, fn func(key string, value any)) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			fn(k, child)
+			walk(child, fn)
+		}
+	case []any:
+		for _, child := range val {
+			walk(child, fn)
+		}
+	}
+}
+
+func toStrings(v any) []string {
+	values, _ := v.([]any)
+	res := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok && s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func containsAll(values []string, required []string) bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	for _, r := range required {
+		if !set[r] {
+			return false
+		}
+	}
+	return true
+}
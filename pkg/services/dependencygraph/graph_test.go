@@ -0,0 +1,131 @@
+package dependencygraph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestBuildGraph(t *testing.T) {
+	g := buildGraph(1, testSources(t), time.Now())
+
+	assert.ElementsMatch(t, []Edge{
+		{From: "dashboard/checkout", To: "datasource/prom", Type: EdgeUses},
+		{From: "dashboard/checkout", To: "datasource/loki", Type: EdgeUses},
+		{From: "dashboard/checkout", To: "datasource/deleted", Type: EdgeUses},
+		{From: "dashboard/checkout", To: "library_panel/errors", Type: EdgeUses},
+		{From: "dashboard/checkout", To: "dashboard/payments", Type: EdgeLinks},
+		{From: "dashboard/overview", To: "dashboard/checkout", Type: EdgeLinks},
+		{From: "dashboard/overview", To: "dashboard/payments", Type: EdgeLinks},
+		{From: "library_panel/errors", To: "datasource/loki", Type: EdgeUses},
+		{From: "alert_rule/high-latency", To: "datasource/prom", Type: EdgeUses},
+		{From: "alert_rule/high-latency", To: "dashboard/checkout", Type: EdgeLinks},
+		{From: "correlation/logs-to-metrics", To: "datasource/loki", Type: EdgeUses},
+		{From: "correlation/logs-to-metrics", To: "datasource/prom", Type: EdgeUses},
+	}, edges(g))
+
+	t.Run("references to resources that don't exist are missing nodes", func(t *testing.T) {
+		assert.True(t, g.nodes["datasource/deleted"].Missing)
+		assert.False(t, g.nodes["datasource/prom"].Missing)
+	})
+
+	t.Run("folders, built-in data sources and variables aren't nodes", func(t *testing.T) {
+		for _, id := range []string{"dashboard/folder", "datasource/-- Grafana --", "datasource/$ds", "datasource/__expr__"} {
+			assert.NotContains(t, g.nodes, id)
+		}
+	})
+}
+
+func TestGraph_Impact(t *testing.T) {
+	g := buildGraph(1, testSources(t), time.Now())
+
+	impact, err := g.Impact(KindDatasource, "loki")
+	require.NoError(t, err)
+	assert.Empty(t, impact.Dependencies)
+	assert.Equal(t, []string{"correlation/logs-to-metrics", "dashboard/checkout", "library_panel/errors"}, ids(impact.Dependents))
+	// The overview and the rule only link to the checkout dashboard, they keep working without it.
+	assert.Equal(t, []string{"alert_rule/high-latency", "correlation/logs-to-metrics", "dashboard/checkout", "dashboard/overview", "library_panel/errors"}, ids(impact.Impacted))
+
+	impact, err = g.Impact(KindDashboard, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dashboard/payments", "datasource/deleted", "datasource/loki", "datasource/prom", "library_panel/errors"}, ids(impact.Dependencies))
+	assert.Equal(t, []string{"alert_rule/high-latency", "dashboard/overview"}, ids(impact.Impacted))
+
+	_, err = g.Impact(KindDashboard, "unknown")
+	assert.ErrorIs(t, err, ErrNodeNotFound)
+	_, err = g.Impact("folder", "checkout")
+	assert.ErrorIs(t, err, ErrUnknownKind)
+}
+
+func testSources(t *testing.T) sources {
+	t.Helper()
+
+	return sources{
+		datasources: []*datasources.DataSource{
+			{UID: "prom", Name: "Prometheus"},
+			{UID: "loki", Name: "Loki"},
+		},
+		dashboards: []*dashboards.Dashboard{
+			{ID: 1, UID: "folder", Title: "Shop", IsFolder: true},
+			testDashboard(t, 2, "checkout", `{
+				"tags": ["shop", "checkout"],
+				"links": [{"type": "link", "url": "/d/payments/payments?orgId=1"}],
+				"templating": {"list": [{"type": "datasource", "name": "ds"}, {"type": "query", "datasource": "Loki"}]},
+				"panels": [
+					{"datasource": {"uid": "prom", "type": "prometheus"}, "targets": [{"datasource": {"uid": "$ds"}}]},
+					{"datasource": {"uid": "-- Mixed --"}, "targets": [{"datasource": {"uid": "deleted"}}, {"datasource": {"uid": "-- Grafana --"}}]},
+					{"libraryPanel": {"uid": "errors", "name": "Errors"}}
+				]
+			}`),
+			testDashboard(t, 3, "payments", `{"tags": ["shop"], "panels": []}`),
+			testDashboard(t, 4, "overview", `{"links": [{"type": "dashboards", "tags": ["shop"]}]}`),
+		},
+		libraryPanels: []*libraryPanel{
+			{ID: 1, UID: "errors", Name: "Errors", Model: []byte(`{"datasource": {"uid": "loki"}}`), DashboardIDs: []int64{2}},
+		},
+		rules: []*ngmodels.AlertRule{{
+			UID:          "high-latency",
+			Title:        "High latency",
+			DashboardUID: util.Pointer("checkout"),
+			Data: []ngmodels.AlertQuery{
+				{RefID: "A", DatasourceUID: "prom"},
+				{RefID: "B", DatasourceUID: "__expr__"},
+			},
+		}},
+		correlations: []correlations.Correlation{
+			{UID: "logs-to-metrics", Label: "Logs to metrics", SourceUID: "loki", TargetUID: util.Pointer("prom")},
+		},
+	}
+}
+
+func testDashboard(t *testing.T, id int64, uid string, model string) *dashboards.Dashboard {
+	t.Helper()
+	data, err := simplejson.NewJson([]byte(model))
+	require.NoError(t, err)
+	return &dashboards.Dashboard{ID: id, UID: uid, Title: uid, Data: data}
+}
+
+func edges(g *Graph) []Edge {
+	res := make([]Edge, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		res = append(res, *e)
+	}
+	return res
+}
+
+func ids(nodes []*Node) []string {
+	res := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, n.ID)
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package dependencygraph
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrUnknownKind  = errutil.BadRequest("dependencygraph.unknown-kind")
+	ErrNodeNotFound = errutil.NotFound("dependencygraph.node-not-found", errutil.WithPublicMessage("Resource not found in the dependency graph"))
+)
+
+// Kind is the kind of resource a node of the graph represents.
+type Kind string
+
+const (
+	KindDashboard    Kind = "dashboard"
+	KindDatasource   Kind = "datasource"
+	KindLibraryPanel Kind = "library_panel"
+	KindAlertRule    Kind = "alert_rule"
+	KindCorrelation  Kind = "correlation"
+)
+
+func (k Kind) valid() bool {
+	switch k {
+	case KindDashboard, KindDatasource, KindLibraryPanel, KindAlertRule, KindCorrelation:
+		return true
+	}
+	return false
+}
+
+// EdgeType tells how a resource depends on another one.
+type EdgeType string
+
+const (
+	// EdgeUses is set when the resource stops working without its dependency, for example a
+	// dashboard querying a data source.
+	EdgeUses EdgeType = "uses"
+	// EdgeLinks is set when the resource only links to its dependency, for example a dashboard
+	// link. Deleting the dependency breaks the link but not the resource.
+	EdgeLinks EdgeType = "links"
+)
+
+type Node struct {
+	// ID identifies the node in the graph, it is the kind and the UID of the resource separated by a slash.
+	ID    string `json:"id"`
+	Kind  Kind   `json:"kind"`
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+	// Missing is set for resources that are referenced but don't exist, their dependents are already broken.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// Edge goes from a resource to one of its dependencies.
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Type EdgeType `json:"type"`
+}
+
+type Graph struct {
+	OrgID int64     `json:"orgId"`
+	Built time.Time `json:"built"`
+	Nodes []*Node   `json:"nodes"`
+	Edges []*Edge   `json:"edges"`
+
+	nodes        map[string]*Node
+	dependencies map[string][]*Edge
+	dependents   map[string][]*Edge
+}
+
+// Impact describes the dependencies of a resource and what breaks if it is deleted.
+type Impact struct {
+	Node *Node `json:"node"`
+	// Dependencies are the resources the node directly depends on.
+	Dependencies []*Node `json:"dependencies"`
+	// Dependents are the resources directly depending on the node.
+	Dependents []*Node `json:"dependents"`
+	// Impacted are all the resources that break when the node is deleted, following the dependents
+	// of the dependents.
+	Impacted []*Node `json:"impacted"`
+}
+
+func nodeID(kind Kind, uid string) string {
+	return string(kind) + "/" + uid
+}
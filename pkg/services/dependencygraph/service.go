@@ -0,0 +1,156 @@
+package dependencygraph
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// cacheTTL is how long the graph of an organization is served before being rebuilt.
+	cacheTTL = 5 * time.Minute
+
+	correlationsPageSize = 1000
+)
+
+type ruleStore interface {
+	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+}
+
+type correlationStore interface {
+	GetCorrelations(ctx context.Context, query correlations.GetCorrelationsQuery) (correlations.GetCorrelationsResponseBody, error)
+}
+
+// Service builds the graph of the dependencies between the dashboards, data sources, library panels,
+// alert rules and correlations of an organization. Graphs are cached as building them reads every
+// dashboard of the organization.
+type Service struct {
+	log   log.Logger
+	store store
+
+	accessControl     accesscontrol.AccessControl
+	dashboardService  dashboards.DashboardService
+	datasourceService datasources.DataSourceService
+	ruleStore         ruleStore
+	correlationStore  correlationStore
+
+	now    func() time.Time
+	builds singleflight.Group
+	mu     sync.RWMutex
+	graphs map[int64]*Graph
+}
+
+func ProvideService(
+	sqlStore db.DB, routeRegister routing.RouteRegister, accessControl accesscontrol.AccessControl,
+	dashboardService dashboards.DashboardService, datasourceService datasources.DataSourceService,
+	ruleStore *ngstore.DBstore, correlationsService *correlations.CorrelationsService,
+) *Service {
+	s := &Service{
+		log:               log.New("dependencygraph"),
+		store:             &xormStore{db: sqlStore},
+		accessControl:     accessControl,
+		dashboardService:  dashboardService,
+		datasourceService: datasourceService,
+		ruleStore:         ruleStore,
+		correlationStore:  correlationsService,
+		now:               time.Now,
+		graphs:            map[int64]*Graph{},
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+// GetGraph returns the dependency graph of an organization, from the cache unless it is older than
+// the cache TTL or refresh is set.
+func (s *Service) GetGraph(ctx context.Context, orgID int64, refresh bool) (*Graph, error) {
+	if !refresh {
+		s.mu.RLock()
+		g, ok := s.graphs[orgID]
+		s.mu.RUnlock()
+		if ok && s.now().Sub(g.Built) < cacheTTL {
+			return g, nil
+		}
+	}
+
+	// Concurrent requests for the same organization share a single build.
+	g, err, _ := s.builds.Do(strconv.FormatInt(orgID, 10), func() (any, error) {
+		g, err := s.build(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.graphs[orgID] = g
+		s.mu.Unlock()
+		return g, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g.(*Graph), nil
+}
+
+// GetImpact returns the dependencies of a resource and the resources that break when it is deleted.
+func (s *Service) GetImpact(ctx context.Context, orgID int64, kind Kind, uid string, refresh bool) (*Impact, error) {
+	if !kind.valid() {
+		return nil, ErrUnknownKind.Errorf("unknown kind %q", kind)
+	}
+	g, err := s.GetGraph(ctx, orgID, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return g.Impact(kind, uid)
+}
+
+func (s *Service) build(ctx context.Context, orgID int64) (*Graph, error) {
+	start := s.now()
+	ctx = identity.WithServiceIdentityContext(ctx, orgID)
+
+	var src sources
+	var err error
+	src.datasources, err = s.datasourceService.GetDataSources(ctx, &datasources.GetDataSourcesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	src.dashboards, err = s.dashboardService.GetAllDashboardsByOrgId(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	src.libraryPanels, err = s.store.ListLibraryPanels(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	src.rules, err = s.ruleStore.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	for page := int64(1); ; page++ {
+		res, err := s.correlationStore.GetCorrelations(ctx, correlations.GetCorrelationsQuery{OrgId: orgID, Limit: correlationsPageSize, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		src.correlations = append(src.correlations, res.Correlations...)
+		if len(res.Correlations) < correlationsPageSize {
+			break
+		}
+	}
+
+	g := buildGraph(orgID, src, s.now())
+	s.log.FromContext(ctx).Debug("Built dependency graph", "orgID", orgID, "nodes", len(g.Nodes), "edges", len(g.Edges), "duration", s.now().Sub(start))
+	return g, nil
+}
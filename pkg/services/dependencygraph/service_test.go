@@ -0,0 +1,110 @@
+package dependencygraph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationDependencyGraph(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	now := time.Now()
+
+	err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		elements := []*model.LibraryElement{
+			{OrgID: 1, UID: "errors", Name: "Errors", Kind: int64(model.PanelElement), Model: []byte(`{"datasource": {"uid": "loki"}}`), Created: now, Updated: now},
+			{OrgID: 2, UID: "other-org", Name: "Errors", Kind: int64(model.PanelElement), Model: []byte(`{}`), Created: now, Updated: now},
+		}
+		for _, e := range elements {
+			if _, err := sess.Insert(e); err != nil {
+				return err
+			}
+		}
+		_, err := sess.Insert(&model.LibraryElementConnection{ElementID: elements[0].ID, Kind: 1, ConnectionID: 2, Created: now})
+		return err
+	})
+	require.NoError(t, err)
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetAllDashboardsByOrgId", mock.Anything, int64(1)).Return([]*dashboards.Dashboard{
+		testDashboard(t, 2, "checkout", `{"panels": [{"datasource": {"uid": "prom"}}]}`),
+	}, nil)
+	rules := &fakeRuleStore{}
+	s := &Service{
+		log:               log.NewNopLogger(),
+		store:             &xormStore{db: sqlStore},
+		dashboardService:  dashboardService,
+		datasourceService: &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{{OrgID: 1, UID: "prom"}, {OrgID: 1, UID: "loki"}}},
+		ruleStore:         rules,
+		correlationStore:  &fakeCorrelationStore{},
+		now:               func() time.Time { return now },
+		graphs:            map[int64]*Graph{},
+	}
+
+	impact, err := s.GetImpact(ctx, 1, KindDatasource, "loki", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"library_panel/errors"}, ids(impact.Dependents))
+	assert.Equal(t, []string{"dashboard/checkout", "library_panel/errors"}, ids(impact.Impacted))
+	assert.Equal(t, 1, rules.calls)
+
+	t.Run("graphs are cached", func(t *testing.T) {
+		_, err := s.GetGraph(ctx, 1, false)
+		require.NoError(t, err)
+		assert.Equal(t, 1, rules.calls)
+
+		_, err = s.GetGraph(ctx, 1, true)
+		require.NoError(t, err)
+		assert.Equal(t, 2, rules.calls)
+
+		now = now.Add(cacheTTL)
+		_, err = s.GetGraph(ctx, 1, false)
+		require.NoError(t, err)
+		assert.Equal(t, 3, rules.calls)
+	})
+
+	t.Run("unknown kinds are rejected", func(t *testing.T) {
+		_, err := s.GetImpact(ctx, 1, "folder", "checkout", false)
+		assert.ErrorIs(t, err, ErrUnknownKind)
+	})
+}
+
+type fakeRuleStore struct {
+	rules ngmodels.RulesGroup
+	calls int
+}
+
+func (f *fakeRuleStore) ListAlertRules(_ context.Context, _ *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error) {
+	f.calls++
+	return f.rules, nil
+}
+
+type fakeCorrelationStore struct {
+	correlations []correlations.Correlation
+}
+
+func (f *fakeCorrelationStore) GetCorrelations(_ context.Context, _ correlations.GetCorrelationsQuery) (correlations.GetCorrelationsResponseBody, error) {
+	return correlations.GetCorrelationsResponseBody{Correlations: f.correlations}, nil
+}
@@ -0,0 +1,52 @@
+package dependencygraph
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+)
+
+type store interface {
+	// ListLibraryPanels returns the library panels of an organization with the dashboards they are connected to.
+	ListLibraryPanels(ctx context.Context, orgID int64) ([]*libraryPanel, error)
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) ListLibraryPanels(ctx context.Context, orgID int64) ([]*libraryPanel, error) {
+	panels := make([]*libraryPanel, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		err := sess.Table("library_element").Cols("id", "uid", "name", "model").
+			Where("org_id = ? AND kind = ?", orgID, int64(model.PanelElement)).Find(&panels)
+		if err != nil {
+			return err
+		}
+
+		var connections []struct {
+			ElementID    int64 `xorm:"'element_id'"`
+			ConnectionID int64 `xorm:"'connection_id'"`
+		}
+		err = sess.Table("library_element_connection").
+			Select("library_element_connection.element_id, library_element_connection.connection_id").
+			Join("INNER", "library_element", "library_element.id = library_element_connection.element_id").
+			Where("library_element.org_id = ?", orgID).Find(&connections)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[int64]*libraryPanel, len(panels))
+		for _, p := range panels {
+			byID[p.ID] = p
+		}
+		for _, c := range connections {
+			if p, ok := byID[c.ElementID]; ok {
+				p.DashboardIDs = append(p.DashboardIDs, c.ConnectionID)
+			}
+		}
+		return nil
+	})
+	return panels, err
+}
@@ -1816,6 +1816,13 @@ var (
 			FrontendOnly: true,
 			HideFromDocs: true,
 		},
+		{
+			Name:            "samlAuthnClient",
+			Description:     "Enables the SAML authentication client of the open source edition",
+			Stage:           FeatureStageExperimental,
+			Owner:           identityAccessTeam,
+			RequiresRestart: true,
+		},
 	}
 )
 
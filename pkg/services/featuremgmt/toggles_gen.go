@@ -974,4 +974,8 @@ const (
 	// FlagAlertingJiraIntegration
 	// Enables the new Jira integration for contact points in cloud alert managers.
 	FlagAlertingJiraIntegration = "alertingJiraIntegration"
+
+	// FlagSamlAuthnClient
+	// Enables the SAML authentication client of the open source edition
+	FlagSamlAuthnClient = "samlAuthnClient"
 )
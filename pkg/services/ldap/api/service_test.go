@@ -61,6 +61,10 @@ func (m *LDAPMock) User(login string) (*login.ExternalUserInfo, ldap.ServerConfi
 	return m.UserSearchResult, m.UserSearchConfig, m.UserSearchError
 }
 
+func (m *LDAPMock) AllUsers() ([]*login.ExternalUserInfo, error) {
+	return m.Results, nil
+}
+
 func setupAPITest(t *testing.T, opts ...func(a *Service)) (*Service, *webtest.Server) {
 	t.Helper()
 	router := routing.NewRouteRegister()
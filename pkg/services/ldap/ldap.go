@@ -36,6 +36,7 @@ type IConnection interface {
 type IServer interface {
 	Login(*login.LoginUserQuery) (*login.ExternalUserInfo, error)
 	Users([]string) ([]*login.ExternalUserInfo, error)
+	AllUsers() ([]*login.ExternalUserInfo, error)
 	Bind() error
 	UserBind(string, string) error
 	Dial() error
@@ -75,6 +76,10 @@ func (server *Server) Bind() error {
 // on how much items can we return in one request
 const UsersMaxRequest = 500
 
+// matchingRuleInChain is the Active Directory extensible match rule
+// walking the chain of ancestry of nested groups.
+const matchingRuleInChain = "1.2.840.113556.1.4.1941"
+
 var (
 
 	// ErrInvalidCredentials is returned if username and password do not match
@@ -299,6 +304,65 @@ func (server *Server) Users(logins []string) (
 	return serializedUsers, nil
 }
 
+// AllUsers gets every user matching the search filter of the server, the
+// directory is paged through in requests of `sync_page_size` entries.
+// Dial() sets the connection with the server for this Struct. Therefore, we require a
+// call to Dial() before being able to execute this function.
+func (server *Server) AllUsers() ([]*login.ExternalUserInfo, error) {
+	pageSize := uint32(UsersMaxRequest)
+	if server.cfg != nil && server.cfg.SyncPageSize > 0 {
+		pageSize = uint32(server.cfg.SyncPageSize)
+	}
+
+	entries := make([][]*ldap.Entry, 0, len(server.Config.SearchBaseDNs))
+	for _, base := range server.Config.SearchBaseDNs {
+		request := &ldap.SearchRequest{
+			BaseDN:       base,
+			Scope:        ldap.ScopeWholeSubtree,
+			DerefAliases: ldap.NeverDerefAliases,
+			Attributes:   server.getSearchAttributes(),
+			Filter:       strings.ReplaceAll(server.Config.SearchFilter, "%s", "*"),
+		}
+
+		result, err := server.pagedSearch(request, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result) > 0 {
+			entries = append(entries, result)
+		}
+	}
+
+	if len(entries) == 0 {
+		return []*login.ExternalUserInfo{}, nil
+	}
+
+	return server.serializeUsers(entries)
+}
+
+// pagedSearch runs the search with the simple paged results control
+// until the server stops returning a cookie.
+func (server *Server) pagedSearch(request *ldap.SearchRequest, pageSize uint32) ([]*ldap.Entry, error) {
+	paging := ldap.NewControlPaging(pageSize)
+	request.Controls = append(request.Controls, paging)
+
+	var entries []*ldap.Entry
+	for {
+		result, err := server.Connection.Search(request)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, result.Entries...)
+
+		control, ok := ldap.FindControl(result.Controls, ldap.ControlTypePaging).(*ldap.ControlPaging)
+		if !ok || len(control.Cookie) == 0 {
+			return entries, nil
+		}
+		paging.SetCookie(control.Cookie)
+	}
+}
+
 func getRootCACertPool(config ServerConfig) (*x509.CertPool, error) {
 	var pemCerts [][]byte
 
@@ -431,20 +495,7 @@ func (server *Server) getSearchRequest(
 	base string,
 	logins []string,
 ) *ldap.SearchRequest {
-	attributes := []string{}
-
-	inputs := server.Config.Attr
-	attributes = appendIfNotEmpty(
-		attributes,
-		inputs.Username,
-		inputs.Surname,
-		inputs.Email,
-		inputs.Name,
-		inputs.MemberOf,
-
-		// In case for the POSIX LDAP schema server
-		server.Config.GroupSearchFilterUserAttribute,
-	)
+	attributes := server.getSearchAttributes()
 
 	search := ""
 	for _, login := range logins {
@@ -473,6 +524,22 @@ func (server *Server) getSearchRequest(
 	return searchRequest
 }
 
+// getSearchAttributes returns the user attributes requested from LDAP
+func (server *Server) getSearchAttributes() []string {
+	inputs := server.Config.Attr
+	return appendIfNotEmpty(
+		[]string{},
+		inputs.Username,
+		inputs.Surname,
+		inputs.Email,
+		inputs.Name,
+		inputs.MemberOf,
+
+		// In case for the POSIX LDAP schema server
+		server.Config.GroupSearchFilterUserAttribute,
+	)
+}
+
 // buildGrafanaUser extracts info from UserInfo model to ExternalUserInfo
 func (server *Server) buildGrafanaUser(user *ldap.Entry) (*login.ExternalUserInfo, error) {
 	memberOf, err := server.getMemberOf(user)
@@ -644,6 +711,46 @@ func (server *Server) requestMemberOf(entry *ldap.Entry) ([]string, error) {
 	return memberOf, nil
 }
 
+// requestNestedMemberOf finds all the groups of the user, including the groups
+// the user is a member of through other groups. Only Active Directory implements
+// the LDAP_MATCHING_RULE_IN_CHAIN rule used here.
+func (server *Server) requestNestedMemberOf(entry *ldap.Entry) ([]string, error) {
+	var memberOf []string
+	var config = server.Config
+	var searchBaseDNs []string
+
+	if len(config.GroupSearchBaseDNs) > 0 {
+		searchBaseDNs = config.GroupSearchBaseDNs
+	} else {
+		searchBaseDNs = config.SearchBaseDNs
+	}
+
+	filter := fmt.Sprintf("(member:%s:=%s)", matchingRuleInChain, ldap.EscapeFilter(entry.DN))
+	server.log.Debug("Searching for user's nested groups", "filter", filter)
+
+	for _, groupSearchBase := range searchBaseDNs {
+		groupSearchReq := ldap.SearchRequest{
+			BaseDN:       groupSearchBase,
+			Scope:        ldap.ScopeWholeSubtree,
+			DerefAliases: ldap.NeverDerefAliases,
+			// "1.1" requests no attributes, only the DN of the groups is needed
+			Attributes: []string{"1.1"},
+			Filter:     filter,
+		}
+
+		groupSearchResult, err := server.Connection.Search(&groupSearchReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range groupSearchResult.Entries {
+			memberOf = append(memberOf, group.DN)
+		}
+	}
+
+	return memberOf, nil
+}
+
 // serializeUsers serializes the users
 // from LDAP result to ExternalInfo struct
 func (server *Server) serializeUsers(
@@ -676,6 +783,10 @@ func (server *Server) serializeUsers(
 func (server *Server) getMemberOf(result *ldap.Entry) (
 	[]string, error,
 ) {
+	if server.Config.NestedGroups {
+		return server.requestNestedMemberOf(result)
+	}
+
 	if server.Config.GroupSearchFilter == "" {
 		memberOf := getArrayAttribute(server.Config.Attr.MemberOf, result)
 
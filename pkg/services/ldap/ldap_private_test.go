@@ -281,3 +281,97 @@ func TestServer_binds(t *testing.T) {
 		assert.False(t, server.shouldAdminBind())
 	})
 }
+
+func TestServer_AllUsers(t *testing.T) {
+	conn := &MockConnection{}
+	pages := [][]*ldap.Entry{
+		{userEntry("cn=one,dc=grafana,dc=org", "one"), userEntry("cn=two,dc=grafana,dc=org", "two")},
+		{userEntry("cn=three,dc=grafana,dc=org", "three")},
+	}
+	var requests []*ldap.SearchRequest
+	conn.setSearchFunc(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+		requests = append(requests, request)
+		paging := ldap.FindControl(request.Controls, ldap.ControlTypePaging).(*ldap.ControlPaging)
+
+		page := 0
+		if len(paging.Cookie) > 0 {
+			page = int(paging.Cookie[0])
+		}
+		result := &ldap.SearchResult{Entries: pages[page]}
+		if page+1 < len(pages) {
+			result.Controls = []ldap.Control{&ldap.ControlPaging{Cookie: []byte{byte(page + 1)}}}
+		}
+		return result, nil
+	})
+
+	server := &Server{
+		cfg: &Config{Enabled: true, SyncPageSize: 2},
+		Config: &ServerConfig{
+			Attr:          AttributeMap{Username: "uid", MemberOf: "memberOf"},
+			SearchFilter:  "(uid=%s)",
+			SearchBaseDNs: []string{"dc=grafana,dc=org"},
+		},
+		Connection: conn,
+		log:        log.New("test-logger"),
+	}
+
+	users, err := server.AllUsers()
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	assert.Equal(t, "(uid=*)", requests[0].Filter)
+	assert.Equal(t, uint32(2), ldap.FindControl(requests[0].Controls, ldap.ControlTypePaging).(*ldap.ControlPaging).PagingSize)
+
+	logins := make([]string, 0, len(users))
+	for _, u := range users {
+		logins = append(logins, u.Login)
+	}
+	assert.Equal(t, []string{"one", "two", "three"}, logins)
+}
+
+func TestServer_NestedGroups(t *testing.T) {
+	conn := &MockConnection{}
+	conn.setSearchFunc(func(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+		if request.BaseDN == "ou=groups,dc=grafana,dc=org" {
+			assert.Equal(t, `(member:1.2.840.113556.1.4.1941:=cn=one\28contractor\29,dc=grafana,dc=org)`, request.Filter)
+			return &ldap.SearchResult{Entries: []*ldap.Entry{
+				{DN: "cn=developers,ou=groups,dc=grafana,dc=org"},
+				{DN: "cn=engineering,ou=groups,dc=grafana,dc=org"},
+			}}, nil
+		}
+		return &ldap.SearchResult{Entries: []*ldap.Entry{userEntry("cn=one(contractor),dc=grafana,dc=org", "one")}}, nil
+	})
+
+	server := &Server{
+		cfg: &Config{Enabled: true},
+		Config: &ServerConfig{
+			Attr:               AttributeMap{Username: "uid", MemberOf: "memberOf"},
+			SearchFilter:       "(uid=%s)",
+			SearchBaseDNs:      []string{"dc=grafana,dc=org"},
+			GroupSearchBaseDNs: []string{"ou=groups,dc=grafana,dc=org"},
+			NestedGroups:       true,
+			Groups: []*GroupToOrgRole{
+				{GroupDN: "cn=engineering,ou=groups,dc=grafana,dc=org", OrgId: 1, OrgRole: org.RoleEditor},
+			},
+		},
+		Connection: conn,
+		log:        log.New("test-logger"),
+	}
+
+	users, err := server.Users([]string{"one"})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+
+	assert.Equal(t, []string{"cn=developers,ou=groups,dc=grafana,dc=org", "cn=engineering,ou=groups,dc=grafana,dc=org"}, users[0].Groups)
+	assert.Equal(t, org.RoleEditor, users[0].OrgRoles[1])
+}
+
+func userEntry(dn, uid string) *ldap.Entry {
+	return &ldap.Entry{
+		DN: dn,
+		Attributes: []*ldap.EntryAttribute{
+			{Name: "uid", Values: []string{uid}},
+			{Name: "memberOf", Values: []string{"cn=direct,ou=groups,dc=grafana,dc=org"}},
+		},
+	}
+}
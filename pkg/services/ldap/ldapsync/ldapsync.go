@@ -0,0 +1,260 @@
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldap/service"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamsync"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	lockActionName = "ldap group sync"
+	searchPageSize = 500
+	// settingsCheckInterval is how often the schedule is read again from the LDAP settings.
+	settingsCheckInterval = time.Minute
+)
+
+var errEmptyDirectory = errors.New("the LDAP directory returned no users, refusing to disable every LDAP user")
+
+// Result summarizes a synchronization run.
+type Result struct {
+	Synced   int
+	Disabled int
+	Failed   int
+}
+
+// Service periodically synchronizes the org roles, team membership and the disabled status
+// of the users that signed in with LDAP, so changes in the directory don't wait for the next login.
+// The LDAP settings are read from the LDAP service on every run, as they can be changed at runtime.
+type Service struct {
+	cfg *setting.Cfg
+	log log.Logger
+
+	ldapService          service.LDAP
+	identitySynchronizer authn.IdentitySynchronizer
+	userService          user.Service
+	sessionService       auth.UserTokenService
+	teamSyncer           *teamsync.Syncer
+	serverLock           *serverlock.ServerLockService
+}
+
+func ProvideService(
+	cfg *setting.Cfg, ldapService service.LDAP, authnService authn.Service, identitySynchronizer authn.IdentitySynchronizer,
+	userService user.Service, sessionService auth.UserTokenService, teamService team.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService, serverLock *serverlock.ServerLockService,
+) *Service {
+	s := &Service{
+		cfg:                  cfg,
+		log:                  log.New("ldap.sync"),
+		ldapService:          ldapService,
+		identitySynchronizer: identitySynchronizer,
+		userService:          userService,
+		sessionService:       sessionService,
+		teamSyncer:           teamsync.NewSyncer("ldap", teamService, teamPermissionsService),
+		serverLock:           serverLock,
+	}
+
+	// The hook only handles LDAP identities, so it is registered even if LDAP is disabled for now.
+	authnService.RegisterPostAuthHook(s.syncTeamsHook, 40)
+
+	return s
+}
+
+// IsDisabled is always false as the background sync can be enabled at runtime, Run waits until it is.
+func (s *Service) IsDisabled() bool {
+	return false
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	var (
+		syncCron string
+		next     time.Time
+	)
+	for {
+		schedule, spec, err := s.schedule()
+		if err != nil && spec != syncCron {
+			s.log.Error("Invalid LDAP sync schedule, background sync is disabled", "sync_cron", spec, "error", err)
+		}
+
+		now := time.Now()
+		switch {
+		case schedule == nil:
+			next = time.Time{}
+		case spec != syncCron || next.IsZero():
+			next = schedule.Next(now)
+		case !now.Before(next):
+			// Only one instance syncs a given run, the lock is held for half of the interval between runs.
+			s.lockAndSync(ctx, schedule.Next(next).Sub(next)/2)
+			next = schedule.Next(time.Now())
+		}
+		syncCron = spec
+
+		wait := settingsCheckInterval
+		if !next.IsZero() && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// schedule returns the schedule of the background sync from the current LDAP settings along with its cron
+// expression, or nil if the background sync is disabled.
+func (s *Service) schedule() (cron.Schedule, string, error) {
+	settings := s.ldapService.Settings()
+	if settings == nil || !settings.Enabled || !settings.ActiveSyncEnabled {
+		return nil, "", nil
+	}
+	schedule, err := cron.ParseStandard(settings.SyncCron)
+	if err != nil {
+		return nil, settings.SyncCron, err
+	}
+	return schedule, settings.SyncCron, nil
+}
+
+func (s *Service) lockAndSync(ctx context.Context, maxInterval time.Duration) {
+	err := s.serverLock.LockAndExecute(ctx, lockActionName, maxInterval, func(ctx context.Context) {
+		start := time.Now()
+		result, err := s.Sync(ctx)
+		if err != nil {
+			s.log.Error("LDAP sync failed", "error", err)
+			return
+		}
+		s.log.Info("LDAP sync completed", "synced", result.Synced, "disabled", result.Disabled, "failed", result.Failed, "duration", time.Since(start))
+	})
+	if err != nil {
+		s.log.Error("Failed to acquire the LDAP sync lock", "error", err)
+	}
+}
+
+// Sync reconciles every Grafana user authenticated by LDAP with the directory. Users found in the
+// directory get their profile, org roles and teams updated, users missing from the directory or no
+// longer matching any group mapping are disabled and signed out.
+func (s *Service) Sync(ctx context.Context) (*Result, error) {
+	settings := s.ldapService.Settings()
+	if settings == nil || !settings.Enabled {
+		return nil, errors.New("LDAP is disabled")
+	}
+	client := s.ldapService.Client()
+	if client == nil {
+		return nil, errors.New("LDAP client is not configured")
+	}
+
+	entries, err := client.AllUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LDAP users: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, errEmptyDirectory
+	}
+
+	directory := make(map[string]*login.ExternalUserInfo, len(entries))
+	for _, entry := range entries {
+		directory[strings.ToLower(entry.Login)] = entry
+	}
+
+	requester := accesscontrol.BackgroundUser("ldap_sync", accesscontrol.GlobalOrgID, org.RoleNone, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionUsersRead, Scope: accesscontrol.ScopeGlobalUsersAll},
+	})
+
+	result := &Result{}
+	for page := 1; ; page++ {
+		res, err := s.userService.Search(ctx, &user.SearchUsersQuery{
+			SignedInUser: requester,
+			AuthModule:   login.LDAPAuthModule,
+			Page:         page,
+			Limit:        searchPageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search LDAP users: %w", err)
+		}
+
+		for _, hit := range res.Users {
+			if err := s.syncUser(ctx, settings, hit, directory[strings.ToLower(hit.Login)], result); err != nil {
+				s.log.Warn("Failed to sync LDAP user", "login", hit.Login, "error", err)
+				result.Failed++
+			}
+		}
+
+		if len(res.Users) < searchPageSize {
+			return result, nil
+		}
+	}
+}
+
+func (s *Service) syncUser(ctx context.Context, settings *ldap.Config, hit *user.UserSearchHitDTO, info *login.ExternalUserInfo, result *Result) error {
+	if info != nil && !info.IsDisabled {
+		if err := s.identitySynchronizer.SyncIdentity(ctx, identityFromLDAPInfo(settings, info)); err != nil {
+			return err
+		}
+		result.Synced++
+		return nil
+	}
+
+	if hit.Login == s.cfg.AdminUser {
+		s.log.Warn("Refusing to disable the Grafana super admin missing from LDAP", "login", hit.Login)
+		return nil
+	}
+
+	if hit.IsDisabled {
+		return nil
+	}
+
+	s.log.Info("Disabling user removed from LDAP", "login", hit.Login, "userID", hit.ID)
+	isDisabled := true
+	if err := s.userService.Update(ctx, &user.UpdateUserCommand{UserID: hit.ID, IsDisabled: &isDisabled}); err != nil {
+		return err
+	}
+	if err := s.sessionService.RevokeAllUserTokens(ctx, hit.ID); err != nil {
+		return err
+	}
+	result.Disabled++
+	return nil
+}
+
+func identityFromLDAPInfo(settings *ldap.Config, info *login.ExternalUserInfo) *authn.Identity {
+	return &authn.Identity{
+		OrgRoles:        info.OrgRoles,
+		Login:           info.Login,
+		Name:            info.Name,
+		Email:           info.Email,
+		IsGrafanaAdmin:  info.IsGrafanaAdmin,
+		AuthenticatedBy: info.AuthModule,
+		AuthID:          info.AuthId,
+		Groups:          info.Groups,
+		ClientParams: authn.ClientParams{
+			SyncUser:     true,
+			SyncTeams:    true,
+			EnableUser:   true,
+			SyncOrgRoles: !settings.SkipOrgRoleSync,
+			// The sync only updates existing users, users are created on their first login.
+			AllowSignUp: false,
+			LookUpParams: login.UserLookupParams{
+				Login: &info.Login,
+				Email: &info.Email,
+			},
+		},
+	}
+}
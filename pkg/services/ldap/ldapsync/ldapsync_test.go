@@ -0,0 +1,223 @@
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/ldap/multildap"
+	"github.com/grafana/grafana/pkg/services/ldap/service"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_Schedule(t *testing.T) {
+	tests := []struct {
+		desc     string
+		enabled  bool
+		active   bool
+		cron     string
+		disabled bool
+	}{
+		{desc: "LDAP disabled", enabled: false, active: true, cron: "0 1 * * *", disabled: true},
+		{desc: "active sync disabled", enabled: true, active: false, cron: "0 1 * * *", disabled: true},
+		{desc: "invalid schedule", enabled: true, active: true, cron: "every night", disabled: true},
+		{desc: "active sync enabled", enabled: true, active: true, cron: "0 1 * * *", disabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := setting.NewCfg()
+			cfg.LDAPAuthEnabled = tt.enabled
+			cfg.LDAPActiveSyncEnabled = tt.active
+			cfg.LDAPSyncCron = tt.cron
+
+			s := setupTestService(t, cfg, &fakeClient{})
+			schedule, _, _ := s.schedule()
+			assert.Equal(t, tt.disabled, schedule == nil)
+		})
+	}
+
+	t.Run("reads the settings again", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		s := setupTestService(t, cfg, &fakeClient{})
+		schedule, _, _ := s.schedule()
+		require.Nil(t, schedule)
+
+		s.ldapService.(*service.LDAPFakeService).ExpectedSettings = &ldap.Config{Enabled: true, ActiveSyncEnabled: true, SyncCron: "0 1 * * *"}
+		schedule, spec, err := s.schedule()
+		require.NoError(t, err)
+		require.NotNil(t, schedule)
+		assert.Equal(t, "0 1 * * *", spec)
+	})
+}
+
+func TestService_Sync(t *testing.T) {
+	admin := true
+	directory := []*login.ExternalUserInfo{
+		{Login: "alice", AuthModule: login.LDAPAuthModule, AuthId: "cn=alice", OrgRoles: map[int64]org.RoleType{1: org.RoleEditor}, IsGrafanaAdmin: &admin},
+		{Login: "Bob", AuthModule: login.LDAPAuthModule, AuthId: "cn=bob", OrgRoles: map[int64]org.RoleType{1: org.RoleViewer}},
+		{Login: "carol", AuthModule: login.LDAPAuthModule, AuthId: "cn=carol", IsDisabled: true},
+	}
+
+	t.Run("syncs users in the directory and disables the others", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.LDAPAuthEnabled = true
+		cfg.AdminUser = "admin"
+
+		s := setupTestService(t, cfg, &fakeClient{users: directory})
+		s.userService.(*usertest.FakeUserService).ExpectedSearchUsers = user.SearchUserQueryResult{Users: []*user.UserSearchHitDTO{
+			{ID: 1, Login: "alice"},
+			{ID: 2, Login: "bob", IsDisabled: true},
+			{ID: 3, Login: "carol"},
+			{ID: 4, Login: "dave"},
+			{ID: 5, Login: "erin", IsDisabled: true},
+			{ID: 6, Login: "admin"},
+		}}
+
+		var disabled []int64
+		s.userService.(*usertest.FakeUserService).UpdateFn = func(_ context.Context, cmd *user.UpdateUserCommand) error {
+			require.True(t, *cmd.IsDisabled)
+			disabled = append(disabled, cmd.UserID)
+			return nil
+		}
+		var revoked []int64
+		s.sessionService.(*authtest.FakeUserAuthTokenService).RevokeAllUserTokensProvider = func(_ context.Context, userID int64) error {
+			revoked = append(revoked, userID)
+			return nil
+		}
+
+		result, err := s.Sync(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &Result{Synced: 2, Disabled: 2}, result)
+
+		assert.Equal(t, []int64{3, 4}, disabled)
+		assert.Equal(t, []int64{3, 4}, revoked)
+
+		synced := s.identitySynchronizer.(*fakeSynchronizer).identities
+		require.Len(t, synced, 2)
+		assert.Equal(t, "alice", synced[0].Login)
+		assert.Equal(t, org.RoleEditor, synced[0].OrgRoles[1])
+		assert.True(t, *synced[0].IsGrafanaAdmin)
+		assert.True(t, synced[0].ClientParams.SyncTeams)
+		assert.False(t, synced[0].ClientParams.AllowSignUp)
+		// re-enables users added back to the directory
+		assert.Equal(t, "Bob", synced[1].Login)
+		assert.True(t, synced[1].ClientParams.EnableUser)
+	})
+
+	t.Run("counts failed users and carries on", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.LDAPAuthEnabled = true
+
+		s := setupTestService(t, cfg, &fakeClient{users: directory})
+		s.identitySynchronizer = &fakeSynchronizer{err: errors.New("sync failed")}
+		s.userService.(*usertest.FakeUserService).ExpectedSearchUsers = user.SearchUserQueryResult{Users: []*user.UserSearchHitDTO{
+			{ID: 1, Login: "alice"},
+			{ID: 2, Login: "bob"},
+		}}
+
+		result, err := s.Sync(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &Result{Failed: 2}, result)
+	})
+
+	t.Run("uses the current settings", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.LDAPAuthEnabled = true
+
+		s := setupTestService(t, cfg, &fakeClient{users: directory})
+		s.userService.(*usertest.FakeUserService).ExpectedSearchUsers = user.SearchUserQueryResult{Users: []*user.UserSearchHitDTO{{ID: 1, Login: "alice"}}}
+		s.ldapService.(*service.LDAPFakeService).ExpectedSettings.SkipOrgRoleSync = true
+
+		_, err := s.Sync(context.Background())
+		require.NoError(t, err)
+		synced := s.identitySynchronizer.(*fakeSynchronizer).identities
+		require.Len(t, synced, 1)
+		assert.False(t, synced[0].ClientParams.SyncOrgRoles)
+
+		s.ldapService.(*service.LDAPFakeService).ExpectedSettings.Enabled = false
+		_, err = s.Sync(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("does not disable anyone when the directory can't be listed", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.LDAPAuthEnabled = true
+
+		for _, client := range []*fakeClient{{err: errors.New("connection refused")}, {}} {
+			s := setupTestService(t, cfg, client)
+			s.userService.(*usertest.FakeUserService).ExpectedSearchUsers = user.SearchUserQueryResult{Users: []*user.UserSearchHitDTO{{ID: 1, Login: "alice"}}}
+			s.userService.(*usertest.FakeUserService).UpdateFn = func(context.Context, *user.UpdateUserCommand) error {
+				t.Fatal("no user should be updated")
+				return nil
+			}
+
+			_, err := s.Sync(context.Background())
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestTeamMappings(t *testing.T) {
+	mappings := teamMappings(&ldap.ServersConfig{Servers: []*ldap.ServerConfig{
+		{Teams: []*ldap.GroupToTeam{
+			{GroupDN: "cn=sre,ou=groups", OrgId: 1, TeamName: "Platform"},
+			{GroupDN: "cn=backend,ou=groups", OrgId: 2, TeamName: "Backend"},
+		}},
+		{Teams: []*ldap.GroupToTeam{
+			{GroupDN: "cn=platform-admins,ou=groups", OrgId: 1, TeamName: "Platform"},
+		}},
+	}})
+
+	assert.Equal(t, map[teamKey][]string{
+		{orgID: 1, name: "Platform"}: {"cn=sre,ou=groups", "cn=platform-admins,ou=groups"},
+		{orgID: 2, name: "Backend"}:  {"cn=backend,ou=groups"},
+	}, mappings)
+	assert.Empty(t, teamMappings(nil))
+}
+
+func setupTestService(t *testing.T, cfg *setting.Cfg, client *fakeClient) *Service {
+	t.Helper()
+
+	ldapService := service.NewLDAPFakeService()
+	ldapService.ExpectedClient = client
+	ldapService.ExpectedSettings = ldap.GetLDAPConfig(cfg)
+
+	return ProvideService(
+		cfg, ldapService, &authntest.FakeService{}, &fakeSynchronizer{}, &usertest.FakeUserService{},
+		authtest.NewFakeUserAuthTokenService(), teamtest.NewFakeService(), &actest.FakePermissionsService{}, nil,
+	)
+}
+
+type fakeClient struct {
+	multildap.IMultiLDAP
+	users []*login.ExternalUserInfo
+	err   error
+}
+
+func (c *fakeClient) AllUsers() ([]*login.ExternalUserInfo, error) {
+	return c.users, c.err
+}
+
+type fakeSynchronizer struct {
+	identities []*authn.Identity
+	err        error
+}
+
+func (f *fakeSynchronizer) SyncIdentity(_ context.Context, identity *authn.Identity) error {
+	f.identities = append(f.identities, identity)
+	return f.err
+}
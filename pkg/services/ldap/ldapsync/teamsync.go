@@ -0,0 +1,63 @@
+package ldapsync
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/ldap"
+	"github.com/grafana/grafana/pkg/services/login"
+)
+
+type teamKey struct {
+	orgID int64
+	name  string
+}
+
+// teamMappings groups the `team_mappings` of every server by team.
+func teamMappings(config *ldap.ServersConfig) map[teamKey][]string {
+	teams := map[teamKey][]string{}
+	if config == nil {
+		return teams
+	}
+	for _, server := range config.Servers {
+		for _, m := range server.Teams {
+			key := teamKey{orgID: m.OrgId, name: m.TeamName}
+			teams[key] = append(teams[key], m.GroupDN)
+		}
+	}
+	return teams
+}
+
+// syncTeamsHook keeps the membership of the teams listed in `team_mappings` in sync with the
+// groups of a LDAP user, on login and during the background sync. Teams that are not mapped are
+// never modified.
+func (s *Service) syncTeamsHook(ctx context.Context, id *authn.Identity, _ *authn.Request) error {
+	if id.AuthenticatedBy != login.LDAPAuthModule || !id.ClientParams.SyncTeams {
+		return nil
+	}
+
+	mappings := teamMappings(s.ldapService.Config())
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	userID, err := id.GetInternalID()
+	if err != nil {
+		s.log.FromContext(ctx).Debug("Skipping team sync for identity without a user", "id", id.ID)
+		return nil
+	}
+
+	for key, groups := range mappings {
+		member := false
+		for _, group := range groups {
+			if ldap.IsMemberOf(id.Groups, group) {
+				member = true
+				break
+			}
+		}
+		if err := s.teamSyncer.SyncMembership(ctx, key.orgID, key.name, userID, member); err != nil {
+			return err
+		}
+	}
+	return nil
+}
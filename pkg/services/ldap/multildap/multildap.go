@@ -2,6 +2,7 @@ package multildap
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -48,6 +49,10 @@ type IMultiLDAP interface {
 	User(login string) (
 		*login.ExternalUserInfo, ldap.ServerConfig, error,
 	)
+
+	AllUsers() (
+		[]*login.ExternalUserInfo, error,
+	)
 }
 
 // MultiLDAP is basic struct of LDAP authorization
@@ -236,6 +241,52 @@ func (multiples *MultiLDAP) Users(logins []string) (
 	return result, nil
 }
 
+// AllUsers lists the users of every LDAP server. Unlike Users, it fails if any
+// server is unavailable since callers rely on the result being complete.
+// A user present in several servers is returned from the first one, as in User.
+func (multiples *MultiLDAP) AllUsers() (
+	[]*login.ExternalUserInfo,
+	error,
+) {
+	if len(multiples.configs) == 0 {
+		return nil, ErrNoLDAPServers
+	}
+
+	var result []*login.ExternalUserInfo
+	seen := map[string]struct{}{}
+
+	for _, config := range multiples.configs {
+		server := newLDAP(config, multiples.cfg)
+
+		if err := server.Dial(); err != nil {
+			logDialFailure(err, config)
+			return nil, err
+		}
+
+		defer server.Close()
+
+		if err := server.Bind(); err != nil {
+			return nil, err
+		}
+
+		users, err := server.AllUsers()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			key := strings.ToLower(user.Login)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			result = append(result, user)
+		}
+	}
+
+	return result, nil
+}
+
 // isSilentError evaluates an error and tells whenever we should fail the LDAP request
 // immediately or if we should continue into other LDAP servers
 func isSilentError(err error) bool {
@@ -448,6 +448,55 @@ func TestMultiLDAP(t *testing.T) {
 			teardown()
 		})
 	})
+
+	t.Run("AllUsers()", func(t *testing.T) {
+		t.Run("Should return a dial error even if another server is available", func(t *testing.T) {
+			mock := setup()
+
+			expected := errors.New("Dial error")
+			mock.dialErrReturn = expected
+
+			multi := New([]*ldap.ServerConfig{
+				{}, {},
+			}, &ldap.Config{})
+			_, err := multi.AllUsers()
+
+			require.Equal(t, 1, mock.dialCalledTimes)
+			require.Equal(t, expected, err)
+
+			teardown()
+		})
+
+		t.Run("Should get the users of every server without duplicates", func(t *testing.T) {
+			mock := setup()
+
+			mock.usersFirstReturn = []*login.ExternalUserInfo{
+				{Login: "one", AuthId: "first"},
+				{Login: "two"},
+			}
+			mock.usersRestReturn = []*login.ExternalUserInfo{
+				{Login: "One", AuthId: "second"},
+				{Login: "three"},
+			}
+
+			multi := New([]*ldap.ServerConfig{
+				{}, {},
+			}, &ldap.Config{})
+			users, err := multi.AllUsers()
+
+			require.Equal(t, 2, mock.dialCalledTimes)
+			require.Equal(t, 2, mock.allUsersCalledTimes)
+			require.Equal(t, 2, mock.closeCalledTimes)
+
+			require.NoError(t, err)
+			require.Len(t, users, 3)
+			require.Equal(t, "first", users[0].AuthId)
+			require.Equal(t, "two", users[1].Login)
+			require.Equal(t, "three", users[2].Login)
+
+			teardown()
+		})
+	})
 }
 
 // mockLDAP represents testing struct for ldap testing
@@ -458,6 +507,8 @@ type mockLDAP struct {
 	usersCalledTimes int
 	bindCalledTimes  int
 
+	allUsersCalledTimes int
+
 	dialErrReturn error
 
 	loginErrReturn error
@@ -487,6 +538,17 @@ func (mock *mockLDAP) Users([]string) ([]*login.ExternalUserInfo, error) {
 	return mock.usersRestReturn, mock.usersErrReturn
 }
 
+// AllUsers test fn
+func (mock *mockLDAP) AllUsers() ([]*login.ExternalUserInfo, error) {
+	mock.allUsersCalledTimes++
+
+	if mock.allUsersCalledTimes == 1 {
+		return mock.usersFirstReturn, mock.usersErrReturn
+	}
+
+	return mock.usersRestReturn, mock.usersErrReturn
+}
+
 // UserBind test fn
 func (mock *mockLDAP) UserBind(string, string) error {
 	return nil
@@ -7,11 +7,12 @@ import (
 )
 
 type LDAPFakeService struct {
-	ExpectedConfig *ldap.ServersConfig
-	ExpectedClient multildap.IMultiLDAP
-	ExpectedError  error
-	ExpectedUser   *login.ExternalUserInfo
-	UserCalled     bool
+	ExpectedConfig   *ldap.ServersConfig
+	ExpectedSettings *ldap.Config
+	ExpectedClient   multildap.IMultiLDAP
+	ExpectedError    error
+	ExpectedUser     *login.ExternalUserInfo
+	UserCalled       bool
 }
 
 func NewLDAPFakeService() *LDAPFakeService {
@@ -26,6 +27,10 @@ func (s *LDAPFakeService) Config() *ldap.ServersConfig {
 	return s.ExpectedConfig
 }
 
+func (s *LDAPFakeService) Settings() *ldap.Config {
+	return s.ExpectedSettings
+}
+
 func (s *LDAPFakeService) Client() multildap.IMultiLDAP {
 	return s.ExpectedClient
 }
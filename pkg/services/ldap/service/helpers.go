@@ -17,6 +17,15 @@ func resolveBool(input any, defaultValue bool) bool {
 	return result
 }
 
+func resolveInt(input any, defaultValue int) int {
+	strInput := fmt.Sprintf("%v", input)
+	result, err := strconv.Atoi(strInput)
+	if err != nil {
+		return defaultValue
+	}
+	return result
+}
+
 func resolveServerConfig(input any) (*ldap.ServersConfig, error) {
 	var ldapCfg ldap.ServersConfig
 
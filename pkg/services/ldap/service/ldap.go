@@ -28,6 +28,9 @@ var (
 type LDAP interface {
 	ReloadConfig() error
 	Config() *ldap.ServersConfig
+	// Settings returns the general LDAP settings, such as whether LDAP is enabled and the schedule of the
+	// background sync. They can change at runtime when LDAP is configured with the SSO settings API.
+	Settings() *ldap.Config
 	Client() multildap.IMultiLDAP
 
 	// Login authenticates the user against the LDAP server.
@@ -93,6 +96,11 @@ func (s *LDAPImpl) Reload(ctx context.Context, settings models.SSOSettings) erro
 	cfg.Enabled = resolveBool(settings.Settings["enabled"], false)
 	cfg.SkipOrgRoleSync = resolveBool(settings.Settings["skip_org_role_sync"], false)
 	cfg.AllowSignUp = resolveBool(settings.Settings["allow_sign_up"], true)
+	cfg.SyncPageSize = resolveInt(settings.Settings["sync_page_size"], 0)
+	cfg.ActiveSyncEnabled = resolveBool(settings.Settings["active_sync_enabled"], false)
+	if syncCron, ok := settings.Settings["sync_cron"].(string); ok {
+		cfg.SyncCron = syncCron
+	}
 
 	ldapCfg, err := resolveServerConfig(settings.Settings["config"])
 	if err != nil {
@@ -122,6 +130,12 @@ func (s *LDAPImpl) Reload(ctx context.Context, settings models.SSOSettings) erro
 			}
 		}
 
+		for _, teamMap := range server.Teams {
+			if teamMap.OrgId == 0 {
+				teamMap.OrgId = 1
+			}
+		}
+
 		if server.Timeout == 0 {
 			server.Timeout = ldap.DefaultTimeout
 		}
@@ -222,6 +236,10 @@ func (s *LDAPImpl) Config() *ldap.ServersConfig {
 	return s.ldapCfg
 }
 
+func (s *LDAPImpl) Settings() *ldap.Config {
+	return s.cfg
+}
+
 func (s *LDAPImpl) Login(query *login.LoginUserQuery) (*login.ExternalUserInfo, error) {
 	if !s.cfg.Enabled {
 		return nil, ErrLDAPNotEnabled
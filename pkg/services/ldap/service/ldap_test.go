@@ -110,9 +110,11 @@ func TestReload(t *testing.T) {
 			settings: models.SSOSettings{
 				Provider: "ldap",
 				Settings: map[string]any{
-					"enabled":            true,
-					"skip_org_role_sync": false,
-					"allow_sign_up":      true,
+					"enabled":             true,
+					"skip_org_role_sync":  false,
+					"allow_sign_up":       true,
+					"active_sync_enabled": true,
+					"sync_cron":           "0 1 * * *",
 					"config": map[string]any{
 						"servers": []any{
 							map[string]any{
@@ -234,9 +236,11 @@ func TestReload(t *testing.T) {
 				},
 			},
 			expectedConfig: &ldap.Config{
-				Enabled:         true,
-				AllowSignUp:     true,
-				SkipOrgRoleSync: false,
+				Enabled:           true,
+				AllowSignUp:       true,
+				SkipOrgRoleSync:   false,
+				ActiveSyncEnabled: true,
+				SyncCron:          "0 1 * * *",
 			},
 		},
 		{
@@ -24,6 +24,7 @@ type Config struct {
 	SkipOrgRoleSync   bool
 	SyncCron          string
 	ActiveSyncEnabled bool
+	SyncPageSize      int
 }
 
 // ServersConfig holds list of connections to LDAP
@@ -62,7 +63,12 @@ type ServerConfig struct {
 	GroupSearchFilterUserAttribute string   `toml:"group_search_filter_user_attribute" json:"group_search_filter_user_attribute"`
 	GroupSearchBaseDNs             []string `toml:"group_search_base_dns" json:"group_search_base_dns"`
 
+	// NestedGroups resolves the groups of a user transitively with LDAP_MATCHING_RULE_IN_CHAIN,
+	// it is only supported by Active Directory.
+	NestedGroups bool `toml:"nested_groups" json:"nested_groups"`
+
 	Groups []*GroupToOrgRole `toml:"group_mappings" json:"group_mappings"`
+	Teams  []*GroupToTeam    `toml:"team_mappings" json:"team_mappings"`
 }
 
 // AttributeMap is a struct representation for LDAP "attributes" setting
@@ -86,6 +92,14 @@ type GroupToOrgRole struct {
 	OrgRole org.RoleType `toml:"org_role" json:"org_role"`
 }
 
+// GroupToTeam is a struct representation of LDAP
+// config "team_mappings" setting
+type GroupToTeam struct {
+	GroupDN  string `toml:"group_dn" json:"group_dn"`
+	OrgId    int64  `toml:"org_id" json:"org_id"`
+	TeamName string `toml:"team" json:"team"`
+}
+
 // logger for all LDAP stuff
 var logger = log.New("ldap")
 
@@ -108,6 +122,7 @@ func GetLDAPConfig(cfg *setting.Cfg) *Config {
 		SkipOrgRoleSync:   cfg.LDAPSkipOrgRoleSync,
 		SyncCron:          cfg.LDAPSyncCron,
 		ActiveSyncEnabled: cfg.LDAPActiveSyncEnabled,
+		SyncPageSize:      cfg.LDAPSyncPageSize,
 	}
 }
 
@@ -203,6 +218,16 @@ func readConfig(configFile string) (*ServersConfig, error) {
 			}
 		}
 
+		for _, teamMap := range server.Teams {
+			if teamMap.GroupDN == "" || teamMap.TeamName == "" {
+				return nil, fmt.Errorf("LDAP team mapping: group_dn and team are required")
+			}
+
+			if teamMap.OrgId == 0 {
+				teamMap.OrgId = 1
+			}
+		}
+
 		// set default timeout if unspecified
 		if server.Timeout == 0 {
 			server.Timeout = DefaultTimeout
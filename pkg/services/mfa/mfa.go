@@ -0,0 +1,52 @@
+package mfa
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+type FactorType string
+
+const (
+	FactorTypeTOTP     FactorType = "totp"
+	FactorTypeWebAuthn FactorType = "webauthn"
+)
+
+var (
+	ErrFactorNotFound = errutil.NotFound("mfa.factor-not-found", errutil.WithPublicMessage("Second factor not found"))
+)
+
+// Service manages the second factors users enroll for password logins.
+type Service interface {
+	// GetFactors returns the second factors enrolled by a user.
+	GetFactors(ctx context.Context, userID int64) ([]*Factor, error)
+	// Reset removes every second factor and recovery code of a user,
+	// the user will have to enroll again if a policy requires it.
+	Reset(ctx context.Context, userID int64) error
+}
+
+// Factor is a second factor enrolled by a user.
+type Factor struct {
+	ID     int64      `json:"id" xorm:"pk autoincr 'id'"`
+	UserID int64      `json:"-" xorm:"user_id"`
+	Type   FactorType `json:"type" xorm:"type"`
+	Name   string     `json:"name" xorm:"name"`
+	// Secret is the encrypted and base64 encoded TOTP secret
+	Secret string `json:"-" xorm:"secret"`
+	// CredentialID is the base64url encoded WebAuthn credential id
+	CredentialID string `json:"-" xorm:"credential_id"`
+	// PublicKey is the base64url encoded COSE public key of a WebAuthn credential
+	PublicKey string `json:"-" xorm:"public_key"`
+	// Counter is the WebAuthn signature counter or the last TOTP time step used, to prevent replays
+	Counter int64 `json:"-" xorm:"counter"`
+	// BackupEligible tells whether a WebAuthn credential can be synced between devices, it must not change
+	BackupEligible bool       `json:"-" xorm:"backup_eligible"`
+	Created        time.Time  `json:"created" xorm:"created"`
+	LastUsed       *time.Time `json:"lastUsed,omitempty" xorm:"last_used"`
+}
+
+func (Factor) TableName() string {
+	return "user_mfa_factor"
+}
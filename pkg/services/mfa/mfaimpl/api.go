@@ -0,0 +1,258 @@
+package mfaimpl
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type statusResponse struct {
+	Required               bool          `json:"required"`
+	Factors                []*mfa.Factor `json:"factors"`
+	RecoveryCodesRemaining int64         `json:"recoveryCodesRemaining"`
+}
+
+type confirmTOTPRequest struct {
+	EnrollmentToken string `json:"enrollmentToken"`
+	Code            string `json:"code"`
+	Name            string `json:"name"`
+}
+
+type confirmWebAuthnRequest struct {
+	EnrollmentToken string          `json:"enrollmentToken"`
+	Name            string          `json:"name"`
+	Credential      json.RawMessage `json:"credential"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Group("/api/user/mfa", func(r routing.RouteRegister) {
+		r.Get("/", routing.Wrap(s.getStatus))
+		r.Post("/totp", routing.Wrap(s.startTOTP))
+		r.Post("/totp/confirm", routing.Wrap(s.confirmTOTP))
+		r.Post("/webauthn", routing.Wrap(s.startWebAuthn))
+		r.Post("/webauthn/confirm", routing.Wrap(s.confirmWebAuthn))
+		r.Delete("/factors/:factorId", routing.Wrap(s.deleteFactor))
+		r.Post("/recovery-codes", routing.Wrap(s.regenerateRecoveryCodesHandler))
+	}, middleware.ReqSignedInNoAnonymous)
+}
+
+// userID returns the id of the signed in user, second factors are only available to users.
+func userID(c *contextmodel.ReqContext) (int64, bool) {
+	if !c.SignedInUser.IsIdentityType(claims.TypeUser) {
+		return 0, false
+	}
+	id, err := c.SignedInUser.GetInternalID()
+	return id, err == nil
+}
+
+func (s *Service) getStatus(c *contextmodel.ReqContext) response.Response {
+	id, ok := userID(c)
+	if !ok {
+		return response.Error(http.StatusForbidden, "Second factors are only available to users", nil)
+	}
+
+	factors, err := s.store.ListFactors(c.Req.Context(), id)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list second factors", err)
+	}
+	remaining, err := s.store.CountRecoveryCodes(c.Req.Context(), id)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to count recovery codes", err)
+	}
+	required, err := s.isRequired(c.Req.Context(), id, c.SignedInUser.GetIsGrafanaAdmin())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to evaluate second factor policy", err)
+	}
+
+	return response.JSON(http.StatusOK, statusResponse{Required: required, Factors: factors, RecoveryCodesRemaining: remaining})
+}
+
+func (s *Service) startTOTP(c *contextmodel.ReqContext) response.Response {
+	id, ok := userID(c)
+	if !ok {
+		return response.Error(http.StatusForbidden, "Second factors are only available to users", nil)
+	}
+	if resp := s.confirmPresence(c, id, nil); resp != nil {
+		return resp
+	}
+
+	enrollment, err := s.startTOTPEnrollment(c.Req.Context(), id, c.SignedInUser.GetLogin())
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+func (s *Service) confirmTOTP(c *contextmodel.ReqContext) response.Response {
+	id, ok := userID(c)
+	if !ok {
+		return response.Error(http.StatusForbidden, "Second factors are only available to users", nil)
+	}
+
+	cmd := confirmTOTPRequest{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if cmd.EnrollmentToken == "" || cmd.Code == "" {
+		return response.Err(errMFABadRequest.Errorf("enrollmentToken and code are required"))
+	}
+
+	factor, err := s.completeTOTPEnrollment(c.Req.Context(), id, cmd.EnrollmentToken, cmd.Code, factorName(cmd.Name, "Authenticator app"))
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, factor)
+}
+
+func (s *Service) startWebAuthn(c *contextmodel.ReqContext) response.Response {
+	id, ok := userID(c)
+	if !ok {
+		return response.Error(http.StatusForbidden, "Second factors are only available to users", nil)
+	}
+	if resp := s.confirmPresence(c, id, nil); resp != nil {
+		return resp
+	}
+
+	enrollment, err := s.startWebAuthnEnrollment(c.Req.Context(), id, c.SignedInUser.GetLogin(), c.SignedInUser.GetName())
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, enrollment)
+}
+
+func (s *Service) confirmWebAuthn(c *contextmodel.ReqContext) response.Response {
+	id, ok := userID(c)
+	if !ok {
+		return response.Error(http.StatusForbidden, "Second factors are only available to users", nil)
+	}
+
+	cmd := confirmWebAuthnRequest{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if cmd.EnrollmentToken == "" || len(cmd.Credential) == 0 {
+		return response.Err(errMFABadRequest.Errorf("enrollmentToken and credential are required"))
+	}
+
+	factor, err := s.completeWebAuthnEnrollment(c.Req.Context(), id, cmd.EnrollmentToken, factorName(cmd.Name, "Security key"), cmd.Credential)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, factor)
+}
+
+func (s *Service) deleteFactor(c *contextmodel.ReqContext) response.Response {
+	id, ok := userID(c)
+	if !ok {
+		return response.Error(http.StatusForbidden, "Second factors are only available to users", nil)
+	}
+
+	factorID, err := strconv.ParseInt(web.Params(c.Req)[":factorId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "factorId is invalid", err)
+	}
+
+	ctx := c.Req.Context()
+	factors, err := s.store.ListFactors(ctx, id)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list second factors", err)
+	}
+	if resp := s.confirmPresence(c, id, factors); resp != nil {
+		return resp
+	}
+
+	if len(factors) == 1 && factors[0].ID == factorID {
+		required, err := s.isRequired(ctx, id, c.SignedInUser.GetIsGrafanaAdmin())
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to evaluate second factor policy", err)
+		}
+		if required {
+			return response.Err(errMFAFactorRequired.Errorf("user %d must keep a second factor", id))
+		}
+		// Without any factor left the recovery codes are useless, drop them too.
+		if err := s.store.DeleteAll(ctx, id); err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to delete second factor", err)
+		}
+		return response.Success("Second factor deleted")
+	}
+
+	if err := s.store.DeleteFactor(ctx, id, factorID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete second factor", err)
+	}
+	return response.Success("Second factor deleted")
+}
+
+func (s *Service) regenerateRecoveryCodesHandler(c *contextmodel.ReqContext) response.Response {
+	id, ok := userID(c)
+	if !ok {
+		return response.Error(http.StatusForbidden, "Second factors are only available to users", nil)
+	}
+
+	factors, err := s.store.ListFactors(c.Req.Context(), id)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list second factors", err)
+	}
+	if len(factors) == 0 {
+		return response.Err(errMFANoFactor.Errorf("user %d has no second factor enrolled", id))
+	}
+	if resp := s.confirmPresence(c, id, factors); resp != nil {
+		return resp
+	}
+
+	codes, err := s.regenerateRecoveryCodes(c.Req.Context(), id)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// confirmPresence requires a second factor in the JSON body of requests changing the second factors of a
+// user that already has some, see verifyPresence. The factors are listed when nil. It returns nil once
+// the change can proceed.
+func (s *Service) confirmPresence(c *contextmodel.ReqContext, id int64, factors []*mfa.Factor) response.Response {
+	ctx := c.Req.Context()
+	if factors == nil {
+		var err error
+		if factors, err = s.store.ListFactors(ctx, id); err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to list second factors", err)
+		}
+	}
+
+	proof := secondFactor{}
+	// Requests without a body, such as the first attempt of a change, carry no factor.
+	if c.Req.Header.Get("Content-Type") != "" {
+		if err := web.Bind(c.Req, &proof); err != nil {
+			return response.Error(http.StatusBadRequest, "bad request data", err)
+		}
+	}
+
+	if err := s.verifyPresence(ctx, id, c.SignedInUser.GetLogin(), web.RemoteAddr(c.Req), factors, proof); err != nil {
+		return response.Err(err)
+	}
+	return nil
+}
+
+func factorName(name, fallback string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fallback
+	}
+	if len(name) > 190 {
+		return name[:190]
+	}
+	return name
+}
@@ -0,0 +1,684 @@
+package mfaimpl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	claims "github.com/grafana/authlib/types"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// roleGrafanaAdmin can be listed in required_roles to require a second factor from server admins.
+const roleGrafanaAdmin = "GrafanaAdmin"
+
+// recoveryCodeAlphabet avoids characters that are easily confused when written down.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+var (
+	errMFARequired           = errutil.Unauthorized("mfa.required", errutil.WithPublicMessage("A second factor is required to sign in"))
+	errMFAEnrollmentRequired = errutil.Unauthorized("mfa.enrollment-required", errutil.WithPublicMessage("A second factor must be enrolled to sign in"))
+	errMFAInvalid            = errutil.Unauthorized("mfa.invalid", errutil.WithPublicMessage("Invalid second factor"))
+	errMFAEnrollmentNotFound = errutil.BadRequest("mfa.enrollment-not-found", errutil.WithPublicMessage("Enrollment not found or expired, start again"))
+	errMFABadRequest         = errutil.BadRequest("mfa.bad-request")
+	errMFANoFactor           = errutil.BadRequest("mfa.no-factor", errutil.WithPublicMessage("Enroll a second factor first"))
+	errMFAInvalidCredential  = errutil.BadRequest("mfa.invalid-credential", errutil.WithPublicMessage("Invalid security key registration"))
+	errMFACredentialExists   = errutil.BadRequest("mfa.credential-exists", errutil.WithPublicMessage("Security key already registered"))
+	errMFAFactorRequired     = errutil.BadRequest("mfa.factor-required", errutil.WithPublicMessage("A second factor is required for your account, enroll another one before removing the last"))
+	errMFAInternal           = errutil.Internal("mfa.internal")
+
+	// Changes to the second factors of a signed in user are confirmed with one of them. These errors are
+	// forbidden rather than unauthorized, the session itself is valid.
+	errMFAVerificationRequired = errutil.Forbidden("mfa.verification-required", errutil.WithPublicMessage("Confirm this change with a second factor"))
+	errMFAVerificationInvalid  = errutil.Forbidden("mfa.verification-invalid", errutil.WithPublicMessage("Invalid second factor"))
+	errMFAVerificationBlocked  = errutil.Forbidden("mfa.verification-blocked", errutil.WithPublicMessage("Too many invalid second factors, try again later"))
+)
+
+// secondFactor is a second factor presented by a user, at most one of its fields is expected to be set.
+type secondFactor struct {
+	OTP          string          `json:"otp"`
+	RecoveryCode string          `json:"recoveryCode"`
+	WebAuthn     json.RawMessage `json:"webauthn"`
+}
+
+func (f secondFactor) empty() bool {
+	return f.OTP == "" && f.RecoveryCode == "" && len(f.WebAuthn) == 0
+}
+
+var _ mfa.Service = new(Service)
+
+type Service struct {
+	cfg           *setting.Cfg
+	log           log.Logger
+	store         store
+	secrets       secrets.Service
+	cache         remotecache.CacheStorage
+	orgService    org.Service
+	loginAttempts loginattempt.Service
+	webauthn      *webAuthn
+	now           func() time.Time
+}
+
+func ProvideService(
+	cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, authnService authn.Service,
+	secretsService secrets.Service, cache remotecache.CacheStorage, orgService org.Service, loginAttempts loginattempt.Service,
+) (*Service, error) {
+	s := &Service{
+		cfg:           cfg,
+		log:           log.New("mfa"),
+		store:         &xormStore{db: sqlStore},
+		secrets:       secretsService,
+		cache:         cache,
+		orgService:    orgService,
+		loginAttempts: loginAttempts,
+		now:           time.Now,
+	}
+
+	if !cfg.MFA.Enabled {
+		return s, nil
+	}
+
+	var err error
+	if s.webauthn, err = newWebAuthn(cfg); err != nil {
+		return nil, err
+	}
+
+	// Run after the user has been synced and fetched so the user id and server admin flag are known,
+	// but before permissions are loaded.
+	authnService.RegisterPostAuthHook(s.verifySecondFactorHook, 105)
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) GetFactors(ctx context.Context, userID int64) ([]*mfa.Factor, error) {
+	return s.store.ListFactors(ctx, userID)
+}
+
+func (s *Service) Reset(ctx context.Context, userID int64) error {
+	if err := s.store.DeleteAll(ctx, userID); err != nil {
+		return err
+	}
+	// Drop any pending webauthn login challenge as well.
+	if err := s.cache.Delete(ctx, loginChallengeKey(userID)); err != nil && !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+		s.log.FromContext(ctx).Warn("Failed to delete login challenge", "userID", userID, "error", err)
+	}
+	return nil
+}
+
+// verifySecondFactorHook requires a second factor from users that have one enrolled, or that
+// must use one by policy, when they sign in with a password. API keys and service accounts are
+// exempt: they are how API clients authenticate, basic auth requires a new code for every request.
+func (s *Service) verifySecondFactorHook(ctx context.Context, id *authn.Identity, r *authn.Request) error {
+	if !id.IsIdentityType(claims.TypeUser) {
+		return nil
+	}
+	if r.GetMeta(authn.MetaKeyPasswordVerified) != "true" {
+		return nil
+	}
+
+	userID, err := id.GetInternalID()
+	if err != nil {
+		return nil
+	}
+
+	factors, err := s.store.ListFactors(ctx, userID)
+	if err != nil {
+		return errMFAInternal.Errorf("failed to list second factors: %w", err)
+	}
+
+	if len(factors) > 0 {
+		return s.verifyLogin(ctx, r, userID, factors)
+	}
+
+	required, err := s.isRequired(ctx, userID, id.IsGrafanaAdmin != nil && *id.IsGrafanaAdmin)
+	if err != nil {
+		return errMFAInternal.Errorf("failed to evaluate second factor policy: %w", err)
+	}
+	if !required {
+		return nil
+	}
+	return s.enrollDuringLogin(ctx, r, userID, id.Login)
+}
+
+// isRequired reports whether the policy in [auth.mfa] requires a second factor from the user.
+func (s *Service) isRequired(ctx context.Context, userID int64, isGrafanaAdmin bool) (bool, error) {
+	roles := s.cfg.MFA.RequiredRoles
+	if isGrafanaAdmin && slices.ContainsFunc(roles, func(r string) bool { return strings.EqualFold(r, roleGrafanaAdmin) }) {
+		return true, nil
+	}
+	if len(roles) == 0 && len(s.cfg.MFA.RequiredOrgIDs) == 0 {
+		return false, nil
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return false, err
+	}
+	for _, o := range orgs {
+		if slices.Contains(s.cfg.MFA.RequiredOrgIDs, o.OrgID) {
+			return true, nil
+		}
+		if slices.ContainsFunc(roles, func(r string) bool { return strings.EqualFold(r, string(o.Role)) }) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Service) verifyLogin(ctx context.Context, r *authn.Request, userID int64, factors []*mfa.Factor) error {
+	proof := secondFactor{
+		OTP:          r.GetMeta(authn.MetaKeyOTP),
+		RecoveryCode: r.GetMeta(authn.MetaKeyRecoveryCode),
+		WebAuthn:     json.RawMessage(r.GetMeta(authn.MetaKeyWebAuthnAssertion)),
+	}
+	if proof.empty() {
+		payload, err := s.challenge(ctx, userID, factors)
+		if err != nil {
+			return err
+		}
+		mfaErr := errMFARequired.Errorf("second factor required for user %d", userID)
+		mfaErr.PublicPayload = payload
+		return mfaErr
+	}
+
+	ok, err := s.checkSecondFactor(ctx, userID, factors, proof)
+	if err != nil {
+		return errMFAInternal.Errorf("failed to verify second factor: %w", err)
+	}
+	if !ok {
+		s.recordFailure(ctx, r)
+		return errMFAInvalid.Errorf("invalid second factor for user %d", userID)
+	}
+	return nil
+}
+
+// verifyPresence requires a second factor from a signed in user before a change to their second
+// factors, so that a stolen session can't remove them, enroll new ones or mint recovery codes.
+// Users without any factor have nothing to prove. Failures count as failed login attempts.
+func (s *Service) verifyPresence(ctx context.Context, userID int64, login, remoteAddr string, factors []*mfa.Factor, proof secondFactor) error {
+	if len(factors) == 0 {
+		return nil
+	}
+	if proof.empty() {
+		payload, err := s.challenge(ctx, userID, factors)
+		if err != nil {
+			return err
+		}
+		mfaErr := errMFAVerificationRequired.Errorf("second factor verification required for user %d", userID)
+		mfaErr.PublicPayload = payload
+		return mfaErr
+	}
+
+	ok, err := s.loginAttempts.Validate(ctx, login)
+	if err != nil {
+		return errMFAInternal.Errorf("failed to validate login attempts: %w", err)
+	}
+	if !ok {
+		return errMFAVerificationBlocked.Errorf("too many invalid second factors for user %d", userID)
+	}
+
+	ok, err = s.checkSecondFactor(ctx, userID, factors, proof)
+	if err != nil {
+		return errMFAInternal.Errorf("failed to verify second factor: %w", err)
+	}
+	if !ok {
+		if err := s.loginAttempts.Add(ctx, login, remoteAddr); err != nil {
+			s.log.FromContext(ctx).Warn("Failed to record failed second factor attempt", "error", err)
+		}
+		return errMFAVerificationInvalid.Errorf("invalid second factor for user %d", userID)
+	}
+	return nil
+}
+
+// checkSecondFactor checks a second factor of the user, consuming it: codes and assertions can't be replayed.
+func (s *Service) checkSecondFactor(ctx context.Context, userID int64, factors []*mfa.Factor, proof secondFactor) (bool, error) {
+	switch {
+	case proof.OTP != "":
+		return s.verifyTOTP(ctx, factors, proof.OTP)
+	case len(proof.WebAuthn) > 0:
+		return s.verifyWebAuthn(ctx, userID, factors, string(proof.WebAuthn))
+	case proof.RecoveryCode != "":
+		return s.store.UseRecoveryCode(ctx, userID, hashRecoveryCode(userID, proof.RecoveryCode))
+	default:
+		return false, nil
+	}
+}
+
+// challenge returns the second factors the client can use, along with the WebAuthn request options
+// when the user has security keys enrolled.
+func (s *Service) challenge(ctx context.Context, userID int64, factors []*mfa.Factor) (map[string]any, error) {
+	methods := []string{}
+	for _, f := range factors {
+		if !slices.Contains(methods, string(f.Type)) {
+			methods = append(methods, string(f.Type))
+		}
+	}
+	methods = append(methods, "recoveryCode")
+	payload := map[string]any{"methods": methods}
+
+	if slices.Contains(methods, string(mfa.FactorTypeWebAuthn)) {
+		user, err := newWebAuthnUser(userID, "", "", factors)
+		if err != nil {
+			return nil, errMFAInternal.Errorf("failed to load security keys: %w", err)
+		}
+		options, session, err := s.webauthn.beginLogin(user)
+		if err != nil {
+			return nil, errMFAInternal.Errorf("failed to generate challenge: %w", err)
+		}
+		data, err := json.Marshal(session)
+		if err != nil {
+			return nil, errMFAInternal.Errorf("failed to encode challenge: %w", err)
+		}
+		if err := s.cache.Set(ctx, loginChallengeKey(userID), data, s.cfg.MFA.ChallengeTTL); err != nil {
+			return nil, errMFAInternal.Errorf("failed to store challenge: %w", err)
+		}
+		payload["webauthn"] = options
+	}
+
+	return payload, nil
+}
+
+func (s *Service) verifyTOTP(ctx context.Context, factors []*mfa.Factor, code string) (bool, error) {
+	now := s.now()
+	for _, f := range factors {
+		if f.Type != mfa.FactorTypeTOTP {
+			continue
+		}
+		secret, err := s.decryptSecret(ctx, f.Secret)
+		if err != nil {
+			return false, err
+		}
+		step, ok := validateTOTP(secret, code, now, f.Counter)
+		if !ok {
+			continue
+		}
+		// A concurrent login may have used the same code, only one of them wins.
+		return s.store.UseFactor(ctx, f.ID, f.Counter, step, now)
+	}
+	return false, nil
+}
+
+func (s *Service) verifyWebAuthn(ctx context.Context, userID int64, factors []*mfa.Factor, raw string) (bool, error) {
+	data, err := s.cache.Get(ctx, loginChallengeKey(userID))
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	// Challenges are single use, whatever the outcome.
+	if err := s.cache.Delete(ctx, loginChallengeKey(userID)); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to delete login challenge", "userID", userID, "error", err)
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return false, fmt.Errorf("malformed login challenge: %w", err)
+	}
+	user, err := newWebAuthnUser(userID, "", "", factors)
+	if err != nil {
+		return false, err
+	}
+	credential, err := s.webauthn.finishLogin(user, session, []byte(raw))
+	if err != nil {
+		s.log.FromContext(ctx).Debug("Failed to verify webauthn assertion", "userID", userID, "error", err)
+		return false, nil
+	}
+
+	credentialID := encodeBase64URL(credential.ID)
+	for _, f := range factors {
+		if f.Type != mfa.FactorTypeWebAuthn || f.CredentialID != credentialID {
+			continue
+		}
+		// A concurrent login may have used the same assertion, only one of them wins.
+		return s.store.UseFactor(ctx, f.ID, f.Counter, int64(credential.Authenticator.SignCount), s.now())
+	}
+	return false, nil
+}
+
+func (s *Service) recordFailure(ctx context.Context, r *authn.Request) {
+	username := r.GetMeta(authn.MetaKeyUsername)
+	if username == "" || r.HTTPRequest == nil {
+		return
+	}
+	if err := s.loginAttempts.Add(ctx, username, web.RemoteAddr(r.HTTPRequest)); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to record failed second factor attempt", "error", err)
+	}
+}
+
+// enrollDuringLogin lets users that must use a second factor enroll an authenticator app while
+// signing in: the first attempt returns a new secret, the next one confirms it with a code.
+func (s *Service) enrollDuringLogin(ctx context.Context, r *authn.Request, userID int64, login string) error {
+	token, code := r.GetMeta(authn.MetaKeyMFAEnrollmentToken), r.GetMeta(authn.MetaKeyOTP)
+	if token != "" && code != "" {
+		if _, err := s.completeTOTPEnrollment(ctx, userID, token, code, "Authenticator app"); err != nil {
+			if errors.Is(err, errMFAInvalid) {
+				s.recordFailure(ctx, r)
+			}
+			return err
+		}
+		return nil
+	}
+
+	enrollment, err := s.startTOTPEnrollment(ctx, userID, login)
+	if err != nil {
+		return err
+	}
+	mfaErr := errMFAEnrollmentRequired.Errorf("second factor enrollment required for user %d", userID)
+	mfaErr.PublicPayload = map[string]any{"methods": []string{string(mfa.FactorTypeTOTP)}, "totp": enrollment}
+	return mfaErr
+}
+
+// pendingEnrollment is kept in the remote cache between the start and the confirmation of an enrollment.
+type pendingEnrollment struct {
+	UserID        int64                 `json:"userId"`
+	Type          mfa.FactorType        `json:"type"`
+	Secret        string                `json:"secret,omitempty"`
+	Session       *webauthn.SessionData `json:"session,omitempty"`
+	RecoveryCodes []string              `json:"recoveryCodes,omitempty"`
+}
+
+// enrollmentResponse is returned when an enrollment starts. Recovery codes are only generated
+// for the first factor of a user and become valid once the enrollment is confirmed.
+type enrollmentResponse struct {
+	EnrollmentToken string                                       `json:"enrollmentToken"`
+	Secret          string                                       `json:"secret,omitempty"`
+	URL             string                                       `json:"url,omitempty"`
+	PublicKey       *protocol.PublicKeyCredentialCreationOptions `json:"publicKey,omitempty"`
+	RecoveryCodes   []string                                     `json:"recoveryCodes,omitempty"`
+}
+
+func (s *Service) startTOTPEnrollment(ctx context.Context, userID int64, login string) (*enrollmentResponse, error) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, errMFAInternal.Errorf("failed to generate secret: %w", err)
+	}
+
+	pending := &pendingEnrollment{UserID: userID, Type: mfa.FactorTypeTOTP, Secret: secret}
+	token, err := s.savePendingEnrollment(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	return &enrollmentResponse{
+		EnrollmentToken: token,
+		Secret:          secret,
+		URL:             totpURL(s.cfg.MFA.Issuer, login, secret),
+		RecoveryCodes:   pending.RecoveryCodes,
+	}, nil
+}
+
+func (s *Service) completeTOTPEnrollment(ctx context.Context, userID int64, token, code, name string) (*mfa.Factor, error) {
+	pending, err := s.getPendingEnrollment(ctx, userID, token, mfa.FactorTypeTOTP)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	step, ok := validateTOTP(pending.Secret, code, now, 0)
+	if !ok {
+		return nil, errMFAInvalid.Errorf("invalid code for totp enrollment of user %d", userID)
+	}
+
+	secret, err := s.encryptSecret(ctx, pending.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	factor := &mfa.Factor{UserID: userID, Type: mfa.FactorTypeTOTP, Name: name, Secret: secret, Counter: step, Created: now, LastUsed: &now}
+	if err := s.completeEnrollment(ctx, token, pending, factor); err != nil {
+		return nil, err
+	}
+	return factor, nil
+}
+
+func (s *Service) startWebAuthnEnrollment(ctx context.Context, userID int64, login, displayName string) (*enrollmentResponse, error) {
+	factors, err := s.store.ListFactors(ctx, userID)
+	if err != nil {
+		return nil, errMFAInternal.Errorf("failed to list second factors: %w", err)
+	}
+	user, err := newWebAuthnUser(userID, login, displayName, factors)
+	if err != nil {
+		return nil, errMFAInternal.Errorf("failed to load security keys: %w", err)
+	}
+
+	options, session, err := s.webauthn.beginRegistration(user)
+	if err != nil {
+		return nil, errMFAInternal.Errorf("failed to generate challenge: %w", err)
+	}
+
+	pending := &pendingEnrollment{UserID: userID, Type: mfa.FactorTypeWebAuthn, Session: session}
+	token, err := s.savePendingEnrollment(ctx, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	return &enrollmentResponse{
+		EnrollmentToken: token,
+		PublicKey:       options,
+		RecoveryCodes:   pending.RecoveryCodes,
+	}, nil
+}
+
+func (s *Service) completeWebAuthnEnrollment(ctx context.Context, userID int64, token, name string, credential []byte) (*mfa.Factor, error) {
+	pending, err := s.getPendingEnrollment(ctx, userID, token, mfa.FactorTypeWebAuthn)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Session == nil {
+		return nil, errMFAEnrollmentNotFound.Errorf("enrollment has no webauthn session")
+	}
+
+	factors, err := s.store.ListFactors(ctx, userID)
+	if err != nil {
+		return nil, errMFAInternal.Errorf("failed to list second factors: %w", err)
+	}
+	user, err := newWebAuthnUser(userID, "", "", factors)
+	if err != nil {
+		return nil, errMFAInternal.Errorf("failed to load security keys: %w", err)
+	}
+
+	registered, err := s.webauthn.finishRegistration(user, *pending.Session, credential)
+	if err != nil {
+		return nil, errMFAInvalidCredential.Errorf("failed to verify credential: %w", err)
+	}
+
+	credentialID := encodeBase64URL(registered.ID)
+	if slices.ContainsFunc(factors, func(f *mfa.Factor) bool { return f.CredentialID == credentialID }) {
+		return nil, errMFACredentialExists.Errorf("credential %s already registered", credentialID)
+	}
+
+	now := s.now()
+	factor := &mfa.Factor{
+		UserID:         userID,
+		Type:           mfa.FactorTypeWebAuthn,
+		Name:           name,
+		CredentialID:   credentialID,
+		PublicKey:      encodeBase64URL(registered.PublicKey),
+		Counter:        int64(registered.Authenticator.SignCount),
+		BackupEligible: registered.Flags.BackupEligible,
+		Created:        now,
+	}
+	if err := s.completeEnrollment(ctx, token, pending, factor); err != nil {
+		return nil, err
+	}
+	return factor, nil
+}
+
+func (s *Service) completeEnrollment(ctx context.Context, token string, pending *pendingEnrollment, factor *mfa.Factor) error {
+	if err := s.store.CreateFactor(ctx, factor); err != nil {
+		return errMFAInternal.Errorf("failed to save second factor: %w", err)
+	}
+	if len(pending.RecoveryCodes) > 0 {
+		if err := s.store.ReplaceRecoveryCodes(ctx, pending.UserID, hashRecoveryCodes(pending.UserID, pending.RecoveryCodes)); err != nil {
+			return errMFAInternal.Errorf("failed to save recovery codes: %w", err)
+		}
+	}
+	if err := s.cache.Delete(ctx, enrollmentKey(token)); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to delete pending enrollment", "userID", pending.UserID, "error", err)
+	}
+	return nil
+}
+
+// savePendingEnrollment stores an enrollment until it is confirmed. Users enrolling their
+// first factor also get a new set of recovery codes.
+func (s *Service) savePendingEnrollment(ctx context.Context, pending *pendingEnrollment) (string, error) {
+	factors, err := s.store.ListFactors(ctx, pending.UserID)
+	if err != nil {
+		return "", errMFAInternal.Errorf("failed to list second factors: %w", err)
+	}
+	if len(factors) == 0 {
+		if pending.RecoveryCodes, err = generateRecoveryCodes(s.cfg.MFA.RecoveryCodes); err != nil {
+			return "", errMFAInternal.Errorf("failed to generate recovery codes: %w", err)
+		}
+	}
+
+	tokenBytes, err := randomBytes(32)
+	if err != nil {
+		return "", errMFAInternal.Errorf("failed to generate enrollment token: %w", err)
+	}
+	token := encodeBase64URL(tokenBytes)
+
+	// The pending secret is encrypted as it is usable once the enrollment is confirmed.
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return "", errMFAInternal.Errorf("failed to encode enrollment: %w", err)
+	}
+	encrypted, err := s.secrets.Encrypt(ctx, data, secrets.WithoutScope())
+	if err != nil {
+		return "", errMFAInternal.Errorf("failed to encrypt enrollment: %w", err)
+	}
+	if err := s.cache.Set(ctx, enrollmentKey(token), encrypted, s.cfg.MFA.ChallengeTTL); err != nil {
+		return "", errMFAInternal.Errorf("failed to store enrollment: %w", err)
+	}
+	return token, nil
+}
+
+func (s *Service) getPendingEnrollment(ctx context.Context, userID int64, token string, typ mfa.FactorType) (*pendingEnrollment, error) {
+	encrypted, err := s.cache.Get(ctx, enrollmentKey(token))
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, errMFAEnrollmentNotFound.Errorf("enrollment not found")
+		}
+		return nil, errMFAInternal.Errorf("failed to get enrollment: %w", err)
+	}
+	data, err := s.secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return nil, errMFAInternal.Errorf("failed to decrypt enrollment: %w", err)
+	}
+
+	var pending pendingEnrollment
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, errMFAInternal.Errorf("failed to decode enrollment: %w", err)
+	}
+	if pending.UserID != userID || pending.Type != typ {
+		return nil, errMFAEnrollmentNotFound.Errorf("enrollment belongs to another user or factor type")
+	}
+	return &pending, nil
+}
+
+// regenerateRecoveryCodes replaces the recovery codes of a user that has at least one factor enrolled.
+func (s *Service) regenerateRecoveryCodes(ctx context.Context, userID int64) ([]string, error) {
+	codes, err := generateRecoveryCodes(s.cfg.MFA.RecoveryCodes)
+	if err != nil {
+		return nil, errMFAInternal.Errorf("failed to generate recovery codes: %w", err)
+	}
+	if err := s.store.ReplaceRecoveryCodes(ctx, userID, hashRecoveryCodes(userID, codes)); err != nil {
+		return nil, errMFAInternal.Errorf("failed to save recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+func (s *Service) encryptSecret(ctx context.Context, secret string) (string, error) {
+	encrypted, err := s.secrets.Encrypt(ctx, []byte(secret), secrets.WithoutScope())
+	if err != nil {
+		return "", errMFAInternal.Errorf("failed to encrypt secret: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (s *Service) decryptSecret(ctx context.Context, secret string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := s.secrets.Decrypt(ctx, encrypted)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+func generateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b, err := randomBytes(10)
+		if err != nil {
+			return nil, err
+		}
+		code := make([]byte, 0, 11)
+		for j, v := range b {
+			if j == 5 {
+				code = append(code, '-')
+			}
+			code = append(code, recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, string(code))
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode hashes a recovery code for storage. Codes are random enough that a salted
+// fast hash is sufficient, and it lets a code be looked up directly.
+func hashRecoveryCode(userID int64, code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", userID, normalized)))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashRecoveryCodes(userID int64, codes []string) []string {
+	hashes := make([]string, 0, len(codes))
+	for _, c := range codes {
+		hashes = append(hashes, hashRecoveryCode(userID, c))
+	}
+	return hashes
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func loginChallengeKey(userID int64) string {
+	return fmt.Sprintf("mfa-login-challenge-%d", userID)
+}
+
+func enrollmentKey(token string) string {
+	return "mfa-enrollment-" + token
+}
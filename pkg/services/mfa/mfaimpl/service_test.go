@@ -0,0 +1,319 @@
+package mfaimpl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	claims "github.com/grafana/authlib/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationMFA_VerifySecondFactorHook(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	t.Run("ignores logins without a password", func(t *testing.T) {
+		s := setupTestService(t, nil)
+		err := s.verifySecondFactorHook(ctx, testIdentity(1, false), &authn.Request{})
+		require.NoError(t, err)
+	})
+
+	t.Run("ignores api keys and service accounts", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) { cfg.MFA.RequiredRoles = []string{"Viewer", "Editor", "Admin", "GrafanaAdmin"} })
+		for _, typ := range []claims.IdentityType{claims.TypeAPIKey, claims.TypeServiceAccount} {
+			id := testIdentity(2, true)
+			id.Type = typ
+			require.NoError(t, s.verifySecondFactorHook(ctx, id, passwordRequest(nil)))
+		}
+	})
+
+	t.Run("users without factors sign in when not required", func(t *testing.T) {
+		s := setupTestService(t, nil)
+		err := s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(nil))
+		require.NoError(t, err)
+	})
+
+	t.Run("users required by policy enroll during login", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) { cfg.MFA.RequiredRoles = []string{"Admin"} })
+		s.orgService.(*orgtest.FakeOrgService).ExpectedUserOrgDTO = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleAdmin}}
+		s.now = func() time.Time { return now }
+
+		err := s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(nil))
+		payload := requireMFAError(t, err, "mfa.enrollment-required")
+		enrollment := payload["totp"].(*enrollmentResponse)
+		require.NotEmpty(t, enrollment.EnrollmentToken)
+		require.Len(t, enrollment.RecoveryCodes, 10)
+
+		err = s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(map[string]string{
+			authn.MetaKeyMFAEnrollmentToken: enrollment.EnrollmentToken,
+			authn.MetaKeyOTP:                "000000",
+		}))
+		requireMFAError(t, err, "mfa.invalid")
+
+		code := currentCode(t, enrollment.Secret, now)
+		err = s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(map[string]string{
+			authn.MetaKeyMFAEnrollmentToken: enrollment.EnrollmentToken,
+			authn.MetaKeyOTP:                code,
+		}))
+		require.NoError(t, err)
+
+		factors, err := s.GetFactors(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, factors, 1)
+		assert.Equal(t, mfa.FactorTypeTOTP, factors[0].Type)
+		assert.NotEqual(t, enrollment.Secret, factors[0].Secret)
+
+		count, err := s.store.CountRecoveryCodes(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), count)
+	})
+
+	t.Run("grafana admins can be required to use a second factor", func(t *testing.T) {
+		s := setupTestService(t, func(cfg *setting.Cfg) { cfg.MFA.RequiredRoles = []string{"GrafanaAdmin"} })
+
+		err := s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(nil))
+		require.NoError(t, err)
+
+		err = s.verifySecondFactorHook(ctx, testIdentity(1, true), passwordRequest(nil))
+		requireMFAError(t, err, "mfa.enrollment-required")
+	})
+
+	t.Run("enrolled users must provide a second factor", func(t *testing.T) {
+		s := setupTestService(t, nil)
+		s.now = func() time.Time { return now }
+		secret, recoveryCodes := enrollTOTP(t, s, 1, now)
+
+		err := s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(nil))
+		payload := requireMFAError(t, err, "mfa.required")
+		assert.Equal(t, []string{"totp", "recoveryCode"}, payload["methods"])
+
+		err = s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(map[string]string{authn.MetaKeyOTP: "000000"}))
+		requireMFAError(t, err, "mfa.invalid")
+
+		// The code used during enrollment can't be replayed, the next one is accepted once.
+		next := now.Add(totpPeriod * time.Second)
+		s.now = func() time.Time { return next }
+		code := currentCode(t, secret, next)
+		require.NoError(t, s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(map[string]string{authn.MetaKeyOTP: code})))
+		err = s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(map[string]string{authn.MetaKeyOTP: code}))
+		requireMFAError(t, err, "mfa.invalid")
+
+		// Recovery codes are single use.
+		recovery := map[string]string{authn.MetaKeyRecoveryCode: recoveryCodes[0]}
+		require.NoError(t, s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(recovery)))
+		err = s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(recovery))
+		requireMFAError(t, err, "mfa.invalid")
+	})
+
+	t.Run("reset removes factors and recovery codes", func(t *testing.T) {
+		s := setupTestService(t, nil)
+		enrollTOTP(t, s, 1, now)
+
+		require.NoError(t, s.Reset(ctx, 1))
+
+		factors, err := s.GetFactors(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, factors)
+		count, err := s.store.CountRecoveryCodes(ctx, 1)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		require.NoError(t, s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(nil)))
+	})
+}
+
+func TestIntegrationMFA_WebAuthnLogin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := setupTestService(t, nil)
+	authenticator := newTestAuthenticator(t)
+
+	enrollment, err := s.startWebAuthnEnrollment(ctx, 1, "admin", "Admin")
+	require.NoError(t, err)
+
+	credential := authenticator.create(t, testRPID, testOrigin, enrollment.PublicKey.Challenge)
+	_, err = s.completeWebAuthnEnrollment(ctx, 1, enrollment.EnrollmentToken, "Security key", credential)
+	require.NoError(t, err)
+
+	err = s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(nil))
+	payload := requireMFAError(t, err, "mfa.required")
+	options := payload["webauthn"].(*protocol.PublicKeyCredentialRequestOptions)
+
+	raw := string(authenticator.get(t, testRPID, testOrigin, options.Challenge, 1))
+	require.NoError(t, s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(map[string]string{authn.MetaKeyWebAuthnAssertion: raw})))
+
+	// The challenge is single use.
+	err = s.verifySecondFactorHook(ctx, testIdentity(1, false), passwordRequest(map[string]string{authn.MetaKeyWebAuthnAssertion: raw}))
+	requireMFAError(t, err, "mfa.invalid")
+}
+
+func TestIntegrationMFA_ChangesRequireSecondFactor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	now := time.Unix(1700000000, 0)
+	routeRegister := routing.NewRouteRegister()
+	s := setupTestServiceWithRoutes(t, nil, routeRegister)
+	server := webtest.NewServer(t, routeRegister)
+	signedIn := &user.SignedInUser{UserID: 1, OrgID: 1, Login: "admin"}
+
+	send := func(method, target string, body any) (*http.Response, map[string]any) {
+		t.Helper()
+		var req *http.Request
+		if body == nil {
+			req = server.NewRequest(method, target, nil)
+		} else {
+			raw, err := json.Marshal(body)
+			require.NoError(t, err)
+			req = server.NewRequest(method, target, bytes.NewReader(raw))
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := server.Send(webtest.RequestWithSignedInUser(req, signedIn))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, resp.Body.Close()) }()
+		payload := map[string]any{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+		return resp, payload
+	}
+
+	// The first factor is enrolled without proof, there is nothing to prove yet.
+	resp, _ := send(http.MethodPost, "/api/user/mfa/totp", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	secret, recoveryCodes := enrollTOTP(t, s, 1, now)
+	enrollTOTP(t, s, 1, now)
+	factors, err := s.GetFactors(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, factors, 2)
+	deleteFactor := fmt.Sprintf("/api/user/mfa/factors/%d", factors[0].ID)
+
+	t.Run("changes without a second factor are challenged", func(t *testing.T) {
+		for _, r := range []struct{ method, target string }{
+			{http.MethodPost, "/api/user/mfa/totp"},
+			{http.MethodPost, "/api/user/mfa/webauthn"},
+			{http.MethodPost, "/api/user/mfa/recovery-codes"},
+			{http.MethodDelete, deleteFactor},
+		} {
+			resp, payload := send(r.method, r.target, nil)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, r.target)
+			assert.Equal(t, "mfa.verification-required", payload["messageId"], r.target)
+		}
+	})
+
+	t.Run("changes with an invalid second factor are rejected", func(t *testing.T) {
+		resp, payload := send(http.MethodPost, "/api/user/mfa/recovery-codes", map[string]string{"otp": "000000"})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "mfa.verification-invalid", payload["messageId"])
+	})
+
+	t.Run("changes with a second factor are applied", func(t *testing.T) {
+		resp, payload := send(http.MethodPost, "/api/user/mfa/recovery-codes", map[string]string{"recoveryCode": recoveryCodes[0]})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, payload["recoveryCodes"], 10)
+
+		next := now.Add(totpPeriod * time.Second)
+		s.now = func() time.Time { return next }
+		resp, _ = send(http.MethodDelete, deleteFactor, map[string]string{"otp": currentCode(t, secret, next)})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		factors, err := s.GetFactors(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Len(t, factors, 1)
+	})
+}
+
+func setupTestService(t *testing.T, configure func(cfg *setting.Cfg)) *Service {
+	t.Helper()
+	return setupTestServiceWithRoutes(t, configure, routing.NewRouteRegister())
+}
+
+func setupTestServiceWithRoutes(t *testing.T, configure func(cfg *setting.Cfg), routeRegister routing.RouteRegister) *Service {
+	t.Helper()
+
+	cfg := setting.NewCfg()
+	cfg.AppURL = "https://grafana.example.com/"
+	cfg.MFA = setting.MFASettings{Enabled: true, Issuer: "Grafana", RecoveryCodes: 10, ChallengeTTL: 5 * time.Minute}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	s, err := ProvideService(
+		cfg, db.InitTestDB(t), routeRegister, &authntest.FakeService{},
+		fakes.NewFakeSecretsService(), remotecache.NewFakeCacheStorage(), &orgtest.FakeOrgService{},
+		loginattempttest.FakeLoginAttemptService{ExpectedValid: true},
+	)
+	require.NoError(t, err)
+	return s
+}
+
+func enrollTOTP(t *testing.T, s *Service, userID int64, now time.Time) (string, []string) {
+	t.Helper()
+
+	enrollment, err := s.startTOTPEnrollment(context.Background(), userID, "admin")
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+	_, err = s.completeTOTPEnrollment(context.Background(), userID, enrollment.EnrollmentToken, currentCode(t, enrollment.Secret, now), "Phone")
+	require.NoError(t, err)
+	return enrollment.Secret, enrollment.RecoveryCodes
+}
+
+func currentCode(t *testing.T, secret string, now time.Time) string {
+	t.Helper()
+	code, err := totpCode(secret, now.Unix()/totpPeriod)
+	require.NoError(t, err)
+	return code
+}
+
+func testIdentity(userID int64, isGrafanaAdmin bool) *authn.Identity {
+	return &authn.Identity{ID: fmt.Sprint(userID), Type: claims.TypeUser, Login: "admin", IsGrafanaAdmin: &isGrafanaAdmin}
+}
+
+func passwordRequest(meta map[string]string) *authn.Request {
+	r := &authn.Request{HTTPRequest: &http.Request{Header: http.Header{}, RemoteAddr: "127.0.0.1:1234"}}
+	r.SetMeta(authn.MetaKeyUsername, "admin")
+	r.SetMeta(authn.MetaKeyPasswordVerified, "true")
+	for k, v := range meta {
+		r.SetMeta(k, v)
+	}
+	return r
+}
+
+func requireMFAError(t *testing.T, err error, messageID string) map[string]any {
+	t.Helper()
+	var gfErr errutil.Error
+	require.ErrorAs(t, err, &gfErr)
+	require.Equal(t, messageID, gfErr.MessageID)
+	return gfErr.PublicPayload
+}
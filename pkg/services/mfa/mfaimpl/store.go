@@ -0,0 +1,138 @@
+package mfaimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+type store interface {
+	ListFactors(ctx context.Context, userID int64) ([]*mfa.Factor, error)
+	CreateFactor(ctx context.Context, factor *mfa.Factor) error
+	// UseFactor records a successful verification, it returns false if the counter was already
+	// moved past the given value by a concurrent verification.
+	UseFactor(ctx context.Context, factorID int64, previousCounter, counter int64, now time.Time) (bool, error)
+	DeleteFactor(ctx context.Context, userID, factorID int64) error
+	DeleteAll(ctx context.Context, userID int64) error
+
+	CountRecoveryCodes(ctx context.Context, userID int64) (int64, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error
+	// UseRecoveryCode deletes a recovery code and returns false if it didn't exist.
+	UseRecoveryCode(ctx context.Context, userID int64, hash string) (bool, error)
+}
+
+type recoveryCode struct {
+	ID       int64     `xorm:"pk autoincr 'id'"`
+	UserID   int64     `xorm:"user_id"`
+	CodeHash string    `xorm:"code_hash"`
+	Created  time.Time `xorm:"created"`
+}
+
+func (recoveryCode) TableName() string {
+	return "user_mfa_recovery_code"
+}
+
+type xormStore struct {
+	db db.DB
+}
+
+func (s *xormStore) ListFactors(ctx context.Context, userID int64) ([]*mfa.Factor, error) {
+	factors := make([]*mfa.Factor, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("user_id = ?", userID).Asc("id").Find(&factors)
+	})
+	return factors, err
+}
+
+func (s *xormStore) CreateFactor(ctx context.Context, factor *mfa.Factor) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(factor)
+		return err
+	})
+}
+
+func (s *xormStore) UseFactor(ctx context.Context, factorID int64, previousCounter, counter int64, now time.Time) (bool, error) {
+	var updated bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE user_mfa_factor SET counter = ?, last_used = ? WHERE id = ? AND counter = ?", counter, now, factorID, previousCounter)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		updated = rows > 0
+		return err
+	})
+	return updated, err
+}
+
+func (s *xormStore) DeleteFactor(ctx context.Context, userID, factorID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM user_mfa_factor WHERE id = ? AND user_id = ?", factorID, userID)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return mfa.ErrFactorNotFound.Errorf("factor %d not found", factorID)
+		}
+		return nil
+	})
+}
+
+func (s *xormStore) DeleteAll(ctx context.Context, userID int64) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_mfa_factor WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM user_mfa_recovery_code WHERE user_id = ?", userID)
+		return err
+	})
+}
+
+func (s *xormStore) CountRecoveryCodes(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		count, err = sess.Where("user_id = ?", userID).Count(&recoveryCode{})
+		return err
+	})
+	return count, err
+}
+
+func (s *xormStore) ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_mfa_recovery_code WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		codes := make([]*recoveryCode, 0, len(hashes))
+		for _, h := range hashes {
+			codes = append(codes, &recoveryCode{UserID: userID, CodeHash: h, Created: now})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		_, err := sess.InsertMulti(codes)
+		return err
+	})
+}
+
+func (s *xormStore) UseRecoveryCode(ctx context.Context, userID int64, hash string) (bool, error) {
+	var used bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM user_mfa_recovery_code WHERE user_id = ? AND code_hash = ?", userID, hash)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		used = rows > 0
+		return err
+	})
+	return used, err
+}
@@ -0,0 +1,70 @@
+package mfaimpl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA1 seed of the RFC 6238 test vectors, base32 encoded.
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode(t *testing.T) {
+	// The RFC lists 8 digit codes, we use the last 6.
+	for unix, expected := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		code, err := totpCode(rfc6238Secret, unix/totpPeriod)
+		require.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+
+	t.Run("accepts the current code and adjacent steps", func(t *testing.T) {
+		for _, step := range []int64{current - 1, current, current + 1} {
+			code, err := totpCode(rfc6238Secret, step)
+			require.NoError(t, err)
+			got, ok := validateTOTP(rfc6238Secret, code, now, 0)
+			require.True(t, ok)
+			assert.Equal(t, step, got)
+		}
+	})
+
+	t.Run("accepts lower case secrets and spaces in codes", func(t *testing.T) {
+		_, ok := validateTOTP(strings.ToLower(rfc6238Secret), "050 471", now, 0)
+		assert.True(t, ok)
+	})
+
+	t.Run("rejects codes outside the skew window", func(t *testing.T) {
+		code, err := totpCode(rfc6238Secret, current+2)
+		require.NoError(t, err)
+		_, ok := validateTOTP(rfc6238Secret, code, now, 0)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects codes already used", func(t *testing.T) {
+		_, ok := validateTOTP(rfc6238Secret, "050471", now, current)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects malformed codes", func(t *testing.T) {
+		_, ok := validateTOTP(rfc6238Secret, "12345", now, 0)
+		assert.False(t, ok)
+	})
+}
+
+func TestTOTPURL(t *testing.T) {
+	url := totpURL("Grafana", "admin@example.com", "ABC")
+	assert.Equal(t, "otpauth://totp/Grafana:admin@example.com?algorithm=SHA1&digits=6&issuer=Grafana&period=30&secret=ABC", url)
+}
@@ -0,0 +1,187 @@
+package mfaimpl
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var errWebAuthnInvalid = errors.New("invalid webauthn credential")
+
+// webAuthn registers security keys and verifies the assertions they sign with the go-webauthn library.
+// Only the "none" attestation conveyance is requested, so attestation statements are not verified.
+type webAuthn struct {
+	relyingParty *webauthn.WebAuthn
+}
+
+func newWebAuthn(cfg *setting.Cfg) (*webAuthn, error) {
+	u, err := url.Parse(cfg.AppURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse root url: %w", err)
+	}
+	rpID := cfg.MFA.WebAuthnRPID
+	if rpID == "" {
+		rpID = u.Hostname()
+	}
+
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.MFA.ChallengeTTL, TimeoutUVD: cfg.MFA.ChallengeTTL}
+	relyingParty, err := webauthn.New(&webauthn.Config{
+		RPID:                  rpID,
+		RPDisplayName:         cfg.MFA.Issuer,
+		RPOrigins:             []string{u.Scheme + "://" + u.Host},
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			UserVerification: protocol.VerificationDiscouraged,
+		},
+		Timeouts: webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid webauthn configuration: %w", err)
+	}
+	return &webAuthn{relyingParty: relyingParty}, nil
+}
+
+var _ webauthn.User = (*webAuthnUser)(nil)
+
+// webAuthnUser exposes a user and their security keys to the library. The user handle is derived
+// from the user id so that it is the same during enrollment and login.
+type webAuthnUser struct {
+	id          int64
+	login       string
+	displayName string
+	credentials []webauthn.Credential
+}
+
+func newWebAuthnUser(userID int64, login, displayName string, factors []*mfa.Factor) (*webAuthnUser, error) {
+	u := &webAuthnUser{id: userID, login: login, displayName: displayName}
+	for _, f := range factors {
+		if f.Type != mfa.FactorTypeWebAuthn {
+			continue
+		}
+		credential, err := credentialOf(f)
+		if err != nil {
+			return nil, err
+		}
+		u.credentials = append(u.credentials, credential)
+	}
+	return u, nil
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatInt(u.id, 10))
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.login
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	if u.displayName == "" {
+		return u.login
+	}
+	return u.displayName
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// credentialOf converts a security key factor to the credential record the library verifies assertions against.
+func credentialOf(f *mfa.Factor) (webauthn.Credential, error) {
+	id, err := decodeBase64URL(f.CredentialID)
+	if err != nil {
+		return webauthn.Credential{}, fmt.Errorf("malformed credential id for factor %d: %w", f.ID, err)
+	}
+	publicKey, err := decodeBase64URL(f.PublicKey)
+	if err != nil {
+		return webauthn.Credential{}, fmt.Errorf("malformed public key for factor %d: %w", f.ID, err)
+	}
+	return webauthn.Credential{
+		ID:            id,
+		PublicKey:     publicKey,
+		Flags:         webauthn.CredentialFlags{BackupEligible: f.BackupEligible},
+		Authenticator: webauthn.Authenticator{SignCount: uint32(f.Counter)},
+	}, nil
+}
+
+// beginRegistration returns the options to pass to navigator.credentials.create, excluding the keys
+// the user already registered, and the session to verify the created credential against.
+func (w *webAuthn) beginRegistration(user *webAuthnUser) (*protocol.PublicKeyCredentialCreationOptions, *webauthn.SessionData, error) {
+	exclude := make([]protocol.CredentialDescriptor, 0, len(user.credentials))
+	for _, c := range user.credentials {
+		exclude = append(exclude, c.Descriptor())
+	}
+	creation, session, err := w.relyingParty.BeginRegistration(user, webauthn.WithExclusions(exclude))
+	if err != nil {
+		return nil, nil, err
+	}
+	return &creation.Response, session, nil
+}
+
+// finishRegistration validates a newly created credential against the registration session.
+func (w *webAuthn) finishRegistration(user *webAuthnUser, session webauthn.SessionData, raw []byte) (*webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWebAuthnInvalid, describe(err))
+	}
+	credential, err := w.relyingParty.CreateCredential(user, session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWebAuthnInvalid, describe(err))
+	}
+	return credential, nil
+}
+
+// beginLogin returns the options to pass to navigator.credentials.get and the session to verify the
+// assertion against.
+func (w *webAuthn) beginLogin(user *webAuthnUser) (*protocol.PublicKeyCredentialRequestOptions, *webauthn.SessionData, error) {
+	assertion, session, err := w.relyingParty.BeginLogin(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &assertion.Response, session, nil
+}
+
+// finishLogin validates an assertion signed by one of the keys of the user and returns the credential
+// that signed it, with its new signature counter.
+func (w *webAuthn) finishLogin(user *webAuthnUser, session webauthn.SessionData, raw []byte) (*webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWebAuthnInvalid, describe(err))
+	}
+	credential, err := w.relyingParty.ValidateLogin(user, session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWebAuthnInvalid, describe(err))
+	}
+	// Authenticators that implement a counter must always increase it, anything else hints at a cloned key.
+	if credential.Authenticator.CloneWarning {
+		return nil, fmt.Errorf("%w: signature counter did not increase", errWebAuthnInvalid)
+	}
+	return credential, nil
+}
+
+// describe adds the developer information of library errors, their message alone is often too vague to debug.
+func describe(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.DevInfo != "" {
+		return fmt.Errorf("%w: %s", err, protocolErr.DevInfo)
+	}
+	return err
+}
+
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeBase64URL accepts base64url with or without padding, as sent by the various client libraries.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
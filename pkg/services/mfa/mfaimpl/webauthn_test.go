@@ -0,0 +1,238 @@
+package mfaimpl
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	testRPID   = "grafana.example.com"
+	testOrigin = "https://grafana.example.com"
+)
+
+func TestWebAuthn_Registration(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newTestAuthenticator(t)
+	user := &webAuthnUser{id: 1, login: "admin"}
+
+	options, session, err := w.beginRegistration(user)
+	require.NoError(t, err)
+	challenge := []byte(options.Challenge)
+
+	t.Run("accepts a valid credential", func(t *testing.T) {
+		cred, err := w.finishRegistration(user, *session, authenticator.create(t, testRPID, testOrigin, challenge))
+		require.NoError(t, err)
+		assert.Equal(t, authenticator.id, cred.ID)
+		assert.Equal(t, uint32(0), cred.Authenticator.SignCount)
+	})
+
+	t.Run("rejects another challenge", func(t *testing.T) {
+		_, err := w.finishRegistration(user, *session, authenticator.create(t, testRPID, testOrigin, []byte("other")))
+		require.ErrorIs(t, err, errWebAuthnInvalid)
+	})
+
+	t.Run("rejects another origin", func(t *testing.T) {
+		_, err := w.finishRegistration(user, *session, authenticator.create(t, testRPID, "https://evil.example.com", challenge))
+		require.ErrorIs(t, err, errWebAuthnInvalid)
+	})
+
+	t.Run("rejects another relying party", func(t *testing.T) {
+		_, err := w.finishRegistration(user, *session, authenticator.create(t, "evil.example.com", testOrigin, challenge))
+		require.ErrorIs(t, err, errWebAuthnInvalid)
+	})
+
+	t.Run("rejects another user", func(t *testing.T) {
+		other := &webAuthnUser{id: 2, login: "editor"}
+		_, err := w.finishRegistration(other, *session, authenticator.create(t, testRPID, testOrigin, challenge))
+		require.ErrorIs(t, err, errWebAuthnInvalid)
+	})
+}
+
+func TestWebAuthn_Assertion(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newTestAuthenticator(t)
+	factor := registerTestFactor(t, w, authenticator)
+
+	login := func(t *testing.T, counter int64, sign func(challenge []byte) []byte) error {
+		t.Helper()
+		f := *factor
+		f.Counter = counter
+		user, err := newWebAuthnUser(1, "admin", "", []*mfa.Factor{&f})
+		require.NoError(t, err)
+		options, session, err := w.beginLogin(user)
+		require.NoError(t, err)
+		_, err = w.finishLogin(user, *session, sign(options.Challenge))
+		return err
+	}
+
+	t.Run("accepts a valid assertion", func(t *testing.T) {
+		err := login(t, 4, func(challenge []byte) []byte {
+			return authenticator.get(t, testRPID, testOrigin, challenge, 5)
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects a counter that did not increase", func(t *testing.T) {
+		err := login(t, 5, func(challenge []byte) []byte {
+			return authenticator.get(t, testRPID, testOrigin, challenge, 5)
+		})
+		require.ErrorIs(t, err, errWebAuthnInvalid)
+	})
+
+	t.Run("accepts authenticators without a counter", func(t *testing.T) {
+		err := login(t, 0, func(challenge []byte) []byte {
+			return authenticator.get(t, testRPID, testOrigin, challenge, 0)
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects another challenge", func(t *testing.T) {
+		err := login(t, 5, func(_ []byte) []byte {
+			return authenticator.get(t, testRPID, testOrigin, []byte("other"), 6)
+		})
+		require.ErrorIs(t, err, errWebAuthnInvalid)
+	})
+
+	t.Run("rejects a key of another authenticator", func(t *testing.T) {
+		other := newTestAuthenticator(t)
+		other.id = authenticator.id
+		err := login(t, 0, func(challenge []byte) []byte {
+			return other.get(t, testRPID, testOrigin, challenge, 7)
+		})
+		require.ErrorIs(t, err, errWebAuthnInvalid)
+	})
+}
+
+func newTestWebAuthn(t *testing.T) *webAuthn {
+	t.Helper()
+	cfg := setting.NewCfg()
+	cfg.AppURL = testOrigin + "/"
+	cfg.MFA = setting.MFASettings{Issuer: "Grafana", ChallengeTTL: 5 * time.Minute}
+	w, err := newWebAuthn(cfg)
+	require.NoError(t, err)
+	return w
+}
+
+// registerTestFactor registers the authenticator for the user with id 1 and returns the resulting factor.
+func registerTestFactor(t *testing.T, w *webAuthn, authenticator *testAuthenticator) *mfa.Factor {
+	t.Helper()
+	user := &webAuthnUser{id: 1, login: "admin"}
+	options, session, err := w.beginRegistration(user)
+	require.NoError(t, err)
+	cred, err := w.finishRegistration(user, *session, authenticator.create(t, testRPID, testOrigin, options.Challenge))
+	require.NoError(t, err)
+	return &mfa.Factor{
+		ID:           1,
+		UserID:       1,
+		Type:         mfa.FactorTypeWebAuthn,
+		CredentialID: encodeBase64URL(cred.ID),
+		PublicKey:    encodeBase64URL(cred.PublicKey),
+	}
+}
+
+// testAuthenticator emulates a P-256 security key.
+type testAuthenticator struct {
+	id  []byte
+	key *ecdsa.PrivateKey
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	require.NoError(t, err)
+	return &testAuthenticator{id: id, key: key}
+}
+
+// create returns the JSON serialization of the PublicKeyCredential returned by navigator.credentials.create.
+func (a *testAuthenticator) create(t *testing.T, rpID, origin string, challenge []byte) []byte {
+	t.Helper()
+
+	coseKey, err := webauthncbor.Marshal(map[int]any{
+		1:  int(webauthncose.EllipticKey),
+		3:  int(webauthncose.AlgES256),
+		-1: int(webauthncose.P256),
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	authData := a.authData(rpID, byte(protocol.FlagUserPresent|protocol.FlagAttestedCredentialData), 0)
+	authData = append(authData, make([]byte, 16)...) // aaguid
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.id)))
+	authData = append(authData, a.id...)
+	authData = append(authData, coseKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{"fmt": "none", "attStmt": map[string]any{}, "authData": authData})
+	require.NoError(t, err)
+
+	return mustMarshalBytes(t, map[string]any{
+		"id":    encodeBase64URL(a.id),
+		"rawId": encodeBase64URL(a.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    encodeBase64URL(clientDataJSON(t, "webauthn.create", origin, challenge)),
+			"attestationObject": encodeBase64URL(attestation),
+		},
+	})
+}
+
+// get returns the JSON serialization of the PublicKeyCredential returned by navigator.credentials.get.
+func (a *testAuthenticator) get(t *testing.T, rpID, origin string, challenge []byte, signCount uint32) []byte {
+	t.Helper()
+
+	authData := a.authData(rpID, byte(protocol.FlagUserPresent), signCount)
+	clientData := clientDataJSON(t, "webauthn.get", origin, challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	require.NoError(t, err)
+
+	return mustMarshalBytes(t, map[string]any{
+		"id":    encodeBase64URL(a.id),
+		"rawId": encodeBase64URL(a.id),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    encodeBase64URL(clientData),
+			"authenticatorData": encodeBase64URL(authData),
+			"signature":         encodeBase64URL(signature),
+		},
+	})
+}
+
+func (a *testAuthenticator) authData(rpID string, flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
+
+func clientDataJSON(t *testing.T, typ, origin string, challenge []byte) []byte {
+	t.Helper()
+	return mustMarshalBytes(t, map[string]any{"type": typ, "challenge": encodeBase64URL(challenge), "origin": origin})
+}
+
+func mustMarshalBytes(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,25 @@
+package mfatest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/mfa"
+)
+
+var _ mfa.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedFactors []*mfa.Factor
+	ExpectedErr     error
+
+	ResetUserIDs []int64
+}
+
+func (f *FakeService) GetFactors(ctx context.Context, userID int64) ([]*mfa.Factor, error) {
+	return f.ExpectedFactors, f.ExpectedErr
+}
+
+func (f *FakeService) Reset(ctx context.Context, userID int64) error {
+	f.ResetUserIDs = append(f.ResetUserIDs, userID)
+	return f.ExpectedErr
+}
@@ -21,7 +21,7 @@ func (s *ServiceImpl) getAdminNode(c *contextmodel.ReqContext) (*navtree.NavLink
 	hasAccess := ac.HasAccess(s.accessControl, c)
 	hasGlobalAccess := ac.HasGlobalAccess(s.accessControl, s.authnService, c)
 	orgsAccessEvaluator := ac.EvalPermission(ac.ActionOrgsRead)
-	authConfigUIAvailable := s.license.FeatureEnabled(social.SAMLProviderName) || s.cfg.LDAPAuthEnabled
+	authConfigUIAvailable := s.license.FeatureEnabled(social.SAMLProviderName) || s.features.IsEnabledGlobally(featuremgmt.FlagSamlAuthnClient) || s.cfg.LDAPAuthEnabled
 
 	generalNodeLinks := []*navtree.NavLink{}
 	if hasAccess(ac.OrgPreferencesAccessEvaluator) {
@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -20,6 +21,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/ruletest"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -64,6 +66,7 @@ type API struct {
 	DataProxy            *datasourceproxy.DataSourceProxyService
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	StateManager         *state.Manager
+	InstanceStore        state.InstanceReader
 	Scheduler            StatusReader
 	AccessControl        ac.AccessControl
 	Policies             *provisioning.NotificationPolicyService
@@ -71,6 +74,8 @@ type API struct {
 	ContactPointService  *provisioning.ContactPointService
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
+	SilenceTemplates     *provisioning.SilenceTemplateService
+	DeliveryLog          *notifier.NotificationDeliveryService
 	AlertRules           *provisioning.AlertRuleService
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
@@ -80,6 +85,7 @@ type API struct {
 	Tracer               tracing.Tracer
 	AppUrl               *url.URL
 	UserService          user.Service
+	FolderService        folder.Service
 
 	// Hooks can be used to replace API handlers for specific paths.
 	Hooks *Hooks
@@ -93,6 +99,14 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		ac:        api.AccessControl,
 	}
 	ruleAuthzService := accesscontrol.NewRuleService(api.AccessControl)
+	silenceSvc := notifier.NewSilenceService(
+		accesscontrol.NewSilenceService(api.AccessControl, api.RuleStore),
+		api.TransactionManager,
+		logger,
+		api.MultiOrgAlertmanager,
+		api.RuleStore,
+		ruleAuthzService,
+	)
 
 	// Register endpoints for proxying to Alertmanager-compatible backends.
 	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
@@ -104,15 +118,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			ac:             api.AccessControl,
 			mam:            api.MultiOrgAlertmanager,
 			featureManager: api.FeatureManager,
-			silenceSvc: notifier.NewSilenceService(
-				accesscontrol.NewSilenceService(api.AccessControl, api.RuleStore),
-				api.TransactionManager,
-				logger,
-				api.MultiOrgAlertmanager,
-				api.RuleStore,
-				ruleAuthzService,
-			),
-			receiverAuthz: accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
+			silenceSvc:     silenceSvc,
+			receiverAuthz:  accesscontrol.NewReceiverAccess[ReceiverStatus](api.AccessControl, false),
 		},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
@@ -149,6 +156,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
 			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory, api.Tracer),
+			ruleTests:       ruletest.NewEngine(api.AppUrl, api.Cfg, api.FeatureManager, api.Tracer),
 			featureManager:  api.FeatureManager,
 			appUrl:          api.AppUrl,
 			tracer:          api.Tracer,
@@ -161,6 +169,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:                  logger,
 			alertmanagerProvider: api.AlertsRouter,
 			featureManager:       api.FeatureManager,
+			stateSnapshotter:     api.StateManager,
+			ruleStore:            api.RuleStore,
+			haEnabled:            api.Cfg.UnifiedAlerting.HARedisAddr != "" || len(api.Cfg.UnifiedAlerting.HAPeers) > 0,
 		},
 	), m)
 
@@ -170,7 +181,11 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		contactPointService: api.ContactPointService,
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
+		silenceTemplates:    api.SilenceTemplates,
+		silences:            silenceSvc,
 		alertRules:          api.AlertRules,
+		folderSvc:           api.FolderService,
+		xact:                api.TransactionManager,
 		// XXX: Used to flag recording rules, remove when FT is removed
 		featureManager: api.FeatureManager,
 	}), m)
@@ -180,15 +195,29 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		hist:   api.Historian,
 	}), m)
 
-	api.RegisterNotificationsApiEndpoints(NewNotificationsApi(&NotificationSrv{
+	notificationSrv := &NotificationSrv{
 		logger:            logger,
 		receiverService:   api.ReceiverService,
 		muteTimingService: api.MuteTimings,
-	}), m)
+		routingSimulator:  api.MultiOrgAlertmanager,
+		ruleStore:         api.RuleStore,
+		ruleAuthz:         ruleAuthzService,
+		stateManager:      api.StateManager,
+		instanceStore:     api.InstanceStore,
+		silenceSvc:        silenceSvc,
+	}
+	if api.DeliveryLog != nil {
+		notificationSrv.deliveryLog = api.DeliveryLog
+	}
+	api.RegisterNotificationsApiEndpoints(NewNotificationsApi(notificationSrv), m)
 
 	if api.FeatureManager.IsEnabledGlobally(featuremgmt.FlagAlertingConversionAPI) {
-		api.RegisterConvertPrometheusApiEndpoints(NewConvertPrometheusApi(&ConvertPrometheusSrv{
-			logger: logger,
-		}), m)
+		api.RegisterConvertPrometheusApiEndpoints(NewConvertPrometheusApi(NewConvertPrometheusSrv(
+			&api.Cfg.UnifiedAlerting,
+			logger,
+			api.RuleStore,
+			api.DatasourceCache,
+			api.AlertRules,
+		)), m)
 	}
 }
@@ -20,12 +20,23 @@ import (
 	"github.com/grafana/grafana/pkg/util"
 )
 
+// AlertStateSnapshotter exports and imports the state of alert instances.
+type AlertStateSnapshotter interface {
+	ExportSnapshot(ctx context.Context, orgID int64) (ngmodels.AlertStateSnapshot, error)
+	ImportSnapshot(ctx context.Context, orgID int64, snapshot ngmodels.AlertStateSnapshot, rules []*ngmodels.AlertRule) (ngmodels.AlertStateSnapshotImportResult, error)
+}
+
 type ConfigSrv struct {
 	datasourceService    datasources.DataSourceService
 	alertmanagerProvider ExternalAlertmanagerProvider
 	store                store.AdminConfigurationStore
 	log                  log.Logger
 	featureManager       featuremgmt.FeatureToggles
+	stateSnapshotter     AlertStateSnapshotter
+	ruleStore            RuleStore
+	// haEnabled is true when Grafana runs in a high availability cluster, where the state of alerts can't be
+	// imported while Grafana is running because every instance holds its own state.
+	haEnabled bool
 }
 
 func (srv ConfigSrv) RouteGetAlertmanagers(c *contextmodel.ReqContext) response.Response {
@@ -167,3 +178,43 @@ func (srv ConfigSrv) RouteGetAlertingStatus(c *contextmodel.ReqContext) response
 	}
 	return response.JSON(http.StatusOK, resp)
 }
+
+func (srv ConfigSrv) RouteGetAlertStateSnapshot(c *contextmodel.ReqContext) response.Response {
+	if c.SignedInUser.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	snapshot, err := srv.stateSnapshotter.ExportSnapshot(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to export the state of alerts")
+	}
+	return response.JSON(http.StatusOK, ApiAlertStateSnapshotFromModel(snapshot))
+}
+
+func (srv ConfigSrv) RoutePostAlertStateSnapshot(c *contextmodel.ReqContext, body apimodels.AlertStateSnapshot) response.Response {
+	if c.SignedInUser.GetOrgRole() != org.RoleAdmin {
+		return accessForbiddenResp()
+	}
+
+	if srv.haEnabled {
+		return ErrResp(http.StatusBadRequest, errors.New("the state of alerts can't be imported while Grafana runs in high availability mode, stop all instances and import it with the Grafana CLI"), "")
+	}
+
+	orgID := c.SignedInUser.GetOrgID()
+	rules, err := srv.ruleStore.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to list alert rules")
+	}
+
+	result, err := srv.stateSnapshotter.ImportSnapshot(c.Req.Context(), orgID, AlertStateSnapshotFromApi(body), rules)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrInvalidAlertStateSnapshot) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to import the state of alerts")
+	}
+	return response.JSON(http.StatusOK, apimodels.AlertStateSnapshotImportResult{
+		Imported:     result.Imported,
+		MissingRules: result.MissingRules,
+	})
+}
@@ -123,6 +123,19 @@ func TestExternalAlertmanagerChoice(t *testing.T) {
 	}
 }
 
+func TestRoutePostAlertStateSnapshot(t *testing.T) {
+	t.Run("rejects the import in high availability mode", func(t *testing.T) {
+		sut := createAPIAdminSut(t, nil, featuremgmt.WithFeatures())
+		sut.haEnabled = true
+
+		ctx := createRequestCtxInOrg(1)
+		ctx.OrgRole = org.RoleAdmin
+		resp := sut.RoutePostAlertStateSnapshot(ctx, definitions.AlertStateSnapshot{})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Contains(t, string(resp.Body()), "high availability")
+	})
+}
+
 func createAPIAdminSut(t *testing.T,
 	datasources []*datasources.DataSource, features featuremgmt.FeatureToggles) ConfigSrv {
 	return ConfigSrv{
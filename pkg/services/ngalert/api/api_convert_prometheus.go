@@ -1,36 +1,404 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	datasourceUIDHeader        = "X-Datasource-UID"
+	recordingRulesPausedHeader = "X-Recording-Rules-Paused"
+	alertRulesPausedHeader     = "X-Alert-Rules-Paused"
+)
+
+var (
+	errDatasourceUIDHeaderMissing = errutil.ValidationFailed(
+		"alerting.datasourceUIDHeaderMissing",
+		errutil.WithPublicMessage(fmt.Sprintf("Missing datasource UID header: %s", datasourceUIDHeader)),
+	).Errorf("missing datasource UID header")
+
+	errInvalidHeaderValue = errutil.ValidationFailed("alerting.invalidHeaderValue").MustTemplate(
+		"invalid value for header {{.Public.Header}}: must be 'true' or 'false'",
+		errutil.WithPublic("Invalid value for header {{.Public.Header}}: must be 'true' or 'false'"),
+	)
+
+	errRuleGroupNotConverted = errutil.NotFound(
+		"alerting.ruleGroupNotConverted",
+		errutil.WithPublicMessage("The rule group was not found or was not imported in the Prometheus format"),
+	)
+	errRuleGroupNotConvertedConflict = errutil.Conflict(
+		"alerting.ruleGroupNotConvertedConflict",
+		errutil.WithPublicMessage("A rule group with the same name exists and was not imported in the Prometheus format"),
+	)
 )
 
+// ConvertPrometheusSrv imports rule groups in the Prometheus format as Grafana-managed rules.
+// Each namespace is mapped to the folder with the same title at the root level, and the
+// converted rules are marked with the ProvenanceConvertedPrometheus provenance.
 type ConvertPrometheusSrv struct {
-	logger log.Logger
+	cfg              *setting.UnifiedAlertingSettings
+	logger           log.Logger
+	ruleStore        RuleStore
+	datasourceCache  datasources.CacheService
+	alertRuleService *provisioning.AlertRuleService
 }
 
+func NewConvertPrometheusSrv(cfg *setting.UnifiedAlertingSettings, logger log.Logger, ruleStore RuleStore, datasourceCache datasources.CacheService, alertRuleService *provisioning.AlertRuleService) *ConvertPrometheusSrv {
+	return &ConvertPrometheusSrv{
+		cfg:              cfg,
+		logger:           logger,
+		ruleStore:        ruleStore,
+		datasourceCache:  datasourceCache,
+		alertRuleService: alertRuleService,
+	}
+}
+
+// RouteConvertPrometheusGetRules returns all the converted rule groups, by namespace.
 func (srv *ConvertPrometheusSrv) RouteConvertPrometheusGetRules(c *contextmodel.ReqContext) response.Response {
-	return response.Error(501, "Not implemented", nil)
+	groups, err := srv.alertRuleService.GetAlertGroupsWithFolderFullpath(c.Req.Context(), c.SignedInUser, nil)
+	if err != nil {
+		return errorToResponse(err)
+	}
+
+	namespaces := map[string][]apimodels.PrometheusRuleGroup{}
+	for _, group := range groups {
+		if !isConvertedGroup(group.Rules) {
+			continue
+		}
+		promGroup, err := grafanaRuleGroupToPrometheus(group.Title, group.Interval, group.Rules)
+		if err != nil {
+			return errorToResponse(err)
+		}
+		namespaces[group.FolderFullpath] = append(namespaces[group.FolderFullpath], promGroup)
+	}
+
+	return response.YAML(http.StatusOK, namespaces)
 }
 
+// RouteConvertPrometheusDeleteNamespace deletes all the converted rule groups of the namespace.
 func (srv *ConvertPrometheusSrv) RouteConvertPrometheusDeleteNamespace(c *contextmodel.ReqContext, namespaceTitle string) response.Response {
-	return response.Error(501, "Not implemented", nil)
+	logger := srv.logger.New("namespace_title", namespaceTitle)
+
+	namespace, err := srv.ruleStore.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	groups, err := srv.alertRuleService.GetAlertGroupsWithFolderFullpath(c.Req.Context(), c.SignedInUser, []string{namespace.UID})
+	if err != nil {
+		return errorToResponse(err)
+	}
+
+	for _, group := range groups {
+		if !isConvertedGroup(group.Rules) {
+			continue
+		}
+		if err := srv.alertRuleService.DeleteRuleGroup(c.Req.Context(), c.SignedInUser, namespace.UID, group.Title, models.ProvenanceConvertedPrometheus); err != nil {
+			return convertPrometheusErrorToResponse(err)
+		}
+		logger.Info("Deleted converted rule group", "group", group.Title)
+	}
+
+	return successfulConvertResponse()
 }
 
+// RouteConvertPrometheusDeleteRuleGroup deletes a converted rule group.
 func (srv *ConvertPrometheusSrv) RouteConvertPrometheusDeleteRuleGroup(c *contextmodel.ReqContext, namespaceTitle string, group string) response.Response {
-	return response.Error(501, "Not implemented", nil)
+	namespace, err := srv.ruleStore.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	if _, err := srv.getConvertedRuleGroup(c, namespace.UID, group); err != nil {
+		return errorToResponse(err)
+	}
+
+	if err := srv.alertRuleService.DeleteRuleGroup(c.Req.Context(), c.SignedInUser, namespace.UID, group, models.ProvenanceConvertedPrometheus); err != nil {
+		return convertPrometheusErrorToResponse(err)
+	}
+
+	srv.logger.Info("Deleted converted rule group", "namespace_title", namespaceTitle, "group", group)
+	return successfulConvertResponse()
 }
 
+// RouteConvertPrometheusGetNamespace returns the converted rule groups of the namespace.
 func (srv *ConvertPrometheusSrv) RouteConvertPrometheusGetNamespace(c *contextmodel.ReqContext, namespaceTitle string) response.Response {
-	return response.Error(501, "Not implemented", nil)
+	namespace, err := srv.ruleStore.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	groups, err := srv.alertRuleService.GetAlertGroupsWithFolderFullpath(c.Req.Context(), c.SignedInUser, []string{namespace.UID})
+	if err != nil {
+		return errorToResponse(err)
+	}
+
+	promGroups := make([]apimodels.PrometheusRuleGroup, 0, len(groups))
+	for _, group := range groups {
+		if !isConvertedGroup(group.Rules) {
+			continue
+		}
+		promGroup, err := grafanaRuleGroupToPrometheus(group.Title, group.Interval, group.Rules)
+		if err != nil {
+			return errorToResponse(err)
+		}
+		promGroups = append(promGroups, promGroup)
+	}
+
+	return response.YAML(http.StatusOK, map[string][]apimodels.PrometheusRuleGroup{
+		namespaceTitle: promGroups,
+	})
 }
 
+// RouteConvertPrometheusGetRuleGroup returns a converted rule group in the Prometheus format.
 func (srv *ConvertPrometheusSrv) RouteConvertPrometheusGetRuleGroup(c *contextmodel.ReqContext, namespaceTitle string, group string) response.Response {
-	return response.Error(501, "Not implemented", nil)
+	namespace, err := srv.ruleStore.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	ruleGroup, err := srv.getConvertedRuleGroup(c, namespace.UID, group)
+	if err != nil {
+		return errorToResponse(err)
+	}
+
+	promGroup, err := grafanaRuleGroupToPrometheus(ruleGroup.Title, ruleGroup.Interval, ruleGroup.Rules)
+	if err != nil {
+		return errorToResponse(err)
+	}
+
+	return response.YAML(http.StatusOK, promGroup)
+}
+
+// RouteConvertPrometheusPostRuleGroup converts the rule group to Grafana-managed rules, in the folder
+// with the title of the namespace, and replaces the group if it exists.
+func (srv *ConvertPrometheusSrv) RouteConvertPrometheusPostRuleGroup(c *contextmodel.ReqContext, namespaceTitle string, promGroup apimodels.PrometheusRuleGroup) response.Response {
+	logger := srv.logger.New("namespace_title", namespaceTitle, "group", promGroup.Name)
+
+	datasourceUID := strings.TrimSpace(c.Req.Header.Get(datasourceUIDHeader))
+	if datasourceUID == "" {
+		return response.Err(errDatasourceUIDHeaderMissing)
+	}
+	recordingRulesPaused, err := parseBooleanHeader(c.Req.Header.Get(recordingRulesPausedHeader), recordingRulesPausedHeader)
+	if err != nil {
+		return response.Err(err)
+	}
+	alertRulesPaused, err := parseBooleanHeader(c.Req.Header.Get(alertRulesPausedHeader), alertRulesPausedHeader)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	ds, err := srv.datasourceCache.GetDatasourceByUID(c.Req.Context(), datasourceUID, c.SignedInUser, c.SkipDSCache)
+	if err != nil {
+		return errorToResponse(err)
+	}
+
+	namespace, err := srv.ruleStore.GetOrCreateNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	converter, err := prom.NewConverter(prom.Config{
+		DatasourceUID:  ds.UID,
+		DatasourceType: ds.Type,
+		RecordingRules: prom.RulesConfig{IsPaused: recordingRulesPaused},
+		AlertRules:     prom.RulesConfig{IsPaused: alertRulesPaused},
+	})
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	group, err := converter.PrometheusRulesToGrafana(c.SignedInUser.GetOrgID(), namespace.UID, srv.toPrometheusRuleGroup(promGroup))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	if err := srv.keepRuleUIDs(c, group); err != nil {
+		return errorToResponse(err)
+	}
+
+	if err := srv.alertRuleService.ReplaceRuleGroup(c.Req.Context(), c.SignedInUser, *group, models.ProvenanceConvertedPrometheus); err != nil {
+		return convertPrometheusErrorToResponse(err)
+	}
+
+	logger.Info("Converted Prometheus rule group", "namespace_uid", namespace.UID, "rules", len(group.Rules), "datasource_uid", ds.UID)
+	return successfulConvertResponse()
+}
+
+// getConvertedRuleGroup returns the rule group if it was imported in the Prometheus format.
+func (srv *ConvertPrometheusSrv) getConvertedRuleGroup(c *contextmodel.ReqContext, namespaceUID, group string) (models.AlertRuleGroup, error) {
+	ruleGroup, err := srv.alertRuleService.GetRuleGroup(c.Req.Context(), c.SignedInUser, namespaceUID, group)
+	if err != nil {
+		if errors.Is(err, models.ErrAlertRuleGroupNotFound) {
+			return models.AlertRuleGroup{}, errRuleGroupNotConverted.Errorf("rule group %s not found", group)
+		}
+		return models.AlertRuleGroup{}, err
+	}
+	if !isConvertedGroup(ruleGroup.Rules) {
+		return models.AlertRuleGroup{}, errRuleGroupNotConverted.Errorf("rule group %s was not imported in the Prometheus format", group)
+	}
+	return ruleGroup, nil
+}
+
+// keepRuleUIDs reuses the UIDs of the existing rules of the group, so that syncing the same group again updates the
+// rules instead of recreating them. Prometheus rules have no identifier, so a rule is matched first with the existing
+// rule of the same original definition, which keeps the UIDs of unchanged rules when they are reordered, and then with
+// the existing rule of the same title. Titles are the alert or record names, suffixed with the occurrence of the name
+// in the group when it is not unique, so a changed rule whose name is repeated in the group may take the UID of
+// another rule with that name.
+// It returns a conflict if the group exists and was not imported in the Prometheus format.
+func (srv *ConvertPrometheusSrv) keepRuleUIDs(c *contextmodel.ReqContext, group *models.AlertRuleGroup) error {
+	existing, err := srv.ruleStore.ListAlertRules(c.Req.Context(), &models.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		NamespaceUIDs: []string{group.FolderUID},
+		RuleGroups:    []string{group.Title},
+	})
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 && !slices.ContainsFunc(existing, func(rule *models.AlertRule) bool { return isConvertedRule(*rule) }) {
+		return errRuleGroupNotConvertedConflict.Errorf("rule group %s exists and was not imported in the Prometheus format", group.Title)
+	}
+
+	byDefinition := make(map[string][]string, len(existing))
+	byTitle := make(map[string]string, len(existing))
+	for _, rule := range existing {
+		if isConvertedRule(*rule) {
+			definition := rule.Metadata.PrometheusStyleRule.OriginalRuleDefinition
+			byDefinition[definition] = append(byDefinition[definition], rule.UID)
+		}
+		byTitle[rule.Title] = rule.UID
+	}
+
+	used := make(map[string]struct{}, len(existing))
+	for i := range group.Rules {
+		rule := &group.Rules[i]
+		if !isConvertedRule(*rule) {
+			continue
+		}
+		definition := rule.Metadata.PrometheusStyleRule.OriginalRuleDefinition
+		if uids := byDefinition[definition]; len(uids) > 0 {
+			rule.UID = uids[0]
+			used[uids[0]] = struct{}{}
+			byDefinition[definition] = uids[1:]
+		}
+	}
+	for i := range group.Rules {
+		rule := &group.Rules[i]
+		if rule.UID != "" {
+			continue
+		}
+		if uid, ok := byTitle[rule.Title]; ok {
+			if _, taken := used[uid]; !taken {
+				rule.UID = uid
+				used[uid] = struct{}{}
+			}
+		}
+	}
+	return nil
+}
+
+// toPrometheusRuleGroup converts the API model of the rule group to the model of the converter.
+// The interval defaults to the default evaluation interval, like the global evaluation_interval of Prometheus.
+func (srv *ConvertPrometheusSrv) toPrometheusRuleGroup(group apimodels.PrometheusRuleGroup) prom.PrometheusRuleGroup {
+	interval := group.Interval
+	if interval == 0 {
+		interval = prommodel.Duration(srv.cfg.DefaultRuleEvaluationInterval)
+	}
+
+	rules := make([]prom.PrometheusRule, 0, len(group.Rules))
+	for _, r := range group.Rules {
+		rules = append(rules, prom.PrometheusRule{
+			Alert:         r.Alert,
+			Expr:          r.Expr,
+			For:           r.For,
+			KeepFiringFor: r.KeepFiringFor,
+			Labels:        r.Labels,
+			Annotations:   r.Annotations,
+			Record:        r.Record,
+		})
+	}
+
+	return prom.PrometheusRuleGroup{
+		Name:     group.Name,
+		Interval: interval,
+		Rules:    rules,
+	}
+}
+
+// grafanaRuleGroupToPrometheus returns the rule group in the Prometheus format, using the original definitions of the rules.
+func grafanaRuleGroupToPrometheus(title string, intervalSeconds int64, rules []models.AlertRule) (apimodels.PrometheusRuleGroup, error) {
+	promGroup := apimodels.PrometheusRuleGroup{
+		Name:     title,
+		Interval: prommodel.Duration(time.Duration(intervalSeconds) * time.Second),
+		Rules:    make([]apimodels.PrometheusRule, 0, len(rules)),
+	}
+
+	models.SortAlertRulesByGroupIndex(rules)
+	for _, rule := range rules {
+		if !isConvertedRule(rule) {
+			continue
+		}
+		var promRule apimodels.PrometheusRule
+		if err := yaml.Unmarshal([]byte(rule.Metadata.PrometheusStyleRule.OriginalRuleDefinition), &promRule); err != nil {
+			return apimodels.PrometheusRuleGroup{}, fmt.Errorf("failed to unmarshal the original definition of rule %s: %w", rule.UID, err)
+		}
+		promGroup.Rules = append(promGroup.Rules, promRule)
+	}
+
+	return promGroup, nil
+}
+
+func isConvertedRule(rule models.AlertRule) bool {
+	return rule.Metadata.PrometheusStyleRule != nil && rule.Metadata.PrometheusStyleRule.OriginalRuleDefinition != ""
+}
+
+func isConvertedGroup(rules []models.AlertRule) bool {
+	for _, rule := range rules {
+		if isConvertedRule(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseBooleanHeader(value, header string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errInvalidHeaderValue.Build(errutil.TemplateData{Public: map[string]any{"Header": header}})
+	}
+	return result, nil
+}
+
+func convertPrometheusErrorToResponse(err error) response.Response {
+	if errors.Is(err, models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	return errorToResponse(err)
 }
 
-func (srv *ConvertPrometheusSrv) RouteConvertPrometheusPostRuleGroup(c *contextmodel.ReqContext, namespaceTitle string, prometheusGroup apimodels.PrometheusRuleGroup) response.Response {
-	return response.Error(501, "Not implemented", nil)
+func successfulConvertResponse() response.Response {
+	return response.JSON(http.StatusAccepted, apimodels.ConvertPrometheusResponse{Status: "success"})
 }
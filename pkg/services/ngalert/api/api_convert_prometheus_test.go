@@ -0,0 +1,273 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestConvertPrometheusSrv(t *testing.T) {
+	forDuration := prommodel.Duration(5 * time.Minute)
+	promGroup := apimodels.PrometheusRuleGroup{
+		Name:     "test-group",
+		Interval: prommodel.Duration(30 * time.Second),
+		Rules: []apimodels.PrometheusRule{
+			{
+				Alert:       "HighErrorRate",
+				Expr:        `rate(errors_total[5m]) > 0.1`,
+				For:         &forDuration,
+				Labels:      map[string]string{"severity": "critical"},
+				Annotations: map[string]string{"summary": "Error rate is high"},
+			},
+			{
+				Record: "job:errors:rate5m",
+				Expr:   `sum by (job) (rate(errors_total[5m]))`,
+			},
+		},
+	}
+
+	t.Run("rule group is converted and returned in the Prometheus format", func(t *testing.T) {
+		srv, env := createConvertPrometheusSrv(t)
+
+		rc := createTestRequestCtx()
+		rc.Req.Header.Set(datasourceUIDHeader, "prometheus-uid")
+		resp := srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", promGroup)
+		require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+
+		rc = createTestRequestCtx()
+		resp = srv.RouteConvertPrometheusGetRuleGroup(&rc, "prom-namespace", "test-group")
+		require.Equal(t, http.StatusOK, resp.Status(), string(resp.Body()))
+		var group apimodels.PrometheusRuleGroup
+		require.NoError(t, yaml.Unmarshal(resp.Body(), &group))
+		require.Equal(t, promGroup, group)
+
+		rc = createTestRequestCtx()
+		resp = srv.RouteConvertPrometheusGetRules(&rc)
+		require.Equal(t, http.StatusOK, resp.Status(), string(resp.Body()))
+		var namespaces map[string][]apimodels.PrometheusRuleGroup
+		require.NoError(t, yaml.Unmarshal(resp.Body(), &namespaces))
+		require.Equal(t, map[string][]apimodels.PrometheusRuleGroup{"prom-namespace": {promGroup}}, namespaces)
+
+		rules, err := env.store.ListAlertRules(rc.Req.Context(), &models.ListAlertRulesQuery{OrgID: 1, RuleGroups: []string{"test-group"}})
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		for _, rule := range rules {
+			provenance, err := env.store.GetProvenance(rc.Req.Context(), rule, 1)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceConvertedPrometheus, provenance)
+		}
+	})
+
+	t.Run("syncing the same group again keeps the rules", func(t *testing.T) {
+		srv, env := createConvertPrometheusSrv(t)
+
+		rc := createTestRequestCtx()
+		rc.Req.Header.Set(datasourceUIDHeader, "prometheus-uid")
+		resp := srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", promGroup)
+		require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+		before, err := env.store.ListAlertRules(rc.Req.Context(), &models.ListAlertRulesQuery{OrgID: 1, RuleGroups: []string{"test-group"}})
+		require.NoError(t, err)
+
+		resp = srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", promGroup)
+		require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+		after, err := env.store.ListAlertRules(rc.Req.Context(), &models.ListAlertRulesQuery{OrgID: 1, RuleGroups: []string{"test-group"}})
+		require.NoError(t, err)
+
+		require.ElementsMatch(t, ruleUIDs(before), ruleUIDs(after))
+	})
+
+	t.Run("reordered rules keep their UIDs", func(t *testing.T) {
+		srv, env := createConvertPrometheusSrv(t)
+
+		group := apimodels.PrometheusRuleGroup{
+			Name: "test-group",
+			Rules: []apimodels.PrometheusRule{
+				{Alert: "Down", Expr: "up == 0", Labels: map[string]string{"severity": "warning"}},
+				{Alert: "Down", Expr: "up == 0", For: &forDuration, Labels: map[string]string{"severity": "critical"}},
+			},
+		}
+		rc := createTestRequestCtx()
+		rc.Req.Header.Set(datasourceUIDHeader, "prometheus-uid")
+		resp := srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", group)
+		require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+		before := uidsBySeverity(t, env)
+
+		group.Rules[0], group.Rules[1] = group.Rules[1], group.Rules[0]
+		resp = srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", group)
+		require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+
+		require.Equal(t, before, uidsBySeverity(t, env))
+	})
+
+	t.Run("group that was not converted is not replaced", func(t *testing.T) {
+		srv, env := createConvertPrometheusSrv(t)
+
+		rc := createTestRequestCtx()
+		rc.Req.Header.Set(datasourceUIDHeader, "prometheus-uid")
+		namespace, err := env.store.GetOrCreateNamespaceByTitle(rc.Req.Context(), "prom-namespace", 1, rc.SignedInUser)
+		require.NoError(t, err)
+		gen := models.RuleGen
+		rule := gen.With(gen.WithOrgID(1), gen.WithNamespaceUID(namespace.UID), gen.WithGroupName("test-group"), gen.WithInterval(time.Minute)).Generate()
+		_, err = env.store.InsertAlertRules(rc.Req.Context(), nil, []models.AlertRule{rule})
+		require.NoError(t, err)
+
+		resp := srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", promGroup)
+		require.Equal(t, http.StatusConflict, resp.Status(), string(resp.Body()))
+
+		rules, err := env.store.ListAlertRules(rc.Req.Context(), &models.ListAlertRulesQuery{OrgID: 1, RuleGroups: []string{"test-group"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{rule.UID}, ruleUIDs(rules))
+	})
+
+	t.Run("deleted rule group is not found", func(t *testing.T) {
+		srv, _ := createConvertPrometheusSrv(t)
+
+		rc := createTestRequestCtx()
+		rc.Req.Header.Set(datasourceUIDHeader, "prometheus-uid")
+		resp := srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", promGroup)
+		require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+
+		resp = srv.RouteConvertPrometheusDeleteRuleGroup(&rc, "prom-namespace", "test-group")
+		require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+
+		resp = srv.RouteConvertPrometheusGetRuleGroup(&rc, "prom-namespace", "test-group")
+		require.Equal(t, http.StatusNotFound, resp.Status(), string(resp.Body()))
+	})
+
+	t.Run("deleting the namespace deletes its rule groups", func(t *testing.T) {
+		srv, _ := createConvertPrometheusSrv(t)
+
+		rc := createTestRequestCtx()
+		rc.Req.Header.Set(datasourceUIDHeader, "prometheus-uid")
+		for _, name := range []string{"group-1", "group-2"} {
+			// rule titles must be unique in the folder
+			group := apimodels.PrometheusRuleGroup{
+				Name:  name,
+				Rules: []apimodels.PrometheusRule{{Alert: name + "-alert", Expr: "up == 0"}},
+			}
+			resp := srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", group)
+			require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+		}
+
+		resp := srv.RouteConvertPrometheusDeleteNamespace(&rc, "prom-namespace")
+		require.Equal(t, http.StatusAccepted, resp.Status(), string(resp.Body()))
+
+		resp = srv.RouteConvertPrometheusGetNamespace(&rc, "prom-namespace")
+		require.Equal(t, http.StatusOK, resp.Status(), string(resp.Body()))
+		var namespaces map[string][]apimodels.PrometheusRuleGroup
+		require.NoError(t, yaml.Unmarshal(resp.Body(), &namespaces))
+		require.Empty(t, namespaces["prom-namespace"])
+	})
+
+	t.Run("unknown namespace is not found", func(t *testing.T) {
+		srv, _ := createConvertPrometheusSrv(t)
+
+		rc := createTestRequestCtx()
+		resp := srv.RouteConvertPrometheusGetNamespace(&rc, "unknown")
+		require.Equal(t, http.StatusNotFound, resp.Status(), string(resp.Body()))
+	})
+
+	t.Run("invalid requests are rejected", func(t *testing.T) {
+		srv, _ := createConvertPrometheusSrv(t)
+
+		testCases := []struct {
+			name    string
+			headers map[string]string
+			group   apimodels.PrometheusRuleGroup
+			status  int
+		}{
+			{
+				name:   "missing datasource header",
+				group:  promGroup,
+				status: http.StatusBadRequest,
+			},
+			{
+				name:    "invalid paused header",
+				headers: map[string]string{datasourceUIDHeader: "prometheus-uid", alertRulesPausedHeader: "maybe"},
+				group:   promGroup,
+				status:  http.StatusBadRequest,
+			},
+			{
+				name:    "unknown datasource",
+				headers: map[string]string{datasourceUIDHeader: "unknown"},
+				group:   promGroup,
+				status:  http.StatusNotFound,
+			},
+			{
+				name:    "unsupported datasource type",
+				headers: map[string]string{datasourceUIDHeader: "mysql-uid"},
+				group:   promGroup,
+				status:  http.StatusBadRequest,
+			},
+			{
+				name:    "unsupported rule field",
+				headers: map[string]string{datasourceUIDHeader: "prometheus-uid"},
+				group: apimodels.PrometheusRuleGroup{
+					Name:  "test-group",
+					Rules: []apimodels.PrometheusRule{{Alert: "a", Expr: "up == 0", KeepFiringFor: &forDuration}},
+				},
+				status: http.StatusBadRequest,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				rc := createTestRequestCtx()
+				for k, v := range tc.headers {
+					rc.Req.Header.Set(k, v)
+				}
+				resp := srv.RouteConvertPrometheusPostRuleGroup(&rc, "prom-namespace", tc.group)
+				require.Equal(t, tc.status, resp.Status(), string(resp.Body()))
+			})
+		}
+	})
+}
+
+func createConvertPrometheusSrv(t *testing.T) (*ConvertPrometheusSrv, testEnvironment) {
+	t.Helper()
+
+	env := createTestEnv(t, testConfig)
+	alertRuleService := provisioning.NewAlertRuleService(env.store, env.store, env.folderService, env.quotas, env.xact, 60, 10, 100, env.log, &provisioning.NotificationSettingsValidatorProviderFake{}, env.rulesAuthz)
+	dsCache := &fakeDatasources.FakeCacheService{
+		DataSources: []*datasources.DataSource{
+			{UID: "prometheus-uid", Type: datasources.DS_PROMETHEUS},
+			{UID: "mysql-uid", Type: datasources.DS_MYSQL},
+		},
+	}
+	cfg := &setting.UnifiedAlertingSettings{DefaultRuleEvaluationInterval: time.Minute}
+
+	return NewConvertPrometheusSrv(cfg, log.NewNopLogger(), env.store, dsCache, alertRuleService), env
+}
+
+func ruleUIDs(rules models.RulesGroup) []string {
+	uids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		uids = append(uids, rule.UID)
+	}
+	return uids
+}
+
+func uidsBySeverity(t *testing.T, env testEnvironment) map[string]string {
+	t.Helper()
+
+	rules, err := env.store.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: 1, RuleGroups: []string{"test-group"}})
+	require.NoError(t, err)
+	uids := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		uids[rule.Labels["severity"]] = rule.UID
+	}
+	return uids
+}
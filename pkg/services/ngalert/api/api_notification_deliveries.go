@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// maxNotificationDeliveriesLimit is the maximum number of notification deliveries returned by a single request.
+const maxNotificationDeliveriesLimit = 1000
+
+var (
+	errNotificationDeliveryLogDisabled = errors.New("the notification delivery log is disabled")
+	fingerprintRegexp                  = regexp.MustCompile(`^[0-9a-f]{16}$`)
+)
+
+type NotificationDeliveryService interface {
+	ListNotificationDeliveries(ctx context.Context, query models.ListNotificationDeliveriesQuery) ([]models.NotificationDelivery, error)
+	ResendNotification(ctx context.Context, orgID int64, id int64) (models.NotificationDelivery, error)
+}
+
+func (srv *NotificationSrv) RouteGetNotificationDeliveries(c *contextmodel.ReqContext) response.Response {
+	if srv.deliveryLog == nil {
+		return ErrResp(http.StatusNotFound, errNotificationDeliveryLogDisabled, "")
+	}
+
+	q := models.ListNotificationDeliveriesQuery{
+		OrgID:          c.SignedInUser.GetOrgID(),
+		Receiver:       c.Query("receiver"),
+		IntegrationUID: c.Query("integration_uid"),
+		OnlyFailed:     c.QueryBool("failed"),
+		Limit:          c.QueryInt("limit"),
+	}
+	if fp := c.Query("fingerprint"); fp != "" {
+		if !fingerprintRegexp.MatchString(fp) {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid fingerprint %q", fp), "")
+		}
+		q.AlertFingerprint = fp
+	}
+	var err error
+	if q.From, err = parseOptionalTime(c.Query("from")); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse 'from'")
+	}
+	if q.To, err = parseOptionalTime(c.Query("to")); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse 'to'")
+	}
+	if q.Limit < 0 || q.Limit > maxNotificationDeliveriesLimit {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("limit must be between 0 and %d", maxNotificationDeliveriesLimit), "")
+	}
+
+	deliveries, err := srv.deliveryLog.ListNotificationDeliveries(c.Req.Context(), q)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get notification deliveries", err)
+	}
+
+	result := make(apimodels.NotificationDeliveries, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, NotificationDeliveryToApiModel(d))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *NotificationSrv) RoutePostNotificationDeliveryResend(c *contextmodel.ReqContext, id string) response.Response {
+	if srv.deliveryLog == nil {
+		return ErrResp(http.StatusNotFound, errNotificationDeliveryLogDisabled, "")
+	}
+
+	deliveryID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse notification delivery id")
+	}
+
+	delivery, err := srv.deliveryLog.ResendNotification(c.Req.Context(), c.SignedInUser.GetOrgID(), deliveryID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to resend notification", err)
+	}
+	return response.JSON(http.StatusOK, NotificationDeliveryToApiModel(delivery))
+}
+
+func parseOptionalTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeNotificationDeliveryService struct {
+	deliveries []models.NotificationDelivery
+	query      models.ListNotificationDeliveriesQuery
+	resent     int64
+	resendErr  error
+}
+
+func (f *fakeNotificationDeliveryService) ListNotificationDeliveries(_ context.Context, q models.ListNotificationDeliveriesQuery) ([]models.NotificationDelivery, error) {
+	f.query = q
+	return f.deliveries, nil
+}
+
+func (f *fakeNotificationDeliveryService) ResendNotification(_ context.Context, orgID int64, id int64) (models.NotificationDelivery, error) {
+	if f.resendErr != nil {
+		return models.NotificationDelivery{}, f.resendErr
+	}
+	f.resent = id
+	return models.NotificationDelivery{ID: 2, OrgID: orgID, ResentFrom: id}, nil
+}
+
+func TestRouteGetNotificationDeliveries(t *testing.T) {
+	delivery := models.NotificationDelivery{
+		ID:                1,
+		OrgID:             1,
+		Created:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Receiver:          "team-a",
+		IntegrationUID:    "webhook-uid",
+		IntegrationType:   "webhook",
+		AlertFingerprints: []string{"0123456789abcdef"},
+		StatusCode:        http.StatusInternalServerError,
+		Error:             "webhook response status 500 Internal Server Error",
+		Duration:          1500 * time.Millisecond,
+	}
+
+	t.Run("returns deliveries that match the query", func(t *testing.T) {
+		svc := &fakeNotificationDeliveryService{deliveries: []models.NotificationDelivery{delivery}}
+		srv := newNotificationSrv(nil)
+		srv.deliveryLog = svc
+		rc := testReqCtx("GET")
+		rc.Context.Req.Form.Set("receiver", "team-a")
+		rc.Context.Req.Form.Set("integration_uid", "webhook-uid")
+		rc.Context.Req.Form.Set("fingerprint", "0123456789abcdef")
+		rc.Context.Req.Form.Set("from", "2024-01-01T00:00:00Z")
+		rc.Context.Req.Form.Set("failed", "true")
+		rc.Context.Req.Form.Set("limit", "10")
+
+		resp := NewNotificationsApi(srv).handleRouteGetNotificationDeliveries(&rc)
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, models.ListNotificationDeliveriesQuery{
+			OrgID:            1,
+			Receiver:         "team-a",
+			IntegrationUID:   "webhook-uid",
+			AlertFingerprint: "0123456789abcdef",
+			From:             time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			OnlyFailed:       true,
+			Limit:            10,
+		}, svc.query)
+		var result definitions.NotificationDeliveries
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, definitions.NotificationDeliveries{NotificationDeliveryToApiModel(delivery)}, result)
+		require.Equal(t, int64(1500), result[0].DurationMs)
+	})
+
+	t.Run("returns 400 for invalid parameters", func(t *testing.T) {
+		for param, value := range map[string]string{"fingerprint": "%", "from": "yesterday", "limit": "5000"} {
+			srv := newNotificationSrv(nil)
+			srv.deliveryLog = &fakeNotificationDeliveryService{}
+			rc := testReqCtx("GET")
+			rc.Context.Req.Form.Set(param, value)
+
+			resp := NewNotificationsApi(srv).handleRouteGetNotificationDeliveries(&rc)
+			require.Equalf(t, http.StatusBadRequest, resp.Status(), "parameter %s", param)
+		}
+	})
+
+	t.Run("returns 404 if the delivery log is disabled", func(t *testing.T) {
+		rc := testReqCtx("GET")
+		resp := NewNotificationsApi(newNotificationSrv(nil)).handleRouteGetNotificationDeliveries(&rc)
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
+
+func TestRoutePostNotificationDeliveryResend(t *testing.T) {
+	t.Run("resends the notification", func(t *testing.T) {
+		svc := &fakeNotificationDeliveryService{}
+		srv := newNotificationSrv(nil)
+		srv.deliveryLog = svc
+		rc := testReqCtx("POST")
+
+		resp := NewNotificationsApi(srv).handleRoutePostNotificationDeliveryResend(&rc, "1")
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, int64(1), svc.resent)
+		var result definitions.NotificationDelivery
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, int64(1), result.ResentFrom)
+	})
+
+	t.Run("returns errors of the service", func(t *testing.T) {
+		testCases := []struct {
+			err    error
+			status int
+		}{
+			{models.ErrNotificationDeliveryNotFound.Errorf(""), http.StatusNotFound},
+			{models.MakeErrNotificationDeliveryResendFailed(context.DeadlineExceeded), http.StatusBadRequest},
+		}
+		for _, tc := range testCases {
+			srv := newNotificationSrv(nil)
+			srv.deliveryLog = &fakeNotificationDeliveryService{resendErr: tc.err}
+			rc := testReqCtx("POST")
+
+			resp := NewNotificationsApi(srv).handleRoutePostNotificationDeliveryResend(&rc, "1")
+			require.Equal(t, tc.status, resp.Status())
+		}
+	})
+
+	t.Run("returns 400 if the ID is invalid", func(t *testing.T) {
+		srv := newNotificationSrv(nil)
+		srv.deliveryLog = &fakeNotificationDeliveryService{}
+		rc := testReqCtx("POST")
+
+		resp := NewNotificationsApi(srv).handleRoutePostNotificationDeliveryResend(&rc, "abc")
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var (
+	errSimulationNoAlerts       = errors.New("either alerts or ruleUid must be specified")
+	errSimulationAlertsAndRule  = errors.New("alerts and ruleUid cannot be specified together")
+	errSimulationNoRuleInstance = errors.New("the alert rule has no alert instances")
+)
+
+type RuleByUIDReader interface {
+	GetAlertRuleByUID(ctx context.Context, query *models.GetAlertRuleByUIDQuery) (*models.AlertRule, error)
+}
+
+type RoutingSimulator interface {
+	SimulateRouting(ctx context.Context, orgID int64, route *apimodels.Route, alerts []model.LabelSet, silences []*models.Silence, now time.Time) (apimodels.NotificationPolicySimulationResult, error)
+}
+
+// RoutePostNotificationPolicySimulation routes the alerts of the request through the notification policy tree.
+// Only the silences that the user can read are matched against the alerts.
+// The alert instances of a rule are read from the state manager, or from the instance store when the rule isn't
+// evaluated by this instance.
+func (srv *NotificationSrv) RoutePostNotificationPolicySimulation(c *contextmodel.ReqContext, body apimodels.NotificationPolicySimulationRequest) response.Response {
+	ctx := c.Req.Context()
+	alerts := body.Alerts
+	switch {
+	case len(alerts) == 0 && body.RuleUID == "":
+		return ErrResp(http.StatusBadRequest, errSimulationNoAlerts, "")
+	case len(alerts) > 0 && body.RuleUID != "":
+		return ErrResp(http.StatusBadRequest, errSimulationAlertsAndRule, "")
+	}
+
+	if body.RuleUID != "" {
+		rule, err := srv.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{UID: body.RuleUID, OrgID: c.SignedInUser.GetOrgID()})
+		if err != nil {
+			if errors.Is(err, models.ErrAlertRuleNotFound) {
+				return response.Empty(http.StatusNotFound)
+			}
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to get rule by UID", err)
+		}
+		if err := srv.ruleAuthz.AuthorizeAccessInFolder(ctx, c.SignedInUser, rule); err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "failed to authorize access to rule", err)
+		}
+		for _, s := range srv.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+			alerts = append(alerts, labelSetFromLabels(s.Labels))
+		}
+		if len(alerts) == 0 {
+			instances, err := srv.instanceStore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleUID: rule.UID, RuleOrgID: rule.OrgID})
+			if err != nil {
+				return response.ErrOrFallback(http.StatusInternalServerError, "failed to list alert instances", err)
+			}
+			for _, instance := range instances {
+				alerts = append(alerts, labelSetFromLabels(instance.Labels))
+			}
+		}
+		if len(alerts) == 0 {
+			return ErrResp(http.StatusBadRequest, errSimulationNoRuleInstance, "")
+		}
+	}
+	for _, lset := range alerts {
+		if err := lset.Validate(); err != nil {
+			return ErrResp(http.StatusBadRequest, err, "invalid alert labels")
+		}
+	}
+
+	now := time.Now()
+	if body.Time != nil {
+		now = *body.Time
+	}
+
+	silences, err := srv.silenceSvc.ListSilences(ctx, c.SignedInUser, nil)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to list silences", err)
+	}
+
+	result, err := srv.routingSimulator.SimulateRouting(ctx, c.SignedInUser.GetOrgID(), body.Route, alerts, silences, now)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to simulate notification routing", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func labelSetFromLabels[T ~map[string]string](labels T) model.LabelSet {
+	lset := make(model.LabelSet, len(labels))
+	for k, v := range labels {
+		lset[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return lset
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeRoutingSimulator struct {
+	route    *definitions.Route
+	alerts   []model.LabelSet
+	silences []*models.Silence
+	now      time.Time
+}
+
+func (f *fakeRoutingSimulator) SimulateRouting(_ context.Context, _ int64, route *definitions.Route, alerts []model.LabelSet, silences []*models.Silence, now time.Time) (definitions.NotificationPolicySimulationResult, error) {
+	f.route, f.alerts, f.silences, f.now = route, alerts, silences, now
+	return definitions.NotificationPolicySimulationResult{Time: now}, nil
+}
+
+type fakeSimulationSilenceService struct {
+	SilenceService
+	silences []*models.Silence
+}
+
+func (f *fakeSimulationSilenceService) ListSilences(_ context.Context, _ identity.Requester, _ []string) ([]*models.Silence, error) {
+	return f.silences, nil
+}
+
+type fakeSimulationInstanceReader struct {
+	instances []*models.AlertInstance
+}
+
+func (f *fakeSimulationInstanceReader) ListAlertInstances(_ context.Context, q *models.ListAlertInstancesQuery) ([]*models.AlertInstance, error) {
+	var result []*models.AlertInstance
+	for _, instance := range f.instances {
+		if instance.RuleOrgID == q.RuleOrgID && instance.RuleUID == q.RuleUID {
+			result = append(result, instance)
+		}
+	}
+	return result, nil
+}
+
+func TestRoutePostNotificationPolicySimulation(t *testing.T) {
+	silences := []*models.Silence{{ID: util.Pointer("silence")}}
+	newSrv := func(t *testing.T) (*NotificationSrv, *fakeRoutingSimulator, *fakes.RuleStore, *fakeAlertInstanceManager) {
+		simulator := &fakeRoutingSimulator{}
+		ruleStore := fakes.NewRuleStore(t)
+		states := NewFakeAlertInstanceManager(t)
+		srv := newNotificationSrv(nil)
+		srv.routingSimulator = simulator
+		srv.ruleStore = ruleStore
+		srv.ruleAuthz = &fakeRuleAccessControlService{}
+		srv.stateManager = states
+		srv.instanceStore = &fakeSimulationInstanceReader{}
+		srv.silenceSvc = &fakeSimulationSilenceService{silences: silences}
+		return srv, simulator, ruleStore, states
+	}
+
+	t.Run("should simulate the routing of the alerts of the request", func(t *testing.T) {
+		srv, simulator, _, _ := newSrv(t)
+		rc := testReqCtx("POST")
+		at := time.Date(2024, 6, 2, 3, 0, 0, 0, time.UTC)
+		route := &definitions.Route{Receiver: "default"}
+		alerts := []model.LabelSet{{"alertname": "HighLatency"}}
+
+		resp := NewNotificationsApi(srv).handleRoutePostNotificationPolicySimulation(&rc, definitions.NotificationPolicySimulationRequest{
+			Alerts: alerts,
+			Route:  route,
+			Time:   &at,
+		})
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		assert.Equal(t, route, simulator.route)
+		assert.Equal(t, alerts, simulator.alerts)
+		assert.Equal(t, silences, simulator.silences)
+		assert.Equal(t, at, simulator.now)
+	})
+
+	t.Run("should simulate the routing of the current alert instances of the rule", func(t *testing.T) {
+		srv, simulator, ruleStore, states := newSrv(t)
+		rule := models.RuleGen.With(models.RuleGen.WithOrgID(1)).GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+		states.states[1] = map[string][]*state.State{
+			rule.UID: {
+				{Labels: data.Labels{"alertname": rule.Title, "instance": "a"}},
+				{Labels: data.Labels{"alertname": rule.Title, "instance": "b"}},
+			},
+		}
+		rc := testReqCtx("POST")
+
+		resp := NewNotificationsApi(srv).handleRoutePostNotificationPolicySimulation(&rc, definitions.NotificationPolicySimulationRequest{RuleUID: rule.UID})
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		assert.ElementsMatch(t, []model.LabelSet{
+			{"alertname": model.LabelValue(rule.Title), "instance": "a"},
+			{"alertname": model.LabelValue(rule.Title), "instance": "b"},
+		}, simulator.alerts)
+		assert.Nil(t, simulator.route)
+		assert.False(t, simulator.now.IsZero())
+	})
+
+	t.Run("should read the alert instances of the rule from the store if the state manager has none", func(t *testing.T) {
+		srv, simulator, ruleStore, _ := newSrv(t)
+		rule := models.RuleGen.With(models.RuleGen.WithOrgID(1)).GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+		srv.instanceStore = &fakeSimulationInstanceReader{instances: []*models.AlertInstance{
+			{AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: 1, RuleUID: rule.UID}, Labels: models.InstanceLabels{"alertname": rule.Title, "instance": "a"}},
+			{AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: 1, RuleUID: "other"}, Labels: models.InstanceLabels{"alertname": "other"}},
+		}}
+		rc := testReqCtx("POST")
+
+		resp := NewNotificationsApi(srv).handleRoutePostNotificationPolicySimulation(&rc, definitions.NotificationPolicySimulationRequest{RuleUID: rule.UID})
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		assert.Equal(t, []model.LabelSet{{"alertname": model.LabelValue(rule.Title), "instance": "a"}}, simulator.alerts)
+	})
+
+	t.Run("should return 404 if the rule does not exist", func(t *testing.T) {
+		srv, _, _, _ := newSrv(t)
+		rc := testReqCtx("POST")
+
+		resp := NewNotificationsApi(srv).handleRoutePostNotificationPolicySimulation(&rc, definitions.NotificationPolicySimulationRequest{RuleUID: "unknown"})
+
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+
+	t.Run("should return 400 if the rule has no alert instances", func(t *testing.T) {
+		srv, _, ruleStore, _ := newSrv(t)
+		rule := models.RuleGen.With(models.RuleGen.WithOrgID(1)).GenerateRef()
+		ruleStore.PutRule(context.Background(), rule)
+		rc := testReqCtx("POST")
+
+		resp := NewNotificationsApi(srv).handleRoutePostNotificationPolicySimulation(&rc, definitions.NotificationPolicySimulationRequest{RuleUID: rule.UID})
+
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("should return 400 if the request is invalid", func(t *testing.T) {
+		srv, _, _, _ := newSrv(t)
+		for _, body := range []definitions.NotificationPolicySimulationRequest{
+			{},
+			{Alerts: []model.LabelSet{{"alertname": "a"}}, RuleUID: "rule"},
+			{Alerts: []model.LabelSet{{"": "a"}}},
+		} {
+			rc := testReqCtx("POST")
+			resp := NewNotificationsApi(srv).handleRoutePostNotificationPolicySimulation(&rc, body)
+			assert.Equalf(t, http.StatusBadRequest, resp.Status(), "request: %+v", body)
+		}
+	})
+}
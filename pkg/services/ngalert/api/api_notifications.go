@@ -9,12 +9,20 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 )
 
 type NotificationSrv struct {
 	logger            log.Logger
 	receiverService   ReceiverService
 	muteTimingService MuteTimingService // defined in api_provisioning.go
+	deliveryLog       NotificationDeliveryService
+	routingSimulator  RoutingSimulator
+	ruleStore         RuleByUIDReader
+	ruleAuthz         RuleAccessControlService
+	stateManager      state.AlertInstanceManager
+	instanceStore     state.InstanceReader
+	silenceSvc        SilenceService
 }
 
 type ReceiverService interface {
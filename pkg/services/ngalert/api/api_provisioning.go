@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
@@ -31,8 +32,11 @@ type ProvisioningSrv struct {
 	contactPointService ContactPointService
 	templates           TemplateService
 	muteTimings         MuteTimingService
+	silenceTemplates    SilenceTemplateService
+	silences            SilenceService
 	alertRules          AlertRuleService
 	folderSvc           folder.Service
+	xact                provisioning.TransactionManager
 
 	// XXX: Used to flag recording rules, remove when FT is removed
 	featureManager featuremgmt.FeatureToggles
@@ -66,6 +70,14 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64, provenance definitions.Provenance, version string) error
 }
 
+type SilenceTemplateService interface {
+	GetSilenceTemplates(ctx context.Context, orgID int64) ([]alerting_models.SilenceTemplate, map[string]alerting_models.Provenance, error)
+	GetSilenceTemplate(ctx context.Context, orgID int64, uid string) (alerting_models.SilenceTemplate, alerting_models.Provenance, error)
+	CreateSilenceTemplate(ctx context.Context, orgID int64, template alerting_models.SilenceTemplate, provenance alerting_models.Provenance) (alerting_models.SilenceTemplate, error)
+	UpdateSilenceTemplate(ctx context.Context, orgID int64, template alerting_models.SilenceTemplate, provenance alerting_models.Provenance) (alerting_models.SilenceTemplate, error)
+	DeleteSilenceTemplate(ctx context.Context, orgID int64, uid string, provenance alerting_models.Provenance) error
+}
+
 type AlertRuleService interface {
 	GetAlertRules(ctx context.Context, user identity.Requester) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
 	GetAlertRule(ctx context.Context, user identity.Requester, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
@@ -74,6 +86,7 @@ type AlertRuleService interface {
 	DeleteAlertRule(ctx context.Context, user identity.Requester, ruleUID string, provenance alerting_models.Provenance) error
 	GetRuleGroup(ctx context.Context, user identity.Requester, folder, group string) (alerting_models.AlertRuleGroup, error)
 	ReplaceRuleGroup(ctx context.Context, user identity.Requester, group alerting_models.AlertRuleGroup, provenance alerting_models.Provenance) error
+	CalculateRuleGroupChanges(ctx context.Context, user identity.Requester, group alerting_models.AlertRuleGroup) (*store.GroupDelta, error)
 	DeleteRuleGroup(ctx context.Context, user identity.Requester, folder, group string, provenance alerting_models.Provenance) error
 	GetAlertRuleWithFolderFullpath(ctx context.Context, u identity.Requester, ruleUID string) (provisioning.AlertRuleWithFolderFullpath, error)
 	GetAlertRuleGroupWithFolderFullpath(ctx context.Context, u identity.Requester, folder, group string) (alerting_models.AlertRuleGroupWithFolderFullpath, error)
@@ -315,6 +328,80 @@ func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, na
 	return response.JSON(http.StatusNoContent, nil)
 }
 
+func (srv *ProvisioningSrv) RouteGetSilenceTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, provenances, err := srv.silenceTemplates.GetSilenceTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silence templates", err)
+	}
+	result := make(definitions.SilenceTemplates, 0, len(templates))
+	for _, t := range templates {
+		provenance := alerting_models.ProvenanceNone
+		if p, ok := provenances[t.ResourceID()]; ok {
+			provenance = p
+		}
+		result = append(result, ApiSilenceTemplateFromModel(t, provenance))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetSilenceTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	template, provenance, err := srv.silenceTemplates.GetSilenceTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silence template", err)
+	}
+	return response.JSON(http.StatusOK, ApiSilenceTemplateFromModel(template, provenance))
+}
+
+func (srv *ProvisioningSrv) RoutePostSilenceTemplate(c *contextmodel.ReqContext, st definitions.SilenceTemplate) response.Response {
+	provenance := alerting_models.Provenance(determineProvenance(c))
+	created, err := srv.silenceTemplates.CreateSilenceTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), SilenceTemplateFromApi(st), provenance)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create silence template", err)
+	}
+	return response.JSON(http.StatusCreated, ApiSilenceTemplateFromModel(created, provenance))
+}
+
+func (srv *ProvisioningSrv) RoutePutSilenceTemplate(c *contextmodel.ReqContext, st definitions.SilenceTemplate, UID string) response.Response {
+	st.UID = UID
+	provenance := alerting_models.Provenance(determineProvenance(c))
+	updated, err := srv.silenceTemplates.UpdateSilenceTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), SilenceTemplateFromApi(st), provenance)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update silence template", err)
+	}
+	return response.JSON(http.StatusAccepted, ApiSilenceTemplateFromModel(updated, provenance))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteSilenceTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.silenceTemplates.DeleteSilenceTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), UID, alerting_models.Provenance(determineProvenance(c)))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete silence template", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RoutePostSilenceTemplateApply(c *contextmodel.ReqContext, body definitions.SilenceTemplateApply, UID string) response.Response {
+	template, _, err := srv.silenceTemplates.GetSilenceTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get silence template", err)
+	}
+	duration := template.Duration
+	if body.Duration > 0 {
+		duration = time.Duration(body.Duration)
+	}
+	if duration <= 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("silence template has no duration"), "duration is required")
+	}
+	start := timeNow()
+	silence := template.Silence(c.SignedInUser.GetLogin(), start, start.Add(duration))
+	silenceID, err := srv.silences.CreateSilence(c.Req.Context(), c.SignedInUser, silence)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create silence", err)
+	}
+	return response.JSON(http.StatusAccepted, definitions.PostSilencesOKBody{
+		SilenceID: silenceID,
+	})
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
 	rules, provenances, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.SignedInUser)
 	if err != nil {
@@ -574,7 +661,7 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 		return exportHcl(params.Download, body)
 	}
 
-	body = escapeAlertingFileExport(body)
+	body = escapeAlertingFileExport(body, addEscapeCharactersToString)
 	if params.Download {
 		r := response.JSONDownload
 		if params.Format == "yaml" {
@@ -598,63 +685,63 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 // Mute timings time intervals: muteTimes[].time_intervals[]
 // Notification template name: templates[].name
 // Notification template content: templates[].template
-func escapeAlertingFileExport(body definitions.AlertingFileExport) definitions.AlertingFileExport {
+func escapeAlertingFileExport(body definitions.AlertingFileExport, escape func(string) string) definitions.AlertingFileExport {
 	for i, group := range body.Groups {
-		body.Groups[i] = escapeRuleGroup(group)
+		body.Groups[i] = escapeRuleGroup(group, escape)
 	}
 	for i, cp := range body.ContactPoints {
-		body.ContactPoints[i] = escapeContactPoint(cp)
+		body.ContactPoints[i] = escapeContactPoint(cp, escape)
 	}
 	for i, np := range body.Policies {
-		body.Policies[i] = escapeNotificationPolicy(np)
+		body.Policies[i] = escapeNotificationPolicy(np, escape)
 	}
 	return body
 }
 
-func escapeRouteExport(r *definitions.RouteExport) {
-	r.Receiver = addEscapeCharactersToString(r.Receiver)
+func escapeRouteExport(r *definitions.RouteExport, escape func(string) string) {
+	r.Receiver = escape(r.Receiver)
 	if r.GroupByStr != nil {
 		groupByStr := make([]string, len(*r.GroupByStr))
 		for i, groupBy := range *r.GroupByStr {
-			groupByStr[i] = addEscapeCharactersToString(groupBy)
+			groupByStr[i] = escape(groupBy)
 		}
 		r.GroupByStr = &groupByStr
 	}
 	for k, v := range r.Match {
-		r.Match[k] = addEscapeCharactersToString(v)
+		r.Match[k] = escape(v)
 	}
 	for k, v := range r.MatchRE {
 		// convert regex to string, escape then covert back to regex
-		stringRepr := addEscapeCharactersToString(v.String())
+		stringRepr := escape(v.String())
 		mutated := regexp.MustCompile(stringRepr)
 		r.MatchRE[k] = alertmanager_config.Regexp{Regexp: mutated}
 	}
 	if r.MuteTimeIntervals != nil {
 		muteTimeIntervals := make([]string, len(*r.MuteTimeIntervals))
 		for i, muteTimeInterval := range *r.MuteTimeIntervals {
-			muteTimeIntervals[i] = addEscapeCharactersToString(muteTimeInterval)
+			muteTimeIntervals[i] = escape(muteTimeInterval)
 		}
 		r.MuteTimeIntervals = &muteTimeIntervals
 	}
 	for i := range r.Routes {
-		escapeRouteExport(r.Routes[i])
+		escapeRouteExport(r.Routes[i], escape)
 	}
 }
 
-func escapeNotificationPolicy(np definitions.NotificationPolicyExport) definitions.NotificationPolicyExport {
-	escapeRouteExport(np.RouteExport)
+func escapeNotificationPolicy(np definitions.NotificationPolicyExport, escape func(string) string) definitions.NotificationPolicyExport {
+	escapeRouteExport(np.RouteExport, escape)
 	return np
 }
 
-func escapeContactPoint(cp definitions.ContactPointExport) definitions.ContactPointExport {
-	cp.Name = addEscapeCharactersToString(cp.Name)
+func escapeContactPoint(cp definitions.ContactPointExport, escape func(string) string) definitions.ContactPointExport {
+	cp.Name = escape(cp.Name)
 	for i, receiver := range cp.Receivers {
 		settingsJson, err := receiver.Settings.MarshalJSON()
 		if err != nil {
 			// This should never happen, as the settings are already marshaled to JSON in the API
 			panic(fmt.Errorf("failed to marshal settings to JSON: %w", err))
 		}
-		settingsEscaped := []byte(addEscapeCharactersToString(string(settingsJson)))
+		settingsEscaped := []byte(escape(string(settingsJson)))
 		if err := cp.Receivers[i].Settings.UnmarshalJSON(settingsEscaped); err != nil {
 			// This should never happen, as the settings are already marshaled to JSON in the API
 			panic(fmt.Errorf("failed to unmarshal settings from JSON: %w", err))
@@ -667,37 +754,37 @@ func escapeContactPoint(cp definitions.ContactPointExport) definitions.ContactPo
 // Alert rule annotations: groups[].rules[].annotations
 // Alert rule time range: groups[].rules[].relativeTimeRange
 // Alert rule query model: groups[].rules[].data.model
-func escapeRuleGroup(group definitions.AlertRuleGroupExport) definitions.AlertRuleGroupExport {
-	group.Name = addEscapeCharactersToString(group.Name)
-	group.Folder = addEscapeCharactersToString(group.Folder)
+func escapeRuleGroup(group definitions.AlertRuleGroupExport, escape func(string) string) definitions.AlertRuleGroupExport {
+	group.Name = escape(group.Name)
+	group.Folder = escape(group.Folder)
 	for i, rule := range group.Rules {
-		group.Rules[i].Title = addEscapeCharactersToString(rule.Title)
+		group.Rules[i].Title = escape(rule.Title)
 		if rule.Labels != nil {
-			group.Rules[i].Labels = escapeMapValues(*rule.Labels)
+			group.Rules[i].Labels = escapeMapValues(*rule.Labels, escape)
 		}
 		if rule.NotificationSettings != nil {
-			notificationSettings := escapeRuleNotificationSettings(*rule.NotificationSettings)
+			notificationSettings := escapeRuleNotificationSettings(*rule.NotificationSettings, escape)
 			group.Rules[i].NotificationSettings = &notificationSettings
 		}
 	}
 	return group
 }
 
-func escapeRuleNotificationSettings(ns definitions.AlertRuleNotificationSettingsExport) definitions.AlertRuleNotificationSettingsExport {
-	ns.Receiver = addEscapeCharactersToString(ns.Receiver)
+func escapeRuleNotificationSettings(ns definitions.AlertRuleNotificationSettingsExport, escape func(string) string) definitions.AlertRuleNotificationSettingsExport {
+	ns.Receiver = escape(ns.Receiver)
 	for j := range ns.GroupBy {
-		ns.GroupBy[j] = addEscapeCharactersToString(ns.GroupBy[j])
+		ns.GroupBy[j] = escape(ns.GroupBy[j])
 	}
 	for k := range ns.MuteTimeIntervals {
-		ns.MuteTimeIntervals[k] = addEscapeCharactersToString(ns.MuteTimeIntervals[k])
+		ns.MuteTimeIntervals[k] = escape(ns.MuteTimeIntervals[k])
 	}
 	return ns
 }
 
-func escapeMapValues(m map[string]string) *map[string]string {
+func escapeMapValues(m map[string]string, escape func(string) string) *map[string]string {
 	escapedMap := make(map[string]string, len(m))
 	for k, v := range m {
-		escapedMap[k] = addEscapeCharactersToString(v)
+		escapedMap[k] = escape(v)
 	}
 	return &escapedMap
 }
@@ -706,6 +793,10 @@ func addEscapeCharactersToString(s string) string {
 	return strings.ReplaceAll(s, "$", "$$")
 }
 
+func removeEscapeCharactersFromString(s string) string {
+	return strings.ReplaceAll(s, "$$", "$")
+}
+
 func exportHcl(download bool, body definitions.AlertingFileExport) response.Response {
 	resources := make([]hcl.Resource, 0, len(body.Groups)+len(body.ContactPoints)+len(body.Policies)+len(body.MuteTimings))
 	convertToResources := func() error {
@@ -0,0 +1,757 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/folderimpl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	importResourceRuleGroup          = "rule_group"
+	importResourceContactPoint       = "contact_point"
+	importResourceNotificationPolicy = "notification_policy"
+	importResourceMuteTiming         = "mute_timing"
+
+	importActionCreate    = "create"
+	importActionUpdate    = "update"
+	importActionUnchanged = "unchanged"
+
+	importOpAdd    = "add"
+	importOpRemove = "remove"
+	importOpChange = "change"
+)
+
+var errInvalidImport = errutil.ValidationFailed("alerting.provisioning.invalidImport").MustTemplate(
+	"invalid import: {{.Public.Reason}}",
+	errutil.WithPublic("Invalid import: {{.Public.Reason}}"),
+)
+
+func makeErrInvalidImport(format string, args ...any) error {
+	return errInvalidImport.Build(errutil.TemplateData{
+		Public: map[string]any{"Reason": fmt.Sprintf(format, args...)},
+	})
+}
+
+// importStep is the change of a single resource of an import.
+type importStep struct {
+	change definitions.ProvisioningImportChange
+	apply  func(ctx context.Context) error
+}
+
+// RoutePostProvisioningImport creates or updates the resources of a file in one of the formats of the export
+// endpoints. All resources are validated and compared with the current ones before any of them is saved, and they
+// are saved in one transaction: if saving one fails, none of them is saved.
+func (srv *ProvisioningSrv) RoutePostProvisioningImport(c *contextmodel.ReqContext, body []byte) response.Response {
+	format, err := importFormat(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	file, err := parseAlertingFileImport(format, body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse the %s file", format)
+	}
+
+	steps, err := srv.planImport(c, file, alerting_models.Provenance(determineProvenance(c)))
+	if err != nil {
+		return importErrorResponse(err)
+	}
+
+	result := definitions.ProvisioningImportResult{
+		DryRun:  c.QueryBoolWithDefault("dry_run", false),
+		Changes: make([]definitions.ProvisioningImportChange, 0, len(steps)),
+	}
+	for _, step := range steps {
+		result.Changes = append(result.Changes, step.change)
+	}
+	if result.DryRun {
+		return response.JSON(http.StatusOK, result)
+	}
+
+	err = srv.xact.InTransaction(c.Req.Context(), func(ctx context.Context) error {
+		for _, step := range steps {
+			if step.change.Action == importActionUnchanged {
+				continue
+			}
+			if err := step.apply(ctx); err != nil {
+				srv.log.FromContext(ctx).Error("Failed to import resource", "resource", step.change.Resource, "name", step.change.Name, "error", err)
+				return fmt.Errorf("failed to import %s '%s': %w", step.change.Resource, step.change.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return importErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func importFormat(c *contextmodel.ReqContext) (string, error) {
+	format := c.Query("format")
+	if format == "" {
+		contentType := c.Req.Header.Get("Content-Type")
+		switch {
+		case strings.Contains(contentType, "hcl"), strings.Contains(contentType, "terraform"):
+			format = "hcl"
+		case strings.Contains(contentType, "json"):
+			format = "json"
+		default:
+			format = "yaml"
+		}
+	}
+	if format != "yaml" && format != "json" && format != "hcl" {
+		return "", fmt.Errorf("unsupported format %q, must be one of yaml, json or hcl", format)
+	}
+	return format, nil
+}
+
+// parseAlertingFileImport parses a file in the format of the export endpoints. The escaping of the YAML and JSON
+// exports is reverted.
+func parseAlertingFileImport(format string, data []byte) (definitions.AlertingFileExport, error) {
+	var file definitions.AlertingFileExport
+	switch format {
+	case "hcl":
+		return parseHclImport(data)
+	case "json":
+		if err := json.Unmarshal(data, &file); err != nil {
+			return definitions.AlertingFileExport{}, err
+		}
+	default:
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return definitions.AlertingFileExport{}, err
+		}
+	}
+	return escapeAlertingFileExport(file, removeEscapeCharactersFromString), nil
+}
+
+// parseHclImport converts the resources of the HCL export to definitions.AlertingFileExport.
+func parseHclImport(data []byte) (definitions.AlertingFileExport, error) {
+	resources, err := hcl.Decode(data, "import.tf", func(resourceType string) (interface{}, error) {
+		switch resourceType {
+		case "grafana_rule_group":
+			return &definitions.AlertRuleGroupExport{}, nil
+		case "grafana_contact_point":
+			return &definitions.ContactPoint{}, nil
+		case "grafana_notification_policy":
+			return &definitions.RouteExport{}, nil
+		case "grafana_mute_timing":
+			return &definitions.MuteTimeIntervalExportHcl{}, nil
+		}
+		return nil, fmt.Errorf("unsupported resource type %q", resourceType)
+	})
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+
+	file := definitions.AlertingFileExport{APIVersion: 1}
+	for _, resource := range resources {
+		switch body := resource.Body.(type) {
+		case *definitions.AlertRuleGroupExport:
+			body.Interval = model.Duration(time.Duration(body.IntervalSeconds) * time.Second)
+			for i := range body.Rules {
+				rule := &body.Rules[i]
+				if rule.ForString != nil {
+					d, err := model.ParseDuration(*rule.ForString)
+					if err != nil {
+						return definitions.AlertingFileExport{}, fmt.Errorf("resource %q: failed to parse 'for' of rule '%s': %w", resource.Name, rule.Title, err)
+					}
+					rule.For = d
+				}
+				for j := range rule.Data {
+					query := &rule.Data[j]
+					if err := json.Unmarshal([]byte(query.ModelString), &query.Model); err != nil {
+						return definitions.AlertingFileExport{}, fmt.Errorf("resource %q: failed to parse the model of query %s of rule '%s': %w", resource.Name, query.RefID, rule.Title, err)
+					}
+				}
+			}
+			file.Groups = append(file.Groups, *body)
+		case *definitions.ContactPoint:
+			cp, err := ContactPointExportFromContactPoint(*body)
+			if err != nil {
+				return definitions.AlertingFileExport{}, fmt.Errorf("resource %q: %w", resource.Name, err)
+			}
+			file.ContactPoints = append(file.ContactPoints, cp)
+		case *definitions.RouteExport:
+			if err := objectMatchersFromHcl(body); err != nil {
+				return definitions.AlertingFileExport{}, fmt.Errorf("resource %q: %w", resource.Name, err)
+			}
+			file.Policies = append(file.Policies, definitions.NotificationPolicyExport{RouteExport: body})
+		case *definitions.MuteTimeIntervalExportHcl:
+			mt, err := MuteTimeIntervalExportFromMuteTimeIntervalHclExport(*body)
+			if err != nil {
+				return definitions.AlertingFileExport{}, fmt.Errorf("resource %q: %w", resource.Name, err)
+			}
+			file.MuteTimings = append(file.MuteTimings, mt)
+		}
+	}
+	return file, nil
+}
+
+func objectMatchersFromHcl(r *definitions.RouteExport) error {
+	matchers, err := ObjectMatchersFromMatcherExports(r.ObjectMatchersSlice)
+	if err != nil {
+		return err
+	}
+	r.ObjectMatchers = matchers
+	for _, child := range r.Routes {
+		if err := objectMatchersFromHcl(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planImport validates the resources of the file and compares them with the current resources of the organization.
+// The steps are returned in the order in which they must be applied, so that resources are created before they are referenced.
+func (srv *ProvisioningSrv) planImport(c *contextmodel.ReqContext, file definitions.AlertingFileExport, provenance alerting_models.Provenance) ([]importStep, error) {
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+
+	muteTimingSteps, muteTimingNames, err := srv.planMuteTimingsImport(ctx, orgID, file.MuteTimings, provenance)
+	if err != nil {
+		return nil, err
+	}
+	contactPointSteps, receiverNames, err := srv.planContactPointsImport(c, file.ContactPoints, provenance)
+	if err != nil {
+		return nil, err
+	}
+	steps := append(muteTimingSteps, contactPointSteps...)
+
+	if len(file.Policies) > 1 {
+		return nil, makeErrInvalidImport("the file contains %d notification policy trees, at most one is allowed", len(file.Policies))
+	}
+	if len(file.Policies) == 1 {
+		step, err := srv.planPolicyTreeImport(ctx, orgID, file.Policies[0], receiverNames, muteTimingNames, provenance)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	ruleGroupSteps, err := srv.planRuleGroupsImport(c, file.Groups, receiverNames, muteTimingNames, provenance)
+	if err != nil {
+		return nil, err
+	}
+	return append(steps, ruleGroupSteps...), nil
+}
+
+func (srv *ProvisioningSrv) planMuteTimingsImport(ctx context.Context, orgID int64, muteTimings []definitions.MuteTimeIntervalExport, provenance alerting_models.Provenance) ([]importStep, map[string]struct{}, error) {
+	current, err := srv.muteTimings.GetMuteTimings(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[string]struct{}, len(current)+len(muteTimings))
+	currentByName := make(map[string]definitions.MuteTimeInterval, len(current))
+	for _, mt := range current {
+		names[mt.Name] = struct{}{}
+		currentByName[mt.Name] = mt
+	}
+
+	steps := make([]importStep, 0, len(muteTimings))
+	seen := make(map[string]struct{}, len(muteTimings))
+	for _, imported := range muteTimings {
+		if _, ok := seen[imported.Name]; ok {
+			return nil, nil, makeErrInvalidImport("duplicate mute timing '%s'", imported.Name)
+		}
+		seen[imported.Name] = struct{}{}
+		names[imported.Name] = struct{}{}
+
+		mt := definitions.MuteTimeInterval{
+			MuteTimeInterval: imported.MuteTimeInterval,
+			Provenance:       definitions.Provenance(provenance),
+		}
+		if err := mt.Validate(); err != nil {
+			return nil, nil, provisioning.MakeErrTimeIntervalInvalid(err)
+		}
+
+		step := importStep{change: definitions.ProvisioningImportChange{Resource: importResourceMuteTiming, Name: mt.Name}}
+		existing, ok := currentByName[mt.Name]
+		if !ok {
+			step.change.Action = importActionCreate
+			step.apply = func(ctx context.Context) error {
+				_, err := srv.muteTimings.CreateMuteTiming(ctx, mt, orgID)
+				return err
+			}
+			steps = append(steps, step)
+			continue
+		}
+		if step.change.Diff, err = diffImportResource("", existing.MuteTimeInterval, mt.MuteTimeInterval); err != nil {
+			return nil, nil, err
+		}
+		step.change.Action = importAction(step.change.Diff)
+		step.apply = func(ctx context.Context) error {
+			_, err := srv.muteTimings.UpdateMuteTiming(ctx, mt, orgID)
+			return err
+		}
+		steps = append(steps, step)
+	}
+	return steps, names, nil
+}
+
+// importedIntegration is the representation of an integration of a contact point that is compared in an import.
+type importedIntegration struct {
+	Type                  string          `json:"type"`
+	DisableResolveMessage bool            `json:"disableResolveMessage"`
+	Settings              json.RawMessage `json:"settings"`
+}
+
+// planContactPointsImport matches the imported integrations of every contact point with the current ones by UID,
+// and then by type in the order of the integrations. Current integrations of the contact point that are not matched
+// are deleted.
+func (srv *ProvisioningSrv) planContactPointsImport(c *contextmodel.ReqContext, contactPoints []definitions.ContactPointExport, provenance alerting_models.Provenance) ([]importStep, map[string]struct{}, error) {
+	orgID := c.SignedInUser.GetOrgID()
+	current, err := srv.contactPointService.GetContactPoints(c.Req.Context(), provisioning.ContactPointQuery{OrgID: orgID}, c.SignedInUser)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[string]struct{}, len(current)+len(contactPoints))
+	currentByUID := make(map[string]definitions.EmbeddedContactPoint, len(current))
+	for _, ecp := range current {
+		names[ecp.Name] = struct{}{}
+		currentByUID[ecp.UID] = ecp
+	}
+
+	steps := make([]importStep, 0, len(contactPoints))
+	seen := make(map[string]struct{}, len(contactPoints))
+	for _, cp := range contactPoints {
+		if cp.Name == "" {
+			return nil, nil, makeErrInvalidImport("contact point without name")
+		}
+		if _, ok := seen[cp.Name]; ok {
+			return nil, nil, makeErrInvalidImport("duplicate contact point '%s'", cp.Name)
+		}
+		seen[cp.Name] = struct{}{}
+		names[cp.Name] = struct{}{}
+		if len(cp.Receivers) == 0 {
+			return nil, nil, makeErrInvalidImport("contact point '%s' has no integrations", cp.Name)
+		}
+
+		imported := make([]definitions.EmbeddedContactPoint, 0, len(cp.Receivers))
+		for _, recv := range cp.Receivers {
+			settings, err := simplejson.NewJson(recv.Settings)
+			if err != nil {
+				return nil, nil, makeErrInvalidImport("failed to parse the settings of integration %s of contact point '%s': %s", recv.Type, cp.Name, err)
+			}
+			imported = append(imported, definitions.EmbeddedContactPoint{
+				UID:                   recv.UID,
+				Name:                  cp.Name,
+				Type:                  recv.Type,
+				Settings:              settings,
+				DisableResolveMessage: recv.DisableResolveMessage,
+			})
+		}
+
+		// matched[i] is the current integration that is updated by the imported integration i.
+		matched := make([]*definitions.EmbeddedContactPoint, len(imported))
+		used := make(map[string]struct{})
+		for i, ecp := range imported {
+			if existing, ok := currentByUID[ecp.UID]; ok && ecp.UID != "" {
+				matched[i] = &existing
+				used[existing.UID] = struct{}{}
+			}
+		}
+		for i, ecp := range imported {
+			if matched[i] != nil {
+				continue
+			}
+			for _, existing := range current {
+				if _, ok := used[existing.UID]; ok || existing.Name != cp.Name || existing.Type != ecp.Type {
+					continue
+				}
+				matched[i] = &existing
+				used[existing.UID] = struct{}{}
+				break
+			}
+		}
+		var deleted []definitions.EmbeddedContactPoint
+		for _, existing := range current {
+			if _, ok := used[existing.UID]; !ok && existing.Name == cp.Name {
+				deleted = append(deleted, existing)
+			}
+		}
+
+		step := importStep{change: definitions.ProvisioningImportChange{Resource: importResourceContactPoint, Name: cp.Name, Action: importActionCreate}}
+		if len(used) > 0 || len(deleted) > 0 {
+			step.change.Action = importActionUnchanged
+		}
+		var create, update []definitions.EmbeddedContactPoint
+		for i, ecp := range imported {
+			path := fmt.Sprintf("integrations[%d]", i)
+			if matched[i] == nil {
+				if err := checkImportedSecrets(ecp); err != nil {
+					return nil, nil, err
+				}
+				create = append(create, ecp)
+				step.change.Diff = append(step.change.Diff, definitions.ProvisioningImportDiff{Path: path, Op: importOpAdd})
+				continue
+			}
+			diff, err := diffImportResource(path, integrationForImportDiff(*matched[i]), integrationForImportDiff(ecp))
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(diff) > 0 || matched[i].Name != cp.Name {
+				ecp.UID = matched[i].UID
+				update = append(update, ecp)
+				step.change.Diff = append(step.change.Diff, diff...)
+			}
+		}
+		for i := range deleted {
+			step.change.Diff = append(step.change.Diff, definitions.ProvisioningImportDiff{Path: fmt.Sprintf("integrations[%d]", len(imported)+i), Op: importOpRemove})
+		}
+		if step.change.Action == importActionCreate {
+			step.change.Diff = nil
+		} else if len(create)+len(update)+len(deleted) > 0 {
+			step.change.Action = importActionUpdate
+		}
+
+		step.apply = func(ctx context.Context) error {
+			for _, ecp := range create {
+				if _, err := srv.contactPointService.CreateContactPoint(ctx, orgID, c.SignedInUser, ecp, provenance); err != nil {
+					return err
+				}
+			}
+			for _, ecp := range update {
+				if err := srv.contactPointService.UpdateContactPoint(ctx, orgID, ecp, provenance); err != nil {
+					return err
+				}
+			}
+			for _, ecp := range deleted {
+				if err := srv.contactPointService.DeleteContactPoint(ctx, orgID, ecp.UID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		steps = append(steps, step)
+	}
+	return steps, names, nil
+}
+
+// checkImportedSecrets rejects new integrations with redacted secure settings. Exports contain redacted values
+// unless they are decrypted, and there is no current value that they could be replaced with.
+func checkImportedSecrets(ecp definitions.EmbeddedContactPoint) error {
+	secretKeys, err := channels_config.GetSecretKeysForContactPointType(ecp.Type)
+	if err != nil {
+		return makeErrInvalidImport("integration %s of contact point '%s': %s", ecp.Type, ecp.Name, err)
+	}
+	for _, key := range secretKeys {
+		if ecp.Settings.Get(key).MustString() == definitions.RedactedValue {
+			return makeErrInvalidImport("integration %s of contact point '%s' is new but its setting '%s' is redacted, export the contact point with decrypted settings", ecp.Type, ecp.Name, key)
+		}
+	}
+	return nil
+}
+
+func integrationForImportDiff(ecp definitions.EmbeddedContactPoint) importedIntegration {
+	result := importedIntegration{
+		Type:                  ecp.Type,
+		DisableResolveMessage: ecp.DisableResolveMessage,
+	}
+	if ecp.Settings != nil {
+		result.Settings, _ = ecp.Settings.MarshalJSON()
+	}
+	return result
+}
+
+func (srv *ProvisioningSrv) planPolicyTreeImport(ctx context.Context, orgID int64, policy definitions.NotificationPolicyExport, receivers, muteTimings map[string]struct{}, provenance alerting_models.Provenance) (importStep, error) {
+	if policy.RouteExport == nil {
+		return importStep{}, makeErrInvalidImport("empty notification policy tree")
+	}
+	tree, err := RouteFromRouteExport(policy.RouteExport)
+	if err != nil {
+		return importStep{}, makeErrInvalidImport("invalid notification policy tree: %s", err)
+	}
+	if err := tree.Validate(); err != nil {
+		return importStep{}, makeErrInvalidImport("invalid notification policy tree: %s", err)
+	}
+	if err := tree.ValidateReceivers(receivers); err != nil {
+		return importStep{}, makeErrInvalidImport("invalid notification policy tree: %s", err)
+	}
+	if err := tree.ValidateMuteTimes(muteTimings); err != nil {
+		return importStep{}, makeErrInvalidImport("invalid notification policy tree: %s", err)
+	}
+
+	current, _, err := srv.policies.GetPolicyTree(ctx, orgID)
+	if err != nil {
+		return importStep{}, err
+	}
+	step := importStep{change: definitions.ProvisioningImportChange{Resource: importResourceNotificationPolicy}}
+	if step.change.Diff, err = diffImportResource("", RouteExportFromRoute(&current), RouteExportFromRoute(&tree)); err != nil {
+		return importStep{}, err
+	}
+	step.change.Action = importAction(step.change.Diff)
+	step.apply = func(ctx context.Context) error {
+		_, _, err := srv.policies.UpdatePolicyTree(ctx, orgID, tree, provenance, "")
+		return err
+	}
+	return step, nil
+}
+
+func (srv *ProvisioningSrv) planRuleGroupsImport(c *contextmodel.ReqContext, groups []definitions.AlertRuleGroupExport, receivers, muteTimings map[string]struct{}, provenance alerting_models.Provenance) ([]importStep, error) {
+	ctx := c.Req.Context()
+	steps := make([]importStep, 0, len(groups))
+	seen := make(map[alerting_models.AlertRuleGroupKey]struct{}, len(groups))
+	for _, export := range groups {
+		if export.FolderUID == "" {
+			if export.Folder == "" {
+				return nil, makeErrInvalidImport("rule group '%s' has no folder", export.Name)
+			}
+			uid, err := srv.folderUIDByFullpath(ctx, c, export.Folder)
+			if err != nil {
+				return nil, err
+			}
+			export.FolderUID = uid
+		}
+		group, err := AlertRuleGroupFromAlertRuleGroupExport(export)
+		if err != nil {
+			return nil, makeErrInvalidImport("rule group '%s': %s", export.Name, err)
+		}
+		key := alerting_models.AlertRuleGroupKey{OrgID: c.SignedInUser.GetOrgID(), NamespaceUID: group.FolderUID, RuleGroup: group.Title}
+		if _, ok := seen[key]; ok {
+			return nil, makeErrInvalidImport("duplicate rule group '%s' in folder %s", group.Title, group.FolderUID)
+		}
+		seen[key] = struct{}{}
+		for _, rule := range group.Rules {
+			if err := validateImportedNotificationSettings(rule, receivers, muteTimings); err != nil {
+				return nil, err
+			}
+		}
+
+		step := importStep{change: definitions.ProvisioningImportChange{Resource: importResourceRuleGroup, Name: group.Title, FolderUID: group.FolderUID}}
+		existing, err := srv.alertRules.GetRuleGroup(ctx, c.SignedInUser, group.FolderUID, group.Title)
+		if err != nil && !errors.Is(err, alerting_models.ErrAlertRuleGroupNotFound) {
+			return nil, err
+		}
+		// Rules exported to HCL have no UID, they update the rules with the same title.
+		uidByTitle := make(map[string]string, len(existing.Rules))
+		for _, rule := range existing.Rules {
+			uidByTitle[rule.Title] = rule.UID
+		}
+		for i := range group.Rules {
+			if group.Rules[i].UID == "" {
+				group.Rules[i].UID = uidByTitle[group.Rules[i].Title]
+			}
+		}
+
+		delta, err := srv.alertRules.CalculateRuleGroupChanges(ctx, c.SignedInUser, group)
+		if err != nil {
+			return nil, fmt.Errorf("rule group '%s': %w", group.Title, err)
+		}
+		if len(existing.Rules) == 0 {
+			step.change.Action = importActionCreate
+		} else {
+			step.change.Diff = ruleGroupImportDiff(delta)
+			step.change.Action = importAction(step.change.Diff)
+		}
+		step.apply = func(ctx context.Context) error {
+			return srv.alertRules.ReplaceRuleGroup(ctx, c.SignedInUser, group, provenance)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func (srv *ProvisioningSrv) folderUIDByFullpath(ctx context.Context, c *contextmodel.ReqContext, fullpath string) (string, error) {
+	var parentUID *string
+	for _, title := range folderimpl.SplitFullpath(fullpath) {
+		f, err := srv.folderSvc.Get(ctx, &folder.GetFolderQuery{
+			Title:        &title,
+			ParentUID:    parentUID,
+			OrgID:        c.SignedInUser.GetOrgID(),
+			SignedInUser: c.SignedInUser,
+		})
+		if errors.Is(err, dashboards.ErrFolderNotFound) {
+			return "", makeErrInvalidImport("folder '%s' not found", fullpath)
+		}
+		if err != nil {
+			return "", err
+		}
+		parentUID = &f.UID
+	}
+	if parentUID == nil {
+		return "", makeErrInvalidImport("invalid folder '%s'", fullpath)
+	}
+	return *parentUID, nil
+}
+
+func validateImportedNotificationSettings(rule alerting_models.AlertRule, receivers, muteTimings map[string]struct{}) error {
+	for _, ns := range rule.NotificationSettings {
+		if _, ok := receivers[ns.Receiver]; !ok {
+			return makeErrInvalidImport("rule '%s' refers to unknown contact point '%s'", rule.Title, ns.Receiver)
+		}
+		for _, name := range ns.MuteTimeIntervals {
+			if _, ok := muteTimings[name]; !ok {
+				return makeErrInvalidImport("rule '%s' refers to unknown mute timing '%s'", rule.Title, name)
+			}
+		}
+	}
+	return nil
+}
+
+// ruleGroupImportDiff returns the changed fields of the rules of the group. The delta can contain updates of rules
+// that are not changed.
+func ruleGroupImportDiff(delta *store.GroupDelta) []definitions.ProvisioningImportDiff {
+	var result []definitions.ProvisioningImportDiff
+	for _, rule := range delta.New {
+		result = append(result, definitions.ProvisioningImportDiff{Path: fmt.Sprintf("rules[%s]", rule.Title), Op: importOpAdd})
+	}
+	for _, update := range delta.Update {
+		seen := make(map[string]struct{}, len(update.Diff))
+		for _, d := range update.Diff {
+			path := fmt.Sprintf("rules[%s].%s", update.New.Title, d.Path)
+			if _, ok := seen[path]; ok {
+				continue
+			}
+			seen[path] = struct{}{}
+			op := importOpChange
+			if !d.Left.IsValid() {
+				op = importOpAdd
+			} else if !d.Right.IsValid() {
+				op = importOpRemove
+			}
+			result = append(result, definitions.ProvisioningImportDiff{Path: path, Op: op})
+		}
+	}
+	for _, rule := range delta.Delete {
+		result = append(result, definitions.ProvisioningImportDiff{Path: fmt.Sprintf("rules[%s]", rule.Title), Op: importOpRemove})
+	}
+	return result
+}
+
+func importAction(diff []definitions.ProvisioningImportDiff) string {
+	if len(diff) == 0 {
+		return importActionUnchanged
+	}
+	return importActionUpdate
+}
+
+// diffImportResource compares the JSON representations of the current and the imported resource and returns the
+// paths of the fields that are different. Missing and empty values are equal.
+func diffImportResource(path string, current, imported any) ([]definitions.ProvisioningImportDiff, error) {
+	var left, right any
+	for _, v := range []struct {
+		in  any
+		out *any
+	}{{current, &left}, {imported, &right}} {
+		data, err := json.Marshal(v.in)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, v.out); err != nil {
+			return nil, err
+		}
+	}
+	var result []definitions.ProvisioningImportDiff
+	diffImportValues(path, left, right, &result)
+	return result, nil
+}
+
+func diffImportValues(path string, left, right any, result *[]definitions.ProvisioningImportDiff) {
+	switch {
+	case isEmptyImportValue(left) && isEmptyImportValue(right):
+		return
+	case isEmptyImportValue(left):
+		*result = append(*result, definitions.ProvisioningImportDiff{Path: path, Op: importOpAdd})
+		return
+	case isEmptyImportValue(right):
+		*result = append(*result, definitions.ProvisioningImportDiff{Path: path, Op: importOpRemove})
+		return
+	}
+
+	leftMap, leftIsMap := left.(map[string]any)
+	rightMap, rightIsMap := right.(map[string]any)
+	if leftIsMap && rightIsMap {
+		keys := make([]string, 0, len(leftMap)+len(rightMap))
+		for k := range leftMap {
+			keys = append(keys, k)
+		}
+		for k := range rightMap {
+			if _, ok := leftMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			diffImportValues(p, leftMap[k], rightMap[k], result)
+		}
+		return
+	}
+
+	leftSlice, leftIsSlice := left.([]any)
+	rightSlice, rightIsSlice := right.([]any)
+	if leftIsSlice && rightIsSlice {
+		for i := 0; i < max(len(leftSlice), len(rightSlice)); i++ {
+			var l, r any
+			if i < len(leftSlice) {
+				l = leftSlice[i]
+			}
+			if i < len(rightSlice) {
+				r = rightSlice[i]
+			}
+			diffImportValues(fmt.Sprintf("%s[%d]", path, i), l, r, result)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		*result = append(*result, definitions.ProvisioningImportDiff{Path: path, Op: importOpChange})
+	}
+}
+
+func isEmptyImportValue(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case bool:
+		return !val
+	case float64:
+		return val == 0
+	case map[string]any:
+		return len(val) == 0
+	case []any:
+		return len(val) == 0
+	}
+	return false
+}
+
+func importErrorResponse(err error) response.Response {
+	switch {
+	case errors.Is(err, provisioning.ErrValidation),
+		errors.Is(err, alerting_models.ErrAlertRuleFailedValidation),
+		errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation):
+		return ErrResp(http.StatusBadRequest, err, "")
+	case errors.Is(err, store.ErrNoAlertmanagerConfiguration):
+		return ErrResp(http.StatusNotFound, err, "")
+	case errors.Is(err, store.ErrOptimisticLock):
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, "failed to import alerting resources", err)
+}
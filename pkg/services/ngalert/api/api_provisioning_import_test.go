@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/legacy_storage"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestProvisioningApiImport(t *testing.T) {
+	createTestEnv := func(t *testing.T) testEnvironment {
+		env := createTestEnv(t, testConfig)
+		env.ac = &recordingAccessControlFake{
+			Callback: func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
+				return true, nil
+			},
+		}
+		return env
+	}
+	createProvisioningSrvSut := func(t *testing.T) ProvisioningSrv {
+		env := createTestEnv(t)
+		return createProvisioningSrvSutFromEnv(t, &env)
+	}
+	insertImportTestRule := func(t *testing.T, sut ProvisioningSrv) {
+		t.Helper()
+		rule := createTestAlertRule("rule", 1)
+		rule.For = model.Duration(time.Minute)
+		rule.NotificationSettings = nil
+		insertRule(t, sut, rule)
+	}
+	exportRuleGroup := func(t *testing.T, sut ProvisioningSrv, format string) []byte {
+		t.Helper()
+		rc := createTestRequestCtx()
+		rc.Context.Req.Form.Set("format", format)
+		response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+		require.Equal(t, http.StatusOK, response.Status())
+		return response.Body()
+	}
+	importFile := func(t *testing.T, sut ProvisioningSrv, format string, dryRun bool, body []byte) (int, definitions.ProvisioningImportResult) {
+		t.Helper()
+		rc := createTestRequestCtx()
+		rc.Context.Req.Form.Set("format", format)
+		if dryRun {
+			rc.Context.Req.Form.Set("dry_run", "true")
+		}
+		response := sut.RoutePostProvisioningImport(&rc, body)
+		var result definitions.ProvisioningImportResult
+		if response.Status() == http.StatusOK {
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+		}
+		return response.Status(), result
+	}
+
+	for _, format := range []string{"yaml", "json", "hcl"} {
+		t.Run("exported rule group in "+format+" is unchanged", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			insertImportTestRule(t, sut)
+
+			status, result := importFile(t, sut, format, false, exportRuleGroup(t, sut, format))
+
+			require.Equal(t, http.StatusOK, status)
+			require.Equal(t, []definitions.ProvisioningImportChange{{
+				Resource:  importResourceRuleGroup,
+				Name:      "my-cool-group",
+				FolderUID: "folder-uid",
+				Action:    importActionUnchanged,
+			}}, result.Changes)
+		})
+	}
+
+	t.Run("dry run returns the diff without saving the changes", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		insertImportTestRule(t, sut)
+		var file definitions.AlertingFileExport
+		require.NoError(t, json.Unmarshal(exportRuleGroup(t, sut, "json"), &file))
+		file.Groups[0].Rules[0].For = model.Duration(5 * time.Minute)
+		body, err := json.Marshal(file)
+		require.NoError(t, err)
+
+		status, result := importFile(t, sut, "json", true, body)
+
+		require.Equal(t, http.StatusOK, status)
+		require.True(t, result.DryRun)
+		require.Len(t, result.Changes, 1)
+		require.Equal(t, importActionUpdate, result.Changes[0].Action)
+		require.Equal(t, []definitions.ProvisioningImportDiff{{Path: "rules[rule].For", Op: importOpChange}}, result.Changes[0].Diff)
+
+		_, result = importFile(t, sut, "json", true, body)
+		require.Equal(t, importActionUpdate, result.Changes[0].Action)
+
+		t.Run("and saves them without dry run", func(t *testing.T) {
+			status, _ := importFile(t, sut, "json", false, body)
+			require.Equal(t, http.StatusOK, status)
+
+			_, result := importFile(t, sut, "json", true, body)
+			require.Equal(t, importActionUnchanged, result.Changes[0].Action)
+		})
+	})
+
+	t.Run("exported mute timings in hcl are unchanged", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+		rc.Context.Req.Form.Set("format", "hcl")
+		export := sut.RouteGetMuteTimingsExport(&rc)
+		require.Equal(t, http.StatusOK, export.Status())
+
+		status, result := importFile(t, sut, "hcl", false, export.Body())
+
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, result.Changes, 2)
+		for _, change := range result.Changes {
+			require.Equal(t, importResourceMuteTiming, change.Resource)
+			require.Equal(t, importActionUnchanged, change.Action)
+		}
+	})
+
+	t.Run("new mute timing is created", func(t *testing.T) {
+		env := createTestEnv(t)
+		saved := models.SaveAlertmanagerConfigurationCmd{}
+		env.configs.(*legacy_storage.MockAMConfigStore).EXPECT().SaveSucceedsIntercept(&saved)
+		sut := createProvisioningSrvSutFromEnv(t, &env)
+		body := []byte(`
+apiVersion: 1
+muteTimes:
+  - orgId: 1
+    name: maintenance
+    time_intervals:
+      - weekdays: ["saturday"]
+`)
+
+		status, result := importFile(t, sut, "yaml", false, body)
+
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, []definitions.ProvisioningImportChange{{Resource: importResourceMuteTiming, Name: "maintenance", Action: importActionCreate}}, result.Changes)
+		require.Contains(t, saved.AlertmanagerConfiguration, `{"name":"maintenance","time_intervals":[{"weekdays":["saturday"]}]}`)
+	})
+
+	t.Run("resources are saved in one transaction", func(t *testing.T) {
+		env := createTestEnv(t)
+		env.configs.(*legacy_storage.MockAMConfigStore).EXPECT().SaveSucceeds()
+		sut := createProvisioningSrvSutFromEnv(t, &env)
+		xact := &countingTransactionManager{}
+		sut.xact = xact
+		body := []byte(`
+apiVersion: 1
+muteTimes:
+  - orgId: 1
+    name: maintenance
+    time_intervals:
+      - weekdays: ["saturday"]
+  - orgId: 1
+    name: holidays
+    time_intervals:
+      - months: ["december"]
+`)
+
+		status, _ := importFile(t, sut, "yaml", true, body)
+		require.Equal(t, http.StatusOK, status)
+		require.Zero(t, xact.calls, "dry run saves nothing")
+
+		status, result := importFile(t, sut, "yaml", false, body)
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, result.Changes, 2)
+		require.Equal(t, 1, xact.calls)
+	})
+
+	t.Run("new integration with a redacted secure setting returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		body := []byte(`
+apiVersion: 1
+contactPoints:
+  - orgId: 1
+    name: new-slack
+    receivers:
+      - uid: new-slack-uid
+        type: slack
+        settings:
+          recipient: "#alerts"
+          url: "[REDACTED]"
+`)
+
+		status, _ := importFile(t, sut, "yaml", true, body)
+
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unknown contact point of a rule returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		var file definitions.AlertingFileExport
+		insertImportTestRule(t, sut)
+		require.NoError(t, json.Unmarshal(exportRuleGroup(t, sut, "json"), &file))
+		file.Groups[0].Rules[0].NotificationSettings = &definitions.AlertRuleNotificationSettingsExport{Receiver: "unknown"}
+		body, err := json.Marshal(file)
+		require.NoError(t, err)
+
+		status, _ := importFile(t, sut, "json", true, body)
+
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unsupported format returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+
+		status, _ := importFile(t, sut, "xml", true, []byte("<xml/>"))
+
+		require.Equal(t, http.StatusBadRequest, status)
+	})
+}
+
+type countingTransactionManager struct {
+	calls int
+}
+
+func (m *countingTransactionManager) InTransaction(ctx context.Context, work func(ctx context.Context) error) error {
+	m.calls++
+	return work(ctx)
+}
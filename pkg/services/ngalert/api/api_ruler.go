@@ -429,6 +429,15 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			return err
 		}
 
+		// the submitted rules are patched with the stored optional fields by CalculateChanges, so they make the whole group.
+		groupRules := make([]*ngmodels.AlertRule, 0, len(rules))
+		for _, r := range rules {
+			groupRules = append(groupRules, &r.AlertRule)
+		}
+		if err := ngmodels.ValidateRuleGroupDependencies(groupRules); err != nil {
+			return err
+		}
+
 		newOrUpdatedNotificationSettings := groupChanges.NewOrUpdatedNotificationSettings()
 		if len(newOrUpdatedNotificationSettings) > 0 {
 			dbConfig, err = srv.amConfigStore.GetLatestAlertmanagerConfiguration(c.Req.Context(), groupChanges.GroupKey.OrgID)
@@ -607,6 +616,7 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(r.NotificationSettings),
 			Record:               ApiRecordFromModelRecord(r.Record),
 			Metadata:             AlertRuleMetadataFromModelMetadata(r.Metadata),
+			Dependencies:         ApiRuleDependenciesFromModelRuleDependencies(r.Dependencies),
 		},
 	}
 	forDuration := model.Duration(r.For)
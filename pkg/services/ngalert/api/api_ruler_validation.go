@@ -314,7 +314,7 @@ func ValidateRuleGroup(
 			uids[rule.UID] = idx
 		}
 
-		var hasPause, isPaused, hasMetadata bool
+		var hasPause, isPaused, hasMetadata, hasDependencies bool
 		original := ruleGroupConfig.Rules[idx]
 		if alert := original.GrafanaManagedAlert; alert != nil {
 			if alert.IsPaused != nil {
@@ -324,6 +324,10 @@ func ValidateRuleGroup(
 			if alert.Metadata != nil {
 				hasMetadata = true
 			}
+			if alert.Dependencies != nil {
+				rule.Dependencies = ModelRuleDependenciesFromApiRuleDependencies(*alert.Dependencies)
+				hasDependencies = true
+			}
 		}
 
 		ruleWithOptionals := ngmodels.AlertRuleWithOptionals{}
@@ -332,6 +336,7 @@ func ValidateRuleGroup(
 		ruleWithOptionals.AlertRule = *rule
 		ruleWithOptionals.HasPause = hasPause
 		ruleWithOptionals.HasMetadata = hasMetadata
+		ruleWithOptionals.HasDependencies = hasDependencies
 
 		result = append(result, &ruleWithOptionals)
 	}
@@ -29,6 +29,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/ruletest"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
@@ -47,6 +48,7 @@ type TestingApiSrv struct {
 	evaluator       eval.EvaluatorFactory
 	cfg             *setting.UnifiedAlertingSettings
 	backtesting     *backtesting.Engine
+	ruleTests       *ruletest.Engine
 	featureManager  featuremgmt.FeatureToggles
 	appUrl          *url.URL
 	tracer          tracing.Tracer
@@ -286,3 +288,19 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// RouteUnitTestRules runs the tests of a unit test file against the rule groups of the file. The rules are evaluated
+// with the input series of the tests, data sources are not queried.
+func (srv TestingApiSrv) RouteUnitTestRules(c *contextmodel.ReqContext, file apimodels.RuleUnitTestFile) response.Response {
+	if len(file.RuleFiles) > 0 {
+		return ErrResp(http.StatusBadRequest, nil, "rule_files are not supported by the API, the rule groups must be specified in groups")
+	}
+	result, err := srv.ruleTests.Run(c.Req.Context(), c.SignedInUser.GetOrgID(), file)
+	if err != nil {
+		if errors.Is(err, ruletest.ErrInvalidTestFile) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to run the tests")
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -9,9 +9,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
@@ -26,8 +28,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/ruletest"
 	fakes2 "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -391,6 +395,66 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestRouteUnitTestRules(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	srv := &TestingApiSrv{
+		ruleTests: ruletest.NewEngine(nil, &setting.Cfg{ExpressionsEnabled: true}, featuremgmt.WithFeatures(), tracing.InitializeTracerForTest()),
+	}
+
+	t.Run("should return 400 if the file refers to rule files", func(t *testing.T) {
+		response := srv.RouteUnitTestRules(rc, definitions.RuleUnitTestFile{RuleFiles: []string{"rules.yaml"}})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 400 if the file is invalid", func(t *testing.T) {
+		response := srv.RouteUnitTestRules(rc, definitions.RuleUnitTestFile{})
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 200 with the results of the tests", func(t *testing.T) {
+		condition := "A"
+		file := definitions.RuleUnitTestFile{
+			Groups: []definitions.AlertRuleGroupExport{{
+				Name:     "group",
+				Interval: model.Duration(time.Minute),
+				Rules: []definitions.AlertRuleExport{{
+					UID:       "rule",
+					Title:     "rule",
+					Condition: &condition,
+					Data: []definitions.AlertQueryExport{{
+						RefID:         "A",
+						DatasourceUID: expr.DatasourceUID,
+						Model:         map[string]any{"type": "math", "expression": "1 > 0"},
+					}},
+				}},
+			}},
+			Tests: []definitions.RuleUnitTest{{
+				Name: "always firing",
+				AlertTests: []definitions.RuleUnitTestCase{{
+					RuleUID: "rule",
+				}},
+			}},
+		}
+
+		response := srv.RouteUnitTestRules(rc, file)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var result definitions.RuleUnitTestResults
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.False(t, result.Passed)
+		require.Len(t, result.Tests, 1)
+		require.Len(t, result.Tests[0].Failures, 1)
+		require.Contains(t, result.Tests[0].Failures[0], "unexpected alert Alerting")
+	})
+}
+
 func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory, featureManager featuremgmt.FeatureToggles, ruleStore RuleStore) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New()
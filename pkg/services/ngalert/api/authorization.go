@@ -84,6 +84,20 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingReceiversRead),
 			ac.EvalPermission(ac.ActionAlertingReceiversReadSecrets),
 		)
+	case http.MethodGet + "/api/v1/notifications/deliveries":
+		// Deliveries contain the alerts of the notifications.
+		eval = ac.EvalAll(
+			ac.EvalAny(
+				ac.EvalPermission(ac.ActionAlertingNotificationsRead),
+				ac.EvalPermission(ac.ActionAlertingReceiversRead),
+			),
+			ac.EvalPermission(ac.ActionAlertingInstanceRead),
+		)
+	case http.MethodPost + "/api/v1/notifications/policies/simulate":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingNotificationsRead),
+			ac.EvalPermission(ac.ActionAlertingRoutesRead),
+		)
 
 	// Grafana, Prometheus-compatible Paths
 	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules":
@@ -97,6 +111,9 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/v1/rule/backtest":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/unit-test":
+		// rules are evaluated with the input series of the tests, data sources are not queried
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 	case http.MethodPost + "/api/v1/eval":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
@@ -176,7 +193,8 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingInstanceRead),
 			ac.EvalPermission(ac.ActionAlertingSilencesRead),
 		)
-	case http.MethodPost + "/api/alertmanager/grafana/api/v2/silences":
+	case http.MethodPost + "/api/alertmanager/grafana/api/v2/silences",
+		http.MethodPost + "/api/v1/provisioning/silence-templates/{UID}/apply":
 		eval = ac.EvalAll(
 			ac.EvalAny(
 				ac.EvalPermission(ac.ActionAlertingInstanceRead),
@@ -279,7 +297,10 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodDelete + "/api/v1/ngalert/admin_config",
 		http.MethodGet + "/api/v1/ngalert/admin_config",
 		http.MethodPost + "/api/v1/ngalert/admin_config",
-		http.MethodGet + "/api/v1/ngalert/alertmanagers":
+		http.MethodGet + "/api/v1/ngalert/alertmanagers",
+		http.MethodGet + "/api/v1/ngalert/state/export",
+		http.MethodPost + "/api/v1/ngalert/state/import",
+		http.MethodPost + "/api/v1/notifications/deliveries/{ID}/resend":
 		return middleware.ReqOrgAdmin
 
 	// Grafana-only Provisioning Export Paths for everything except contact points.
@@ -350,7 +371,9 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodGet + "/api/v1/provisioning/templates",
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
-		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}":
+		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodGet + "/api/v1/provisioning/silence-templates",
+		http.MethodGet + "/api/v1/provisioning/silence-templates/{UID}":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningRead),
 			ac.EvalPermission(ac.ActionAlertingNotificationsProvisioningRead), // organization scope
@@ -414,6 +437,9 @@ func (api *API) authorize(method, path string) web.Handler {
 				),
 			),
 		)
+	case http.MethodPost + "/api/v1/provisioning/import":
+		// the import can change all kinds of alerting resources
+		eval = ac.EvalPermission(ac.ActionAlertingProvisioningWrite)
 
 	case http.MethodPut + "/api/v1/provisioning/policies",
 		http.MethodDelete + "/api/v1/provisioning/policies",
@@ -424,7 +450,10 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodDelete + "/api/v1/provisioning/templates/{name}",
 		http.MethodPost + "/api/v1/provisioning/mute-timings",
 		http.MethodPut + "/api/v1/provisioning/mute-timings/{name}",
-		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}":
+		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodPost + "/api/v1/provisioning/silence-templates",
+		http.MethodPut + "/api/v1/provisioning/silence-templates/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/silence-templates/{UID}":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),              // organization scope,
 			ac.EvalPermission(ac.ActionAlertingNotificationsProvisioningWrite), // organization scope
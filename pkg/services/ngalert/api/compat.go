@@ -3,11 +3,14 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	amConfig "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -34,6 +37,7 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		IsPaused:             a.IsPaused,
 		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:               ModelRecordFromApiRecord(a.Record),
+		Dependencies:         ModelRuleDependenciesFromApiRuleDependencies(a.Dependencies),
 	}
 
 	if rule.Type() == models.RuleTypeRecording {
@@ -64,6 +68,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:               ApiRecordFromModelRecord(rule.Record),
+		Dependencies:         ApiRuleDependenciesFromModelRuleDependencies(rule.Dependencies),
 	}
 }
 
@@ -215,6 +220,7 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsExportFromNotificationSettings(rule.NotificationSettings),
 		Record:               AlertRuleRecordExportFromRecord(rule.Record),
+		Dependencies:         AlertRuleDependencyExportFromRuleDependencies(rule.Dependencies),
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
@@ -270,6 +276,108 @@ func AlertQueryExportFromAlertQuery(query models.AlertQuery) (definitions.AlertQ
 	}, nil
 }
 
+// AlertRuleGroupFromAlertRuleGroupExport converts definitions.AlertRuleGroupExport to models.AlertRuleGroup.
+// The folder of the group is taken from FolderUID, Folder must be resolved by the caller.
+func AlertRuleGroupFromAlertRuleGroupExport(d definitions.AlertRuleGroupExport) (models.AlertRuleGroup, error) {
+	group := models.AlertRuleGroup{
+		Title:     d.Name,
+		FolderUID: d.FolderUID,
+		Interval:  int64(time.Duration(d.Interval).Seconds()),
+		Rules:     make([]models.AlertRule, 0, len(d.Rules)),
+	}
+	for i := range d.Rules {
+		rule, err := AlertRuleFromAlertRuleExport(d.Rules[i])
+		if err != nil {
+			return models.AlertRuleGroup{}, fmt.Errorf("rule '%s' failed to parse: %w", d.Rules[i].Title, err)
+		}
+		rule.NamespaceUID = d.FolderUID
+		rule.RuleGroup = d.Name
+		rule.IntervalSeconds = group.Interval
+		group.Rules = append(group.Rules, rule)
+	}
+	return group, nil
+}
+
+// AlertRuleFromAlertRuleExport converts definitions.AlertRuleExport to models.AlertRule.
+// Missing states are set to the defaults of file provisioning.
+func AlertRuleFromAlertRuleExport(r definitions.AlertRuleExport) (models.AlertRule, error) {
+	rule := models.AlertRule{
+		UID:          r.UID,
+		Title:        r.Title,
+		For:          time.Duration(r.For),
+		DashboardUID: r.DashboardUID,
+		PanelID:      r.PanelID,
+		NoDataState:  models.NoData,
+		ExecErrState: models.AlertingErrState,
+		IsPaused:     r.IsPaused,
+		Record:       ModelRecordFromAlertRuleRecordExport(r.Record),
+		Dependencies: ModelRuleDependenciesFromAlertRuleDependencyExports(r.Dependencies),
+	}
+	if r.Condition != nil {
+		rule.Condition = *r.Condition
+	}
+	if r.NoDataState != nil {
+		state, err := models.NoDataStateFromString(string(*r.NoDataState))
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.NoDataState = state
+	}
+	if r.ExecErrState != nil {
+		state, err := models.ErrStateFromString(string(*r.ExecErrState))
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.ExecErrState = state
+	}
+	if r.Annotations != nil {
+		rule.Annotations = *r.Annotations
+	}
+	if r.Labels != nil {
+		rule.Labels = *r.Labels
+	}
+	for i := range r.Data {
+		query, err := AlertQueryFromAlertQueryExport(r.Data[i])
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.Data = append(rule.Data, query)
+	}
+	if r.NotificationSettings != nil {
+		ns, err := NotificationSettingsFromAlertRuleNotificationSettingsExport(*r.NotificationSettings)
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.NotificationSettings = []models.NotificationSettings{ns}
+	}
+
+	if rule.Type() == models.RuleTypeRecording {
+		models.ClearRecordingRuleIgnoredFields(&rule)
+	}
+	return rule, nil
+}
+
+// AlertQueryFromAlertQueryExport converts definitions.AlertQueryExport to models.AlertQuery.
+func AlertQueryFromAlertQueryExport(q definitions.AlertQueryExport) (models.AlertQuery, error) {
+	mdl, err := json.Marshal(q.Model)
+	if err != nil {
+		return models.AlertQuery{}, err
+	}
+	query := models.AlertQuery{
+		RefID: q.RefID,
+		RelativeTimeRange: models.RelativeTimeRange{
+			From: models.Duration(time.Duration(q.RelativeTimeRange.FromSeconds) * time.Second),
+			To:   models.Duration(time.Duration(q.RelativeTimeRange.ToSeconds) * time.Second),
+		},
+		DatasourceUID: q.DatasourceUID,
+		Model:         mdl,
+	}
+	if q.QueryType != nil {
+		query.QueryType = *q.QueryType
+	}
+	return query, nil
+}
+
 // AlertingFileExportFromEmbeddedContactPoints creates a definitions.AlertingFileExport DTO from []definitions.EmbeddedContactPoint.
 func AlertingFileExportFromEmbeddedContactPoints(orgID int64, ecps []definitions.EmbeddedContactPoint) (definitions.AlertingFileExport, error) {
 	f := definitions.AlertingFileExport{APIVersion: 1}
@@ -371,6 +479,49 @@ func RouteExportFromRoute(route *definitions.Route) *definitions.RouteExport {
 	return &export
 }
 
+// RouteFromRouteExport converts definitions.RouteExport to definitions.Route and validates the route.
+func RouteFromRouteExport(r *definitions.RouteExport) (definitions.Route, error) {
+	// The fields of both models have the same YAML representation.
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	var route definitions.Route
+	if err := yaml.Unmarshal(data, &route); err != nil {
+		return definitions.Route{}, err
+	}
+	return route, nil
+}
+
+// ObjectMatchersFromMatcherExports converts the matchers of the HCL representation of a route to definitions.ObjectMatchers.
+func ObjectMatchersFromMatcherExports(matchers []*definitions.MatcherExport) (definitions.ObjectMatchers, error) {
+	if len(matchers) == 0 {
+		return nil, nil
+	}
+	result := make(definitions.ObjectMatchers, 0, len(matchers))
+	for _, m := range matchers {
+		matchType, ok := matchTypeFromString(m.Match)
+		if !ok {
+			return nil, fmt.Errorf("invalid match type %q of the matcher of label %q", m.Match, m.Label)
+		}
+		matcher, err := labels.NewMatcher(matchType, m.Label, m.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, matcher)
+	}
+	return result, nil
+}
+
+func matchTypeFromString(s string) (labels.MatchType, bool) {
+	for _, t := range []labels.MatchType{labels.MatchEqual, labels.MatchNotEqual, labels.MatchRegexp, labels.MatchNotRegexp} {
+		if t.String() == s {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
 // OmitDefault returns nil if the value is the default.
 func OmitDefault[T comparable](v *T) *T {
 	var def T
@@ -421,6 +572,18 @@ func MuteTimingIntervalToMuteTimeIntervalHclExport(m definitions.MuteTimeInterva
 	return result, err
 }
 
+// MuteTimeIntervalExportFromMuteTimeIntervalHclExport converts definitions.MuteTimeIntervalExportHcl to definitions.MuteTimeIntervalExport using JSON marshalling.
+func MuteTimeIntervalExportFromMuteTimeIntervalHclExport(m definitions.MuteTimeIntervalExportHcl) (definitions.MuteTimeIntervalExport, error) {
+	result := definitions.MuteTimeIntervalExport{}
+	j := jsoniter.ConfigCompatibleWithStandardLibrary
+	mdata, err := j.Marshal(m)
+	if err != nil {
+		return result, err
+	}
+	err = j.Unmarshal(mdata, &result)
+	return result, err
+}
+
 // AlertRuleEditorSettingsFromEditorSettings converts models.EditorSettings to definitions.AlertRuleEditorSettings
 func AlertRuleEditorSettingsFromModelEditorSettings(es models.EditorSettings) *definitions.AlertRuleEditorSettings {
 	return &definitions.AlertRuleEditorSettings{
@@ -494,6 +657,36 @@ func NotificationSettingsFromAlertRuleNotificationSettings(ns *definitions.Alert
 	}
 }
 
+// NotificationSettingsFromAlertRuleNotificationSettingsExport converts definitions.AlertRuleNotificationSettingsExport to models.NotificationSettings
+func NotificationSettingsFromAlertRuleNotificationSettingsExport(ns definitions.AlertRuleNotificationSettingsExport) (models.NotificationSettings, error) {
+	parseIfNotNil := func(field string, s *string) (*model.Duration, error) {
+		if s == nil {
+			return nil, nil
+		}
+		d, err := model.ParseDuration(*s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s of the notification settings: %w", field, err)
+		}
+		return &d, nil
+	}
+	result := models.NotificationSettings{
+		Receiver:          ns.Receiver,
+		GroupBy:           ns.GroupBy,
+		MuteTimeIntervals: ns.MuteTimeIntervals,
+	}
+	var err error
+	if result.GroupWait, err = parseIfNotNil("group_wait", ns.GroupWait); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	if result.GroupInterval, err = parseIfNotNil("group_interval", ns.GroupInterval); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	if result.RepeatInterval, err = parseIfNotNil("repeat_interval", ns.RepeatInterval); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	return result, nil
+}
+
 func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRecordExport {
 	if r == nil {
 		return nil
@@ -504,6 +697,16 @@ func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRec
 	}
 }
 
+func ModelRecordFromAlertRuleRecordExport(r *definitions.AlertRuleRecordExport) *models.Record {
+	if r == nil {
+		return nil
+	}
+	return &models.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
 func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
 	if r == nil {
 		return nil
@@ -524,6 +727,62 @@ func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
 	}
 }
 
+func AlertRuleDependencyExportFromRuleDependencies(deps []models.RuleDependency) []definitions.AlertRuleDependencyExport {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleDependencyExport, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, definitions.AlertRuleDependencyExport{
+			RuleUID: d.RuleUID,
+			RefID:   d.RefID,
+		})
+	}
+	return result
+}
+
+func ModelRuleDependenciesFromApiRuleDependencies(deps []definitions.RuleDependency) []models.RuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]models.RuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, models.RuleDependency{
+			RuleUID: d.RuleUID,
+			RefID:   d.RefID,
+		})
+	}
+	return result
+}
+
+func ModelRuleDependenciesFromAlertRuleDependencyExports(deps []definitions.AlertRuleDependencyExport) []models.RuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]models.RuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, models.RuleDependency{
+			RuleUID: d.RuleUID,
+			RefID:   d.RefID,
+		})
+	}
+	return result
+}
+
+func ApiRuleDependenciesFromModelRuleDependencies(deps []models.RuleDependency) []definitions.RuleDependency {
+	if len(deps) == 0 {
+		return nil
+	}
+	result := make([]definitions.RuleDependency, 0, len(deps))
+	for _, d := range deps {
+		result = append(result, definitions.RuleDependency{
+			RuleUID: d.RuleUID,
+			RefID:   d.RefID,
+		})
+	}
+	return result
+}
+
 func GettableGrafanaReceiverFromReceiver(r *models.Integration, provenance models.Provenance) (definitions.GettableGrafanaReceiver, error) {
 	out := definitions.GettableGrafanaReceiver{
 		UID:                   r.UID,
@@ -576,3 +835,110 @@ func GettableApiReceiversFromReceivers(recvs []*models.Receiver) ([]*definitions
 	}
 	return out, nil
 }
+
+func ApiAlertStateSnapshotFromModel(snapshot models.AlertStateSnapshot) definitions.AlertStateSnapshot {
+	instances := make([]definitions.AlertInstanceSnapshot, 0, len(snapshot.Instances))
+	for _, i := range snapshot.Instances {
+		instances = append(instances, definitions.AlertInstanceSnapshot{
+			RuleUID:           i.RuleUID,
+			Labels:            i.Labels,
+			State:             string(i.State),
+			StateReason:       i.StateReason,
+			StartsAt:          i.StartsAt,
+			EndsAt:            i.EndsAt,
+			LastEvaluatedAt:   i.LastEvaluatedAt,
+			LastSentAt:        i.LastSentAt,
+			ResolvedAt:        i.ResolvedAt,
+			ResultFingerprint: i.ResultFingerprint,
+			Annotations:       i.Annotations,
+		})
+	}
+	return definitions.AlertStateSnapshot{
+		Version:    snapshot.Version,
+		OrgID:      snapshot.OrgID,
+		ExportedAt: snapshot.ExportedAt,
+		Instances:  instances,
+	}
+}
+
+func AlertStateSnapshotFromApi(snapshot definitions.AlertStateSnapshot) models.AlertStateSnapshot {
+	instances := make([]models.AlertInstanceSnapshot, 0, len(snapshot.Instances))
+	for _, i := range snapshot.Instances {
+		instances = append(instances, models.AlertInstanceSnapshot{
+			RuleUID:           i.RuleUID,
+			Labels:            i.Labels,
+			State:             models.InstanceStateType(i.State),
+			StateReason:       i.StateReason,
+			StartsAt:          i.StartsAt,
+			EndsAt:            i.EndsAt,
+			LastEvaluatedAt:   i.LastEvaluatedAt,
+			LastSentAt:        i.LastSentAt,
+			ResolvedAt:        i.ResolvedAt,
+			ResultFingerprint: i.ResultFingerprint,
+			Annotations:       i.Annotations,
+		})
+	}
+	return models.AlertStateSnapshot{
+		Version:    snapshot.Version,
+		OrgID:      snapshot.OrgID,
+		ExportedAt: snapshot.ExportedAt,
+		Instances:  instances,
+	}
+}
+
+// SilenceTemplateFromApi converts a silence template of the provisioning API to the model.
+func SilenceTemplateFromApi(t definitions.SilenceTemplate) models.SilenceTemplate {
+	return models.SilenceTemplate{
+		UID:      t.UID,
+		Title:    t.Title,
+		Matchers: t.Matchers,
+		Comment:  t.Comment,
+		Duration: time.Duration(t.Duration),
+		Schedule: t.Schedule,
+	}
+}
+
+// ApiSilenceTemplateFromModel converts a silence template to the representation of the provisioning API.
+func ApiSilenceTemplateFromModel(t models.SilenceTemplate, provenance models.Provenance) definitions.SilenceTemplate {
+	return definitions.SilenceTemplate{
+		UID:        t.UID,
+		Title:      t.Title,
+		Matchers:   t.Matchers,
+		Comment:    t.Comment,
+		Duration:   model.Duration(t.Duration),
+		Schedule:   t.Schedule,
+		Provenance: definitions.Provenance(provenance),
+	}
+}
+
+// NotificationDeliveryToApiModel converts models.NotificationDelivery to definitions.NotificationDelivery.
+func NotificationDeliveryToApiModel(d models.NotificationDelivery) definitions.NotificationDelivery {
+	alerts := make([]definitions.NotificationDeliveryAlert, 0, len(d.Alerts))
+	for _, a := range d.Alerts {
+		alerts = append(alerts, definitions.NotificationDeliveryAlert{
+			Labels:       a.Labels,
+			Annotations:  a.Annotations,
+			StartsAt:     a.StartsAt,
+			EndsAt:       a.EndsAt,
+			GeneratorURL: a.GeneratorURL,
+		})
+	}
+	return definitions.NotificationDelivery{
+		ID:                d.ID,
+		Timestamp:         d.Created,
+		Receiver:          d.Receiver,
+		IntegrationUID:    d.IntegrationUID,
+		IntegrationType:   d.IntegrationType,
+		IntegrationIndex:  d.IntegrationIndex,
+		GroupKey:          d.GroupKey,
+		GroupLabels:       d.GroupLabels,
+		Alerts:            alerts,
+		AlertFingerprints: d.AlertFingerprints,
+		PayloadDigest:     d.PayloadDigest,
+		StatusCode:        d.StatusCode,
+		Error:             d.Error,
+		Retry:             d.Retry,
+		DurationMs:        d.Duration.Milliseconds(),
+		ResentFrom:        d.ResentFrom,
+	}
+}
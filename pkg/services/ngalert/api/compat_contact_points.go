@@ -221,6 +221,28 @@ func ContactPointToContactPointExport(cp definitions.ContactPoint) (notify.APIRe
 	return contactPoint, nil
 }
 
+// ContactPointExportFromContactPoint converts the strongly typed definitions.ContactPoint to the file export of the contact point
+// where settings are represented in JSON. The integrations are ordered by type.
+func ContactPointExportFromContactPoint(cp definitions.ContactPoint) (definitions.ContactPointExport, error) {
+	recv, err := ContactPointToContactPointExport(cp)
+	if err != nil {
+		return definitions.ContactPointExport{}, err
+	}
+	result := definitions.ContactPointExport{
+		Name:      cp.Name,
+		Receivers: make([]definitions.ReceiverExport, 0, len(recv.Integrations)),
+	}
+	for _, integration := range recv.Integrations {
+		result.Receivers = append(result.Receivers, definitions.ReceiverExport{
+			UID:                   integration.UID,
+			Type:                  integration.Type,
+			Settings:              definitions.RawMessage(integration.Settings),
+			DisableResolveMessage: integration.DisableResolveMessage,
+		})
+	}
+	return result, nil
+}
+
 // marshallIntegration converts the API model integration to the storage model that contains settings in the JSON format.
 // The secret fields are not encrypted.
 func marshallIntegration(json jsoniter.API, integrationType string, integration interface{}, disableResolveMessage *bool) (*notify.GrafanaIntegrationConfig, error) {
@@ -36,3 +36,11 @@ func (f *ConfigurationApiHandler) handleRouteDeleteNGalertConfig(c *contextmodel
 func (f *ConfigurationApiHandler) handleRouteGetStatus(c *contextmodel.ReqContext) response.Response {
 	return f.grafana.RouteGetAlertingStatus(c)
 }
+
+func (f *ConfigurationApiHandler) handleRouteGetAlertStateSnapshot(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetAlertStateSnapshot(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostAlertStateSnapshot(c *contextmodel.ReqContext, body apimodels.AlertStateSnapshot) response.Response {
+	return f.grafana.RoutePostAlertStateSnapshot(c, body)
+}
@@ -14,17 +14,24 @@ import (
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/middleware/requestmeta"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 type NotificationsApi interface {
+	RouteGetNotificationDeliveries(*contextmodel.ReqContext) response.Response
 	RouteGetReceiver(*contextmodel.ReqContext) response.Response
 	RouteGetReceivers(*contextmodel.ReqContext) response.Response
 	RouteNotificationsGetTimeInterval(*contextmodel.ReqContext) response.Response
 	RouteNotificationsGetTimeIntervals(*contextmodel.ReqContext) response.Response
+	RoutePostNotificationDeliveryResend(*contextmodel.ReqContext) response.Response
+	RoutePostNotificationPolicySimulation(*contextmodel.ReqContext) response.Response
 }
 
+func (f *NotificationsApiHandler) RouteGetNotificationDeliveries(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetNotificationDeliveries(ctx)
+}
 func (f *NotificationsApiHandler) RouteGetReceiver(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -41,9 +48,35 @@ func (f *NotificationsApiHandler) RouteNotificationsGetTimeInterval(ctx *context
 func (f *NotificationsApiHandler) RouteNotificationsGetTimeIntervals(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteNotificationsGetTimeIntervals(ctx)
 }
+func (f *NotificationsApiHandler) RoutePostNotificationDeliveryResend(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	idParam := web.Params(ctx.Req)[":ID"]
+	return f.handleRoutePostNotificationDeliveryResend(ctx, idParam)
+}
+
+func (f *NotificationsApiHandler) RoutePostNotificationPolicySimulation(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.NotificationPolicySimulationRequest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostNotificationPolicySimulation(ctx, conf)
+}
 
 func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Get(
+			toMacaronPath("/api/v1/notifications/deliveries"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/notifications/deliveries"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/notifications/deliveries",
+				api.Hooks.Wrap(srv.RouteGetNotificationDeliveries),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/notifications/receivers/{Name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -92,5 +125,29 @@ func (api *API) RegisterNotificationsApiEndpoints(srv NotificationsApi, m *metri
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/notifications/deliveries/{ID}/resend"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/notifications/deliveries/{ID}/resend"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/notifications/deliveries/{ID}/resend",
+				api.Hooks.Wrap(srv.RoutePostNotificationDeliveryResend),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/notifications/policies/simulate"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/notifications/policies/simulate"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/notifications/policies/simulate",
+				api.Hooks.Wrap(srv.RoutePostNotificationPolicySimulation),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
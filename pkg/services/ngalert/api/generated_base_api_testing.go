@@ -24,6 +24,7 @@ type TestingApi interface {
 	RouteEvalQueries(*contextmodel.ReqContext) response.Response
 	RouteTestRuleConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
+	RouteUnitTestRules(*contextmodel.ReqContext) response.Response
 }
 
 func (f *TestingApiHandler) BacktestConfig(ctx *contextmodel.ReqContext) response.Response {
@@ -61,6 +62,15 @@ func (f *TestingApiHandler) RouteTestRuleGrafanaConfig(ctx *contextmodel.ReqCont
 	return f.handleRouteTestRuleGrafanaConfig(ctx, conf)
 }
 
+func (f *TestingApiHandler) RouteUnitTestRules(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RuleUnitTestFile{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRouteUnitTestRules(ctx, conf)
+}
+
 func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
 		group.Post(
@@ -111,5 +121,17 @@ func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/rule/unit-test"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/rule/unit-test"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/unit-test",
+				api.Hooks.Wrap(srv.RouteUnitTestRules),
+				m,
+			),
+		)
 	}, middleware.ReqSignedIn)
 }
@@ -2,8 +2,12 @@ package hcl
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
@@ -28,3 +32,114 @@ func Encode(resources ...Resource) (data []byte, err error) {
 	}
 	return f.Bytes(), nil
 }
+
+// Decode parses resource blocks produced by Encode. The body of every resource is decoded into the pointer to a struct
+// returned by newBody for the type of the resource. Unlike gohcl.DecodeBody, all attributes are optional because Encode
+// omits attributes with nil values.
+func Decode(data []byte, filename string, newBody func(resourceType string) (interface{}, error)) ([]Resource, error) {
+	file, diags := hclsyntax.ParseConfig(data, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type of HCL body %T", file.Body)
+	}
+	for _, attr := range body.Attributes {
+		return nil, fmt.Errorf("%s: unexpected argument %q, only resource blocks are supported", attr.SrcRange, attr.Name)
+	}
+
+	resources := make([]Resource, 0, len(body.Blocks))
+	for _, blk := range body.Blocks {
+		if blk.Type != "resource" || len(blk.Labels) != 2 {
+			return nil, fmt.Errorf("%s: unexpected block %q, only resource blocks with a type and a name are supported", blk.DefRange(), blk.Type)
+		}
+		target, err := newBody(blk.Labels[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", blk.DefRange(), err)
+		}
+		val := reflect.ValueOf(target)
+		if val.Kind() != reflect.Pointer || val.Elem().Kind() != reflect.Struct {
+			return nil, fmt.Errorf("body of resource %q must be a pointer to a struct, got %T", blk.Labels[0], target)
+		}
+		if err := decodeBody(blk.Body, val.Elem()); err != nil {
+			return nil, err
+		}
+		resources = append(resources, Resource{
+			Type: blk.Labels[0],
+			Name: blk.Labels[1],
+			Body: target,
+		})
+	}
+	return resources, nil
+}
+
+// decodeBody decodes the attributes and nested blocks of body into the struct val by using the hcl tags of its fields.
+func decodeBody(body *hclsyntax.Body, val reflect.Value) error {
+	attributes := make(map[string]int)
+	blocks := make(map[string]int)
+	for i := 0; i < val.NumField(); i++ {
+		tag, ok := val.Type().Field(i).Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+		name, kind, _ := strings.Cut(tag, ",")
+		switch kind {
+		case "block":
+			blocks[name] = i
+		case "label", "remain":
+		default:
+			attributes[name] = i
+		}
+	}
+
+	for name, attr := range body.Attributes {
+		idx, ok := attributes[name]
+		if !ok {
+			return fmt.Errorf("%s: unsupported argument %q", attr.SrcRange, name)
+		}
+		if diags := gohcl.DecodeExpression(attr.Expr, nil, val.Field(idx).Addr().Interface()); diags.HasErrors() {
+			return diags
+		}
+	}
+
+	for _, blk := range body.Blocks {
+		idx, ok := blocks[blk.Type]
+		if !ok {
+			return fmt.Errorf("%s: unsupported block %q", blk.DefRange(), blk.Type)
+		}
+		field := val.Field(idx)
+		switch field.Kind() {
+		case reflect.Slice:
+			elemType := field.Type().Elem()
+			var elem reflect.Value
+			if elemType.Kind() == reflect.Pointer {
+				elem = reflect.New(elemType.Elem())
+				if err := decodeBody(blk.Body, elem.Elem()); err != nil {
+					return err
+				}
+			} else {
+				elem = reflect.New(elemType).Elem()
+				if err := decodeBody(blk.Body, elem); err != nil {
+					return err
+				}
+			}
+			field.Set(reflect.Append(field, elem))
+		case reflect.Pointer:
+			if !field.IsNil() {
+				return fmt.Errorf("%s: duplicate block %q", blk.DefRange(), blk.Type)
+			}
+			field.Set(reflect.New(field.Type().Elem()))
+			if err := decodeBody(blk.Body, field.Elem()); err != nil {
+				return err
+			}
+		case reflect.Struct:
+			if err := decodeBody(blk.Body, field); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("field for block %q has unsupported type %s", blk.Type, field.Type())
+		}
+	}
+	return nil
+}
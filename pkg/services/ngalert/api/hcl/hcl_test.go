@@ -1,6 +1,7 @@
 package hcl
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -73,3 +74,61 @@ func TestEncode(t *testing.T) {
 }
 `, string(encoded))
 }
+
+func TestDecode(t *testing.T) {
+	type data struct {
+		Name      string             `hcl:"name"`
+		Number    float64            `hcl:"number"`
+		NumberRef *float64           `hcl:"numberRef"`
+		BoolRef   *bool              `hcl:"bulRef"`
+		Labels    *map[string]string `hcl:"labels"`
+		Ignored   string
+		Blocks    []data `hcl:"blocks,block"`
+		SubData   *data  `hcl:"sub,block"`
+	}
+	newBody := func(resourceType string) (interface{}, error) {
+		if resourceType != "grafana_test" {
+			return nil, fmt.Errorf("unsupported resource type %q", resourceType)
+		}
+		return &data{}, nil
+	}
+
+	t.Run("decodes the output of Encode", func(t *testing.T) {
+		expected := &data{
+			Name:      "test",
+			Number:    123,
+			NumberRef: func(f float64) *float64 { return &f }(1333),
+			Labels:    &map[string]string{"team": "a"},
+			Blocks: []data{
+				{Name: "el-0", Number: 1},
+				{Name: "el-1", BoolRef: func(f bool) *bool { return &f }(true)},
+			},
+			SubData: &data{Name: "sub-data"},
+		}
+		encoded, err := Encode(Resource{Type: "grafana_test", Name: "test-01", Body: expected})
+		require.NoError(t, err)
+
+		resources, err := Decode(encoded, "test.tf", newBody)
+		require.NoError(t, err)
+		require.Equal(t, []Resource{{Type: "grafana_test", Name: "test-01", Body: expected}}, resources)
+	})
+
+	t.Run("fails on invalid documents", func(t *testing.T) {
+		testCases := map[string]string{
+			"syntax error":           `resource "grafana_test" "a" {`,
+			"top-level argument":     `name = "test"`,
+			"unknown resource type":  `resource "grafana_unknown" "a" {}`,
+			"missing resource name":  `resource "grafana_test" {}`,
+			"unsupported argument":   `resource "grafana_test" "a" { unknown = 1 }`,
+			"unsupported block":      "resource \"grafana_test\" \"a\" {\n  unknown {}\n}",
+			"duplicate block":        "resource \"grafana_test\" \"a\" {\n  sub {}\n  sub {}\n}",
+			"wrong type of argument": `resource "grafana_test" "a" { number = "abc" }`,
+		}
+		for name, doc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := Decode([]byte(doc), "test.tf", newBody)
+				require.Error(t, err)
+			})
+		}
+	})
+}
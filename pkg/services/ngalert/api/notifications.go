@@ -3,6 +3,7 @@ package api
 import (
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 )
 
 type NotificationsApiHandler struct {
@@ -30,3 +31,15 @@ func (f *NotificationsApiHandler) handleRouteGetReceiver(ctx *contextmodel.ReqCo
 func (f *NotificationsApiHandler) handleRouteGetReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.notificationSrv.RouteGetReceivers(ctx)
 }
+
+func (f *NotificationsApiHandler) handleRouteGetNotificationDeliveries(ctx *contextmodel.ReqContext) response.Response {
+	return f.notificationSrv.RouteGetNotificationDeliveries(ctx)
+}
+
+func (f *NotificationsApiHandler) handleRoutePostNotificationDeliveryResend(ctx *contextmodel.ReqContext, id string) response.Response {
+	return f.notificationSrv.RoutePostNotificationDeliveryResend(ctx, id)
+}
+
+func (f *NotificationsApiHandler) handleRoutePostNotificationPolicySimulation(ctx *contextmodel.ReqContext, body apimodels.NotificationPolicySimulationRequest) response.Response {
+	return f.notificationSrv.RoutePostNotificationPolicySimulation(ctx, body)
+}
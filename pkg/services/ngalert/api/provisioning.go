@@ -1,6 +1,9 @@
 package api
 
 import (
+	"io"
+	"net/http"
+
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -139,3 +142,36 @@ func (f *ProvisioningApiHandler) handleRouteExportMuteTimings(ctx *contextmodel.
 func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleGroup(ctx *contextmodel.ReqContext, folderUID, group string) response.Response {
 	return f.svc.RouteDeleteAlertRuleGroup(ctx, folderUID, group)
 }
+
+func (f *ProvisioningApiHandler) handleRouteGetSilenceTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetSilenceTemplates(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetSilenceTemplate(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetSilenceTemplate(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostSilenceTemplate(ctx *contextmodel.ReqContext, st apimodels.SilenceTemplate) response.Response {
+	return f.svc.RoutePostSilenceTemplate(ctx, st)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutSilenceTemplate(ctx *contextmodel.ReqContext, st apimodels.SilenceTemplate, uid string) response.Response {
+	return f.svc.RoutePutSilenceTemplate(ctx, st, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteSilenceTemplate(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteSilenceTemplate(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostSilenceTemplateApply(ctx *contextmodel.ReqContext, body apimodels.SilenceTemplateApply, uid string) response.Response {
+	return f.svc.RoutePostSilenceTemplateApply(ctx, body, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostProvisioningImport(ctx *contextmodel.ReqContext) response.Response {
+	body, err := io.ReadAll(ctx.Req.Body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to read the request body")
+	}
+	defer func() { _ = ctx.Req.Body.Close() }()
+	return f.svc.RoutePostProvisioningImport(ctx, body)
+}
@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
@@ -58,6 +60,35 @@ import (
 //       200: Ack
 //       500: Failure
 
+// swagger:route GET /v1/ngalert/state/export configuration RouteGetAlertStateSnapshot
+//
+// Exports the state of all alert instances of the user's organization.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertStateSnapshot
+//       500: Failure
+
+// swagger:route POST /v1/ngalert/state/import configuration RoutePostAlertStateSnapshot
+//
+// Imports the state of alert instances into the user's organization.
+//
+// The state of every rule of the snapshot is replaced with the imported state. Instances of rules that do not exist in
+// the organization are skipped.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: AlertStateSnapshotImportResult
+//       400: ValidationError
+//       500: Failure
+
 // swagger:parameters RoutePostNGalertConfig
 type NGalertConfig struct {
 	// in:body
@@ -95,3 +126,44 @@ type AlertingStatus struct {
 	AlertmanagersChoice      AlertmanagersChoice `json:"alertmanagersChoice"`
 	NumExternalAlertmanagers int                 `json:"numExternalAlertmanagers"`
 }
+
+// swagger:parameters RoutePostAlertStateSnapshot
+type AlertStateSnapshotParams struct {
+	// in:body
+	Body AlertStateSnapshot
+}
+
+// swagger:model
+type AlertStateSnapshot struct {
+	Version    int                     `json:"version"`
+	OrgID      int64                   `json:"orgId"`
+	ExportedAt time.Time               `json:"exportedAt"`
+	Instances  []AlertInstanceSnapshot `json:"instances"`
+}
+
+// swagger:model
+type AlertInstanceSnapshot struct {
+	// required: true
+	RuleUID string            `json:"ruleUid"`
+	Labels  map[string]string `json:"labels"`
+	// required: true
+	// enum: Alerting,Normal,Pending,NoData,Error
+	State             string     `json:"state"`
+	StateReason       string     `json:"stateReason,omitempty"`
+	StartsAt          time.Time  `json:"startsAt"`
+	EndsAt            time.Time  `json:"endsAt"`
+	LastEvaluatedAt   time.Time  `json:"lastEvaluatedAt"`
+	LastSentAt        *time.Time `json:"lastSentAt,omitempty"`
+	ResolvedAt        *time.Time `json:"resolvedAt,omitempty"`
+	ResultFingerprint string     `json:"resultFingerprint,omitempty"`
+	// Annotations of the alert instance. They are exported from the state of a running Grafana only.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// swagger:model
+type AlertStateSnapshotImportResult struct {
+	// Number of imported alert instances.
+	Imported int `json:"imported"`
+	// UIDs of the rules that do not exist in the organization. Their instances were not imported.
+	MissingRules []string `json:"missingRules,omitempty"`
+}
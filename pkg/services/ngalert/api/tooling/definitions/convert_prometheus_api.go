@@ -9,7 +9,7 @@ import (
 // Gets all namespaces with their rule groups in Prometheus format.
 //
 //     Produces:
-//     - application/json
+//     - application/yaml
 //
 //     Responses:
 //       200: PrometheusNamespace
@@ -21,7 +21,7 @@ import (
 // Gets rules in prometheus format for a given namespace.
 //
 //     Produces:
-//     - application/json
+//     - application/yaml
 //
 //     Responses:
 //       200: PrometheusNamespace
@@ -33,7 +33,7 @@ import (
 // Gets a rule group in Prometheus format.
 //
 //     Produces:
-//     - application/json
+//     - application/yaml
 //
 //     Responses:
 //       200: PrometheusRuleGroup
@@ -53,6 +53,7 @@ import (
 //     Responses:
 //       202: ConvertPrometheusResponse
 //       403: ForbiddenError
+//       409: PublicError
 //
 //     Extensions:
 //       x-raw-request: true
@@ -535,6 +535,18 @@ type Record struct {
 	From string `json:"from" yaml:"from"`
 }
 
+// RuleDependency declares a rule of the same rule group that is evaluated before the rule, in the same tick.
+// swagger:model
+type RuleDependency struct {
+	// UID of the rule of the same rule group that is evaluated first.
+	// required: true
+	// example: recording-rule-uid
+	RuleUID string `json:"rule_uid" yaml:"rule_uid"`
+	// Query of the dependent rule that is answered with the output of the recording rule, instead of querying the data source.
+	// example: A
+	RefID string `json:"ref_id,omitempty" yaml:"ref_id,omitempty"`
+}
+
 // swagger:model
 type PostableGrafanaRule struct {
 	Title                string                         `json:"title" yaml:"title"`
@@ -547,6 +559,7 @@ type PostableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
 	Record               *Record                        `json:"record" yaml:"record"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Dependencies         *[]RuleDependency              `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 }
 
 // swagger:model
@@ -568,6 +581,7 @@ type GettableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
 	Record               *Record                        `json:"record,omitempty" yaml:"record,omitempty"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Dependencies         []RuleDependency               `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 }
 
 // UserInfo represents user-related information, including a unique identifier and a name.
@@ -0,0 +1,107 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/notifications/deliveries notifications RouteGetNotificationDeliveries
+//
+// Get the notification delivery log.
+//
+// Get the notification attempts of contact point integrations, from the most recent. Requires the notification
+// delivery log to be enabled.
+//
+//     Responses:
+//       200: NotificationDeliveries
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:route POST /v1/notifications/deliveries/{ID}/resend notifications RoutePostNotificationDeliveryResend
+//
+// Resend a notification.
+//
+// Send the alerts of a notification attempt again with the current configuration of the integration. The new
+// attempt is added to the notification delivery log.
+//
+//     Responses:
+//       200: NotificationDelivery
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:parameters RouteGetNotificationDeliveries
+type GetNotificationDeliveriesParams struct {
+	// Name of the contact point.
+	// in:query
+	// required: false
+	Receiver string `json:"receiver"`
+	// UID of the integration of the contact point.
+	// in:query
+	// required: false
+	IntegrationUID string `json:"integration_uid"`
+	// Fingerprint of an alert of the notification.
+	// in:query
+	// required: false
+	Fingerprint string `json:"fingerprint"`
+	// Start of the time range, in RFC3339 format.
+	// in:query
+	// required: false
+	From time.Time `json:"from"`
+	// End of the time range, in RFC3339 format.
+	// in:query
+	// required: false
+	To time.Time `json:"to"`
+	// Return only the failed attempts.
+	// in:query
+	// required: false
+	Failed bool `json:"failed"`
+	// Maximum number of attempts to return. Defaults to 100.
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters RoutePostNotificationDeliveryResend
+type NotificationDeliveryIDParam struct {
+	// in:path
+	// required: true
+	ID int64 `json:"ID"`
+}
+
+// swagger:model
+type NotificationDeliveries []NotificationDelivery
+
+// NotificationDelivery is an attempt of a contact point integration to send a notification.
+// swagger:model
+type NotificationDelivery struct {
+	ID                int64                       `json:"id"`
+	Timestamp         time.Time                   `json:"timestamp"`
+	Receiver          string                      `json:"receiver"`
+	IntegrationUID    string                      `json:"integrationUid"`
+	IntegrationType   string                      `json:"integrationType"`
+	IntegrationIndex  int                         `json:"integrationIndex"`
+	GroupKey          string                      `json:"groupKey"`
+	GroupLabels       map[string]string           `json:"groupLabels"`
+	Alerts            []NotificationDeliveryAlert `json:"alerts"`
+	AlertFingerprints []string                    `json:"alertFingerprints"`
+	// SHA-256 digest of the payload, if the integration sends it with the webhook or email sender of Grafana.
+	PayloadDigest string `json:"payloadDigest,omitempty"`
+	// HTTP status code of the response, if the integration sends the payload with the webhook sender of Grafana.
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Number of previous attempts to send the notification.
+	Retry      int   `json:"retry"`
+	DurationMs int64 `json:"durationMs"`
+	// ID of the attempt that was resent by this attempt.
+	ResentFrom int64 `json:"resentFrom,omitempty"`
+}
+
+// NotificationDeliveryAlert is an alert of a notification.
+type NotificationDeliveryAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
@@ -0,0 +1,100 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /v1/notifications/policies/simulate notifications RoutePostNotificationPolicySimulation
+//
+// Simulate the routing of alerts through the notification policy tree.
+//
+// Routes alerts with the given label sets, or the current alert instances of an alert rule, through the notification
+// policy tree and returns the matched policies with their inherited settings, the time intervals and silences that
+// apply to the alerts, and the contact points that would be notified. If a notification policy tree is provided, it
+// is used instead of the saved one, so that changes can be reviewed before they are saved.
+//
+//     Responses:
+//       200: NotificationPolicySimulationResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+
+// swagger:parameters RoutePostNotificationPolicySimulation
+type NotificationPolicySimulationParams struct {
+	// in:body
+	Body NotificationPolicySimulationRequest
+}
+
+// swagger:model
+type NotificationPolicySimulationRequest struct {
+	// Label sets of the alerts to route.
+	Alerts []model.LabelSet `json:"alerts,omitempty"`
+	// UID of an alert rule whose current alert instances are routed.
+	RuleUID string `json:"ruleUid,omitempty"`
+	// Notification policy tree to use instead of the saved one.
+	Route *Route `json:"route,omitempty"`
+	// Time at which the time intervals and silences are evaluated. Defaults to the current time.
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// swagger:model
+type NotificationPolicySimulationResult struct {
+	Time   time.Time                           `json:"time"`
+	Alerts []NotificationPolicySimulationAlert `json:"alerts"`
+}
+
+// NotificationPolicySimulationAlert is the routing trace of an alert.
+type NotificationPolicySimulationAlert struct {
+	Labels model.LabelSet `json:"labels"`
+	// Policies that route the alert. There is more than one if a matching policy has continue set.
+	Routes []SimulatedRoute `json:"routes"`
+	// Active silences that match the alert. Only the silences that the user can read are considered.
+	Silences []SimulatedSilence `json:"silences,omitempty"`
+	// Silenced is true if an active silence matches the alert.
+	Silenced bool `json:"silenced"`
+	// Contact points that would be notified about the alert.
+	Receivers []string `json:"receivers"`
+}
+
+// SimulatedRoute is a policy that routes an alert, with the settings that it inherits from its parents.
+type SimulatedRoute struct {
+	// Path of the matched policies from the root of the tree to the policy.
+	Path           []SimulatedRoutePolicy `json:"path"`
+	Receiver       string                 `json:"receiver"`
+	GroupBy        []string               `json:"groupBy"`
+	GroupWait      model.Duration         `json:"groupWait"`
+	GroupInterval  model.Duration         `json:"groupInterval"`
+	RepeatInterval model.Duration         `json:"repeatInterval"`
+	// Mute time intervals of the policy. Active is true if the interval mutes the policy.
+	MuteTimeIntervals []SimulatedTimeInterval `json:"muteTimeIntervals,omitempty"`
+	// Active time intervals of the policy. Active is true if the policy is active in the interval.
+	ActiveTimeIntervals []SimulatedTimeInterval `json:"activeTimeIntervals,omitempty"`
+	// Muted is true if the time intervals of the policy mute its notifications.
+	Muted bool `json:"muted"`
+}
+
+// SimulatedRoutePolicy is a matched policy in the path of a SimulatedRoute.
+type SimulatedRoutePolicy struct {
+	// Position of the policy among the child policies of its parent. It is not set for the root policy.
+	Index *int `json:"index,omitempty"`
+	// Matchers of the policy.
+	Matchers []string `json:"matchers,omitempty"`
+	// Receiver of the policy, if it is set by the policy itself.
+	Receiver string `json:"receiver,omitempty"`
+	Continue bool   `json:"continue,omitempty"`
+}
+
+type SimulatedTimeInterval struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+type SimulatedSilence struct {
+	ID        string    `json:"id"`
+	Matchers  []string  `json:"matchers"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	EndsAt    time.Time `json:"endsAt"`
+}
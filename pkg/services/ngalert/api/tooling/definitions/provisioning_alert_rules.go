@@ -170,6 +170,8 @@ type ProvisionedAlertRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings"`
 	//example: {"metric":"grafana_alerts_ratio", "from":"A"}
 	Record *Record `json:"record"`
+	// example: [{"rule_uid":"recording-rule-uid","ref_id":"A"}]
+	Dependencies []RuleDependency `json:"dependencies,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -277,6 +279,7 @@ type AlertRuleExport struct {
 	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record               *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
+	Dependencies         []AlertRuleDependencyExport          `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -311,3 +314,9 @@ type AlertRuleRecordExport struct {
 	Metric string `json:"metric" yaml:"metric" hcl:"metric"`
 	From   string `json:"from" yaml:"from" hcl:"from"`
 }
+
+// AlertRuleDependencyExport is the provisioned export of models.RuleDependency.
+type AlertRuleDependencyExport struct {
+	RuleUID string `json:"ruleUid" yaml:"ruleUid"`
+	RefID   string `json:"refId,omitempty" yaml:"refId,omitempty"`
+}
@@ -0,0 +1,68 @@
+package definitions
+
+// swagger:route POST /v1/provisioning/import provisioning stable RoutePostProvisioningImport
+//
+// Import alerting resources from a file export.
+//
+// Creates or updates the alert rule groups, contact points, notification policy tree and mute timings of a file in
+// the YAML, JSON or HCL format of the export endpoints. Resources that are not in the file are not changed. A rule
+// group or a contact point in the file replaces all rules of the group or integrations of the contact point.
+//
+//     Consumes:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//     - application/terraform+hcl
+//     - text/hcl
+//
+//     Responses:
+//       200: ProvisioningImportResult
+//       400: ValidationError
+//       404: NotFound
+//
+//     Extensions:
+//       x-raw-request: true
+
+// swagger:parameters RoutePostProvisioningImport
+type ProvisioningImportParams struct {
+	// Format of the file. If it is not set, the format is determined from the Content-Type header and defaults to yaml.
+	// in: query
+	// required: false
+	// enum: yaml,json,hcl
+	Format string `json:"format"`
+	// Only calculate the changes of the import without saving them.
+	// in: query
+	// required: false
+	// default: false
+	DryRun bool `json:"dry_run"`
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+	// in:body
+	Body AlertingFileExport
+}
+
+// ProvisioningImportResult contains the changes of the resources of an import.
+// swagger:model
+type ProvisioningImportResult struct {
+	DryRun  bool                       `json:"dryRun"`
+	Changes []ProvisioningImportChange `json:"changes"`
+}
+
+type ProvisioningImportChange struct {
+	// enum: rule_group,contact_point,notification_policy,mute_timing
+	Resource string `json:"resource"`
+	// Name of the resource. Not set for the notification policy tree.
+	Name string `json:"name,omitempty"`
+	// UID of the folder of the rule group.
+	FolderUID string `json:"folderUid,omitempty"`
+	// enum: create,update,unchanged
+	Action string                   `json:"action"`
+	Diff   []ProvisioningImportDiff `json:"diff,omitempty"`
+}
+
+type ProvisioningImportDiff struct {
+	// Path of the changed field of the resource.
+	Path string `json:"path"`
+	// enum: add,remove,change
+	Op string `json:"op"`
+}
@@ -0,0 +1,121 @@
+package definitions
+
+import (
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /v1/provisioning/silence-templates provisioning stable RouteGetSilenceTemplates
+//
+// Get all the silence templates.
+//
+//     Responses:
+//       200: SilenceTemplates
+
+// swagger:route GET /v1/provisioning/silence-templates/{UID} provisioning stable RouteGetSilenceTemplate
+//
+// Get a silence template.
+//
+//     Responses:
+//       200: SilenceTemplate
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/silence-templates provisioning stable RoutePostSilenceTemplate
+//
+// Create a new silence template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: SilenceTemplate
+//       400: ValidationError
+
+// swagger:route PUT /v1/provisioning/silence-templates/{UID} provisioning stable RoutePutSilenceTemplate
+//
+// Replace an existing silence template. Silences that were already created from the template are not changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: SilenceTemplate
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route DELETE /v1/provisioning/silence-templates/{UID} provisioning stable RouteDeleteSilenceTemplate
+//
+// Delete a silence template. Silences that were already created from the template are not expired.
+//
+//     Responses:
+//       204: description: The silence template was deleted successfully.
+
+// swagger:route POST /v1/provisioning/silence-templates/{UID}/apply provisioning stable RoutePostSilenceTemplateApply
+//
+// Create a silence from a silence template that starts now.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: postSilencesOKBody
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:model
+type SilenceTemplates []SilenceTemplate
+
+// swagger:parameters RouteGetSilenceTemplate RoutePutSilenceTemplate RouteDeleteSilenceTemplate RoutePostSilenceTemplateApply
+type SilenceTemplateUIDParam struct {
+	// Silence template UID
+	// in:path
+	UID string `json:"UID"`
+}
+
+// swagger:parameters RoutePostSilenceTemplate RoutePutSilenceTemplate
+type SilenceTemplatePayload struct {
+	// in:body
+	Body SilenceTemplate
+}
+
+// swagger:parameters RoutePostSilenceTemplate RoutePutSilenceTemplate RouteDeleteSilenceTemplate
+type SilenceTemplateHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:parameters RoutePostSilenceTemplateApply
+type SilenceTemplateApplyPayload struct {
+	// in:body
+	Body SilenceTemplateApply
+}
+
+// SilenceTemplate is a saved set of silence matchers. Silences are created from the template on demand, or
+// automatically for every window of its schedule.
+// swagger:model
+type SilenceTemplate struct {
+	// example: weekly-maintenance
+	UID string `json:"uid"`
+	// required: true
+	// example: Weekly maintenance
+	Title string `json:"title"`
+	// required: true
+	Matchers amv2.Matchers `json:"matchers"`
+	// example: Database maintenance
+	Comment string `json:"comment,omitempty"`
+	// Duration of the silences created from the template on demand. Required if the template has no schedule.
+	// example: 2h
+	Duration model.Duration `json:"duration,omitempty"`
+	// Time intervals, in the same format as the time intervals of mute timings. A silence is created for every
+	// window in which the time matches one of the time intervals.
+	Schedule   []timeinterval.TimeInterval `json:"schedule,omitempty"`
+	Provenance Provenance                  `json:"provenance,omitempty"`
+}
+
+// swagger:model
+type SilenceTemplateApply struct {
+	// Duration of the silence. Defaults to the duration of the silence template.
+	// example: 1h
+	Duration model.Duration `json:"duration,omitempty"`
+}
@@ -0,0 +1,102 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route Post /v1/rule/unit-test testing RouteUnitTestRules
+//
+// Run unit tests of Grafana-managed alert rules. The data source queries of the rules are answered with the input
+// series of the tests instead of querying the data sources.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleUnitTestResults
+//       400: ValidationError
+
+// swagger:parameters RouteUnitTestRules
+type RuleUnitTestRequest struct {
+	// in:body
+	Body RuleUnitTestFile
+}
+
+// RuleUnitTestFile describes the alert rules under test and the tests to run against them.
+// swagger:model
+type RuleUnitTestFile struct {
+	// Paths of alerting provisioning files with the rule groups under test, relative to the test file. Only
+	// supported by the CLI.
+	RuleFiles []string `json:"rule_files,omitempty" yaml:"rule_files,omitempty"`
+	// Rule groups under test, in the format of the alerting provisioning files.
+	Groups []AlertRuleGroupExport `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// Interval between evaluations of the rules. Defaults to the interval of the rule group.
+	// example: 1m
+	EvaluationInterval model.Duration `json:"evaluation_interval,omitempty" yaml:"evaluation_interval,omitempty"`
+	// required: true
+	Tests []RuleUnitTest `json:"tests" yaml:"tests"`
+}
+
+// RuleUnitTest is a set of input series and the alerts expected at given evaluation times.
+type RuleUnitTest struct {
+	// example: fires when CPU usage is high
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Interval between the values of the input series. Defaults to the evaluation interval.
+	// example: 1m
+	Interval    model.Duration       `json:"interval,omitempty" yaml:"interval,omitempty"`
+	InputSeries []RuleUnitTestSeries `json:"input_series" yaml:"input_series"`
+	AlertTests  []RuleUnitTestCase   `json:"alert_rule_test" yaml:"alert_rule_test"`
+}
+
+// RuleUnitTestSeries is a series returned by the data source query with the given RefID.
+type RuleUnitTestSeries struct {
+	// required: true
+	// example: A
+	RefID  string            `json:"ref_id" yaml:"ref_id"`
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Values of the series in expanding notation, one value per interval. 'a+bxn' expands to n+1 values starting
+	// at a and incremented by b, 'axn' repeats a n+1 times, '_' is a missing value and '_xn' are n missing values.
+	// example: 10+10x5 _ 60
+	Values string `json:"values" yaml:"values"`
+}
+
+// RuleUnitTestCase describes the alerts of a rule expected at an evaluation time.
+type RuleUnitTestCase struct {
+	// Time since the start of the test.
+	// example: 5m
+	EvalTime model.Duration `json:"eval_time" yaml:"eval_time"`
+	// UID of the rule under test. Either the UID or the title of the rule must be specified.
+	RuleUID string `json:"rule_uid,omitempty" yaml:"rule_uid,omitempty"`
+	// Title of the rule under test.
+	RuleTitle string `json:"rule_title,omitempty" yaml:"rule_title,omitempty"`
+	// Expected alerts that are not in the Normal state. An empty list expects no such alerts.
+	ExpAlerts []RuleUnitTestAlert `json:"exp_alerts" yaml:"exp_alerts"`
+}
+
+// RuleUnitTestAlert is an expected alert.
+type RuleUnitTestAlert struct {
+	// One of Alerting, Pending, NoData or Error.
+	// example: Alerting
+	State string `json:"state" yaml:"state"`
+	// All labels of the alert, except the private labels that start with "__".
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Annotations of the alert, except the private annotations that start with "__". The annotations are not
+	// compared if they are not specified.
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// swagger:model
+type RuleUnitTestResults struct {
+	Passed bool                 `json:"passed"`
+	Tests  []RuleUnitTestResult `json:"tests"`
+}
+
+// RuleUnitTestResult is the result of a test.
+type RuleUnitTestResult struct {
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Failures []string `json:"failures,omitempty"`
+}
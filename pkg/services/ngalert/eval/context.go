@@ -2,6 +2,7 @@ package eval
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -19,6 +20,16 @@ type EvaluationContext struct {
 	Ctx                   context.Context
 	User                  identity.Requester
 	AlertingResultsReader AlertingResultsReader
+	// Limits are the limits of the evaluation of the condition. Zero values disable a limit.
+	Limits EvaluationLimits
+}
+
+// EvaluationLimits are the limits of a single evaluation of a condition.
+type EvaluationLimits struct {
+	// MaxSeries is the maximum number of series that the condition can return.
+	MaxSeries int
+	// MaxDuration is the maximum duration of the execution of the queries and expressions.
+	MaxDuration time.Duration
 }
 
 func NewContext(ctx context.Context, user identity.Requester) EvaluationContext {
@@ -26,6 +26,13 @@ import (
 
 var logger = log.New("ngalert.eval")
 
+var (
+	// ErrMaxSeriesExceeded is returned when the condition returns more series than the evaluation limits allow.
+	ErrMaxSeriesExceeded = errors.New("evaluation returned more series than the limit")
+	// ErrMaxDurationExceeded is returned when the evaluation takes longer than the evaluation limits allow.
+	ErrMaxDurationExceeded = errors.New("evaluation took longer than the limit")
+)
+
 type EvaluatorFactory interface {
 	// Create builds an evaluator pipeline ready to evaluate a rule's query
 	Create(ctx EvaluationContext, condition models.Condition) (ConditionEvaluator, error)
@@ -54,6 +61,7 @@ type conditionEvaluator struct {
 	condition         models.Condition
 	evalTimeout       time.Duration
 	evalResultLimit   int
+	limits            EvaluationLimits
 }
 
 func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (resp *backend.QueryDataResponse, err error) {
@@ -75,8 +83,25 @@ func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (re
 		defer cancel()
 		execCtx = timeoutCtx
 	}
+	if r.limits.MaxDuration > 0 {
+		limitCtx, cancel := context.WithTimeoutCause(execCtx, r.limits.MaxDuration, ErrMaxDurationExceeded)
+		defer cancel()
+		execCtx = limitCtx
+	}
 	logger.FromContext(ctx).Debug("Executing pipeline", "commands", strings.Join(r.pipeline.GetCommandTypes(), ","), "datasources", strings.Join(r.pipeline.GetDatasourceTypes(), ","))
 	result, err := r.expressionService.ExecutePipeline(execCtx, now, r.pipeline)
+	if errors.Is(context.Cause(execCtx), ErrMaxDurationExceeded) {
+		logger.FromContext(ctx).Error("Query evaluation exceeded the evaluation limits", "maxDuration", r.limits.MaxDuration)
+		return nil, fmt.Errorf("%w: %s", ErrMaxDurationExceeded, r.limits.MaxDuration)
+	}
+
+	// Check if the condition returned more series than the evaluation limits of the rule allow
+	if err == nil && result != nil && r.limits.MaxSeries > 0 {
+		if series := len(result.Responses[r.condition.Condition].Frames); series > r.limits.MaxSeries {
+			logger.FromContext(ctx).Error("Query evaluation exceeded the evaluation limits", "maxSeries", r.limits.MaxSeries, "actual", series)
+			return nil, fmt.Errorf("%w: %d (limit: %d)", ErrMaxSeriesExceeded, series, r.limits.MaxSeries)
+		}
+	}
 
 	// Check if the result of the condition evaluation is too large
 	if err == nil && result != nil && r.evalResultLimit > 0 {
@@ -192,7 +217,7 @@ func (evalResults Results) HasNonRetryableErrors() bool {
 }
 
 // IsNonRetryableError indicates whether an error is considered persistent and not worth performing evaluation retries.
-// Currently it is true if err is `&invalidEvalResultFormatError` or `ErrSeriesMustBeWide`
+// Currently it is true if err is `&invalidEvalResultFormatError`, `ErrSeriesMustBeWide` or a violation of the evaluation limits
 func IsNonRetryableError(err error) bool {
 	var nonRetryableError *invalidEvalResultFormatError
 	if errors.As(err, &nonRetryableError) {
@@ -201,6 +226,9 @@ func IsNonRetryableError(err error) bool {
 	if errors.Is(err, expr.ErrSeriesMustBeWide) {
 		return true
 	}
+	if errors.Is(err, ErrMaxSeriesExceeded) || errors.Is(err, ErrMaxDurationExceeded) {
+		return true
+	}
 	return false
 }
 
@@ -834,10 +862,10 @@ func (e *evaluatorImpl) Create(ctx EvaluationContext, condition models.Condition
 	if err != nil {
 		return nil, err
 	}
-	return e.create(condition, req)
+	return e.create(condition, req, ctx.Limits)
 }
 
-func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (ConditionEvaluator, error) {
+func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request, limits EvaluationLimits) (ConditionEvaluator, error) {
 	pipeline, err := e.expressionService.BuildPipeline(req)
 	if err != nil {
 		return nil, err
@@ -851,6 +879,7 @@ func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (C
 				condition:         condition,
 				evalTimeout:       e.evaluationTimeout,
 				evalResultLimit:   e.evaluationResultLimit,
+				limits:            limits,
 			}, nil
 		}
 		conditions = append(conditions, node.RefID())
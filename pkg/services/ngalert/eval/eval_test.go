@@ -1171,6 +1171,72 @@ func TestEvaluateRawLimit(t *testing.T) {
 	})
 }
 
+func TestEvaluateRawEvaluationLimits(t *testing.T) {
+	t.Run("should return non-retryable error if the condition returns too many series", func(t *testing.T) {
+		resp := backend.QueryDataResponse{
+			Responses: backend.Responses{
+				"A": {Frames: []*data.Frame{{RefID: "A"}, {RefID: "A"}, {RefID: "A"}}},
+			},
+		}
+		e := conditionEvaluator{
+			expressionService: &fakeExpressionService{
+				hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+					return &resp, nil
+				},
+			},
+			condition:   models.Condition{Condition: "A"},
+			evalTimeout: time.Minute,
+			limits:      EvaluationLimits{MaxSeries: 2},
+		}
+
+		_, err := e.EvaluateRaw(context.Background(), time.Now())
+		require.ErrorIs(t, err, ErrMaxSeriesExceeded)
+		require.EqualError(t, err, "evaluation returned more series than the limit: 3 (limit: 2)")
+		require.True(t, IsNonRetryableError(err))
+
+		e.limits.MaxSeries = 3
+		result, err := e.EvaluateRaw(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Equal(t, &resp, result)
+	})
+
+	t.Run("should return non-retryable error if the evaluation takes too long", func(t *testing.T) {
+		e := conditionEvaluator{
+			expressionService: &fakeExpressionService{
+				hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				},
+			},
+			condition:   models.Condition{Condition: "A"},
+			evalTimeout: time.Minute,
+			limits:      EvaluationLimits{MaxDuration: 10 * time.Millisecond},
+		}
+
+		_, err := e.EvaluateRaw(context.Background(), time.Now())
+		require.ErrorIs(t, err, ErrMaxDurationExceeded)
+		require.True(t, IsNonRetryableError(err))
+	})
+
+	t.Run("should not be applied to the evaluation timeout", func(t *testing.T) {
+		e := conditionEvaluator{
+			expressionService: &fakeExpressionService{
+				hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				},
+			},
+			condition:   models.Condition{Condition: "A"},
+			evalTimeout: 10 * time.Millisecond,
+			limits:      EvaluationLimits{MaxDuration: time.Minute},
+		}
+
+		_, err := e.EvaluateRaw(context.Background(), time.Now())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.False(t, IsNonRetryableError(err))
+	})
+}
+
 func TestResults_HasNonRetryableErrors(t *testing.T) {
 	tc := []struct {
 		name     string
@@ -32,6 +32,8 @@ type Scheduler struct {
 	Ticker                              *ticker.Metrics
 	EvaluationMissed                    *prometheus.CounterVec
 	SimplifiedEditorRules               *prometheus.GaugeVec
+	EvaluationLimitViolations           *prometheus.CounterVec
+	RulesPausedByLimits                 *prometheus.GaugeVec
 }
 
 func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
@@ -192,5 +194,23 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org", "setting"},
 		),
+		EvaluationLimitViolations: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_evaluation_limit_violations_total",
+				Help:      "The total number of times alert rules were paused because they exceeded the evaluation limits.",
+			},
+			[]string{"org", "limit"},
+		),
+		RulesPausedByLimits: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rules_paused_by_limits",
+				Help:      "The number of alert rules that are paused because they exceeded the evaluation limits.",
+			},
+			[]string{"org", "limit"},
+		),
 	}
 }
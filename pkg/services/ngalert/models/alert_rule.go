@@ -292,6 +292,8 @@ type AlertRule struct {
 	IsPaused             bool
 	NotificationSettings []NotificationSettings
 	Metadata             AlertRuleMetadata
+	// Dependencies are the rules of the same group that are evaluated before this rule, in the same tick.
+	Dependencies []RuleDependency
 }
 
 type AlertRuleMetadata struct {
@@ -327,8 +329,9 @@ type AlertRuleWithOptionals struct {
 	AlertRule
 	// This parameter is to know if an optional API field was sent and, therefore, patch it with the current field from
 	// DB in case it was not sent.
-	HasPause    bool
-	HasMetadata bool
+	HasPause        bool
+	HasMetadata     bool
+	HasDependencies bool
 }
 
 // AlertsRulesBy is a function that defines the ordering of alert rules.
@@ -762,6 +765,8 @@ func (alertRule *AlertRule) Copy() *AlertRule {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
 
+	result.Dependencies = CopyRuleDependencies(alertRule.Dependencies)
+
 	return &result
 }
 
@@ -925,6 +930,9 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if !ruleToPatch.HasMetadata {
 		ruleToPatch.Metadata = existingRule.Metadata
 	}
+	if !ruleToPatch.HasDependencies {
+		ruleToPatch.Dependencies = existingRule.Dependencies
+	}
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
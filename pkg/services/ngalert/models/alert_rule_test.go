@@ -978,6 +978,8 @@ func TestGeneratorFillsAllFields(t *testing.T) {
 		"ID":       {},
 		"IsPaused": {},
 		"Record":   {},
+		// dependencies refer to other rules of the group so they are set by tests that need them.
+		"Dependencies": {},
 	}
 
 	tpe := reflect.TypeOf(AlertRule{})
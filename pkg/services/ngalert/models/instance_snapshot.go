@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AlertStateSnapshotVersion is the version of the AlertStateSnapshot format.
+const AlertStateSnapshotVersion = 1
+
+// AlertStateSnapshot is a portable export of the alert instances of an organization. It is used to move the state of
+// alerts to another database or Grafana installation without resetting firing alerts to Normal.
+type AlertStateSnapshot struct {
+	Version    int                     `json:"version"`
+	OrgID      int64                   `json:"orgId"`
+	ExportedAt time.Time               `json:"exportedAt"`
+	Instances  []AlertInstanceSnapshot `json:"instances"`
+}
+
+// AlertInstanceSnapshot is the exported state of a single alert instance.
+type AlertInstanceSnapshot struct {
+	RuleUID           string            `json:"ruleUid"`
+	Labels            map[string]string `json:"labels"`
+	State             InstanceStateType `json:"state"`
+	StateReason       string            `json:"stateReason,omitempty"`
+	StartsAt          time.Time         `json:"startsAt"`
+	EndsAt            time.Time         `json:"endsAt"`
+	LastEvaluatedAt   time.Time         `json:"lastEvaluatedAt"`
+	LastSentAt        *time.Time        `json:"lastSentAt,omitempty"`
+	ResolvedAt        *time.Time        `json:"resolvedAt,omitempty"`
+	ResultFingerprint string            `json:"resultFingerprint,omitempty"`
+	// Annotations are exported only from the state of a running Grafana. They are not saved in the database, so an
+	// instance imported without annotations gets them on the next evaluation of the rule.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// AlertStateSnapshotImportResult describes the outcome of an import of AlertStateSnapshot.
+type AlertStateSnapshotImportResult struct {
+	// Imported is the number of imported alert instances.
+	Imported int
+	// MissingRules are the UIDs of the rules of the snapshot that do not exist. Their instances are not imported.
+	MissingRules []string
+}
+
+var ErrInvalidAlertStateSnapshot = errors.New("invalid alert state snapshot")
+
+// Validate checks that the snapshot can be imported.
+func (s AlertStateSnapshot) Validate() error {
+	if s.Version != AlertStateSnapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d, expected %d", ErrInvalidAlertStateSnapshot, s.Version, AlertStateSnapshotVersion)
+	}
+	for idx, instance := range s.Instances {
+		if instance.RuleUID == "" {
+			return fmt.Errorf("%w: instance at index %d has no rule UID", ErrInvalidAlertStateSnapshot, idx)
+		}
+		if !instance.State.IsValid() {
+			return fmt.Errorf("%w: instance at index %d has invalid state '%s'", ErrInvalidAlertStateSnapshot, idx, instance.State)
+		}
+	}
+	return nil
+}
+
+// AlertInstanceSnapshotFromInstance converts the alert instance saved in the database into its exported state.
+func AlertInstanceSnapshotFromInstance(instance AlertInstance) AlertInstanceSnapshot {
+	return AlertInstanceSnapshot{
+		RuleUID:           instance.RuleUID,
+		Labels:            instance.Labels,
+		State:             instance.CurrentState,
+		StateReason:       instance.CurrentReason,
+		StartsAt:          instance.CurrentStateSince,
+		EndsAt:            instance.CurrentStateEnd,
+		LastEvaluatedAt:   instance.LastEvalTime,
+		LastSentAt:        instance.LastSentAt,
+		ResolvedAt:        instance.ResolvedAt,
+		ResultFingerprint: instance.ResultFingerprint,
+	}
+}
+
+// ToAlertInstance converts the exported state into an alert instance of the organization.
+func (s AlertInstanceSnapshot) ToAlertInstance(orgID int64) (AlertInstance, error) {
+	labels := InstanceLabels(s.Labels)
+	_, hash, err := labels.StringAndHash()
+	if err != nil {
+		return AlertInstance{}, err
+	}
+	return AlertInstance{
+		AlertInstanceKey: AlertInstanceKey{
+			RuleOrgID:  orgID,
+			RuleUID:    s.RuleUID,
+			LabelsHash: hash,
+		},
+		Labels:            labels,
+		CurrentState:      s.State,
+		CurrentReason:     s.StateReason,
+		CurrentStateSince: s.StartsAt,
+		CurrentStateEnd:   s.EndsAt,
+		LastEvalTime:      s.LastEvaluatedAt,
+		LastSentAt:        s.LastSentAt,
+		ResolvedAt:        s.ResolvedAt,
+		ResultFingerprint: s.ResultFingerprint,
+	}, nil
+}
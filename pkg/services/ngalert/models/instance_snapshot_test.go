@@ -0,0 +1,65 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertStateSnapshotValidate(t *testing.T) {
+	valid := func() AlertStateSnapshot {
+		return AlertStateSnapshot{
+			Version: AlertStateSnapshotVersion,
+			OrgID:   1,
+			Instances: []AlertInstanceSnapshot{{
+				RuleUID: "rule",
+				Labels:  map[string]string{"instance": "a"},
+				State:   InstanceStateFiring,
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(s *AlertStateSnapshot)
+		err    bool
+	}{
+		{name: "valid snapshot", mutate: func(s *AlertStateSnapshot) {}},
+		{name: "unsupported version", mutate: func(s *AlertStateSnapshot) { s.Version = AlertStateSnapshotVersion + 1 }, err: true},
+		{name: "instance without rule UID", mutate: func(s *AlertStateSnapshot) { s.Instances[0].RuleUID = "" }, err: true},
+		{name: "instance with unknown state", mutate: func(s *AlertStateSnapshot) { s.Instances[0].State = "Unknown" }, err: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid()
+			tc.mutate(&s)
+			err := s.Validate()
+			if tc.err {
+				require.ErrorIs(t, err, ErrInvalidAlertStateSnapshot)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAlertInstanceSnapshotRoundTrip(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	instance := AlertInstanceSnapshot{
+		RuleUID:           "rule",
+		Labels:            map[string]string{"instance": "a"},
+		State:             InstanceStatePending,
+		StateReason:       "reason",
+		StartsAt:          now,
+		EndsAt:            now.Add(time.Minute),
+		LastEvaluatedAt:   now,
+		ResultFingerprint: "fingerprint",
+	}
+
+	converted, err := instance.ToAlertInstance(2)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), converted.RuleOrgID)
+	require.NotEmpty(t, converted.LabelsHash)
+	require.Equal(t, instance, AlertInstanceSnapshotFromInstance(converted))
+}
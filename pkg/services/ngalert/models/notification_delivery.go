@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+var (
+	ErrNotificationDeliveryNotFound     = errutil.NotFound("alerting.notification-deliveries.notFound", errutil.WithPublicMessage("Notification delivery not found."))
+	ErrNotificationDeliveryResendFailed = errutil.BadRequest("alerting.notification-deliveries.resendFailed").MustTemplate(
+		"Failed to resend the notification",
+		errutil.WithPublic("Failed to resend the notification: {{ .Public.Error }}"),
+	)
+)
+
+// NotificationDelivery is an attempt of a receiver integration to send a notification. Every retry of a notification
+// is a separate attempt.
+type NotificationDelivery struct {
+	ID               int64
+	OrgID            int64
+	Created          time.Time
+	Receiver         string
+	IntegrationUID   string
+	IntegrationType  string
+	IntegrationIndex int
+	GroupKey         string
+	GroupLabels      map[string]string
+	// Alerts are the alerts of the notification. They are kept to resend the notification.
+	Alerts            []NotificationDeliveryAlert
+	AlertFingerprints []string
+	// PayloadDigest is the SHA-256 digest of the payload sent by the integration, if the integration sends it with
+	// the webhook or email sender of Grafana.
+	PayloadDigest string
+	// StatusCode is the HTTP status code of the response, if the integration sends the payload with the webhook
+	// sender of Grafana.
+	StatusCode int
+	Error      string
+	// Retry is the number of previous attempts to send the notification.
+	Retry    int
+	Duration time.Duration
+	// ResentFrom is the ID of the delivery that was resent by this delivery, or 0.
+	ResentFrom int64
+}
+
+// Succeeded returns true if the integration sent the notification without an error.
+func (d NotificationDelivery) Succeeded() bool {
+	return d.Error == ""
+}
+
+// NotificationDeliveryAlert is an alert of a notification.
+type NotificationDeliveryAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// ListNotificationDeliveriesQuery filters the notification deliveries of an organization. The deliveries are returned
+// from the most recent.
+type ListNotificationDeliveriesQuery struct {
+	OrgID            int64
+	Receiver         string
+	IntegrationUID   string
+	AlertFingerprint string
+	From             time.Time
+	To               time.Time
+	OnlyFailed       bool
+	Limit            int
+}
+
+// MakeErrNotificationDeliveryResendFailed creates an error with the ErrNotificationDeliveryResendFailed template.
+func MakeErrNotificationDeliveryResendFailed(err error) error {
+	return ErrNotificationDeliveryResendFailed.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Error": err.Error(),
+		},
+		Error: err,
+	})
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+// RuleDependency declares that a rule is evaluated after another rule of the same rule group, in the same tick.
+type RuleDependency struct {
+	// RuleUID is the UID of the rule of the same group that is evaluated first.
+	RuleUID string `json:"rule_uid"`
+	// RefID is the query of the dependent rule that is answered with the output of the recording rule
+	// RuleUID, instead of querying the data source. Optional.
+	RefID string `json:"ref_id,omitempty"`
+}
+
+// CopyRuleDependencies returns a copy of the dependencies.
+func CopyRuleDependencies(dependencies []RuleDependency) []RuleDependency {
+	if dependencies == nil {
+		return nil
+	}
+	return slices.Clone(dependencies)
+}
+
+// ValidateRuleGroupDependencies validates the dependencies between the rules of a rule group.
+// Every dependency must refer to another rule of the group, only recording rules can feed a query of the dependent
+// rule, and the dependencies must not form a cycle.
+func ValidateRuleGroupDependencies(rules []*AlertRule) error {
+	byUID := make(map[string]*AlertRule, len(rules))
+	for _, rule := range rules {
+		if rule.UID != "" {
+			byUID[rule.UID] = rule
+		}
+	}
+
+	for _, rule := range rules {
+		refIDs := make(map[string]struct{}, len(rule.Dependencies))
+		for _, dep := range rule.Dependencies {
+			if dep.RuleUID == "" {
+				return fmt.Errorf("%w: rule %q has a dependency without rule UID", ErrAlertRuleFailedValidation, rule.Title)
+			}
+			if dep.RuleUID == rule.UID {
+				return fmt.Errorf("%w: rule %q cannot depend on itself", ErrAlertRuleFailedValidation, rule.Title)
+			}
+			producer, ok := byUID[dep.RuleUID]
+			if !ok {
+				return fmt.Errorf("%w: rule %q depends on rule %q that is not in the rule group", ErrAlertRuleFailedValidation, rule.Title, dep.RuleUID)
+			}
+			if dep.RefID == "" {
+				continue
+			}
+			if producer.Type() != RuleTypeRecording {
+				return fmt.Errorf("%w: rule %q can read the output of recording rules only but %q is not a recording rule", ErrAlertRuleFailedValidation, rule.Title, producer.Title)
+			}
+			if _, ok := refIDs[dep.RefID]; ok {
+				return fmt.Errorf("%w: query %s of rule %q is fed by more than one recording rule", ErrAlertRuleFailedValidation, dep.RefID, rule.Title)
+			}
+			refIDs[dep.RefID] = struct{}{}
+			idx := slices.IndexFunc(rule.Data, func(q AlertQuery) bool { return q.RefID == dep.RefID })
+			if idx < 0 {
+				return fmt.Errorf("%w: rule %q does not have query %s to read the output of recording rule %q", ErrAlertRuleFailedValidation, rule.Title, dep.RefID, producer.Title)
+			}
+			if isExpr, _ := rule.Data[idx].IsExpression(); isExpr {
+				return fmt.Errorf("%w: query %s of rule %q is an expression and cannot read the output of recording rule %q", ErrAlertRuleFailedValidation, dep.RefID, rule.Title, producer.Title)
+			}
+		}
+	}
+
+	if cycle := findDependencyCycle(rules); len(cycle) > 0 {
+		return fmt.Errorf("%w: rule dependencies form a cycle: %v", ErrAlertRuleFailedValidation, cycle)
+	}
+	return nil
+}
+
+// findDependencyCycle returns the titles of the rules that form a dependency cycle, or nil if there is no cycle.
+func findDependencyCycle(rules []*AlertRule) []string {
+	byUID := make(map[string]*AlertRule, len(rules))
+	for _, rule := range rules {
+		byUID[rule.UID] = rule
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(rules))
+	var path []string
+	var visit func(rule *AlertRule) []string
+	visit = func(rule *AlertRule) []string {
+		switch marks[rule.UID] {
+		case visiting:
+			start := slices.Index(path, rule.Title)
+			return append(slices.Clone(path[start:]), rule.Title)
+		case visited:
+			return nil
+		}
+		marks[rule.UID] = visiting
+		path = append(path, rule.Title)
+		for _, dep := range rule.Dependencies {
+			producer, ok := byUID[dep.RuleUID]
+			if !ok {
+				continue
+			}
+			if cycle := visit(producer); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		marks[rule.UID] = visited
+		return nil
+	}
+
+	for _, rule := range rules {
+		if cycle := visit(rule); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+func TestValidateRuleGroupDependencies(t *testing.T) {
+	gen := RuleGen
+	query := gen.GenerateQuery()
+	query.RefID = "A"
+	condition := gen.GenerateQuery()
+	condition.RefID = "B"
+	expression := gen.GenerateQuery()
+	expression.RefID = "C"
+	expression.DatasourceUID = expr.DatasourceUID
+
+	group := gen.With(gen.WithSameGroup())
+	recording := func(uid string, deps ...RuleDependency) *AlertRule {
+		return group.With(gen.WithUID(uid), gen.WithAllRecordingRules(), gen.WithDependencies(deps...)).GenerateRef()
+	}
+	alerting := func(uid string, deps ...RuleDependency) *AlertRule {
+		return group.With(gen.WithUID(uid), gen.WithQuery(query, condition, expression), gen.WithCondition("B"), gen.WithDependencies(deps...)).GenerateRef()
+	}
+
+	testCases := []struct {
+		name          string
+		rules         []*AlertRule
+		expectedError string
+	}{
+		{
+			name:  "no dependencies",
+			rules: []*AlertRule{recording("r1"), alerting("a1")},
+		},
+		{
+			name: "chain of rules",
+			rules: []*AlertRule{
+				recording("r1"),
+				recording("r2", RuleDependency{RuleUID: "r1"}),
+				alerting("a1", RuleDependency{RuleUID: "r2", RefID: "A"}, RuleDependency{RuleUID: "r1"}),
+			},
+		},
+		{
+			name: "ordering after alert rule",
+			rules: []*AlertRule{
+				alerting("a1"),
+				alerting("a2", RuleDependency{RuleUID: "a1"}),
+			},
+		},
+		{
+			name:          "dependency on itself",
+			rules:         []*AlertRule{alerting("a1", RuleDependency{RuleUID: "a1"})},
+			expectedError: "cannot depend on itself",
+		},
+		{
+			name:          "dependency without rule UID",
+			rules:         []*AlertRule{alerting("a1", RuleDependency{RefID: "A"})},
+			expectedError: "without rule UID",
+		},
+		{
+			name:          "dependency on rule outside of the group",
+			rules:         []*AlertRule{alerting("a1", RuleDependency{RuleUID: "missing"})},
+			expectedError: "not in the rule group",
+		},
+		{
+			name: "query fed by alert rule",
+			rules: []*AlertRule{
+				alerting("a1"),
+				alerting("a2", RuleDependency{RuleUID: "a1", RefID: "A"}),
+			},
+			expectedError: "not a recording rule",
+		},
+		{
+			name: "query fed twice",
+			rules: []*AlertRule{
+				recording("r1"),
+				recording("r2"),
+				alerting("a1", RuleDependency{RuleUID: "r1", RefID: "A"}, RuleDependency{RuleUID: "r2", RefID: "A"}),
+			},
+			expectedError: "more than one recording rule",
+		},
+		{
+			name: "unknown query",
+			rules: []*AlertRule{
+				recording("r1"),
+				alerting("a1", RuleDependency{RuleUID: "r1", RefID: "Z"}),
+			},
+			expectedError: "does not have query Z",
+		},
+		{
+			name: "expression fed by recording rule",
+			rules: []*AlertRule{
+				recording("r1"),
+				alerting("a1", RuleDependency{RuleUID: "r1", RefID: "C"}),
+			},
+			expectedError: "is an expression",
+		},
+		{
+			name: "cycle",
+			rules: []*AlertRule{
+				recording("r1", RuleDependency{RuleUID: "r3"}),
+				recording("r2", RuleDependency{RuleUID: "r1"}),
+				recording("r3", RuleDependency{RuleUID: "r2"}),
+			},
+			expectedError: "form a cycle",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRuleGroupDependencies(tc.rules)
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+			require.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+)
+
+// MaxSilenceTemplateWindow is the longest silence that is created for a single window of the schedule of a silence
+// template. Longer windows, for example a schedule that matches all days of the week, are split into several silences.
+const MaxSilenceTemplateWindow = 7 * 24 * time.Hour
+
+var (
+	ErrSilenceTemplateNotFound = errutil.NotFound("alerting.silence-templates.notFound", errutil.WithPublicMessage("Silence template not found."))
+	ErrSilenceTemplateExists   = errutil.BadRequest("alerting.silence-templates.titleExists", errutil.WithPublicMessage("Silence template with this title already exists. Use a different title or update the existing one."))
+	ErrSilenceTemplateInvalid  = errutil.BadRequest("alerting.silence-templates.invalidFormat").MustTemplate(
+		"Invalid format of the submitted silence template",
+		errutil.WithPublic("Silence template is in invalid format: {{ .Public.Error }}. Correct the payload and try again."),
+	)
+)
+
+// SilenceTemplate is a saved set of silence matchers. Silences are created from the template either on demand, for the
+// duration of the template, or by a background job for every window of the schedule of the template.
+type SilenceTemplate struct {
+	UID      string
+	OrgID    int64
+	Title    string
+	Matchers amv2.Matchers
+	Comment  string
+	// Duration is the duration of the silences created from the template on demand.
+	Duration time.Duration
+	// Schedule makes the template a recurring silence. A silence is created for every window in which the time
+	// matches one of the time intervals, in the same format as the time intervals of mute timings.
+	Schedule []timeinterval.TimeInterval
+	Updated  time.Time
+	// MaterializedUntil is the end of the last silence created for the schedule.
+	MaterializedUntil time.Time
+}
+
+func (t *SilenceTemplate) ResourceType() string {
+	return "silenceTemplate"
+}
+
+func (t *SilenceTemplate) ResourceID() string {
+	return t.UID
+}
+
+// IsRecurring returns true if silences are created for the schedule of the template.
+func (t SilenceTemplate) IsRecurring() bool {
+	return len(t.Schedule) > 0
+}
+
+// Validate checks that silences can be created from the template.
+func (t SilenceTemplate) Validate() error {
+	if err := t.validate(); err != nil {
+		return MakeErrSilenceTemplateInvalid(err)
+	}
+	return nil
+}
+
+func (t SilenceTemplate) validate() error {
+	if t.Title == "" {
+		return errors.New("title must not be empty")
+	}
+	if len(t.Matchers) == 0 {
+		return errors.New("at least one matcher is required")
+	}
+	if err := t.Matchers.Validate(strfmt.Default); err != nil {
+		return fmt.Errorf("invalid matchers: %w", err)
+	}
+	if t.Duration < 0 {
+		return errors.New("duration must not be negative")
+	}
+	if !t.IsRecurring() && t.Duration == 0 {
+		return errors.New("duration is required if the template has no schedule")
+	}
+	return nil
+}
+
+// MakeErrSilenceTemplateInvalid creates an error with the ErrSilenceTemplateInvalid template.
+func MakeErrSilenceTemplateInvalid(err error) error {
+	return ErrSilenceTemplateInvalid.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Error": err.Error(),
+		},
+		Error: err,
+	})
+}
+
+// NextWindow returns the first window of the schedule that is active between from and until. The window starts at from
+// if the schedule is active at that time. The window ends when the schedule is not active anymore, but it is never
+// longer than MaxSilenceTemplateWindow.
+func (t SilenceTemplate) NextWindow(from, until time.Time) (start, end time.Time, ok bool) {
+	for ts := from.Truncate(time.Minute); ts.Before(until); ts = ts.Add(time.Minute) {
+		if t.scheduleContains(ts) {
+			start = ts
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	if start.Before(from) {
+		start = from
+	}
+
+	// Time intervals are defined in minutes, so it is enough to check the schedule every minute.
+	end = start.Truncate(time.Minute).Add(time.Minute)
+	limit := start.Add(MaxSilenceTemplateWindow)
+	for end.Before(limit) && t.scheduleContains(end) {
+		end = end.Add(time.Minute)
+	}
+	if end.After(limit) {
+		end = limit
+	}
+	return start, end, true
+}
+
+func (t SilenceTemplate) scheduleContains(ts time.Time) bool {
+	for _, interval := range t.Schedule {
+		if interval.ContainsTime(ts) {
+			return true
+		}
+	}
+	return false
+}
+
+// Silence returns a silence with the matchers of the template that is active between start and end.
+func (t SilenceTemplate) Silence(createdBy string, start, end time.Time) Silence {
+	comment := t.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("Created from silence template %q", t.Title)
+	}
+	startsAt := strfmt.DateTime(start)
+	endsAt := strfmt.DateTime(end)
+	silence := Silence{}
+	silence.Silence.Matchers = t.Matchers
+	silence.Silence.Comment = &comment
+	silence.Silence.CreatedBy = &createdBy
+	silence.Silence.StartsAt = &startsAt
+	silence.Silence.EndsAt = &endsAt
+	return silence
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func silenceTemplateMatchers() amv2.Matchers {
+	return amv2.Matchers{{
+		Name:    util.Pointer("env"),
+		Value:   util.Pointer("maint"),
+		IsEqual: util.Pointer(true),
+		IsRegex: util.Pointer(false),
+	}}
+}
+
+func parseSchedule(t *testing.T, s string) []timeinterval.TimeInterval {
+	t.Helper()
+	var schedule []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte(s), &schedule))
+	return schedule
+}
+
+func TestSilenceTemplateValidate(t *testing.T) {
+	valid := SilenceTemplate{Title: "maintenance", Matchers: silenceTemplateMatchers(), Duration: time.Hour}
+	require.NoError(t, valid.Validate())
+
+	recurring := valid
+	recurring.Duration = 0
+	recurring.Schedule = parseSchedule(t, "- weekdays: ['sunday']")
+	require.NoError(t, recurring.Validate())
+
+	testCases := []struct {
+		name   string
+		mutate func(t *SilenceTemplate)
+	}{
+		{name: "missing title", mutate: func(t *SilenceTemplate) { t.Title = "" }},
+		{name: "missing matchers", mutate: func(t *SilenceTemplate) { t.Matchers = nil }},
+		{name: "invalid matcher", mutate: func(t *SilenceTemplate) { t.Matchers[0].Name = nil }},
+		{name: "negative duration", mutate: func(t *SilenceTemplate) { t.Duration = -time.Minute }},
+		{name: "no duration and no schedule", mutate: func(t *SilenceTemplate) { t.Duration = 0 }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			template := valid
+			template.Matchers = silenceTemplateMatchers()
+			tc.mutate(&template)
+			require.ErrorIs(t, template.Validate(), ErrSilenceTemplateInvalid)
+		})
+	}
+}
+
+func TestSilenceTemplateNextWindow(t *testing.T) {
+	template := SilenceTemplate{
+		Schedule: parseSchedule(t, "- weekdays: ['sunday']\n  times: [{start_time: '02:00', end_time: '04:00'}]"),
+	}
+	// 2024-06-02 is a Sunday.
+	sunday := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("returns the next window", func(t *testing.T) {
+		start, end, ok := template.NextWindow(sunday, sunday.Add(3*time.Hour))
+		require.True(t, ok)
+		require.Equal(t, sunday.Add(2*time.Hour), start)
+		require.Equal(t, sunday.Add(4*time.Hour), end)
+	})
+
+	t.Run("starts at from if the window is active", func(t *testing.T) {
+		from := sunday.Add(3*time.Hour + 30*time.Second)
+		start, end, ok := template.NextWindow(from, from.Add(time.Hour))
+		require.True(t, ok)
+		require.Equal(t, from, start)
+		require.Equal(t, sunday.Add(4*time.Hour), end)
+	})
+
+	t.Run("returns false if there is no window before until", func(t *testing.T) {
+		_, _, ok := template.NextWindow(sunday.Add(4*time.Hour), sunday.Add(24*time.Hour))
+		require.False(t, ok)
+	})
+
+	t.Run("limits the window to MaxSilenceTemplateWindow", func(t *testing.T) {
+		always := SilenceTemplate{Schedule: parseSchedule(t, "- weekdays: ['sunday:saturday']")}
+		start, end, ok := always.NextWindow(sunday, sunday.Add(time.Hour))
+		require.True(t, ok)
+		require.Equal(t, sunday, start)
+		require.Equal(t, sunday.Add(MaxSilenceTemplateWindow), end)
+	})
+}
+
+func TestSilenceTemplateSilence(t *testing.T) {
+	template := SilenceTemplate{Title: "maintenance", Matchers: silenceTemplateMatchers()}
+	start := time.Date(2024, 6, 2, 2, 0, 0, 0, time.UTC)
+	silence := template.Silence("admin", start, start.Add(time.Hour))
+
+	require.Equal(t, template.Matchers, silence.Matchers)
+	require.Equal(t, "admin", *silence.CreatedBy)
+	require.Equal(t, `Created from silence template "maintenance"`, *silence.Comment)
+	require.Equal(t, start, time.Time(*silence.StartsAt))
+	require.Equal(t, start.Add(time.Hour), time.Time(*silence.EndsAt))
+}
@@ -576,6 +576,12 @@ func (a *AlertRuleMutators) WithMetadata(meta AlertRuleMetadata) AlertRuleMutato
 	}
 }
 
+func (a *AlertRuleMutators) WithDependencies(deps ...RuleDependency) AlertRuleMutator {
+	return func(r *AlertRule) {
+		r.Dependencies = deps
+	}
+}
+
 func (g *AlertRuleGenerator) GenerateLabels(min, max int, prefix string) data.Labels {
 	count := max
 	if min > max {
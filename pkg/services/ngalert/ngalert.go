@@ -161,6 +161,11 @@ type AlertNG struct {
 	store                *store.DBstore
 	userService          user.Service
 
+	// silenceTemplateScheduler creates the silences of recurring silence templates.
+	silenceTemplateScheduler *notifier.SilenceTemplateScheduler
+	// notificationDeliveries queries and cleans up the notification delivery log. It is nil if the log is disabled.
+	notificationDeliveries *notifier.NotificationDeliveryService
+
 	bus          bus.Bus
 	pluginsStore pluginstore.Store
 	tracer       tracing.Tracer
@@ -316,6 +321,10 @@ func (ng *AlertNG) init() error {
 		}
 	}
 
+	if ng.Cfg.UnifiedAlerting.NotificationDeliveryLog {
+		overrides = append(overrides, notifier.WithNotificationDeliveryLog(ng.store))
+	}
+
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
 	moa, err := notifier.NewMultiOrgAlertmanager(
@@ -396,6 +405,17 @@ func (ng *AlertNG) init() error {
 		Tracer:               ng.tracer,
 		Log:                  log.New("ngalert.scheduler"),
 		RecordingWriter:      ng.RecordingWriter,
+		EvaluationLimits:     ng.Cfg.UnifiedAlerting.EvaluationLimits,
+	}
+
+	if ng.Cfg.UnifiedAlerting.HAEvaluationSharding {
+		if ng.FeatureToggles.IsEnabledGlobally(featuremgmt.FlagAlertingSaveStatePeriodic) {
+			// The periodic persister replaces all the states in the database with the ones of this instance.
+			ng.Log.Warn("Evaluation sharding is not supported with the periodic state persister and will be disabled")
+		} else {
+			schedCfg.ClusterMembership = ng.MultiOrgAlertmanager
+			schedCfg.KVStore = ng.KVStore
+		}
 	}
 
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
@@ -413,6 +433,7 @@ func (ng *AlertNG) init() error {
 		ExternalURL:                    appUrl,
 		DisableExecution:               !ng.Cfg.UnifiedAlerting.ExecuteAlerts,
 		InstanceStore:                  ng.InstanceStore,
+		TransactionManager:             ng.store,
 		Images:                         ng.ImageService,
 		Clock:                          clk,
 		Historian:                      history,
@@ -465,6 +486,12 @@ func (ng *AlertNG) init() error {
 	contactPointService := provisioning.NewContactPointService(configStore, ng.SecretsService, ng.store, ng.store, provisioningReceiverService, ng.Log, ng.store, ng.ResourcePermissions)
 	templateService := provisioning.NewTemplateService(configStore, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(configStore, ng.store, ng.store, ng.Log, ng.store)
+	silenceTemplateService := provisioning.NewSilenceTemplateService(ng.store, ng.store, ng.store, ng.Log)
+	ng.silenceTemplateScheduler = notifier.NewSilenceTemplateScheduler(ng.store, ng.MultiOrgAlertmanager, clk, log.New("ngalert.silence-templates"))
+	if ng.Cfg.UnifiedAlerting.NotificationDeliveryLog {
+		ng.notificationDeliveries = notifier.NewNotificationDeliveryService(ng.store, ng.MultiOrgAlertmanager,
+			ng.Cfg.UnifiedAlerting.NotificationDeliveryLogRetention, clk, log.New("ngalert.notification-deliveries"))
+	}
 	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.folderService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
@@ -485,6 +512,7 @@ func (ng *AlertNG) init() error {
 		ProvenanceStore:      ng.store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
 		StateManager:         ng.stateManager,
+		InstanceStore:        ng.InstanceStore,
 		Scheduler:            scheduler,
 		AccessControl:        ng.accesscontrol,
 		Policies:             policyService,
@@ -492,6 +520,8 @@ func (ng *AlertNG) init() error {
 		ContactPointService:  contactPointService,
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
+		SilenceTemplates:     silenceTemplateService,
+		DeliveryLog:          ng.notificationDeliveries,
 		AlertRules:           alertRuleService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
@@ -502,6 +532,7 @@ func (ng *AlertNG) init() error {
 		Hooks:                api.NewHooks(ng.Log),
 		Tracer:               ng.tracer,
 		UserService:          ng.userService,
+		FolderService:        ng.folderService,
 	}
 	ng.Api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -603,6 +634,14 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.silenceTemplateScheduler.Run(subCtx)
+	})
+	if ng.notificationDeliveries != nil {
+		children.Go(func() error {
+			return ng.notificationDeliveries.Run(subCtx)
+		})
+	}
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		// Only Warm() the state manager if we are actually executing alerts.
@@ -641,6 +680,15 @@ func (ng *AlertNG) GetHooks() *api.Hooks {
 	return ng.Api.Hooks
 }
 
+// GetStatesForRuleUID returns the current state of the alert instances of a rule, or nothing when
+// the alerting service is disabled.
+func (ng *AlertNG) GetStatesForRuleUID(orgID int64, ruleUID string) []*state.State {
+	if ng.stateManager == nil {
+		return nil
+	}
+	return ng.stateManager.GetStatesForRuleUID(orgID, ruleUID)
+}
+
 type Historian interface {
 	api.Historian
 	state.Historian
@@ -62,6 +62,9 @@ type alertmanager struct {
 	orgID     int64
 
 	withAutogen bool
+
+	// deliveryLog saves the notification attempts of the integrations. It is nil if the delivery log is disabled.
+	deliveryLog NotificationDeliveryStore
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -363,7 +366,7 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig) (bool, er
 		timeIntervals:            cfg.AlertmanagerConfig.TimeIntervals,
 		templates:                ToTemplateDefinitions(cfg),
 		receivers:                PostableApiAlertingConfigToApiReceivers(cfg.AlertmanagerConfig),
-		receiverIntegrationsFunc: am.buildReceiverIntegrations,
+		receiverIntegrationsFunc: am.buildRecordedReceiverIntegrations,
 	})
 	if err != nil {
 		return false, err
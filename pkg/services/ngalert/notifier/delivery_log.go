@@ -0,0 +1,358 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// deliveryStoreTimeout limits the time to save a notification delivery, so that a slow database does not delay
+// notifications.
+const deliveryStoreTimeout = 10 * time.Second
+
+// NotificationDeliveryStore saves the notification attempts of receiver integrations.
+type NotificationDeliveryStore interface {
+	InsertNotificationDelivery(ctx context.Context, delivery models.NotificationDelivery) (models.NotificationDelivery, error)
+	GetNotificationDelivery(ctx context.Context, orgID int64, id int64) (models.NotificationDelivery, error)
+	ListNotificationDeliveries(ctx context.Context, query models.ListNotificationDeliveriesQuery) ([]models.NotificationDelivery, error)
+	DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WithNotificationDeliveryLog saves every notification attempt of the receiver integrations of the Alertmanagers in
+// the store.
+func WithNotificationDeliveryLog(store NotificationDeliveryStore) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.deliveryLog = store
+	}
+}
+
+type deliveryAttemptKey struct{}
+
+// deliveryAttempt collects the details of a notification attempt that are only known to the webhook and email
+// senders.
+type deliveryAttempt struct {
+	mtx           sync.Mutex
+	payloadDigest string
+	statusCode    int
+}
+
+func withDeliveryAttempt(ctx context.Context, attempt *deliveryAttempt) context.Context {
+	return context.WithValue(ctx, deliveryAttemptKey{}, attempt)
+}
+
+func deliveryAttemptFromContext(ctx context.Context) *deliveryAttempt {
+	attempt, _ := ctx.Value(deliveryAttemptKey{}).(*deliveryAttempt)
+	return attempt
+}
+
+// recordPayload sets the digest of the payload sent by the integration. Integrations that send several requests
+// record the last one.
+func (a *deliveryAttempt) recordPayload(payload ...string) {
+	if a == nil {
+		return
+	}
+	h := sha256.New()
+	for _, p := range payload {
+		_, _ = h.Write([]byte(p))
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.payloadDigest = hex.EncodeToString(h.Sum(nil))
+}
+
+func (a *deliveryAttempt) recordStatusCode(statusCode int) {
+	if a == nil {
+		return
+	}
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.statusCode = statusCode
+}
+
+// deliveryRecorder is a notifier that saves every notification attempt of an integration.
+type deliveryRecorder struct {
+	upstream notify.Notifier
+	store    NotificationDeliveryStore
+	logger   log.Logger
+
+	orgID            int64
+	receiver         string
+	integrationUID   string
+	integrationType  string
+	integrationIndex int
+	resentFrom       int64
+
+	// attempts counts the attempts of the notifications that are retried. The notification pipeline retries a
+	// notification with the same context.
+	attemptsMtx sync.Mutex
+	attempts    map[context.Context]int
+}
+
+func (r *deliveryRecorder) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	_, retry, err := r.notify(ctx, alerts...)
+	return retry, err
+}
+
+// notify sends the notification with the upstream notifier and returns the saved delivery.
+func (r *deliveryRecorder) notify(ctx context.Context, alerts ...*types.Alert) (models.NotificationDelivery, bool, error) {
+	retryNumber := r.nextAttempt(ctx)
+	attempt := &deliveryAttempt{}
+	start := time.Now()
+	retry, err := r.upstream.Notify(withDeliveryAttempt(ctx, attempt), alerts...)
+	duration := time.Since(start)
+	if err == nil || !retry {
+		r.forgetAttempts(ctx)
+	}
+
+	delivery := models.NotificationDelivery{
+		OrgID:            r.orgID,
+		Created:          start,
+		Receiver:         r.receiver,
+		IntegrationUID:   r.integrationUID,
+		IntegrationType:  r.integrationType,
+		IntegrationIndex: r.integrationIndex,
+		Alerts:           make([]models.NotificationDeliveryAlert, 0, len(alerts)),
+		Retry:            retryNumber,
+		Duration:         duration,
+		ResentFrom:       r.resentFrom,
+	}
+	delivery.GroupKey, _ = notify.GroupKey(ctx)
+	groupLabels, _ := notify.GroupLabels(ctx)
+	delivery.GroupLabels = make(map[string]string, len(groupLabels))
+	for k, v := range groupLabels {
+		delivery.GroupLabels[string(k)] = string(v)
+	}
+	for _, alert := range alerts {
+		delivery.Alerts = append(delivery.Alerts, deliveryAlertFromAlert(alert))
+		delivery.AlertFingerprints = append(delivery.AlertFingerprints, alert.Fingerprint().String())
+	}
+	attempt.mtx.Lock()
+	delivery.PayloadDigest = attempt.payloadDigest
+	delivery.StatusCode = attempt.statusCode
+	attempt.mtx.Unlock()
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	// Detached context here is to make sure that the attempt is saved even if the notification timed out.
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deliveryStoreTimeout)
+	defer cancel()
+	saved, storeErr := r.store.InsertNotificationDelivery(storeCtx, delivery)
+	if storeErr != nil {
+		r.logger.Error("Failed to save notification delivery", "receiver", r.receiver, "integration", r.integrationType, "error", storeErr)
+		saved = delivery
+	}
+	return saved, retry, err
+}
+
+func (r *deliveryRecorder) nextAttempt(ctx context.Context) int {
+	r.attemptsMtx.Lock()
+	defer r.attemptsMtx.Unlock()
+	n, ok := r.attempts[ctx]
+	if !ok {
+		// Forget the attempts when the notification times out without another attempt.
+		context.AfterFunc(ctx, func() { r.forgetAttempts(ctx) })
+	}
+	r.attempts[ctx] = n + 1
+	return n
+}
+
+func (r *deliveryRecorder) forgetAttempts(ctx context.Context) {
+	r.attemptsMtx.Lock()
+	defer r.attemptsMtx.Unlock()
+	delete(r.attempts, ctx)
+}
+
+func deliveryAlertFromAlert(alert *types.Alert) models.NotificationDeliveryAlert {
+	result := models.NotificationDeliveryAlert{
+		Labels:       make(map[string]string, len(alert.Labels)),
+		StartsAt:     alert.StartsAt,
+		EndsAt:       alert.EndsAt,
+		UpdatedAt:    alert.UpdatedAt,
+		GeneratorURL: alert.GeneratorURL,
+	}
+	for k, v := range alert.Labels {
+		result.Labels[string(k)] = string(v)
+	}
+	if len(alert.Annotations) > 0 {
+		result.Annotations = make(map[string]string, len(alert.Annotations))
+		for k, v := range alert.Annotations {
+			result.Annotations[string(k)] = string(v)
+		}
+	}
+	return result
+}
+
+func alertFromDeliveryAlert(alert models.NotificationDeliveryAlert) *types.Alert {
+	result := &types.Alert{
+		Alert: model.Alert{
+			Labels:       make(model.LabelSet, len(alert.Labels)),
+			Annotations:  make(model.LabelSet, len(alert.Annotations)),
+			StartsAt:     alert.StartsAt,
+			EndsAt:       alert.EndsAt,
+			GeneratorURL: alert.GeneratorURL,
+		},
+		UpdatedAt: alert.UpdatedAt,
+	}
+	for k, v := range alert.Labels {
+		result.Labels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	for k, v := range alert.Annotations {
+		result.Annotations[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return result
+}
+
+// buildRecordedReceiverIntegrations builds the integrations of a receiver that save their notification attempts in
+// the delivery log, if it is enabled.
+func (am *alertmanager) buildRecordedReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+	integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+	if err != nil || am.deliveryLog == nil {
+		return integrations, err
+	}
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		recorder := am.newDeliveryRecorder(receiver, integration)
+		result = append(result, alertingNotify.NewIntegration(recorder, integration, integration.Name(), integration.Index(), receiver.Name))
+	}
+	return result, nil
+}
+
+func (am *alertmanager) newDeliveryRecorder(receiver *alertingNotify.APIReceiver, integration *alertingNotify.Integration) *deliveryRecorder {
+	return &deliveryRecorder{
+		upstream:         integration,
+		store:            am.deliveryLog,
+		logger:           am.logger,
+		orgID:            am.orgID,
+		receiver:         receiver.Name,
+		integrationUID:   integrationUID(receiver, integration),
+		integrationType:  integration.Name(),
+		integrationIndex: integration.Index(),
+		attempts:         make(map[context.Context]int),
+	}
+}
+
+// integrationUID returns the UID of the configuration of the integration. The index of an integration is its index
+// among the integrations of the same type of the receiver.
+func integrationUID(receiver *alertingNotify.APIReceiver, integration *alertingNotify.Integration) string {
+	idx := 0
+	for _, cfg := range receiver.Integrations {
+		if cfg.Type != integration.Name() {
+			continue
+		}
+		if idx == integration.Index() {
+			return cfg.UID
+		}
+		idx++
+	}
+	return ""
+}
+
+// findDeliveryIntegration returns the configuration of the integration that sent the delivery. Deliveries of
+// integrations without a UID are matched by the type and the index of the integration, like integrationUID.
+func findDeliveryIntegration(integrations []*alertingNotify.GrafanaIntegrationConfig, delivery models.NotificationDelivery) *alertingNotify.GrafanaIntegrationConfig {
+	idx := 0
+	for _, integration := range integrations {
+		if delivery.IntegrationUID != "" {
+			if integration.UID == delivery.IntegrationUID {
+				return integration
+			}
+			continue
+		}
+		if integration.Type != delivery.IntegrationType {
+			continue
+		}
+		if idx == delivery.IntegrationIndex {
+			return integration
+		}
+		idx++
+	}
+	return nil
+}
+
+// resendNotification sends the alerts of a notification delivery again with the current configuration of its
+// integration, and returns the new delivery.
+func (am *alertmanager) resendNotification(ctx context.Context, delivery models.NotificationDelivery) (models.NotificationDelivery, error) {
+	if am.deliveryLog == nil {
+		return models.NotificationDelivery{}, errors.New("the notification delivery log is disabled")
+	}
+	dbCfg, err := am.Store.GetLatestAlertmanagerConfiguration(ctx, am.orgID)
+	if err != nil {
+		return models.NotificationDelivery{}, fmt.Errorf("failed to get the Alertmanager configuration: %w", err)
+	}
+	cfg, err := Load([]byte(dbCfg.AlertmanagerConfiguration))
+	if err != nil {
+		return models.NotificationDelivery{}, fmt.Errorf("failed to parse the Alertmanager configuration: %w", err)
+	}
+
+	var receiver *alertingNotify.APIReceiver
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		if r.Name != delivery.Receiver {
+			continue
+		}
+		if integration := findDeliveryIntegration(PostableApiReceiverToApiReceiver(r).Integrations, delivery); integration != nil {
+			receiver = &alertingNotify.APIReceiver{
+				ConfigReceiver:      r.Receiver,
+				GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: []*alertingNotify.GrafanaIntegrationConfig{integration}},
+			}
+		}
+	}
+	if receiver == nil {
+		integration := delivery.IntegrationUID
+		if integration == "" {
+			integration = fmt.Sprintf("%s #%d", delivery.IntegrationType, delivery.IntegrationIndex)
+		}
+		return models.NotificationDelivery{}, models.MakeErrNotificationDeliveryResendFailed(
+			fmt.Errorf("integration %s of contact point %s does not exist", integration, delivery.Receiver))
+	}
+
+	tmpl, err := am.Base.GetTemplate()
+	if err != nil {
+		return models.NotificationDelivery{}, fmt.Errorf("failed to get the notification templates: %w", err)
+	}
+	integrations, err := am.buildReceiverIntegrations(receiver, tmpl)
+	if err != nil {
+		return models.NotificationDelivery{}, models.MakeErrNotificationDeliveryResendFailed(err)
+	}
+	if len(integrations) != 1 {
+		return models.NotificationDelivery{}, fmt.Errorf("expected one integration, got %d", len(integrations))
+	}
+
+	recorder := am.newDeliveryRecorder(receiver, integrations[0])
+	// The integration of the receiver built from a single integration always has the index 0.
+	recorder.integrationUID = delivery.IntegrationUID
+	recorder.integrationIndex = delivery.IntegrationIndex
+	recorder.resentFrom = delivery.ID
+
+	groupLabels := make(model.LabelSet, len(delivery.GroupLabels))
+	for k, v := range delivery.GroupLabels {
+		groupLabels[model.LabelName(k)] = model.LabelValue(v)
+	}
+	alerts := make([]*types.Alert, 0, len(delivery.Alerts))
+	for _, alert := range delivery.Alerts {
+		alerts = append(alerts, alertFromDeliveryAlert(alert))
+	}
+
+	ctx = notify.WithGroupKey(ctx, delivery.GroupKey)
+	ctx = notify.WithGroupLabels(ctx, groupLabels)
+	ctx = notify.WithReceiverName(ctx, delivery.Receiver)
+	ctx = notify.WithNow(ctx, time.Now())
+	resent, _, err := recorder.notify(ctx, alerts...)
+	if err != nil {
+		return resent, models.MakeErrNotificationDeliveryResendFailed(err)
+	}
+	return resent, nil
+}
@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// deliveryLogCleanupInterval is how often the notification deliveries older than the retention are deleted.
+const deliveryLogCleanupInterval = time.Hour
+
+// alertmanagerProvider returns the Alertmanager of an organization.
+type alertmanagerProvider interface {
+	AlertmanagerFor(orgID int64) (Alertmanager, error)
+}
+
+// NotificationDeliveryService queries the notification delivery log, resends notifications and deletes the
+// deliveries older than the retention.
+type NotificationDeliveryService struct {
+	store         NotificationDeliveryStore
+	alertmanagers alertmanagerProvider
+	retention     time.Duration
+	clock         clock.Clock
+	log           log.Logger
+}
+
+func NewNotificationDeliveryService(store NotificationDeliveryStore, alertmanagers alertmanagerProvider, retention time.Duration, clk clock.Clock, logger log.Logger) *NotificationDeliveryService {
+	return &NotificationDeliveryService{
+		store:         store,
+		alertmanagers: alertmanagers,
+		retention:     retention,
+		clock:         clk,
+		log:           logger,
+	}
+}
+
+// ListNotificationDeliveries returns the notification deliveries that match the query, from the most recent.
+func (s *NotificationDeliveryService) ListNotificationDeliveries(ctx context.Context, query models.ListNotificationDeliveriesQuery) ([]models.NotificationDelivery, error) {
+	return s.store.ListNotificationDeliveries(ctx, query)
+}
+
+// ResendNotification sends the alerts of a notification delivery again with the current configuration of its
+// integration. It returns the new delivery, which is also saved in the log.
+func (s *NotificationDeliveryService) ResendNotification(ctx context.Context, orgID int64, id int64) (models.NotificationDelivery, error) {
+	delivery, err := s.store.GetNotificationDelivery(ctx, orgID, id)
+	if err != nil {
+		return models.NotificationDelivery{}, err
+	}
+	am, err := s.alertmanagers.AlertmanagerFor(orgID)
+	if err != nil {
+		return models.NotificationDelivery{}, err
+	}
+	internal, ok := am.(*alertmanager)
+	if !ok {
+		return models.NotificationDelivery{}, models.MakeErrNotificationDeliveryResendFailed(errors.New("notifications can only be resent by the internal Alertmanager"))
+	}
+	s.log.Info("Resending notification", "orgID", orgID, "id", id, "receiver", delivery.Receiver, "integration", delivery.IntegrationUID)
+	return internal.resendNotification(ctx, delivery)
+}
+
+// Run deletes the notification deliveries older than the retention until the context is cancelled.
+func (s *NotificationDeliveryService) Run(ctx context.Context) error {
+	ticker := s.clock.Ticker(deliveryLogCleanupInterval)
+	defer ticker.Stop()
+	for {
+		s.cleanup(ctx, s.clock.Now())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *NotificationDeliveryService) cleanup(ctx context.Context, now time.Time) {
+	deleted, err := s.store.DeleteNotificationDeliveriesBefore(ctx, now.Add(-s.retention))
+	if err != nil {
+		s.log.Error("Failed to delete old notification deliveries", "error", err)
+		return
+	}
+	if deleted > 0 {
+		s.log.Debug("Deleted old notification deliveries", "count", deleted)
+	}
+}
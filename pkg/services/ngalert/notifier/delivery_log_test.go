@@ -0,0 +1,354 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+type fakeNotificationDeliveryStore struct {
+	mtx        sync.Mutex
+	deliveries []models.NotificationDelivery
+	insertErr  error
+}
+
+func (f *fakeNotificationDeliveryStore) InsertNotificationDelivery(_ context.Context, delivery models.NotificationDelivery) (models.NotificationDelivery, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if f.insertErr != nil {
+		return models.NotificationDelivery{}, f.insertErr
+	}
+	delivery.ID = int64(len(f.deliveries) + 1)
+	f.deliveries = append(f.deliveries, delivery)
+	return delivery, nil
+}
+
+func (f *fakeNotificationDeliveryStore) GetNotificationDelivery(_ context.Context, orgID int64, id int64) (models.NotificationDelivery, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, d := range f.deliveries {
+		if d.OrgID == orgID && d.ID == id {
+			return d, nil
+		}
+	}
+	return models.NotificationDelivery{}, models.ErrNotificationDeliveryNotFound.Errorf("")
+}
+
+func (f *fakeNotificationDeliveryStore) ListNotificationDeliveries(_ context.Context, q models.ListNotificationDeliveriesQuery) ([]models.NotificationDelivery, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var result []models.NotificationDelivery
+	for _, d := range f.deliveries {
+		if d.OrgID == q.OrgID {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeNotificationDeliveryStore) DeleteNotificationDeliveriesBefore(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	kept := f.deliveries[:0]
+	for _, d := range f.deliveries {
+		if !d.Created.Before(before) {
+			kept = append(kept, d)
+		}
+	}
+	deleted := int64(len(f.deliveries) - len(kept))
+	f.deliveries = kept
+	return deleted, nil
+}
+
+type fakeNotifier struct {
+	calls  int
+	notify func(ctx context.Context, call int) (bool, error)
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, _ ...*types.Alert) (bool, error) {
+	f.calls++
+	return f.notify(ctx, f.calls)
+}
+
+func newTestDeliveryRecorder(upstream notify.Notifier, store NotificationDeliveryStore) *deliveryRecorder {
+	return &deliveryRecorder{
+		upstream:         upstream,
+		store:            store,
+		logger:           log.NewNopLogger(),
+		orgID:            1,
+		receiver:         "team-a",
+		integrationUID:   "webhook-uid",
+		integrationType:  "webhook",
+		integrationIndex: 0,
+		attempts:         make(map[context.Context]int),
+	}
+}
+
+func testDeliveryAlert(name string) *types.Alert {
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels:       model.LabelSet{"alertname": model.LabelValue(name)},
+			Annotations:  model.LabelSet{"summary": "test"},
+			StartsAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			GeneratorURL: "http://localhost/alerting",
+		},
+	}
+}
+
+func TestDeliveryRecorder(t *testing.T) {
+	groupCtx := func() context.Context {
+		ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+		return notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "test"})
+	}
+
+	t.Run("saves every attempt of a notification", func(t *testing.T) {
+		store := &fakeNotificationDeliveryStore{}
+		upstream := &fakeNotifier{notify: func(_ context.Context, call int) (bool, error) {
+			if call < 3 {
+				return true, errors.New("unavailable")
+			}
+			return false, nil
+		}}
+		r := newTestDeliveryRecorder(upstream, store)
+		ctx := groupCtx()
+		alerts := []*types.Alert{testDeliveryAlert("test-1"), testDeliveryAlert("test-2")}
+
+		for i := 0; i < 3; i++ {
+			_, _ = r.Notify(ctx, alerts...)
+		}
+
+		require.Len(t, store.deliveries, 3)
+		for i, d := range store.deliveries {
+			assert.Equal(t, i, d.Retry)
+			assert.Equal(t, int64(1), d.OrgID)
+			assert.Equal(t, "team-a", d.Receiver)
+			assert.Equal(t, "webhook-uid", d.IntegrationUID)
+			assert.Equal(t, "webhook", d.IntegrationType)
+			assert.Equal(t, "{}:{alertname=\"test\"}", d.GroupKey)
+			assert.Equal(t, map[string]string{"alertname": "test"}, d.GroupLabels)
+			assert.Equal(t, []string{alerts[0].Fingerprint().String(), alerts[1].Fingerprint().String()}, d.AlertFingerprints)
+			require.Len(t, d.Alerts, 2)
+			assert.Equal(t, map[string]string{"alertname": "test-1"}, d.Alerts[0].Labels)
+			assert.Equal(t, "http://localhost/alerting", d.Alerts[0].GeneratorURL)
+		}
+		assert.Equal(t, "unavailable", store.deliveries[0].Error)
+		assert.False(t, store.deliveries[1].Succeeded())
+		assert.True(t, store.deliveries[2].Succeeded())
+		assert.Empty(t, r.attempts)
+	})
+
+	t.Run("numbers the attempts of different notifications separately", func(t *testing.T) {
+		store := &fakeNotificationDeliveryStore{}
+		upstream := &fakeNotifier{notify: func(_ context.Context, _ int) (bool, error) {
+			return true, errors.New("unavailable")
+		}}
+		r := newTestDeliveryRecorder(upstream, store)
+		ctx1, ctx2 := groupCtx(), groupCtx()
+
+		_, _ = r.Notify(ctx1, testDeliveryAlert("test"))
+		_, _ = r.Notify(ctx2, testDeliveryAlert("test"))
+		_, _ = r.Notify(ctx1, testDeliveryAlert("test"))
+
+		require.Len(t, store.deliveries, 3)
+		assert.Equal(t, 0, store.deliveries[0].Retry)
+		assert.Equal(t, 0, store.deliveries[1].Retry)
+		assert.Equal(t, 1, store.deliveries[2].Retry)
+	})
+
+	t.Run("forgets the attempts when the notification is cancelled", func(t *testing.T) {
+		upstream := &fakeNotifier{notify: func(_ context.Context, _ int) (bool, error) {
+			return true, errors.New("unavailable")
+		}}
+		r := newTestDeliveryRecorder(upstream, &fakeNotificationDeliveryStore{})
+		ctx, cancel := context.WithCancel(groupCtx())
+
+		_, _ = r.Notify(ctx, testDeliveryAlert("test"))
+		cancel()
+
+		require.Eventually(t, func() bool {
+			r.attemptsMtx.Lock()
+			defer r.attemptsMtx.Unlock()
+			return len(r.attempts) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("returns the result of the notifier if the delivery cannot be saved", func(t *testing.T) {
+		store := &fakeNotificationDeliveryStore{insertErr: errors.New("database is locked")}
+		upstream := &fakeNotifier{notify: func(_ context.Context, _ int) (bool, error) {
+			return true, errors.New("unavailable")
+		}}
+		r := newTestDeliveryRecorder(upstream, store)
+
+		retry, err := r.Notify(groupCtx(), testDeliveryAlert("test"))
+
+		require.EqualError(t, err, "unavailable")
+		require.True(t, retry)
+	})
+
+	t.Run("saves the payload digest and status code of webhooks", func(t *testing.T) {
+		store := &fakeNotificationDeliveryStore{}
+		ns := &notifications.NotificationServiceMock{
+			WebhookHandler: func(_ context.Context, cmd *notifications.SendWebhookSync) error {
+				return cmd.Validation([]byte("ok"), http.StatusAccepted)
+			},
+		}
+		s := sender{ns: ns}
+		upstream := &fakeNotifier{notify: func(ctx context.Context, _ int) (bool, error) {
+			return false, s.SendWebhook(ctx, &receivers.SendWebhookSettings{URL: "http://localhost/hook", Body: `{"status":"firing"}`})
+		}}
+		r := newTestDeliveryRecorder(upstream, store)
+
+		_, err := r.Notify(groupCtx(), testDeliveryAlert("test"))
+		require.NoError(t, err)
+
+		require.Len(t, store.deliveries, 1)
+		attempt := &deliveryAttempt{}
+		attempt.recordPayload(`{"status":"firing"}`)
+		assert.Equal(t, attempt.payloadDigest, store.deliveries[0].PayloadDigest)
+		assert.Equal(t, http.StatusAccepted, store.deliveries[0].StatusCode)
+	})
+}
+
+func TestSender_SendWebhookValidation(t *testing.T) {
+	t.Run("calls the validation of the integration", func(t *testing.T) {
+		ns := &notifications.NotificationServiceMock{
+			WebhookHandler: func(_ context.Context, cmd *notifications.SendWebhookSync) error {
+				return cmd.Validation([]byte("failed"), http.StatusOK)
+			},
+		}
+		attempt := &deliveryAttempt{}
+		err := sender{ns: ns}.SendWebhook(withDeliveryAttempt(context.Background(), attempt), &receivers.SendWebhookSettings{
+			Validation: func(body []byte, _ int) error {
+				return errors.New(string(body))
+			},
+		})
+		require.EqualError(t, err, "failed")
+		require.Equal(t, http.StatusOK, attempt.statusCode)
+	})
+
+	t.Run("does not add a validation outside of the delivery log", func(t *testing.T) {
+		ns := &notifications.NotificationServiceMock{}
+		require.NoError(t, sender{ns: ns}.SendWebhook(context.Background(), &receivers.SendWebhookSettings{}))
+		require.Nil(t, ns.Webhook.Validation)
+	})
+}
+
+func TestIntegrationUID(t *testing.T) {
+	receiver := &alertingNotify.APIReceiver{
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+			Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+				{UID: "webhook-1", Type: "webhook"},
+				{UID: "email-1", Type: "email"},
+				{UID: "webhook-2", Type: "webhook"},
+			},
+		},
+	}
+	newIntegration := func(name string, idx int) *alertingNotify.Integration {
+		return alertingNotify.NewIntegration(&fakeNotifier{}, nil, name, idx, "receiver")
+	}
+
+	require.Equal(t, "webhook-1", integrationUID(receiver, newIntegration("webhook", 0)))
+	require.Equal(t, "webhook-2", integrationUID(receiver, newIntegration("webhook", 1)))
+	require.Equal(t, "email-1", integrationUID(receiver, newIntegration("email", 0)))
+	require.Empty(t, integrationUID(receiver, newIntegration("slack", 0)))
+}
+
+func TestAlertmanager_ResendNotification(t *testing.T) {
+	ctx := context.Background()
+	am := setupAMTest(t)
+	deliveries := &fakeNotificationDeliveryStore{}
+	am.deliveryLog = deliveries
+	var sent []string
+	am.NotificationService = &notifications.NotificationServiceMock{
+		WebhookHandler: func(_ context.Context, cmd *notifications.SendWebhookSync) error {
+			sent = append(sent, cmd.Url)
+			return cmd.Validation(nil, http.StatusOK)
+		},
+	}
+
+	cfg := `{"alertmanager_config":{"route":{"receiver":"team-a"},"receivers":[{"name":"team-a","grafana_managed_receiver_configs":[{"uid":"webhook-uid","name":"team-a","type":"webhook","settings":{"url":"http://localhost/hook"}}]}]}}`
+	postable, err := Load([]byte(cfg))
+	require.NoError(t, err)
+	require.NoError(t, am.SaveAndApplyConfig(ctx, postable))
+
+	original, err := deliveries.InsertNotificationDelivery(ctx, models.NotificationDelivery{
+		OrgID:           1,
+		Created:         time.Now(),
+		Receiver:        "team-a",
+		IntegrationUID:  "webhook-uid",
+		IntegrationType: "webhook",
+		GroupKey:        "{}:{}",
+		GroupLabels:     map[string]string{"alertname": "test"},
+		Alerts:          []models.NotificationDeliveryAlert{deliveryAlertFromAlert(testDeliveryAlert("test"))},
+		Error:           "webhook response status 500 Internal Server Error",
+	})
+	require.NoError(t, err)
+
+	t.Run("sends the alerts with the current integration", func(t *testing.T) {
+		resent, err := am.resendNotification(ctx, original)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"http://localhost/hook"}, sent)
+		require.Equal(t, original.ID, resent.ResentFrom)
+		require.NotEqual(t, original.ID, resent.ID)
+		require.Equal(t, "webhook-uid", resent.IntegrationUID)
+		require.Equal(t, http.StatusOK, resent.StatusCode)
+		require.Equal(t, original.GroupLabels, resent.GroupLabels)
+		require.Equal(t, []string{testDeliveryAlert("test").Fingerprint().String()}, resent.AlertFingerprints)
+		require.True(t, resent.Succeeded())
+	})
+
+	t.Run("finds integrations without a UID by their index", func(t *testing.T) {
+		sent = nil
+		withoutUID := original
+		withoutUID.IntegrationUID = ""
+		resent, err := am.resendNotification(ctx, withoutUID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"http://localhost/hook"}, sent)
+		require.True(t, resent.Succeeded())
+
+		withoutUID.IntegrationIndex = 1
+		_, err = am.resendNotification(ctx, withoutUID)
+		require.ErrorIs(t, err, models.ErrNotificationDeliveryResendFailed)
+	})
+
+	t.Run("fails if the integration no longer exists", func(t *testing.T) {
+		deleted := original
+		deleted.IntegrationUID = "deleted-uid"
+		_, err := am.resendNotification(ctx, deleted)
+		require.ErrorIs(t, err, models.ErrNotificationDeliveryResendFailed)
+	})
+}
+
+func TestNotificationDeliveryService_Cleanup(t *testing.T) {
+	now := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	store := &fakeNotificationDeliveryStore{}
+	for _, created := range []time.Time{now.Add(-8 * 24 * time.Hour), now.Add(-6 * 24 * time.Hour), now} {
+		_, err := store.InsertNotificationDelivery(context.Background(), models.NotificationDelivery{OrgID: 1, Created: created})
+		require.NoError(t, err)
+	}
+	svc := NewNotificationDeliveryService(store, nil, 7*24*time.Hour, nil, log.NewNopLogger())
+
+	svc.cleanup(context.Background(), now)
+
+	var ids []int64
+	for _, d := range store.deliveries {
+		ids = append(ids, d.ID)
+	}
+	require.Equal(t, []int64{2, 3}, ids)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -103,6 +104,9 @@ type MultiOrgAlertmanager struct {
 	ns      notifications.Service
 
 	receiverResourcePermissions ac.ReceiverPermissionsService
+
+	// deliveryLog saves the notification attempts of the integrations. It is nil if the delivery log is disabled.
+	deliveryLog NotificationDeliveryStore
 }
 
 type OrgAlertmanagerFactory func(ctx context.Context, orgID int64) (Alertmanager, error)
@@ -160,7 +164,12 @@ func NewMultiOrgAlertmanager(
 	moa.factory = func(ctx context.Context, orgID int64) (Alertmanager, error) {
 		m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID), l)
 		stateStore := NewFileStore(orgID, kvStore)
-		return NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, stateStore, moa.peer, moa.decryptFn, moa.ns, m, featureManager)
+		am, err := NewAlertmanager(ctx, orgID, moa.settings, moa.configStore, stateStore, moa.peer, moa.decryptFn, moa.ns, m, featureManager)
+		if err != nil {
+			return nil, err
+		}
+		am.deliveryLog = moa.deliveryLog
+		return am, nil
 	}
 
 	for _, opt := range opts {
@@ -406,6 +415,30 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// ClusterMembers returns the name of this Grafana instance and the sorted names of the live members of the
+// Alertmanager cluster, this instance included. It returns no members if Grafana does not run in HA mode.
+func (moa *MultiOrgAlertmanager) ClusterMembers() (string, []string) {
+	var self string
+	var members []string
+	switch p := moa.peer.(type) {
+	case *alertingCluster.Peer:
+		self = p.Name()
+		for _, m := range p.Peers() {
+			members = append(members, m.Name())
+		}
+	case *redisPeer:
+		self = p.withPrefix(p.name)
+		members = slices.Clone(p.Members())
+	default:
+		return "", nil
+	}
+	if !slices.Contains(members, self) {
+		members = append(members, self)
+	}
+	slices.Sort(members)
+	return self, members
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
@@ -0,0 +1,294 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+var ErrRoutingSimulationInvalidRoute = errutil.BadRequest("alerting.notifications.simulation.invalidRoute").MustTemplate(
+	"Invalid notification policy tree.",
+	errutil.WithPublic("Invalid notification policy tree: {{.Public.Error}}. Correct the payload and try again."),
+)
+
+func makeErrRoutingSimulationInvalidRoute(err error) error {
+	return ErrRoutingSimulationInvalidRoute.Build(errutil.TemplateData{
+		Public: map[string]any{
+			"Error": err.Error(),
+		},
+		Error: err,
+	})
+}
+
+// SimulateRouting routes the alerts with the given label sets through the notification policy tree of the organization
+// at the given time. If route is not nil, it is used instead of the saved notification policy tree, so that changes to
+// the tree can be reviewed before they are saved. The silences are matched against the alerts as they would be by the
+// Alertmanager.
+func (moa *MultiOrgAlertmanager) SimulateRouting(ctx context.Context, orgID int64, route *definitions.Route, alerts []model.LabelSet, silences []*models.Silence, now time.Time) (definitions.NotificationPolicySimulationResult, error) {
+	cfg, err := moa.GetAlertmanagerConfiguration(ctx, orgID, false)
+	if err != nil {
+		return definitions.NotificationPolicySimulationResult{}, err
+	}
+	amConfig := cfg.AlertmanagerConfig
+	if route != nil {
+		RemoveAutogenConfigIfExists(route)
+		amConfig.Route = route
+		if err := validateSimulatedRoute(&amConfig); err != nil {
+			return definitions.NotificationPolicySimulationResult{}, makeErrRoutingSimulationInvalidRoute(err)
+		}
+	}
+	if moa.featureManager.IsEnabled(ctx, featuremgmt.FlagAlertingSimplifiedRouting) {
+		if err := AddAutogenConfig(ctx, moa.logger, moa.configStore, orgID, &amConfig, true); err != nil {
+			return definitions.NotificationPolicySimulationResult{}, err
+		}
+	}
+	return simulateRouting(&amConfig, alerts, silences, now)
+}
+
+// validateSimulatedRoute validates the notification policy tree of the configuration in the same way as it is validated
+// when it is saved.
+func validateSimulatedRoute(cfg *definitions.GettableApiAlertingConfig) error {
+	if cfg.Route == nil {
+		return fmt.Errorf("the notification policy tree is empty")
+	}
+	if err := cfg.Route.Validate(); err != nil {
+		return err
+	}
+	receivers := map[string]struct{}{
+		"": {}, // Allow empty receiver (inheriting from parent)
+	}
+	for _, receiver := range cfg.GetReceivers() {
+		receivers[receiver.Name] = struct{}{}
+	}
+	if err := cfg.Route.ValidateReceivers(receivers); err != nil {
+		return err
+	}
+	timeIntervals := map[string]struct{}{}
+	for _, mt := range cfg.MuteTimeIntervals {
+		timeIntervals[mt.Name] = struct{}{}
+	}
+	for _, ti := range cfg.TimeIntervals {
+		timeIntervals[ti.Name] = struct{}{}
+	}
+	return cfg.Route.ValidateTimeIntervals(timeIntervals)
+}
+
+// simulateRouting returns the routing trace of the alerts through the notification policy tree of the configuration.
+func simulateRouting(cfg *definitions.GettableApiAlertingConfig, alerts []model.LabelSet, silences []*models.Silence, now time.Time) (definitions.NotificationPolicySimulationResult, error) {
+	if cfg.Route == nil {
+		return definitions.NotificationPolicySimulationResult{}, fmt.Errorf("the configuration has no notification policy tree")
+	}
+	intervals := make(map[string][]timeinterval.TimeInterval, len(cfg.MuteTimeIntervals)+len(cfg.TimeIntervals))
+	for _, mt := range cfg.MuteTimeIntervals {
+		intervals[mt.Name] = mt.TimeIntervals
+	}
+	for _, ti := range cfg.TimeIntervals {
+		intervals[ti.Name] = ti.TimeIntervals
+	}
+
+	active, err := activeSilences(silences, now)
+	if err != nil {
+		return definitions.NotificationPolicySimulationResult{}, err
+	}
+
+	root := dispatch.NewRoute(cfg.Route.AsAMRoute(), nil)
+	result := definitions.NotificationPolicySimulationResult{
+		Time:   now,
+		Alerts: make([]definitions.NotificationPolicySimulationAlert, 0, len(alerts)),
+	}
+	for _, lset := range alerts {
+		alert := definitions.NotificationPolicySimulationAlert{
+			Labels:    lset,
+			Routes:    []definitions.SimulatedRoute{},
+			Receivers: []string{},
+		}
+		for _, s := range active {
+			if s.matchers.Matches(lset) {
+				alert.Silences = append(alert.Silences, s.silence)
+			}
+		}
+		alert.Silenced = len(alert.Silences) > 0
+
+		for _, path := range matchRoutes(root, cfg.Route, nil, nil, lset) {
+			r := simulatedRoute(path, intervals, now)
+			alert.Routes = append(alert.Routes, r)
+			if !r.Muted && !alert.Silenced && !slices.Contains(alert.Receivers, r.Receiver) {
+				alert.Receivers = append(alert.Receivers, r.Receiver)
+			}
+		}
+		result.Alerts = append(result.Alerts, alert)
+	}
+	return result, nil
+}
+
+// routeMatch is a policy in the path of a matched route.
+type routeMatch struct {
+	route  *dispatch.Route
+	config *definitions.Route
+	index  *int
+}
+
+// matchRoutes returns the paths to the routes that match the label set. It mirrors dispatch.Route.Match, which does
+// not expose the parents of the matched routes.
+func matchRoutes(r *dispatch.Route, cr *definitions.Route, index *int, path []routeMatch, lset model.LabelSet) [][]routeMatch {
+	if !r.Matchers.Matches(lset) {
+		return nil
+	}
+	path = append(slices.Clone(path), routeMatch{route: r, config: cr, index: index})
+
+	var all [][]routeMatch
+	for i, child := range r.Routes {
+		idx := i
+		matches := matchRoutes(child, cr.Routes[i], &idx, path, lset)
+		all = append(all, matches...)
+		if matches != nil && !child.Continue {
+			break
+		}
+	}
+	if len(all) == 0 {
+		all = append(all, path)
+	}
+	return all
+}
+
+func simulatedRoute(path []routeMatch, intervals map[string][]timeinterval.TimeInterval, now time.Time) definitions.SimulatedRoute {
+	opts := path[len(path)-1].route.RouteOpts
+	r := definitions.SimulatedRoute{
+		Path:           make([]definitions.SimulatedRoutePolicy, 0, len(path)),
+		Receiver:       opts.Receiver,
+		GroupBy:        make([]string, 0, len(opts.GroupBy)),
+		GroupWait:      model.Duration(opts.GroupWait),
+		GroupInterval:  model.Duration(opts.GroupInterval),
+		RepeatInterval: model.Duration(opts.RepeatInterval),
+	}
+	for _, m := range path {
+		p := definitions.SimulatedRoutePolicy{
+			Index:    m.index,
+			Receiver: m.config.Receiver,
+			Continue: m.config.Continue,
+		}
+		for _, matcher := range m.route.Matchers {
+			p.Matchers = append(p.Matchers, matcher.String())
+		}
+		r.Path = append(r.Path, p)
+	}
+	if opts.GroupByAll {
+		r.GroupBy = append(r.GroupBy, "...")
+	} else {
+		for l := range opts.GroupBy {
+			r.GroupBy = append(r.GroupBy, string(l))
+		}
+		slices.Sort(r.GroupBy)
+	}
+
+	// The Alertmanager mutes a route if any of its mute time intervals contains the current time, or if it has active
+	// time intervals and none of them contains the current time.
+	for _, name := range opts.MuteTimeIntervals {
+		active := intervalsContain(intervals[name], now)
+		r.MuteTimeIntervals = append(r.MuteTimeIntervals, definitions.SimulatedTimeInterval{Name: name, Active: active})
+		r.Muted = r.Muted || active
+	}
+	inActiveInterval := false
+	for _, name := range opts.ActiveTimeIntervals {
+		active := intervalsContain(intervals[name], now)
+		r.ActiveTimeIntervals = append(r.ActiveTimeIntervals, definitions.SimulatedTimeInterval{Name: name, Active: active})
+		inActiveInterval = inActiveInterval || active
+	}
+	if len(opts.ActiveTimeIntervals) > 0 && !inActiveInterval {
+		r.Muted = true
+	}
+	return r
+}
+
+func intervalsContain(intervals []timeinterval.TimeInterval, t time.Time) bool {
+	for _, ti := range intervals {
+		if ti.ContainsTime(t.UTC()) {
+			return true
+		}
+	}
+	return false
+}
+
+type simulatedSilence struct {
+	silence  definitions.SimulatedSilence
+	matchers labels.Matchers
+}
+
+// activeSilences returns the silences that are active at the given time with their matchers.
+func activeSilences(silences []*models.Silence, now time.Time) ([]simulatedSilence, error) {
+	result := make([]simulatedSilence, 0, len(silences))
+	for _, s := range silences {
+		if s == nil || s.StartsAt == nil || s.EndsAt == nil {
+			continue
+		}
+		startsAt, endsAt := time.Time(*s.StartsAt), time.Time(*s.EndsAt)
+		if now.Before(startsAt) || !now.Before(endsAt) {
+			continue
+		}
+		matchers, err := silenceMatchers(s.Matchers)
+		if err != nil {
+			return nil, err
+		}
+		sim := simulatedSilence{
+			silence: definitions.SimulatedSilence{
+				EndsAt:   endsAt,
+				Matchers: make([]string, 0, len(matchers)),
+			},
+			matchers: matchers,
+		}
+		if s.ID != nil {
+			sim.silence.ID = *s.ID
+		}
+		if s.Comment != nil {
+			sim.silence.Comment = *s.Comment
+		}
+		if s.CreatedBy != nil {
+			sim.silence.CreatedBy = *s.CreatedBy
+		}
+		for _, m := range matchers {
+			sim.silence.Matchers = append(sim.silence.Matchers, m.String())
+		}
+		result = append(result, sim)
+	}
+	return result, nil
+}
+
+func silenceMatchers(matchers amv2.Matchers) (labels.Matchers, error) {
+	result := make(labels.Matchers, 0, len(matchers))
+	for _, m := range matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			continue
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		isRegex := m.IsRegex != nil && *m.IsRegex
+		var t labels.MatchType
+		switch {
+		case isRegex && isEqual:
+			t = labels.MatchRegexp
+		case isRegex:
+			t = labels.MatchNotRegexp
+		case isEqual:
+			t = labels.MatchEqual
+		default:
+			t = labels.MatchNotEqual
+		}
+		matcher, err := labels.NewMatcher(t, *m.Name, *m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher of silence: %w", err)
+		}
+		result = append(result, matcher)
+	}
+	return result, nil
+}
@@ -0,0 +1,193 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestSimulateRouting(t *testing.T) {
+	// Sunday, 03:00 UTC
+	now := time.Date(2024, 6, 2, 3, 0, 0, 0, time.UTC)
+
+	var nights, weekdays []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte("- times: [{start_time: '00:00', end_time: '06:00'}]"), &nights))
+	require.NoError(t, yaml.Unmarshal([]byte("- weekdays: ['monday:friday']"), &weekdays))
+
+	matcher := func(t *testing.T, name, value string) *labels.Matcher {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, value)
+		require.NoError(t, err)
+		return m
+	}
+	groupWait := model.Duration(time.Minute)
+	newConfig := func(t *testing.T) *definitions.GettableApiAlertingConfig {
+		cfg := &definitions.GettableApiAlertingConfig{}
+		cfg.Route = &definitions.Route{
+			Receiver:   "default",
+			GroupByStr: []string{"alertname"},
+			Routes: []*definitions.Route{
+				{
+					Receiver:       "team-a",
+					ObjectMatchers: definitions.ObjectMatchers{matcher(t, "team", "a")},
+					GroupWait:      &groupWait,
+					Continue:       true,
+					Routes: []*definitions.Route{
+						{
+							ObjectMatchers:    definitions.ObjectMatchers{matcher(t, "severity", "low")},
+							GroupByStr:        []string{"..."},
+							MuteTimeIntervals: []string{"nights"},
+						},
+					},
+				},
+				{
+					Receiver:            "on-call",
+					ObjectMatchers:      definitions.ObjectMatchers{matcher(t, "severity", "critical")},
+					ActiveTimeIntervals: []string{"weekdays"},
+				},
+			},
+		}
+		cfg.MuteTimeIntervals = []config.MuteTimeInterval{{Name: "nights", TimeIntervals: nights}}
+		cfg.TimeIntervals = []config.TimeInterval{{Name: "weekdays", TimeIntervals: weekdays}}
+		require.NoError(t, cfg.Route.Validate())
+		return cfg
+	}
+
+	t.Run("should return the matched routes with the inherited settings", func(t *testing.T) {
+		result, err := simulateRouting(newConfig(t), []model.LabelSet{
+			{"alertname": "HighLatency", "team": "a", "severity": "critical"},
+			{"alertname": "HighLatency", "team": "b"},
+		}, nil, now)
+		require.NoError(t, err)
+		assert.Equal(t, now, result.Time)
+		require.Len(t, result.Alerts, 2)
+
+		teamA := result.Alerts[0]
+		require.Len(t, teamA.Routes, 2)
+		assert.Equal(t, definitions.SimulatedRoute{
+			Path: []definitions.SimulatedRoutePolicy{
+				{Receiver: "default"},
+				{Index: util.Pointer(0), Matchers: []string{`team="a"`}, Receiver: "team-a", Continue: true},
+			},
+			Receiver:       "team-a",
+			GroupBy:        []string{"alertname"},
+			GroupWait:      groupWait,
+			GroupInterval:  model.Duration(5 * time.Minute),
+			RepeatInterval: model.Duration(4 * time.Hour),
+		}, teamA.Routes[0])
+		assert.Equal(t, "on-call", teamA.Routes[1].Receiver)
+		assert.Equal(t, []definitions.SimulatedTimeInterval{{Name: "weekdays", Active: false}}, teamA.Routes[1].ActiveTimeIntervals)
+		assert.True(t, teamA.Routes[1].Muted, "the route should be muted outside of its active time intervals")
+		assert.Equal(t, []string{"team-a"}, teamA.Receivers)
+
+		teamB := result.Alerts[1]
+		require.Len(t, teamB.Routes, 1)
+		assert.Equal(t, []definitions.SimulatedRoutePolicy{{Receiver: "default"}}, teamB.Routes[0].Path)
+		assert.Equal(t, []string{"default"}, teamB.Receivers)
+	})
+
+	t.Run("should mute routes in active mute time intervals", func(t *testing.T) {
+		result, err := simulateRouting(newConfig(t), []model.LabelSet{{"alertname": "DiskFull", "team": "a", "severity": "low"}}, nil, now)
+		require.NoError(t, err)
+
+		alert := result.Alerts[0]
+		require.Len(t, alert.Routes, 1)
+		route := alert.Routes[0]
+		assert.Len(t, route.Path, 3)
+		assert.Equal(t, "team-a", route.Receiver)
+		assert.Equal(t, []string{"..."}, route.GroupBy)
+		assert.Equal(t, []definitions.SimulatedTimeInterval{{Name: "nights", Active: true}}, route.MuteTimeIntervals)
+		assert.True(t, route.Muted)
+		assert.Empty(t, alert.Receivers)
+
+		result, err = simulateRouting(newConfig(t), []model.LabelSet{{"alertname": "DiskFull", "team": "a", "severity": "low"}}, nil, now.Add(6*time.Hour))
+		require.NoError(t, err)
+		assert.False(t, result.Alerts[0].Routes[0].Muted)
+		assert.Equal(t, []string{"team-a"}, result.Alerts[0].Receivers)
+	})
+
+	t.Run("should return the active silences that match the alerts", func(t *testing.T) {
+		silence := func(id string, startsAt, endsAt time.Time, name, value string, isEqual bool) *models.Silence {
+			return &models.Silence{
+				ID: util.Pointer(id),
+				Silence: amv2.Silence{
+					Matchers: amv2.Matchers{{
+						Name:    util.Pointer(name),
+						Value:   util.Pointer(value),
+						IsEqual: util.Pointer(isEqual),
+						IsRegex: util.Pointer(false),
+					}},
+					StartsAt:  util.Pointer(strfmt.DateTime(startsAt)),
+					EndsAt:    util.Pointer(strfmt.DateTime(endsAt)),
+					CreatedBy: util.Pointer("admin"),
+					Comment:   util.Pointer("maintenance"),
+				},
+			}
+		}
+		silences := []*models.Silence{
+			silence("active", now.Add(-time.Hour), now.Add(time.Hour), "team", "b", false),
+			silence("expired", now.Add(-2*time.Hour), now, "team", "a", true),
+			silence("pending", now.Add(time.Minute), now.Add(time.Hour), "team", "a", true),
+		}
+
+		result, err := simulateRouting(newConfig(t), []model.LabelSet{
+			{"alertname": "HighLatency", "team": "a"},
+			{"alertname": "HighLatency", "team": "b"},
+		}, silences, now)
+		require.NoError(t, err)
+
+		silenced := result.Alerts[0]
+		assert.True(t, silenced.Silenced)
+		assert.Equal(t, []definitions.SimulatedSilence{{
+			ID:        "active",
+			Matchers:  []string{`team!="b"`},
+			Comment:   "maintenance",
+			CreatedBy: "admin",
+			EndsAt:    now.Add(time.Hour),
+		}}, silenced.Silences)
+		assert.NotEmpty(t, silenced.Routes)
+		assert.Empty(t, silenced.Receivers)
+
+		notSilenced := result.Alerts[1]
+		assert.False(t, notSilenced.Silenced)
+		assert.Empty(t, notSilenced.Silences)
+		assert.Equal(t, []string{"default"}, notSilenced.Receivers)
+	})
+}
+
+func TestValidateSimulatedRoute(t *testing.T) {
+	newConfig := func(route *definitions.Route) *definitions.GettableApiAlertingConfig {
+		cfg := &definitions.GettableApiAlertingConfig{}
+		cfg.Route = route
+		cfg.Receivers = []*definitions.GettableApiReceiver{{Receiver: config.Receiver{Name: "default"}}}
+		cfg.MuteTimeIntervals = []config.MuteTimeInterval{{Name: "nights"}}
+		return cfg
+	}
+
+	assert.NoError(t, validateSimulatedRoute(newConfig(&definitions.Route{
+		Receiver: "default",
+		Routes:   []*definitions.Route{{MuteTimeIntervals: []string{"nights"}}},
+	})))
+	assert.ErrorContains(t, validateSimulatedRoute(newConfig(nil)), "empty")
+	assert.ErrorContains(t, validateSimulatedRoute(newConfig(&definitions.Route{})), "root route must specify a default receiver")
+	assert.ErrorContains(t, validateSimulatedRoute(newConfig(&definitions.Route{
+		Receiver: "default",
+		Routes:   []*definitions.Route{{Receiver: "unknown"}},
+	})), "receiver 'unknown' does not exist")
+	assert.ErrorContains(t, validateSimulatedRoute(newConfig(&definitions.Route{
+		Receiver: "default",
+		Routes:   []*definitions.Route{{ActiveTimeIntervals: []string{"weekdays"}}},
+	})), "active time interval 'weekdays' does not exist")
+}
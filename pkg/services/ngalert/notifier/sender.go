@@ -2,6 +2,8 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 
 	"github.com/grafana/alerting/receivers"
 
@@ -13,6 +15,17 @@ type sender struct {
 }
 
 func (s sender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSettings) error {
+	validation := cmd.Validation
+	if attempt := deliveryAttemptFromContext(ctx); attempt != nil {
+		attempt.recordPayload(cmd.Body)
+		validation = func(body []byte, statusCode int) error {
+			attempt.recordStatusCode(statusCode)
+			if cmd.Validation != nil {
+				return cmd.Validation(body, statusCode)
+			}
+			return nil
+		}
+	}
 	return s.ns.SendWebhookSync(ctx, &notifications.SendWebhookSync{
 		Url:         cmd.URL,
 		User:        cmd.User,
@@ -21,7 +34,7 @@ func (s sender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSetti
 		HttpMethod:  cmd.HTTPMethod,
 		HttpHeader:  cmd.HTTPHeader,
 		ContentType: cmd.ContentType,
-		Validation:  cmd.Validation,
+		Validation:  validation,
 		TLSConfig:   cmd.TLSConfig,
 	})
 }
@@ -45,6 +58,10 @@ func (s sender) SendEmail(ctx context.Context, cmd *receivers.SendEmailSettings)
 			}
 		}
 	}
+	if attempt := deliveryAttemptFromContext(ctx); attempt != nil {
+		data, _ := json.Marshal(cmd.Data)
+		attempt.recordPayload(strings.Join(cmd.To, ","), cmd.Subject, cmd.Template, string(data))
+	}
 	return s.ns.SendEmailCommandHandlerSync(ctx, &notifications.SendEmailCommandSync{
 		SendEmailCommand: sendEmailCommand,
 	})
@@ -0,0 +1,110 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// silenceTemplateInterval is how often the schedules of silence templates are checked.
+	silenceTemplateInterval = time.Minute
+	// silenceTemplateLookahead is how long before the start of a window of the schedule the silence is created, so
+	// that it is already active in all replicas when the window starts.
+	silenceTemplateLookahead = time.Hour
+)
+
+// RecurringSilenceTemplateStore is the store of the silence templates that have a schedule.
+type RecurringSilenceTemplateStore interface {
+	ListRecurringSilenceTemplates(ctx context.Context) ([]models.SilenceTemplate, error)
+	UpdateSilenceTemplateMaterializedUntil(ctx context.Context, orgID int64, uid string, previous, next time.Time) (bool, error)
+}
+
+// SilenceCreator creates silences in the Alertmanager of an organization.
+type SilenceCreator interface {
+	CreateSilence(ctx context.Context, orgID int64, ps models.Silence) (string, error)
+}
+
+// SilenceTemplateScheduler creates the silences of recurring silence templates ahead of every window of their schedule.
+// All replicas run the scheduler, the store makes sure that each window is materialized only once.
+type SilenceTemplateScheduler struct {
+	store    RecurringSilenceTemplateStore
+	silences SilenceCreator
+	clock    clock.Clock
+	log      log.Logger
+}
+
+func NewSilenceTemplateScheduler(store RecurringSilenceTemplateStore, silences SilenceCreator, clk clock.Clock, logger log.Logger) *SilenceTemplateScheduler {
+	return &SilenceTemplateScheduler{
+		store:    store,
+		silences: silences,
+		clock:    clk,
+		log:      logger,
+	}
+}
+
+// Run checks the schedules of the silence templates until the context is cancelled.
+func (s *SilenceTemplateScheduler) Run(ctx context.Context) error {
+	ticker := s.clock.Ticker(silenceTemplateInterval)
+	defer ticker.Stop()
+	for {
+		s.materialize(ctx, s.clock.Now())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SilenceTemplateScheduler) materialize(ctx context.Context, now time.Time) {
+	templates, err := s.store.ListRecurringSilenceTemplates(ctx)
+	if err != nil {
+		s.log.Error("Failed to list recurring silence templates", "error", err)
+		return
+	}
+	for _, template := range templates {
+		if err := s.materializeTemplate(ctx, template, now); err != nil {
+			s.log.Error("Failed to create silence for silence template", "orgID", template.OrgID, "uid", template.UID, "error", err)
+		}
+	}
+}
+
+func (s *SilenceTemplateScheduler) materializeTemplate(ctx context.Context, template models.SilenceTemplate, now time.Time) error {
+	from := now
+	if template.MaterializedUntil.After(from) {
+		from = template.MaterializedUntil
+	}
+	start, end, ok := template.NextWindow(from, now.Add(silenceTemplateLookahead))
+	if !ok {
+		return nil
+	}
+	// The store keeps the end of the window with a precision of seconds.
+	end = end.Truncate(time.Second)
+
+	claimed, err := s.store.UpdateSilenceTemplateMaterializedUntil(ctx, template.OrgID, template.UID, template.MaterializedUntil, end)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		// Another replica created the silence, or the template was changed in the meantime.
+		return nil
+	}
+
+	silence := template.Silence(fmt.Sprintf("silence template %q", template.Title), start, end)
+	silenceID, err := s.silences.CreateSilence(ctx, template.OrgID, silence)
+	if err != nil {
+		// Release the window, so that the silence is created again at the next check.
+		if _, rerr := s.store.UpdateSilenceTemplateMaterializedUntil(ctx, template.OrgID, template.UID, end, template.MaterializedUntil); rerr != nil {
+			s.log.Error("Failed to release the window of silence template", "orgID", template.OrgID, "uid", template.UID, "error", rerr)
+		}
+		return err
+	}
+	s.log.Info("Created silence from silence template", "orgID", template.OrgID, "uid", template.UID, "silenceID", silenceID, "startsAt", start, "endsAt", end)
+	return nil
+}
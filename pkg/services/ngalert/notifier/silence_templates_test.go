@@ -0,0 +1,129 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeRecurringSilenceTemplateStore struct {
+	templates []models.SilenceTemplate
+}
+
+func (f *fakeRecurringSilenceTemplateStore) ListRecurringSilenceTemplates(_ context.Context) ([]models.SilenceTemplate, error) {
+	return f.templates, nil
+}
+
+func (f *fakeRecurringSilenceTemplateStore) UpdateSilenceTemplateMaterializedUntil(_ context.Context, orgID int64, uid string, previous, next time.Time) (bool, error) {
+	for i, t := range f.templates {
+		if t.OrgID == orgID && t.UID == uid && t.MaterializedUntil.Equal(previous) {
+			f.templates[i].MaterializedUntil = next
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeSilenceCreator struct {
+	silences []models.Silence
+	err      error
+}
+
+func (f *fakeSilenceCreator) CreateSilence(_ context.Context, _ int64, ps models.Silence) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.silences = append(f.silences, ps)
+	return util.GenerateShortUID(), nil
+}
+
+func TestSilenceTemplateScheduler(t *testing.T) {
+	var schedule []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte("- weekdays: ['sunday']\n  times: [{start_time: '02:00', end_time: '04:00'}]"), &schedule))
+	template := models.SilenceTemplate{
+		UID:   "maintenance",
+		OrgID: 1,
+		Title: "Weekly maintenance",
+		Matchers: amv2.Matchers{{
+			Name:    util.Pointer("env"),
+			Value:   util.Pointer("maint"),
+			IsEqual: util.Pointer(true),
+			IsRegex: util.Pointer(false),
+		}},
+		Schedule: schedule,
+	}
+	// 2024-06-02 is a Sunday.
+	sunday := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)
+
+	newScheduler := func(silences *fakeSilenceCreator) (*SilenceTemplateScheduler, *fakeRecurringSilenceTemplateStore) {
+		store := &fakeRecurringSilenceTemplateStore{templates: []models.SilenceTemplate{template}}
+		return NewSilenceTemplateScheduler(store, silences, clock.NewMock(), log.NewNopLogger()), store
+	}
+
+	t.Run("does not create a silence before the lookahead", func(t *testing.T) {
+		silences := &fakeSilenceCreator{}
+		s, store := newScheduler(silences)
+		s.materialize(context.Background(), sunday)
+		require.Empty(t, silences.silences)
+		require.True(t, store.templates[0].MaterializedUntil.IsZero())
+	})
+
+	t.Run("creates a silence for the next window once", func(t *testing.T) {
+		silences := &fakeSilenceCreator{}
+		s, store := newScheduler(silences)
+		now := sunday.Add(90 * time.Minute)
+		s.materialize(context.Background(), now)
+		s.materialize(context.Background(), now.Add(time.Minute))
+
+		require.Len(t, silences.silences, 1)
+		silence := silences.silences[0]
+		require.Equal(t, sunday.Add(2*time.Hour), time.Time(*silence.StartsAt))
+		require.Equal(t, sunday.Add(4*time.Hour), time.Time(*silence.EndsAt))
+		require.Equal(t, `silence template "Weekly maintenance"`, *silence.CreatedBy)
+		require.Equal(t, sunday.Add(4*time.Hour), store.templates[0].MaterializedUntil)
+	})
+
+	t.Run("does not create a silence if another replica claimed the window", func(t *testing.T) {
+		silences := &fakeSilenceCreator{}
+		s, store := newScheduler(silences)
+		// The template was read before another replica claimed the window.
+		s.store = &staleSilenceTemplateStore{fakeRecurringSilenceTemplateStore: store, stale: []models.SilenceTemplate{template}}
+		store.templates[0].MaterializedUntil = sunday.Add(4 * time.Hour)
+
+		s.materialize(context.Background(), sunday.Add(90*time.Minute))
+		require.Empty(t, silences.silences)
+	})
+
+	t.Run("releases the window if the silence cannot be created", func(t *testing.T) {
+		silences := &fakeSilenceCreator{err: errors.New("alertmanager not ready")}
+		s, store := newScheduler(silences)
+		now := sunday.Add(90 * time.Minute)
+		s.materialize(context.Background(), now)
+		require.True(t, store.templates[0].MaterializedUntil.IsZero())
+
+		silences.err = nil
+		s.materialize(context.Background(), now.Add(time.Minute))
+		require.Len(t, silences.silences, 1)
+	})
+}
+
+// staleSilenceTemplateStore lists templates as they were before another replica updated them.
+type staleSilenceTemplateStore struct {
+	*fakeRecurringSilenceTemplateStore
+	stale []models.SilenceTemplate
+}
+
+func (f *staleSilenceTemplateStore) ListRecurringSilenceTemplates(_ context.Context) ([]models.SilenceTemplate, error) {
+	return f.stale, nil
+}
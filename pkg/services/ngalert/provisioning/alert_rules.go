@@ -219,6 +219,9 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, user ident
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := service.validateRuleDependencies(ctx, rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	rule.Updated = time.Now()
 	if len(rule.NotificationSettings) > 0 {
 		validator, err := service.nsValidatorProvider.Validator(ctx, rule.OrgID)
@@ -364,55 +367,76 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user ident
 }
 
 func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, user identity.Requester, group models.AlertRuleGroup, provenance models.Provenance) error {
-	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
+	delta, err := service.CalculateRuleGroupChanges(ctx, user, group)
+	if err != nil {
 		return err
 	}
 
-	for _, rule := range group.Rules {
+	if delta.IsEmpty() {
+		return nil
+	}
+
+	newOrUpdatedNotificationSettings := delta.NewOrUpdatedNotificationSettings()
+	if len(newOrUpdatedNotificationSettings) > 0 {
+		validator, err := service.nsValidatorProvider.Validator(ctx, delta.GroupKey.OrgID)
+		if err != nil {
+			return err
+		}
+		for _, s := range newOrUpdatedNotificationSettings {
+			if err := validator.Validate(s); err != nil {
+				return errors.Join(models.ErrAlertRuleFailedValidation, err)
+			}
+		}
+	}
+
+	return service.persistDelta(ctx, user, delta, provenance)
+}
+
+// CalculateRuleGroupChanges validates the rule group and returns the changes that ReplaceRuleGroup would make to the
+// stored rules, without persisting them. The notification settings of the rules are not validated against the
+// current Alertmanager configuration.
+func (service *AlertRuleService) CalculateRuleGroupChanges(ctx context.Context, user identity.Requester, group models.AlertRuleGroup) (*store.GroupDelta, error) {
+	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
+		return nil, err
+	}
+
+	groupRules := make([]*models.AlertRule, 0, len(group.Rules))
+	for i, rule := range group.Rules {
+		groupRules = append(groupRules, &group.Rules[i])
 		if rule.UID == "" {
 			// if empty the UID will be generated before save
 			continue
 		}
 		if err := util.ValidateUID(rule.UID); err != nil {
-			return fmt.Errorf("%w: cannot create rule with UID %q: %w", models.ErrAlertRuleFailedValidation, rule.UID, err)
+			return nil, fmt.Errorf("%w: cannot create rule with UID %q: %w", models.ErrAlertRuleFailedValidation, rule.UID, err)
 		}
 	}
+	if err := models.ValidateRuleGroupDependencies(groupRules); err != nil {
+		return nil, err
+	}
 
 	delta, err := service.calcDelta(ctx, user, group)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if delta.IsEmpty() {
-		return nil
+		return delta, nil
 	}
 
 	// check if the current user has permissions to all rules and can bypass the regular authorization validation.
 	can, err := service.authz.CanWriteAllRules(ctx, user)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !can {
 		if err := service.authz.AuthorizeRuleGroupWrite(ctx, user, delta); err != nil {
-			return err
-		}
-	}
-
-	newOrUpdatedNotificationSettings := delta.NewOrUpdatedNotificationSettings()
-	if len(newOrUpdatedNotificationSettings) > 0 {
-		validator, err := service.nsValidatorProvider.Validator(ctx, delta.GroupKey.OrgID)
-		if err != nil {
-			return err
-		}
-		for _, s := range newOrUpdatedNotificationSettings {
-			if err := validator.Validate(s); err != nil {
-				return errors.Join(models.ErrAlertRuleFailedValidation, err)
-			}
+			return nil, err
 		}
 	}
 
-	return service.persistDelta(ctx, user, delta, provenance)
+	return delta, nil
 }
 
 func (service *AlertRuleService) DeleteRuleGroup(ctx context.Context, user identity.Requester, namespaceUID, group string, provenance models.Provenance) error {
@@ -631,6 +655,9 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user ident
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := service.validateRuleDependencies(ctx, rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err := service.ruleStore.UpdateAlertRules(ctx, userUidOrFallback(user), []models.UpdateRule{
 			{
@@ -686,6 +713,34 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, user ident
 }
 
 // checkLimitsTransactionCtx checks whether the current transaction (as identified by the ctx) breaches configured alert rule limits.
+// validateRuleDependencies validates the dependencies of the rule group the rule is created in or moved to, as if the
+// rule was already saved.
+func (service *AlertRuleService) validateRuleDependencies(ctx context.Context, rule models.AlertRule) error {
+	q := &models.ListAlertRulesQuery{
+		OrgID:         rule.OrgID,
+		NamespaceUIDs: []string{rule.NamespaceUID},
+		RuleGroups:    []string{rule.RuleGroup},
+	}
+	existing, err := service.ruleStore.ListAlertRules(ctx, q)
+	if err != nil {
+		return err
+	}
+	hasDependencies := len(rule.Dependencies) > 0
+	groupRules := make([]*models.AlertRule, 0, len(existing)+1)
+	groupRules = append(groupRules, &rule)
+	for _, r := range existing {
+		if r.UID == rule.UID {
+			continue
+		}
+		hasDependencies = hasDependencies || len(r.Dependencies) > 0
+		groupRules = append(groupRules, r)
+	}
+	if !hasDependencies {
+		return nil
+	}
+	return models.ValidateRuleGroupDependencies(groupRules)
+}
+
 func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context, user identity.Requester) error {
 	// default to 0 if there is no user
 	var userID int64
@@ -119,6 +119,60 @@ func TestAlertRuleService(t *testing.T) {
 		}
 	})
 
+	t.Run("calculating group changes should not persist them", func(t *testing.T) {
+		ruleService := createAlertRuleService(t, nil)
+		group := createDummyGroup("group-test-changes", orgID)
+
+		delta, err := ruleService.CalculateRuleGroupChanges(context.Background(), u, group)
+		require.NoError(t, err)
+		require.Len(t, delta.New, len(group.Rules))
+		require.Empty(t, delta.Update)
+		require.Empty(t, delta.Delete)
+
+		_, err = ruleService.GetRuleGroup(context.Background(), u, "my-namespace", "group-test-changes")
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+
+		err = ruleService.ReplaceRuleGroup(context.Background(), u, group, models.ProvenanceAPI)
+		require.NoError(t, err)
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), u, "my-namespace", "group-test-changes")
+		require.NoError(t, err)
+
+		readGroup.Rules[0].Title = "new title"
+		delta, err = ruleService.CalculateRuleGroupChanges(context.Background(), u, readGroup)
+		require.NoError(t, err)
+		require.Empty(t, delta.New)
+		require.Empty(t, delta.Delete)
+		require.Len(t, delta.Update, 1)
+		require.Equal(t, "Title", delta.Update[0].Diff.Paths()[0])
+	})
+
+	t.Run("rule dependencies should be validated", func(t *testing.T) {
+		group := createDummyGroup("group-test-dependencies", orgID)
+		group.Rules = append(group.Rules, dummyRule("group-test-dependencies-rule-2", orgID))
+		group.Rules[0].UID = "dependency-1"
+		group.Rules[1].UID = "dependency-2"
+		group.Rules[0].Dependencies = []models.RuleDependency{{RuleUID: "dependency-2"}}
+		group.Rules[1].Dependencies = []models.RuleDependency{{RuleUID: "dependency-1"}}
+
+		err := ruleService.ReplaceRuleGroup(context.Background(), u, group, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "cycle")
+
+		group.Rules[0].Dependencies = nil
+		err = ruleService.ReplaceRuleGroup(context.Background(), u, group, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		rule := dummyRule("group-test-dependencies-rule-3", orgID)
+		rule.RuleGroup = group.Title
+		rule.Dependencies = []models.RuleDependency{{RuleUID: "dependency-3"}}
+		_, err = ruleService.CreateAlertRule(context.Background(), u, rule, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		rule.Dependencies = []models.RuleDependency{{RuleUID: "dependency-2"}}
+		_, err = ruleService.CreateAlertRule(context.Background(), u, rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+	})
+
 	t.Run("alert rule should get interval from existing rule group", func(t *testing.T) {
 		rule := dummyRule("test#4", orgID)
 		rule.RuleGroup = "b"
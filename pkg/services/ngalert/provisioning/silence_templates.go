@@ -0,0 +1,148 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+)
+
+// SilenceTemplateStore is the store of silence templates.
+type SilenceTemplateStore interface {
+	ListSilenceTemplates(ctx context.Context, orgID int64) ([]models.SilenceTemplate, error)
+	GetSilenceTemplate(ctx context.Context, orgID int64, uid string) (models.SilenceTemplate, error)
+	InsertSilenceTemplate(ctx context.Context, template models.SilenceTemplate) (models.SilenceTemplate, error)
+	UpdateSilenceTemplate(ctx context.Context, template models.SilenceTemplate) (models.SilenceTemplate, error)
+	DeleteSilenceTemplate(ctx context.Context, orgID int64, uid string) error
+}
+
+type SilenceTemplateService struct {
+	store           SilenceTemplateStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+	validator       validation.ProvenanceStatusTransitionValidator
+}
+
+func NewSilenceTemplateService(store SilenceTemplateStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *SilenceTemplateService {
+	return &SilenceTemplateService{
+		store:           store,
+		provenanceStore: prov,
+		xact:            xact,
+		log:             log,
+		validator:       validation.ValidateProvenanceRelaxed,
+	}
+}
+
+// GetSilenceTemplates returns all silence templates of the organization and their provenances by UID.
+func (svc *SilenceTemplateService) GetSilenceTemplates(ctx context.Context, orgID int64) ([]models.SilenceTemplate, map[string]models.Provenance, error) {
+	templates, err := svc.store.ListSilenceTemplates(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&models.SilenceTemplate{}).ResourceType())
+	if err != nil {
+		return nil, nil, err
+	}
+	return templates, provenances, nil
+}
+
+// GetSilenceTemplate returns a silence template by UID and its provenance.
+func (svc *SilenceTemplateService) GetSilenceTemplate(ctx context.Context, orgID int64, uid string) (models.SilenceTemplate, models.Provenance, error) {
+	template, err := svc.store.GetSilenceTemplate(ctx, orgID, uid)
+	if err != nil {
+		return models.SilenceTemplate{}, models.ProvenanceNone, err
+	}
+	provenance, err := svc.provenanceStore.GetProvenance(ctx, &template, orgID)
+	if err != nil {
+		return models.SilenceTemplate{}, models.ProvenanceNone, err
+	}
+	return template, provenance, nil
+}
+
+// CreateSilenceTemplate creates a new silence template within the specified org. The created template is returned.
+func (svc *SilenceTemplateService) CreateSilenceTemplate(ctx context.Context, orgID int64, template models.SilenceTemplate, provenance models.Provenance) (models.SilenceTemplate, error) {
+	template.OrgID = orgID
+	if err := template.Validate(); err != nil {
+		return models.SilenceTemplate{}, err
+	}
+
+	var created models.SilenceTemplate
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = svc.store.InsertSilenceTemplate(ctx, template)
+		if err != nil {
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &created, orgID, provenance)
+	})
+	if err != nil {
+		return models.SilenceTemplate{}, err
+	}
+	return created, nil
+}
+
+// UpdateSilenceTemplate replaces an existing silence template within the specified org. The replaced template is
+// returned. Silences that were already created from the template are not changed.
+func (svc *SilenceTemplateService) UpdateSilenceTemplate(ctx context.Context, orgID int64, template models.SilenceTemplate, provenance models.Provenance) (models.SilenceTemplate, error) {
+	template.OrgID = orgID
+	if err := template.Validate(); err != nil {
+		return models.SilenceTemplate{}, err
+	}
+
+	existing, err := svc.store.GetSilenceTemplate(ctx, orgID, template.UID)
+	if err != nil {
+		return models.SilenceTemplate{}, err
+	}
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, &existing, orgID)
+	if err != nil {
+		return models.SilenceTemplate{}, err
+	}
+	if err := svc.validator(storedProvenance, provenance); err != nil {
+		return models.SilenceTemplate{}, err
+	}
+
+	var updated models.SilenceTemplate
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		updated, err = svc.store.UpdateSilenceTemplate(ctx, template)
+		if err != nil {
+			return err
+		}
+		return svc.provenanceStore.SetProvenance(ctx, &updated, orgID, provenance)
+	})
+	if err != nil {
+		return models.SilenceTemplate{}, err
+	}
+	updated.MaterializedUntil = existing.MaterializedUntil
+	return updated, nil
+}
+
+// DeleteSilenceTemplate deletes a silence template by UID. Silences that were already created from the template
+// are not expired.
+func (svc *SilenceTemplateService) DeleteSilenceTemplate(ctx context.Context, orgID int64, uid string, provenance models.Provenance) error {
+	existing, err := svc.store.GetSilenceTemplate(ctx, orgID, uid)
+	if err != nil {
+		if errors.Is(err, models.ErrSilenceTemplateNotFound) {
+			svc.log.FromContext(ctx).Debug("Silence template was not found. Skip deleting", "uid", uid)
+			return nil
+		}
+		return err
+	}
+	storedProvenance, err := svc.provenanceStore.GetProvenance(ctx, &existing, orgID)
+	if err != nil {
+		return err
+	}
+	if err := svc.validator(storedProvenance, provenance); err != nil {
+		return err
+	}
+
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteSilenceTemplate(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.provenanceStore.DeleteProvenance(ctx, &existing, orgID)
+	})
+}
@@ -0,0 +1,162 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeSilenceTemplateStore struct {
+	templates map[string]models.SilenceTemplate
+}
+
+func (f *fakeSilenceTemplateStore) ListSilenceTemplates(_ context.Context, orgID int64) ([]models.SilenceTemplate, error) {
+	result := make([]models.SilenceTemplate, 0, len(f.templates))
+	for _, t := range f.templates {
+		if t.OrgID == orgID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeSilenceTemplateStore) GetSilenceTemplate(_ context.Context, orgID int64, uid string) (models.SilenceTemplate, error) {
+	t, ok := f.templates[uid]
+	if !ok || t.OrgID != orgID {
+		return models.SilenceTemplate{}, models.ErrSilenceTemplateNotFound.Errorf("")
+	}
+	return t, nil
+}
+
+func (f *fakeSilenceTemplateStore) InsertSilenceTemplate(_ context.Context, t models.SilenceTemplate) (models.SilenceTemplate, error) {
+	if t.UID == "" {
+		t.UID = util.GenerateShortUID()
+	}
+	f.templates[t.UID] = t
+	return t, nil
+}
+
+func (f *fakeSilenceTemplateStore) UpdateSilenceTemplate(_ context.Context, t models.SilenceTemplate) (models.SilenceTemplate, error) {
+	existing, ok := f.templates[t.UID]
+	if !ok || existing.OrgID != t.OrgID {
+		return models.SilenceTemplate{}, models.ErrSilenceTemplateNotFound.Errorf("")
+	}
+	t.MaterializedUntil = existing.MaterializedUntil
+	f.templates[t.UID] = t
+	return t, nil
+}
+
+func (f *fakeSilenceTemplateStore) DeleteSilenceTemplate(_ context.Context, _ int64, uid string) error {
+	delete(f.templates, uid)
+	return nil
+}
+
+func createSilenceTemplateSvcSut() (*SilenceTemplateService, *fakeSilenceTemplateStore, *fakes.FakeProvisioningStore) {
+	store := &fakeSilenceTemplateStore{templates: map[string]models.SilenceTemplate{}}
+	prov := fakes.NewFakeProvisioningStore()
+	return &SilenceTemplateService{
+		store:           store,
+		provenanceStore: prov,
+		xact:            newNopTransactionManager(),
+		log:             log.NewNopLogger(),
+		validator:       validation.ValidateProvenanceRelaxed,
+	}, store, prov
+}
+
+func TestSilenceTemplateService(t *testing.T) {
+	orgID := int64(1)
+	template := models.SilenceTemplate{
+		Title: "Maintenance",
+		Matchers: amv2.Matchers{{
+			Name:    util.Pointer("env"),
+			Value:   util.Pointer("maint"),
+			IsEqual: util.Pointer(true),
+			IsRegex: util.Pointer(false),
+		}},
+		Duration: 2 * time.Hour,
+	}
+
+	t.Run("creates a template with provenance", func(t *testing.T) {
+		sut, _, _ := createSilenceTemplateSvcSut()
+		created, err := sut.CreateSilenceTemplate(context.Background(), orgID, template, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+
+		got, provenance, err := sut.GetSilenceTemplate(context.Background(), orgID, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, created, got)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+
+		all, provenances, err := sut.GetSilenceTemplates(context.Background(), orgID)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, models.ProvenanceAPI, provenances[created.UID])
+	})
+
+	t.Run("rejects invalid templates", func(t *testing.T) {
+		sut, _, _ := createSilenceTemplateSvcSut()
+		invalid := template
+		invalid.Duration = 0
+		_, err := sut.CreateSilenceTemplate(context.Background(), orgID, invalid, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrSilenceTemplateInvalid)
+	})
+
+	t.Run("updates a template and keeps the materialized windows", func(t *testing.T) {
+		sut, store, _ := createSilenceTemplateSvcSut()
+		created, err := sut.CreateSilenceTemplate(context.Background(), orgID, template, models.ProvenanceNone)
+		require.NoError(t, err)
+		materializedUntil := time.Date(2024, 6, 2, 4, 0, 0, 0, time.UTC)
+		stored := store.templates[created.UID]
+		stored.MaterializedUntil = materializedUntil
+		store.templates[created.UID] = stored
+
+		update := created
+		update.Title = "Updated"
+		updated, err := sut.UpdateSilenceTemplate(context.Background(), orgID, update, models.ProvenanceNone)
+		require.NoError(t, err)
+		require.Equal(t, "Updated", updated.Title)
+		require.Equal(t, materializedUntil, updated.MaterializedUntil)
+	})
+
+	t.Run("fails to update a template that does not exist", func(t *testing.T) {
+		sut, _, _ := createSilenceTemplateSvcSut()
+		missing := template
+		missing.UID = "missing"
+		_, err := sut.UpdateSilenceTemplate(context.Background(), orgID, missing, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrSilenceTemplateNotFound)
+	})
+
+	t.Run("does not change provisioned templates through the API", func(t *testing.T) {
+		sut, _, _ := createSilenceTemplateSvcSut()
+		created, err := sut.CreateSilenceTemplate(context.Background(), orgID, template, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = sut.UpdateSilenceTemplate(context.Background(), orgID, created, models.ProvenanceNone)
+		require.Error(t, err)
+		err = sut.DeleteSilenceTemplate(context.Background(), orgID, created.UID, models.ProvenanceNone)
+		require.Error(t, err)
+	})
+
+	t.Run("deletes a template and its provenance", func(t *testing.T) {
+		sut, store, prov := createSilenceTemplateSvcSut()
+		created, err := sut.CreateSilenceTemplate(context.Background(), orgID, template, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.DeleteSilenceTemplate(context.Background(), orgID, created.UID, models.ProvenanceAPI))
+		require.Empty(t, store.templates)
+		provenance, err := prov.GetProvenance(context.Background(), &created, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+
+		require.NoError(t, sut.DeleteSilenceTemplate(context.Background(), orgID, created.UID, models.ProvenanceAPI))
+	})
+}
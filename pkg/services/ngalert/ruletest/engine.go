@@ -0,0 +1,308 @@
+package ruletest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var (
+	ErrInvalidTestFile = errors.New("invalid test file")
+
+	logger = log.New("ngalert.ruletest")
+)
+
+const (
+	defaultEvaluationInterval = time.Minute
+	evaluationTimeout         = 30 * time.Second
+	// maxEvaluations limits the number of evaluations of a test, so that a test cannot keep the server busy.
+	maxEvaluations = 10000
+)
+
+// Engine runs unit tests of alert rules. The rules are evaluated by the same evaluator and state manager as the
+// scheduler, but the data source queries are answered with the input series of the tests.
+type Engine struct {
+	appURL      *url.URL
+	evalFactory eval.EvaluatorFactory
+	tracer      tracing.Tracer
+}
+
+func NewEngine(appURL *url.URL, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer) *Engine {
+	exprService := expr.NewOfflineService(cfg, features, tracer)
+	return &Engine{
+		appURL: appURL,
+		evalFactory: eval.NewEvaluatorFactory(
+			setting.UnifiedAlertingSettings{EvaluationTimeout: evaluationTimeout},
+			offlineDatasources{},
+			exprService,
+		),
+		tracer: tracer,
+	}
+}
+
+// Run runs the tests of the file against the rule groups of the file. The rule files of the test file must already be
+// loaded into its groups. It returns ErrInvalidTestFile if the tests cannot be run.
+func (e *Engine) Run(ctx context.Context, orgID int64, file definitions.RuleUnitTestFile) (definitions.RuleUnitTestResults, error) {
+	rules, err := rulesFromGroups(orgID, file.Groups)
+	if err != nil {
+		return definitions.RuleUnitTestResults{}, err
+	}
+	if len(rules) == 0 {
+		return definitions.RuleUnitTestResults{}, fmt.Errorf("%w: no rules to test", ErrInvalidTestFile)
+	}
+	if len(file.Tests) == 0 {
+		return definitions.RuleUnitTestResults{}, fmt.Errorf("%w: no tests", ErrInvalidTestFile)
+	}
+
+	results := definitions.RuleUnitTestResults{Passed: true}
+	for idx, test := range file.Tests {
+		name := test.Name
+		if name == "" {
+			name = fmt.Sprintf("test %d", idx+1)
+		}
+		failures, err := e.runTest(ctx, orgID, file.EvaluationInterval, rules, test)
+		if err != nil {
+			return definitions.RuleUnitTestResults{}, fmt.Errorf("%s: %w", name, err)
+		}
+		results.Tests = append(results.Tests, definitions.RuleUnitTestResult{
+			Name:     name,
+			Passed:   len(failures) == 0,
+			Failures: failures,
+		})
+		results.Passed = results.Passed && len(failures) == 0
+	}
+	return results, nil
+}
+
+func (e *Engine) runTest(ctx context.Context, orgID int64, evaluationInterval model.Duration, rules []rule, test definitions.RuleUnitTest) ([]string, error) {
+	interval := time.Duration(evaluationInterval)
+	if interval <= 0 {
+		interval = time.Duration(rules[0].IntervalSeconds) * time.Second
+	}
+	if interval <= 0 {
+		interval = defaultEvaluationInterval
+	}
+	seriesInterval := time.Duration(test.Interval)
+	if seriesInterval <= 0 {
+		seriesInterval = interval
+	}
+
+	inputs := make(map[string][]series, len(test.InputSeries))
+	for _, s := range test.InputSeries {
+		parsed, err := parseSeries(s)
+		if err != nil {
+			return nil, err
+		}
+		inputs[parsed.refID] = append(inputs[parsed.refID], parsed)
+	}
+
+	cases := slices.Clone(test.AlertTests)
+	sort.SliceStable(cases, func(i, j int) bool { return cases[i].EvalTime < cases[j].EvalTime })
+	caseRules := make([]rule, 0, len(cases))
+	for _, c := range cases {
+		r, err := findRule(rules, c)
+		if err != nil {
+			return nil, err
+		}
+		if c.EvalTime < 0 {
+			return nil, fmt.Errorf("%w: eval_time must not be negative", ErrInvalidTestFile)
+		}
+		caseRules = append(caseRules, r)
+	}
+	if len(cases) == 0 {
+		return nil, nil
+	}
+	evaluations := int(time.Duration(cases[len(cases)-1].EvalTime)/interval) + 1
+	if evaluations > maxEvaluations {
+		return nil, fmt.Errorf("%w: the test requires %d evaluations, the maximum is %d", ErrInvalidTestFile, evaluations, maxEvaluations)
+	}
+
+	manager := e.newStateManager()
+	user := schedule.SchedulerUserFor(orgID)
+	start := time.Unix(0, 0).UTC()
+	var failures []string
+	next := 0
+	for i := 0; i < evaluations && next < len(cases); i++ {
+		now := start.Add(time.Duration(i) * interval)
+		for _, r := range rules {
+			if r.IsPaused || r.Type() == models.RuleTypeRecording {
+				continue
+			}
+			if err := e.evaluate(ctx, manager, user, r, inputs, start, seriesInterval, now); err != nil {
+				return nil, err
+			}
+		}
+		// Check all cases whose eval_time is before the next evaluation.
+		for next < len(cases) && time.Duration(cases[next].EvalTime) < time.Duration(i+1)*interval {
+			failures = append(failures, compare(cases[next], caseRules[next], manager.GetStatesForRuleUID(orgID, caseRules[next].UID))...)
+			next++
+		}
+	}
+	return failures, nil
+}
+
+func (e *Engine) newStateManager() *state.Manager {
+	cfg := state.ManagerCfg{
+		Metrics:       nil,
+		ExternalURL:   e.appURL,
+		InstanceStore: nil,
+		Images:        &backtesting.NoopImageService{},
+		Clock:         clock.New(),
+		Historian:     nil,
+		Tracer:        e.tracer,
+		Log:           log.New("ngalert.state.manager"),
+	}
+	return state.NewManager(cfg, state.NewNoopPersister())
+}
+
+func (e *Engine) evaluate(ctx context.Context, manager *state.Manager, user identity.Requester, r rule, inputs map[string][]series, start time.Time, seriesInterval time.Duration, now time.Time) error {
+	ruleCtx := models.WithRuleKey(ctx, r.GetKey())
+	reader := &schedule.AlertingResultsFromRuleState{Manager: manager, Rule: r.AlertRule}
+	evaluator, err := e.evalFactory.Create(eval.NewContextWithPreviousResults(ruleCtx, user, reader), r.GetEvalCondition().WithSource("unit-test"))
+	if err != nil {
+		return fmt.Errorf("%w: rule %q: %s", ErrInvalidTestFile, r.Title, err)
+	}
+
+	responses := make(map[string]backend.DataResponse, len(r.Data))
+	for _, q := range r.Data {
+		s, ok := inputs[q.RefID]
+		if !ok || expr.NodeTypeFromDatasourceUID(q.DatasourceUID) != expr.TypeDatasourceNode {
+			continue
+		}
+		from := now.Add(-time.Duration(q.RelativeTimeRange.From))
+		to := now.Add(-time.Duration(q.RelativeTimeRange.To))
+		responses[q.RefID] = response(s, start, seriesInterval, from, to)
+	}
+
+	results, err := evaluator.Evaluate(expr.WithQueryResponses(ruleCtx, responses), now)
+	if err != nil {
+		results = eval.Results{eval.NewResultFromError(err, now, 0)}
+	}
+	includeFolder := r.folderTitle != ""
+	manager.ProcessEvalResults(ruleCtx, now, r.AlertRule, results, state.GetRuleExtraLabels(logger, r.AlertRule, r.folderTitle, includeFolder), nil)
+	return nil
+}
+
+func findRule(rules []rule, c definitions.RuleUnitTestCase) (rule, error) {
+	for _, r := range rules {
+		if (c.RuleUID != "" && r.UID == c.RuleUID) || (c.RuleUID == "" && c.RuleTitle != "" && r.Title == c.RuleTitle) {
+			if r.Type() == models.RuleTypeRecording {
+				return rule{}, fmt.Errorf("%w: rule %q is a recording rule", ErrInvalidTestFile, r.Title)
+			}
+			return r, nil
+		}
+	}
+	if c.RuleUID == "" && c.RuleTitle == "" {
+		return rule{}, fmt.Errorf("%w: alert rule test must specify rule_uid or rule_title", ErrInvalidTestFile)
+	}
+	return rule{}, fmt.Errorf("%w: rule %s%s not found", ErrInvalidTestFile, c.RuleUID, c.RuleTitle)
+}
+
+// compare returns a failure for every expected alert that was not found and every alert that was not expected.
+func compare(c definitions.RuleUnitTestCase, r rule, states []*state.State) []string {
+	var actual []*state.State
+	for _, s := range states {
+		if s.State != eval.Normal {
+			actual = append(actual, s)
+		}
+	}
+
+	var failures []string
+	prefix := fmt.Sprintf("eval_time %s, rule %q", c.EvalTime, r.Title)
+	matched := make([]bool, len(actual))
+	for _, exp := range c.ExpAlerts {
+		found := false
+		for i, s := range actual {
+			if !matched[i] && matches(exp, s) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			failures = append(failures, fmt.Sprintf("%s: expected alert %s was not found", prefix, formatExpected(exp)))
+		}
+	}
+	for i, s := range actual {
+		if !matched[i] {
+			failures = append(failures, fmt.Sprintf("%s: unexpected alert %s", prefix, formatState(s)))
+		}
+	}
+	return failures
+}
+
+func matches(exp definitions.RuleUnitTestAlert, s *state.State) bool {
+	if !strings.EqualFold(exp.State, s.State.String()) {
+		return false
+	}
+	if !maps.Equal(publicKeys(exp.Labels), publicKeys(s.Labels)) {
+		return false
+	}
+	return exp.Annotations == nil || maps.Equal(publicKeys(exp.Annotations), publicKeys(s.Annotations))
+}
+
+// publicKeys returns the labels or annotations without the private ones that start with "__".
+func publicKeys(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		if !strings.HasPrefix(k, "__") {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func formatExpected(exp definitions.RuleUnitTestAlert) string {
+	result := exp.State + " " + data.Labels(publicKeys(exp.Labels)).String()
+	if exp.Annotations != nil {
+		result += " annotations " + data.Labels(publicKeys(exp.Annotations)).String()
+	}
+	return result
+}
+
+func formatState(s *state.State) string {
+	result := s.State.String() + " " + data.Labels(publicKeys(s.Labels)).String()
+	if s.StateReason != "" {
+		result += " (" + s.StateReason + ")"
+	}
+	if s.Error != nil {
+		result += " error: " + s.Error.Error()
+	}
+	return result + " annotations " + data.Labels(publicKeys(s.Annotations)).String()
+}
+
+// offlineDatasources resolves every data source UID, so that rules can be evaluated without the data sources.
+type offlineDatasources struct{}
+
+func (offlineDatasources) GetDatasource(_ context.Context, id int64, _ identity.Requester, _ bool) (*datasources.DataSource, error) {
+	return nil, fmt.Errorf("data source %d: %w", id, datasources.ErrDataSourceNotFound)
+}
+
+func (offlineDatasources) GetDatasourceByUID(_ context.Context, uid string, user identity.Requester, _ bool) (*datasources.DataSource, error) {
+	return &datasources.DataSource{UID: uid, OrgID: user.GetOrgID(), Type: "ruletest", Name: uid}, nil
+}
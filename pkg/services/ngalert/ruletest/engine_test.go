@@ -0,0 +1,192 @@
+package ruletest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testRules = `
+groups:
+  - name: api
+    folder: Services
+    interval: 1m
+    rules:
+      - uid: high-latency
+        title: HighLatency
+        condition: C
+        for: 2m
+        labels:
+          severity: page
+        annotations:
+          summary: 'Latency of {{ $labels.job }} is {{ $values.B.Value }}'
+        data:
+          - refId: A
+            relativeTimeRange:
+              from: 300
+              to: 0
+            datasourceUid: prometheus
+            model:
+              expr: latency_seconds
+          - refId: B
+            datasourceUid: __expr__
+            model:
+              type: reduce
+              expression: A
+              reducer: last
+          - refId: C
+            datasourceUid: __expr__
+            model:
+              type: threshold
+              expression: B
+              conditions:
+                - evaluator:
+                    type: gt
+                    params: [1]
+`
+
+func newTestEngine() *Engine {
+	return NewEngine(nil, &setting.Cfg{ExpressionsEnabled: true}, featuremgmt.WithFeatures(), tracing.InitializeTracerForTest())
+}
+
+func loadTestFile(t *testing.T, tests string) definitions.RuleUnitTestFile {
+	t.Helper()
+	var file definitions.RuleUnitTestFile
+	require.NoError(t, yaml.Unmarshal([]byte(testRules+tests), &file))
+	return file
+}
+
+func TestEngineRun(t *testing.T) {
+	t.Run("passes when the alerts are as expected", func(t *testing.T) {
+		file := loadTestFile(t, `
+tests:
+  - name: latency above threshold
+    input_series:
+      - ref_id: A
+        labels:
+          job: api
+        values: '0.5 2x5'
+      - ref_id: A
+        labels:
+          job: web
+        values: '0.5x5'
+    alert_rule_test:
+      - eval_time: 0m
+        rule_title: HighLatency
+      - eval_time: 1m
+        rule_uid: high-latency
+        exp_alerts:
+          - state: Pending
+            labels:
+              alertname: HighLatency
+              grafana_folder: Services
+              job: api
+              severity: page
+      - eval_time: 3m30s
+        rule_uid: high-latency
+        exp_alerts:
+          - state: Alerting
+            labels:
+              alertname: HighLatency
+              grafana_folder: Services
+              job: api
+              severity: page
+            annotations:
+              summary: Latency of api is 2
+`)
+		results, err := newTestEngine().Run(context.Background(), 1, file)
+		require.NoError(t, err)
+		require.True(t, results.Passed, "%v", results.Tests)
+		require.Equal(t, []definitions.RuleUnitTestResult{{Name: "latency above threshold", Passed: true}}, results.Tests)
+	})
+
+	t.Run("reports missing and unexpected alerts", func(t *testing.T) {
+		file := loadTestFile(t, `
+tests:
+  - input_series:
+      - ref_id: A
+        labels:
+          job: api
+        values: '2x5'
+    alert_rule_test:
+      - eval_time: 1m
+        rule_uid: high-latency
+        exp_alerts:
+          - state: Alerting
+            labels:
+              job: api
+`)
+		results, err := newTestEngine().Run(context.Background(), 1, file)
+		require.NoError(t, err)
+		require.False(t, results.Passed)
+		require.Len(t, results.Tests, 1)
+		require.Equal(t, "test 1", results.Tests[0].Name)
+		require.Len(t, results.Tests[0].Failures, 2)
+		require.Contains(t, results.Tests[0].Failures[0], "expected alert Alerting")
+		require.Contains(t, results.Tests[0].Failures[1], "unexpected alert Pending")
+	})
+
+	t.Run("evaluates to error if a query has no input series", func(t *testing.T) {
+		file := loadTestFile(t, `
+tests:
+  - alert_rule_test:
+      - eval_time: 0m
+        rule_uid: high-latency
+        exp_alerts:
+          - state: Error
+            labels:
+              alertname: HighLatency
+              datasource_uid: prometheus
+              grafana_folder: Services
+              ref_id: A
+              severity: page
+`)
+		results, err := newTestEngine().Run(context.Background(), 1, file)
+		require.NoError(t, err)
+		require.True(t, results.Passed, "%v", results.Tests)
+	})
+
+	t.Run("fails if the test file is invalid", func(t *testing.T) {
+		testCases := map[string]string{
+			"unknown rule": `
+tests:
+  - alert_rule_test:
+      - eval_time: 1m
+        rule_uid: unknown
+`,
+			"no rule reference": `
+tests:
+  - alert_rule_test:
+      - eval_time: 1m
+`,
+			"invalid values": `
+tests:
+  - input_series:
+      - ref_id: A
+        values: 'abc'
+`,
+			"too many evaluations": `
+tests:
+  - alert_rule_test:
+      - eval_time: 1000d
+        rule_uid: high-latency
+`,
+		}
+		for name, tests := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := newTestEngine().Run(context.Background(), 1, loadTestFile(t, tests))
+				require.ErrorIs(t, err, ErrInvalidTestFile)
+			})
+		}
+
+		_, err := newTestEngine().Run(context.Background(), 1, definitions.RuleUnitTestFile{})
+		require.ErrorIs(t, err, ErrInvalidTestFile)
+	})
+}
@@ -0,0 +1,96 @@
+package ruletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// rule is an alert rule under test with the title of its folder.
+type rule struct {
+	*models.AlertRule
+	folderTitle string
+}
+
+func rulesFromGroups(orgID int64, groups []definitions.AlertRuleGroupExport) ([]rule, error) {
+	var result []rule
+	for _, group := range groups {
+		for idx, r := range group.Rules {
+			converted, err := alertRuleFromExport(orgID, group, idx, r)
+			if err != nil {
+				return nil, fmt.Errorf("%w: rule %q of group %q: %s", ErrInvalidTestFile, r.Title, group.Name, err)
+			}
+			result = append(result, rule{AlertRule: converted, folderTitle: group.Folder})
+		}
+	}
+	return result, nil
+}
+
+func alertRuleFromExport(orgID int64, group definitions.AlertRuleGroupExport, idx int, r definitions.AlertRuleExport) (*models.AlertRule, error) {
+	result := &models.AlertRule{
+		UID:             r.UID,
+		OrgID:           orgID,
+		Title:           r.Title,
+		NamespaceUID:    group.FolderUID,
+		RuleGroup:       group.Name,
+		RuleGroupIndex:  idx + 1,
+		IntervalSeconds: int64(time.Duration(group.Interval).Seconds()),
+		For:             time.Duration(r.For),
+		IsPaused:        r.IsPaused,
+		NoDataState:     models.NoData,
+		ExecErrState:    models.ErrorErrState,
+	}
+	if result.UID == "" {
+		result.UID = util.GenerateShortUID()
+	}
+	if r.Condition != nil {
+		result.Condition = *r.Condition
+	}
+	if r.Labels != nil {
+		result.Labels = *r.Labels
+	}
+	if r.Annotations != nil {
+		result.Annotations = *r.Annotations
+	}
+	if r.NoDataState != nil {
+		state, err := models.NoDataStateFromString(string(*r.NoDataState))
+		if err != nil {
+			return nil, err
+		}
+		result.NoDataState = state
+	}
+	if r.ExecErrState != nil {
+		state, err := models.ErrStateFromString(string(*r.ExecErrState))
+		if err != nil {
+			return nil, err
+		}
+		result.ExecErrState = state
+	}
+	if r.Record != nil {
+		result.Record = &models.Record{Metric: r.Record.Metric, From: r.Record.From}
+	}
+	for _, q := range r.Data {
+		model, err := json.Marshal(q.Model)
+		if err != nil {
+			return nil, fmt.Errorf("invalid model of query %s: %w", q.RefID, err)
+		}
+		query := models.AlertQuery{
+			RefID:         q.RefID,
+			DatasourceUID: q.DatasourceUID,
+			Model:         model,
+			RelativeTimeRange: models.RelativeTimeRange{
+				From: models.Duration(time.Duration(q.RelativeTimeRange.FromSeconds) * time.Second),
+				To:   models.Duration(time.Duration(q.RelativeTimeRange.ToSeconds) * time.Second),
+			},
+		}
+		if q.QueryType != nil {
+			query.QueryType = *q.QueryType
+		}
+		result.Data = append(result.Data, query)
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package ruletest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// series is an input series with one value per interval, starting at the start of the test. Missing values are nil.
+type series struct {
+	refID  string
+	labels data.Labels
+	values []*float64
+}
+
+func parseSeries(s definitions.RuleUnitTestSeries) (series, error) {
+	if s.RefID == "" {
+		return series{}, fmt.Errorf("%w: input series must have a ref_id", ErrInvalidTestFile)
+	}
+	values, err := parseSeriesValues(s.Values)
+	if err != nil {
+		return series{}, fmt.Errorf("%w: invalid values of input series %s: %s", ErrInvalidTestFile, s.RefID, err)
+	}
+	return series{
+		refID:  s.RefID,
+		labels: data.Labels(s.Labels),
+		values: values,
+	}, nil
+}
+
+// parseSeriesValues parses values in the expanding notation of promtool: 'a+bxn' expands to n+1 values starting at
+// a and incremented by b, 'a-bxn' to decreasing values, 'axn' repeats a n+1 times, '_' is a missing value and '_xn'
+// are n missing values.
+func parseSeriesValues(s string) ([]*float64, error) {
+	var result []*float64
+	for _, token := range strings.Fields(s) {
+		values, err := expandValue(token)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, values...)
+	}
+	return result, nil
+}
+
+func expandValue(token string) ([]*float64, error) {
+	if token == "_" {
+		return []*float64{nil}, nil
+	}
+	idx := strings.LastIndex(token, "x")
+	if idx < 0 {
+		v, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", token)
+		}
+		return []*float64{&v}, nil
+	}
+
+	times, err := strconv.Atoi(token[idx+1:])
+	if err != nil || times < 0 {
+		return nil, fmt.Errorf("invalid number of repetitions in %q", token)
+	}
+	expr := token[:idx]
+	if expr == "_" {
+		return make([]*float64, times), nil
+	}
+
+	start, increment := expr, "0"
+	// The operator is the last sign that is not at the start of the value or part of an exponent.
+	for i := len(expr) - 1; i > 0; i-- {
+		if (expr[i] == '+' || expr[i] == '-') && expr[i-1] != 'e' && expr[i-1] != 'E' {
+			start, increment = expr[:i], expr[i:]
+			break
+		}
+	}
+	a, err := strconv.ParseFloat(start, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q", token)
+	}
+	b, err := strconv.ParseFloat(strings.TrimPrefix(increment, "+"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid increment %q", token)
+	}
+	result := make([]*float64, 0, times+1)
+	for i := 0; i <= times; i++ {
+		v := a + float64(i)*b
+		result = append(result, &v)
+	}
+	return result, nil
+}
+
+// response returns the data source response with the values of the series between from and to, inclusive.
+func response(inputs []series, start time.Time, interval time.Duration, from, to time.Time) backend.DataResponse {
+	frames := make(data.Frames, 0, len(inputs))
+	for _, s := range inputs {
+		times := make([]time.Time, 0)
+		values := make([]*float64, 0)
+		for i, v := range s.values {
+			ts := start.Add(time.Duration(i) * interval)
+			if v == nil || ts.Before(from) || ts.After(to) {
+				continue
+			}
+			times = append(times, ts)
+			values = append(values, v)
+		}
+		frames = append(frames, data.NewFrame("",
+			data.NewField("Time", nil, times),
+			data.NewField("Value", s.labels, values),
+		))
+	}
+	return backend.DataResponse{Frames: frames}
+}
@@ -0,0 +1,58 @@
+package ruletest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeriesValues(t *testing.T) {
+	v := func(f float64) *float64 { return &f }
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected []*float64
+		err      string
+	}{
+		{name: "single values", input: "1 2.5 -3", expected: []*float64{v(1), v(2.5), v(-3)}},
+		{name: "missing value", input: "1 _ 2", expected: []*float64{v(1), nil, v(2)}},
+		{name: "missing values", input: "_x2 1", expected: []*float64{nil, nil, v(1)}},
+		{name: "repeated value", input: "5x2", expected: []*float64{v(5), v(5), v(5)}},
+		{name: "increasing values", input: "1+2x3", expected: []*float64{v(1), v(3), v(5), v(7)}},
+		{name: "decreasing values", input: "-1-1x2", expected: []*float64{v(-1), v(-2), v(-3)}},
+		{name: "exponent", input: "1e+2+1x1", expected: []*float64{v(100), v(101)}},
+		{name: "empty", input: "", expected: nil},
+		{name: "invalid value", input: "a", err: "invalid value"},
+		{name: "invalid repetitions", input: "1x-1", err: "invalid number of repetitions"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseSeriesValues(tc.input)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestResponse(t *testing.T) {
+	one, two, three := 1.0, 2.0, 3.0
+	start := time.Unix(0, 0).UTC()
+	s := series{refID: "A", labels: data.Labels{"job": "api"}, values: []*float64{&one, nil, &two, &three}}
+
+	resp := response([]series{s}, start, time.Minute, start.Add(time.Minute), start.Add(2*time.Minute))
+
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames, 1)
+	frame := resp.Frames[0]
+	require.Equal(t, 1, frame.Rows())
+	require.Equal(t, start.Add(2*time.Minute), frame.Fields[0].At(0))
+	require.Equal(t, &two, frame.Fields[1].At(0))
+	require.Equal(t, data.Labels{"job": "api"}, frame.Fields[1].Labels)
+}
@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -66,8 +67,10 @@ func newRuleFactory(
 	logger log.Logger,
 	tracer tracing.Tracer,
 	recordingWriter RecordingWriter,
+	limiter *evaluationLimiter,
 	evalAppliedHook evalAppliedFunc,
 	stopAppliedHook stopAppliedFunc,
+	handOffs *handOffStore,
 ) ruleFactoryFunc {
 	return func(ctx context.Context, rule *ngmodels.AlertRule) Rule {
 		if rule.Type() == ngmodels.RuleTypeRecording {
@@ -82,6 +85,7 @@ func newRuleFactory(
 				met,
 				tracer,
 				recordingWriter,
+				limiter,
 				evalAppliedHook,
 				stopAppliedHook,
 			)
@@ -100,8 +104,10 @@ func newRuleFactory(
 			met,
 			logger,
 			tracer,
+			limiter,
 			evalAppliedHook,
 			stopAppliedHook,
+			handOffs,
 		)
 	}
 }
@@ -130,6 +136,8 @@ type alertRule struct {
 	stateManager *state.Manager
 	evalFactory  eval.EvaluatorFactory
 	ruleProvider ruleProvider
+	limiter      *evaluationLimiter
+	handOffs     *handOffStore
 
 	// Event hooks that are only used in tests.
 	evalAppliedHook evalAppliedFunc
@@ -154,8 +162,10 @@ func newAlertRule(
 	met *metrics.Scheduler,
 	logger log.Logger,
 	tracer tracing.Tracer,
+	limiter *evaluationLimiter,
 	evalAppliedHook func(ngmodels.AlertRuleKey, time.Time),
 	stopAppliedHook func(ngmodels.AlertRuleKey),
+	handOffs *handOffStore,
 ) *alertRule {
 	ctx, stop := util.WithCancelCause(ngmodels.WithRuleKey(parent, key.AlertRuleKey))
 	return &alertRule{
@@ -172,6 +182,8 @@ func newAlertRule(
 		stateManager:         stateManager,
 		evalFactory:          evalFactory,
 		ruleProvider:         ruleProvider,
+		limiter:              limiter,
+		handOffs:             handOffs,
 		evalAppliedHook:      evalAppliedHook,
 		stopAppliedHook:      stopAppliedHook,
 		metrics:              met,
@@ -274,6 +286,9 @@ func (a *alertRule) Run() error {
 			logger.Debug("Processing tick")
 
 			func() {
+				// Rules that depend on an alert rule only wait for its evaluation to finish.
+				defer ctx.complete(nil)
+
 				orgID := fmt.Sprint(a.key.OrgID)
 				evalDuration := a.metrics.EvalDuration.WithLabelValues(orgID)
 				evalTotal := a.metrics.EvalTotal.WithLabelValues(orgID)
@@ -284,6 +299,11 @@ func (a *alertRule) Run() error {
 					a.evalApplied(ctx.scheduledAt)
 				}()
 
+				queryResponses, ok := ctx.waitForDependencies(grafanaCtx, time.Duration(ctx.rule.IntervalSeconds)*time.Second)
+				if !ok {
+					logger.Warn("Rules the rule depends on did not finish in time, evaluating the rule without waiting for them")
+				}
+
 				for attempt := int64(1); attempt <= a.maxAttempts; attempt++ {
 					isPaused := ctx.rule.IsPaused
 
@@ -313,7 +333,7 @@ func (a *alertRule) Run() error {
 
 					fpStr := currentFingerprint.String()
 					utcTick := ctx.scheduledAt.UTC().Format(time.RFC3339Nano)
-					tracingCtx, span := a.tracer.Start(grafanaCtx, "alert rule execution", trace.WithAttributes(
+					tracingCtx, span := a.tracer.Start(expr.WithQueryResponses(grafanaCtx, queryResponses), "alert rule execution", trace.WithAttributes(
 						attribute.String("rule_uid", ctx.rule.UID),
 						attribute.Int64("org_id", ctx.rule.OrgID),
 						attribute.Int64("rule_version", ctx.rule.Version),
@@ -364,6 +384,17 @@ func (a *alertRule) Run() error {
 				// the evaluation loop is that the rule was deleted.
 				stateTransitions := a.stateManager.DeleteStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key, ngmodels.StateReasonRuleDeleted)
 				a.expireAndSend(grafanaCtx, stateTransitions)
+			} else if errors.Is(reason, errRuleHandedOff) {
+				// The rule is evaluated by another instance of the cluster now. Save the state so that it
+				// continues from where this instance stopped.
+				a.stateManager.HandOffStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key)
+				// Let the instance that takes the rule over know that the state is saved.
+				var handOff *ruleHandOff
+				if errors.As(reason, &handOff) {
+					if err := a.handOffs.markHandedOff(ctx, a.key.AlertRuleKey, handOff.ringVersion, a.clock.Now()); err != nil {
+						a.logger.Error("Failed to record the hand off of the rule state", "error", err)
+					}
+				}
 			} else {
 				// Otherwise, just clean up the cache.
 				a.stateManager.ForgetStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key)
@@ -386,6 +417,7 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 	start := a.clock.Now()
 
 	evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), a.newLoadedMetricsReader(e.rule))
+	evalCtx.Limits = a.limiter.evalLimits(e.rule)
 	ruleEval, err := a.evalFactory.Create(evalCtx, e.rule.GetEvalCondition().WithSource("scheduler").WithFolder(e.folderTitle))
 	var results eval.Results
 	var dur time.Duration
@@ -408,11 +440,17 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 		return nil
 	}
 
+	// Rules that exceed the evaluation limits are not retried but paused, and the error is recorded in their state.
+	limitExceeded := isEvaluationLimitError(err)
+	if limitExceeded {
+		a.limiter.pause(e.rule, err)
+	}
+
 	if err != nil || results.HasErrors() {
 		evalAttemptFailures.Inc()
 
 		// Only retry (return errors) if this isn't the last attempt, otherwise skip these return operations.
-		if retry {
+		if retry && !limitExceeded {
 			// The only thing that can return non-nil `err` from ruleEval.Evaluate is the server side expression pipeline.
 			// This includes transport errors such as transient network errors.
 			if err != nil {
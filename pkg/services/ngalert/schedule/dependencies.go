@@ -0,0 +1,141 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// evaluationCompletion is signaled when the evaluation of a rule that other rules of the group depend on is finished.
+type evaluationCompletion struct {
+	once sync.Once
+	done chan struct{}
+	// output is the result of the recording rule query. It is nil if the evaluation failed or was skipped.
+	output *backend.DataResponse
+}
+
+func newEvaluationCompletion() *evaluationCompletion {
+	return &evaluationCompletion{done: make(chan struct{})}
+}
+
+func (c *evaluationCompletion) complete(output *backend.DataResponse) {
+	c.once.Do(func() {
+		c.output = output
+		close(c.done)
+	})
+}
+
+// evaluationDependency is an evaluation of the same tick that must finish before the dependent rule is evaluated.
+type evaluationDependency struct {
+	ruleUID    string
+	refID      string
+	completion *evaluationCompletion
+}
+
+// complete signals the rules that depend on this evaluation that it is finished. Only the first call has an effect.
+func (e *Evaluation) complete(output *backend.DataResponse) {
+	if e.completion != nil {
+		e.completion.complete(output)
+	}
+}
+
+// waitForDependencies blocks until all evaluations the rule depends on are finished, the context is cancelled, or the
+// timeout elapses. It returns the output of the recording rules that answer queries of the rule, by query RefID.
+func (e *Evaluation) waitForDependencies(ctx context.Context, timeout time.Duration) (map[string]backend.DataResponse, bool) {
+	if len(e.dependencies) == 0 {
+		return nil, true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var responses map[string]backend.DataResponse
+	for _, dep := range e.dependencies {
+		select {
+		case <-dep.completion.done:
+		case <-ctx.Done():
+			return responses, false
+		case <-timer.C:
+			return responses, false
+		}
+		if dep.refID == "" || dep.completion.output == nil {
+			continue
+		}
+		if responses == nil {
+			responses = make(map[string]backend.DataResponse, len(e.dependencies))
+		}
+		responses[dep.refID] = *dep.completion.output
+	}
+	return responses, true
+}
+
+// linkDependencies makes the evaluations of rules wait for the evaluations of the same tick they depend on.
+// Dependencies on rules that are not evaluated on the tick, for example because they were deleted, are ignored.
+func linkDependencies(items []readyToRunItem) {
+	byKey := make(map[ngmodels.AlertRuleKey]*readyToRunItem, len(items))
+	for i := range items {
+		byKey[items[i].rule.GetKey()] = &items[i]
+	}
+	for i := range items {
+		item := &items[i]
+		for _, dep := range item.rule.Dependencies {
+			producer, ok := byKey[ngmodels.AlertRuleKey{OrgID: item.rule.OrgID, UID: dep.RuleUID}]
+			if !ok || producer.rule.GetGroupKey() != item.rule.GetGroupKey() {
+				continue
+			}
+			if producer.completion == nil {
+				producer.completion = newEvaluationCompletion()
+			}
+			item.dependencies = append(item.dependencies, evaluationDependency{
+				ruleUID:    dep.RuleUID,
+				refID:      dep.RefID,
+				completion: producer.completion,
+			})
+		}
+	}
+}
+
+// groupsWithDependencies returns the rule groups that have rules that depend on other rules.
+func groupsWithDependencies(rules []*ngmodels.AlertRule) map[ngmodels.AlertRuleGroupKey]struct{} {
+	result := make(map[ngmodels.AlertRuleGroupKey]struct{})
+	for _, rule := range rules {
+		if len(rule.Dependencies) > 0 {
+			result[rule.GetGroupKey()] = struct{}{}
+		}
+	}
+	return result
+}
+
+// recordingOutput returns the response of the recording rule query as a query of the recorded metric would return it.
+func recordingOutput(rule *ngmodels.AlertRule, resp backend.DataResponse) *backend.DataResponse {
+	frames := make(data.Frames, 0, len(resp.Frames))
+	for _, frame := range resp.Frames {
+		fields := make([]*data.Field, 0, len(frame.Fields))
+		for _, field := range frame.Fields {
+			if field.Type().Time() {
+				fields = append(fields, field)
+				continue
+			}
+			labels := make(data.Labels, len(field.Labels)+len(rule.Labels)+1)
+			for k, v := range field.Labels {
+				labels[k] = v
+			}
+			for k, v := range rule.Labels {
+				labels[k] = v
+			}
+			labels["__name__"] = rule.Record.Metric
+			f := *field
+			f.Labels = labels
+			fields = append(fields, &f)
+		}
+		f := data.NewFrame(frame.Name, fields...)
+		f.RefID = frame.RefID
+		f.Meta = frame.Meta
+		frames = append(frames, f)
+	}
+	return &backend.DataResponse{Frames: frames, Status: resp.Status}
+}
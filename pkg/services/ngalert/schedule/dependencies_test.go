@@ -0,0 +1,74 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestLinkDependencies(t *testing.T) {
+	gen := models.RuleGen
+	group := gen.With(gen.WithGroupKey(models.GenerateGroupKey(1)))
+	producer := group.With(gen.WithUID("producer"), gen.WithAllRecordingRules()).GenerateRef()
+	dependent := group.With(gen.WithUID("dependent"), gen.WithDependencies(
+		models.RuleDependency{RuleUID: "producer", RefID: "A"},
+		models.RuleDependency{RuleUID: "deleted"},
+	)).GenerateRef()
+	other := gen.With(gen.WithOrgID(1), gen.WithUID("other"), gen.WithDependencies(models.RuleDependency{RuleUID: "producer"})).GenerateRef()
+
+	items := []readyToRunItem{
+		{Evaluation: Evaluation{rule: dependent}},
+		{Evaluation: Evaluation{rule: producer}},
+		{Evaluation: Evaluation{rule: other}},
+	}
+	linkDependencies(items)
+
+	require.Nil(t, items[0].completion)
+	require.NotNil(t, items[1].completion)
+	require.Len(t, items[0].dependencies, 1, "dependencies on rules that are not evaluated should be ignored")
+	require.Equal(t, "A", items[0].dependencies[0].refID)
+	require.Same(t, items[1].completion, items[0].dependencies[0].completion)
+	require.Empty(t, items[2].dependencies, "dependencies on rules of other groups should be ignored")
+
+	t.Run("waits for the dependencies and returns their output", func(t *testing.T) {
+		output := backend.DataResponse{Frames: data.Frames{data.NewFrame("")}}
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			items[1].complete(&output)
+			items[1].complete(nil)
+		}()
+		responses, ok := items[0].waitForDependencies(context.Background(), time.Minute)
+		require.True(t, ok)
+		require.Equal(t, map[string]backend.DataResponse{"A": output}, responses)
+	})
+
+	t.Run("gives up waiting after the timeout", func(t *testing.T) {
+		item := readyToRunItem{Evaluation: Evaluation{rule: dependent, dependencies: []evaluationDependency{
+			{ruleUID: "producer", completion: newEvaluationCompletion()},
+		}}}
+		responses, ok := item.waitForDependencies(context.Background(), 10*time.Millisecond)
+		require.False(t, ok)
+		require.Nil(t, responses)
+	})
+}
+
+func TestRecordingOutput(t *testing.T) {
+	gen := models.RuleGen
+	rule := gen.With(gen.WithAllRecordingRules(), gen.WithMetric("recorded"), gen.WithLabels(data.Labels{"team": "a"})).GenerateRef()
+	frame := data.NewFrame("frame",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+		data.NewField("value", data.Labels{"instance": "b"}, []float64{1}),
+	)
+
+	output := recordingOutput(rule, backend.DataResponse{Frames: data.Frames{frame}})
+
+	require.Len(t, output.Frames, 1)
+	require.Equal(t, data.Labels{"__name__": "recorded", "team": "a", "instance": "b"}, output.Frames[0].Fields[1].Labels)
+	require.Equal(t, data.Labels{"instance": "b"}, frame.Fields[1].Labels, "input frame must not be changed")
+}
@@ -0,0 +1,274 @@
+package schedule
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	limitSeries           = "series"
+	limitDuration         = "duration"
+	limitRulesPerInterval = "rules_per_interval"
+)
+
+// errMaxRulesPerIntervalExceeded is the reason of rules that are paused because their organization or folder
+// evaluates more rules than the evaluation limits allow.
+var errMaxRulesPerIntervalExceeded = errors.New("rule evaluations exceed the limit")
+
+// pausedRule is a rule that is paused because it exceeded the evaluation limits.
+type pausedRule struct {
+	limit  string
+	reason error
+	// version of the rule when it was paused. A new version of the rule is evaluated again.
+	version int64
+	// until is the time when the rule is evaluated again. It is zero for rules that are paused because of
+	// the rules per interval limit, which are checked on every tick.
+	until time.Time
+}
+
+// evaluationLimiter enforces the evaluation limits of organizations and folders.
+// A nil limiter does not limit the evaluation of rules.
+type evaluationLimiter struct {
+	cfg     setting.UnifiedAlertingEvaluationLimitsSettings
+	clock   clock.Clock
+	metrics *metrics.Scheduler
+	logger  log.Logger
+
+	mtx    sync.Mutex
+	paused map[ngmodels.AlertRuleKey]pausedRule
+}
+
+func newEvaluationLimiter(cfg setting.UnifiedAlertingEvaluationLimitsSettings, clock clock.Clock, metrics *metrics.Scheduler, logger log.Logger) *evaluationLimiter {
+	if !cfg.IsEnabled() {
+		return nil
+	}
+	return &evaluationLimiter{
+		cfg:     cfg,
+		clock:   clock,
+		metrics: metrics,
+		logger:  logger,
+		paused:  make(map[ngmodels.AlertRuleKey]pausedRule),
+	}
+}
+
+// limits returns the limits of the folder of the rule, or of its organization if the folder has no limits of its own.
+func (l *evaluationLimiter) limits(rule *ngmodels.AlertRule) setting.UnifiedAlertingEvaluationLimits {
+	if limits, ok := l.cfg.Folders[setting.EvaluationLimitsFolderKey{OrgID: rule.OrgID, FolderUID: rule.NamespaceUID}]; ok {
+		return limits
+	}
+	return l.orgLimits(rule.OrgID)
+}
+
+func (l *evaluationLimiter) orgLimits(orgID int64) setting.UnifiedAlertingEvaluationLimits {
+	if limits, ok := l.cfg.Orgs[orgID]; ok {
+		return limits
+	}
+	return l.cfg.Default
+}
+
+// evalLimits returns the limits of a single evaluation of the rule.
+func (l *evaluationLimiter) evalLimits(rule *ngmodels.AlertRule) eval.EvaluationLimits {
+	if l == nil {
+		return eval.EvaluationLimits{}
+	}
+	limits := l.limits(rule)
+	return eval.EvaluationLimits{
+		MaxSeries:   limits.MaxSeriesPerEvaluation,
+		MaxDuration: limits.MaxEvaluationDuration,
+	}
+}
+
+// isPaused returns true if the rule must not be evaluated because it exceeded the evaluation limits.
+// Rules that exceeded the limits of a single evaluation are evaluated again after the pause duration, or when the rule is updated.
+func (l *evaluationLimiter) isPaused(rule *ngmodels.AlertRule) bool {
+	if l == nil {
+		return false
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	p, ok := l.paused[rule.GetKey()]
+	if !ok {
+		return false
+	}
+	if p.until.IsZero() {
+		return true
+	}
+	if p.version == rule.Version && l.clock.Now().Before(p.until) {
+		return true
+	}
+	delete(l.paused, rule.GetKey())
+	l.updateMetrics()
+	return false
+}
+
+// pause pauses the rule because its evaluation failed with an error of the evaluation limits.
+func (l *evaluationLimiter) pause(rule *ngmodels.AlertRule, err error) {
+	if l == nil {
+		return
+	}
+	limit := limitDuration
+	if errors.Is(err, eval.ErrMaxSeriesExceeded) {
+		limit = limitSeries
+	}
+	until := l.clock.Now().Add(l.cfg.PauseDuration)
+	l.logger.Warn("Rule is paused because it exceeded the evaluation limits", append(rule.GetKey().LogContext(), "limit", limit, "until", until, "error", err)...)
+	l.metrics.EvaluationLimitViolations.WithLabelValues(fmt.Sprint(rule.OrgID), limit).Inc()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.paused[rule.GetKey()] = pausedRule{
+		limit:   limit,
+		reason:  fmt.Errorf("rule is paused until %s because it exceeded the evaluation limits: %w", until.UTC().Format(time.RFC3339), err),
+		version: rule.Version,
+		until:   until,
+	}
+	l.updateMetrics()
+}
+
+// pauseReason returns the reason why the rule is paused, or nil if it is not.
+func (l *evaluationLimiter) pauseReason(key ngmodels.AlertRuleKey) error {
+	if l == nil {
+		return nil
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if p, ok := l.paused[key]; ok {
+		return p.reason
+	}
+	return nil
+}
+
+// forget removes the rules from the paused rules.
+func (l *evaluationLimiter) forget(keys ...ngmodels.AlertRuleKey) {
+	if l == nil {
+		return
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for _, key := range keys {
+		delete(l.paused, key)
+	}
+	l.updateMetrics()
+}
+
+type rulesBudget struct {
+	max  int
+	used float64
+}
+
+// exceeded returns true if the budget does not allow the cost. A small tolerance prevents rounding errors.
+func (b *rulesBudget) exceeded(cost float64) bool {
+	return b != nil && b.max > 0 && b.used+cost > float64(b.max)+1e-9
+}
+
+func (b *rulesBudget) add(cost float64) {
+	if b != nil {
+		b.used += cost
+	}
+}
+
+// applyRulesPerInterval pauses the rules of organizations and folders that evaluate more rules per interval than the limits allow.
+// A rule costs the number of its evaluations per interval. The rules are admitted in the order of their creation, so
+// the rules that were created last are paused first.
+func (l *evaluationLimiter) applyRulesPerInterval(rules []*ngmodels.AlertRule) {
+	if l == nil {
+		return
+	}
+	sorted := slices.Clone(rules)
+	slices.SortFunc(sorted, func(a, b *ngmodels.AlertRule) int {
+		if c := cmp.Compare(a.ID, b.ID); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UID, b.UID)
+	})
+
+	orgs := make(map[int64]*rulesBudget)
+	folders := make(map[setting.EvaluationLimitsFolderKey]*rulesBudget)
+	for key, limits := range l.cfg.Folders {
+		if limits.MaxRulesPerInterval > 0 {
+			folders[key] = &rulesBudget{max: limits.MaxRulesPerInterval}
+		}
+	}
+
+	exceeded := make(map[ngmodels.AlertRuleKey]pausedRule)
+	for _, rule := range sorted {
+		if rule.IsPaused || rule.IntervalSeconds <= 0 {
+			continue
+		}
+		org, ok := orgs[rule.OrgID]
+		if !ok {
+			org = &rulesBudget{max: l.orgLimits(rule.OrgID).MaxRulesPerInterval}
+			orgs[rule.OrgID] = org
+		}
+		folder := folders[setting.EvaluationLimitsFolderKey{OrgID: rule.OrgID, FolderUID: rule.NamespaceUID}]
+
+		cost := l.cfg.RulesInterval.Seconds() / float64(rule.IntervalSeconds)
+		var reason error
+		switch {
+		case folder.exceeded(cost):
+			reason = fmt.Errorf("%w: the rules of the folder are evaluated more than %d times per %s", errMaxRulesPerIntervalExceeded, folder.max, l.cfg.RulesInterval)
+		case org.exceeded(cost):
+			reason = fmt.Errorf("%w: the rules of the organization are evaluated more than %d times per %s", errMaxRulesPerIntervalExceeded, org.max, l.cfg.RulesInterval)
+		default:
+			org.add(cost)
+			folder.add(cost)
+			continue
+		}
+		exceeded[rule.GetKey()] = pausedRule{
+			limit:   limitRulesPerInterval,
+			reason:  fmt.Errorf("rule is paused because it exceeded the evaluation limits: %w", reason),
+			version: rule.Version,
+		}
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for key, p := range l.paused {
+		if p.limit != limitRulesPerInterval {
+			continue
+		}
+		if _, ok := exceeded[key]; !ok {
+			l.logger.Info("Rule is resumed because it no longer exceeds the evaluation limits", key.LogContext()...)
+			delete(l.paused, key)
+		}
+	}
+	for key, p := range exceeded {
+		current, ok := l.paused[key]
+		if ok && current.limit != limitRulesPerInterval {
+			// The rule is already paused because of the limits of a single evaluation.
+			continue
+		}
+		if !ok {
+			l.logger.Warn("Rule is paused because it exceeded the evaluation limits", append(key.LogContext(), "limit", limitRulesPerInterval, "error", p.reason)...)
+			l.metrics.EvaluationLimitViolations.WithLabelValues(fmt.Sprint(key.OrgID), limitRulesPerInterval).Inc()
+		}
+		l.paused[key] = p
+	}
+	l.updateMetrics()
+}
+
+// updateMetrics updates the number of paused rules. It must be called with the lock held.
+func (l *evaluationLimiter) updateMetrics() {
+	l.metrics.RulesPausedByLimits.Reset()
+	for key, p := range l.paused {
+		l.metrics.RulesPausedByLimits.WithLabelValues(fmt.Sprint(key.OrgID), p.limit).Inc()
+	}
+}
+
+// isEvaluationLimitError returns true if the evaluation failed because it exceeded the evaluation limits.
+func isEvaluationLimitError(err error) bool {
+	return errors.Is(err, eval.ErrMaxSeriesExceeded) || errors.Is(err, eval.ErrMaxDurationExceeded)
+}
@@ -0,0 +1,205 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestEvaluationLimiter(t *testing.T) {
+	cfg := setting.UnifiedAlertingEvaluationLimitsSettings{
+		Default: setting.UnifiedAlertingEvaluationLimits{MaxSeriesPerEvaluation: 100, MaxEvaluationDuration: time.Minute},
+		Orgs: map[int64]setting.UnifiedAlertingEvaluationLimits{
+			2: {MaxSeriesPerEvaluation: 10, MaxRulesPerInterval: 3},
+		},
+		Folders: map[setting.EvaluationLimitsFolderKey]setting.UnifiedAlertingEvaluationLimits{
+			{OrgID: 2, FolderUID: "team-a"}: {MaxSeriesPerEvaluation: 1000, MaxRulesPerInterval: 1},
+		},
+		RulesInterval: time.Minute,
+		PauseDuration: time.Hour,
+	}
+	gen := models.RuleGen.With(models.RuleGen.WithIsPaused(false), models.RuleGen.WithIntervalSeconds(60))
+
+	newLimiter := func(t *testing.T) (*evaluationLimiter, *clock.Mock, *prometheus.Registry) {
+		reg := prometheus.NewPedanticRegistry()
+		clk := clock.NewMock()
+		return newEvaluationLimiter(cfg, clk, metrics.NewSchedulerMetrics(reg), log.NewNopLogger()), clk, reg
+	}
+
+	t.Run("should be nil without limits", func(t *testing.T) {
+		l := newEvaluationLimiter(setting.UnifiedAlertingEvaluationLimitsSettings{}, clock.NewMock(), nil, log.NewNopLogger())
+		require.Nil(t, l)
+
+		rule := gen.GenerateRef()
+		assert.Equal(t, eval.EvaluationLimits{}, l.evalLimits(rule))
+		assert.False(t, l.isPaused(rule))
+		assert.NoError(t, l.pauseReason(rule.GetKey()))
+		l.pause(rule, eval.ErrMaxSeriesExceeded)
+		l.applyRulesPerInterval([]*models.AlertRule{rule})
+		l.forget(rule.GetKey())
+	})
+
+	t.Run("should use the limits of the folder, organization or default", func(t *testing.T) {
+		l, _, _ := newLimiter(t)
+
+		assert.Equal(t, eval.EvaluationLimits{MaxSeries: 1000}, l.evalLimits(gen.With(gen.WithOrgID(2), gen.WithNamespaceUID("team-a")).GenerateRef()))
+		assert.Equal(t, eval.EvaluationLimits{MaxSeries: 10}, l.evalLimits(gen.With(gen.WithOrgID(2), gen.WithNamespaceUID("team-b")).GenerateRef()))
+		assert.Equal(t, eval.EvaluationLimits{MaxSeries: 100, MaxDuration: time.Minute}, l.evalLimits(gen.With(gen.WithOrgID(1)).GenerateRef()))
+	})
+
+	t.Run("should pause rule that exceeded the limits of an evaluation until the pause duration elapsed", func(t *testing.T) {
+		l, clk, reg := newLimiter(t)
+		rule := gen.With(gen.WithOrgID(1), gen.WithVersion(1)).GenerateRef()
+		other := gen.With(gen.WithOrgID(1)).GenerateRef()
+
+		l.pause(rule, fmt.Errorf("%w: 101 (limit: 100)", eval.ErrMaxSeriesExceeded))
+
+		assert.True(t, l.isPaused(rule))
+		assert.False(t, l.isPaused(other))
+		assert.ErrorIs(t, l.pauseReason(rule.GetKey()), eval.ErrMaxSeriesExceeded)
+		assert.ErrorContains(t, l.pauseReason(rule.GetKey()), "rule is paused until 1970-01-01T01:00:00Z because it exceeded the evaluation limits")
+		assert.NoError(t, testutil.GatherAndCompare(reg, bytes.NewBufferString(`
+# HELP grafana_alerting_rule_evaluation_limit_violations_total The total number of times alert rules were paused because they exceeded the evaluation limits.
+# TYPE grafana_alerting_rule_evaluation_limit_violations_total counter
+grafana_alerting_rule_evaluation_limit_violations_total{limit="series",org="1"} 1
+# HELP grafana_alerting_rules_paused_by_limits The number of alert rules that are paused because they exceeded the evaluation limits.
+# TYPE grafana_alerting_rules_paused_by_limits gauge
+grafana_alerting_rules_paused_by_limits{limit="series",org="1"} 1
+`), "grafana_alerting_rule_evaluation_limit_violations_total", "grafana_alerting_rules_paused_by_limits"))
+
+		clk.Add(time.Hour)
+
+		assert.False(t, l.isPaused(rule))
+		assert.NoError(t, l.pauseReason(rule.GetKey()))
+		assert.NoError(t, testutil.GatherAndCompare(reg, bytes.NewBufferString(""), "grafana_alerting_rules_paused_by_limits"))
+	})
+
+	t.Run("should resume paused rule when it is updated", func(t *testing.T) {
+		l, _, _ := newLimiter(t)
+		rule := gen.With(gen.WithOrgID(1), gen.WithVersion(1)).GenerateRef()
+
+		l.pause(rule, eval.ErrMaxDurationExceeded)
+		require.True(t, l.isPaused(rule))
+
+		updated := models.CopyRule(rule)
+		updated.Version++
+		assert.False(t, l.isPaused(updated))
+	})
+
+	t.Run("should pause rules over the rules per interval limit in the order of creation", func(t *testing.T) {
+		l, _, reg := newLimiter(t)
+		rule := func(id int64, folderUID string, interval int64) *models.AlertRule {
+			r := gen.With(gen.WithOrgID(2), gen.WithNamespaceUID(folderUID), gen.WithIntervalSeconds(interval)).GenerateRef()
+			r.ID = id
+			return r
+		}
+		teamA1 := rule(1, "team-a", 60)
+		teamA2 := rule(2, "team-a", 60)
+		teamB1 := rule(3, "team-b", 120)
+		teamB2 := rule(4, "team-b", 30)
+		teamB3 := rule(5, "team-b", 120)
+		teamB4 := rule(6, "team-b", 30)
+		otherOrg := gen.With(gen.WithOrgID(1)).GenerateRef()
+		rules := []*models.AlertRule{teamB4, teamB3, teamB2, teamB1, teamA2, teamA1, otherOrg}
+
+		l.applyRulesPerInterval(rules)
+
+		// team-a: 1 of 1, the organization: 1 + 0.5 + 0.5 of 3
+		for _, r := range []*models.AlertRule{teamA1, teamB1, teamB3, otherOrg} {
+			assert.Falsef(t, l.isPaused(r), "rule %d should not be paused", r.ID)
+		}
+		for _, r := range []*models.AlertRule{teamA2, teamB2, teamB4} {
+			assert.Truef(t, l.isPaused(r), "rule %d should be paused", r.ID)
+		}
+		assert.ErrorContains(t, l.pauseReason(teamA2.GetKey()), "the rules of the folder are evaluated more than 1 times per 1m0s")
+		assert.ErrorContains(t, l.pauseReason(teamB2.GetKey()), "the rules of the organization are evaluated more than 3 times per 1m0s")
+
+		// The violations are counted only once.
+		l.applyRulesPerInterval(rules)
+		assert.NoError(t, testutil.GatherAndCompare(reg, bytes.NewBufferString(`
+# HELP grafana_alerting_rule_evaluation_limit_violations_total The total number of times alert rules were paused because they exceeded the evaluation limits.
+# TYPE grafana_alerting_rule_evaluation_limit_violations_total counter
+grafana_alerting_rule_evaluation_limit_violations_total{limit="rules_per_interval",org="2"} 3
+# HELP grafana_alerting_rules_paused_by_limits The number of alert rules that are paused because they exceeded the evaluation limits.
+# TYPE grafana_alerting_rules_paused_by_limits gauge
+grafana_alerting_rules_paused_by_limits{limit="rules_per_interval",org="2"} 3
+`), "grafana_alerting_rule_evaluation_limit_violations_total", "grafana_alerting_rules_paused_by_limits"))
+
+		t.Run("and resume them when they fit the limits", func(t *testing.T) {
+			l.applyRulesPerInterval([]*models.AlertRule{teamA2, teamB2, teamB4})
+
+			assert.False(t, l.isPaused(teamA2))
+			assert.False(t, l.isPaused(teamB2))
+			assert.True(t, l.isPaused(teamB4))
+		})
+	})
+
+	t.Run("should not pause rules that are paused by the user", func(t *testing.T) {
+		l, _, _ := newLimiter(t)
+		paused := gen.With(gen.WithOrgID(2), gen.WithNamespaceUID("team-a"), gen.WithIsPaused(true)).GenerateRef()
+		paused.ID = 1
+		active := gen.With(gen.WithOrgID(2), gen.WithNamespaceUID("team-a")).GenerateRef()
+		active.ID = 2
+
+		l.applyRulesPerInterval([]*models.AlertRule{paused, active})
+
+		assert.False(t, l.isPaused(active))
+	})
+}
+
+func TestSchedulerEvaluationLimits(t *testing.T) {
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil, nil)
+	sch.limiter = newEvaluationLimiter(setting.UnifiedAlertingEvaluationLimitsSettings{
+		Default:       setting.UnifiedAlertingEvaluationLimits{MaxRulesPerInterval: 1},
+		RulesInterval: time.Minute,
+		PauseDuration: time.Hour,
+	}, sch.clock, sch.metrics, log.NewNopLogger())
+
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(1), models.RuleGen.WithIsPaused(false), models.RuleGen.WithIntervalSeconds(1))
+	first := gen.GenerateRef()
+	first.ID = 1
+	second := gen.GenerateRef()
+	second.ID = 2
+	ruleStore.PutRule(context.Background(), first, second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, time.Unix(0, 0))
+
+	require.Empty(t, scheduled)
+	status, ok := sch.Status(first.GetKey())
+	require.True(t, ok)
+	assert.Equal(t, "error", status.Health)
+	assert.ErrorIs(t, status.LastError, errMaxRulesPerIntervalExceeded)
+
+	t.Run("should evaluate rules that fit the limits", func(t *testing.T) {
+		first.IntervalSeconds = 60
+		first.Version++
+		ruleStore.PutRule(context.Background(), first)
+
+		scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, time.Unix(60, 0))
+
+		require.Len(t, scheduled, 1)
+		assert.Equal(t, first.GetKey(), scheduled[0].rule.GetKey())
+		status, _ := sch.Status(first.GetKey())
+		assert.NotEqual(t, "error", status.Health)
+	})
+}
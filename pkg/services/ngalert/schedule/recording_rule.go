@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -46,6 +47,7 @@ type recordingRule struct {
 	evalFactory eval.EvaluatorFactory
 	cfg         setting.RecordingRuleSettings
 	writer      RecordingWriter
+	limiter     *evaluationLimiter
 
 	// Event hooks that are only used in tests.
 	evalAppliedHook evalAppliedFunc
@@ -56,7 +58,7 @@ type recordingRule struct {
 	tracer  tracing.Tracer
 }
 
-func newRecordingRule(parent context.Context, key ngmodels.AlertRuleKeyWithGroup, maxAttempts int64, clock clock.Clock, evalFactory eval.EvaluatorFactory, cfg setting.RecordingRuleSettings, logger log.Logger, metrics *metrics.Scheduler, tracer tracing.Tracer, writer RecordingWriter, evalAppliedHook evalAppliedFunc, stopAppliedHook stopAppliedFunc) *recordingRule {
+func newRecordingRule(parent context.Context, key ngmodels.AlertRuleKeyWithGroup, maxAttempts int64, clock clock.Clock, evalFactory eval.EvaluatorFactory, cfg setting.RecordingRuleSettings, logger log.Logger, metrics *metrics.Scheduler, tracer tracing.Tracer, writer RecordingWriter, limiter *evaluationLimiter, evalAppliedHook evalAppliedFunc, stopAppliedHook stopAppliedFunc) *recordingRule {
 	ctx, stop := util.WithCancelCause(ngmodels.WithRuleKey(parent, key.AlertRuleKey))
 	return &recordingRule{
 		key:                 key,
@@ -77,6 +79,7 @@ func newRecordingRule(parent context.Context, key ngmodels.AlertRuleKeyWithGroup
 		metrics:             metrics,
 		tracer:              tracer,
 		writer:              writer,
+		limiter:             limiter,
 	}
 }
 
@@ -137,6 +140,7 @@ func (r *recordingRule) Run() error {
 				return nil
 			}
 			if !r.cfg.Enabled {
+				eval.complete(nil)
 				r.logger.Warn("Recording rule scheduled but subsystem is not enabled. Skipping")
 				return nil
 			}
@@ -171,6 +175,8 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 
 		r.evaluationDoneTestHook(ev)
 	}()
+	// The output is handed to the dependent rules as soon as the query succeeds. Otherwise, they only wait for the evaluation to finish.
+	defer ev.complete(nil)
 
 	if ev.rule.IsPaused {
 		logger.Debug("Skip recording rule evaluation because it is paused")
@@ -186,6 +192,12 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 	))
 	defer span.End()
 
+	queryResponses, ok := ev.waitForDependencies(ctx, time.Duration(ev.rule.IntervalSeconds)*time.Second)
+	if !ok {
+		logger.Warn("Rules the rule depends on did not finish in time, evaluating the rule without waiting for them")
+	}
+	ctx = expr.WithQueryResponses(ctx, queryResponses)
+
 	var latestError error
 	for attempt := int64(1); attempt <= r.maxAttempts; attempt++ {
 		logger := logger.New("attempt", attempt)
@@ -226,6 +238,9 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 		span.RecordError(latestError)
 		r.lastError.Store(latestError)
 		r.health.Store("error")
+		if isEvaluationLimitError(latestError) {
+			r.limiter.pause(ev.rule, latestError)
+		}
 		if r.maxAttempts > 0 {
 			logger.Error("Recording rule evaluation failed after all attempts", "lastError", latestError)
 		}
@@ -240,6 +255,7 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logger log.Logger) error {
 	evalStart := r.clock.Now()
 	evalCtx := eval.NewContext(ctx, SchedulerUserFor(ev.rule.OrgID))
+	evalCtx.Limits = r.limiter.evalLimits(ev.rule)
 	result, err := r.buildAndExecutePipeline(ctx, evalCtx, ev, logger)
 	evalDur := r.clock.Now().Sub(evalStart)
 	if err != nil {
@@ -253,6 +269,7 @@ func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logge
 	// TODO: This is missing dedicated logic for NoData. If NoData we can skip the write.
 
 	logger.Debug("Recording rule query completed", "resultCount", len(result.Responses), "duration", evalDur)
+	ev.complete(recordingOutput(ev.rule, result.Responses[ev.rule.Record.From]))
 	span := trace.SpanFromContext(ctx)
 	span.AddEvent("query succeeded", trace.WithAttributes(
 		attribute.Int64("results", int64(len(result.Responses))),
@@ -18,6 +18,7 @@ import (
 var (
 	errRuleDeleted   = errors.New("rule deleted")
 	errRuleRestarted = errors.New("rule restarted")
+	errRuleHandedOff = errors.New("rule handed off to another instance")
 )
 
 type ruleFactory interface {
@@ -96,6 +97,10 @@ type Evaluation struct {
 	scheduledAt time.Time
 	rule        *models.AlertRule
 	folderTitle string
+	// completion is set if other rules evaluated on the same tick depend on this evaluation.
+	completion *evaluationCompletion
+	// dependencies are the evaluations of the same tick this evaluation waits for.
+	dependencies []evaluationDependency
 }
 
 func (e *Evaluation) Fingerprint() fingerprint {
@@ -329,6 +334,10 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		binary.LittleEndian.PutUint64(tmp, uint64(rule.Record.Fingerprint()))
 		writeBytes(tmp)
 	}
+	for _, dep := range rule.Dependencies {
+		writeString(dep.RuleUID)
+		writeString(dep.RefID)
+	}
 
 	return fingerprint(sum.Sum64())
 }
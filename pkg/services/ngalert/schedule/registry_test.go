@@ -193,6 +193,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NoDataState:     "test-nodata",
 			ExecErrState:    "test-err",
 			Record:          &models.Record{Metric: "my_metric", From: "A"},
+			Dependencies:    []models.RuleDependency{{RuleUID: "producer", RefID: "A"}},
 			For:             12,
 			Annotations: map[string]string{
 				"key-annotation": "value-annotation",
@@ -238,6 +239,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NoDataState:     "test-nodata2",
 			ExecErrState:    "test-err2",
 			Record:          &models.Record{Metric: "my_metric2", From: "B"},
+			Dependencies:    []models.RuleDependency{{RuleUID: "producer-2"}},
 			For:             1141,
 			Annotations: map[string]string{
 				"key-annotation2": "value-annotation",
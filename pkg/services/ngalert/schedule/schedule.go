@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -111,6 +112,19 @@ type schedule struct {
 	tracer tracing.Tracer
 
 	recordingWriter RecordingWriter
+
+	// sharder is set when the evaluation of rule groups is sharded across the members of the HA cluster.
+	sharder *ruleSharder
+	// releasedRules contains the rules that are evaluated by other members of the cluster.
+	releasedRules map[ngmodels.AlertRuleKey]struct{}
+	// pendingTakeOvers contains the rules taken over from other members that wait for their state to be handed off.
+	pendingTakeOvers map[ngmodels.AlertRuleKey]pendingTakeOver
+	handOffs         *handOffStore
+	// handOffTimeout is how long a rule that is taken over waits for its state before it is evaluated anyway.
+	handOffTimeout time.Duration
+
+	// limiter enforces the evaluation limits of organizations and folders. It is nil if there are no limits.
+	limiter *evaluationLimiter
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -131,6 +145,12 @@ type SchedulerCfg struct {
 	Log                    log.Logger
 	RecordingWriter        RecordingWriter
 	RuleStopReasonProvider AlertRuleStopReasonProvider
+	// ClusterMembership is set to shard the evaluation of rule groups across the members of the HA cluster.
+	ClusterMembership ClusterMembership
+	// KVStore is where the members of the cluster record the rules whose state they handed off.
+	KVStore kvstore.KVStore
+	// EvaluationLimits are the limits of the evaluation of the rules of organizations and folders.
+	EvaluationLimits setting.UnifiedAlertingEvaluationLimitsSettings
 }
 
 // NewScheduler returns a new scheduler.
@@ -161,6 +181,15 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		tracer:                 cfg.Tracer,
 		recordingWriter:        cfg.RecordingWriter,
 		ruleStopReasonProvider: cfg.RuleStopReasonProvider,
+		limiter:                newEvaluationLimiter(cfg.EvaluationLimits, cfg.C, cfg.Metrics, cfg.Log),
+	}
+
+	if cfg.ClusterMembership != nil {
+		sch.sharder = newRuleSharder(cfg.ClusterMembership, cfg.Log)
+		sch.releasedRules = make(map[ngmodels.AlertRuleKey]struct{})
+		sch.pendingTakeOvers = make(map[ngmodels.AlertRuleKey]pendingTakeOver)
+		sch.handOffs = &handOffStore{kv: cfg.KVStore}
+		sch.handOffTimeout = 3 * cfg.BaseInterval
 	}
 
 	return &sch
@@ -187,7 +216,12 @@ func (sch *schedule) Rules() ([]*ngmodels.AlertRule, map[ngmodels.FolderKey]stri
 // Status fetches the health of a given scheduled rule, by key.
 func (sch *schedule) Status(key ngmodels.AlertRuleKey) (ngmodels.RuleStatus, bool) {
 	if rule, ok := sch.registry.get(key); ok {
-		return rule.Status(), true
+		status := rule.Status()
+		if reason := sch.limiter.pauseReason(key); reason != nil {
+			status.Health = "error"
+			status.LastError = reason
+		}
+		return status, true
 	}
 	return ngmodels.RuleStatus{}, false
 }
@@ -199,6 +233,7 @@ func (sch *schedule) deleteAlertRule(ctx context.Context, keys ...ngmodels.Alert
 		// Ruler API has called DeleteAlertRule. This can happen as requests to
 		// the Ruler API do not hold an exclusive lock over all scheduler operations.
 		_, ok := sch.schedulableAlertRules.del(key)
+		sch.limiter.forget(key)
 		if !ok {
 			sch.log.Info("Alert rule cannot be removed from the scheduler as it is not scheduled", key.LogContext()...)
 		}
@@ -291,6 +326,19 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 	sch.updateRulesMetrics(alertRules)
 
+	// The limits of organizations and folders apply to all of their rules, regardless of which member of the cluster evaluates them.
+	sch.limiter.applyRulesPerInterval(alertRules)
+
+	// takenOver contains the rules that this instance starts evaluating after another member of the cluster.
+	var takenOver map[ngmodels.AlertRuleKey]struct{}
+	if sch.sharder != nil {
+		alertRules, takenOver = sch.shardRules(ctx, alertRules, registeredDefinitions, tick)
+	}
+
+	// rules of groups with dependencies are evaluated on the same tick regardless of the jitter strategy
+	// so that dependent rules can wait for the rules they depend on.
+	dependentGroups := groupsWithDependencies(alertRules)
+
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	restartedRules := make([]Rule, 0)
@@ -309,8 +357,10 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.log,
 		sch.tracer,
 		sch.recordingWriter,
+		sch.limiter,
 		sch.evalAppliedFunc,
 		sch.stopAppliedFunc,
+		sch.handOffs,
 	)
 	for _, item := range alertRules {
 		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
@@ -334,7 +384,14 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		if newRoutine && !invalidInterval {
+			_, isTakenOver := takenOver[key]
 			dispatcherGroup.Go(func() error {
+				if isTakenOver {
+					// Continue from the state saved by the member that evaluated the rule before.
+					if err := sch.stateManager.LoadStateByRuleUID(ngmodels.WithRuleKey(ctx, key), item); err != nil {
+						logger.Error("Failed to load the state of the rule taken over from another instance", "error", err)
+					}
+				}
 				return ruleRoutine.Run()
 			})
 		}
@@ -347,7 +404,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-		offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterEvaluations)
+		jitterStrategy := sch.jitterEvaluations
+		if _, ok := dependentGroups[item.GetGroupKey()]; ok && jitterStrategy == JitterByRule {
+			jitterStrategy = JitterByGroup
+		}
+		offset := jitterOffsetInTicks(item, sch.baseInterval, jitterStrategy)
 		isReadyToRun := item.IntervalSeconds != 0 && (tickNum%itemFrequency)-offset == 0
 
 		var folderTitle string
@@ -360,6 +421,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 			}
 		}
 
+		if isReadyToRun && sch.limiter.isPaused(item) {
+			logger.Debug("Skip rule evaluation because it exceeded the evaluation limits", "tick", tick)
+			isReadyToRun = false
+		}
+
 		if isReadyToRun {
 			logger.Debug("Rule is ready to run on the current tick", "tick", tick, "frequency", itemFrequency, "offset", offset)
 			readyToRun = append(readyToRun, readyToRunItem{ruleRoutine: ruleRoutine, Evaluation: Evaluation{
@@ -399,13 +465,18 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	slices.SortFunc(readyToRun, func(a, b readyToRunItem) int {
 		return strings.Compare(a.rule.UID, b.rule.UID)
 	})
+	linkDependencies(readyToRun)
 	for i := range readyToRun {
 		item := readyToRun[i]
 
 		time.AfterFunc(time.Duration(int64(i)*step), func() {
 			key := item.rule.GetKey()
 			success, dropped := item.ruleRoutine.Eval(&item.Evaluation)
+			if dropped != nil {
+				dropped.complete(nil)
+			}
 			if !success {
+				item.complete(nil)
 				sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", append(key.LogContext(), "time", tick)...)
 				return
 			}
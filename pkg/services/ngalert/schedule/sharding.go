@@ -0,0 +1,268 @@
+package schedule
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ClusterMembership provides the live members of the cluster the evaluation of rule groups is sharded across.
+type ClusterMembership interface {
+	// ClusterMembers returns the name of this instance and the sorted names of all live members, this instance included.
+	ClusterMembers() (string, []string)
+}
+
+// ringTokensPerMember is the number of virtual nodes each member gets on the hash ring.
+// More tokens spread the rule groups more evenly at the cost of a bigger ring.
+const ringTokensPerMember = 128
+
+type ringToken struct {
+	hash   uint32
+	member string
+}
+
+// hashRing assigns rule groups to cluster members by consistent hashing.
+// When a member joins or leaves the cluster only the rule groups of that member move.
+type hashRing struct {
+	members []string
+	tokens  []ringToken
+	// version identifies the members of the ring, all members compute the same version for the same members.
+	version string
+}
+
+func newHashRing(members []string) *hashRing {
+	r := &hashRing{
+		members: members,
+		tokens:  make([]ringToken, 0, len(members)*ringTokensPerMember),
+		version: strconv.FormatUint(uint64(hashString(strings.Join(members, ","))), 16),
+	}
+	for _, member := range members {
+		for i := 0; i < ringTokensPerMember; i++ {
+			r.tokens = append(r.tokens, ringToken{hash: hashString(fmt.Sprintf("%s-%d", member, i)), member: member})
+		}
+	}
+	slices.SortFunc(r.tokens, func(a, b ringToken) int {
+		if a.hash != b.hash {
+			return cmp.Compare(a.hash, b.hash)
+		}
+		return strings.Compare(a.member, b.member)
+	})
+	return r
+}
+
+// owner returns the member that evaluates the rule group.
+func (r *hashRing) owner(key ngmodels.AlertRuleGroupKey) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := hashString(fmt.Sprintf("%d/%s/%s", key.OrgID, key.NamespaceUID, key.RuleGroup))
+	i := sort.Search(len(r.tokens), func(i int) bool {
+		return r.tokens[i].hash >= h
+	})
+	if i == len(r.tokens) {
+		i = 0
+	}
+	return r.tokens[i].member
+}
+
+// ruleSharder decides which rule groups are evaluated by this instance.
+type ruleSharder struct {
+	membership ClusterMembership
+	log        log.Logger
+
+	self string
+	ring *hashRing
+}
+
+func newRuleSharder(membership ClusterMembership, logger log.Logger) *ruleSharder {
+	return &ruleSharder{
+		membership: membership,
+		log:        logger,
+		ring:       newHashRing(nil),
+	}
+}
+
+// update rebuilds the ring if the members of the cluster changed since the last call.
+// It returns the previous ring if it changed, and nil otherwise.
+func (s *ruleSharder) update() *hashRing {
+	self, members := s.membership.ClusterMembers()
+	if self == s.self && slices.Equal(members, s.ring.members) {
+		return nil
+	}
+	s.log.Info("Cluster members changed, rebalancing rule groups", "self", self, "members", members)
+	previous := s.ring
+	s.self = self
+	s.ring = newHashRing(members)
+	return previous
+}
+
+// owns returns true if the rule group of the rule is evaluated by this instance.
+// All rules are owned as long as this instance does not know about other members.
+func (s *ruleSharder) owns(rule *ngmodels.AlertRule) bool {
+	if len(s.ring.members) <= 1 {
+		return true
+	}
+	return s.ring.owner(rule.GetGroupKey()) == s.self
+}
+
+// mayHandOff returns true if another live member may have evaluated the rule before the ring changed from previous,
+// and then hands its state off to this instance.
+func (s *ruleSharder) mayHandOff(previous *hashRing, rule *ngmodels.AlertRule) bool {
+	if len(s.ring.members) <= 1 {
+		return false
+	}
+	if len(previous.members) <= 1 {
+		// This instance just joined the cluster, any other member may have evaluated the rule.
+		return true
+	}
+	owner := previous.owner(rule.GetGroupKey())
+	return owner != s.self && slices.Contains(s.ring.members, owner)
+}
+
+// ruleHandOff is the reason the routine of a rule is stopped when another member of the cluster takes the rule over.
+type ruleHandOff struct {
+	ringVersion string
+}
+
+func (h *ruleHandOff) Error() string {
+	return errRuleHandedOff.Error()
+}
+
+func (h *ruleHandOff) Is(target error) bool {
+	return target == errRuleHandedOff
+}
+
+// handOffNamespace is the namespace of the key-value store where the members record the rules they handed off.
+const handOffNamespace = "alerting.sharding.handoff"
+
+// handOffStore records the rules whose state was saved by the member that evaluated them, so that the member taking
+// them over doesn't evaluate them from an older state. The value is the version of the ring and the time of the hand off.
+type handOffStore struct {
+	kv kvstore.KVStore
+}
+
+func (h *handOffStore) markHandedOff(ctx context.Context, key ngmodels.AlertRuleKey, ringVersion string, at time.Time) error {
+	if h == nil || h.kv == nil {
+		return nil
+	}
+	return h.kv.Set(ctx, key.OrgID, handOffNamespace, key.UID, fmt.Sprintf("%s/%d", ringVersion, at.UnixMilli()))
+}
+
+// handedOff returns true if the state of the rule was handed off for the version of the ring after the given time.
+// The record is removed once it's seen so that it isn't mistaken for a later hand off.
+func (h *handOffStore) handedOff(ctx context.Context, key ngmodels.AlertRuleKey, ringVersion string, after time.Time) (bool, error) {
+	if h == nil || h.kv == nil {
+		return true, nil
+	}
+	value, ok, err := h.kv.Get(ctx, key.OrgID, handOffNamespace, key.UID)
+	if err != nil || !ok {
+		return false, err
+	}
+	version, at, _ := strings.Cut(value, "/")
+	ms, err := strconv.ParseInt(at, 10, 64)
+	if err != nil || version != ringVersion || time.UnixMilli(ms).Before(after) {
+		return false, nil
+	}
+	return true, h.kv.Del(ctx, key.OrgID, handOffNamespace, key.UID)
+}
+
+// pendingTakeOver is a rule owned by this instance that is not evaluated until the member that evaluated it
+// before hands its state off, or until the hand off times out.
+type pendingTakeOver struct {
+	since time.Time
+	wait  bool
+}
+
+// shardRules returns the rules evaluated by this instance and the keys of the rules this instance takes over from
+// another member. The routines of the rules that are taken over by another member are stopped and their state is
+// handed off through the instance store. Those rules are removed from registered so they are not deleted.
+//
+// After the members of the cluster change, every owned rule that this instance doesn't evaluate yet and that another
+// member may have evaluated is taken over, including all the rules of an instance that just joined the cluster.
+// A rule taken over from another member isn't evaluated until the member that evaluated it before records that it
+// handed the state off for the current ring, or until handOffTimeout passed, for example because the members don't
+// agree on the ring yet.
+func (sch *schedule) shardRules(ctx context.Context, alertRules []*ngmodels.AlertRule, registered map[ngmodels.AlertRuleKey]struct{}, tick time.Time) ([]*ngmodels.AlertRule, map[ngmodels.AlertRuleKey]struct{}) {
+	previous := sch.sharder.update()
+	ringVersion := sch.sharder.ring.version
+	logger := sch.log.FromContext(ctx)
+
+	owned := make([]*ngmodels.AlertRule, 0, len(alertRules))
+	takenOver := make(map[ngmodels.AlertRuleKey]struct{})
+	released := make(map[ngmodels.AlertRuleKey]struct{})
+	waiting := make(map[ngmodels.AlertRuleKey]struct{})
+	for _, rule := range alertRules {
+		key := rule.GetKey()
+		_, wasReleased := sch.releasedRules[key]
+		if sch.sharder.owns(rule) {
+			if previous != nil {
+				wait := rule.Type() != ngmodels.RuleTypeRecording && sch.sharder.mayHandOff(previous, rule)
+				// A rule this instance doesn't evaluate yet has the state loaded on startup, which is outdated if
+				// another member evaluated the rule since.
+				if wasReleased || (wait && !sch.registry.exists(key)) {
+					sch.pendingTakeOvers[key] = pendingTakeOver{since: tick, wait: wait}
+				}
+			}
+			if pending, ok := sch.pendingTakeOvers[key]; ok {
+				if pending.wait && tick.Sub(pending.since) < sch.handOffTimeout {
+					handedOff, err := sch.handOffs.handedOff(ctx, key, ringVersion, pending.since.Add(-sch.handOffTimeout))
+					if err != nil {
+						logger.Error("Failed to check if the state of the rule was handed off", append(key.LogContext(), "error", err)...)
+					}
+					if !handedOff {
+						logger.Debug("Waiting for the state of the rule to be handed off", key.LogContext()...)
+						waiting[key] = struct{}{}
+						continue
+					}
+				}
+				delete(sch.pendingTakeOvers, key)
+				takenOver[key] = struct{}{}
+			}
+			owned = append(owned, rule)
+			continue
+		}
+
+		delete(sch.pendingTakeOvers, key)
+		delete(registered, key)
+		released[key] = struct{}{}
+		if wasReleased {
+			continue
+		}
+		if ruleRoutine, ok := sch.registry.del(key); ok {
+			ruleRoutine.Stop(&ruleHandOff{ringVersion: ringVersion})
+		} else {
+			// The state of the rule was loaded on startup but this instance never evaluated it.
+			sch.stateManager.ForgetStateByRuleUID(ngmodels.WithRuleKey(ctx, key), rule.GetKeyWithGroup())
+			if err := sch.handOffs.markHandedOff(ctx, key, ringVersion, tick); err != nil {
+				logger.Error("Failed to record the hand off of the rule", append(key.LogContext(), "error", err)...)
+			}
+		}
+	}
+	// Forget the rules that were deleted while waiting for their state.
+	for key := range sch.pendingTakeOvers {
+		if _, ok := waiting[key]; !ok {
+			delete(sch.pendingTakeOvers, key)
+		}
+	}
+	if len(takenOver) > 0 || len(released) != len(sch.releasedRules) {
+		sch.log.Debug("Rule groups sharded", "owned", len(owned), "released", len(released), "takenOver", len(takenOver), "pending", len(sch.pendingTakeOvers))
+	}
+	sch.releasedRules = released
+	return owned, takenOver
+}
+
+func hashString(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
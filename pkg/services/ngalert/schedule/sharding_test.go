@@ -0,0 +1,294 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+type fakeClusterMembership struct {
+	mtx     sync.Mutex
+	self    string
+	members []string
+}
+
+func (f *fakeClusterMembership) ClusterMembers() (string, []string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.self, f.members
+}
+
+func (f *fakeClusterMembership) setMembers(members ...string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.members = members
+}
+
+func TestHashRing(t *testing.T) {
+	keys := make([]models.AlertRuleGroupKey, 0, 1000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: fmt.Sprintf("group-%d", i)})
+	}
+
+	t.Run("rule groups are spread across all members", func(t *testing.T) {
+		ring := newHashRing([]string{"a", "b", "c"})
+		counts := map[string]int{}
+		for _, key := range keys {
+			counts[ring.owner(key)]++
+		}
+		require.Len(t, counts, 3)
+		for member, count := range counts {
+			assert.Greaterf(t, count, len(keys)/6, "member %s owns too few rule groups", member)
+		}
+	})
+
+	t.Run("only rule groups of a joining member move", func(t *testing.T) {
+		before := newHashRing([]string{"a", "b", "c"})
+		after := newHashRing([]string{"a", "b", "c", "d"})
+		moved := 0
+		for _, key := range keys {
+			if before.owner(key) != after.owner(key) {
+				require.Equal(t, "d", after.owner(key))
+				moved++
+			}
+		}
+		require.NotZero(t, moved)
+	})
+
+	t.Run("empty ring has no owner", func(t *testing.T) {
+		require.Empty(t, newHashRing(nil).owner(keys[0]))
+	})
+}
+
+func TestSchedule_shardRules(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	t.Cleanup(func() {
+		cancel()
+		_ = dispatcherGroup.Wait()
+	})
+
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	sch := setupScheduler(t, ruleStore, instanceStore, nil, nil, nil, nil)
+	membership := &fakeClusterMembership{self: "a", members: []string{"a"}}
+	sch.sharder = newRuleSharder(membership, log.NewNopLogger())
+	sch.releasedRules = map[models.AlertRuleKey]struct{}{}
+	sch.pendingTakeOvers = map[models.AlertRuleKey]pendingTakeOver{}
+	kv := fakes.NewFakeKVStore(t)
+	sch.handOffs = &handOffStore{kv: kv}
+	sch.handOffTimeout = time.Minute
+
+	// rules are not evaluated on the ticks below so that the state is changed only by the test
+	gen := models.RuleGen
+	rules := gen.With(gen.WithOrgID(1), gen.WithInterval(10*time.Second)).GenerateManyRef(20)
+	ruleStore.PutRule(ctx, rules...)
+
+	ring := newHashRing([]string{"a", "b"})
+	var owned, released []*models.AlertRule
+	for _, rule := range rules {
+		if ring.owner(rule.GetGroupKey()) == "a" {
+			owned = append(owned, rule)
+		} else {
+			released = append(released, rule)
+		}
+	}
+	require.NotEmpty(t, released)
+
+	tick := time.Time{}.Add(time.Second)
+	_, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+	require.Empty(t, stopped)
+	for _, rule := range rules {
+		require.True(t, sch.registry.exists(rule.GetKey()), "single member should evaluate all rules")
+		sch.stateManager.Put([]*state.State{{
+			OrgID:        rule.OrgID,
+			AlertRuleUID: rule.UID,
+			CacheID:      data.Labels{"rule": rule.UID}.Fingerprint(),
+			Labels:       data.Labels{"rule": rule.UID},
+			State:        eval.Alerting,
+		}})
+	}
+
+	t.Run("rules of the other member are handed off", func(t *testing.T) {
+		membership.setMembers("a", "b")
+		tick = tick.Add(time.Second)
+		_, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Empty(t, stopped, "handed off rules must not be deleted")
+
+		all, _ := sch.Rules()
+		require.Len(t, all, len(rules))
+		for _, rule := range owned {
+			require.True(t, sch.registry.exists(rule.GetKey()))
+			require.Len(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID), 1)
+		}
+		for _, rule := range released {
+			require.False(t, sch.registry.exists(rule.GetKey()))
+		}
+		require.Eventually(t, func() bool {
+			saved := map[string]struct{}{}
+			for _, op := range instanceStore.RecordedOps() {
+				if instance, ok := op.(models.AlertInstance); ok {
+					saved[instance.RuleUID] = struct{}{}
+				}
+			}
+			for _, rule := range released {
+				if _, ok := saved[rule.UID]; !ok {
+					return false
+				}
+				if len(sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)) > 0 {
+					return false
+				}
+				if _, ok, _ := kv.Get(ctx, rule.OrgID, handOffNamespace, rule.UID); !ok {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 10*time.Millisecond, "state of the handed off rules should be saved, removed from the cache and recorded as handed off")
+	})
+
+	t.Run("rules of a member that left are taken over", func(t *testing.T) {
+		membership.setMembers("a")
+		tick = tick.Add(time.Second)
+		_, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+		require.Empty(t, stopped)
+
+		for _, rule := range rules {
+			require.True(t, sch.registry.exists(rule.GetKey()))
+		}
+		require.Eventually(t, func() bool {
+			loaded := map[string]struct{}{}
+			for _, op := range instanceStore.RecordedOps() {
+				if q, ok := op.(models.ListAlertInstancesQuery); ok {
+					loaded[q.RuleUID] = struct{}{}
+				}
+			}
+			for _, rule := range released {
+				if _, ok := loaded[rule.UID]; !ok {
+					return false
+				}
+			}
+			return len(loaded) == len(released)
+		}, 5*time.Second, 10*time.Millisecond, "state of the taken over rules should be loaded from the instance store")
+	})
+}
+
+func TestSchedule_shardRules_joiningMember(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	t.Cleanup(func() {
+		cancel()
+		_ = dispatcherGroup.Wait()
+	})
+
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	sch := setupScheduler(t, ruleStore, instanceStore, nil, nil, nil, nil)
+	membership := &fakeClusterMembership{self: "b", members: []string{"a", "b"}}
+	sch.sharder = newRuleSharder(membership, log.NewNopLogger())
+	sch.releasedRules = map[models.AlertRuleKey]struct{}{}
+	sch.pendingTakeOvers = map[models.AlertRuleKey]pendingTakeOver{}
+	kv := fakes.NewFakeKVStore(t)
+	sch.handOffs = &handOffStore{kv: kv}
+	sch.handOffTimeout = 30 * time.Second
+
+	gen := models.RuleGen
+	rules := gen.With(gen.WithOrgID(1), gen.WithInterval(10*time.Second)).GenerateManyRef(20)
+	ruleStore.PutRule(ctx, rules...)
+
+	ring := newHashRing([]string{"a", "b"})
+	var owned []*models.AlertRule
+	for _, rule := range rules {
+		if ring.owner(rule.GetGroupKey()) == "b" {
+			owned = append(owned, rule)
+		}
+	}
+	require.GreaterOrEqual(t, len(owned), 2)
+	handedOff, notHandedOff := owned[:len(owned)/2], owned[len(owned)/2:]
+
+	loaded := func() map[string]struct{} {
+		loaded := map[string]struct{}{}
+		for _, op := range instanceStore.RecordedOps() {
+			if q, ok := op.(models.ListAlertInstancesQuery); ok {
+				loaded[q.RuleUID] = struct{}{}
+			}
+		}
+		return loaded
+	}
+
+	tick := time.Time{}.Add(time.Second)
+	sch.processTick(ctx, dispatcherGroup, tick)
+	for _, rule := range owned {
+		require.False(t, sch.registry.exists(rule.GetKey()), "owned rules should wait for the hand off of their state")
+	}
+
+	t.Run("rules are evaluated once their state is handed off", func(t *testing.T) {
+		for _, rule := range handedOff {
+			require.NoError(t, sch.handOffs.markHandedOff(ctx, rule.GetKey(), ring.version, tick))
+		}
+		tick = tick.Add(10 * time.Second)
+		sch.processTick(ctx, dispatcherGroup, tick)
+
+		for _, rule := range handedOff {
+			require.True(t, sch.registry.exists(rule.GetKey()))
+			_, ok, err := kv.Get(ctx, rule.OrgID, handOffNamespace, rule.UID)
+			require.NoError(t, err)
+			require.False(t, ok, "the hand off record should be removed once seen")
+		}
+		for _, rule := range notHandedOff {
+			require.False(t, sch.registry.exists(rule.GetKey()))
+		}
+		require.Eventually(t, func() bool {
+			l := loaded()
+			for _, rule := range handedOff {
+				if _, ok := l[rule.UID]; !ok {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 10*time.Millisecond, "state of the taken over rules should be loaded from the instance store")
+	})
+
+	t.Run("hand off records of another ring are ignored", func(t *testing.T) {
+		for _, rule := range notHandedOff {
+			require.NoError(t, sch.handOffs.markHandedOff(ctx, rule.GetKey(), newHashRing([]string{"a", "b", "c"}).version, tick))
+		}
+		tick = tick.Add(10 * time.Second)
+		sch.processTick(ctx, dispatcherGroup, tick)
+
+		for _, rule := range notHandedOff {
+			require.False(t, sch.registry.exists(rule.GetKey()))
+		}
+	})
+
+	t.Run("rules are evaluated when the hand off times out", func(t *testing.T) {
+		tick = tick.Add(10 * time.Second)
+		sch.processTick(ctx, dispatcherGroup, tick)
+
+		for _, rule := range owned {
+			require.True(t, sch.registry.exists(rule.GetKey()))
+		}
+		require.Empty(t, sch.pendingTakeOvers)
+		require.Eventually(t, func() bool {
+			l := loaded()
+			for _, rule := range owned {
+				if _, ok := l[rule.UID]; !ok {
+					return false
+				}
+			}
+			return true
+		}, 5*time.Second, 10*time.Millisecond, "state of the taken over rules should be loaded from the instance store")
+	})
+}
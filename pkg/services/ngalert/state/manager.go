@@ -59,15 +59,18 @@ type Manager struct {
 	rulesPerRuleGroupLimit         int64
 
 	persister StatePersister
+	xact      TransactionManager
 }
 
 type ManagerCfg struct {
 	Metrics       *metrics.State
 	ExternalURL   *url.URL
 	InstanceStore InstanceStore
-	Images        ImageCapturer
-	Clock         clock.Clock
-	Historian     Historian
+	// TransactionManager runs the writes of an import of a state snapshot in a single transaction.
+	TransactionManager TransactionManager
+	Images             ImageCapturer
+	Clock              clock.Clock
+	Historian          Historian
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
 	// StatePeriodicSaveBatchSize controls the size of the alert instance batch that is saved periodically when the
@@ -109,6 +112,7 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		applyNoDataAndErrorToAllStates: cfg.ApplyNoDataAndErrorToAllStates,
 		rulesPerRuleGroupLimit:         cfg.RulesPerRuleGroupLimit,
 		persister:                      statePersister,
+		xact:                           cfg.TransactionManager,
 		tracer:                         cfg.Tracer,
 	}
 
@@ -188,38 +192,7 @@ func (st *Manager) Warm(ctx context.Context, orgReader OrgReader, rulesReader Ru
 				continue
 			}
 
-			// nil safety.
-			annotations := ruleForEntry.Annotations
-			if annotations == nil {
-				annotations = make(map[string]string)
-			}
-
-			lbs := map[string]string(entry.Labels)
-			cacheID := entry.Labels.Fingerprint()
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					logger.Error("Failed to parse result fingerprint of alert instance", "error", err, "rule_uid", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			state := &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          annotations,
-				ResultFingerprint:    resultFp,
-				ResolvedAt:           entry.ResolvedAt,
-				LastSentAt:           entry.LastSentAt,
-			}
+			state := stateFromInstance(logger, ruleForEntry, entry)
 			st.cache.set(state)
 			statesCount++
 		}
@@ -228,6 +201,84 @@ func (st *Manager) Warm(ctx context.Context, orgReader OrgReader, rulesReader Ru
 	logger.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// LoadStateByRuleUID replaces the states of the rule in the cache with the ones saved in the instance store.
+// It is used when the evaluation of the rule is taken over from another instance.
+func (st *Manager) LoadStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule) error {
+	logger := st.log.FromContext(ctx)
+	if st.instanceStore == nil {
+		return nil
+	}
+
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		return err
+	}
+
+	st.cache.removeByRuleUID(rule.OrgID, rule.UID)
+	for _, entry := range alertInstances {
+		st.cache.set(stateFromInstance(logger, rule, entry))
+	}
+	logger.Debug("Rule state was loaded", "states", len(alertInstances))
+	return nil
+}
+
+// HandOffStateByRuleUID saves the states of the rule to the instance store and removes them from the cache.
+// It is used when the evaluation of the rule is taken over by another instance, which then loads the states.
+func (st *Manager) HandOffStateByRuleUID(ctx context.Context, ruleKey ngModels.AlertRuleKeyWithGroup) []*State {
+	logger := st.log.FromContext(ctx)
+	states := st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
+
+	transitions := make(StateTransitions, 0, len(states))
+	for _, s := range states {
+		transitions = append(transitions, StateTransition{
+			State:               s,
+			PreviousState:       s.State,
+			PreviousStateReason: s.StateReason,
+		})
+	}
+	st.persister.Sync(ctx, trace.SpanFromContext(ctx), ruleKey, transitions)
+	logger.Debug("Rule state was handed off", "states", len(states))
+	return states
+}
+
+func stateFromInstance(logger log.Logger, rule *ngModels.AlertRule, entry *ngModels.AlertInstance) *State {
+	// nil safety.
+	annotations := rule.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	lbs := map[string]string(entry.Labels)
+	cacheID := entry.Labels.Fingerprint()
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			logger.Error("Failed to parse result fingerprint of alert instance", "error", err, "rule_uid", entry.RuleUID)
+		}
+		resultFp = data.Fingerprint(fp)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               lbs,
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          annotations,
+		ResultFingerprint:    resultFp,
+		ResolvedAt:           entry.ResolvedAt,
+		LastSentAt:           entry.LastSentAt,
+	}
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -0,0 +1,209 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type snapshotKey struct {
+	ruleUID string
+	labels  data.Fingerprint
+}
+
+// SnapshotFromInstances returns the snapshot of the alert instances of the organization saved in the instance store.
+func SnapshotFromInstances(orgID int64, instances []*ngModels.AlertInstance) ngModels.AlertStateSnapshot {
+	result := make([]ngModels.AlertInstanceSnapshot, 0, len(instances))
+	for _, instance := range instances {
+		result = append(result, ngModels.AlertInstanceSnapshotFromInstance(*instance))
+	}
+	return newSnapshot(orgID, result)
+}
+
+func newSnapshot(orgID int64, instances []ngModels.AlertInstanceSnapshot) ngModels.AlertStateSnapshot {
+	slices.SortFunc(instances, func(a, b ngModels.AlertInstanceSnapshot) int {
+		if c := strings.Compare(a.RuleUID, b.RuleUID); c != 0 {
+			return c
+		}
+		return strings.Compare(data.Labels(a.Labels).String(), data.Labels(b.Labels).String())
+	})
+	return ngModels.AlertStateSnapshot{
+		Version:    ngModels.AlertStateSnapshotVersion,
+		OrgID:      orgID,
+		ExportedAt: time.Now().UTC(),
+		Instances:  instances,
+	}
+}
+
+// ExportSnapshot returns the snapshot of the alert instances of the organization. The state saved in the instance
+// store is overridden by the state in the cache because the latter is more recent and has annotations.
+func (st *Manager) ExportSnapshot(ctx context.Context, orgID int64) (ngModels.AlertStateSnapshot, error) {
+	byKey := make(map[snapshotKey]ngModels.AlertInstanceSnapshot)
+	if st.instanceStore != nil {
+		instances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{RuleOrgID: orgID})
+		if err != nil {
+			return ngModels.AlertStateSnapshot{}, fmt.Errorf("failed to list alert instances: %w", err)
+		}
+		for _, instance := range instances {
+			byKey[snapshotKey{ruleUID: instance.RuleUID, labels: instance.Labels.Fingerprint()}] = ngModels.AlertInstanceSnapshotFromInstance(*instance)
+		}
+	}
+	for _, s := range st.cache.getAll(orgID) {
+		byKey[snapshotKey{ruleUID: s.AlertRuleUID, labels: s.CacheID}] = ngModels.AlertInstanceSnapshot{
+			RuleUID:           s.AlertRuleUID,
+			Labels:            s.Labels,
+			State:             ngModels.InstanceStateType(s.State.String()),
+			StateReason:       s.StateReason,
+			StartsAt:          s.StartsAt,
+			EndsAt:            s.EndsAt,
+			LastEvaluatedAt:   s.LastEvaluationTime,
+			LastSentAt:        s.LastSentAt,
+			ResolvedAt:        s.ResolvedAt,
+			ResultFingerprint: s.ResultFingerprint.String(),
+			Annotations:       s.Annotations,
+		}
+	}
+
+	instances := make([]ngModels.AlertInstanceSnapshot, 0, len(byKey))
+	for _, instance := range byKey {
+		instances = append(instances, instance)
+	}
+	return newSnapshot(orgID, instances), nil
+}
+
+// TransactionManager runs the writes of a snapshot import in a single database transaction.
+type TransactionManager interface {
+	InTransaction(ctx context.Context, f func(ctx context.Context) error) error
+}
+
+// ImportSnapshot replaces the state of the rules of the snapshot with the imported state, in the instance store and
+// then in the cache. The next evaluation of the rules continues from the imported state.
+//
+// The state is only replaced in the cache of this Grafana instance, the other instances of a high availability
+// cluster keep evaluating the rules from their own state and overwrite the imported one.
+func (st *Manager) ImportSnapshot(ctx context.Context, orgID int64, snapshot ngModels.AlertStateSnapshot, rules []*ngModels.AlertRule) (ngModels.AlertStateSnapshotImportResult, error) {
+	return importSnapshot(ctx, st.log, st.instanceStore, st.persister, st.xact, orgID, snapshot, rules, func(rule *ngModels.AlertRule, states []*State) {
+		st.cache.removeByRuleUID(rule.OrgID, rule.UID)
+		for _, s := range states {
+			st.cache.set(s)
+		}
+	})
+}
+
+// ImportSnapshot writes the state of the snapshot to the instance store, replacing the saved state of the rules of the
+// snapshot. It is used when Grafana is not running, the state is loaded into the cache on the next start.
+func ImportSnapshot(ctx context.Context, logger log.Logger, store InstanceStore, persister StatePersister, xact TransactionManager, orgID int64, snapshot ngModels.AlertStateSnapshot, rules []*ngModels.AlertRule) (ngModels.AlertStateSnapshotImportResult, error) {
+	return importSnapshot(ctx, logger, store, persister, xact, orgID, snapshot, rules, nil)
+}
+
+func importSnapshot(
+	ctx context.Context,
+	logger log.Logger,
+	store InstanceStore,
+	persister StatePersister,
+	xact TransactionManager,
+	orgID int64,
+	snapshot ngModels.AlertStateSnapshot,
+	rules []*ngModels.AlertRule,
+	setStates func(rule *ngModels.AlertRule, states []*State),
+) (ngModels.AlertStateSnapshotImportResult, error) {
+	result := ngModels.AlertStateSnapshotImportResult{}
+	if err := snapshot.Validate(); err != nil {
+		return result, err
+	}
+	logger = logger.FromContext(ctx)
+
+	ruleByUID := make(map[string]*ngModels.AlertRule, len(rules))
+	for _, rule := range rules {
+		if rule.OrgID == orgID {
+			ruleByUID[rule.UID] = rule
+		}
+	}
+
+	statesByRule := make(map[string][]*State)
+	instancesByRule := make(map[string][]ngModels.AlertInstance)
+	missing := make(map[string]struct{})
+	for _, s := range snapshot.Instances {
+		rule, ok := ruleByUID[s.RuleUID]
+		if !ok {
+			missing[s.RuleUID] = struct{}{}
+			continue
+		}
+		instance, err := s.ToAlertInstance(orgID)
+		if err != nil {
+			return result, fmt.Errorf("%w: instance of rule %s has invalid labels: %w", ngModels.ErrInvalidAlertStateSnapshot, s.RuleUID, err)
+		}
+		state := stateFromInstance(logger, rule, &instance)
+		if len(s.Annotations) > 0 {
+			state.Annotations = s.Annotations
+		}
+		statesByRule[rule.UID] = append(statesByRule[rule.UID], state)
+		instancesByRule[rule.UID] = append(instancesByRule[rule.UID], instance)
+	}
+
+	uids := make([]string, 0, len(statesByRule))
+	for uid := range statesByRule {
+		uids = append(uids, uid)
+	}
+	slices.Sort(uids)
+
+	// The state of all the rules is replaced at once, a failure leaves the saved state untouched.
+	if store != nil {
+		err := inTransaction(ctx, xact, func(ctx context.Context) error {
+			for _, uid := range uids {
+				if err := saveRuleState(ctx, store, persister, ruleByUID[uid].GetKeyWithGroup(), instancesByRule[uid]); err != nil {
+					return fmt.Errorf("failed to save the state of rule %s: %w", uid, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	for _, uid := range uids {
+		if setStates != nil {
+			setStates(ruleByUID[uid], statesByRule[uid])
+		}
+		result.Imported += len(statesByRule[uid])
+	}
+
+	for uid := range missing {
+		result.MissingRules = append(result.MissingRules, uid)
+	}
+	slices.Sort(result.MissingRules)
+	logger.Info("Alert state snapshot imported", "orgID", orgID, "instances", result.Imported, "missingRules", len(result.MissingRules))
+	return result, nil
+}
+
+// saveRuleState replaces the saved state of the rule with the given instances, in the format the persister of the
+// state manager reads and writes: one entry per rule for the rule state persister, one row per instance otherwise.
+func saveRuleState(ctx context.Context, store InstanceStore, persister StatePersister, key ngModels.AlertRuleKeyWithGroup, instances []ngModels.AlertInstance) error {
+	if _, ok := persister.(*SyncRuleStatePersister); ok {
+		return store.SaveAlertInstancesForRule(ctx, key, instances)
+	}
+	if err := store.DeleteAlertInstancesByRule(ctx, key); err != nil {
+		return err
+	}
+	for _, instance := range instances {
+		if err := store.SaveAlertInstance(ctx, instance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func inTransaction(ctx context.Context, xact TransactionManager, f func(ctx context.Context) error) error {
+	if xact == nil {
+		return f(ctx)
+	}
+	return xact.InTransaction(ctx, f)
+}
@@ -0,0 +1,181 @@
+package state_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestAlertStateSnapshot(t *testing.T) {
+	ctx := context.Background()
+	const orgID int64 = 1
+	evaluationTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newManagerWithPersister := func(instanceStore state.InstanceStore, xact state.TransactionManager, newPersister func(cfg state.ManagerCfg) state.StatePersister) *state.Manager {
+		cfg := state.ManagerCfg{
+			Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore:           instanceStore,
+			TransactionManager:      xact,
+			Images:                  &state.NoopImageService{},
+			Clock:                   clock.NewMock(),
+			Historian:               &state.FakeHistorian{},
+			Tracer:                  tracing.InitializeTracerForTest(),
+			Log:                     log.New("ngalert.state.manager"),
+			MaxStateSaveConcurrency: 1,
+		}
+		return state.NewManager(cfg, newPersister(cfg))
+	}
+	newManager := func(instanceStore state.InstanceStore) *state.Manager {
+		return newManagerWithPersister(instanceStore, nil, func(cfg state.ManagerCfg) state.StatePersister {
+			return state.NewSyncStatePersisiter(log.NewNopLogger(), cfg)
+		})
+	}
+
+	gen := models.RuleGen.With(models.RuleGen.WithOrgID(orgID))
+	rule := gen.GenerateRef()
+	rule.For = 0
+	rule.Annotations = map[string]string{"summary": "test"}
+
+	source := newManager(&state.FakeInstanceStore{})
+	source.ProcessEvalResults(ctx, evaluationTime, rule, eval.Results{{
+		Instance:    data.Labels{"instance": "a"},
+		State:       eval.Alerting,
+		EvaluatedAt: evaluationTime,
+	}}, nil, nil)
+
+	snapshot, err := source.ExportSnapshot(ctx, orgID)
+	require.NoError(t, err)
+	require.Equal(t, models.AlertStateSnapshotVersion, snapshot.Version)
+	require.Equal(t, orgID, snapshot.OrgID)
+	require.Len(t, snapshot.Instances, 1)
+	exported := snapshot.Instances[0]
+	assert.Equal(t, rule.UID, exported.RuleUID)
+	assert.Equal(t, "a", exported.Labels["instance"])
+	assert.Equal(t, models.InstanceStateFiring, exported.State)
+	assert.Equal(t, "test", exported.Annotations["summary"])
+
+	t.Run("import sets the state in the cache and the instance store", func(t *testing.T) {
+		instanceStore := &state.FakeInstanceStore{}
+		target := newManager(instanceStore)
+
+		result, err := target.ImportSnapshot(ctx, orgID, snapshot, []*models.AlertRule{rule})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Imported)
+		assert.Empty(t, result.MissingRules)
+
+		states := target.GetStatesForRuleUID(orgID, rule.UID)
+		require.Len(t, states, 1)
+		assert.Equal(t, eval.Alerting, states[0].State)
+		assert.Equal(t, exported.StartsAt, states[0].StartsAt)
+		assert.Equal(t, "test", states[0].Annotations["summary"])
+
+		var saved []models.AlertInstance
+		for _, op := range instanceStore.RecordedOps() {
+			if instance, ok := op.(models.AlertInstance); ok {
+				saved = append(saved, instance)
+			}
+		}
+		require.Len(t, saved, 1)
+		assert.Equal(t, rule.UID, saved[0].RuleUID)
+		assert.Equal(t, models.InstanceStateFiring, saved[0].CurrentState)
+	})
+
+	t.Run("import saves the state when the state is saved periodically", func(t *testing.T) {
+		instanceStore := &state.FakeInstanceStore{}
+		xact := &fakeTransactionManager{}
+		target := newManagerWithPersister(instanceStore, xact, func(cfg state.ManagerCfg) state.StatePersister {
+			return state.NewAsyncStatePersister(log.NewNopLogger(), clock.NewMock().Ticker(time.Hour), cfg)
+		})
+
+		result, err := target.ImportSnapshot(ctx, orgID, snapshot, []*models.AlertRule{rule})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Imported)
+		assert.Equal(t, 1, xact.calls)
+
+		var saved []models.AlertInstance
+		for _, op := range instanceStore.RecordedOps() {
+			if instance, ok := op.(models.AlertInstance); ok {
+				saved = append(saved, instance)
+			}
+		}
+		require.Len(t, saved, 1)
+		assert.Equal(t, rule.UID, saved[0].RuleUID)
+	})
+
+	t.Run("import saves the state of each rule at once with the rule state persister", func(t *testing.T) {
+		instanceStore := &state.FakeInstanceStore{}
+		target := newManagerWithPersister(instanceStore, &fakeTransactionManager{}, func(cfg state.ManagerCfg) state.StatePersister {
+			return state.NewSyncRuleStatePersisiter(log.NewNopLogger(), cfg)
+		})
+
+		_, err := target.ImportSnapshot(ctx, orgID, snapshot, []*models.AlertRule{rule})
+		require.NoError(t, err)
+
+		ops := instanceStore.RecordedOps()
+		require.Len(t, ops, 1)
+		op, ok := ops[0].(state.FakeInstanceStoreOp)
+		require.True(t, ok)
+		assert.Equal(t, "SaveAlertInstancesForRule", op.Name)
+		assert.Equal(t, rule.GetKeyWithGroup(), op.Args[1])
+		assert.Len(t, op.Args[2], 1)
+	})
+
+	t.Run("import fails without changing the state if the state can't be saved", func(t *testing.T) {
+		target := newManager(&failingInstanceStore{})
+
+		result, err := target.ImportSnapshot(ctx, orgID, snapshot, []*models.AlertRule{rule})
+		require.ErrorContains(t, err, "failed to save the state of rule "+rule.UID)
+		assert.Equal(t, 0, result.Imported)
+		assert.Empty(t, target.GetStatesForRuleUID(orgID, rule.UID))
+	})
+
+	t.Run("import skips the state of rules that do not exist", func(t *testing.T) {
+		target := newManager(&state.FakeInstanceStore{})
+
+		result, err := target.ImportSnapshot(ctx, orgID, snapshot, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Imported)
+		assert.Equal(t, []string{rule.UID}, result.MissingRules)
+		assert.Empty(t, target.GetStatesForRuleUID(orgID, rule.UID))
+	})
+
+	t.Run("import fails if the snapshot is invalid", func(t *testing.T) {
+		target := newManager(&state.FakeInstanceStore{})
+
+		invalid := snapshot
+		invalid.Version = 100
+		_, err := target.ImportSnapshot(ctx, orgID, invalid, []*models.AlertRule{rule})
+		require.ErrorIs(t, err, models.ErrInvalidAlertStateSnapshot)
+	})
+}
+
+type fakeTransactionManager struct {
+	calls int
+}
+
+func (f *fakeTransactionManager) InTransaction(ctx context.Context, work func(ctx context.Context) error) error {
+	f.calls++
+	return work(ctx)
+}
+
+type failingInstanceStore struct {
+	state.FakeInstanceStore
+}
+
+func (f *failingInstanceStore) SaveAlertInstance(context.Context, models.AlertInstance) error {
+	return errors.New("database is locked")
+}
@@ -671,6 +671,23 @@ func (st DBstore) GetNamespaceByUID(ctx context.Context, uid string, orgID int64
 	return f[0], nil
 }
 
+// GetNamespaceByTitle returns the namespace with the given title at the root level.
+func (st DBstore) GetNamespaceByTitle(ctx context.Context, title string, orgID int64, user identity.Requester) (*folder.Folder, error) {
+	return st.FolderService.Get(ctx, &folder.GetFolderQuery{OrgID: orgID, Title: &title, WithFullpath: true, SignedInUser: user})
+}
+
+// GetOrCreateNamespaceByTitle returns the namespace with the given title at the root level, the folder is created if it does not exist.
+func (st DBstore) GetOrCreateNamespaceByTitle(ctx context.Context, title string, orgID int64, user identity.Requester) (*folder.Folder, error) {
+	f, err := st.GetNamespaceByTitle(ctx, title, orgID, user)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, dashboards.ErrFolderNotFound) && !errors.Is(err, folder.ErrFolderNotFound) {
+		return nil, err
+	}
+	return st.FolderService.Create(ctx, &folder.CreateFolderCommand{OrgID: orgID, Title: title, SignedInUser: user})
+}
+
 func (st DBstore) GetAlertRulesKeysForScheduling(ctx context.Context) ([]ngmodels.AlertRuleKeyWithVersion, error) {
 	var result []ngmodels.AlertRuleKeyWithVersion
 	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
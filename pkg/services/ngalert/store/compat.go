@@ -93,6 +93,13 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		}
 	}
 
+	if ar.Dependencies != "" {
+		err = json.Unmarshal([]byte(ar.Dependencies), &result.Dependencies)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse dependencies: %w", err)
+		}
+	}
+
 	return result, nil
 }
 
@@ -174,6 +181,14 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 	}
 	result.Metadata = string(metadata)
 
+	if len(ar.Dependencies) > 0 {
+		dependenciesData, err := json.Marshal(ar.Dependencies)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal dependencies: %w", err)
+		}
+		result.Dependencies = string(dependenciesData)
+	}
+
 	return result, nil
 }
 
@@ -202,6 +217,7 @@ func alertRuleToAlertRuleVersion(rule alertRule) alertRuleVersion {
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: rule.NotificationSettings,
 		Metadata:             rule.Metadata,
+		Dependencies:         rule.Dependencies,
 	}
 }
 
@@ -233,5 +249,6 @@ func alertRuleVersionToAlertRule(version alertRuleVersion) alertRule {
 		IsPaused:             version.IsPaused,
 		NotificationSettings: version.NotificationSettings,
 		Metadata:             version.Metadata,
+		Dependencies:         version.Dependencies,
 	}
 }
@@ -28,6 +28,7 @@ type alertRule struct {
 	IsPaused             bool
 	NotificationSettings string `xorm:"notification_settings"`
 	Metadata             string `xorm:"metadata"`
+	Dependencies         string `xorm:"dependencies"`
 }
 
 func (a alertRule) TableName() string {
@@ -63,6 +64,7 @@ type alertRuleVersion struct {
 	IsPaused             bool
 	NotificationSettings string `xorm:"notification_settings"`
 	Metadata             string `xorm:"metadata"`
+	Dependencies         string `xorm:"dependencies"`
 }
 
 // EqualSpec compares two alertRuleVersion objects for equality based on their specifications and returns true if they match.
@@ -85,7 +87,8 @@ func (a alertRuleVersion) EqualSpec(b alertRuleVersion) bool {
 		a.Labels == b.Labels &&
 		a.IsPaused == b.IsPaused &&
 		a.NotificationSettings == b.NotificationSettings &&
-		a.Metadata == b.Metadata
+		a.Metadata == b.Metadata &&
+		a.Dependencies == b.Dependencies
 }
 
 func (a alertRuleVersion) TableName() string {
@@ -0,0 +1,197 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// defaultNotificationDeliveriesLimit is the number of deliveries returned by ListNotificationDeliveries if the query
+// has no limit.
+const defaultNotificationDeliveriesLimit = 100
+
+// notificationDelivery represents a record in alert_notification_delivery table
+type notificationDelivery struct {
+	ID                int64  `xorm:"pk autoincr 'id'"`
+	OrgID             int64  `xorm:"org_id"`
+	Created           int64  `xorm:"'created'"`
+	Receiver          string `xorm:"receiver"`
+	IntegrationUID    string `xorm:"integration_uid"`
+	IntegrationType   string `xorm:"integration_type"`
+	IntegrationIndex  int    `xorm:"integration_index"`
+	GroupKey          string `xorm:"group_key"`
+	GroupLabels       string `xorm:"group_labels"`
+	Alerts            string `xorm:"alerts"`
+	AlertFingerprints string `xorm:"alert_fingerprints"`
+	PayloadDigest     string `xorm:"payload_digest"`
+	StatusCode        int    `xorm:"status_code"`
+	Error             string `xorm:"error"`
+	Retry             int    `xorm:"retry"`
+	DurationMs        int64  `xorm:"duration_ms"`
+	ResentFrom        int64  `xorm:"resent_from"`
+}
+
+func (d notificationDelivery) TableName() string {
+	return "alert_notification_delivery"
+}
+
+// InsertNotificationDelivery saves a notification delivery and returns it with its ID.
+func (st DBstore) InsertNotificationDelivery(ctx context.Context, delivery models.NotificationDelivery) (models.NotificationDelivery, error) {
+	row, err := notificationDeliveryFromModel(delivery)
+	if err != nil {
+		return models.NotificationDelivery{}, err
+	}
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&row); err != nil {
+			return fmt.Errorf("failed to insert notification delivery: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.NotificationDelivery{}, err
+	}
+	delivery.ID = row.ID
+	return delivery, nil
+}
+
+// GetNotificationDelivery returns the notification delivery with the given ID. It returns
+// models.ErrNotificationDeliveryNotFound if the delivery does not exist.
+func (st DBstore) GetNotificationDelivery(ctx context.Context, orgID int64, id int64) (models.NotificationDelivery, error) {
+	var result models.NotificationDelivery
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := notificationDelivery{}
+		has, err := sess.Where("org_id = ? AND id = ?", orgID, id).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return models.ErrNotificationDeliveryNotFound.Errorf("")
+		}
+		result, err = notificationDeliveryToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// ListNotificationDeliveries returns the notification deliveries that match the query, from the most recent.
+func (st DBstore) ListNotificationDeliveries(ctx context.Context, query models.ListNotificationDeliveriesQuery) ([]models.NotificationDelivery, error) {
+	var result []models.NotificationDelivery
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.IntegrationUID != "" {
+			q = q.And("integration_uid = ?", query.IntegrationUID)
+		}
+		if query.AlertFingerprint != "" {
+			q = q.And("alert_fingerprints LIKE ?", "%"+query.AlertFingerprint+"%")
+		}
+		if !query.From.IsZero() {
+			q = q.And("created >= ?", query.From.UnixMilli())
+		}
+		if !query.To.IsZero() {
+			q = q.And("created <= ?", query.To.UnixMilli())
+		}
+		if query.OnlyFailed {
+			q = q.And("error IS NOT NULL AND error <> ''")
+		}
+		limit := query.Limit
+		if limit <= 0 {
+			limit = defaultNotificationDeliveriesLimit
+		}
+		var rows []notificationDelivery
+		if err := q.Desc("created", "id").Limit(limit).Find(&rows); err != nil {
+			return err
+		}
+		result = make([]models.NotificationDelivery, 0, len(rows))
+		for _, row := range rows {
+			d, err := notificationDeliveryToModel(row)
+			if err != nil {
+				return err
+			}
+			result = append(result, d)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// DeleteNotificationDeliveriesBefore deletes the notification deliveries of all organizations created before the
+// given time, and returns the number of deleted deliveries.
+func (st DBstore) DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM alert_notification_delivery WHERE created < ?", before.UnixMilli())
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
+
+func notificationDeliveryFromModel(d models.NotificationDelivery) (notificationDelivery, error) {
+	groupLabels, err := json.Marshal(d.GroupLabels)
+	if err != nil {
+		return notificationDelivery{}, fmt.Errorf("failed to marshal group labels: %w", err)
+	}
+	alerts, err := json.Marshal(d.Alerts)
+	if err != nil {
+		return notificationDelivery{}, fmt.Errorf("failed to marshal alerts: %w", err)
+	}
+	return notificationDelivery{
+		ID:                d.ID,
+		OrgID:             d.OrgID,
+		Created:           d.Created.UnixMilli(),
+		Receiver:          d.Receiver,
+		IntegrationUID:    d.IntegrationUID,
+		IntegrationType:   d.IntegrationType,
+		IntegrationIndex:  d.IntegrationIndex,
+		GroupKey:          d.GroupKey,
+		GroupLabels:       string(groupLabels),
+		Alerts:            string(alerts),
+		AlertFingerprints: strings.Join(d.AlertFingerprints, ","),
+		PayloadDigest:     d.PayloadDigest,
+		StatusCode:        d.StatusCode,
+		Error:             d.Error,
+		Retry:             d.Retry,
+		DurationMs:        d.Duration.Milliseconds(),
+		ResentFrom:        d.ResentFrom,
+	}, nil
+}
+
+func notificationDeliveryToModel(row notificationDelivery) (models.NotificationDelivery, error) {
+	result := models.NotificationDelivery{
+		ID:               row.ID,
+		OrgID:            row.OrgID,
+		Created:          time.UnixMilli(row.Created).UTC(),
+		Receiver:         row.Receiver,
+		IntegrationUID:   row.IntegrationUID,
+		IntegrationType:  row.IntegrationType,
+		IntegrationIndex: row.IntegrationIndex,
+		GroupKey:         row.GroupKey,
+		PayloadDigest:    row.PayloadDigest,
+		StatusCode:       row.StatusCode,
+		Error:            row.Error,
+		Retry:            row.Retry,
+		Duration:         time.Duration(row.DurationMs) * time.Millisecond,
+		ResentFrom:       row.ResentFrom,
+	}
+	if row.AlertFingerprints != "" {
+		result.AlertFingerprints = strings.Split(row.AlertFingerprints, ",")
+	}
+	if err := json.Unmarshal([]byte(row.GroupLabels), &result.GroupLabels); err != nil {
+		return models.NotificationDelivery{}, fmt.Errorf("failed to parse group labels of notification delivery %d: %w", row.ID, err)
+	}
+	if err := json.Unmarshal([]byte(row.Alerts), &result.Alerts); err != nil {
+		return models.NotificationDelivery{}, fmt.Errorf("failed to parse alerts of notification delivery %d: %w", row.ID, err)
+	}
+	return result, nil
+}
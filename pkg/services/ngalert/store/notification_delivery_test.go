@@ -0,0 +1,118 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationDeliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	insert := func(d models.NotificationDelivery) models.NotificationDelivery {
+		t.Helper()
+		saved, err := dbstore.InsertNotificationDelivery(ctx, d)
+		require.NoError(t, err)
+		return saved
+	}
+	failed := insert(models.NotificationDelivery{
+		OrgID:             1,
+		Created:           now.Add(-2 * time.Hour),
+		Receiver:          "team-a",
+		IntegrationUID:    "webhook-uid",
+		IntegrationType:   "webhook",
+		GroupKey:          "{}:{}",
+		GroupLabels:       map[string]string{"alertname": "test"},
+		Alerts:            []models.NotificationDeliveryAlert{{Labels: map[string]string{"alertname": "test"}, StartsAt: now.Add(-3 * time.Hour)}},
+		AlertFingerprints: []string{"0123456789abcdef", "fedcba9876543210"},
+		PayloadDigest:     "digest",
+		StatusCode:        500,
+		Error:             "webhook response status 500 Internal Server Error",
+		Duration:          1500 * time.Millisecond,
+	})
+	retried := insert(models.NotificationDelivery{
+		OrgID:             1,
+		Created:           now.Add(-time.Hour),
+		Receiver:          "team-a",
+		IntegrationUID:    "webhook-uid",
+		IntegrationType:   "webhook",
+		AlertFingerprints: []string{"0123456789abcdef"},
+		StatusCode:        200,
+		Retry:             1,
+	})
+	other := insert(models.NotificationDelivery{
+		OrgID:             1,
+		Created:           now,
+		Receiver:          "team-b",
+		IntegrationUID:    "email-uid",
+		IntegrationType:   "email",
+		AlertFingerprints: []string{"1111111111111111"},
+	})
+	insert(models.NotificationDelivery{OrgID: 2, Created: now, Receiver: "team-a"})
+
+	t.Run("gets a delivery of the organization", func(t *testing.T) {
+		d, err := dbstore.GetNotificationDelivery(ctx, 1, failed.ID)
+		require.NoError(t, err)
+		require.Equal(t, failed, d)
+
+		_, err = dbstore.GetNotificationDelivery(ctx, 2, failed.ID)
+		require.ErrorIs(t, err, models.ErrNotificationDeliveryNotFound)
+	})
+
+	t.Run("lists deliveries from the most recent", func(t *testing.T) {
+		deliveries, err := dbstore.ListNotificationDeliveries(ctx, models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []int64{other.ID, retried.ID, failed.ID}, deliveryIDs(deliveries))
+	})
+
+	t.Run("filters deliveries", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			query    models.ListNotificationDeliveriesQuery
+			expected []int64
+		}{
+			{"by receiver", models.ListNotificationDeliveriesQuery{Receiver: "team-a"}, []int64{retried.ID, failed.ID}},
+			{"by integration", models.ListNotificationDeliveriesQuery{IntegrationUID: "email-uid"}, []int64{other.ID}},
+			{"by alert fingerprint", models.ListNotificationDeliveriesQuery{AlertFingerprint: "fedcba9876543210"}, []int64{failed.ID}},
+			{"by time range", models.ListNotificationDeliveriesQuery{From: now.Add(-90 * time.Minute), To: now.Add(-time.Minute)}, []int64{retried.ID}},
+			{"failed only", models.ListNotificationDeliveriesQuery{OnlyFailed: true}, []int64{failed.ID}},
+			{"with limit", models.ListNotificationDeliveriesQuery{Limit: 1}, []int64{other.ID}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				tc.query.OrgID = 1
+				deliveries, err := dbstore.ListNotificationDeliveries(ctx, tc.query)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, deliveryIDs(deliveries))
+			})
+		}
+	})
+
+	t.Run("deletes deliveries older than the retention", func(t *testing.T) {
+		deleted, err := dbstore.DeleteNotificationDeliveriesBefore(ctx, now.Add(-30*time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		deliveries, err := dbstore.ListNotificationDeliveries(ctx, models.ListNotificationDeliveriesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []int64{other.ID}, deliveryIDs(deliveries))
+	})
+}
+
+func deliveryIDs(deliveries []models.NotificationDelivery) []int64 {
+	ids := make([]int64, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
@@ -0,0 +1,243 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// silenceTemplate represents a record in alert_silence_template table
+type silenceTemplate struct {
+	ID                int64  `xorm:"pk autoincr 'id'"`
+	OrgID             int64  `xorm:"org_id"`
+	UID               string `xorm:"uid"`
+	Title             string
+	Matchers          string
+	Comment           string
+	DurationSeconds   int64
+	Schedule          string
+	Updated           time.Time
+	MaterializedUntil int64
+}
+
+func (t silenceTemplate) TableName() string {
+	return "alert_silence_template"
+}
+
+// ListSilenceTemplates returns the silence templates of the organization ordered by title.
+func (st DBstore) ListSilenceTemplates(ctx context.Context, orgID int64) ([]models.SilenceTemplate, error) {
+	var result []models.SilenceTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []silenceTemplate
+		if err := sess.Where("org_id = ?", orgID).Asc("title").Find(&rows); err != nil {
+			return err
+		}
+		var err error
+		result, err = silenceTemplatesToModel(rows)
+		return err
+	})
+	return result, err
+}
+
+// ListRecurringSilenceTemplates returns the silence templates of all organizations that have a schedule.
+func (st DBstore) ListRecurringSilenceTemplates(ctx context.Context) ([]models.SilenceTemplate, error) {
+	var result []models.SilenceTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []silenceTemplate
+		if err := sess.Where("schedule IS NOT NULL AND schedule <> ''").Asc("org_id", "id").Find(&rows); err != nil {
+			return err
+		}
+		var err error
+		result, err = silenceTemplatesToModel(rows)
+		return err
+	})
+	return result, err
+}
+
+// GetSilenceTemplate returns the silence template with the given UID. It returns models.ErrSilenceTemplateNotFound
+// if the template does not exist.
+func (st DBstore) GetSilenceTemplate(ctx context.Context, orgID int64, uid string) (models.SilenceTemplate, error) {
+	var result models.SilenceTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		row := silenceTemplate{}
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return models.ErrSilenceTemplateNotFound.Errorf("")
+		}
+		result, err = silenceTemplateToModel(row)
+		return err
+	})
+	return result, err
+}
+
+// InsertSilenceTemplate saves a new silence template. A UID is generated if the template has none.
+func (st DBstore) InsertSilenceTemplate(ctx context.Context, template models.SilenceTemplate) (models.SilenceTemplate, error) {
+	if template.UID == "" {
+		template.UID = util.GenerateShortUID()
+	}
+	template.Updated = TimeNow()
+	row, err := silenceTemplateFromModel(template)
+	if err != nil {
+		return models.SilenceTemplate{}, err
+	}
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&row); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrSilenceTemplateExists.Errorf("")
+			}
+			return fmt.Errorf("failed to insert silence template: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.SilenceTemplate{}, err
+	}
+	return template, nil
+}
+
+// UpdateSilenceTemplate replaces the silence template with the same UID. The end of the last silence created for
+// the schedule is kept, so that silences that were already created are not created again.
+func (st DBstore) UpdateSilenceTemplate(ctx context.Context, template models.SilenceTemplate) (models.SilenceTemplate, error) {
+	template.Updated = TimeNow()
+	row, err := silenceTemplateFromModel(template)
+	if err != nil {
+		return models.SilenceTemplate{}, err
+	}
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id = ? AND uid = ?", template.OrgID, template.UID).
+			Cols("title", "matchers", "comment", "duration_seconds", "schedule", "updated").
+			Update(&row)
+		if err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return models.ErrSilenceTemplateExists.Errorf("")
+			}
+			return fmt.Errorf("failed to update silence template: %w", err)
+		}
+		if affected > 0 {
+			return nil
+		}
+		// MySQL does not count rows that were not changed by the update.
+		exists, err := sess.Table(silenceTemplate{}).Where("org_id = ? AND uid = ?", template.OrgID, template.UID).Exist()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrSilenceTemplateNotFound.Errorf("")
+		}
+		return nil
+	})
+	if err != nil {
+		return models.SilenceTemplate{}, err
+	}
+	return template, nil
+}
+
+// DeleteSilenceTemplate deletes the silence template with the given UID.
+func (st DBstore) DeleteSilenceTemplate(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_silence_template WHERE org_id = ? AND uid = ?", orgID, uid)
+		return err
+	})
+}
+
+// UpdateSilenceTemplateMaterializedUntil sets the end of the last silence created for the schedule of the template,
+// if it has not changed since it was read. It returns false if another replica changed it in the meantime.
+func (st DBstore) UpdateSilenceTemplateMaterializedUntil(ctx context.Context, orgID int64, uid string, previous, next time.Time) (bool, error) {
+	var updated bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE alert_silence_template SET materialized_until = ? WHERE org_id = ? AND uid = ? AND materialized_until = ?",
+			unixOrZero(next), orgID, uid, unixOrZero(previous))
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		updated = affected > 0
+		return nil
+	})
+	return updated, err
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func silenceTemplateFromModel(t models.SilenceTemplate) (silenceTemplate, error) {
+	matchers, err := json.Marshal(t.Matchers)
+	if err != nil {
+		return silenceTemplate{}, fmt.Errorf("failed to marshal matchers: %w", err)
+	}
+	var schedule []byte
+	if len(t.Schedule) > 0 {
+		schedule, err = json.Marshal(t.Schedule)
+		if err != nil {
+			return silenceTemplate{}, fmt.Errorf("failed to marshal schedule: %w", err)
+		}
+	}
+	return silenceTemplate{
+		OrgID:             t.OrgID,
+		UID:               t.UID,
+		Title:             t.Title,
+		Matchers:          string(matchers),
+		Comment:           t.Comment,
+		DurationSeconds:   int64(t.Duration.Seconds()),
+		Schedule:          string(schedule),
+		Updated:           t.Updated,
+		MaterializedUntil: unixOrZero(t.MaterializedUntil),
+	}, nil
+}
+
+func silenceTemplateToModel(row silenceTemplate) (models.SilenceTemplate, error) {
+	result := models.SilenceTemplate{
+		UID:      row.UID,
+		OrgID:    row.OrgID,
+		Title:    row.Title,
+		Comment:  row.Comment,
+		Duration: time.Duration(row.DurationSeconds) * time.Second,
+		Updated:  row.Updated,
+	}
+	if row.MaterializedUntil > 0 {
+		result.MaterializedUntil = time.Unix(row.MaterializedUntil, 0).UTC()
+	}
+	var matchers amv2.Matchers
+	if err := json.Unmarshal([]byte(row.Matchers), &matchers); err != nil {
+		return models.SilenceTemplate{}, fmt.Errorf("failed to parse matchers of silence template %s: %w", row.UID, err)
+	}
+	result.Matchers = matchers
+	if row.Schedule != "" {
+		var schedule []timeinterval.TimeInterval
+		if err := json.Unmarshal([]byte(row.Schedule), &schedule); err != nil {
+			return models.SilenceTemplate{}, fmt.Errorf("failed to parse schedule of silence template %s: %w", row.UID, err)
+		}
+		result.Schedule = schedule
+	}
+	return result, nil
+}
+
+func silenceTemplatesToModel(rows []silenceTemplate) ([]models.SilenceTemplate, error) {
+	result := make([]models.SilenceTemplate, 0, len(rows))
+	for _, row := range rows {
+		t, err := silenceTemplateToModel(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
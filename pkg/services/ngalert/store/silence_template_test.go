@@ -0,0 +1,89 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestIntegrationSilenceTemplates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	var schedule []timeinterval.TimeInterval
+	require.NoError(t, yaml.Unmarshal([]byte("- weekdays: ['sunday']\n  times: [{start_time: '02:00', end_time: '04:00'}]"), &schedule))
+	matchers := amv2.Matchers{{
+		Name:    util.Pointer("env"),
+		Value:   util.Pointer("maint"),
+		IsEqual: util.Pointer(true),
+		IsRegex: util.Pointer(false),
+	}}
+
+	onDemand, err := dbstore.InsertSilenceTemplate(ctx, models.SilenceTemplate{OrgID: 1, Title: "b", Matchers: matchers, Duration: time.Hour})
+	require.NoError(t, err)
+	recurring, err := dbstore.InsertSilenceTemplate(ctx, models.SilenceTemplate{OrgID: 1, UID: "weekly", Title: "a", Matchers: matchers, Schedule: schedule})
+	require.NoError(t, err)
+	require.NotEmpty(t, onDemand.UID)
+	require.Equal(t, "weekly", recurring.UID)
+
+	t.Run("titles are unique within an organization", func(t *testing.T) {
+		_, err := dbstore.InsertSilenceTemplate(ctx, models.SilenceTemplate{OrgID: 1, Title: "a", Matchers: matchers, Duration: time.Hour})
+		require.ErrorIs(t, err, models.ErrSilenceTemplateExists)
+	})
+
+	t.Run("lists templates ordered by title", func(t *testing.T) {
+		templates, err := dbstore.ListSilenceTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, templates, 2)
+		require.Equal(t, "weekly", templates[0].UID)
+		require.Equal(t, schedule, templates[0].Schedule)
+		require.Equal(t, onDemand.UID, templates[1].UID)
+		require.Equal(t, time.Hour, templates[1].Duration)
+	})
+
+	t.Run("lists only recurring templates", func(t *testing.T) {
+		templates, err := dbstore.ListRecurringSilenceTemplates(ctx)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, "weekly", templates[0].UID)
+	})
+
+	t.Run("updates materialized windows only if unchanged", func(t *testing.T) {
+		end := time.Date(2024, 6, 2, 4, 0, 0, 0, time.UTC)
+		ok, err := dbstore.UpdateSilenceTemplateMaterializedUntil(ctx, 1, "weekly", time.Time{}, end)
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = dbstore.UpdateSilenceTemplateMaterializedUntil(ctx, 1, "weekly", time.Time{}, end)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		update := recurring
+		update.Comment = "updated"
+		_, err = dbstore.UpdateSilenceTemplate(ctx, update)
+		require.NoError(t, err)
+		got, err := dbstore.GetSilenceTemplate(ctx, 1, "weekly")
+		require.NoError(t, err)
+		require.Equal(t, "updated", got.Comment)
+		require.Equal(t, end, got.MaterializedUntil)
+	})
+
+	t.Run("deletes templates", func(t *testing.T) {
+		require.NoError(t, dbstore.DeleteSilenceTemplate(ctx, 1, onDemand.UID))
+		_, err := dbstore.GetSilenceTemplate(ctx, 1, onDemand.UID)
+		require.ErrorIs(t, err, models.ErrSilenceTemplateNotFound)
+		_, err = dbstore.UpdateSilenceTemplate(ctx, onDemand)
+		require.ErrorIs(t, err, models.ErrSilenceTemplateNotFound)
+	})
+}
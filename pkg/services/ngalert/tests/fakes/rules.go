@@ -11,6 +11,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util"
@@ -258,6 +259,57 @@ func (f *RuleStore) GetNamespaceByUID(_ context.Context, uid string, orgID int64
 	return nil, fmt.Errorf("not found")
 }
 
+func (f *RuleStore) GetNamespaceByTitle(_ context.Context, title string, orgID int64, user identity.Requester) (*folder.Folder, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q := GenericRecordedQuery{
+		Name:   "GetNamespaceByTitle",
+		Params: []any{orgID, title, user},
+	}
+	defer func() {
+		f.RecordedOps = append(f.RecordedOps, q)
+	}()
+	err := f.Hook(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range f.Folders[orgID] {
+		if folder.Title == title && folder.ParentUID == "" {
+			return folder, nil
+		}
+	}
+	return nil, dashboards.ErrFolderNotFound
+}
+
+func (f *RuleStore) GetOrCreateNamespaceByTitle(_ context.Context, title string, orgID int64, user identity.Requester) (*folder.Folder, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q := GenericRecordedQuery{
+		Name:   "GetOrCreateNamespaceByTitle",
+		Params: []any{orgID, title, user},
+	}
+	defer func() {
+		f.RecordedOps = append(f.RecordedOps, q)
+	}()
+	err := f.Hook(q)
+	if err != nil {
+		return nil, err
+	}
+	for _, folder := range f.Folders[orgID] {
+		if folder.Title == title && folder.ParentUID == "" {
+			return folder, nil
+		}
+	}
+	newFolder := &folder.Folder{
+		UID:      util.GenerateShortUID(),
+		OrgID:    orgID,
+		Title:    title,
+		Fullpath: title,
+	}
+	f.Folders[orgID] = append(f.Folders[orgID], newFolder)
+	return newFolder, nil
+}
+
 func (f *RuleStore) UpdateAlertRules(_ context.Context, _ *models.UserUID, q []models.UpdateRule) error {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
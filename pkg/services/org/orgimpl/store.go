@@ -240,6 +240,7 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			"DELETE FROM alert WHERE org_id = ?",
 			"DELETE FROM annotation WHERE org_id = ?",
 			"DELETE FROM kv_store WHERE org_id = ?",
+			"DELETE FROM dashboard_version_retention WHERE org_id = ?",
 			"DELETE FROM team WHERE org_id = ?",
 			"DELETE FROM team_member WHERE org_id = ?",
 			"DELETE FROM team_role WHERE org_id = ?",
@@ -819,6 +820,9 @@ func (ss *sqlStore) userDeletions() []string {
 		"DELETE FROM user_auth WHERE user_id = ?",
 		"DELETE FROM user_auth_token WHERE user_id = ?",
 		"DELETE FROM quota WHERE user_id = ?",
+		"DELETE FROM user_mfa_factor WHERE user_id = ?",
+		"DELETE FROM user_mfa_recovery_code WHERE user_id = ?",
+		"DELETE FROM access_grant WHERE user_id = ?",
 	}
 	return deletes
 }
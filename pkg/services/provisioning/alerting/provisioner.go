@@ -19,6 +19,7 @@ type ProvisionerConfig struct {
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
 	TemplateService            provisioning.TemplateService
+	SilenceTemplateService     provisioning.SilenceTemplateService
 }
 
 func Provision(ctx context.Context, cfg ProvisionerConfig) error {
@@ -45,6 +46,11 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
 	}
+	stProvisioner := NewSilenceTemplatesProvisioner(logger, cfg.SilenceTemplateService)
+	err = stProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("silence templates: %w", err)
+	}
 	npProvisioner := NewNotificationPolicyProvisoner(logger, cfg.NotificiationPolicyService)
 	err = npProvisioner.Provision(ctx, files)
 	if err != nil {
@@ -62,6 +68,10 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
 	}
+	err = stProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("silence templates: %w", err)
+	}
 	ruleProvisioner := NewAlertRuleProvisioner(
 		logger,
 		cfg.FolderService,
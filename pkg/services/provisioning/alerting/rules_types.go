@@ -77,6 +77,7 @@ type AlertRuleV1 struct {
 	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
 	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
 	Record               *RecordV1               `json:"record" yaml:"record"`
+	Dependencies         []DependencyV1          `json:"dependencies" yaml:"dependencies"`
 }
 
 func withFallback(value, fallback string) *string {
@@ -162,6 +163,9 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 		alertRule.Record = &record
 	}
+	for _, dep := range rule.Dependencies {
+		alertRule.Dependencies = append(alertRule.Dependencies, dep.mapToModel())
+	}
 	return alertRule, nil
 }
 
@@ -281,3 +285,15 @@ func (record *RecordV1) mapToModel() (models.Record, error) {
 		From:   record.From.Value(),
 	}, nil
 }
+
+type DependencyV1 struct {
+	RuleUID values.StringValue `json:"ruleUid" yaml:"ruleUid"`
+	RefID   values.StringValue `json:"refId" yaml:"refId"`
+}
+
+func (dep *DependencyV1) mapToModel() models.RuleDependency {
+	return models.RuleDependency{
+		RuleUID: dep.RuleUID.Value(),
+		RefID:   dep.RefID.Value(),
+	}
+}
@@ -0,0 +1,63 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type SilenceTemplatesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultSilenceTemplatesProvisioner struct {
+	logger                 log.Logger
+	silenceTemplateService provisioning.SilenceTemplateService
+}
+
+func NewSilenceTemplatesProvisioner(logger log.Logger,
+	silenceTemplateService provisioning.SilenceTemplateService) SilenceTemplatesProvisioner {
+	return &defaultSilenceTemplatesProvisioner{
+		logger:                 logger,
+		silenceTemplateService: silenceTemplateService,
+	}
+}
+
+func (c *defaultSilenceTemplatesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, st := range file.SilenceTemplates {
+			_, _, err := c.silenceTemplateService.GetSilenceTemplate(ctx, st.OrgID, st.Template.UID)
+			if err == nil {
+				if _, err := c.silenceTemplateService.UpdateSilenceTemplate(ctx, st.OrgID, st.Template, models.ProvenanceFile); err != nil {
+					return err
+				}
+				continue
+			}
+			if !errors.Is(err, models.ErrSilenceTemplateNotFound) {
+				return err
+			}
+			if _, err := c.silenceTemplateService.CreateSilenceTemplate(ctx, st.OrgID, st.Template, models.ProvenanceFile); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultSilenceTemplatesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deleteSilenceTemplate := range file.DeleteSilenceTemplates {
+			err := c.silenceTemplateService.DeleteSilenceTemplate(ctx, deleteSilenceTemplate.OrgID, deleteSilenceTemplate.UID, models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type SilenceTemplateV1 struct {
+	OrgID    values.Int64Value           `json:"orgId" yaml:"orgId"`
+	UID      values.StringValue          `json:"uid" yaml:"uid"`
+	Title    values.StringValue          `json:"title" yaml:"title"`
+	Matchers definitions.ObjectMatchers  `json:"matchers" yaml:"matchers"`
+	Comment  values.StringValue          `json:"comment" yaml:"comment"`
+	Duration values.StringValue          `json:"duration" yaml:"duration"`
+	Schedule []timeinterval.TimeInterval `json:"schedule" yaml:"schedule"`
+}
+
+func (v1 *SilenceTemplateV1) mapToModel() (SilenceTemplate, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return SilenceTemplate{}, errors.New("silence template missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	var duration time.Duration
+	if d := strings.TrimSpace(v1.Duration.Value()); d != "" {
+		parsed, err := model.ParseDuration(d)
+		if err != nil {
+			return SilenceTemplate{}, fmt.Errorf("silence template '%s' has invalid duration: %w", uid, err)
+		}
+		duration = time.Duration(parsed)
+	}
+	matchers := make(amv2.Matchers, 0, len(v1.Matchers))
+	for _, m := range v1.Matchers {
+		isEqual := m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp
+		isRegex := m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp
+		matchers = append(matchers, &amv2.Matcher{
+			Name:    &m.Name,
+			Value:   &m.Value,
+			IsEqual: &isEqual,
+			IsRegex: &isRegex,
+		})
+	}
+	return SilenceTemplate{
+		OrgID: orgID,
+		Template: models.SilenceTemplate{
+			UID:      uid,
+			OrgID:    orgID,
+			Title:    v1.Title.Value(),
+			Matchers: matchers,
+			Comment:  v1.Comment.Value(),
+			Duration: duration,
+			Schedule: v1.Schedule,
+		},
+	}, nil
+}
+
+type SilenceTemplate struct {
+	OrgID    int64
+	Template models.SilenceTemplate
+}
+
+type DeleteSilenceTemplateV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteSilenceTemplateV1) mapToModel() (DeleteSilenceTemplate, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteSilenceTemplate{}, errors.New("delete silence template missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteSilenceTemplate{
+		OrgID: orgID,
+		UID:   uid,
+	}, nil
+}
+
+type DeleteSilenceTemplate struct {
+	OrgID int64
+	UID   string
+}
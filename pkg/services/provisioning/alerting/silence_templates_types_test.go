@@ -0,0 +1,51 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSilenceTemplate(t *testing.T) {
+	data := `orgId: 2
+uid: weekly-maintenance
+title: Weekly maintenance
+comment: Database maintenance
+duration: 2h
+matchers:
+  - ['env', '=', 'maint']
+  - ['service', '!~', 'db-.*']
+schedule:
+  - weekdays: ['sunday']
+    times:
+      - start_time: '02:00'
+        end_time: '04:00'
+`
+	var model SilenceTemplateV1
+	require.NoError(t, yaml.Unmarshal([]byte(data), &model))
+	st, err := model.mapToModel()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), st.OrgID)
+	require.Equal(t, int64(2), st.Template.OrgID)
+	require.Equal(t, "weekly-maintenance", st.Template.UID)
+	require.Equal(t, "Weekly maintenance", st.Template.Title)
+	require.Equal(t, "Database maintenance", st.Template.Comment)
+	require.Equal(t, 2*time.Hour, st.Template.Duration)
+	require.Len(t, st.Template.Schedule, 1)
+	require.Len(t, st.Template.Matchers, 2)
+	require.Equal(t, "env", *st.Template.Matchers[0].Name)
+	require.True(t, *st.Template.Matchers[0].IsEqual)
+	require.False(t, *st.Template.Matchers[0].IsRegex)
+	require.Equal(t, "db-.*", *st.Template.Matchers[1].Value)
+	require.False(t, *st.Template.Matchers[1].IsEqual)
+	require.True(t, *st.Template.Matchers[1].IsRegex)
+	require.NoError(t, st.Template.Validate())
+
+	t.Run("requires a uid", func(t *testing.T) {
+		model := SilenceTemplateV1{}
+		_, err := model.mapToModel()
+		require.Error(t, err)
+	})
+}
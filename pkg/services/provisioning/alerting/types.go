@@ -15,32 +15,36 @@ type OrgID int64
 
 type AlertingFile struct {
 	configVersion
-	Filename            string
-	Groups              []models.AlertRuleGroupWithFolderFullpath
-	DeleteRules         []RuleDelete
-	ContactPoints       []ContactPoint
-	DeleteContactPoints []DeleteContactPoint
-	Policies            []NotificiationPolicy
-	ResetPolicies       []OrgID
-	MuteTimes           []MuteTime
-	DeleteMuteTimes     []DeleteMuteTime
-	Templates           []Template
-	DeleteTemplates     []DeleteTemplate
+	Filename               string
+	Groups                 []models.AlertRuleGroupWithFolderFullpath
+	DeleteRules            []RuleDelete
+	ContactPoints          []ContactPoint
+	DeleteContactPoints    []DeleteContactPoint
+	Policies               []NotificiationPolicy
+	ResetPolicies          []OrgID
+	MuteTimes              []MuteTime
+	DeleteMuteTimes        []DeleteMuteTime
+	Templates              []Template
+	DeleteTemplates        []DeleteTemplate
+	SilenceTemplates       []SilenceTemplate
+	DeleteSilenceTemplates []DeleteSilenceTemplate
 }
 
 type AlertingFileV1 struct {
 	configVersion
-	Filename            string
-	Groups              []AlertRuleGroupV1      `json:"groups" yaml:"groups"`
-	DeleteRules         []RuleDeleteV1          `json:"deleteRules" yaml:"deleteRules"`
-	ContactPoints       []ContactPointV1        `json:"contactPoints" yaml:"contactPoints"`
-	DeleteContactPoints []DeleteContactPointV1  `json:"deleteContactPoints" yaml:"deleteContactPoints"`
-	Policies            []NotificiationPolicyV1 `json:"policies" yaml:"policies"`
-	ResetPolicies       []values.Int64Value     `json:"resetPolicies" yaml:"resetPolicies"`
-	MuteTimes           []MuteTimeV1            `json:"muteTimes" yaml:"muteTimes"`
-	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
-	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
-	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	Filename               string
+	Groups                 []AlertRuleGroupV1        `json:"groups" yaml:"groups"`
+	DeleteRules            []RuleDeleteV1            `json:"deleteRules" yaml:"deleteRules"`
+	ContactPoints          []ContactPointV1          `json:"contactPoints" yaml:"contactPoints"`
+	DeleteContactPoints    []DeleteContactPointV1    `json:"deleteContactPoints" yaml:"deleteContactPoints"`
+	Policies               []NotificiationPolicyV1   `json:"policies" yaml:"policies"`
+	ResetPolicies          []values.Int64Value       `json:"resetPolicies" yaml:"resetPolicies"`
+	MuteTimes              []MuteTimeV1              `json:"muteTimes" yaml:"muteTimes"`
+	DeleteMuteTimes        []DeleteMuteTimeV1        `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
+	Templates              []TemplateV1              `json:"templates" yaml:"templates"`
+	DeleteTemplates        []DeleteTemplateV1        `json:"deleteTemplates" yaml:"deleteTemplates"`
+	SilenceTemplates       []SilenceTemplateV1       `json:"silenceTemplates" yaml:"silenceTemplates"`
+	DeleteSilenceTemplates []DeleteSilenceTemplateV1 `json:"deleteSilenceTemplates" yaml:"deleteSilenceTemplates"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -61,6 +65,9 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
+	if err := fileV1.mapSilenceTemplates(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing silence templates: %w", err)
+	}
 	return alertingFile, nil
 }
 
@@ -78,6 +85,24 @@ func (fileV1 *AlertingFileV1) mapTemplates(alertingFile *AlertingFile) error {
 	return nil
 }
 
+func (fileV1 *AlertingFileV1) mapSilenceTemplates(alertingFile *AlertingFile) error {
+	for _, stV1 := range fileV1.SilenceTemplates {
+		st, err := stV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.SilenceTemplates = append(alertingFile.SilenceTemplates, st)
+	}
+	for _, deleteV1 := range fileV1.DeleteSilenceTemplates {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteSilenceTemplates = append(alertingFile.DeleteSilenceTemplates, delReq)
+	}
+	return nil
+}
+
 func (fileV1 *AlertingFileV1) mapMuteTimes(alertingFile *AlertingFile) error {
 	for _, mtV1 := range fileV1.MuteTimes {
 		alertingFile.MuteTimes = append(alertingFile.MuteTimes, mtV1.mapToModel())
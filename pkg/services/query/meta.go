@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// Status of the queries of a data source in the response metadata.
+const (
+	DatasourceStatusOK      = "ok"
+	DatasourceStatusError   = "error"
+	DatasourceStatusTimeout = "timeout"
+)
+
+// ResponseMeta collects the latency and status of each data source queried for a request.
+type ResponseMeta struct {
+	mu          sync.Mutex
+	Datasources []DatasourceMeta `json:"datasources"`
+}
+
+// DatasourceMeta is the metadata of the queries sent to one data source.
+type DatasourceMeta struct {
+	UID        string   `json:"uid"`
+	Type       string   `json:"type"`
+	RefIDs     []string `json:"refIds"`
+	DurationMs int64    `json:"durationMs"`
+	Status     string   `json:"status"`
+}
+
+type responseMetaKey struct{}
+
+// WithResponseMeta returns a context in which QueryData records the metadata of the queried data sources.
+func WithResponseMeta(ctx context.Context) (context.Context, *ResponseMeta) {
+	meta := &ResponseMeta{Datasources: []DatasourceMeta{}}
+	return context.WithValue(ctx, responseMetaKey{}, meta), meta
+}
+
+func responseMetaFromContext(ctx context.Context) *ResponseMeta {
+	meta, _ := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	return meta
+}
+
+// withoutResponseMeta is used for the queries of a single data source in a mixed request,
+// they are recorded by the fan-out which knows whether they timed out.
+func withoutResponseMeta(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, (*ResponseMeta)(nil))
+}
+
+func (m *ResponseMeta) add(queries []parsedQuery, duration time.Duration, status string) {
+	if m == nil || len(queries) == 0 {
+		return
+	}
+
+	refIDs := make([]string, 0, len(queries))
+	for _, q := range queries {
+		refIDs = append(refIDs, q.query.RefID)
+	}
+	slices.Sort(refIDs)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Datasources = append(m.Datasources, DatasourceMeta{
+		UID:        queries[0].datasource.UID,
+		Type:       queries[0].datasource.Type,
+		RefIDs:     refIDs,
+		DurationMs: duration.Milliseconds(),
+		Status:     status,
+	})
+	slices.SortFunc(m.Datasources, func(a, b DatasourceMeta) int {
+		return strings.Compare(a.UID, b.UID)
+	})
+}
+
+func responsesStatus(responses backend.Responses, err error) string {
+	if err != nil {
+		return DatasourceStatusError
+	}
+	for _, res := range responses {
+		if res.Status == backend.StatusTimeout {
+			return DatasourceStatusTimeout
+		}
+		if res.Error != nil {
+			return DatasourceStatusError
+		}
+	}
+	return DatasourceStatusOK
+}
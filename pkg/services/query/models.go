@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
@@ -21,6 +22,7 @@ type parsedRequest struct {
 	hasExpression bool
 	parsedQueries map[string][]parsedQuery
 	dsTypes       map[string]bool
+	timeout       time.Duration
 }
 
 func (pr parsedRequest) getFlattenedQueries() []parsedQuery {
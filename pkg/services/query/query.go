@@ -96,30 +96,55 @@ func (s *ServiceImpl) QueryData(ctx context.Context, user identity.Requester, sk
 
 	// If there are expressions, handle them and return
 	if parsedReq.hasExpression {
+		if parsedReq.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, parsedReq.timeout)
+			defer cancel()
+		}
 		return s.handleExpressions(ctx, user, parsedReq)
 	}
-	// If there is only one datasource, query it and return
-	if len(parsedReq.parsedQueries) == 1 {
-		return s.handleQuerySingleDatasource(ctx, user, parsedReq)
+	// If there is only one datasource and no timeout, query it and return
+	if len(parsedReq.parsedQueries) == 1 && parsedReq.timeout == 0 {
+		start := time.Now()
+		resp, err := s.handleQuerySingleDatasource(ctx, user, parsedReq)
+		var responses backend.Responses
+		if resp != nil {
+			responses = resp.Responses
+		}
+		responseMetaFromContext(ctx).add(parsedReq.getFlattenedQueries(), time.Since(start), responsesStatus(responses, err))
+		return resp, err
 	}
-	// If there are multiple datasources, handle their queries concurrently and return the aggregate result
-	return s.executeConcurrentQueries(ctx, user, skipDSCache, reqDTO, parsedReq.parsedQueries)
+	// Otherwise handle the queries of each datasource concurrently and return the aggregate result
+	return s.executeConcurrentQueries(ctx, user, skipDSCache, reqDTO, parsedReq.parsedQueries, parsedReq.timeout)
 }
 
 // splitResponse contains the results of a concurrent data source query - the response and any headers
 type splitResponse struct {
+	uid       string
 	responses backend.Responses
 	header    http.Header
+	duration  time.Duration
+	status    string
 }
 
 // executeConcurrentQueries executes queries to multiple datasources concurrently and returns the aggregate result.
-func (s *ServiceImpl) executeConcurrentQueries(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest, queriesbyDs map[string][]parsedQuery) (*backend.QueryDataResponse, error) {
-	g, ctx := errgroup.WithContext(ctx)
+// When a timeout is set, the datasources which haven't responded by then are cancelled and reported as timed out.
+func (s *ServiceImpl) executeConcurrentQueries(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest, queriesbyDs map[string][]parsedQuery, timeout time.Duration) (*backend.QueryDataResponse, error) {
+	var queryCtx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		queryCtx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		queryCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var g errgroup.Group
 	g.SetLimit(s.concurrentQueryLimit) // prevent too many concurrent requests
 	rchan := make(chan splitResponse, len(queriesbyDs))
 
 	// Create panic recovery function for loop below
-	recoveryFn := func(queries []*simplejson.Json) {
+	recoveryFn := func(uid string, queries []*simplejson.Json, start time.Time) {
 		if r := recover(); r != nil {
 			var err error
 			s.log.Error("query datasource panic", "error", r, "stack", log.Stack(1))
@@ -131,44 +156,92 @@ func (s *ServiceImpl) executeConcurrentQueries(ctx context.Context, user identit
 				err = fmt.Errorf("unexpected error - %s", s.cfg.UserFacingDefaultError)
 			}
 			// Due to the panic, there is no valid response for any query for this datasource. Append an error for each one.
-			rchan <- buildErrorResponses(err, queries)
+			rchan <- buildErrorResponses(uid, err, queries, time.Since(start))
 		}
 	}
 
-	// Query each datasource concurrently
-	for _, queries := range queriesbyDs {
-		rawQueries := make([]*simplejson.Json, len(queries))
-		for i := 0; i < len(queries); i++ {
-			rawQueries[i] = queries[i].rawQuery
-		}
-		g.Go(func() error {
-			subDTO := reqDTO.CloneWithQueries(rawQueries)
-			// Handle panics in the datasource qery
-			defer recoveryFn(subDTO.Queries)
-
-			ctxCopy := contexthandler.CopyWithReqContext(ctx)
-			subResp, err := s.QueryData(ctxCopy, user, skipDSCache, subDTO)
-			if err == nil {
-				reqCtx, header := contexthandler.FromContext(ctxCopy), http.Header{}
-				if reqCtx != nil {
-					header = reqCtx.Resp.Header()
-				}
-				rchan <- splitResponse{subResp.Responses, header}
-			} else {
-				// If there was an error, return an error response for each query for this datasource
-				rchan <- buildErrorResponses(err, subDTO.Queries)
+	// Query each datasource concurrently. The loop runs in the background as it blocks
+	// when the concurrency limit is reached, which must not hold the response past the deadline.
+	fanoutStart := time.Now()
+	go func() {
+		for uid, queries := range queriesbyDs {
+			rawQueries := make([]*simplejson.Json, len(queries))
+			for i := 0; i < len(queries); i++ {
+				rawQueries[i] = queries[i].rawQuery
 			}
-			return nil
-		})
-	}
+			g.Go(func() error {
+				// Don't start queries whose results can no longer be used
+				if queryCtx.Err() != nil {
+					return nil
+				}
 
-	if err := g.Wait(); err != nil {
-		return nil, err
+				start := time.Now()
+				subDTO := reqDTO.CloneWithQueries(rawQueries)
+				// The deadline is already set on the context
+				subDTO.Timeout = ""
+				// Handle panics in the datasource qery
+				defer recoveryFn(uid, subDTO.Queries, start)
+
+				ctxCopy := withoutResponseMeta(contexthandler.CopyWithReqContext(queryCtx))
+				subResp, err := s.QueryData(ctxCopy, user, skipDSCache, subDTO)
+				switch {
+				case err != nil && timeout > 0 && errors.Is(queryCtx.Err(), context.DeadlineExceeded):
+					// The datasource gave up because of the deadline
+					rchan <- buildTimeoutResponses(uid, timeout, subDTO.Queries, time.Since(start))
+				case err != nil:
+					// If there was an error, return an error response for each query for this datasource
+					rchan <- buildErrorResponses(uid, err, subDTO.Queries, time.Since(start))
+				default:
+					reqCtx, header := contexthandler.FromContext(ctxCopy), http.Header{}
+					if reqCtx != nil {
+						header = reqCtx.Resp.Header()
+					}
+					rchan <- splitResponse{uid, subResp.Responses, header, time.Since(start), responsesStatus(subResp.Responses, nil)}
+				}
+				return nil
+			})
+		}
+	}()
+
+	// Wait for every datasource to respond, or for the deadline
+	results := make(map[string]splitResponse, len(queriesbyDs))
+	for len(results) < len(queriesbyDs) {
+		select {
+		case result := <-rchan:
+			results[result.uid] = result
+			continue
+		default:
+		}
+		if queryCtx.Err() != nil {
+			break
+		}
+		select {
+		case result := <-rchan:
+			results[result.uid] = result
+		case <-queryCtx.Done():
+		}
 	}
-	close(rchan)
+
 	resp := backend.NewQueryDataResponse()
 	reqCtx := contexthandler.FromContext(ctx)
-	for result := range rchan {
+	meta := responseMetaFromContext(ctx)
+	for uid, queries := range queriesbyDs {
+		result, ok := results[uid]
+		if !ok {
+			rawQueries := make([]*simplejson.Json, len(queries))
+			for i := 0; i < len(queries); i++ {
+				rawQueries[i] = queries[i].rawQuery
+			}
+			if err := ctx.Err(); err != nil {
+				// The whole request was cancelled
+				result = buildErrorResponses(uid, err, rawQueries, time.Since(fanoutStart))
+			} else {
+				s.log.Warn("datasource did not respond before the timeout", "datasource", uid, "timeout", timeout)
+				result = buildTimeoutResponses(uid, timeout, rawQueries, time.Since(fanoutStart))
+			}
+		}
+		meta.add(queries, result.duration, result.status)
+
 		for refId, dataResponse := range result.responses {
 			resp.Responses[refId] = dataResponse
 		}
@@ -189,14 +262,28 @@ func (s *ServiceImpl) executeConcurrentQueries(ctx context.Context, user identit
 }
 
 // buildErrorResponses applies the provided error to each query response in the list. These queries should all belong to the same datasource.
-func buildErrorResponses(err error, queries []*simplejson.Json) splitResponse {
+func buildErrorResponses(uid string, err error, queries []*simplejson.Json, duration time.Duration) splitResponse {
 	er := backend.Responses{}
 	for _, query := range queries {
 		er[query.Get("refId").MustString("A")] = backend.DataResponse{
 			Error: err,
 		}
 	}
-	return splitResponse{er, http.Header{}}
+	return splitResponse{uid, er, http.Header{}, duration, DatasourceStatusError}
+}
+
+// buildTimeoutResponses reports each query of a datasource that did not respond before the timeout of the request.
+func buildTimeoutResponses(uid string, timeout time.Duration, queries []*simplejson.Json, duration time.Duration) splitResponse {
+	err := ErrQueryTimeout.Errorf("data source %s did not respond within %s", uid, timeout)
+	er := backend.Responses{}
+	for _, query := range queries {
+		er[query.Get("refId").MustString("A")] = backend.DataResponse{
+			Error:       err,
+			Status:      backend.StatusTimeout,
+			ErrorSource: backend.ErrorSourceDownstream,
+		}
+	}
+	return splitResponse{uid, er, http.Header{}, duration, DatasourceStatusTimeout}
 }
 
 // handleExpressions handles POST /api/ds/query when there is an expression.
@@ -278,11 +365,20 @@ func (s *ServiceImpl) parseMetricRequest(ctx context.Context, user identity.Requ
 		return nil, ErrNoQueriesFound
 	}
 
+	var timeout time.Duration
+	if reqDTO.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(reqDTO.Timeout); err != nil || timeout <= 0 {
+			return nil, ErrInvalidTimeout
+		}
+	}
+
 	timeRange := gtime.NewTimeRange(reqDTO.From, reqDTO.To)
 	req := &parsedRequest{
 		hasExpression: false,
 		parsedQueries: make(map[string][]parsedQuery),
 		dsTypes:       make(map[string]bool),
+		timeout:       timeout,
 	}
 
 	// Parse the queries and store them by datasource
@@ -424,6 +424,84 @@ func TestQueryDataMultipleSources(t *testing.T) {
 		require.NotContains(t, res.Responses, "A")
 	})
 
+	t.Run("datasources that don't respond before the timeout are reported as timed out", func(t *testing.T) {
+		tc := setup(t)
+		for _, queryType := range []string{"SLOW", "HANG"} {
+			reqDTO := metricRequestWithQueries(t, `{
+				"datasource": {"type": "mysql", "uid": "ds1"},
+				"refId": "A"
+			}`, `{
+				"datasource": {"type": "mysql", "uid": "ds2"},
+				"refId": "B",
+				"queryType": "`+queryType+`"
+			}`)
+			reqDTO.Timeout = "100ms"
+			ctx, meta := WithResponseMeta(context.Background())
+
+			start := time.Now()
+			res, err := tc.queryService.QueryData(ctx, tc.signedInUser, true, reqDTO)
+			require.NoError(t, err)
+			require.Less(t, time.Since(start), 5*time.Second, queryType)
+
+			require.ErrorIs(t, res.Responses["B"].Error, ErrQueryTimeout, queryType)
+			require.Equal(t, backend.StatusTimeout, res.Responses["B"].Status, queryType)
+			require.NotContains(t, res.Responses, "A", queryType)
+
+			require.Len(t, meta.Datasources, 2, queryType)
+			require.Equal(t, "ds1", meta.Datasources[0].UID)
+			require.Equal(t, DatasourceStatusOK, meta.Datasources[0].Status)
+			require.Equal(t, []string{"A"}, meta.Datasources[0].RefIDs)
+			require.Equal(t, "ds2", meta.Datasources[1].UID)
+			require.Equal(t, DatasourceStatusTimeout, meta.Datasources[1].Status)
+			require.GreaterOrEqual(t, meta.Datasources[1].DurationMs, int64(100))
+		}
+	})
+
+	t.Run("timeout applies to a single datasource", func(t *testing.T) {
+		tc := setup(t)
+		reqDTO := metricRequestWithQueries(t, `{
+			"datasource": {"type": "mysql", "uid": "ds1"},
+			"refId": "A",
+			"queryType": "SLOW"
+		}`)
+		reqDTO.Timeout = "50ms"
+
+		res, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, reqDTO)
+		require.NoError(t, err)
+		require.ErrorIs(t, res.Responses["A"].Error, ErrQueryTimeout)
+		require.Equal(t, backend.ErrorSourceDownstream, res.Responses["A"].ErrorSource)
+	})
+
+	t.Run("invalid timeout is rejected", func(t *testing.T) {
+		tc := setup(t)
+		for _, timeout := range []string{"soon", "-1s", "0s"} {
+			reqDTO := metricRequestWithQueries(t, `{
+				"datasource": {"type": "mysql", "uid": "ds1"},
+				"refId": "A"
+			}`)
+			reqDTO.Timeout = timeout
+
+			_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, reqDTO)
+			require.ErrorIs(t, err, ErrInvalidTimeout, timeout)
+		}
+	})
+
+	t.Run("response meta is recorded for a single datasource", func(t *testing.T) {
+		tc := setup(t)
+		reqDTO := metricRequestWithQueries(t, `{
+			"datasource": {"type": "mysql", "uid": "ds1"},
+			"refId": "B"
+		}`, `{
+			"datasource": {"type": "mysql", "uid": "ds1"},
+			"refId": "A"
+		}`)
+		ctx, meta := WithResponseMeta(context.Background())
+
+		_, err := tc.queryService.QueryData(ctx, tc.signedInUser, true, reqDTO)
+		require.NoError(t, err)
+		require.Equal(t, []DatasourceMeta{{UID: "ds1", Type: "mysql", RefIDs: []string{"A", "B"}, DurationMs: meta.Datasources[0].DurationMs, Status: DatasourceStatusOK}}, meta.Datasources)
+	})
+
 	t.Run("ignores a deprecated datasourceID", func(t *testing.T) {
 		tc := setup(t)
 		query1, err := simplejson.NewJson([]byte(`
@@ -460,7 +538,8 @@ func setup(t *testing.T) *testContext {
 	}
 
 	t.Helper()
-	pc := &fakePluginClient{}
+	pc := &fakePluginClient{hang: make(chan struct{})}
+	t.Cleanup(func() { close(pc.hang) })
 	dc := &fakeDataSourceCache{cache: dss}
 	rv := &fakeDataSourceRequestValidator{}
 
@@ -547,11 +626,20 @@ func (c *fakeDataSourceCache) GetDatasourceByUID(ctx context.Context, datasource
 
 type fakePluginClient struct {
 	plugins.Client
-	req *backend.QueryDataRequest
-	mu  sync.Mutex
+	req  *backend.QueryDataRequest
+	mu   sync.Mutex
+	hang chan struct{}
 }
 
 func (c *fakePluginClient) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	switch req.Queries[0].QueryType {
+	case "SLOW": // gives up when the request is cancelled
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case "HANG": // ignores the cancellation of the request
+		<-c.hang
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -0,0 +1,207 @@
+package saml
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/login/social"
+	"github.com/grafana/grafana/pkg/login/social/connectors"
+	"github.com/grafana/grafana/pkg/middleware/csrf"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/clients"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ssosettings"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamsync"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const requestIDCookieMaxAge = 300
+
+// Service plugs the SAML authn client into Grafana: it registers the client with authn,
+// keeps it in sync with the SSO settings of the "saml" provider and serves the
+// service provider endpoints used by the identity provider.
+// The client is only registered when the samlAuthnClient feature toggle is enabled.
+type Service struct {
+	cfg      *setting.Cfg
+	log      log.Logger
+	features featuremgmt.FeatureToggles
+
+	client       *clients.SAML
+	authnService authn.Service
+	teamSyncer   *teamsync.Syncer
+}
+
+func ProvideService(
+	cfg *setting.Cfg, routeRegister routing.RouteRegister, features featuremgmt.FeatureToggles,
+	authnService authn.Service, ssoSettings ssosettings.Service, orgRoleMapper *connectors.OrgRoleMapper,
+	teamService team.Service, teamPermissionsService accesscontrol.TeamPermissionsService, csrfService csrf.Service,
+	remoteCache remotecache.CacheStorage,
+) *Service {
+	s := &Service{
+		cfg:          cfg,
+		log:          log.New("saml"),
+		features:     features,
+		authnService: authnService,
+		teamSyncer:   teamsync.NewSyncer("saml", teamService, teamPermissionsService),
+	}
+	if !features.IsEnabledGlobally(featuremgmt.FlagSamlAuthnClient) {
+		return s
+	}
+	s.client = clients.ProvideSAML(cfg, orgRoleMapper, remoteCache)
+
+	ssoSettings.RegisterReloadable(social.SAMLProviderName, s.client)
+	settings, err := ssoSettings.GetForProvider(context.Background(), social.SAMLProviderName)
+	if err != nil {
+		s.log.Error("Failed to load SAML settings", "error", err)
+	} else if err := s.client.Reload(context.Background(), *settings); err != nil {
+		s.log.Error("Failed to configure SAML client", "error", err)
+	}
+
+	authnService.RegisterClient(s.client)
+	authnService.RegisterPostAuthHook(s.syncTeamsHook, 40)
+
+	// The identity provider posts its messages cross-site, without an Origin matching ours.
+	csrfService.AddSafeEndpoint(cfg.AppSubURL + "/saml/acs")
+	csrfService.AddSafeEndpoint(cfg.AppSubURL + "/saml/slo")
+
+	routeRegister.Get("/login/saml", s.login)
+	routeRegister.Get("/saml/metadata", s.metadata)
+	routeRegister.Post("/saml/acs", s.acs)
+	routeRegister.Get("/saml/slo", s.slo)
+	routeRegister.Post("/saml/slo", s.slo)
+
+	return s
+}
+
+func (s *Service) login(c *contextmodel.ReqContext) {
+	redirect, err := s.authnService.RedirectURL(c.Req.Context(), authn.ClientSAML, &authn.Request{HTTPRequest: c.Req})
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	s.writeRequestIDCookie(c, redirect.Extra[authn.KeySAMLRequestID], requestIDCookieMaxAge)
+	if s.features.IsEnabledGlobally(featuremgmt.FlagUseSessionStorageForRedirection) {
+		s.writeCookie(c, "redirectTo", c.Query("redirectTo"), requestIDCookieMaxAge)
+	}
+	c.Redirect(redirect.URL)
+}
+
+func (s *Service) metadata(c *contextmodel.ReqContext) {
+	metadata, err := s.client.Metadata()
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+	c.Resp.Header().Set("Content-Type", "application/samlmetadata+xml")
+	c.Resp.WriteHeader(http.StatusOK)
+	if _, err := c.Resp.Write(metadata); err != nil {
+		s.log.Warn("Failed to write SAML metadata", "error", err)
+	}
+}
+
+// acs is the assertion consumer service, it handles both SP-initiated and IdP-initiated logins.
+func (s *Service) acs(c *contextmodel.ReqContext) {
+	identity, err := s.authnService.Login(c.Req.Context(), authn.ClientSAML, &authn.Request{HTTPRequest: c.Req})
+	// NOTE: always delete the request id cookie, even if login failed
+	s.writeRequestIDCookie(c, "", -1)
+	if err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	authn.HandleLoginRedirect(c.Req, c.Resp, s.cfg, identity, s.validateRedirectTo, s.features)
+}
+
+// slo completes a single logout started by Grafana once the identity provider has ended its session.
+// Logouts started by the identity provider are out of scope: they are rejected and the Grafana session stays valid
+// until the user signs out or the session expires.
+func (s *Service) slo(c *contextmodel.ReqContext) {
+	if c.Req.URL.Query().Get("SAMLRequest") != "" || c.Req.PostFormValue("SAMLRequest") != "" {
+		c.Handle(s.cfg, http.StatusNotImplemented, "Identity provider initiated logout is not supported, sign out from Grafana instead", nil)
+		return
+	}
+
+	if err := s.client.ValidateLogoutResponse(c.Req); err != nil {
+		s.handleError(c, err)
+		return
+	}
+
+	redirectURL := s.cfg.AppSubURL + "/login"
+	if s.cfg.SignoutRedirectUrl != "" {
+		redirectURL = s.cfg.SignoutRedirectUrl
+	}
+	c.Redirect(redirectURL)
+}
+
+func (s *Service) handleError(c *contextmodel.ReqContext, err error) {
+	status, message := http.StatusInternalServerError, "SAML login failed"
+	var gfErr errutil.Error
+	if errors.As(err, &gfErr) {
+		public := gfErr.Public()
+		status, message = public.StatusCode, public.Message
+	}
+	c.Handle(s.cfg, status, message, err)
+}
+
+// writeRequestIDCookie stores the pending request id. The identity provider posts the response cross-site,
+// so the cookie has to be sent with SameSite=None whenever the browser allows it.
+func (s *Service) writeRequestIDCookie(c *contextmodel.ReqContext, value string, maxAge int) {
+	cookie := &http.Cookie{
+		Name:     clients.SAMLRequestIDCookieName,
+		Value:    value,
+		MaxAge:   maxAge,
+		Path:     s.cookiePath(),
+		HttpOnly: true,
+		Secure:   s.cfg.CookieSecure || c.Req.TLS != nil,
+	}
+	if cookie.Secure {
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(c.Resp, cookie)
+}
+
+func (s *Service) writeCookie(c *contextmodel.ReqContext, name, value string, maxAge int) {
+	http.SetCookie(c.Resp, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		MaxAge:   maxAge,
+		Path:     s.cookiePath(),
+		HttpOnly: true,
+		Secure:   s.cfg.CookieSecure,
+		SameSite: s.cfg.CookieSameSiteMode,
+	})
+}
+
+func (s *Service) cookiePath() string {
+	if s.cfg.AppSubURL != "" {
+		return s.cfg.AppSubURL
+	}
+	return "/"
+}
+
+var errForbiddenRedirectTo = errors.New("forbidden redirect to")
+
+func (s *Service) validateRedirectTo(redirectTo string) error {
+	to, err := url.Parse(redirectTo)
+	if err != nil || to.IsAbs() || to.Host != "" {
+		return errForbiddenRedirectTo
+	}
+	if !strings.HasPrefix(to.Path, "/") || strings.HasPrefix(to.Path, "//") {
+		return errForbiddenRedirectTo
+	}
+	if s.cfg.AppSubURL != "" && !strings.HasPrefix(to.Path, s.cfg.AppSubURL+"/") {
+		return errForbiddenRedirectTo
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package saml
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/login"
+)
+
+// parseTeamMapping parses `team_mapping` entries of the form "<group>:<team name>"
+// into the groups that grant membership of each team.
+func parseTeamMapping(mappings []string) map[string][]string {
+	teams := make(map[string][]string, len(mappings))
+	for _, m := range mappings {
+		i := strings.LastIndex(m, ":")
+		if i <= 0 || i == len(m)-1 {
+			continue
+		}
+		teams[m[i+1:]] = append(teams[m[i+1:]], m[:i])
+	}
+	return teams
+}
+
+// syncTeamsHook keeps the membership of the teams listed in `team_mapping` in sync with the
+// groups of a user signing in with SAML. Teams that are not mapped are never modified.
+func (s *Service) syncTeamsHook(ctx context.Context, id *authn.Identity, _ *authn.Request) error {
+	if id.AuthenticatedBy != login.SAMLAuthModule || !id.ClientParams.SyncTeams {
+		return nil
+	}
+
+	info := s.client.Info()
+	if info == nil || len(info.TeamMapping) == 0 {
+		return nil
+	}
+
+	userID, err := id.GetInternalID()
+	if err != nil {
+		s.log.FromContext(ctx).Debug("Skipping team sync for identity without a user", "id", id.ID)
+		return nil
+	}
+
+	orgIDs := make([]int64, 0, len(id.OrgRoles))
+	for orgID := range id.OrgRoles {
+		orgIDs = append(orgIDs, orgID)
+	}
+	if len(orgIDs) == 0 && id.OrgID > 0 {
+		orgIDs = append(orgIDs, id.OrgID)
+	}
+
+	for teamName, groups := range parseTeamMapping(info.TeamMapping) {
+		member := slices.ContainsFunc(id.Groups, func(g string) bool { return slices.Contains(groups, g) })
+		for _, orgID := range orgIDs {
+			if err := s.teamSyncer.SyncMembership(ctx, orgID, teamName, userID, member); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
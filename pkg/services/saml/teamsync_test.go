@@ -0,0 +1,23 @@
+package saml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTeamMapping(t *testing.T) {
+	mapping := parseTeamMapping([]string{
+		"platform-admins:Platform",
+		"sre:Platform",
+		"urn:group:backend:Backend",
+		"invalid",
+		":Missing group",
+		"missing-team:",
+	})
+
+	assert.Equal(t, map[string][]string{
+		"Platform": {"platform-admins", "sre"},
+		"Backend":  {"urn:group:backend"},
+	}, mapping)
+}
@@ -0,0 +1,251 @@
+package scim
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (s *Service) bulkHandler(c *contextmodel.ReqContext) response.Response {
+	var req BulkRequest
+	if err := bind(c.Req, &req); err != nil {
+		return s.errorResponse(err)
+	}
+	if s.cfg.MaxBulkOperations > 0 && len(req.Operations) > s.cfg.MaxBulkOperations {
+		return s.errorResponse(newError(http.StatusRequestEntityTooLarge, ScimTypeTooMany,
+			"the bulk request has %d operations, the maximum is %d", len(req.Operations), s.cfg.MaxBulkOperations))
+	}
+	return scimResponse(http.StatusOK, s.bulk(c.Req.Context(), c.SignedInUser, &req))
+}
+
+// bulk processes the operations of a bulk request in order (RFC 7644, section 3.7).
+// Operations can reference resources created earlier in the request with
+// "bulkId:<id>", and processing stops after failOnErrors failures.
+func (s *Service) bulk(ctx context.Context, requester identity.Requester, req *BulkRequest) *BulkResponse {
+	resp := &BulkResponse{Schemas: []string{SchemaBulkResponse}, Operations: []BulkOperationResult{}}
+	created := map[string]string{}
+	failures := 0
+
+	for _, op := range req.Operations {
+		result := BulkOperationResult{Method: strings.ToUpper(op.Method), BulkID: op.BulkID}
+
+		location, resource, status, err := s.bulkOperation(ctx, requester, op, created)
+		if err != nil {
+			scimErr := asError(err)
+			if scimErr.StatusCode() >= http.StatusInternalServerError {
+				s.log.Error("SCIM bulk operation failed", "method", op.Method, "path", op.Path, "error", err)
+			}
+			result.Status = scimErr.Status
+			result.Response = scimErr
+			failures++
+		} else {
+			result.Status = strconv.Itoa(status)
+			result.Location = location
+			if resource != nil && result.Method == http.MethodPost && op.BulkID != "" {
+				created[op.BulkID] = resourceIDOf(resource)
+			}
+		}
+		resp.Operations = append(resp.Operations, result)
+
+		if req.FailOnErrors > 0 && failures >= req.FailOnErrors {
+			break
+		}
+	}
+	return resp
+}
+
+func (s *Service) bulkOperation(ctx context.Context, requester identity.Requester, op BulkOperation, created map[string]string) (string, any, int, error) {
+	path, err := resolveBulkIDs(op.Path, created)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	data := op.Data
+	if bytes.Contains(data, []byte(bulkIDPrefix)) {
+		resolved, err := resolveBulkIDs(string(data), created)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		data = []byte(resolved)
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) == 0 || len(segments) > 2 {
+		return "", nil, 0, newError(http.StatusBadRequest, ScimTypeInvalidPath, "invalid path %q", op.Path)
+	}
+	id := ""
+	if len(segments) == 2 {
+		id = segments[1]
+	}
+	method := strings.ToUpper(op.Method)
+	if (method == http.MethodPost) != (id == "") {
+		return "", nil, 0, newError(http.StatusBadRequest, ScimTypeInvalidPath, "invalid path %q for method %s", op.Path, op.Method)
+	}
+
+	switch segments[0] {
+	case "Users":
+		return s.bulkUserOperation(ctx, requester, method, id, data)
+	case "Groups":
+		return s.bulkGroupOperation(ctx, requester, method, id, data)
+	default:
+		return "", nil, 0, newError(http.StatusBadRequest, ScimTypeInvalidPath, "unknown resource type in path %q", op.Path)
+	}
+}
+
+func (s *Service) bulkUserOperation(ctx context.Context, requester identity.Requester, method, id string, data []byte) (string, any, int, error) {
+	var evaluator ac.Evaluator
+	switch method {
+	case http.MethodPost:
+		evaluator = evalUsersCreate
+	case http.MethodPut, http.MethodPatch:
+		evaluator = evalUsersWrite
+	case http.MethodDelete:
+		evaluator = evalUsersDelete
+	default:
+		return "", nil, 0, newError(http.StatusMethodNotAllowed, "", "unsupported method %s", method)
+	}
+	if err := s.authorize(ctx, requester, evaluator); err != nil {
+		return "", nil, 0, err
+	}
+
+	switch method {
+	case http.MethodPost:
+		var u User
+		if err := decode(data, &u); err != nil {
+			return "", nil, 0, err
+		}
+		created, err := s.createUser(ctx, requester, &u)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return created.Meta.Location, created, http.StatusCreated, nil
+	case http.MethodPut:
+		var u User
+		if err := decode(data, &u); err != nil {
+			return "", nil, 0, err
+		}
+		updated, err := s.replaceUser(ctx, requester, id, &u)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return updated.Meta.Location, updated, http.StatusOK, nil
+	case http.MethodPatch:
+		var patch PatchRequest
+		if err := decode(data, &patch); err != nil {
+			return "", nil, 0, err
+		}
+		updated, err := s.patchUser(ctx, requester, id, patch.Operations)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return updated.Meta.Location, updated, http.StatusOK, nil
+	default:
+		if err := s.deleteUser(ctx, requester, id); err != nil {
+			return "", nil, 0, err
+		}
+		return s.location("Users", id), nil, http.StatusNoContent, nil
+	}
+}
+
+func (s *Service) bulkGroupOperation(ctx context.Context, requester identity.Requester, method, id string, data []byte) (string, any, int, error) {
+	var evaluator ac.Evaluator
+	switch method {
+	case http.MethodPost:
+		evaluator = evalGroupsCreate
+	case http.MethodPut, http.MethodPatch:
+		evaluator = evalGroupsWrite
+	case http.MethodDelete:
+		evaluator = evalGroupsDelete
+	default:
+		return "", nil, 0, newError(http.StatusMethodNotAllowed, "", "unsupported method %s", method)
+	}
+	if err := s.authorize(ctx, requester, evaluator); err != nil {
+		return "", nil, 0, err
+	}
+
+	switch method {
+	case http.MethodPost:
+		var g Group
+		if err := decode(data, &g); err != nil {
+			return "", nil, 0, err
+		}
+		created, err := s.createGroup(ctx, requester, &g)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return created.Meta.Location, created, http.StatusCreated, nil
+	case http.MethodPut:
+		var g Group
+		if err := decode(data, &g); err != nil {
+			return "", nil, 0, err
+		}
+		updated, err := s.replaceGroup(ctx, requester, id, &g)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return updated.Meta.Location, updated, http.StatusOK, nil
+	case http.MethodPatch:
+		var patch PatchRequest
+		if err := decode(data, &patch); err != nil {
+			return "", nil, 0, err
+		}
+		updated, err := s.patchGroup(ctx, requester, id, patch.Operations)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return updated.Meta.Location, updated, http.StatusOK, nil
+	default:
+		if err := s.deleteGroup(ctx, requester, id); err != nil {
+			return "", nil, 0, err
+		}
+		return s.location("Groups", id), nil, http.StatusNoContent, nil
+	}
+}
+
+func (s *Service) authorize(ctx context.Context, requester identity.Requester, evaluator ac.Evaluator) error {
+	ok, err := s.accessControl.Evaluate(ctx, requester, evaluator)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return newError(http.StatusForbidden, "", "permissions needed: %s", evaluator.String())
+	}
+	return nil
+}
+
+// resolveBulkIDs replaces "bulkId:<id>" references with the ID of the resource
+// created by the operation with that bulk ID.
+func resolveBulkIDs(s string, created map[string]string) (string, error) {
+	for {
+		i := strings.Index(s, bulkIDPrefix)
+		if i < 0 {
+			return s, nil
+		}
+		end := i + len(bulkIDPrefix)
+		for end < len(s) && !strings.ContainsRune("\"/ ,}]", rune(s[end])) {
+			end++
+		}
+		ref := s[i+len(bulkIDPrefix) : end]
+		id, ok := created[ref]
+		if !ok {
+			return "", newError(http.StatusConflict, ScimTypeInvalidValue, "unresolved bulkId %q", ref)
+		}
+		s = s[:i] + id + s[end:]
+	}
+}
+
+func resourceIDOf(resource any) string {
+	switch r := resource.(type) {
+	case *User:
+		return r.ID
+	case *Group:
+		return r.ID
+	}
+	return ""
+}
@@ -0,0 +1,485 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter is a parsed SCIM filter expression (RFC 7644, section 3.4.2.2).
+//
+// Filters are evaluated against the JSON representation of a resource, so
+// attribute names are matched case-insensitively and string comparisons
+// ignore case, which is what the core User and Group schemas require for
+// every attribute Grafana exposes.
+type Filter interface {
+	Matches(resource map[string]any) bool
+}
+
+type comparison struct {
+	path  []string
+	op    string
+	value any
+}
+
+type logical struct {
+	op          string
+	left, right Filter
+}
+
+type negation struct {
+	inner Filter
+}
+
+// valuePath filters a multi-valued attribute, e.g. emails[type eq "work"].
+type valuePath struct {
+	path   []string
+	filter Filter
+}
+
+var comparisonOperators = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true, "pr": true,
+}
+
+// ParseFilter parses a SCIM filter expression.
+func ParseFilter(expr string) (Filter, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, invalidFilter("unexpected token %q", p.peek().text)
+	}
+	return f, nil
+}
+
+func invalidFilter(format string, args ...any) *Error {
+	return newError(http.StatusBadRequest, ScimTypeInvalidFilter, format, args...)
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOpenParen
+	tokenCloseParen
+	tokenOpenBracket
+	tokenCloseBracket
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpenParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenCloseParen, text: ")"})
+			i++
+		case c == '[':
+			tokens = append(tokens, token{kind: tokenOpenBracket, text: "["})
+			i++
+		case c == ']':
+			tokens = append(tokens, token{kind: tokenCloseBracket, text: "]"})
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(expr); end++ {
+				if expr[end] == '\\' {
+					end++
+					continue
+				}
+				if expr[end] == '"' {
+					break
+				}
+			}
+			if end >= len(expr) {
+				return nil, invalidFilter("unterminated string at position %d", i)
+			}
+			var s string
+			if err := json.Unmarshal([]byte(expr[i:end+1]), &s); err != nil {
+				return nil, invalidFilter("invalid string at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s})
+			i = end + 1
+		default:
+			end := i
+			for end < len(expr) && !unicode.IsSpace(rune(expr[end])) && !strings.ContainsRune("()[]\"", rune(expr[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: expr[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() (token, error) {
+	if p.done() {
+		return token{}, invalidFilter("unexpected end of filter")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *filterParser) peekKeyword(keyword string) bool {
+	return !p.done() && p.peek().kind == tokenWord && strings.EqualFold(p.peek().text, keyword)
+}
+
+func (p *filterParser) expect(kind tokenKind, text string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != kind {
+		return invalidFilter("expected %q, got %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (Filter, error) {
+	if p.peekKeyword("not") {
+		p.pos++
+		if err := p.expect(tokenOpenParen, "("); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenCloseParen, ")"); err != nil {
+			return nil, err
+		}
+		return &negation{inner: inner}, nil
+	}
+
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.kind {
+	case tokenOpenParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenCloseParen, ")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokenWord:
+		path := parseAttrPath(t.text)
+		if !p.done() && p.peek().kind == tokenOpenBracket {
+			p.pos++
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenCloseBracket, "]"); err != nil {
+				return nil, err
+			}
+			return &valuePath{path: path, filter: inner}, nil
+		}
+		return p.parseComparison(path)
+	default:
+		return nil, invalidFilter("unexpected token %q", t.text)
+	}
+}
+
+func (p *filterParser) parseComparison(path []string) (Filter, error) {
+	opToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := strings.ToLower(opToken.text)
+	if opToken.kind != tokenWord || !comparisonOperators[op] {
+		return nil, invalidFilter("unknown operator %q", opToken.text)
+	}
+	if op == "pr" {
+		return &comparison{path: path, op: op}, nil
+	}
+
+	valueToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseCompValue(valueToken)
+	if err != nil {
+		return nil, err
+	}
+	return &comparison{path: path, op: op, value: value}, nil
+}
+
+func parseCompValue(t token) (any, error) {
+	if t.kind == tokenString {
+		return t.text, nil
+	}
+	if t.kind != tokenWord {
+		return nil, invalidFilter("unexpected token %q", t.text)
+	}
+	switch strings.ToLower(t.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return nil, invalidFilter("invalid comparison value %q", t.text)
+	}
+	return n, nil
+}
+
+// parseAttrPath splits an attribute path into its components, dropping any schema URN prefix.
+func parseAttrPath(attr string) []string {
+	if strings.HasPrefix(strings.ToLower(attr), "urn:") {
+		attr = attr[strings.LastIndex(attr, ":")+1:]
+	}
+	return strings.Split(attr, ".")
+}
+
+func (c *comparison) Matches(resource map[string]any) bool {
+	values := resolve(resource, c.path)
+	if c.op == "pr" {
+		for _, v := range values {
+			if !isEmpty(v) {
+				return true
+			}
+		}
+		return false
+	}
+	if c.op == "ne" {
+		for _, v := range values {
+			if compare(v, "eq", c.value) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range values {
+		if compare(v, c.op, c.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *logical) Matches(resource map[string]any) bool {
+	if l.op == "and" {
+		return l.left.Matches(resource) && l.right.Matches(resource)
+	}
+	return l.left.Matches(resource) || l.right.Matches(resource)
+}
+
+func (n *negation) Matches(resource map[string]any) bool {
+	return !n.inner.Matches(resource)
+}
+
+func (v *valuePath) Matches(resource map[string]any) bool {
+	parents := []any{resource}
+	if len(v.path) > 1 {
+		parents = resolve(resource, v.path[:len(v.path)-1])
+	}
+	for _, p := range parents {
+		node, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		values, _ := lookup(node, v.path[len(v.path)-1])
+		items, ok := values.([]any)
+		if !ok {
+			items = []any{values}
+		}
+		for _, item := range items {
+			if sub, ok := item.(map[string]any); ok && v.filter.Matches(sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolve returns every value found at path, flattening multi-valued attributes.
+// When a multi-valued attribute of complex values is addressed without a
+// sub-attribute, its "value" sub-attribute is used, as RFC 7644 requires.
+func resolve(node any, path []string) []any {
+	if len(path) == 0 {
+		switch v := node.(type) {
+		case []any:
+			var out []any
+			for _, item := range v {
+				out = append(out, resolve(item, path)...)
+			}
+			return out
+		case map[string]any:
+			if value, ok := lookup(v, "value"); ok {
+				return []any{value}
+			}
+			return []any{v}
+		default:
+			return []any{v}
+		}
+	}
+
+	switch v := node.(type) {
+	case []any:
+		var out []any
+		for _, item := range v {
+			out = append(out, resolve(item, path)...)
+		}
+		return out
+	case map[string]any:
+		child, ok := lookup(v, path[0])
+		if !ok {
+			return nil
+		}
+		return resolve(child, path[1:])
+	default:
+		return nil
+	}
+}
+
+func lookup(m map[string]any, key string) (any, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func isEmpty(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	}
+	return false
+}
+
+func compare(actual any, op string, expected any) bool {
+	switch a := actual.(type) {
+	case string:
+		e, ok := expected.(string)
+		if !ok {
+			return false
+		}
+		a, e = strings.ToLower(a), strings.ToLower(e)
+		switch op {
+		case "eq":
+			return a == e
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case float64:
+		e, ok := expected.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case bool:
+		e, ok := expected.(bool)
+		return ok && op == "eq" && a == e
+	case nil:
+		return op == "eq" && expected == nil
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	active := true
+	u := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          "u1",
+		UserName:    "Alice@Example.com",
+		DisplayName: "Alice Smith",
+		Name:        &Name{GivenName: "Alice", FamilyName: "Smith"},
+		Emails: []MultiValued{
+			{Value: "alice@example.com", Type: "work", Primary: true},
+			{Value: "alice@home.org", Type: "home"},
+		},
+		Active: &active,
+		Roles:  []MultiValued{{Value: "Editor", Primary: true}},
+	}
+	resource, err := toMap(u)
+	require.NoError(t, err)
+
+	tests := []struct {
+		filter  string
+		matches bool
+	}{
+		{filter: `userName eq "alice@example.com"`, matches: true},
+		{filter: `USERNAME Eq "ALICE@EXAMPLE.COM"`, matches: true},
+		{filter: `userName eq "bob@example.com"`, matches: false},
+		{filter: `userName ne "bob@example.com"`, matches: true},
+		{filter: `urn:ietf:params:scim:schemas:core:2.0:User:userName sw "alice"`, matches: true},
+		{filter: `name.familyName co "mit"`, matches: true},
+		{filter: `displayName ew "smith"`, matches: true},
+		{filter: `emails co "home.org"`, matches: true},
+		{filter: `emails[type eq "work" and value co "@example.com"]`, matches: true},
+		{filter: `emails[type eq "work" and value co "@home.org"]`, matches: false},
+		{filter: `emails.type eq "home"`, matches: true},
+		{filter: `active eq true`, matches: true},
+		{filter: `active eq false`, matches: false},
+		{filter: `externalId pr`, matches: false},
+		{filter: `title pr or roles pr`, matches: true},
+		{filter: `userName eq "bob" or (roles eq "editor" and not (active eq false))`, matches: true},
+		{filter: `not (userName sw "alice")`, matches: false},
+		{filter: `displayName gt "Alice" and displayName lt "Bob"`, matches: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := ParseFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.matches, f.Matches(resource))
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	tests := []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName foo "alice"`,
+		`userName eq "alice`,
+		`(userName eq "alice"`,
+		`emails[type eq "work"`,
+		`userName eq alice`,
+		`userName eq "alice" and`,
+		`userName eq "alice" "bob"`,
+	}
+
+	for _, filter := range tests {
+		t.Run(filter, func(t *testing.T) {
+			_, err := ParseFilter(filter)
+			require.Error(t, err)
+			assert.Equal(t, ScimTypeInvalidFilter, asError(err).ScimType)
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	groups := []*Group{
+		{Schemas: []string{SchemaGroup}, ID: "1", DisplayName: "backend"},
+		{Schemas: []string{SchemaGroup}, ID: "2", DisplayName: "frontend"},
+		{Schemas: []string{SchemaGroup}, ID: "3", DisplayName: "backoffice"},
+	}
+
+	t.Run("filters before paginating", func(t *testing.T) {
+		f, err := ParseFilter(`displayName sw "back"`)
+		require.NoError(t, err)
+
+		list, err := paginate(groups, listParams{filter: f, startIndex: 2, count: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 2, list.TotalResults)
+		assert.Equal(t, 1, list.ItemsPerPage)
+		assert.Equal(t, 2, list.StartIndex)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, "3", list.Resources[0].(*Group).ID)
+	})
+
+	t.Run("start index beyond the results returns an empty page", func(t *testing.T) {
+		list, err := paginate(groups, listParams{startIndex: 10, count: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 3, list.TotalResults)
+		assert.Empty(t, list.Resources)
+	})
+
+	t.Run("count zero only returns the total", func(t *testing.T) {
+		list, err := paginate(groups, listParams{startIndex: 1, count: 0})
+		require.NoError(t, err)
+		assert.Equal(t, 3, list.TotalResults)
+		assert.Equal(t, 0, list.ItemsPerPage)
+	})
+}
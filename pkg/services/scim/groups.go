@@ -0,0 +1,337 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+func (s *Service) listGroupsHandler(c *contextmodel.ReqContext) response.Response {
+	params, err := s.parseListParams(c)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	// Members are expensive to resolve and identity providers usually
+	// exclude them when looking up groups by displayName.
+	withMembers := c.Query("excludedAttributes") != "members"
+	groups, err := s.listGroups(c.Req.Context(), c.SignedInUser, withMembers)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	list, err := paginate(groups, params)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusOK, list)
+}
+
+func (s *Service) getGroupHandler(c *contextmodel.ReqContext) response.Response {
+	g, err := s.getGroup(c.Req.Context(), c.SignedInUser, resourceID(c))
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusOK, g)
+}
+
+func (s *Service) createGroupHandler(c *contextmodel.ReqContext) response.Response {
+	var g Group
+	if err := bind(c.Req, &g); err != nil {
+		return s.errorResponse(err)
+	}
+	created, err := s.createGroup(c.Req.Context(), c.SignedInUser, &g)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusCreated, created).SetHeader("Location", created.Meta.Location)
+}
+
+func (s *Service) replaceGroupHandler(c *contextmodel.ReqContext) response.Response {
+	var g Group
+	if err := bind(c.Req, &g); err != nil {
+		return s.errorResponse(err)
+	}
+	updated, err := s.replaceGroup(c.Req.Context(), c.SignedInUser, resourceID(c), &g)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusOK, updated)
+}
+
+func (s *Service) patchGroupHandler(c *contextmodel.ReqContext) response.Response {
+	var patch PatchRequest
+	if err := bind(c.Req, &patch); err != nil {
+		return s.errorResponse(err)
+	}
+	updated, err := s.patchGroup(c.Req.Context(), c.SignedInUser, resourceID(c), patch.Operations)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusOK, updated)
+}
+
+func (s *Service) deleteGroupHandler(c *contextmodel.ReqContext) response.Response {
+	if err := s.deleteGroup(c.Req.Context(), c.SignedInUser, resourceID(c)); err != nil {
+		return s.errorResponse(err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+func (s *Service) listGroups(ctx context.Context, requester identity.Requester, withMembers bool) ([]*Group, error) {
+	result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        requester.GetOrgID(),
+		SignedInUser: requester,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search teams: %w", err)
+	}
+
+	users := &orgUserIndex{}
+	if withMembers {
+		if users, err = s.indexOrgUsers(ctx, requester); err != nil {
+			return nil, err
+		}
+	}
+
+	groups := make([]*Group, 0, len(result.Teams))
+	for _, t := range result.Teams {
+		var members []*team.TeamMemberDTO
+		if withMembers {
+			if members, err = s.getTeamMembers(ctx, requester, t.ID); err != nil {
+				return nil, err
+			}
+		}
+		groups = append(groups, s.toSCIMGroup(t, members, users))
+	}
+	return groups, nil
+}
+
+func (s *Service) getGroup(ctx context.Context, requester identity.Requester, uid string) (*Group, error) {
+	t, err := s.getTeam(ctx, requester, uid, evalGroupRead)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupOf(ctx, requester, t)
+}
+
+func (s *Service) groupOf(ctx context.Context, requester identity.Requester, t *team.TeamDTO) (*Group, error) {
+	members, err := s.getTeamMembers(ctx, requester, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.indexOrgUsers(ctx, requester)
+	if err != nil {
+		return nil, err
+	}
+	return s.toSCIMGroup(t, members, users), nil
+}
+
+// getTeam returns the team with the given UID if the requester passes the
+// evaluator scoped to the team.
+func (s *Service) getTeam(ctx context.Context, requester identity.Requester, uid string, evaluator func(scope string) ac.Evaluator) (*team.TeamDTO, error) {
+	t, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{
+		OrgID:        requester.GetOrgID(),
+		UID:          uid,
+		SignedInUser: requester,
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	if err := s.authorize(ctx, requester, evaluator(ac.Scope("teams", "id", strconv.FormatInt(t.ID, 10)))); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Service) getTeamMembers(ctx context.Context, requester identity.Requester, teamID int64) ([]*team.TeamMemberDTO, error) {
+	members, err := s.teamService.GetTeamMembers(ctx, &team.GetTeamMembersQuery{
+		OrgID:        requester.GetOrgID(),
+		TeamID:       teamID,
+		SignedInUser: requester,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+	return members, nil
+}
+
+func (s *Service) createGroup(ctx context.Context, requester identity.Requester, g *Group) (*Group, error) {
+	if g.DisplayName == "" {
+		return nil, newError(http.StatusBadRequest, ScimTypeInvalidValue, "displayName is required")
+	}
+
+	t, err := s.teamService.CreateTeam(ctx, g.DisplayName, "", requester.GetOrgID())
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNameTaken) {
+			return nil, newError(http.StatusConflict, ScimTypeUniqueness, "group %q already exists", g.DisplayName)
+		}
+		return nil, fmt.Errorf("failed to create team: %w", err)
+	}
+
+	if err := s.syncMembers(ctx, requester, t.ID, nil, g.Members); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Provisioned team", "name", t.Name, "orgID", t.OrgID, "members", len(g.Members))
+	return s.groupOf(ctx, requester, &team.TeamDTO{ID: t.ID, UID: t.UID, OrgID: t.OrgID, Name: t.Name, Email: t.Email})
+}
+
+func (s *Service) replaceGroup(ctx context.Context, requester identity.Requester, uid string, g *Group) (*Group, error) {
+	t, err := s.getTeam(ctx, requester, uid, evalGroupWrite)
+	if err != nil {
+		return nil, err
+	}
+	if g.DisplayName == "" {
+		return nil, newError(http.StatusBadRequest, ScimTypeInvalidValue, "displayName is required")
+	}
+
+	if g.DisplayName != t.Name {
+		err := s.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{ID: t.ID, OrgID: t.OrgID, Name: g.DisplayName, Email: t.Email})
+		if err != nil {
+			if errors.Is(err, team.ErrTeamNameTaken) {
+				return nil, newError(http.StatusConflict, ScimTypeUniqueness, "group %q already exists", g.DisplayName)
+			}
+			return nil, fmt.Errorf("failed to update team: %w", err)
+		}
+		t.Name = g.DisplayName
+	}
+
+	current, err := s.getTeamMembers(ctx, requester, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.syncMembers(ctx, requester, t.ID, current, g.Members); err != nil {
+		return nil, err
+	}
+
+	return s.groupOf(ctx, requester, t)
+}
+
+func (s *Service) patchGroup(ctx context.Context, requester identity.Requester, uid string, ops []PatchOperation) (*Group, error) {
+	t, err := s.getTeam(ctx, requester, uid, evalGroupWrite)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.groupOf(ctx, requester, t)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := toMap(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPatch(resource, ops); err != nil {
+		return nil, err
+	}
+
+	var patched Group
+	if err := fromMap(resource, &patched); err != nil {
+		return nil, err
+	}
+	return s.replaceGroup(ctx, requester, uid, &patched)
+}
+
+func (s *Service) deleteGroup(ctx context.Context, requester identity.Requester, uid string) error {
+	t, err := s.getTeam(ctx, requester, uid, evalGroupDelete)
+	if err != nil {
+		return err
+	}
+	if err := s.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: t.OrgID, ID: t.ID}); err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return ErrGroupNotFound
+		}
+		return fmt.Errorf("failed to delete team: %w", err)
+	}
+	s.log.Info("Deprovisioned team", "name", t.Name, "orgID", t.OrgID)
+	return nil
+}
+
+// syncMembers makes the team members match the wanted SCIM members, which
+// reference users by their UID.
+func (s *Service) syncMembers(ctx context.Context, requester identity.Requester, teamID int64, current []*team.TeamMemberDTO, wanted []Reference) error {
+	orgID := requester.GetOrgID()
+
+	currentIDs := make(map[int64]bool, len(current))
+	for _, m := range current {
+		currentIDs[m.UserID] = true
+	}
+
+	users, err := s.indexOrgUsers(ctx, requester)
+	if err != nil {
+		return err
+	}
+	wantedIDs := make(map[int64]bool, len(wanted))
+	for _, ref := range wanted {
+		if ref.Type != "" && ref.Type != ResourceTypeUser {
+			return newError(http.StatusBadRequest, ScimTypeInvalidValue, "nested groups are not supported")
+		}
+		ou, ok := users.byUID[ref.Value]
+		if !ok {
+			return newError(http.StatusBadRequest, ScimTypeInvalidValue, "member %q is not a user of the organization", ref.Value)
+		}
+		wantedIDs[ou.UserID] = true
+	}
+
+	for id := range wantedIDs {
+		if !currentIDs[id] {
+			if err := s.setTeamMembership(ctx, orgID, teamID, id, true); err != nil {
+				return err
+			}
+		}
+	}
+	for id := range currentIDs {
+		if !wantedIDs[id] {
+			if err := s.setTeamMembership(ctx, orgID, teamID, id, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) setTeamMembership(ctx context.Context, orgID, teamID, userID int64, member bool) error {
+	permission := ""
+	if member {
+		permission = team.PermissionTypeMember.String()
+	}
+	_, err := s.teamPermissionsService.SetUserPermission(ctx, orgID, ac.User{ID: userID}, strconv.FormatInt(teamID, 10), permission)
+	if err != nil {
+		return fmt.Errorf("failed to update membership of user %d in team %d: %w", userID, teamID, err)
+	}
+	return nil
+}
+
+func (s *Service) toSCIMGroup(t *team.TeamDTO, members []*team.TeamMemberDTO, users *orgUserIndex) *Group {
+	g := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          t.UID,
+		DisplayName: t.Name,
+		Meta: &Meta{
+			ResourceType: ResourceTypeGroup,
+			Location:     s.location("Groups", t.UID),
+		},
+	}
+	for _, m := range members {
+		ou, ok := users.byID[m.UserID]
+		if !ok {
+			continue
+		}
+		g.Members = append(g.Members, Reference{
+			Value:   ou.UID,
+			Ref:     s.location("Users", ou.UID),
+			Display: m.Login,
+			Type:    ResourceTypeUser,
+		})
+	}
+	return g
+}
@@ -0,0 +1,215 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type membershipChange struct {
+	teamID     string
+	userID     int64
+	permission string
+}
+
+// fakeTeamPermissionsService records the changes of team memberships.
+type fakeTeamPermissionsService struct {
+	ac.TeamPermissionsService
+	changes []membershipChange
+}
+
+func (f *fakeTeamPermissionsService) SetUserPermission(_ context.Context, _ int64, u ac.User, resourceID, permission string) (*ac.ResourcePermission, error) {
+	f.changes = append(f.changes, membershipChange{teamID: resourceID, userID: u.ID, permission: permission})
+	return &ac.ResourcePermission{}, nil
+}
+
+func TestCreateGroup(t *testing.T) {
+	newEnv := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		env.orgs.orgs[2] = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleViewer}}
+		env.orgs.orgs[3] = []*org.UserOrgDTO{{OrgID: 2, Role: org.RoleViewer}}
+		env.teams.ExpectedTeam = team.Team{ID: 5, UID: "t-sre", OrgID: 1, Name: "SRE"}
+		env.teams.ExpectedTeamDTO = &team.TeamDTO{ID: 5, UID: "t-sre", OrgID: 1, Name: "SRE"}
+		return env
+	}
+
+	t.Run("creates the team with its members", func(t *testing.T) {
+		env := newEnv(t)
+		env.teams.ExpectedMembers = []*team.TeamMemberDTO{{TeamID: 5, UserID: 2, Login: "alice"}}
+
+		resp := env.svc.createGroupHandler(scimRequest(t, http.MethodPost, "", Group{
+			Schemas:     []string{SchemaGroup},
+			DisplayName: "SRE",
+			Members:     []Reference{{Value: "u-alice"}},
+		}, requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusCreated, resp.Status())
+		created := decodeResponse[Group](t, resp)
+		assert.Equal(t, "t-sre", created.ID)
+		require.Len(t, created.Members, 1)
+		assert.Equal(t, "u-alice", created.Members[0].Value)
+		assert.Equal(t, []membershipChange{{teamID: "5", userID: 2, permission: team.PermissionTypeMember.String()}}, env.permissions.changes)
+	})
+
+	t.Run("rejects members of other orgs", func(t *testing.T) {
+		env := newEnv(t)
+
+		resp := env.svc.createGroupHandler(scimRequest(t, http.MethodPost, "", Group{
+			Schemas:     []string{SchemaGroup},
+			DisplayName: "SRE",
+			Members:     []Reference{{Value: "u-bob"}},
+		}, requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		assert.Empty(t, env.permissions.changes)
+	})
+
+	t.Run("returns a conflict when the team exists", func(t *testing.T) {
+		env := newEnv(t)
+		env.teams.ExpectedError = team.ErrTeamNameTaken
+
+		resp := env.svc.createGroupHandler(scimRequest(t, http.MethodPost, "", Group{Schemas: []string{SchemaGroup}, DisplayName: "SRE"}, requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusConflict, resp.Status())
+	})
+
+	t.Run("requires a display name", func(t *testing.T) {
+		env := newEnv(t)
+
+		resp := env.svc.createGroupHandler(scimRequest(t, http.MethodPost, "", Group{Schemas: []string{SchemaGroup}}, requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
+
+func TestPatchGroup(t *testing.T) {
+	newEnv := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		env.orgs.orgs[1] = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleAdmin}}
+		env.orgs.orgs[2] = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleViewer}}
+		env.teams.ExpectedTeamDTO = &team.TeamDTO{ID: 5, UID: "t-sre", OrgID: 1, Name: "SRE"}
+		env.teams.ExpectedMembers = []*team.TeamMemberDTO{{TeamID: 5, UserID: 1, Login: "admin"}}
+		return env
+	}
+
+	t.Run("adds and removes members", func(t *testing.T) {
+		env := newEnv(t)
+
+		resp := env.svc.patchGroupHandler(scimRequest(t, http.MethodPatch, "t-sre", PatchRequest{
+			Schemas: []string{SchemaPatchOp},
+			Operations: []PatchOperation{
+				{Op: "replace", Path: "members", Value: json.RawMessage(`[{"value": "u-alice"}]`)},
+			},
+		}, requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		assert.ElementsMatch(t, []membershipChange{
+			{teamID: "5", userID: 2, permission: team.PermissionTypeMember.String()},
+			{teamID: "5", userID: 1, permission: ""},
+		}, env.permissions.changes)
+	})
+
+	t.Run("returns not found for unknown groups", func(t *testing.T) {
+		env := newEnv(t)
+		env.teams.ExpectedError = team.ErrTeamNotFound
+
+		resp := env.svc.patchGroupHandler(scimRequest(t, http.MethodPatch, "unknown", PatchRequest{
+			Schemas:    []string{SchemaPatchOp},
+			Operations: []PatchOperation{{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Ops"`)}},
+		}, requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
+
+func TestDeleteGroup(t *testing.T) {
+	env := newTestEnv(t)
+	env.teams.ExpectedTeamDTO = &team.TeamDTO{ID: 5, UID: "t-sre", OrgID: 1, Name: "SRE"}
+
+	resp := env.svc.deleteGroupHandler(scimRequest(t, http.MethodDelete, "t-sre", nil, requester(org.RoleAdmin)))
+
+	require.Equal(t, http.StatusNoContent, resp.Status())
+}
+
+func TestGroupsAreAuthorizedPerTeam(t *testing.T) {
+	newEnv := func(t *testing.T) *testEnv {
+		env := newTestEnv(t)
+		env.orgs.orgs[2] = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleViewer}}
+		env.teams.ExpectedTeamDTO = &team.TeamDTO{ID: 5, UID: "t-sre", OrgID: 1, Name: "SRE"}
+		return env
+	}
+	// A team admin of another team.
+	otherTeamAdmin := &user.SignedInUser{UserID: 100, OrgID: 1, OrgRole: org.RoleViewer, Permissions: map[int64]map[string][]string{1: {
+		ac.ActionTeamsRead:             {"teams:id:6"},
+		ac.ActionTeamsWrite:            {"teams:id:6"},
+		ac.ActionTeamsPermissionsWrite: {"teams:id:6"},
+		ac.ActionTeamsDelete:           {"teams:id:6"},
+	}}}
+
+	t.Run("rejects changes to the members", func(t *testing.T) {
+		env := newEnv(t)
+
+		resp := env.svc.replaceGroupHandler(scimRequest(t, http.MethodPut, "t-sre", Group{
+			Schemas:     []string{SchemaGroup},
+			DisplayName: "SRE",
+			Members:     []Reference{{Value: "u-alice"}},
+		}, otherTeamAdmin))
+
+		require.Equal(t, http.StatusForbidden, resp.Status())
+		assert.Empty(t, env.permissions.changes)
+	})
+
+	t.Run("rejects patches", func(t *testing.T) {
+		env := newEnv(t)
+
+		resp := env.svc.patchGroupHandler(scimRequest(t, http.MethodPatch, "t-sre", PatchRequest{
+			Schemas:    []string{SchemaPatchOp},
+			Operations: []PatchOperation{{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Ops"`)}},
+		}, otherTeamAdmin))
+
+		require.Equal(t, http.StatusForbidden, resp.Status())
+	})
+
+	t.Run("rejects deletes", func(t *testing.T) {
+		env := newEnv(t)
+
+		resp := env.svc.deleteGroupHandler(scimRequest(t, http.MethodDelete, "t-sre", nil, otherTeamAdmin))
+
+		require.Equal(t, http.StatusForbidden, resp.Status())
+	})
+
+	t.Run("rejects reads", func(t *testing.T) {
+		env := newEnv(t)
+
+		resp := env.svc.getGroupHandler(scimRequest(t, http.MethodGet, "t-sre", nil, otherTeamAdmin))
+
+		require.Equal(t, http.StatusForbidden, resp.Status())
+	})
+
+	t.Run("allows changes to the team with a scoped permission", func(t *testing.T) {
+		env := newEnv(t)
+		teamAdmin := &user.SignedInUser{UserID: 100, OrgID: 1, OrgRole: org.RoleViewer, Permissions: map[int64]map[string][]string{1: {
+			ac.ActionOrgUsersRead:          {ac.ScopeUsersAll},
+			ac.ActionTeamsWrite:            {"teams:id:5"},
+			ac.ActionTeamsPermissionsWrite: {"teams:id:5"},
+		}}}
+
+		resp := env.svc.replaceGroupHandler(scimRequest(t, http.MethodPut, "t-sre", Group{
+			Schemas:     []string{SchemaGroup},
+			DisplayName: "SRE",
+			Members:     []Reference{{Value: "u-alice"}},
+		}, teamAdmin))
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		assert.Equal(t, []membershipChange{{teamID: "5", userID: 2, permission: team.PermissionTypeMember.String()}}, env.permissions.changes)
+	})
+}
@@ -0,0 +1,271 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	SchemaUser               = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup              = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse       = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp            = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaBulkRequest        = "urn:ietf:params:scim:api:messages:2.0:BulkRequest"
+	SchemaBulkResponse       = "urn:ietf:params:scim:api:messages:2.0:BulkResponse"
+	SchemaError              = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProvider    = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType       = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	ResourceTypeUser         = "User"
+	ResourceTypeGroup        = "Group"
+	ContentType              = "application/scim+json"
+	defaultEmailType         = "work"
+	bulkIDPrefix             = "bulkId:"
+	defaultListCount         = 100
+	defaultMaxPayloadSizeMiB = 1
+)
+
+// Error scim types as defined in RFC 7644, section 3.12.
+const (
+	ScimTypeInvalidFilter = "invalidFilter"
+	ScimTypeTooMany       = "tooMany"
+	ScimTypeUniqueness    = "uniqueness"
+	ScimTypeMutability    = "mutability"
+	ScimTypeInvalidSyntax = "invalidSyntax"
+	ScimTypeInvalidPath   = "invalidPath"
+	ScimTypeNoTarget      = "noTarget"
+	ScimTypeInvalidValue  = "invalidValue"
+)
+
+var (
+	ErrUserNotFound  = newError(http.StatusNotFound, "", "user not found")
+	ErrGroupNotFound = newError(http.StatusNotFound, "", "group not found")
+)
+
+// Error is a SCIM error response (RFC 7644, section 3.12).
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+
+	status int
+}
+
+func newError(status int, scimType string, format string, args ...any) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   fmt.Sprintf("%d", status),
+		ScimType: scimType,
+		Detail:   fmt.Sprintf(format, args...),
+		status:   status,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.ScimType != "" {
+		return fmt.Sprintf("scim: %s: %s", e.ScimType, e.Detail)
+	}
+	return "scim: " + e.Detail
+}
+
+// StatusCode returns the HTTP status of the error.
+func (e *Error) StatusCode() int {
+	return e.status
+}
+
+// asError converts any error to a SCIM error, treating unknown errors as internal server errors.
+func asError(err error) *Error {
+	var scimErr *Error
+	if errors.As(err, &scimErr) {
+		return scimErr
+	}
+	return newError(http.StatusInternalServerError, "", "internal server error")
+}
+
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// MultiValued is a multi-valued attribute entry such as an email or a role.
+type MultiValued struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Reference points to another SCIM resource, e.g. a group member or a user's group.
+type Reference struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+type User struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	UserName    string        `json:"userName"`
+	Name        *Name         `json:"name,omitempty"`
+	DisplayName string        `json:"displayName,omitempty"`
+	Emails      []MultiValued `json:"emails,omitempty"`
+	Active      *bool         `json:"active,omitempty"`
+	Roles       []MultiValued `json:"roles,omitempty"`
+	Groups      []Reference   `json:"groups,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+// PrimaryEmail returns the primary email of the user or the first one if none is flagged as primary.
+func (u *User) PrimaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// PrimaryRole returns the primary role of the user or the first one if none is flagged as primary.
+func (u *User) PrimaryRole() string {
+	for _, r := range u.Roles {
+		if r.Primary {
+			return r.Value
+		}
+	}
+	if len(u.Roles) > 0 {
+		return u.Roles[0].Value
+	}
+	return ""
+}
+
+// FullName returns the best display name the IdP sent for the user.
+func (u *User) FullName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name == nil {
+		return ""
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	if u.Name.GivenName != "" && u.Name.FamilyName != "" {
+		return u.Name.GivenName + " " + u.Name.FamilyName
+	}
+	return u.Name.GivenName + u.Name.FamilyName
+}
+
+type Group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members,omitempty"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type BulkRequest struct {
+	Schemas      []string        `json:"schemas"`
+	FailOnErrors int             `json:"failOnErrors,omitempty"`
+	Operations   []BulkOperation `json:"Operations"`
+}
+
+type BulkOperation struct {
+	Method  string          `json:"method"`
+	BulkID  string          `json:"bulkId,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Path    string          `json:"path"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type BulkResponse struct {
+	Schemas    []string              `json:"schemas"`
+	Operations []BulkOperationResult `json:"Operations"`
+}
+
+type BulkOperationResult struct {
+	Method   string `json:"method"`
+	BulkID   string `json:"bulkId,omitempty"`
+	Location string `json:"location,omitempty"`
+	Status   string `json:"status"`
+	Response any    `json:"response,omitempty"`
+}
+
+type ServiceProviderConfig struct {
+	Schemas               []string      `json:"schemas"`
+	Patch                 Supported     `json:"patch"`
+	Bulk                  BulkSupport   `json:"bulk"`
+	Filter                FilterSupport `json:"filter"`
+	ChangePassword        Supported     `json:"changePassword"`
+	Sort                  Supported     `json:"sort"`
+	ETag                  Supported     `json:"etag"`
+	AuthenticationSchemes []AuthScheme  `json:"authenticationSchemes"`
+	Meta                  *Meta         `json:"meta,omitempty"`
+}
+
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+type BulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type FilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type AuthScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary,omitempty"`
+}
+
+type ResourceType struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoint    string   `json:"endpoint"`
+	Description string   `json:"description,omitempty"`
+	Schema      string   `json:"schema"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
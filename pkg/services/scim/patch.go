@@ -0,0 +1,218 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// applyPatch applies SCIM PATCH operations (RFC 7644, section 3.5.2) to the
+// JSON representation of a resource.
+func applyPatch(resource map[string]any, ops []PatchOperation) error {
+	for _, op := range ops {
+		var value any
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return newError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid value for %q operation", op.Op)
+			}
+		}
+
+		kind := strings.ToLower(op.Op)
+		switch kind {
+		case "add", "replace", "remove":
+		default:
+			return newError(http.StatusBadRequest, ScimTypeInvalidSyntax, "unsupported patch operation %q", op.Op)
+		}
+
+		if op.Path == "" {
+			if kind == "remove" {
+				return newError(http.StatusBadRequest, ScimTypeNoTarget, "remove operations require a path")
+			}
+			attrs, ok := value.(map[string]any)
+			if !ok {
+				return newError(http.StatusBadRequest, ScimTypeInvalidValue, "operations without a path require an object value")
+			}
+			for k, v := range attrs {
+				if err := patchAttribute(resource, kind, parseAttrPath(k), v); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := patchPath(resource, kind, op.Path, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patchPath(resource map[string]any, kind, path string, value any) error {
+	open := strings.Index(path, "[")
+	if open < 0 {
+		return patchAttribute(resource, kind, parseAttrPath(path), value)
+	}
+
+	closing := strings.LastIndex(path, "]")
+	if closing < open {
+		return newError(http.StatusBadRequest, ScimTypeInvalidPath, "invalid path %q", path)
+	}
+	attr := parseAttrPath(path[:open])
+	filter, err := ParseFilter(path[open+1 : closing])
+	if err != nil {
+		return newError(http.StatusBadRequest, ScimTypeInvalidPath, "invalid path %q", path)
+	}
+	sub := strings.TrimPrefix(path[closing+1:], ".")
+
+	parent, key := container(resource, attr, kind != "remove")
+	if parent == nil {
+		if kind == "remove" {
+			return nil
+		}
+		return newError(http.StatusBadRequest, ScimTypeNoTarget, "no target for path %q", path)
+	}
+	items, _ := parent[key].([]any)
+
+	matched := false
+	kept := make([]any, 0, len(items))
+	for _, item := range items {
+		element, ok := item.(map[string]any)
+		if !ok || !filter.Matches(element) {
+			kept = append(kept, item)
+			continue
+		}
+		matched = true
+		switch {
+		case kind == "remove" && sub == "":
+			continue
+		case kind == "remove":
+			delete(element, sub)
+		case sub == "":
+			replacement, ok := value.(map[string]any)
+			if !ok {
+				return newError(http.StatusBadRequest, ScimTypeInvalidValue, "expected an object value for path %q", path)
+			}
+			for k, v := range replacement {
+				element[k] = v
+			}
+		default:
+			element[sub] = value
+		}
+		kept = append(kept, element)
+	}
+
+	if !matched && kind != "remove" {
+		// Build the element from the equality filter, so that e.g.
+		// emails[type eq "work"].value creates a work email.
+		element := map[string]any{}
+		if c, ok := filter.(*comparison); ok && c.op == "eq" && len(c.path) == 1 {
+			element[c.path[0]] = c.value
+		}
+		if sub != "" {
+			element[sub] = value
+		} else if replacement, ok := value.(map[string]any); ok {
+			for k, v := range replacement {
+				element[k] = v
+			}
+		}
+		kept = append(kept, element)
+	}
+
+	parent[key] = kept
+	return nil
+}
+
+func patchAttribute(resource map[string]any, kind string, path []string, value any) error {
+	parent, key := container(resource, path, kind != "remove")
+	if parent == nil {
+		return nil
+	}
+
+	switch kind {
+	case "remove":
+		if items, ok := parent[key].([]any); ok && value != nil {
+			parent[key] = removeValues(items, value)
+			return nil
+		}
+		delete(parent, key)
+	case "add":
+		if existing, ok := parent[key].([]any); ok {
+			parent[key] = append(existing, asSlice(value)...)
+			return nil
+		}
+		if existing, ok := parent[key].(map[string]any); ok {
+			if attrs, ok := value.(map[string]any); ok {
+				for k, v := range attrs {
+					existing[k] = v
+				}
+				return nil
+			}
+		}
+		parent[key] = value
+	default:
+		parent[key] = value
+	}
+	return nil
+}
+
+// container walks path and returns the object holding its last component,
+// along with the key of that component in the object.
+func container(resource map[string]any, path []string, create bool) (map[string]any, string) {
+	node := resource
+	for i, part := range path {
+		key := part
+		for k := range node {
+			if strings.EqualFold(k, part) {
+				key = k
+				break
+			}
+		}
+		if i == len(path)-1 {
+			return node, key
+		}
+		child, ok := node[key].(map[string]any)
+		if !ok {
+			if !create {
+				return nil, ""
+			}
+			child = map[string]any{}
+			node[key] = child
+		}
+		node = child
+	}
+	return nil, ""
+}
+
+func asSlice(v any) []any {
+	if s, ok := v.([]any); ok {
+		return s
+	}
+	return []any{v}
+}
+
+// removeValues removes the given complex values from a multi-valued attribute, matching on "value".
+func removeValues(items []any, value any) []any {
+	remove := map[string]bool{}
+	for _, v := range asSlice(value) {
+		if m, ok := v.(map[string]any); ok {
+			if id, ok := lookup(m, "value"); ok {
+				if s, ok := id.(string); ok {
+					remove[s] = true
+				}
+			}
+		}
+	}
+
+	kept := make([]any, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			if id, ok := lookup(m, "value"); ok {
+				if s, ok := id.(string); ok && remove[s] {
+					continue
+				}
+			}
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
@@ -0,0 +1,164 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatch(t *testing.T) {
+	newUser := func() map[string]any {
+		active := true
+		m, err := toMap(&User{
+			Schemas:     []string{SchemaUser},
+			ID:          "u1",
+			UserName:    "alice",
+			DisplayName: "Alice",
+			Emails:      []MultiValued{{Value: "alice@example.com", Type: "work", Primary: true}},
+			Active:      &active,
+		})
+		require.NoError(t, err)
+		return m
+	}
+	op := func(op, path, value string) PatchOperation {
+		o := PatchOperation{Op: op, Path: path}
+		if value != "" {
+			o.Value = json.RawMessage(value)
+		}
+		return o
+	}
+	patched := func(t *testing.T, ops ...PatchOperation) *User {
+		t.Helper()
+		resource := newUser()
+		require.NoError(t, applyPatch(resource, ops))
+		normalizeActive(resource)
+		var u User
+		require.NoError(t, fromMap(resource, &u))
+		return &u
+	}
+
+	t.Run("replace a simple attribute", func(t *testing.T) {
+		u := patched(t, op("replace", "displayName", `"Alice Smith"`))
+		assert.Equal(t, "Alice Smith", u.DisplayName)
+	})
+
+	t.Run("deactivate with a string value", func(t *testing.T) {
+		u := patched(t, op("Replace", "active", `"False"`))
+		require.NotNil(t, u.Active)
+		assert.False(t, *u.Active)
+	})
+
+	t.Run("replace without a path", func(t *testing.T) {
+		u := patched(t, op("replace", "", `{"active": false, "name.givenName": "Alice"}`))
+		require.NotNil(t, u.Active)
+		assert.False(t, *u.Active)
+		require.NotNil(t, u.Name)
+		assert.Equal(t, "Alice", u.Name.GivenName)
+	})
+
+	t.Run("replace a filtered sub-attribute", func(t *testing.T) {
+		u := patched(t, op("replace", `emails[type eq "work"].value`, `"alice@corp.example.com"`))
+		assert.Equal(t, "alice@corp.example.com", u.PrimaryEmail())
+	})
+
+	t.Run("add a filtered sub-attribute without a match creates the element", func(t *testing.T) {
+		u := patched(t, op("add", `emails[type eq "home"].value`, `"alice@home.org"`))
+		require.Len(t, u.Emails, 2)
+		assert.Equal(t, "home", u.Emails[1].Type)
+		assert.Equal(t, "alice@home.org", u.Emails[1].Value)
+	})
+
+	t.Run("add to a multi-valued attribute appends", func(t *testing.T) {
+		u := patched(t, op("add", "roles", `[{"value": "Editor", "primary": true}]`))
+		assert.Equal(t, "Editor", u.PrimaryRole())
+	})
+
+	t.Run("remove an attribute", func(t *testing.T) {
+		u := patched(t, op("remove", "displayName", ""))
+		assert.Empty(t, u.DisplayName)
+	})
+
+	t.Run("unsupported operations fail", func(t *testing.T) {
+		err := applyPatch(newUser(), []PatchOperation{op("move", "displayName", `"x"`)})
+		require.Error(t, err)
+		assert.Equal(t, ScimTypeInvalidSyntax, asError(err).ScimType)
+	})
+
+	t.Run("remove without a path fails", func(t *testing.T) {
+		err := applyPatch(newUser(), []PatchOperation{op("remove", "", "")})
+		require.Error(t, err)
+		assert.Equal(t, ScimTypeNoTarget, asError(err).ScimType)
+	})
+}
+
+func TestApplyPatch_GroupMembers(t *testing.T) {
+	newGroup := func() map[string]any {
+		m, err := toMap(&Group{
+			Schemas:     []string{SchemaGroup},
+			ID:          "g1",
+			DisplayName: "backend",
+			Members:     []Reference{{Value: "u1"}, {Value: "u2"}},
+		})
+		require.NoError(t, err)
+		return m
+	}
+	members := func(t *testing.T, resource map[string]any) []string {
+		t.Helper()
+		var g Group
+		require.NoError(t, fromMap(resource, &g))
+		ids := []string{}
+		for _, m := range g.Members {
+			ids = append(ids, m.Value)
+		}
+		return ids
+	}
+
+	t.Run("add members", func(t *testing.T) {
+		resource := newGroup()
+		require.NoError(t, applyPatch(resource, []PatchOperation{
+			{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "u3"}]`)},
+		}))
+		assert.Equal(t, []string{"u1", "u2", "u3"}, members(t, resource))
+	})
+
+	t.Run("remove a member with a filter", func(t *testing.T) {
+		resource := newGroup()
+		require.NoError(t, applyPatch(resource, []PatchOperation{
+			{Op: "remove", Path: `members[value eq "u1"]`},
+		}))
+		assert.Equal(t, []string{"u2"}, members(t, resource))
+	})
+
+	t.Run("remove members by value", func(t *testing.T) {
+		resource := newGroup()
+		require.NoError(t, applyPatch(resource, []PatchOperation{
+			{Op: "remove", Path: "members", Value: json.RawMessage(`[{"value": "u2"}]`)},
+		}))
+		assert.Equal(t, []string{"u1"}, members(t, resource))
+	})
+
+	t.Run("replace all members", func(t *testing.T) {
+		resource := newGroup()
+		require.NoError(t, applyPatch(resource, []PatchOperation{
+			{Op: "replace", Path: "members", Value: json.RawMessage(`[{"value": "u4"}]`)},
+		}))
+		assert.Equal(t, []string{"u4"}, members(t, resource))
+	})
+}
+
+func TestResolveBulkIDs(t *testing.T) {
+	created := map[string]string{"alice": "uid-1", "bob": "uid-2"}
+
+	resolved, err := resolveBulkIDs(`{"members":[{"value":"bulkId:alice"},{"value":"bulkId:bob"}]}`, created)
+	require.NoError(t, err)
+	assert.Equal(t, `{"members":[{"value":"uid-1"},{"value":"uid-2"}]}`, resolved)
+
+	resolved, err = resolveBulkIDs("/Users/bulkId:alice", created)
+	require.NoError(t, err)
+	assert.Equal(t, "/Users/uid-1", resolved)
+
+	_, err = resolveBulkIDs(`{"value":"bulkId:carol"}`, created)
+	require.Error(t, err)
+}
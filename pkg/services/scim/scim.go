@@ -0,0 +1,338 @@
+package scim
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const basePath = "/api/scim/v2"
+
+// Service exposes a SCIM 2.0 (RFC 7643, RFC 7644) provisioning API, mapping
+// SCIM Users onto Grafana users and their membership in the caller's org, and
+// SCIM Groups onto teams of that org.
+type Service struct {
+	cfg                    setting.SCIMSettings
+	appURL                 string
+	log                    log.Logger
+	accessControl          ac.AccessControl
+	acService              ac.Service
+	userService            user.Service
+	orgService             org.Service
+	teamService            team.Service
+	teamPermissionsService ac.TeamPermissionsService
+	sessionService         auth.UserTokenService
+}
+
+func ProvideService(
+	cfg *setting.Cfg, router routing.RouteRegister, accessControl ac.AccessControl, acService ac.Service,
+	userService user.Service, orgService org.Service, teamService team.Service,
+	teamPermissionsService ac.TeamPermissionsService, sessionService auth.UserTokenService,
+) *Service {
+	s := &Service{
+		cfg:                    cfg.SCIM,
+		appURL:                 cfg.AppURL,
+		log:                    log.New("scim"),
+		accessControl:          accessControl,
+		acService:              acService,
+		userService:            userService,
+		orgService:             orgService,
+		teamService:            teamService,
+		teamPermissionsService: teamPermissionsService,
+		sessionService:         sessionService,
+	}
+
+	if cfg.SCIM.Enabled {
+		s.registerRoutes(router)
+	}
+
+	return s
+}
+
+// Provisioning is scoped to the org of the caller, usually a service account
+// of that org: enabling SCIM lets org user managers create and deactivate the
+// users of their org. Creating a user also creates a Grafana user, and changes
+// to the attributes that users share across orgs are authorized per user, see
+// authorizeGlobalUpdate.
+var (
+	evalUsersRead    = ac.EvalPermission(ac.ActionOrgUsersRead)
+	evalUsersCreate  = ac.EvalAll(ac.EvalPermission(ac.ActionOrgUsersAdd), ac.EvalPermission(ac.ActionUsersCreate))
+	evalUsersWrite   = ac.EvalPermission(ac.ActionOrgUsersWrite)
+	evalUsersDelete  = ac.EvalPermission(ac.ActionOrgUsersRemove)
+	evalGroupsRead   = ac.EvalPermission(ac.ActionTeamsRead)
+	evalGroupsCreate = ac.EvalAll(ac.EvalPermission(ac.ActionTeamsCreate), ac.EvalPermission(ac.ActionTeamsPermissionsWrite))
+	evalGroupsWrite  = ac.EvalAll(ac.EvalPermission(ac.ActionTeamsWrite), ac.EvalPermission(ac.ActionTeamsPermissionsWrite))
+	evalGroupsDelete = ac.EvalPermission(ac.ActionTeamsDelete)
+)
+
+// The group evaluators above only check that the caller has the actions on
+// some team. The team of a request is authorized once its ID is resolved from
+// the SCIM ID, see getTeam.
+func evalGroupRead(scope string) ac.Evaluator {
+	return ac.EvalPermission(ac.ActionTeamsRead, scope)
+}
+
+func evalGroupWrite(scope string) ac.Evaluator {
+	return ac.EvalAll(ac.EvalPermission(ac.ActionTeamsWrite, scope), ac.EvalPermission(ac.ActionTeamsPermissionsWrite, scope))
+}
+
+func evalGroupDelete(scope string) ac.Evaluator {
+	return ac.EvalPermission(ac.ActionTeamsDelete, scope)
+}
+
+func (s *Service) registerRoutes(router routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	router.Group(basePath, func(scimRoute routing.RouteRegister) {
+		scimRoute.Get("/ServiceProviderConfig", routing.Wrap(s.getServiceProviderConfig))
+		scimRoute.Get("/ResourceTypes", routing.Wrap(s.getResourceTypes))
+
+		scimRoute.Get("/Users", authorize(evalUsersRead), routing.Wrap(s.listUsersHandler))
+		scimRoute.Post("/Users", authorize(evalUsersCreate), routing.Wrap(s.createUserHandler))
+		scimRoute.Get("/Users/:id", authorize(evalUsersRead), routing.Wrap(s.getUserHandler))
+		scimRoute.Put("/Users/:id", authorize(evalUsersWrite), routing.Wrap(s.replaceUserHandler))
+		scimRoute.Patch("/Users/:id", authorize(evalUsersWrite), routing.Wrap(s.patchUserHandler))
+		scimRoute.Delete("/Users/:id", authorize(evalUsersDelete), routing.Wrap(s.deleteUserHandler))
+
+		scimRoute.Get("/Groups", authorize(evalGroupsRead), routing.Wrap(s.listGroupsHandler))
+		scimRoute.Post("/Groups", authorize(evalGroupsCreate), routing.Wrap(s.createGroupHandler))
+		scimRoute.Get("/Groups/:id", authorize(evalGroupsRead), routing.Wrap(s.getGroupHandler))
+		scimRoute.Put("/Groups/:id", authorize(evalGroupsWrite), routing.Wrap(s.replaceGroupHandler))
+		scimRoute.Patch("/Groups/:id", authorize(evalGroupsWrite), routing.Wrap(s.patchGroupHandler))
+		scimRoute.Delete("/Groups/:id", authorize(evalGroupsDelete), routing.Wrap(s.deleteGroupHandler))
+
+		// Operations in a bulk request are authorized one by one.
+		scimRoute.Post("/Bulk", routing.Wrap(s.bulkHandler))
+	}, middleware.ReqSignedIn)
+}
+
+func (s *Service) getServiceProviderConfig(c *contextmodel.ReqContext) response.Response {
+	return scimResponse(http.StatusOK, ServiceProviderConfig{
+		Schemas: []string{SchemaServiceProvider},
+		Patch:   Supported{Supported: true},
+		Bulk: BulkSupport{
+			Supported:      true,
+			MaxOperations:  s.cfg.MaxBulkOperations,
+			MaxPayloadSize: defaultMaxPayloadSizeMiB * 1024 * 1024,
+		},
+		Filter:         FilterSupport{Supported: true, MaxResults: s.cfg.MaxResults},
+		ChangePassword: Supported{Supported: false},
+		Sort:           Supported{Supported: false},
+		ETag:           Supported{Supported: false},
+		AuthenticationSchemes: []AuthScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Service account token",
+			Description: "Authentication with a Grafana service account token passed as a bearer token",
+			Primary:     true,
+		}},
+		Meta: &Meta{ResourceType: "ServiceProviderConfig", Location: s.location("ServiceProviderConfig")},
+	})
+}
+
+func (s *Service) getResourceTypes(c *contextmodel.ReqContext) response.Response {
+	resources := []any{
+		ResourceType{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeUser,
+			Name:        ResourceTypeUser,
+			Endpoint:    "/Users",
+			Description: "Grafana user and their role in the organization",
+			Schema:      SchemaUser,
+			Meta:        &Meta{ResourceType: "ResourceType", Location: s.location("ResourceTypes", ResourceTypeUser)},
+		},
+		ResourceType{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeGroup,
+			Name:        ResourceTypeGroup,
+			Endpoint:    "/Groups",
+			Description: "Grafana team",
+			Schema:      SchemaGroup,
+			Meta:        &Meta{ResourceType: "ResourceType", Location: s.location("ResourceTypes", ResourceTypeGroup)},
+		},
+	}
+	return scimResponse(http.StatusOK, ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// location returns the absolute URL of a SCIM resource.
+func (s *Service) location(parts ...string) string {
+	loc := s.appURL
+	if len(loc) > 0 && loc[len(loc)-1] == '/' {
+		loc = loc[:len(loc)-1]
+	}
+	loc += basePath
+	for _, p := range parts {
+		loc += "/" + p
+	}
+	return loc
+}
+
+// listParams holds the standard query parameters of a SCIM list request.
+type listParams struct {
+	filter     Filter
+	startIndex int
+	count      int
+}
+
+func (s *Service) parseListParams(c *contextmodel.ReqContext) (listParams, error) {
+	params := listParams{startIndex: 1, count: defaultListCount}
+	if s.cfg.MaxResults > 0 && params.count > s.cfg.MaxResults {
+		params.count = s.cfg.MaxResults
+	}
+
+	if raw := c.Query("startIndex"); raw != "" {
+		i, err := strconv.Atoi(raw)
+		if err != nil {
+			return params, newError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid startIndex %q", raw)
+		}
+		// Values lower than 1 are interpreted as 1 (RFC 7644, section 3.4.2.4).
+		if i > 1 {
+			params.startIndex = i
+		}
+	}
+	if raw := c.Query("count"); raw != "" {
+		i, err := strconv.Atoi(raw)
+		if err != nil {
+			return params, newError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid count %q", raw)
+		}
+		if i < 0 {
+			i = 0
+		}
+		if s.cfg.MaxResults > 0 && i > s.cfg.MaxResults {
+			i = s.cfg.MaxResults
+		}
+		params.count = i
+	}
+	if raw := c.Query("filter"); raw != "" {
+		f, err := ParseFilter(raw)
+		if err != nil {
+			return params, err
+		}
+		params.filter = f
+	}
+	return params, nil
+}
+
+// paginate filters the resources and returns the requested page as a list response.
+func paginate[T any](resources []T, params listParams) (ListResponse, error) {
+	matched := make([]any, 0, len(resources))
+	for _, r := range resources {
+		if params.filter != nil {
+			m, err := toMap(r)
+			if err != nil {
+				return ListResponse{}, err
+			}
+			if !params.filter.Matches(m) {
+				continue
+			}
+		}
+		matched = append(matched, r)
+	}
+
+	page := []any{}
+	if start := params.startIndex - 1; start < len(matched) {
+		end := start + params.count
+		if end > len(matched) {
+			end = len(matched)
+		}
+		page = matched[start:end]
+	}
+
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(matched),
+		StartIndex:   params.startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}, nil
+}
+
+func toMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]any{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func fromMap(m map[string]any, v any) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return newError(http.StatusBadRequest, ScimTypeInvalidValue, "invalid resource: %s", err)
+	}
+	return nil
+}
+
+// bind decodes a SCIM request body. Unlike web.Bind, it accepts the
+// application/scim+json media type that SCIM clients send.
+func bind(req *http.Request, v any) error {
+	if req.Body == nil {
+		return newError(http.StatusBadRequest, ScimTypeInvalidSyntax, "missing request body")
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		m, _, err := mime.ParseMediaType(ct)
+		if err != nil || (m != ContentType && m != "application/json") {
+			return newError(http.StatusUnsupportedMediaType, "", "unsupported content type %q", ct)
+		}
+	}
+	defer func() { _ = req.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, defaultMaxPayloadSizeMiB*1024*1024+1))
+	if err != nil {
+		return newError(http.StatusBadRequest, ScimTypeInvalidSyntax, "failed to read request body")
+	}
+	if len(body) > defaultMaxPayloadSizeMiB*1024*1024 {
+		return newError(http.StatusRequestEntityTooLarge, "", "request body exceeds %d bytes", defaultMaxPayloadSizeMiB*1024*1024)
+	}
+	return decode(body, v)
+}
+
+func decode(body []byte, v any) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		return newError(http.StatusBadRequest, ScimTypeInvalidSyntax, "invalid request body: %s", err)
+	}
+	return nil
+}
+
+func scimResponse(status int, body any) *response.NormalResponse {
+	return response.JSON(status, body).SetHeader("Content-Type", ContentType)
+}
+
+func (s *Service) errorResponse(err error) response.Response {
+	scimErr := asError(err)
+	if scimErr.StatusCode() >= http.StatusInternalServerError {
+		s.log.Error("SCIM request failed", "error", err)
+	}
+	return scimResponse(scimErr.StatusCode(), scimErr)
+}
+
+func resourceID(c *contextmodel.ReqContext) string {
+	return web.Params(c.Req)[":id"]
+}
@@ -0,0 +1,459 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func (s *Service) listUsersHandler(c *contextmodel.ReqContext) response.Response {
+	params, err := s.parseListParams(c)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	users, err := s.listUsers(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	list, err := paginate(users, params)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusOK, list)
+}
+
+func (s *Service) getUserHandler(c *contextmodel.ReqContext) response.Response {
+	u, err := s.getUser(c.Req.Context(), c.SignedInUser, resourceID(c))
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusOK, u)
+}
+
+func (s *Service) createUserHandler(c *contextmodel.ReqContext) response.Response {
+	var u User
+	if err := bind(c.Req, &u); err != nil {
+		return s.errorResponse(err)
+	}
+	created, err := s.createUser(c.Req.Context(), c.SignedInUser, &u)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusCreated, created).SetHeader("Location", created.Meta.Location)
+}
+
+func (s *Service) replaceUserHandler(c *contextmodel.ReqContext) response.Response {
+	var u User
+	if err := bind(c.Req, &u); err != nil {
+		return s.errorResponse(err)
+	}
+	updated, err := s.replaceUser(c.Req.Context(), c.SignedInUser, resourceID(c), &u)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusOK, updated)
+}
+
+func (s *Service) patchUserHandler(c *contextmodel.ReqContext) response.Response {
+	var patch PatchRequest
+	if err := bind(c.Req, &patch); err != nil {
+		return s.errorResponse(err)
+	}
+	updated, err := s.patchUser(c.Req.Context(), c.SignedInUser, resourceID(c), patch.Operations)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	return scimResponse(http.StatusOK, updated)
+}
+
+func (s *Service) deleteUserHandler(c *contextmodel.ReqContext) response.Response {
+	if err := s.deleteUser(c.Req.Context(), c.SignedInUser, resourceID(c)); err != nil {
+		return s.errorResponse(err)
+	}
+	return response.Empty(http.StatusNoContent)
+}
+
+// listUsers returns every user of the requester's org as a SCIM resource.
+// Group membership is not included in list responses to keep them cheap.
+func (s *Service) listUsers(ctx context.Context, requester identity.Requester) ([]*User, error) {
+	index, err := s.indexOrgUsers(ctx, requester)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*User, 0, len(index.users))
+	for _, ou := range index.users {
+		users = append(users, s.toSCIMUser(&user.User{
+			UID:        ou.UID,
+			Login:      ou.Login,
+			Email:      ou.Email,
+			Name:       ou.Name,
+			IsDisabled: ou.IsDisabled,
+			Created:    ou.Created,
+			Updated:    ou.Updated,
+		}, org.RoleType(ou.Role), nil))
+	}
+	return users, nil
+}
+
+// orgUserIndex indexes the users of an org by ID and UID.
+type orgUserIndex struct {
+	users []*org.OrgUserDTO
+	byID  map[int64]*org.OrgUserDTO
+	byUID map[string]*org.OrgUserDTO
+}
+
+func (s *Service) indexOrgUsers(ctx context.Context, requester identity.Requester) (*orgUserIndex, error) {
+	result, err := s.orgService.SearchOrgUsers(ctx, &org.SearchOrgUsersQuery{
+		OrgID:                    requester.GetOrgID(),
+		DontEnforceAccessControl: true,
+		User:                     requester,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search org users: %w", err)
+	}
+
+	index := &orgUserIndex{
+		users: result.OrgUsers,
+		byID:  make(map[int64]*org.OrgUserDTO, len(result.OrgUsers)),
+		byUID: make(map[string]*org.OrgUserDTO, len(result.OrgUsers)),
+	}
+	for _, ou := range result.OrgUsers {
+		index.byID[ou.UserID] = ou
+		index.byUID[ou.UID] = ou
+	}
+	return index, nil
+}
+
+func (s *Service) getUser(ctx context.Context, requester identity.Requester, uid string) (*User, error) {
+	usr, role, err := s.getOrgUser(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.teamService.GetUserTeamMemberships(ctx, requester.GetOrgID(), usr.ID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team memberships: %w", err)
+	}
+	return s.toSCIMUser(usr, role, memberships), nil
+}
+
+// getOrgUser returns the user with the given UID along with their role,
+// provided they are a member of the org.
+func (s *Service) getOrgUser(ctx context.Context, orgID int64, uid string) (*user.User, org.RoleType, error) {
+	usr, err := s.userService.GetByUID(ctx, &user.GetUserByUIDQuery{UID: uid})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, "", ErrUserNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
+	if usr.IsServiceAccount {
+		return nil, "", ErrUserNotFound
+	}
+
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: usr.ID})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get user orgs: %w", err)
+	}
+	for _, o := range orgs {
+		if o.OrgID == orgID {
+			return usr, o.Role, nil
+		}
+	}
+	return nil, "", ErrUserNotFound
+}
+
+func (s *Service) createUser(ctx context.Context, requester identity.Requester, u *User) (*User, error) {
+	if u.UserName == "" {
+		return nil, newError(http.StatusBadRequest, ScimTypeInvalidValue, "userName is required")
+	}
+	role, err := s.roleFor(u)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeRole(requester, role); err != nil {
+		return nil, err
+	}
+
+	orgID := requester.GetOrgID()
+	email := u.PrimaryEmail()
+	if email == "" && strings.Contains(u.UserName, "@") {
+		email = u.UserName
+	}
+
+	usr, err := s.userService.Create(ctx, &user.CreateUserCommand{
+		Login:         u.UserName,
+		Email:         email,
+		Name:          u.FullName(),
+		OrgID:         orgID,
+		EmailVerified: email != "",
+		IsDisabled:    u.Active != nil && !*u.Active,
+		SkipOrgSetup:  true,
+	})
+	if err != nil {
+		if errors.Is(err, user.ErrUserAlreadyExists) {
+			return nil, newError(http.StatusConflict, ScimTypeUniqueness, "user %q already exists", u.UserName)
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := s.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{OrgID: orgID, UserID: usr.ID, Role: role}); err != nil {
+		// Remove the user we just created, retries would conflict with it.
+		if err := s.userService.Delete(ctx, &user.DeleteUserCommand{UserID: usr.ID}); err != nil {
+			s.log.Error("Failed to delete user that could not be added to the org", "login", usr.Login, "error", err)
+		}
+		return nil, fmt.Errorf("failed to add user to org: %w", err)
+	}
+
+	s.log.Info("Provisioned user", "login", usr.Login, "orgID", orgID, "role", role)
+	return s.toSCIMUser(usr, role, nil), nil
+}
+
+func (s *Service) replaceUser(ctx context.Context, requester identity.Requester, uid string, u *User) (*User, error) {
+	usr, currentRole, err := s.getOrgUser(ctx, requester.GetOrgID(), uid)
+	if err != nil {
+		return nil, err
+	}
+	if u.UserName == "" {
+		return nil, newError(http.StatusBadRequest, ScimTypeInvalidValue, "userName is required")
+	}
+
+	role := currentRole
+	if len(u.Roles) > 0 {
+		if role, err = s.roleFor(u); err != nil {
+			return nil, err
+		}
+	}
+	if role != currentRole {
+		if err := authorizeRole(requester, role); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := &user.UpdateUserCommand{
+		UserID: usr.ID,
+		Login:  u.UserName,
+		Email:  u.PrimaryEmail(),
+		Name:   u.FullName(),
+	}
+	if cmd.Email == "" {
+		cmd.Email = usr.Email
+	}
+	if cmd.Name == "" {
+		cmd.Name = usr.Name
+	}
+	if cmd.Login != usr.Login || cmd.Email != usr.Email || cmd.Name != usr.Name {
+		if err := s.authorizeGlobalUpdate(ctx, requester, usr, ac.ActionUsersWrite); err != nil {
+			return nil, err
+		}
+	}
+	// "active" is optional, the user stays as is when it isn't set.
+	disabled := usr.IsDisabled
+	if u.Active != nil {
+		disabled = !*u.Active
+	}
+	if disabled != usr.IsDisabled {
+		if err := s.authorizeGlobalUpdate(ctx, requester, usr, ac.ActionUsersDisable); err != nil {
+			return nil, err
+		}
+		cmd.IsDisabled = &disabled
+	}
+	if err := s.userService.Update(ctx, cmd); err != nil {
+		if errors.Is(err, user.ErrCaseInsensitive) || errors.Is(err, user.ErrUserAlreadyExists) {
+			return nil, newError(http.StatusConflict, ScimTypeUniqueness, "user %q already exists", u.UserName)
+		}
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if disabled && !usr.IsDisabled {
+		if err := s.sessionService.RevokeAllUserTokens(ctx, usr.ID); err != nil {
+			return nil, fmt.Errorf("failed to revoke sessions of deactivated user: %w", err)
+		}
+		s.log.Info("Deactivated user", "login", usr.Login)
+	}
+
+	if role != currentRole {
+		err := s.orgService.UpdateOrgUser(ctx, &org.UpdateOrgUserCommand{OrgID: requester.GetOrgID(), UserID: usr.ID, Role: role})
+		if err != nil {
+			if errors.Is(err, org.ErrLastOrgAdmin) {
+				return nil, newError(http.StatusBadRequest, ScimTypeMutability, "cannot change the role of the last org admin")
+			}
+			return nil, fmt.Errorf("failed to update org role: %w", err)
+		}
+	}
+
+	return s.getUser(ctx, requester, uid)
+}
+
+// authorizeGlobalUpdate checks that the requester can change the attributes
+// that the user shares across orgs: the login, email, name and whether the
+// user is disabled. It requires the action on the user, unless the user is
+// only a member of the requester's org and isn't a server admin.
+func (s *Service) authorizeGlobalUpdate(ctx context.Context, requester identity.Requester, usr *user.User, action string) error {
+	scope := ac.Scope("global.users", "id", strconv.FormatInt(usr.ID, 10))
+	ok, err := s.accessControl.Evaluate(ctx, requester, ac.EvalPermission(action, scope))
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+	if usr.IsAdmin {
+		return newError(http.StatusForbidden, "", "user %q is a server admin, permissions needed: %s on %s", usr.Login, action, scope)
+	}
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: usr.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get user orgs: %w", err)
+	}
+	for _, o := range orgs {
+		if o.OrgID != requester.GetOrgID() {
+			return newError(http.StatusForbidden, "", "user %q is a member of other organizations, permissions needed: %s on %s", usr.Login, action, scope)
+		}
+	}
+	return nil
+}
+
+// authorizeRole rejects roles higher than the requester's own role, like the
+// org users API does.
+func authorizeRole(requester identity.Requester, role org.RoleType) error {
+	if !requester.GetOrgRole().Includes(role) && !requester.GetIsGrafanaAdmin() {
+		return newError(http.StatusForbidden, "", "cannot assign a role higher than your own")
+	}
+	return nil
+}
+
+func (s *Service) patchUser(ctx context.Context, requester identity.Requester, uid string, ops []PatchOperation) (*User, error) {
+	current, err := s.getUser(ctx, requester, uid)
+	if err != nil {
+		return nil, err
+	}
+	resource, err := toMap(current)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPatch(resource, ops); err != nil {
+		return nil, err
+	}
+	normalizeActive(resource)
+
+	var patched User
+	if err := fromMap(resource, &patched); err != nil {
+		return nil, err
+	}
+	return s.replaceUser(ctx, requester, uid, &patched)
+}
+
+// deleteUser removes the user from the org and its teams, deleting the user
+// when it isn't a member of any other org.
+func (s *Service) deleteUser(ctx context.Context, requester identity.Requester, uid string) error {
+	orgID := requester.GetOrgID()
+	usr, _, err := s.getOrgUser(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+
+	memberships, err := s.teamService.GetUserTeamMemberships(ctx, orgID, usr.ID, false)
+	if err != nil {
+		return fmt.Errorf("failed to get team memberships: %w", err)
+	}
+	for _, m := range memberships {
+		if err := s.setTeamMembership(ctx, orgID, m.TeamID, usr.ID, false); err != nil {
+			return err
+		}
+	}
+
+	cmd := &org.RemoveOrgUserCommand{OrgID: orgID, UserID: usr.ID, ShouldDeleteOrphanedUser: true}
+	if err := s.orgService.RemoveOrgUser(ctx, cmd); err != nil {
+		if errors.Is(err, org.ErrLastOrgAdmin) {
+			return newError(http.StatusBadRequest, ScimTypeMutability, "cannot remove the last org admin")
+		}
+		return fmt.Errorf("failed to remove user from org: %w", err)
+	}
+
+	permissionsOrgID := orgID
+	if cmd.UserWasDeleted {
+		permissionsOrgID = ac.GlobalOrgID
+	}
+	if err := s.acService.DeleteUserPermissions(ctx, permissionsOrgID, usr.ID); err != nil {
+		s.log.Warn("Failed to delete permissions for user", "userID", usr.ID, "orgID", permissionsOrgID, "error", err)
+	}
+	if err := s.sessionService.RevokeAllUserTokens(ctx, usr.ID); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	s.log.Info("Deprovisioned user", "login", usr.Login, "orgID", orgID, "deleted", cmd.UserWasDeleted)
+	return nil
+}
+
+// roleFor returns the org role requested for the user, falling back to the configured default.
+func (s *Service) roleFor(u *User) (org.RoleType, error) {
+	role := u.PrimaryRole()
+	if role == "" {
+		return org.RoleType(s.cfg.DefaultOrgRole), nil
+	}
+	for _, r := range []org.RoleType{org.RoleNone, org.RoleViewer, org.RoleEditor, org.RoleAdmin} {
+		if strings.EqualFold(string(r), role) {
+			return r, nil
+		}
+	}
+	return "", newError(http.StatusBadRequest, ScimTypeInvalidValue, "unknown role %q", role)
+}
+
+func (s *Service) toSCIMUser(usr *user.User, role org.RoleType, memberships []*team.TeamMemberDTO) *User {
+	active := !usr.IsDisabled
+	created, updated := usr.Created, usr.Updated
+
+	u := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          usr.UID,
+		UserName:    usr.Login,
+		DisplayName: usr.Name,
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: ResourceTypeUser,
+			Created:      &created,
+			LastModified: &updated,
+			Location:     s.location("Users", usr.UID),
+		},
+	}
+	if usr.Name != "" {
+		u.Name = &Name{Formatted: usr.Name}
+	}
+	if usr.Email != "" {
+		u.Emails = []MultiValued{{Value: usr.Email, Type: defaultEmailType, Primary: true}}
+	}
+	if role != "" {
+		u.Roles = []MultiValued{{Value: string(role), Primary: true}}
+	}
+	for _, m := range memberships {
+		u.Groups = append(u.Groups, Reference{Value: m.TeamUID, Ref: s.location("Groups", m.TeamUID)})
+	}
+	return u
+}
+
+// normalizeActive converts the "active" attribute to a boolean, as some
+// identity providers send it as a string in PATCH requests.
+func normalizeActive(resource map[string]any) {
+	for k, v := range resource {
+		if !strings.EqualFold(k, "active") {
+			continue
+		}
+		if str, ok := v.(string); ok {
+			if b, err := strconv.ParseBool(str); err == nil {
+				resource[k] = b
+			}
+		}
+	}
+}
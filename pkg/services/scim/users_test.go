@@ -0,0 +1,411 @@
+package scim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// fakeOrgService keeps the org memberships of users in memory.
+type fakeOrgService struct {
+	org.Service
+	orgs    map[int64][]*org.UserOrgDTO
+	added   []*org.AddOrgUserCommand
+	updated []*org.UpdateOrgUserCommand
+	addErr  error
+}
+
+func (f *fakeOrgService) GetUserOrgList(_ context.Context, q *org.GetUserOrgListQuery) ([]*org.UserOrgDTO, error) {
+	return f.orgs[q.UserID], nil
+}
+
+func (f *fakeOrgService) SearchOrgUsers(_ context.Context, q *org.SearchOrgUsersQuery) (*org.SearchOrgUsersQueryResult, error) {
+	result := &org.SearchOrgUsersQueryResult{}
+	for userID, orgs := range f.orgs {
+		for _, o := range orgs {
+			if o.OrgID == q.OrgID {
+				result.OrgUsers = append(result.OrgUsers, &org.OrgUserDTO{UserID: userID, UID: uidOf(userID), Role: string(o.Role)})
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeOrgService) AddOrgUser(_ context.Context, cmd *org.AddOrgUserCommand) error {
+	if f.addErr != nil {
+		return f.addErr
+	}
+	f.added = append(f.added, cmd)
+	return nil
+}
+
+func (f *fakeOrgService) UpdateOrgUser(_ context.Context, cmd *org.UpdateOrgUserCommand) error {
+	f.updated = append(f.updated, cmd)
+	return nil
+}
+
+func (f *fakeOrgService) RemoveOrgUser(_ context.Context, cmd *org.RemoveOrgUserCommand) error {
+	return nil
+}
+
+func uidOf(userID int64) string {
+	return map[int64]string{1: "u-admin", 2: "u-alice", 3: "u-bob"}[userID]
+}
+
+type testEnv struct {
+	svc         *Service
+	orgs        *fakeOrgService
+	users       *usertest.FakeUserService
+	teams       *teamtest.FakeService
+	permissions *fakeTeamPermissionsService
+	updates     []*user.UpdateUserCommand
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		orgs:        &fakeOrgService{orgs: map[int64][]*org.UserOrgDTO{}},
+		users:       usertest.NewUserServiceFake(),
+		teams:       teamtest.NewFakeService(),
+		permissions: &fakeTeamPermissionsService{},
+	}
+	env.users.UpdateFn = func(_ context.Context, cmd *user.UpdateUserCommand) error {
+		env.updates = append(env.updates, cmd)
+		return nil
+	}
+	env.svc = &Service{
+		cfg:                    setting.SCIMSettings{DefaultOrgRole: string(org.RoleViewer), MaxResults: 100},
+		appURL:                 "http://localhost:3000/",
+		log:                    log.NewNopLogger(),
+		accessControl:          acimpl.ProvideAccessControl(featuremgmt.WithFeatures()),
+		acService:              actest.FakeService{},
+		userService:            env.users,
+		orgService:             env.orgs,
+		teamService:            env.teams,
+		teamPermissionsService: env.permissions,
+		sessionService:         authtest.NewFakeUserAuthTokenService(),
+	}
+	return env
+}
+
+// requester returns a user of org 1 with the given role, the permissions to
+// manage the users and teams of the org, and extra action and scope pairs.
+func requester(role org.RoleType, extra ...string) *user.SignedInUser {
+	permissions := map[string][]string{
+		ac.ActionOrgUsersRead:          {ac.ScopeUsersAll},
+		ac.ActionOrgUsersAdd:           {ac.ScopeUsersAll},
+		ac.ActionOrgUsersWrite:         {ac.ScopeUsersAll},
+		ac.ActionOrgUsersRemove:        {ac.ScopeUsersAll},
+		ac.ActionTeamsRead:             {ac.ScopeTeamsAll},
+		ac.ActionTeamsCreate:           {},
+		ac.ActionTeamsWrite:            {ac.ScopeTeamsAll},
+		ac.ActionTeamsPermissionsWrite: {ac.ScopeTeamsAll},
+		ac.ActionTeamsDelete:           {ac.ScopeTeamsAll},
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		permissions[extra[i]] = append(permissions[extra[i]], extra[i+1])
+	}
+	return &user.SignedInUser{UserID: 100, OrgID: 1, OrgRole: role, Permissions: map[int64]map[string][]string{1: permissions}}
+}
+
+func scimRequest(t *testing.T, method, id string, body any, signedInUser *user.SignedInUser) *contextmodel.ReqContext {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, basePath, reader)
+	req.Header.Set("Content-Type", ContentType)
+	req = web.SetURLParams(req, map[string]string{":id": id})
+	return &contextmodel.ReqContext{
+		Context:      &web.Context{Req: req, Resp: web.NewResponseWriter(method, httptest.NewRecorder())},
+		SignedInUser: signedInUser,
+		Logger:       &logtest.Fake{},
+	}
+}
+
+func decodeResponse[T any](t *testing.T, resp response.Response) T {
+	t.Helper()
+	var v T
+	require.NoError(t, json.Unmarshal(resp.(*response.NormalResponse).Body(), &v))
+	return v
+}
+
+func TestCreateUser(t *testing.T) {
+	newUser := func(role string) User {
+		u := User{Schemas: []string{SchemaUser}, UserName: "alice", Emails: []MultiValued{{Value: "alice@example.com", Primary: true}}}
+		if role != "" {
+			u.Roles = []MultiValued{{Value: role, Primary: true}}
+		}
+		return u
+	}
+
+	t.Run("creates the user and adds them to the org", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.users.ExpectedUser = &user.User{ID: 2, UID: "u-alice", Login: "alice", Email: "alice@example.com"}
+
+		resp := env.svc.createUserHandler(scimRequest(t, http.MethodPost, "", newUser("Editor"), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusCreated, resp.Status())
+		created := decodeResponse[User](t, resp)
+		assert.Equal(t, "u-alice", created.ID)
+		assert.Equal(t, "Editor", created.PrimaryRole())
+		require.Len(t, env.orgs.added, 1)
+		assert.Equal(t, org.RoleEditor, env.orgs.added[0].Role)
+	})
+
+	t.Run("uses the default role", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.users.ExpectedUser = &user.User{ID: 2, UID: "u-alice", Login: "alice"}
+
+		resp := env.svc.createUserHandler(scimRequest(t, http.MethodPost, "", newUser(""), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusCreated, resp.Status())
+		assert.Equal(t, org.RoleViewer, env.orgs.added[0].Role)
+	})
+
+	t.Run("rejects a role higher than the requester's", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.users.ExpectedUser = &user.User{ID: 2, UID: "u-alice", Login: "alice"}
+
+		resp := env.svc.createUserHandler(scimRequest(t, http.MethodPost, "", newUser("Admin"), requester(org.RoleEditor)))
+
+		require.Equal(t, http.StatusForbidden, resp.Status())
+		assert.Empty(t, env.orgs.added)
+	})
+
+	t.Run("rejects an unknown role", func(t *testing.T) {
+		env := newTestEnv(t)
+
+		resp := env.svc.createUserHandler(scimRequest(t, http.MethodPost, "", newUser("Owner"), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("returns a conflict when the user exists", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.users.ExpectedError = user.ErrUserAlreadyExists
+
+		resp := env.svc.createUserHandler(scimRequest(t, http.MethodPost, "", newUser(""), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusConflict, resp.Status())
+	})
+
+	t.Run("deletes the user when adding them to the org fails", func(t *testing.T) {
+		env := newTestEnv(t)
+		env.users.ExpectedUser = &user.User{ID: 2, UID: "u-alice", Login: "alice"}
+		env.orgs.addErr = errors.New("database is locked")
+		var deleted []int64
+		env.users.DeleteFn = func(_ context.Context, cmd *user.DeleteUserCommand) error {
+			deleted = append(deleted, cmd.UserID)
+			return nil
+		}
+
+		resp := env.svc.createUserHandler(scimRequest(t, http.MethodPost, "", newUser(""), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusInternalServerError, resp.Status())
+		assert.Equal(t, []int64{2}, deleted)
+	})
+
+	t.Run("requires the permission to create users", func(t *testing.T) {
+		env := newTestEnv(t)
+		ctx := context.Background()
+
+		require.Error(t, env.svc.authorize(ctx, requester(org.RoleAdmin), evalUsersCreate))
+		require.NoError(t, env.svc.authorize(ctx, requester(org.RoleAdmin, ac.ActionUsersCreate, ""), evalUsersCreate))
+	})
+}
+
+func TestReplaceUser(t *testing.T) {
+	alice := func() *user.User {
+		return &user.User{ID: 2, UID: "u-alice", Login: "alice", Email: "alice@example.com", Name: "Alice"}
+	}
+	replacement := func(login, email, role string, active bool) User {
+		u := User{Schemas: []string{SchemaUser}, UserName: login, DisplayName: "Alice", Active: &active}
+		if email != "" {
+			u.Emails = []MultiValued{{Value: email, Primary: true}}
+		}
+		if role != "" {
+			u.Roles = []MultiValued{{Value: role, Primary: true}}
+		}
+		return u
+	}
+	newEnv := func(t *testing.T, orgIDs ...int64) *testEnv {
+		env := newTestEnv(t)
+		env.users.ExpectedUser = alice()
+		for _, id := range orgIDs {
+			env.orgs.orgs[2] = append(env.orgs.orgs[2], &org.UserOrgDTO{OrgID: id, Role: org.RoleViewer})
+		}
+		return env
+	}
+
+	t.Run("updates the user of a single org", func(t *testing.T) {
+		env := newEnv(t, 1)
+
+		resp := env.svc.replaceUserHandler(scimRequest(t, http.MethodPut, "u-alice", replacement("alice2", "alice2@example.com", "Editor", true), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Len(t, env.updates, 1)
+		assert.Equal(t, "alice2", env.updates[0].Login)
+		assert.Equal(t, "alice2@example.com", env.updates[0].Email)
+		assert.Nil(t, env.updates[0].IsDisabled)
+		require.Len(t, env.orgs.updated, 1)
+		assert.Equal(t, org.RoleEditor, env.orgs.updated[0].Role)
+	})
+
+	t.Run("returns not found for users of other orgs", func(t *testing.T) {
+		env := newEnv(t, 2)
+
+		resp := env.svc.replaceUserHandler(scimRequest(t, http.MethodPut, "u-alice", replacement("alice", "", "", true), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+
+	t.Run("rejects a role higher than the requester's", func(t *testing.T) {
+		env := newEnv(t, 1)
+
+		resp := env.svc.replaceUserHandler(scimRequest(t, http.MethodPut, "u-alice", replacement("alice", "", "Admin", true), requester(org.RoleEditor)))
+
+		require.Equal(t, http.StatusForbidden, resp.Status())
+		assert.Empty(t, env.updates)
+		assert.Empty(t, env.orgs.updated)
+	})
+
+	t.Run("changes the role of users of several orgs", func(t *testing.T) {
+		env := newEnv(t, 1, 2)
+
+		resp := env.svc.replaceUserHandler(scimRequest(t, http.MethodPut, "u-alice", replacement("alice", "", "Editor", true), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Len(t, env.orgs.updated, 1)
+	})
+
+	t.Run("keeps disabled users disabled when active is not set", func(t *testing.T) {
+		env := newEnv(t, 1)
+		env.users.ExpectedUser.IsDisabled = true
+		body := replacement("alice", "", "", true)
+		body.Active = nil
+
+		resp := env.svc.replaceUserHandler(scimRequest(t, http.MethodPut, "u-alice", body, requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Len(t, env.updates, 1)
+		assert.Nil(t, env.updates[0].IsDisabled)
+	})
+
+	t.Run("changes of global attributes", func(t *testing.T) {
+		testCases := []struct {
+			desc   string
+			body   User
+			action string
+		}{
+			{desc: "login", body: replacement("mallory", "", "", true), action: ac.ActionUsersWrite},
+			{desc: "email", body: replacement("alice", "mallory@example.com", "", true), action: ac.ActionUsersWrite},
+			{desc: "active", body: replacement("alice", "", "", false), action: ac.ActionUsersDisable},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.desc+" are rejected for users of several orgs", func(t *testing.T) {
+				env := newEnv(t, 1, 2)
+
+				resp := env.svc.replaceUserHandler(scimRequest(t, http.MethodPut, "u-alice", tc.body, requester(org.RoleAdmin)))
+
+				require.Equal(t, http.StatusForbidden, resp.Status())
+				assert.Empty(t, env.updates)
+			})
+
+			t.Run(tc.desc+" are rejected for server admins", func(t *testing.T) {
+				env := newEnv(t, 1)
+				env.users.ExpectedUser.IsAdmin = true
+
+				resp := env.svc.replaceUserHandler(scimRequest(t, http.MethodPut, "u-alice", tc.body, requester(org.RoleAdmin)))
+
+				require.Equal(t, http.StatusForbidden, resp.Status())
+				assert.Empty(t, env.updates)
+			})
+
+			t.Run(tc.desc+" are allowed with the permission on the user", func(t *testing.T) {
+				env := newEnv(t, 1, 2)
+
+				resp := env.svc.replaceUserHandler(scimRequest(t, http.MethodPut, "u-alice", tc.body, requester(org.RoleAdmin, tc.action, "global.users:id:2")))
+
+				require.Equal(t, http.StatusOK, resp.Status())
+				assert.Len(t, env.updates, 1)
+			})
+		}
+	})
+}
+
+func TestPatchUser(t *testing.T) {
+	newEnv := func(t *testing.T, orgIDs ...int64) *testEnv {
+		env := newTestEnv(t)
+		env.users.ExpectedUser = &user.User{ID: 2, UID: "u-alice", Login: "alice", Email: "alice@example.com", Name: "Alice"}
+		for _, id := range orgIDs {
+			env.orgs.orgs[2] = append(env.orgs.orgs[2], &org.UserOrgDTO{OrgID: id, Role: org.RoleViewer})
+		}
+		return env
+	}
+	patch := func(ops ...PatchOperation) PatchRequest {
+		return PatchRequest{Schemas: []string{SchemaPatchOp}, Operations: ops}
+	}
+
+	t.Run("deactivates the user", func(t *testing.T) {
+		env := newEnv(t, 1)
+
+		resp := env.svc.patchUserHandler(scimRequest(t, http.MethodPatch, "u-alice", patch(PatchOperation{Op: "replace", Path: "active", Value: json.RawMessage(`"False"`)}), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Len(t, env.updates, 1)
+		require.NotNil(t, env.updates[0].IsDisabled)
+		assert.True(t, *env.updates[0].IsDisabled)
+		assert.Equal(t, "alice", env.updates[0].Login)
+	})
+
+	t.Run("rejects email changes of users of several orgs", func(t *testing.T) {
+		env := newEnv(t, 1, 2)
+
+		resp := env.svc.patchUserHandler(scimRequest(t, http.MethodPatch, "u-alice", patch(PatchOperation{Op: "replace", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"mallory@example.com"`)}), requester(org.RoleAdmin)))
+
+		require.Equal(t, http.StatusForbidden, resp.Status())
+		assert.Empty(t, env.updates)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	env := newTestEnv(t)
+	env.users.ExpectedUser = &user.User{ID: 2, UID: "u-alice", Login: "alice"}
+	env.orgs.orgs[2] = []*org.UserOrgDTO{{OrgID: 1, Role: org.RoleViewer}}
+	env.teams.ExpectedMembers = []*team.TeamMemberDTO{{TeamID: 5, UserID: 2}}
+
+	resp := env.svc.deleteUserHandler(scimRequest(t, http.MethodDelete, "u-alice", nil, requester(org.RoleAdmin)))
+
+	require.Equal(t, http.StatusNoContent, resp.Status())
+	assert.Equal(t, []membershipChange{{teamID: "5", userID: 2, permission: ""}}, env.permissions.changes)
+}
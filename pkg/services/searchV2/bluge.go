@@ -11,6 +11,7 @@ import (
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/search"
 	"github.com/blugelabs/bluge/search/aggregations"
+	"github.com/blugelabs/bluge/search/highlight"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/slugify"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/store/entity"
+	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
 )
 
 const (
@@ -33,10 +35,22 @@ const (
 	documentFieldTransformer = "transformer"
 	documentFieldDSUID       = "ds_uid"
 	documentFieldDSType      = "ds_type"
+	documentFieldDescription = "description"
+	documentFieldQuery       = "query"    // panel queries
+	documentFieldContent     = "content"  // text panel content
+	documentFieldVariable    = "variable" // template variable queries
 	DocumentFieldCreatedAt   = "created_at"
 	DocumentFieldUpdatedAt   = "updated_at"
 )
 
+// searchTermFields maps the fields of `field:value` search terms to the document fields.
+var searchTermFields = map[string]string{
+	kdash.SearchFieldQuery:       documentFieldQuery,
+	kdash.SearchFieldDescription: documentFieldDescription,
+	kdash.SearchFieldContent:     documentFieldContent,
+	kdash.SearchFieldVariable:    documentFieldVariable,
+}
+
 func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
 	dashboardWriter, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
 	if err != nil {
@@ -160,6 +174,10 @@ func getNonFolderDashboardDoc(dash dashboard, location string) *bluge.Document {
 		AddField(bluge.NewDateTimeField(DocumentFieldCreatedAt, dash.created).Sortable().StoreValue()).
 		AddField(bluge.NewDateTimeField(DocumentFieldUpdatedAt, dash.updated).Sortable().StoreValue())
 
+	if v := dash.summary.Fields[kdash.SummaryFieldTemplateQuery]; v != "" {
+		doc.AddField(newFullTextField(documentFieldVariable, v))
+	}
+
 	// dashboards only use the key part of labels
 	for k := range dash.summary.Labels {
 		doc.AddField(bluge.NewKeywordField(documentFieldTag, k).
@@ -209,6 +227,13 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 			AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
 			AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindPanel)).Aggregatable().StoreValue()) // likely want independent index for this
 
+		if v := panel.Fields[kdash.SummaryFieldQueries]; v != "" {
+			doc.AddField(newFullTextField(documentFieldQuery, v))
+		}
+		if v := panel.Fields[kdash.SummaryFieldContent]; v != "" {
+			doc.AddField(newFullTextField(documentFieldContent, v))
+		}
+
 		for _, ref := range panel.References {
 			switch ref.Family {
 			case entity.StandardKindDashboard:
@@ -254,12 +279,20 @@ func newSearchDocument(uid, name, descr, url string) *bluge.Document {
 			doc.AddField(bluge.NewKeywordField(documentFieldName_sort, sortStr).Sortable())
 		}
 	}
+	if descr != "" {
+		doc.AddField(newFullTextField(documentFieldDescription, descr))
+	}
 	if url != "" {
 		doc.AddField(bluge.NewKeywordField(documentFieldURL, url).StoreValue())
 	}
 	return doc
 }
 
+// newFullTextField returns a field searchable with `field:value` terms, the value is stored to highlight the matches.
+func newFullTextField(name, value string) *bluge.TermField {
+	return bluge.NewTextField(name, value).StoreValue().SearchTermPositions()
+}
+
 func getDashboardPanelIDs(index *orgIndex, panelLocation string) ([]string, error) {
 	var panelIDs []string
 
@@ -392,6 +425,21 @@ func doSearchQuery(
 	fullQuery := bluge.NewBooleanQuery()
 	fullQuery.AddMust(newPermissionFilter(filter, logger))
 
+	// Terms like `query:http_requests_total` match the panel queries, descriptions, etc. The remaining
+	// text matches the names.
+	text, terms := kdash.ParseSearchQuery(q.Query)
+	q.Query = text
+	for _, term := range terms {
+		field := searchTermFields[term.Field]
+		if term.Phrase {
+			fullQuery.AddMust(bluge.NewMatchPhraseQuery(term.Value).SetField(field))
+		} else {
+			fullQuery.AddMust(bluge.NewMatchQuery(term.Value).SetField(field).SetOperator(bluge.MatchQueryOperatorAnd))
+		}
+		hasConstraints = true
+	}
+	highlightMatches := len(terms) > 0
+
 	// Only show dashboard / folders / panels.
 	if len(q.Kind) > 0 {
 		bq := bluge.NewBooleanQuery()
@@ -483,6 +531,9 @@ func doSearchQuery(
 	if q.Explain {
 		req.ExplainScores()
 	}
+	if highlightMatches {
+		req.IncludeLocations()
+	}
 	req.WithStandardAggregations()
 
 	if q.Sort != "" {
@@ -516,6 +567,7 @@ func doSearchQuery(
 	fTags := data.NewFieldFromFieldType(data.FieldTypeNullableJSON, 0)
 	fDSUIDs := data.NewFieldFromFieldType(data.FieldTypeJSON, 0)
 	fExplain := data.NewFieldFromFieldType(data.FieldTypeNullableJSON, 0)
+	fHighlight := data.NewFieldFromFieldType(data.FieldTypeNullableJSON, 0)
 
 	fScore.Name = "score"
 	fUID.Name = "uid"
@@ -532,11 +584,16 @@ func doSearchQuery(
 	fDSUIDs.Name = "ds_uid"
 	fTags.Name = "tags"
 	fExplain.Name = "explain"
+	fHighlight.Name = "highlight"
 
 	frame := data.NewFrame("Query results", fKind, fUID, fName, fPType, fURL, fTags, fDSUIDs, fLocation)
 	if q.Explain {
 		frame.Fields = append(frame.Fields, fScore, fExplain)
 	}
+	if highlightMatches {
+		frame.Fields = append(frame.Fields, fHighlight)
+	}
+	highlighter := highlight.NewHTMLHighlighter()
 	frame.SetMeta(&data.FrameMeta{
 		Type:   "search-results",
 		Custom: header,
@@ -558,6 +615,7 @@ func doSearchQuery(
 		loc := ""
 		var dsUIDs []string
 		var tags []string
+		fullText := map[string][]byte{}
 
 		err = match.VisitStoredFields(func(field string, value []byte) bool {
 			switch field {
@@ -577,6 +635,10 @@ func doSearchQuery(
 				dsUIDs = append(dsUIDs, string(value))
 			case documentFieldTag:
 				tags = append(tags, string(value))
+			case documentFieldDescription, documentFieldQuery, documentFieldContent, documentFieldVariable:
+				if highlightMatches {
+					fullText[field] = append([]byte{}, value...)
+				}
 			default:
 				ext(field, value)
 			}
@@ -633,6 +695,19 @@ func doSearchQuery(
 			}
 		}
 
+		if highlightMatches {
+			// the best fragments of each matching field, the matched terms are in <mark> tags
+			fragments := map[string][]string{}
+			for field, locations := range match.Locations {
+				if orig, ok := fullText[field]; ok {
+					fragments[field] = highlighter.BestFragments(locations, orig, 3)
+				}
+			}
+			js, _ := json.Marshal(fragments)
+			jsb := json.RawMessage(js)
+			fHighlight.Append(&jsb)
+		}
+
 		// extend fields to match the longest field
 		fieldLen++
 		for _, f := range frame.Fields {
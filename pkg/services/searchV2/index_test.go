@@ -2,6 +2,7 @@ package searchV2
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/store/entity"
+	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
 	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -559,6 +561,62 @@ func TestDashboardIndex_Panels(t *testing.T) {
 	})
 }
 
+func TestDashboardIndex_FullText(t *testing.T) {
+	builder := kdash.NewStaticDashboardSummaryBuilder(kdash.CreateDatasourceLookup(nil), false)
+	newDashboard := func(id int64, uid, body string) dashboard {
+		summary, _, err := builder(context.Background(), uid, []byte(body))
+		require.NoError(t, err)
+		return dashboard{id: id, uid: uid, summary: summary}
+	}
+	index := initTestOrgIndexFromDashes(t, []dashboard{
+		newDashboard(1, "checkout", `{
+			"title": "Checkout",
+			"templating": {"list": [{"name": "job", "type": "query", "definition": "label_values(http_requests_total, job)"}]},
+			"panels": [
+				{"id": 1, "title": "Requests", "targets": [{"refId": "A", "expr": "sum(rate(http_requests_total{job=\"$job\"}[5m]))"}]},
+				{"id": 2, "title": "Help", "type": "text", "options": {"content": "Runbook for the on call team"}}
+			]
+		}`),
+		newDashboard(2, "payments", `{
+			"title": "Payments",
+			"panels": [
+				{"id": 3, "title": "Latency", "description": "Latency of the payments", "targets": [{"refId": "A", "rawSql": "SELECT latency FROM payments"}]}
+			]
+		}`),
+	})
+
+	search := func(query string) (uids []string, highlights []string) {
+		resp := doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter, DashboardQuery{Query: query}, &NoopQueryExtender{}, "")
+		require.NoError(t, resp.Error)
+		frame := resp.Frames[0]
+		uidField, _ := frame.FieldByName("uid")
+		highlightField, _ := frame.FieldByName("highlight")
+		require.NotNil(t, highlightField)
+		for i := 0; i < frame.Rows(); i++ {
+			uids = append(uids, uidField.At(i).(string))
+			highlights = append(highlights, string(*highlightField.At(i).(*json.RawMessage)))
+		}
+		return uids, highlights
+	}
+
+	uids, highlights := search("query:http_requests_total")
+	require.Equal(t, []string{"checkout#1"}, uids)
+	require.JSONEq(t, `{"query": ["sum(rate(<mark>http_requests_total</mark>{job=&#34;$job&#34;}[5m]))"]}`, highlights[0])
+
+	uids, highlights = search("variable:http_requests_total")
+	require.Equal(t, []string{"checkout"}, uids)
+	require.JSONEq(t, `{"variable": ["label_values(<mark>http_requests_total</mark>, job)"]}`, highlights[0])
+
+	uids, _ = search(`content:"on call"`)
+	require.Equal(t, []string{"checkout#2"}, uids)
+
+	uids, _ = search("query:payments description:latency")
+	require.Equal(t, []string{"payments#3"}, uids)
+
+	uids, _ = search("Help query:payments")
+	require.Empty(t, uids)
+}
+
 var punctuationSplitNgramDashboards = []dashboard{
 	{
 		id:  1,
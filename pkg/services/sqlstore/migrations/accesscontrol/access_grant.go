@@ -0,0 +1,46 @@
+package accesscontrol
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func AddAccessGrantMigrations(mg *migrator.Migrator) {
+	accessGrantV1 := migrator.Table{
+		Name: "access_grant",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "user_id", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "team_id", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "builtin_role", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "kind", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "role", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "resource", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "resource_id", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "permission", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "previous", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "reason", Type: migrator.DB_Text, Nullable: true},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "requested_by", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "reviewed_by", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "revoked_by", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "expires_at", Type: migrator.DB_DateTime, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "status"}},
+			{Cols: []string{"status", "expires_at"}},
+		},
+	}
+
+	mg.AddMigration("create access_grant table", migrator.NewAddTableMigration(accessGrantV1))
+	mg.AddMigration("add unique index access_grant.org_id_uid", migrator.NewAddIndexMigration(accessGrantV1, accessGrantV1.Indices[0]))
+	mg.AddMigration("add index access_grant.org_id_status", migrator.NewAddIndexMigration(accessGrantV1, accessGrantV1.Indices[1]))
+	mg.AddMigration("add index access_grant.status_expires_at", migrator.NewAddIndexMigration(accessGrantV1, accessGrantV1.Indices[2]))
+
+	mg.AddMigration("add role_uid column to access_grant", migrator.NewAddColumnMigration(accessGrantV1, &migrator.Column{
+		Name: "role_uid", Type: migrator.DB_NVarchar, Length: 40, Default: "''",
+	}))
+}
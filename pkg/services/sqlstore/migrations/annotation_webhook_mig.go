@@ -0,0 +1,34 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addAnnotationWebhookMigrations(mg *Migrator) {
+	webhookV1 := Table{
+		Name: "annotation_webhook",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "panel_id", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "mapping", Type: DB_Text, Nullable: false},
+			{Name: "token_hash", Type: DB_Char, Length: 64, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "name"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create annotation_webhook table", NewAddTableMigration(webhookV1))
+	mg.AddMigration("add unique index annotation_webhook.uid", NewAddIndexMigration(webhookV1, webhookV1.Indices[0]))
+	mg.AddMigration("add unique index annotation_webhook.org_id_name", NewAddIndexMigration(webhookV1, webhookV1.Indices[1]))
+
+	mg.AddMigration("add encrypted_token column to annotation_webhook", NewAddColumnMigration(webhookV1, &Column{
+		Name: "encrypted_token", Type: DB_Text, Nullable: true,
+	}))
+}
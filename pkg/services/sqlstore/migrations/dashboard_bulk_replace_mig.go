@@ -0,0 +1,44 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardBulkReplaceMigrations(mg *Migrator) {
+	batchV1 := Table{
+		Name: "dashboard_bulk_replace",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "message", Type: DB_NVarchar, Length: 500, Nullable: false},
+			{Name: "query", Type: DB_Text, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "rolled_back", Type: DB_DateTime, Nullable: true},
+			{Name: "rolled_back_by", Type: DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+		},
+	}
+
+	itemV1 := Table{
+		Name: "dashboard_bulk_replace_item",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "batch_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "version_before", Type: DB_Int, Nullable: false},
+			{Name: "version_after", Type: DB_Int, Nullable: false},
+			{Name: "changes", Type: DB_Int, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"batch_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_bulk_replace table", NewAddTableMigration(batchV1))
+	mg.AddMigration("add unique index dashboard_bulk_replace.org_id_uid", NewAddIndexMigration(batchV1, batchV1.Indices[0]))
+	mg.AddMigration("create dashboard_bulk_replace_item table", NewAddTableMigration(itemV1))
+	mg.AddMigration("add index dashboard_bulk_replace_item.batch_id", NewAddIndexMigration(itemV1, itemV1.Indices[0]))
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+func addDashboardReportMigrations(mg *Migrator) {
+	reportV1 := Table{
+		Name: "dashboard_report",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "schedule", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "timezone", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "reply_to", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "message", Type: DB_Text, Nullable: false},
+			{Name: "options", Type: DB_Text, Nullable: false},
+			{Name: "enabled", Type: DB_Bool, Nullable: false},
+			{Name: "owner_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "next_run", Type: DB_BigInt, Nullable: false},
+			{Name: "last_run", Type: DB_DateTime, Nullable: true},
+			{Name: "last_error", Type: DB_Text, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"enabled", "next_run"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_report table", NewAddTableMigration(reportV1))
+	mg.AddMigration("add unique index dashboard_report.org_id_uid", NewAddIndexMigration(reportV1, reportV1.Indices[0]))
+	mg.AddMigration("add index dashboard_report.enabled_next_run", NewAddIndexMigration(reportV1, reportV1.Indices[1]))
+}
@@ -57,3 +57,32 @@ FROM dashboard;`
 	mg.AddMigration("alter dashboard_version.data to mediumtext v1", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_version MODIFY data MEDIUMTEXT;"))
 }
+
+func addDashboardVersionRetentionMigrations(mg *Migrator) {
+	mg.AddMigration("add column name to dashboard_version", NewAddColumnMigration(Table{Name: "dashboard_version"}, &Column{
+		Name: "name", Type: DB_NVarchar, Length: 190, Nullable: true,
+	}))
+	mg.AddMigration("add column tags to dashboard_version", NewAddColumnMigration(Table{Name: "dashboard_version"}, &Column{
+		Name: "tags", Type: DB_Text, Nullable: true,
+	}))
+
+	retentionV1 := Table{
+		Name: "dashboard_version_retention",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "keep_versions", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "keep_days", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "keep_tags", Type: DB_Text, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "folder_uid"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard_version_retention table", NewAddTableMigration(retentionV1))
+	mg.AddMigration("add unique index dashboard_version_retention.org_id_folder_uid", NewAddIndexMigration(retentionV1, retentionV1.Indices[0]))
+}
@@ -145,4 +145,22 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	ualert.AddAlertRuleUpdatedByMigration(mg)
 
 	ualert.AddAlertRuleStateTable(mg)
+
+	addUserMFAMigrations(mg)
+
+	accesscontrol.AddAccessGrantMigrations(mg)
+
+	addAnnotationWebhookMigrations(mg)
+
+	addDashboardVersionRetentionMigrations(mg)
+
+	addDashboardBulkReplaceMigrations(mg)
+
+	addDashboardReportMigrations(mg)
+
+	ualert.AddRuleDependenciesColumns(mg)
+
+	ualert.AddSilenceTemplateTable(mg)
+
+	ualert.AddNotificationDeliveryTable(mg)
 }
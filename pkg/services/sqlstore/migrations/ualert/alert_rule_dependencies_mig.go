@@ -0,0 +1,18 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleDependenciesColumns adds columns to store the rules of the same group that a rule is evaluated after.
+func AddRuleDependenciesColumns(mg *migrator.Migrator) {
+	mg.AddMigration("add dependencies column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "dependencies",
+		Type:     migrator.DB_Text, // Text, as this contains a JSON-ified list.
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add dependencies column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "dependencies",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
@@ -0,0 +1,39 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddNotificationDeliveryTable adds a table to store the log of notification attempts of receiver integrations.
+func AddNotificationDeliveryTable(mg *migrator.Migrator) {
+	notificationDeliveryTable := migrator.Table{
+		Name: "alert_notification_delivery",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false}, // Unix time in milliseconds.
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "integration_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "integration_type", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "group_labels", Type: migrator.DB_Text, Nullable: false}, // Text, as this contains a JSON-ified map.
+			{Name: "alerts", Type: migrator.DB_MediumText, Nullable: false}, // JSON-ified list of the notified alerts.
+			{Name: "alert_fingerprints", Type: migrator.DB_Text, Nullable: false},
+			{Name: "payload_digest", Type: migrator.DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "status_code", Type: migrator.DB_Int, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "retry", Type: migrator.DB_Int, Nullable: false},
+			{Name: "duration_ms", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "resent_from", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "created"}},
+			{Cols: []string{"org_id", "receiver", "created"}},
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("add alert_notification_delivery table", migrator.NewAddTableMigration(notificationDeliveryTable))
+	mg.AddMigration("add index to alert_notification_delivery on org_id and created columns", migrator.NewAddIndexMigration(notificationDeliveryTable, notificationDeliveryTable.Indices[0]))
+	mg.AddMigration("add index to alert_notification_delivery on org_id, receiver and created columns", migrator.NewAddIndexMigration(notificationDeliveryTable, notificationDeliveryTable.Indices[1]))
+	mg.AddMigration("add index to alert_notification_delivery on created column", migrator.NewAddIndexMigration(notificationDeliveryTable, notificationDeliveryTable.Indices[2]))
+}
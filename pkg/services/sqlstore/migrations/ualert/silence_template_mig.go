@@ -0,0 +1,30 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddSilenceTemplateTable adds a table to store silence templates and recurring silences.
+func AddSilenceTemplateTable(mg *migrator.Migrator) {
+	silenceTemplateTable := migrator.Table{
+		Name: "alert_silence_template",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "matchers", Type: migrator.DB_Text, Nullable: false}, // Text, as this contains a JSON-ified list.
+			{Name: "comment", Type: migrator.DB_Text, Nullable: true},
+			{Name: "duration_seconds", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "schedule", Type: migrator.DB_Text, Nullable: true},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "materialized_until", Type: migrator.DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "title"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("add alert_silence_template table", migrator.NewAddTableMigration(silenceTemplateTable))
+	mg.AddMigration("add unique index to alert_silence_template on org_id and uid columns", migrator.NewAddIndexMigration(silenceTemplateTable, silenceTemplateTable.Indices[0]))
+	mg.AddMigration("add unique index to alert_silence_template on org_id and title columns", migrator.NewAddIndexMigration(silenceTemplateTable, silenceTemplateTable.Indices[1]))
+}
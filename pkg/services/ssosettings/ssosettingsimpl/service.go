@@ -66,7 +66,7 @@ func ProvideService(cfg *setting.Cfg, sqlStore db.DB, ac ac.AccessControl,
 		configurableProviders[social.LDAPProviderName] = true
 	}
 
-	if licensing.FeatureEnabled(social.SAMLProviderName) {
+	if licensing.FeatureEnabled(social.SAMLProviderName) || features.IsEnabledGlobally(featuremgmt.FlagSamlAuthnClient) {
 		fbStrategies = append(fbStrategies, strategies.NewSAMLStrategy(settingsProvider))
 
 		if features.IsEnabledGlobally(featuremgmt.FlagSsoSettingsSAML) {
@@ -42,6 +42,7 @@ func (s *LDAPStrategy) GetProviderConfig(_ context.Context, _ string) (map[strin
 		"skip_org_role_sync":  section.Key("skip_org_role_sync").MustBool(false),
 		"sync_cron":           section.Key("sync_cron").Value(),
 		"active_sync_enabled": section.Key("active_sync_enabled").MustBool(false),
+		"sync_page_size":      section.Key("sync_page_size").MustInt(500),
 	}
 
 	return result, nil
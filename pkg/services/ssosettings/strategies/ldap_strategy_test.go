@@ -68,16 +68,19 @@ var (
 					"group_search_filter":                "",
 					"group_search_filter_user_attribute": "",
 					"min_tls_version":                    "",
+					"nested_groups":                      false,
 					"root_ca_cert":                       "",
 					"root_ca_cert_value":                 nil,
 					"start_tls":                          false,
 					"use_ssl":                            false,
 					"tls_ciphers":                        nil,
+					"team_mappings":                      nil,
 				},
 			},
 		},
 		"active_sync_enabled": true,
 		"sync_cron":           "0 1 * * *",
+		"sync_page_size":      500,
 	}
 )
 
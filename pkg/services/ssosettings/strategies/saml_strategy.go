@@ -58,6 +58,7 @@ func (s *SAMLStrategy) loadSAMLSettings() map[string]any {
 		"assertion_attribute_org":    section.KeyValue("assertion_attribute_org").MustString(""),
 		"allowed_organizations":      section.KeyValue("allowed_organizations").MustString(""),
 		"org_mapping":                section.KeyValue("org_mapping").MustString(""),
+		"team_mapping":               section.KeyValue("team_mapping").MustString(""),
 		"role_values_none":           section.KeyValue("role_values_none").MustString(""),
 		"role_values_viewer":         section.KeyValue("role_values_viewer").MustString(""),
 		"role_values_editor":         section.KeyValue("role_values_editor").MustString(""),
@@ -40,6 +40,7 @@ var (
 	assertion_attribute_org = orgs
 	allowed_organizations = org1 org2
 	org_mapping = org1:1:editor, *:2:viewer
+	team_mapping = admins:Platform
 	role_values_viewer = viewer
 	role_values_editor = editor
 	role_values_admin = admin
@@ -80,6 +81,7 @@ var (
 		"assertion_attribute_org":    "orgs",
 		"allowed_organizations":      "org1 org2",
 		"org_mapping":                "org1:1:editor, *:2:viewer",
+		"team_mapping":               "admins:Platform",
 		"role_values_viewer":         "viewer",
 		"role_values_editor":         "editor",
 		"role_values_admin":          "admin",
@@ -18,9 +18,26 @@ type templateVariable struct {
 	}
 	name         string
 	query        any
+	definition   string
 	variableType string
 }
 
+// queryText returns the text of the query defining the variable.
+func (v templateVariable) queryText() string {
+	if v.definition != "" {
+		return v.definition
+	}
+	switch q := v.query.(type) {
+	case string:
+		return q
+	case map[string]any:
+		if text, ok := q["query"].(string); ok {
+			return text
+		}
+	}
+	return ""
+}
+
 type datasourceVariableLookup struct {
 	variableNameToRefs map[string][]DataSourceRef
 	dsLookup           DatasourceLookup
@@ -238,6 +255,12 @@ func readDashboardIter(iter *jsoniter.Iterator, lookup DatasourceLookup) (*Dashb
 								templateVariable.variableType = iter.ReadString()
 							case "query":
 								templateVariable.query = iter.Read()
+							case "definition":
+								if iter.WhatIsNext() == jsoniter.StringValue {
+									templateVariable.definition = iter.ReadString()
+								} else {
+									iter.Skip()
+								}
 							case "current":
 								for c := iter.ReadObject(); c != ""; c = iter.ReadObject() {
 									if c == "value" {
@@ -253,6 +276,8 @@ func readDashboardIter(iter *jsoniter.Iterator, lookup DatasourceLookup) (*Dashb
 
 						if templateVariable.variableType == "datasource" {
 							datasourceVariablesLookup.add(templateVariable)
+						} else if text := templateVariable.queryText(); text != "" {
+							dash.TemplateQuery = append(dash.TemplateQuery, text)
 						}
 					}
 				} else {
@@ -411,6 +436,13 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) PanelSummar
 		case "pluginVersion":
 			panel.PluginVersion = iter.ReadString() // since 7x (the saved version for the plugin model)
 
+		case "content": // text panels before 7x
+			if iter.WhatIsNext() == jsoniter.StringValue {
+				panel.Content = iter.ReadString()
+			} else {
+				iter.Skip()
+			}
+
 		case "libraryPanel":
 			var v map[string]interface{}
 			iter.ReadVal(&v)
@@ -455,7 +487,18 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) PanelSummar
 			}
 
 		case "options":
-			fallthrough
+			if iter.WhatIsNext() != jsoniter.ObjectValue {
+				iter.Skip()
+				continue
+			}
+			for sub := iter.ReadObject(); sub != ""; sub = iter.ReadObject() {
+				// the markdown or HTML of text panels
+				if sub == "content" && iter.WhatIsNext() == jsoniter.StringValue {
+					panel.Content = iter.ReadString()
+				} else {
+					iter.Skip()
+				}
+			}
 
 		case "gridPos":
 			fallthrough
@@ -470,6 +513,7 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) PanelSummar
 	}
 
 	panel.Datasource = targets.GetDatasourceInfo()
+	panel.Queries = targets.queries
 
 	return panel
 }
@@ -0,0 +1,73 @@
+package dashboard
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fields of the dashboards and panels that can be searched with `field:value` terms.
+const (
+	SearchFieldQuery       = "query"       // text of the panel queries
+	SearchFieldDescription = "description" // description of the dashboards and panels
+	SearchFieldContent     = "content"     // content of text panels
+	SearchFieldVariable    = "variable"    // queries defining the template variables
+)
+
+var searchFields = map[string]bool{
+	SearchFieldQuery:       true,
+	SearchFieldDescription: true,
+	SearchFieldContent:     true,
+	SearchFieldVariable:    true,
+}
+
+// SearchTerm is a term of a search query restricted to a field, written `field:value` or `field:"some phrase"`.
+type SearchTerm struct {
+	Field  string
+	Value  string
+	Phrase bool
+}
+
+// ParseSearchQuery splits a search query into the terms restricted to a field and the remaining text.
+// Prefixes that aren't a searchable field are kept in the text.
+func ParseSearchQuery(q string) (string, []SearchTerm) {
+	var terms []SearchTerm
+	text := make([]string, 0)
+
+	for q = strings.TrimSpace(q); q != ""; q = strings.TrimSpace(q) {
+		token, rest := nextToken(q)
+		field, value, ok := strings.Cut(token, ":")
+		if !ok || !searchFields[field] || value == "" {
+			text = append(text, token)
+			q = rest
+			continue
+		}
+
+		if value[0] != '"' {
+			terms = append(terms, SearchTerm{Field: field, Value: value})
+			q = rest
+			continue
+		}
+
+		// the phrase can contain spaces, it ends at the next quote
+		phrase := q[len(field)+2:]
+		end := strings.IndexByte(phrase, '"')
+		if end < 0 {
+			end = len(phrase)
+			q = ""
+		} else {
+			q = phrase[end+1:]
+		}
+		if phrase = strings.TrimSpace(phrase[:end]); phrase != "" {
+			terms = append(terms, SearchTerm{Field: field, Value: phrase, Phrase: true})
+		}
+	}
+	return strings.Join(text, " "), terms
+}
+
+func nextToken(q string) (string, string) {
+	end := strings.IndexFunc(q, unicode.IsSpace)
+	if end < 0 {
+		return q, ""
+	}
+	return q[:end], q[end:]
+}
@@ -0,0 +1,49 @@
+package dashboard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	testCases := []struct {
+		query string
+		text  string
+		terms []SearchTerm
+	}{
+		{query: "", text: ""},
+		{query: "cpu usage", text: "cpu usage"},
+		{
+			query: "query:http_requests_total",
+			text:  "",
+			terms: []SearchTerm{{Field: SearchFieldQuery, Value: "http_requests_total"}},
+		},
+		{
+			query: "checkout query:http_requests_total  description:latency",
+			text:  "checkout",
+			terms: []SearchTerm{
+				{Field: SearchFieldQuery, Value: "http_requests_total"},
+				{Field: SearchFieldDescription, Value: "latency"},
+			},
+		},
+		{
+			query: `content:"on call" runbook`,
+			text:  "runbook",
+			terms: []SearchTerm{{Field: SearchFieldContent, Value: "on call", Phrase: true}},
+		},
+		{
+			query: `variable:"label_values(up, job)`,
+			text:  "",
+			terms: []SearchTerm{{Field: SearchFieldVariable, Value: "label_values(up, job)", Phrase: true}},
+		},
+		{query: "unknown:value query:", text: "unknown:value query:"},
+		{query: `query:""`, text: ""},
+	}
+
+	for _, tc := range testCases {
+		text, terms := ParseSearchQuery(tc.query)
+		assert.Equal(t, tc.text, text, tc.query)
+		assert.Equal(t, tc.terms, terms, tc.query)
+	}
+}
@@ -6,11 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/store/entity"
 )
 
+// Summary fields holding the text indexed for full-text search, multiple values are separated by new lines.
+const (
+	SummaryFieldQueries       = "queries"
+	SummaryFieldContent       = "content"
+	SummaryFieldTemplateQuery = "templateQuery"
+)
+
 // This summary does not resolve old name as UID
 func GetEntitySummaryBuilder() entity.EntitySummaryBuilder {
 	builder := NewStaticDashboardSummaryBuilder(&directLookup{}, true)
@@ -58,6 +66,9 @@ func NewStaticDashboardSummaryBuilder(lookup DatasourceLookup, sanitize bool) en
 			summary.Fields["hasTemplateVars"] = "true"
 		}
 		summary.Fields["schemaVersion"] = fmt.Sprint(dash.SchemaVersion)
+		if len(dash.TemplateQuery) > 0 {
+			summary.Fields[SummaryFieldTemplateQuery] = strings.Join(dash.TemplateQuery, "\n")
+		}
 
 		for _, panel := range dash.Panels {
 			s := panelSummary(panel, uid, dashboardRefs)
@@ -86,6 +97,12 @@ func panelSummary(panel PanelSummaryInfo, uid string, dashboardRefs ReferenceAcc
 	p.Description = panel.Description
 	p.Fields = make(map[string]string, 0)
 	p.Fields["type"] = panel.Type
+	if len(panel.Queries) > 0 {
+		p.Fields[SummaryFieldQueries] = strings.Join(panel.Queries, "\n")
+	}
+	if panel.Content != "" {
+		p.Fields[SummaryFieldContent] = panel.Content
+	}
 
 	if panel.Type != "row" {
 		panelRefs.Add(entity.ExternalEntityReferencePlugin, string(plugins.TypePanel), panel.Type)
@@ -4,9 +4,20 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
+// queryTextFields are the target fields holding the text of the query for the common data sources.
+var queryTextFields = map[string]bool{
+	"expr":       true, // Prometheus, Loki
+	"expression": true, // CloudWatch, server side expressions
+	"query":      true, // InfluxDB, Elasticsearch, Tempo...
+	"queryText":  true,
+	"rawSql":     true, // SQL data sources
+	"target":     true, // Graphite
+}
+
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries []string
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -77,6 +88,10 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
 
 		default:
 			v := iter.Read()
+			if text, ok := v.(string); ok && text != "" && queryTextFields[l1Field] {
+				s.queries = append(s.queries, text)
+				continue
+			}
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
 		}
 	}
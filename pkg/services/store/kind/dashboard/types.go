@@ -9,6 +9,8 @@ type PanelSummaryInfo struct {
 	LibraryPanel  string          `json:"libraryPanel,omitempty"` // UID of referenced library panel
 	Datasource    []DataSourceRef `json:"datasource,omitempty"`   // UIDs
 	Transformer   []string        `json:"transformer,omitempty"`  // ids of the transformation steps
+	Queries       []string        `json:"queries,omitempty"`      // text of the queries (PromQL, SQL, LogQL...)
+	Content       string          `json:"content,omitempty"`      // content of text panels
 	// Rows define panels as sub objects
 	Collapsed []PanelSummaryInfo `json:"collapsed,omitempty"`
 }
@@ -19,9 +21,10 @@ type DashboardSummaryInfo struct {
 	Title         string             `json:"title"`
 	Description   string             `json:"description,omitempty"`
 	Tags          []string           `json:"tags"`
-	TemplateVars  []string           `json:"templateVars,omitempty"` // the keys used
-	Datasource    []DataSourceRef    `json:"datasource,omitempty"`   // UIDs
-	Panels        []PanelSummaryInfo `json:"panels"`                 // nesed documents
+	TemplateVars  []string           `json:"templateVars,omitempty"`  // the keys used
+	TemplateQuery []string           `json:"templateQuery,omitempty"` // the queries defining the variables
+	Datasource    []DataSourceRef    `json:"datasource,omitempty"`    // UIDs
+	Panels        []PanelSummaryInfo `json:"panels"`                  // nesed documents
 	SchemaVersion int64              `json:"schemaVersion"`
 	LinkCount     int64              `json:"linkCount"`
 	TimeFrom      string             `json:"timeFrom"`
@@ -0,0 +1,67 @@
+// Package teamsync keeps the membership of teams in sync with the groups users have in an external identity provider.
+package teamsync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+// Syncer adds users to and removes them from the teams mapped to their groups.
+// Teams are looked up by name in each organization, so mappings survive the team being recreated.
+type Syncer struct {
+	source string
+	log    log.Logger
+
+	teamService            team.Service
+	teamPermissionsService accesscontrol.TeamPermissionsService
+}
+
+// NewSyncer returns a syncer for the identity provider named by source, for example "ldap" or "saml".
+func NewSyncer(source string, teamService team.Service, teamPermissionsService accesscontrol.TeamPermissionsService) *Syncer {
+	return &Syncer{
+		source:                 source,
+		log:                    log.New("teamsync", "source", source),
+		teamService:            teamService,
+		teamPermissionsService: teamPermissionsService,
+	}
+}
+
+// SyncMembership makes the user a member of the team with the given name in the organization if member is true,
+// and removes the user from the team otherwise. Nothing is changed if the team doesn't exist.
+func (s *Syncer) SyncMembership(ctx context.Context, orgID int64, teamName string, userID int64, member bool) error {
+	requester := accesscontrol.BackgroundUser(s.source+"_team_sync", orgID, org.RoleNone, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+	})
+	result, err := s.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{OrgID: orgID, Name: teamName, SignedInUser: requester})
+	if err != nil {
+		return fmt.Errorf("failed to search team %q: %w", teamName, err)
+	}
+	if len(result.Teams) == 0 {
+		s.log.FromContext(ctx).Debug("Mapped team not found", "team", teamName, "orgID", orgID)
+		return nil
+	}
+
+	t := result.Teams[0]
+	isMember, err := s.teamService.IsTeamMember(ctx, orgID, t.ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check membership of team %q: %w", teamName, err)
+	}
+	if isMember == member {
+		return nil
+	}
+
+	permission := ""
+	if member {
+		permission = team.PermissionTypeMember.String()
+	}
+	if _, err := s.teamPermissionsService.SetUserPermission(ctx, orgID, accesscontrol.User{ID: userID}, strconv.FormatInt(t.ID, 10), permission); err != nil {
+		return fmt.Errorf("failed to update membership of team %q: %w", teamName, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package teamsync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+)
+
+type fakeTeamService struct {
+	*teamtest.FakeService
+	teams []*team.TeamDTO
+}
+
+func (f *fakeTeamService) SearchTeams(_ context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	result := team.SearchTeamQueryResult{}
+	for _, t := range f.teams {
+		if t.OrgID == query.OrgID && t.Name == query.Name {
+			result.Teams = append(result.Teams, t)
+		}
+	}
+	return result, nil
+}
+
+type membershipChange struct {
+	orgID      int64
+	teamID     string
+	userID     int64
+	permission string
+}
+
+type fakeTeamPermissionsService struct {
+	accesscontrol.TeamPermissionsService
+	changes []membershipChange
+}
+
+func (f *fakeTeamPermissionsService) SetUserPermission(_ context.Context, orgID int64, u accesscontrol.User, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
+	f.changes = append(f.changes, membershipChange{orgID: orgID, teamID: resourceID, userID: u.ID, permission: permission})
+	return &accesscontrol.ResourcePermission{}, nil
+}
+
+func TestSyncer_SyncMembership(t *testing.T) {
+	newSyncer := func(isMember bool) (*Syncer, *fakeTeamPermissionsService) {
+		teams := &fakeTeamService{
+			FakeService: &teamtest.FakeService{ExpectedIsMember: isMember},
+			teams:       []*team.TeamDTO{{ID: 5, OrgID: 2, Name: "Platform"}},
+		}
+		permissions := &fakeTeamPermissionsService{}
+		return NewSyncer("test", teams, permissions), permissions
+	}
+
+	t.Run("adds the user to the team", func(t *testing.T) {
+		syncer, permissions := newSyncer(false)
+		require.NoError(t, syncer.SyncMembership(context.Background(), 2, "Platform", 10, true))
+		assert.Equal(t, []membershipChange{{orgID: 2, teamID: "5", userID: 10, permission: team.PermissionTypeMember.String()}}, permissions.changes)
+	})
+
+	t.Run("removes the user from the team", func(t *testing.T) {
+		syncer, permissions := newSyncer(true)
+		require.NoError(t, syncer.SyncMembership(context.Background(), 2, "Platform", 10, false))
+		assert.Equal(t, []membershipChange{{orgID: 2, teamID: "5", userID: 10, permission: ""}}, permissions.changes)
+	})
+
+	t.Run("does nothing when the membership is up to date", func(t *testing.T) {
+		syncer, permissions := newSyncer(true)
+		require.NoError(t, syncer.SyncMembership(context.Background(), 2, "Platform", 10, true))
+		assert.Empty(t, permissions.changes)
+	})
+
+	t.Run("ignores teams that don't exist in the organization", func(t *testing.T) {
+		syncer, permissions := newSyncer(false)
+		require.NoError(t, syncer.SyncMembership(context.Background(), 1, "Platform", 10, true))
+		require.NoError(t, syncer.SyncMembership(context.Background(), 2, "Backend", 10, true))
+		assert.Empty(t, permissions.changes)
+	})
+}
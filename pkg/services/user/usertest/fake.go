@@ -22,6 +22,8 @@ type FakeUserService struct {
 	CreateFn            func(ctx context.Context, cmd *user.CreateUserCommand) (*user.User, error)
 	BatchDisableUsersFn func(ctx context.Context, cmd *user.BatchDisableUsersCommand) error
 	GetByEmailFn        func(ctx context.Context, query *user.GetUserByEmailQuery) (*user.User, error)
+	DeleteFn            func(ctx context.Context, cmd *user.DeleteUserCommand) error
+	GetByUIDFn          func(ctx context.Context, query *user.GetUserByUIDQuery) (*user.User, error)
 
 	counter int
 }
@@ -47,6 +49,9 @@ func (f *FakeUserService) CreateServiceAccount(ctx context.Context, cmd *user.Cr
 }
 
 func (f *FakeUserService) Delete(ctx context.Context, cmd *user.DeleteUserCommand) error {
+	if f.DeleteFn != nil {
+		return f.DeleteFn(ctx, cmd)
+	}
 	return f.ExpectedError
 }
 
@@ -55,6 +60,9 @@ func (f *FakeUserService) GetByID(ctx context.Context, query *user.GetUserByIDQu
 }
 
 func (f *FakeUserService) GetByUID(ctx context.Context, query *user.GetUserByUIDQuery) (*user.User, error) {
+	if f.GetByUIDFn != nil {
+		return f.GetByUIDFn(ctx, query)
+	}
 	return f.ExpectedUser, f.ExpectedError
 }
 
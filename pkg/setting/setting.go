@@ -152,6 +152,9 @@ type Cfg struct {
 	RendererDefaultImageHeight     int
 	RendererDefaultImageScale      float64
 
+	// Scheduled dashboard reports
+	DashboardReports DashboardReportsSettings
+
 	// Security
 	DisableInitAdminCreation             bool
 	DisableBruteForceLoginProtection     bool
@@ -272,6 +275,12 @@ type Cfg struct {
 
 	PasswordlessMagicLinkAuth AuthPasswordlessMagicLinkSettings
 
+	// SCIM provisioning
+	SCIM SCIMSettings
+
+	// Multi-factor authentication
+	MFA MFASettings
+
 	// SSO Settings Auth
 	SSOSettingsReloadInterval        time.Duration
 	SSOSettingsConfigurableProviders map[string]bool
@@ -391,6 +400,7 @@ type Cfg struct {
 	LDAPAllowSignup       bool
 	LDAPActiveSyncEnabled bool
 	LDAPSyncCron          string
+	LDAPSyncPageSize      int
 
 	DefaultTheme    string
 	DefaultLanguage string
@@ -1160,6 +1170,7 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	if err := cfg.readRenderingSettings(iniFile); err != nil {
 		return err
 	}
+	cfg.readDashboardReportsSettings()
 
 	cfg.TempDataLifetime = iniFile.Section("paths").Key("temp_data_lifetime").MustDuration(time.Second * 3600 * 24)
 	cfg.MetricsEndpointEnabled = iniFile.Section("metrics").Key("enabled").MustBool(true)
@@ -1271,6 +1282,8 @@ func (cfg *Cfg) parseINIFile(iniFile *ini.File) error {
 	cfg.readAuthProxySettings()
 	cfg.readSessionConfig()
 	cfg.readPasswordlessMagicLinkSettings()
+	cfg.readSCIMSettings()
+	cfg.readMFASettings()
 	if err := cfg.readSmtpSettings(); err != nil {
 		return err
 	}
@@ -1379,6 +1392,7 @@ func (cfg *Cfg) readLDAPConfig() {
 	cfg.LDAPAuthEnabled = ldapSec.Key("enabled").MustBool(false)
 	cfg.LDAPSkipOrgRoleSync = ldapSec.Key("skip_org_role_sync").MustBool(false)
 	cfg.LDAPActiveSyncEnabled = ldapSec.Key("active_sync_enabled").MustBool(false)
+	cfg.LDAPSyncPageSize = ldapSec.Key("sync_page_size").MustInt(500)
 	cfg.LDAPAllowSignup = ldapSec.Key("allow_sign_up").MustBool(true)
 }
 
@@ -0,0 +1,19 @@
+package setting
+
+import "time"
+
+type DashboardReportsSettings struct {
+	// Enabled exposes the dashboard reports API and sends the scheduled reports by email
+	Enabled bool
+	// RenderTimeout is the timeout of rendering a report attachment
+	RenderTimeout time.Duration
+	// MaxRecipients is the maximum number of recipients of a single report
+	MaxRecipients int
+}
+
+func (cfg *Cfg) readDashboardReportsSettings() {
+	sec := cfg.SectionWithEnvOverrides("dashboard_reports")
+	cfg.DashboardReports.Enabled = sec.Key("enabled").MustBool(false)
+	cfg.DashboardReports.RenderTimeout = sec.Key("render_timeout").MustDuration(time.Minute)
+	cfg.DashboardReports.MaxRecipients = sec.Key("max_recipients").MustInt(50)
+}
@@ -0,0 +1,46 @@
+package setting
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type MFASettings struct {
+	// Enabled allows users to enroll a second factor for password logins
+	Enabled bool
+	// Issuer is the name shown in authenticator apps
+	Issuer string
+	// RequiredRoles are the org roles, or GrafanaAdmin, that can only sign in with a second factor
+	RequiredRoles []string
+	// RequiredOrgIDs are the orgs whose members can only sign in with a second factor
+	RequiredOrgIDs []int64
+	// RecoveryCodes is the number of single-use recovery codes generated for a user
+	RecoveryCodes int
+	// ChallengeTTL is how long an enrollment or login challenge stays valid
+	ChallengeTTL time.Duration
+	// WebAuthnRPID is the WebAuthn relying party id, defaults to the host of the root url
+	WebAuthnRPID string
+}
+
+func (cfg *Cfg) readMFASettings() {
+	sec := cfg.SectionWithEnvOverrides("auth.mfa")
+	cfg.MFA.Enabled = sec.Key("enabled").MustBool(false)
+	cfg.MFA.Issuer = sec.Key("issuer").MustString("Grafana")
+	cfg.MFA.RequiredRoles = util.SplitString(sec.Key("required_roles").MustString(""))
+
+	cfg.MFA.RequiredOrgIDs = nil
+	for _, s := range util.SplitString(sec.Key("required_org_ids").MustString("")) {
+		orgID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			cfg.Logger.Warn("invalid auth.mfa required_org_ids entry", "value", s)
+			continue
+		}
+		cfg.MFA.RequiredOrgIDs = append(cfg.MFA.RequiredOrgIDs, orgID)
+	}
+
+	cfg.MFA.RecoveryCodes = sec.Key("recovery_codes").MustInt(10)
+	cfg.MFA.ChallengeTTL = sec.Key("challenge_ttl").MustDuration(5 * time.Minute)
+	cfg.MFA.WebAuthnRPID = sec.Key("webauthn_rp_id").MustString("")
+}
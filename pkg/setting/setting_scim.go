@@ -0,0 +1,26 @@
+package setting
+
+import "github.com/grafana/grafana/pkg/apimachinery/identity"
+
+type SCIMSettings struct {
+	// Enabled exposes the SCIM 2.0 provisioning endpoints under /api/scim/v2
+	Enabled bool
+	// DefaultOrgRole is the org role given to provisioned users that don't carry a role
+	DefaultOrgRole string
+	// MaxBulkOperations is the maximum number of operations accepted in a single bulk request
+	MaxBulkOperations int
+	// MaxResults is the maximum number of resources returned in a single list response
+	MaxResults int
+}
+
+func (cfg *Cfg) readSCIMSettings() {
+	sec := cfg.SectionWithEnvOverrides("auth.scim")
+	cfg.SCIM.Enabled = sec.Key("enabled").MustBool(false)
+	cfg.SCIM.DefaultOrgRole = sec.Key("default_org_role").MustString(string(identity.RoleViewer))
+	if !identity.RoleType(cfg.SCIM.DefaultOrgRole).IsValid() {
+		cfg.Logger.Warn("invalid auth.scim default_org_role, falling back to Viewer", "role", cfg.SCIM.DefaultOrgRole)
+		cfg.SCIM.DefaultOrgRole = string(identity.RoleViewer)
+	}
+	cfg.SCIM.MaxBulkOperations = sec.Key("max_bulk_operations").MustInt(1000)
+	cfg.SCIM.MaxResults = sec.Key("max_results").MustInt(1000)
+}
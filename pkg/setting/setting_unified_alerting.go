@@ -91,6 +91,7 @@ type UnifiedAlertingSettings struct {
 	HARedisMaxConns                 int
 	HARedisTLSEnabled               bool
 	HARedisTLSConfig                dstls.ClientConfig
+	HAEvaluationSharding            bool
 	InitializationTimeout           time.Duration
 	MaxAttempts                     int64
 	MinInterval                     time.Duration
@@ -112,6 +113,7 @@ type UnifiedAlertingSettings struct {
 	StateHistory                  UnifiedAlertingStateHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	RecordingRules                RecordingRuleSettings
+	EvaluationLimits              UnifiedAlertingEvaluationLimitsSettings
 
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency    int
@@ -122,6 +124,11 @@ type UnifiedAlertingSettings struct {
 	// Retention period for Alertmanager notification log entries.
 	NotificationLogRetention time.Duration
 
+	// NotificationDeliveryLog enables the log of the notification attempts of receiver integrations.
+	NotificationDeliveryLog bool
+	// Retention period for the entries of the notification delivery log.
+	NotificationDeliveryLogRetention time.Duration
+
 	// Duration for which a resolved alert state transition will continue to be sent to the Alertmanager.
 	ResolvedAlertRetention time.Duration
 
@@ -140,6 +147,41 @@ type RecordingRuleSettings struct {
 	Timeout           time.Duration
 }
 
+// UnifiedAlertingEvaluationLimits are the limits of the evaluation of alert rules. Zero values disable a limit.
+type UnifiedAlertingEvaluationLimits struct {
+	// MaxSeriesPerEvaluation is the maximum number of series that the condition of a rule can return.
+	MaxSeriesPerEvaluation int
+	// MaxEvaluationDuration is the maximum duration of the evaluation of a rule.
+	MaxEvaluationDuration time.Duration
+	// MaxRulesPerInterval is the maximum number of rule evaluations in RulesInterval.
+	MaxRulesPerInterval int
+}
+
+// EvaluationLimitsFolderKey identifies the folder of an organization that has its own evaluation limits.
+type EvaluationLimitsFolderKey struct {
+	OrgID     int64
+	FolderUID string
+}
+
+type UnifiedAlertingEvaluationLimitsSettings struct {
+	// Default contains the limits of organizations that have no limits of their own.
+	Default UnifiedAlertingEvaluationLimits
+	// Orgs contains the limits of organizations. Limits that are not set for an organization are copied from Default.
+	Orgs map[int64]UnifiedAlertingEvaluationLimits
+	// Folders contains the limits of folders. The per-rule limits that are not set for a folder are copied from the
+	// limits of its organization. MaxRulesPerInterval applies to the rules of the folder only if it is set for the folder.
+	Folders map[EvaluationLimitsFolderKey]UnifiedAlertingEvaluationLimits
+	// RulesInterval is the interval of MaxRulesPerInterval.
+	RulesInterval time.Duration
+	// PauseDuration is for how long a rule that exceeds a per-rule limit is not evaluated.
+	PauseDuration time.Duration
+}
+
+// IsEnabled returns true if any limit is set.
+func (s UnifiedAlertingEvaluationLimitsSettings) IsEnabled() bool {
+	return s.Default != (UnifiedAlertingEvaluationLimits{}) || len(s.Orgs) > 0 || len(s.Folders) > 0
+}
+
 // RemoteAlertmanagerSettings contains the configuration needed
 // to disable the internal Alertmanager and use an external one instead.
 type RemoteAlertmanagerSettings struct {
@@ -290,6 +332,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisTLSConfig.InsecureSkipVerify = ua.Key("ha_redis_tls_insecure_skip_verify").MustBool(false)
 	uaCfg.HARedisTLSConfig.CipherSuites = ua.Key("ha_redis_tls_cipher_suites").MustString("")
 	uaCfg.HARedisTLSConfig.MinVersion = ua.Key("ha_redis_tls_min_version").MustString("")
+	uaCfg.HAEvaluationSharding = ua.Key("ha_evaluation_sharding").MustBool(false)
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -451,6 +494,11 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfg.RecordingRules = uaCfgRecordingRules
 
+	uaCfg.EvaluationLimits, err = readEvaluationLimitsSettings(iniFile)
+	if err != nil {
+		return err
+	}
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
 	uaCfg.StatePeriodicSaveInterval, err = gtime.ParseDuration(valueAsString(ua, "state_periodic_save_interval", (time.Minute * 5).String()))
@@ -465,6 +513,12 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		return err
 	}
 
+	uaCfg.NotificationDeliveryLog = ua.Key("notification_delivery_log").MustBool(false)
+	uaCfg.NotificationDeliveryLogRetention, err = gtime.ParseDuration(valueAsString(ua, "notification_delivery_log_retention", (7 * 24 * time.Hour).String()))
+	if err != nil {
+		return err
+	}
+
 	uaCfg.ResolvedAlertRetention, err = gtime.ParseDuration(valueAsString(ua, "resolved_alert_retention", (15 * time.Minute).String()))
 	if err != nil {
 		return err
@@ -490,3 +544,97 @@ func splitTrim(s string, sep string) []string {
 	}
 	return spl
 }
+
+const (
+	evaluationLimitsSection       = "unified_alerting.evaluation_limits"
+	evaluationLimitsOrgPrefix     = evaluationLimitsSection + ".org."
+	evaluationLimitsFolderPrefix  = evaluationLimitsSection + ".folder."
+	evaluationLimitsRulesInterval = time.Minute
+	evaluationLimitsPauseDuration = time.Hour
+)
+
+// readEvaluationLimitsSettings reads the default evaluation limits and the limits of the sections
+// [unified_alerting.evaluation_limits.org.<org ID>] and [unified_alerting.evaluation_limits.folder.<org ID>.<folder UID>].
+func readEvaluationLimitsSettings(iniFile *ini.File) (UnifiedAlertingEvaluationLimitsSettings, error) {
+	section := iniFile.Section(evaluationLimitsSection)
+	result := UnifiedAlertingEvaluationLimitsSettings{
+		Orgs:    make(map[int64]UnifiedAlertingEvaluationLimits),
+		Folders: make(map[EvaluationLimitsFolderKey]UnifiedAlertingEvaluationLimits),
+	}
+	var err error
+	if result.Default, err = readEvaluationLimits(section, UnifiedAlertingEvaluationLimits{}); err != nil {
+		return result, err
+	}
+	if result.RulesInterval, err = gtime.ParseDuration(valueAsString(section, "rules_interval", evaluationLimitsRulesInterval.String())); err != nil {
+		return result, fmt.Errorf("invalid value of setting 'rules_interval' of section [%s]: %w", evaluationLimitsSection, err)
+	}
+	if result.RulesInterval <= 0 {
+		return result, fmt.Errorf("value of setting 'rules_interval' of section [%s] must be positive", evaluationLimitsSection)
+	}
+	if result.PauseDuration, err = gtime.ParseDuration(valueAsString(section, "pause_duration", evaluationLimitsPauseDuration.String())); err != nil {
+		return result, fmt.Errorf("invalid value of setting 'pause_duration' of section [%s]: %w", evaluationLimitsSection, err)
+	}
+
+	var folders []*ini.Section
+	for _, s := range iniFile.Sections() {
+		if strings.HasPrefix(s.Name(), evaluationLimitsFolderPrefix) {
+			folders = append(folders, s)
+			continue
+		}
+		if !strings.HasPrefix(s.Name(), evaluationLimitsOrgPrefix) {
+			continue
+		}
+		orgID, err := strconv.ParseInt(strings.TrimPrefix(s.Name(), evaluationLimitsOrgPrefix), 10, 64)
+		if err != nil {
+			return result, fmt.Errorf("invalid organization ID in section [%s]", s.Name())
+		}
+		if result.Orgs[orgID], err = readEvaluationLimits(s, result.Default); err != nil {
+			return result, err
+		}
+	}
+	// Folders are read after all organizations, so that they inherit the limits of their organization.
+	for _, s := range folders {
+		orgID, folderUID, ok := strings.Cut(strings.TrimPrefix(s.Name(), evaluationLimitsFolderPrefix), ".")
+		key := EvaluationLimitsFolderKey{FolderUID: folderUID}
+		if key.OrgID, err = strconv.ParseInt(orgID, 10, 64); err != nil || !ok || folderUID == "" {
+			return result, fmt.Errorf("invalid section [%s], expected [%s<org ID>.<folder UID>]", s.Name(), evaluationLimitsFolderPrefix)
+		}
+		parent, ok := result.Orgs[key.OrgID]
+		if !ok {
+			parent = result.Default
+		}
+		// The rules of the organization are limited as a whole, the limit of the folder must be set explicitly.
+		parent.MaxRulesPerInterval = 0
+		if result.Folders[key], err = readEvaluationLimits(s, parent); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func readEvaluationLimits(section *ini.Section, parent UnifiedAlertingEvaluationLimits) (UnifiedAlertingEvaluationLimits, error) {
+	// Only the keys of the section itself are considered, because ini falls back to the keys of the parent
+	// sections, which would override the limits inherited from the organization.
+	own := section.KeysHash()
+	limits := parent
+	var err error
+	if v, ok := own["max_series_per_evaluation"]; ok {
+		if limits.MaxSeriesPerEvaluation, err = strconv.Atoi(v); err != nil {
+			return limits, fmt.Errorf("invalid value of setting 'max_series_per_evaluation' of section [%s]: %w", section.Name(), err)
+		}
+	}
+	if v, ok := own["max_rules_per_interval"]; ok {
+		if limits.MaxRulesPerInterval, err = strconv.Atoi(v); err != nil {
+			return limits, fmt.Errorf("invalid value of setting 'max_rules_per_interval' of section [%s]: %w", section.Name(), err)
+		}
+	}
+	if v, ok := own["max_evaluation_duration"]; ok {
+		if limits.MaxEvaluationDuration, err = gtime.ParseDuration(v); err != nil {
+			return limits, fmt.Errorf("invalid value of setting 'max_evaluation_duration' of section [%s]: %w", section.Name(), err)
+		}
+	}
+	if limits.MaxSeriesPerEvaluation < 0 || limits.MaxRulesPerInterval < 0 || limits.MaxEvaluationDuration < 0 {
+		return limits, fmt.Errorf("evaluation limits of section [%s] must not be negative", section.Name())
+	}
+	return limits, nil
+}
@@ -350,3 +350,65 @@ func TestHARedisTLSSettings(t *testing.T) {
 	require.Equal(t, cipherSuites, cfg.UnifiedAlerting.HARedisTLSConfig.CipherSuites)
 	require.Equal(t, minVersion, cfg.UnifiedAlerting.HARedisTLSConfig.MinVersion)
 }
+
+func TestEvaluationLimitsSettings(t *testing.T) {
+	t.Run("limits of organizations and folders inherit the missing limits", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[unified_alerting.evaluation_limits]
+max_series_per_evaluation = 1000
+max_evaluation_duration = 30s
+max_rules_per_interval = 500
+pause_duration = 10m
+
+[unified_alerting.evaluation_limits.org.2]
+max_series_per_evaluation = 5000
+
+[unified_alerting.evaluation_limits.folder.2.Team-A]
+max_evaluation_duration = 1m
+
+[unified_alerting.evaluation_limits.folder.3.team-b]
+max_rules_per_interval = 50
+`))
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+
+		limits := cfg.UnifiedAlerting.EvaluationLimits
+		require.True(t, limits.IsEnabled())
+		require.Equal(t, time.Minute, limits.RulesInterval)
+		require.Equal(t, 10*time.Minute, limits.PauseDuration)
+		require.Equal(t, UnifiedAlertingEvaluationLimits{MaxSeriesPerEvaluation: 1000, MaxEvaluationDuration: 30 * time.Second, MaxRulesPerInterval: 500}, limits.Default)
+		require.Equal(t, map[int64]UnifiedAlertingEvaluationLimits{
+			2: {MaxSeriesPerEvaluation: 5000, MaxEvaluationDuration: 30 * time.Second, MaxRulesPerInterval: 500},
+		}, limits.Orgs)
+		require.Equal(t, map[EvaluationLimitsFolderKey]UnifiedAlertingEvaluationLimits{
+			{OrgID: 2, FolderUID: "Team-A"}: {MaxSeriesPerEvaluation: 5000, MaxEvaluationDuration: time.Minute},
+			{OrgID: 3, FolderUID: "team-b"}: {MaxSeriesPerEvaluation: 1000, MaxEvaluationDuration: 30 * time.Second, MaxRulesPerInterval: 50},
+		}, limits.Folders)
+	})
+
+	t.Run("no limits by default", func(t *testing.T) {
+		cfg := NewCfg()
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(ini.Empty()))
+		require.False(t, cfg.UnifiedAlerting.EvaluationLimits.IsEnabled())
+	})
+
+	for _, section := range []string{
+		"[unified_alerting.evaluation_limits.org.main]",
+		"[unified_alerting.evaluation_limits.folder.uid]",
+		"[unified_alerting.evaluation_limits.folder.1.]",
+	} {
+		t.Run("invalid section "+section, func(t *testing.T) {
+			f, err := ini.Load([]byte(section + "\nmax_series_per_evaluation = 10\n"))
+			require.NoError(t, err)
+			require.Error(t, NewCfg().ReadUnifiedAlertingSettings(f))
+		})
+	}
+
+	t.Run("negative limit", func(t *testing.T) {
+		f, err := ini.Load([]byte("[unified_alerting.evaluation_limits]\nmax_series_per_evaluation = -1\n"))
+		require.NoError(t, err)
+		require.Error(t, NewCfg().ReadUnifiedAlertingSettings(f))
+	})
+}
@@ -22,6 +22,11 @@ type RBACSettings struct {
 
 	OnlyStoreAccessActionSets bool
 
+	// Longest duration of a temporary access grant
+	AccessGrantMaxDuration time.Duration
+	// Time after which access grant requests that weren't reviewed expire
+	AccessGrantRequestTTL time.Duration
+
 	// set of resources that should generate managed permissions when created
 	resourcesWithPermissionsOnCreation map[string]struct{}
 
@@ -53,6 +58,9 @@ func (cfg *Cfg) readRBACSettings() {
 		s.resourcesWithWildcardSeed[resource] = struct{}{}
 	}
 
+	s.AccessGrantMaxDuration = rbac.Key("access_grant_max_duration").MustDuration(24 * time.Hour)
+	s.AccessGrantRequestTTL = rbac.Key("access_grant_request_ttl").MustDuration(24 * time.Hour)
+
 	var err error
 	s.ZanzanaReconciliationInterval, err = gtime.ParseDuration(rbac.Key("zanzana_reconciliation_interval").MustString("1h"))
 	if err != nil {
@@ -269,8 +269,9 @@ const SEARCH_FIELD_REPOSITORY_PATH = "repo.path"
 const SEARCH_FIELD_REPOSITORY_HASH = "repo.hash"
 const SEARCH_FIELD_REPOSITORY_TIME = "repo.time"
 
-const SEARCH_FIELD_SCORE = "_score"     // the match score
-const SEARCH_FIELD_EXPLAIN = "_explain" // score explanation as JSON object
+const SEARCH_FIELD_SCORE = "_score"         // the match score
+const SEARCH_FIELD_EXPLAIN = "_explain"     // score explanation as JSON object
+const SEARCH_FIELD_HIGHLIGHT = "_highlight" // highlighted matches as JSON object
 
 var standardSearchFieldsInit sync.Once
 var standardSearchFields SearchableDocumentFields
@@ -358,6 +359,11 @@ func StandardSearchFields() SearchableDocumentFields {
 				Type:        ResourceTableColumnDefinition_DOUBLE,
 				Description: "The search score",
 			},
+			{
+				Name:        SEARCH_FIELD_HIGHLIGHT,
+				Type:        ResourceTableColumnDefinition_OBJECT,
+				Description: "The matches of the field scoped terms (depends on the engine)",
+			},
 		})
 		if err != nil {
 			panic("failed to initialize standard search fields")
@@ -15,6 +15,7 @@ import (
 	"github.com/blevesearch/bleve/v2"
 	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
 	"github.com/blevesearch/bleve/v2/search/query"
 	bleveSearch "github.com/blevesearch/bleve/v2/search/searcher"
 	index "github.com/blevesearch/bleve_index_api"
@@ -26,6 +27,7 @@ import (
 	authlib "github.com/grafana/authlib/types"
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/store/kind/dashboard"
 	"github.com/grafana/grafana/pkg/storage/unified/resource"
 )
 
@@ -123,7 +125,11 @@ func (b *bleveBackend) BuildIndex(ctx context.Context,
 		resourceDir := filepath.Join(b.opts.Root, key.Namespace,
 			fmt.Sprintf("%s.%s", key.Resource, key.Group),
 		)
-		fname := fmt.Sprintf("rv%d", resourceVersion)
+		mappingsHash, err := getBleveMappingsHash(mapper)
+		if err != nil {
+			return nil, fmt.Errorf("error hashing bleve mappings: %w", err)
+		}
+		fname := fmt.Sprintf("rv%d-%s", resourceVersion, mappingsHash)
 		if resourceVersion == 0 {
 			fname = b.start.Format("tmp-20060102-150405")
 		}
@@ -444,6 +450,10 @@ func (b *bleveIndex) Search(
 		}
 		searchrequest.Fields = f
 	}
+	if searchrequest.Highlight != nil {
+		// maps the matches in the panels to the panel ids
+		searchrequest.Fields = append(searchrequest.Fields, dashboardPanelIDField)
+	}
 
 	res, err := index.SearchInContext(ctx, searchrequest)
 	if err != nil {
@@ -454,7 +464,7 @@ func (b *bleveIndex) Search(
 	response.QueryCost = float64(res.Cost)
 	response.MaxScore = res.MaxScore
 
-	response.Results, err = b.hitsToTable(ctx, searchrequest.Fields, res.Hits, req.Explain)
+	response.Results, err = b.hitsToTable(ctx, searchrequest.Fields, res.Hits, req.Explain, searchrequest.Highlight != nil)
 	if err != nil {
 		return nil, err
 	}
@@ -587,11 +597,37 @@ func (b *bleveIndex) toBleveSearchRequest(ctx context.Context, req *resource.Res
 		}
 	}
 
+	// Terms like `query:http_requests_total` match the panel queries, descriptions, etc. The remaining
+	// text is a text query.
+	text, terms := dashboard.ParseSearchQuery(req.Query)
+	if len(terms) > 0 {
+		searchrequest.Highlight = bleve.NewHighlightWithStyle(html.Name)
+		searchrequest.IncludeLocations = true
+	}
+	for _, term := range terms {
+		fields := dashboardSearchTermFields[term.Field]
+		disjuncts := make([]query.Query, 0, len(fields))
+		for _, field := range fields {
+			if term.Phrase {
+				q := bleve.NewMatchPhraseQuery(term.Value)
+				q.SetField(field)
+				disjuncts = append(disjuncts, q)
+			} else {
+				q := bleve.NewMatchQuery(term.Value)
+				q.SetField(field)
+				q.SetOperator(query.MatchQueryOperatorAnd)
+				disjuncts = append(disjuncts, q)
+			}
+			searchrequest.Highlight.AddField(field)
+		}
+		queries = append(queries, bleve.NewDisjunctionQuery(disjuncts...))
+	}
+
 	// Add a text query
-	if req.Query != "" && req.Query != "*" {
+	if text != "" && text != "*" {
 		searchrequest.Fields = append(searchrequest.Fields, resource.SEARCH_FIELD_SCORE)
 		// mimic the behavior of the sql search
-		query := strings.ToLower(req.Query)
+		query := strings.ToLower(text)
 		if !strings.Contains(query, "*") {
 			query = "*" + query + "*"
 		}
@@ -770,7 +806,7 @@ func filterValue(field string, v string) string {
 	return v
 }
 
-func (b *bleveIndex) hitsToTable(ctx context.Context, selectFields []string, hits search.DocumentMatchCollection, explain bool) (*resource.ResourceTable, error) {
+func (b *bleveIndex) hitsToTable(ctx context.Context, selectFields []string, hits search.DocumentMatchCollection, explain bool, highlight bool) (*resource.ResourceTable, error) {
 	_, span := b.tracing.Start(ctx, tracingPrexfixBleve+"hitsToTable")
 	defer span.End()
 
@@ -804,6 +840,9 @@ func (b *bleveIndex) hitsToTable(ctx context.Context, selectFields []string, hit
 	if explain {
 		fields = append(fields, b.standard.Field(resource.SEARCH_FIELD_EXPLAIN))
 	}
+	if highlight {
+		fields = append(fields, b.standard.Field(resource.SEARCH_FIELD_HIGHLIGHT))
+	}
 
 	builder, err := resource.NewTableBuilder(fields)
 	if err != nil {
@@ -840,6 +879,9 @@ func (b *bleveIndex) hitsToTable(ctx context.Context, selectFields []string, hit
 				if match.Expl != nil {
 					row.Cells[i], err = json.Marshal(match.Expl)
 				}
+
+			case resource.SEARCH_FIELD_HIGHLIGHT:
+				row.Cells[i], err = json.Marshal(dashboardHighlight(match))
 			default:
 				fieldName := f.Name
 				// since the bleve index fields mix common and resource-specific fields, it is possible a conflict can happen
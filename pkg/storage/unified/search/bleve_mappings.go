@@ -1,6 +1,10 @@
 package search
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
 	"github.com/blevesearch/bleve/v2"
 	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
 	"github.com/blevesearch/bleve/v2/mapping"
@@ -14,6 +18,16 @@ func getBleveMappings(fields resource.SearchableDocumentFields) mapping.IndexMap
 	return mapper
 }
 
+// getBleveMappingsHash identifies the mappings of an index. File indexes are stored with it so the
+// indexes built with other mappings, for example before an upgrade, are rebuilt rather than reopened.
+func getBleveMappingsHash(mapper mapping.IndexMapping) (string, error) {
+	b, err := json.Marshal(mapper)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))[:12], nil
+}
+
 func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentMapping {
 	mapper := bleve.NewDocumentStaticMapping()
 
@@ -38,7 +52,7 @@ func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentM
 		Type:               "text",
 		Store:              true,
 		Index:              true,
-		IncludeTermVectors: false,
+		IncludeTermVectors: true, // highlight matches
 		IncludeInAll:       false,
 		DocValues:          false,
 	}
@@ -107,5 +121,28 @@ func getBleveDocMappings(_ resource.SearchableDocumentFields) *mapping.DocumentM
 	fieldMapper := bleve.NewDocumentMapping()
 	mapper.AddSubDocumentMapping("fields", fieldMapper)
 
+	// The text of the dashboard panels and variables is only searched with `field:value` terms
+	panelMapper := bleve.NewDocumentMapping()
+	panelIDMapping := bleve.NewNumericFieldMapping()
+	panelIDMapping.IncludeInAll = false
+	panelMapper.AddFieldMappingsAt(dashboardPanelID, panelIDMapping)
+	panelMapper.AddFieldMappingsAt(dashboardPanelQuery, newFullTextFieldMapping(dashboardPanelQuery))
+	panelMapper.AddFieldMappingsAt(dashboardPanelDescription, newFullTextFieldMapping(dashboardPanelDescription))
+	panelMapper.AddFieldMappingsAt(dashboardPanelContent, newFullTextFieldMapping(dashboardPanelContent))
+	fieldMapper.AddSubDocumentMapping(DASHBOARD_PANELS, panelMapper)
+	fieldMapper.AddFieldMappingsAt(DASHBOARD_TEMPLATE_QUERIES, newFullTextFieldMapping(DASHBOARD_TEMPLATE_QUERIES))
+
 	return mapper
 }
+
+func newFullTextFieldMapping(name string) *mapping.FieldMapping {
+	return &mapping.FieldMapping{
+		Name:               name,
+		Type:               "text",
+		Store:              true,
+		Index:              true,
+		IncludeTermVectors: true, // highlight matches
+		IncludeInAll:       false,
+		DocValues:          false,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/blevesearch/bleve/v2/document"
+	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
@@ -45,3 +46,19 @@ func TestDocumentMapping(t *testing.T) {
 	fmt.Printf("DOC: size %d\n", doc.Size())
 	require.Equal(t, 13, len(doc.Fields))
 }
+
+func TestBleveMappingsHash(t *testing.T) {
+	hash, err := getBleveMappingsHash(getBleveMappings(nil))
+	require.NoError(t, err)
+
+	same, err := getBleveMappingsHash(getBleveMappings(nil))
+	require.NoError(t, err)
+	require.Equal(t, hash, same)
+
+	changed := getBleveMappings(nil).(*mapping.IndexMappingImpl)
+	description := changed.DefaultMapping.Properties[resource.SEARCH_FIELD_DESCRIPTION].Fields[0]
+	description.IncludeTermVectors = !description.IncludeTermVectors
+	other, err := getBleveMappingsHash(changed)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+}
@@ -529,6 +529,109 @@ func TestBleveBackend(t *testing.T) {
 	})
 }
 
+func TestBleveFullTextSearch(t *testing.T) {
+	key := &resource.ResourceKey{
+		Namespace: "default",
+		Group:     "dashboard.grafana.app",
+		Resource:  "dashboards",
+	}
+	backend, err := NewBleveBackend(BleveOptions{
+		Root:          t.TempDir(),
+		FileThreshold: 5,
+	}, tracing.NewNoopTracerService(), featuremgmt.WithFeatures())
+	require.NoError(t, err)
+	resource.NewIndexMetrics(backend.opts.Root, backend)
+
+	builder := &DashboardDocumentBuilder{
+		Namespace:        key.Namespace,
+		DatasourceLookup: dashboard.CreateDatasourceLookup([]*dashboard.DatasourceQueryResult{}),
+	}
+	info, err := DashboardBuilder(nil)
+	require.NoError(t, err)
+
+	dashboards := map[string]string{
+		"checkout": `{
+			"title": "Checkout",
+			"templating": {"list": [{"name": "job", "type": "query", "definition": "label_values(http_requests_total, job)"}]},
+			"panels": [
+				{"id": 1, "title": "Help", "type": "text", "options": {"content": "Runbook for the on call team"}},
+				{"id": 4, "title": "Requests", "targets": [{"refId": "A", "expr": "sum(rate(http_requests_total[5m]))"}]}
+			]
+		}`,
+		"payments": `{
+			"title": "Payments",
+			"description": "Latency of the payments",
+			"panels": [{"id": 2, "title": "Errors", "targets": [{"refId": "A", "expr": "payment_errors_total"}]}]
+		}`,
+	}
+	ctx := context.Background()
+	index, err := backend.BuildIndex(ctx, resource.NamespacedResource{
+		Namespace: key.Namespace,
+		Group:     key.Group,
+		Resource:  key.Resource,
+	}, 2, 1, info.Fields, func(index resource.ResourceIndex) (int64, error) {
+		for name, spec := range dashboards {
+			k := &resource.ResourceKey{Namespace: key.Namespace, Group: key.Group, Resource: key.Resource, Name: name}
+			value := fmt.Sprintf(`{"kind": "Dashboard", "apiVersion": "dashboard.grafana.app/v0alpha1", "metadata": {"name": %q, "namespace": "default"}, "spec": %s}`, name, spec)
+			doc, err := builder.BuildDocument(ctx, k, 1, []byte(value))
+			if err != nil {
+				return 0, err
+			}
+			if err := index.Write(doc); err != nil {
+				return 0, err
+			}
+		}
+		return 1, nil
+	})
+	require.NoError(t, err)
+
+	search := func(query string) map[string]map[string]any {
+		rsp, err := index.Search(ctx, nil, &resource.ResourceSearchRequest{
+			Options: &resource.ListOptions{Key: key},
+			Query:   query,
+			Fields:  []string{resource.SEARCH_FIELD_TITLE},
+			Limit:   10,
+		}, nil)
+		require.NoError(t, err)
+		require.Nil(t, rsp.Error)
+
+		highlights := map[string]map[string]any{}
+		for _, row := range rsp.Results.Rows {
+			highlight := map[string]any{}
+			require.NoError(t, json.Unmarshal(row.Cells[len(row.Cells)-1], &highlight))
+			highlights[row.Key.Name] = highlight
+		}
+		return highlights
+	}
+
+	require.Equal(t, map[string]map[string]any{
+		"checkout": {
+			"query":  []any{"sum(rate(<mark>http_requests_total</mark>[5m]))"},
+			"panels": []any{float64(4)},
+		},
+	}, search("query:http_requests_total"))
+
+	require.Equal(t, map[string]map[string]any{
+		"checkout": {"variable": []any{"label_values(<mark>http_requests_total</mark>, job)"}},
+	}, search("variable:http_requests_total"))
+
+	require.Equal(t, map[string]map[string]any{
+		"checkout": {
+			"content": []any{"Runbook for the on <mark>call</mark> <mark>team</mark>"},
+			"panels":  []any{float64(1)},
+		},
+	}, search(`content:"call team"`))
+
+	require.Equal(t, map[string]map[string]any{
+		"payments": {"description": []any{"<mark>Latency</mark> of the payments"}},
+	}, search("pay description:latency"))
+
+	// the text of the panels is only searched with terms
+	require.Empty(t, search("http_requests_total"))
+
+	require.Empty(t, search("check description:latency"))
+}
+
 func TestGetSortFields(t *testing.T) {
 	t.Run("will prepend 'fields.' to sort fields when they are dashboard fields", func(t *testing.T) {
 		searchReq := &resource.ResourceSearchRequest{
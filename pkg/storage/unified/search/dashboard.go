@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
+	"github.com/blevesearch/bleve/v2/search"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/grafana/grafana/pkg/apimachinery/utils"
@@ -24,6 +27,26 @@ const DASHBOARD_LINK_COUNT = "link_count"
 const DASHBOARD_PANEL_TYPES = "panel_types"
 const DASHBOARD_DS_TYPES = "ds_types"
 const DASHBOARD_TRANSFORMATIONS = "transformation"
+const DASHBOARD_PANELS = "panels"                     // the text of each panel, for full-text search
+const DASHBOARD_TEMPLATE_QUERIES = "template_queries" // the queries defining the template variables
+
+// Keys of the panels indexed in DASHBOARD_PANELS
+const (
+	dashboardPanelID          = "id"
+	dashboardPanelQuery       = "query"
+	dashboardPanelDescription = "description"
+	dashboardPanelContent     = "content"
+)
+
+var dashboardPanelIDField = "fields." + DASHBOARD_PANELS + "." + dashboardPanelID
+
+// dashboardSearchTermFields maps the fields of `field:value` search terms to the index fields.
+var dashboardSearchTermFields = map[string][]string{
+	dashboard.SearchFieldQuery:       {"fields." + DASHBOARD_PANELS + "." + dashboardPanelQuery},
+	dashboard.SearchFieldDescription: {resource.SEARCH_FIELD_DESCRIPTION, "fields." + DASHBOARD_PANELS + "." + dashboardPanelDescription},
+	dashboard.SearchFieldContent:     {"fields." + DASHBOARD_PANELS + "." + dashboardPanelContent},
+	dashboard.SearchFieldVariable:    {"fields." + DASHBOARD_TEMPLATE_QUERIES},
+}
 
 //------------------------------------------------------------
 // The following fields are added in enterprise
@@ -282,8 +305,10 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 	panelTypes := []string{}
 	transformations := []string{}
 	dsTypes := []string{}
+	panels := []map[string]any{}
 
 	for _, p := range summary.Panels {
+		panels = appendPanelText(panels, p)
 		if p.Type != "" {
 			panelTypes = append(panelTypes, p.Type)
 		}
@@ -331,6 +356,12 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 		sort.Strings(transformations)
 		doc.Fields[DASHBOARD_TRANSFORMATIONS] = transformations
 	}
+	if len(panels) > 0 {
+		doc.Fields[DASHBOARD_PANELS] = panels
+	}
+	if len(summary.TemplateQuery) > 0 {
+		doc.Fields[DASHBOARD_TEMPLATE_QUERIES] = summary.TemplateQuery
+	}
 
 	// Add the stats fields
 	for k, v := range s.Stats[summary.UID] {
@@ -340,6 +371,73 @@ func (s *DashboardDocumentBuilder) BuildDocument(ctx context.Context, key *resou
 	return doc, nil
 }
 
+// appendPanelText appends the text of the panel and its collapsed panels. Every panel has an id, so
+// the position of a match in the panels is the position of the id of the panel.
+func appendPanelText(panels []map[string]any, p dashboard.PanelSummaryInfo) []map[string]any {
+	if len(p.Queries) > 0 || p.Description != "" || p.Content != "" {
+		panel := map[string]any{dashboardPanelID: p.ID}
+		if len(p.Queries) > 0 {
+			panel[dashboardPanelQuery] = strings.Join(p.Queries, "\n")
+		}
+		if p.Description != "" {
+			panel[dashboardPanelDescription] = p.Description
+		}
+		if p.Content != "" {
+			panel[dashboardPanelContent] = p.Content
+		}
+		panels = append(panels, panel)
+	}
+	for _, c := range p.Collapsed {
+		panels = appendPanelText(panels, c)
+	}
+	return panels
+}
+
+// dashboardHighlight returns the highlighted fragments matching each search term field, and the ids of
+// the panels where they match.
+func dashboardHighlight(match *search.DocumentMatch) map[string]any {
+	res := map[string]any{}
+	for term, fields := range dashboardSearchTermFields {
+		var fragments []string
+		for _, field := range fields {
+			fragments = append(fragments, match.Fragments[field]...)
+		}
+		if len(fragments) > 0 {
+			res[term] = fragments
+		}
+	}
+
+	var ids []any
+	switch v := match.Fields[dashboardPanelIDField].(type) {
+	case []any:
+		ids = v
+	case nil:
+	default:
+		ids = []any{v}
+	}
+	panels := []int64{}
+	for field, terms := range match.Locations {
+		if !strings.HasPrefix(field, "fields."+DASHBOARD_PANELS+".") {
+			continue
+		}
+		for _, locations := range terms {
+			for _, l := range locations {
+				if len(l.ArrayPositions) == 0 || int(l.ArrayPositions[0]) >= len(ids) {
+					continue
+				}
+				if id, ok := ids[l.ArrayPositions[0]].(float64); ok && !slices.Contains(panels, int64(id)) {
+					panels = append(panels, int64(id))
+				}
+			}
+		}
+	}
+	if len(panels) > 0 {
+		slices.Sort(panels)
+		res["panels"] = panels
+	}
+	return res
+}
+
 func DashboardFields() []string {
 	baseFields := []string{
 		DASHBOARD_LEGACY_ID,
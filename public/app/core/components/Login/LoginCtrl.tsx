@@ -4,7 +4,8 @@ import { FetchError, getBackendSrv, isFetchError, locationService } from '@grafa
 import config from 'app/core/config';
 import { t } from 'app/core/internationalization';
 
-import { LoginDTO, AuthNRedirectDTO } from './types';
+import { LoginDTO, AuthNRedirectDTO, SecondFactorChallenge } from './types';
+import { getAssertion } from './webauthn';
 
 const isOauthEnabled = () => {
   return !!config.oauth && Object.keys(config.oauth).length > 0;
@@ -21,6 +22,11 @@ export interface FormModel {
   email: string;
 }
 
+export interface SecondFactorFormModel {
+  otp?: string;
+  recoveryCode?: string;
+}
+
 export interface PasswordlessFormModel {
   email: string;
 }
@@ -41,6 +47,10 @@ interface Props {
     isChangingPassword: boolean;
     skipPasswordChange: Function;
     login: (data: FormModel) => void;
+    secondFactor: SecondFactorChallenge | undefined;
+    verifySecondFactor: (data: SecondFactorFormModel) => void;
+    verifySecurityKey: () => void;
+    cancelSecondFactor: () => void;
     passwordlessStart: (data: PasswordlessFormModel) => void;
     passwordlessConfirm: (data: PasswordlessConfirmationFormModel) => void;
     showPasswordlessConfirmation: boolean;
@@ -59,10 +69,13 @@ interface State {
   isChangingPassword: boolean;
   showDefaultPasswordWarning: boolean;
   loginErrorMessage?: string;
+  secondFactor?: SecondFactorChallenge;
 }
 
 export class LoginCtrl extends PureComponent<Props, State> {
   result: LoginDTO | undefined;
+  // credentials are kept while the user proves a second factor, the login request is sent again with it.
+  credentials: FormModel | undefined;
 
   constructor(props: Props) {
     super(props);
@@ -105,15 +118,67 @@ export class LoginCtrl extends PureComponent<Props, State> {
   };
 
   login = (formModel: FormModel) => {
+    this.credentials = formModel;
+    this.authenticate({});
+  };
+
+  verifySecondFactor = (secondFactor: SecondFactorFormModel) => {
+    const { totp } = this.state.secondFactor ?? {};
+    this.authenticate(totp ? { ...secondFactor, mfaEnrollmentToken: totp.enrollmentToken } : secondFactor);
+  };
+
+  verifySecurityKey = async () => {
+    this.setState({ loginErrorMessage: undefined, isLoggingIn: true });
+    let webauthn;
+    try {
+      const options = this.state.secondFactor?.webauthn ?? (await this.requestChallenge())?.webauthn;
+      if (!options) {
+        throw new Error('no security key challenge');
+      }
+      webauthn = await getAssertion(options);
+    } catch {
+      this.setState((state) => ({
+        isLoggingIn: false,
+        loginErrorMessage: t('login.mfa.security-key-failed', 'Your security key could not be used, try again'),
+        secondFactor: state.secondFactor && { ...state.secondFactor, webauthn: undefined },
+      }));
+      return;
+    }
+    this.authenticate({ webauthn });
+  };
+
+  // Security key challenges are single use, a failed attempt needs a new one which comes along with
+  // the second factor challenge of a login attempt without any second factor.
+  requestChallenge = async (): Promise<SecondFactorChallenge | undefined> => {
+    try {
+      await getBackendSrv().post('/login', this.credentials, { showErrorAlert: false });
+    } catch (err) {
+      return isFetchError(err) ? getSecondFactorChallenge(err) : undefined;
+    }
+    return undefined;
+  };
+
+  cancelSecondFactor = () => {
+    this.credentials = undefined;
+    this.setState({ loginErrorMessage: undefined, secondFactor: undefined });
+  };
+
+  authenticate = (secondFactor: SecondFactorFormModel & { webauthn?: unknown; mfaEnrollmentToken?: string }) => {
+    const formModel = this.credentials;
+    if (!formModel) {
+      return;
+    }
+
     this.setState({
       loginErrorMessage: undefined,
       isLoggingIn: true,
     });
 
     getBackendSrv()
-      .post<LoginDTO>('/login', formModel, { showErrorAlert: false })
+      .post<LoginDTO>('/login', { ...formModel, ...secondFactor }, { showErrorAlert: false })
       .then((result) => {
         this.result = result;
+        this.credentials = undefined;
         if (formModel.password !== 'admin' || config.ldapEnabled || config.authProxyEnabled) {
           this.toGrafana();
           return;
@@ -122,11 +187,18 @@ export class LoginCtrl extends PureComponent<Props, State> {
         }
       })
       .catch((err) => {
+        const challenge = isFetchError(err) ? getSecondFactorChallenge(err) : undefined;
+        if (challenge) {
+          this.setState({ isLoggingIn: false, secondFactor: challenge });
+          return;
+        }
+
         const fetchErrorMessage = isFetchError(err) ? getErrorMessage(err) : undefined;
-        this.setState({
+        this.setState((state) => ({
           isLoggingIn: false,
           loginErrorMessage: fetchErrorMessage || t('login.error.unknown', 'Unknown error occurred'),
-        });
+          secondFactor: state.secondFactor && { ...state.secondFactor, webauthn: undefined },
+        }));
       });
   };
 
@@ -199,8 +271,17 @@ export class LoginCtrl extends PureComponent<Props, State> {
 
   render() {
     const { children } = this.props;
-    const { isLoggingIn, isChangingPassword, showDefaultPasswordWarning, loginErrorMessage } = this.state;
-    const { login, toGrafana, changePassword, passwordlessStart, passwordlessConfirm } = this;
+    const { isLoggingIn, isChangingPassword, showDefaultPasswordWarning, loginErrorMessage, secondFactor } = this.state;
+    const {
+      login,
+      verifySecondFactor,
+      verifySecurityKey,
+      cancelSecondFactor,
+      toGrafana,
+      changePassword,
+      passwordlessStart,
+      passwordlessConfirm,
+    } = this;
     const { loginHint, passwordHint, disableLoginForm, disableUserSignUp } = config;
 
     return (
@@ -212,6 +293,10 @@ export class LoginCtrl extends PureComponent<Props, State> {
           disableLoginForm,
           disableUserSignUp,
           login,
+          secondFactor,
+          verifySecondFactor,
+          verifySecurityKey,
+          cancelSecondFactor,
           passwordlessStart,
           passwordlessConfirm,
           showPasswordlessConfirmation: showPasswordlessConfirmation(),
@@ -229,12 +314,30 @@ export class LoginCtrl extends PureComponent<Props, State> {
 
 export default LoginCtrl;
 
-function getErrorMessage(err: FetchError<undefined | { messageId?: string; message?: string }>): string | undefined {
+interface LoginErrorData {
+  messageId?: string;
+  message?: string;
+  extra?: Omit<SecondFactorChallenge, 'enrollment'>;
+}
+
+function getSecondFactorChallenge(err: FetchError<undefined | LoginErrorData>): SecondFactorChallenge | undefined {
+  const { messageId, extra } = err.data ?? {};
+  if (!extra || (messageId !== 'mfa.required' && messageId !== 'mfa.enrollment-required')) {
+    return undefined;
+  }
+  return { ...extra, enrollment: messageId === 'mfa.enrollment-required' };
+}
+
+function getErrorMessage(err: FetchError<undefined | LoginErrorData>): string | undefined {
   switch (err.data?.messageId) {
     case 'password-auth.empty':
     case 'password-auth.failed':
     case 'password-auth.invalid':
       return t('login.error.invalid-user-or-password', 'Invalid username or password');
+    case 'mfa.invalid':
+      return t('login.error.mfa-invalid', 'Invalid second factor');
+    case 'mfa.enrollment-not-found':
+      return t('login.error.mfa-enrollment-not-found', 'The enrollment expired, log in again to start over');
     case 'login-attempt.blocked':
       return t(
         'login.error.blocked',
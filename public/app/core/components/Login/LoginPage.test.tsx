@@ -163,4 +163,127 @@ describe('Login Page', () => {
       'You have exceeded the number of login attempts for this user. Please try again later.'
     );
   });
+
+  it('asks for a second factor when the account requires one', async () => {
+    postMock
+      .mockRejectedValueOnce({
+        data: {
+          message: 'A second factor is required to sign in',
+          messageId: 'mfa.required',
+          statusCode: 401,
+          extra: { methods: ['totp', 'recoveryCode'] },
+        },
+        status: 401,
+        statusText: 'Unauthorized',
+      })
+      .mockResolvedValueOnce({ message: 'Logged in' });
+
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    await userEvent.click(screen.getByRole('button', { name: 'Log in' }));
+
+    await userEvent.type(await screen.findByLabelText('Authentication code'), '123456');
+    await userEvent.click(screen.getByRole('button', { name: 'Verify' }));
+
+    await waitFor(() =>
+      expect(postMock).toHaveBeenLastCalledWith(
+        '/login',
+        { password: 'test', user: 'admin', otp: '123456' },
+        { showErrorAlert: false }
+      )
+    );
+  });
+
+  it('signs in with a recovery code', async () => {
+    postMock.mockRejectedValueOnce({
+      data: {
+        message: 'A second factor is required to sign in',
+        messageId: 'mfa.required',
+        statusCode: 401,
+        extra: { methods: ['totp', 'recoveryCode'] },
+      },
+      status: 401,
+      statusText: 'Unauthorized',
+    });
+
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    await userEvent.click(screen.getByRole('button', { name: 'Log in' }));
+
+    await userEvent.click(await screen.findByRole('button', { name: 'Use a recovery code' }));
+    await userEvent.type(screen.getByLabelText('Recovery code'), 'abcd-efgh');
+    await userEvent.click(screen.getByRole('button', { name: 'Verify' }));
+
+    await waitFor(() =>
+      expect(postMock).toHaveBeenLastCalledWith(
+        '/login',
+        { password: 'test', user: 'admin', recoveryCode: 'abcd-efgh' },
+        { showErrorAlert: false }
+      )
+    );
+  });
+
+  it('enrolls an authenticator app when the account must use a second factor', async () => {
+    postMock.mockRejectedValueOnce({
+      data: {
+        message: 'A second factor must be enrolled to sign in',
+        messageId: 'mfa.enrollment-required',
+        statusCode: 401,
+        extra: {
+          methods: ['totp'],
+          totp: { enrollmentToken: 'token', secret: 'JBSWY3DPEHPK3PXP', url: 'otpauth://totp/Grafana:admin' },
+        },
+      },
+      status: 401,
+      statusText: 'Unauthorized',
+    });
+
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    await userEvent.click(screen.getByRole('button', { name: 'Log in' }));
+
+    expect(await screen.findByText('JBSWY3DPEHPK3PXP')).toBeInTheDocument();
+    await userEvent.type(screen.getByLabelText('Authentication code'), '123456');
+    await userEvent.click(screen.getByRole('button', { name: 'Verify' }));
+
+    await waitFor(() =>
+      expect(postMock).toHaveBeenLastCalledWith(
+        '/login',
+        { password: 'test', user: 'admin', otp: '123456', mfaEnrollmentToken: 'token' },
+        { showErrorAlert: false }
+      )
+    );
+  });
+
+  it('shows an error with an invalid second factor', async () => {
+    postMock
+      .mockRejectedValueOnce({
+        data: { messageId: 'mfa.required', statusCode: 401, extra: { methods: ['totp', 'recoveryCode'] } },
+        status: 401,
+        statusText: 'Unauthorized',
+      })
+      .mockRejectedValueOnce({
+        data: { message: 'Invalid second factor', messageId: 'mfa.invalid', statusCode: 401 },
+        status: 401,
+        statusText: 'Unauthorized',
+      });
+
+    render(<LoginPage />);
+
+    await userEvent.type(screen.getByLabelText('Email or username'), 'admin');
+    await userEvent.type(screen.getByLabelText('Password'), 'test');
+    await userEvent.click(screen.getByRole('button', { name: 'Log in' }));
+    await userEvent.type(await screen.findByLabelText('Authentication code'), '000000');
+    await userEvent.click(screen.getByRole('button', { name: 'Verify' }));
+
+    const alert = await screen.findByRole('alert', { name: 'Login failed' });
+    expect(alert).toHaveTextContent('Invalid second factor');
+    expect(screen.getByLabelText('Authentication code')).toBeInTheDocument();
+  });
 });
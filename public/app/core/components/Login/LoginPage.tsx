@@ -16,6 +16,7 @@ import { LoginLayout, InnerBox } from './LoginLayout';
 import { LoginServiceButtons } from './LoginServiceButtons';
 import { PasswordlessConfirmation } from './PasswordlessConfirmationForm';
 import { PasswordlessLoginForm } from './PasswordlessLoginForm';
+import { SecondFactorForm } from './SecondFactorForm';
 import { UserSignup } from './UserSignup';
 
 const LoginPage = () => {
@@ -30,6 +31,10 @@ const LoginPage = () => {
         disableLoginForm,
         disableUserSignUp,
         login,
+        secondFactor,
+        verifySecondFactor,
+        verifySecurityKey,
+        cancelSecondFactor,
         passwordlessStart,
         passwordlessConfirm,
         showPasswordlessConfirmation,
@@ -49,7 +54,17 @@ const LoginPage = () => {
                 </Alert>
               )}
 
-              {!disableLoginForm && !config.auth.passwordlessEnabled && (
+              {secondFactor && (
+                <SecondFactorForm
+                  challenge={secondFactor}
+                  onSubmit={verifySecondFactor}
+                  onSecurityKey={verifySecurityKey}
+                  onCancel={cancelSecondFactor}
+                  isLoggingIn={isLoggingIn}
+                />
+              )}
+
+              {!secondFactor && !disableLoginForm && !config.auth.passwordlessEnabled && (
                 <LoginForm onSubmit={login} loginHint={loginHint} passwordHint={passwordHint} isLoggingIn={isLoggingIn}>
                   <Stack justifyContent="flex-end">
                     {!config.auth.disableLogin && (
@@ -64,11 +79,11 @@ const LoginPage = () => {
                   </Stack>
                 </LoginForm>
               )}
-              {config.auth.passwordlessEnabled && (
+              {!secondFactor && config.auth.passwordlessEnabled && (
                 <PasswordlessLoginForm onSubmit={passwordlessStart} isLoggingIn={isLoggingIn}></PasswordlessLoginForm>
               )}
-              <LoginServiceButtons />
-              {!disableUserSignUp && <UserSignup />}
+              {!secondFactor && <LoginServiceButtons />}
+              {!secondFactor && !disableUserSignUp && <UserSignup />}
             </InnerBox>
           )}
 
@@ -0,0 +1,165 @@
+import { css } from '@emotion/css';
+import { useId, useState } from 'react';
+import { useForm } from 'react-hook-form';
+
+import { GrafanaTheme2 } from '@grafana/data';
+import { selectors } from '@grafana/e2e-selectors';
+import { Button, ClipboardButton, Field, Input, LinkButton, Stack, Text, useStyles2 } from '@grafana/ui';
+import { t, Trans } from 'app/core/internationalization';
+
+import { SecondFactorFormModel } from './LoginCtrl';
+import { SecondFactorChallenge } from './types';
+import { isWebAuthnSupported } from './webauthn';
+
+interface Props {
+  challenge: SecondFactorChallenge;
+  onSubmit: (data: SecondFactorFormModel) => void;
+  onSecurityKey: () => void;
+  onCancel: () => void;
+  isLoggingIn: boolean;
+}
+
+export const SecondFactorForm = ({ challenge, onSubmit, onSecurityKey, onCancel, isLoggingIn }: Props) => {
+  const styles = useStyles2(getStyles);
+  const codeId = useId();
+  const [useRecoveryCode, setUseRecoveryCode] = useState(false);
+  const {
+    handleSubmit,
+    register,
+    formState: { errors },
+  } = useForm<SecondFactorFormModel>({ mode: 'onChange', shouldUnregister: true });
+
+  const { totp } = challenge;
+  const hasTOTP = challenge.methods.includes('totp');
+  const hasSecurityKey = challenge.methods.includes('webauthn') && isWebAuthnSupported();
+  const showCode = challenge.enrollment || hasTOTP || useRecoveryCode;
+
+  return (
+    <div className={styles.wrapper}>
+      <form onSubmit={handleSubmit(onSubmit)}>
+        <Stack direction="column" gap={2}>
+          {challenge.enrollment && totp && (
+            <Stack direction="column" gap={1}>
+              <Text element="p">
+                <Trans i18nKey="login.mfa.enrollment-description">
+                  Your account requires a second factor. Add this account to your authenticator app, then enter the
+                  code it shows.
+                </Trans>
+              </Text>
+              <LinkButton href={totp.url} fill="text" className={styles.enrollmentLink}>
+                {t('login.mfa.enrollment-link', 'Open in authenticator app')}
+              </LinkButton>
+              <Stack alignItems="center">
+                <Text variant="code">{totp.secret}</Text>
+                <ClipboardButton icon="copy" variant="secondary" size="sm" getText={() => totp.secret}>
+                  {t('login.mfa.copy-secret', 'Copy')}
+                </ClipboardButton>
+              </Stack>
+              {totp.recoveryCodes && (
+                <>
+                  <Text element="p">
+                    <Trans i18nKey="login.mfa.recovery-codes-description">
+                      Save these recovery codes somewhere safe. Each of them signs you in once if you lose your
+                      authenticator.
+                    </Trans>
+                  </Text>
+                  <Text variant="code">{totp.recoveryCodes.join(' ')}</Text>
+                </>
+              )}
+            </Stack>
+          )}
+
+          {showCode && (
+            <Field
+              label={
+                useRecoveryCode
+                  ? t('login.mfa.recovery-code-label', 'Recovery code')
+                  : t('login.mfa.otp-label', 'Authentication code')
+              }
+              invalid={!!errors.otp || !!errors.recoveryCode}
+              error={errors.otp?.message || errors.recoveryCode?.message}
+            >
+              {useRecoveryCode ? (
+                <Input
+                  {...register('recoveryCode', {
+                    required: t('login.mfa.recovery-code-required', 'Recovery code is required'),
+                  })}
+                  id={codeId}
+                  autoFocus
+                  autoComplete="off"
+                  autoCapitalize="none"
+                />
+              ) : (
+                <Input
+                  {...register('otp', { required: t('login.mfa.otp-required', 'Authentication code is required') })}
+                  id={codeId}
+                  autoFocus
+                  autoComplete="one-time-code"
+                  inputMode="numeric"
+                  placeholder={t('login.mfa.otp-placeholder', '6-digit code')}
+                />
+              )}
+            </Field>
+          )}
+
+          {showCode && (
+            <Button
+              type="submit"
+              data-testid={selectors.pages.Login.submit}
+              className={styles.submitButton}
+              disabled={isLoggingIn}
+            >
+              {isLoggingIn
+                ? t('login.mfa.verify-loading-label', 'Verifying...')
+                : t('login.mfa.verify-label', 'Verify')}
+            </Button>
+          )}
+
+          {!challenge.enrollment && hasSecurityKey && !useRecoveryCode && (
+            <Button
+              type="button"
+              variant={hasTOTP ? 'secondary' : 'primary'}
+              className={styles.submitButton}
+              disabled={isLoggingIn}
+              onClick={onSecurityKey}
+            >
+              {t('login.mfa.security-key-label', 'Use a security key')}
+            </Button>
+          )}
+
+          <Stack justifyContent="space-between">
+            <Button type="button" fill="text" onClick={onCancel}>
+              {t('login.mfa.cancel-label', 'Back to login')}
+            </Button>
+            {!challenge.enrollment && challenge.methods.includes('recoveryCode') && (
+              <Button type="button" fill="text" onClick={() => setUseRecoveryCode(!useRecoveryCode)}>
+                {useRecoveryCode
+                  ? t('login.mfa.use-second-factor', 'Use your second factor')
+                  : t('login.mfa.use-recovery-code', 'Use a recovery code')}
+              </Button>
+            )}
+          </Stack>
+        </Stack>
+      </form>
+    </div>
+  );
+};
+
+export const getStyles = (theme: GrafanaTheme2) => {
+  return {
+    wrapper: css({
+      width: '100%',
+      paddingBottom: theme.spacing(2),
+    }),
+
+    submitButton: css({
+      justifyContent: 'center',
+      width: '100%',
+    }),
+
+    enrollmentLink: css({
+      alignSelf: 'flex-start',
+      padding: 0,
+    }),
+  };
+};
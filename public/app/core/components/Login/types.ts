@@ -6,3 +6,27 @@ export interface LoginDTO {
 export interface AuthNRedirectDTO {
   URL: string;
 }
+
+export interface WebAuthnRequestOptions {
+  challenge: string;
+  timeout?: number;
+  rpId?: string;
+  userVerification?: UserVerificationRequirement;
+  allowCredentials?: Array<{ type: 'public-key'; id: string; transports?: AuthenticatorTransport[] }>;
+}
+
+export interface TOTPEnrollment {
+  enrollmentToken: string;
+  secret: string;
+  url: string;
+  recoveryCodes?: string[];
+}
+
+// SecondFactorChallenge is returned by the login endpoint when the password is correct but the user
+// must also prove a second factor, or enroll one first.
+export interface SecondFactorChallenge {
+  enrollment: boolean;
+  methods: string[];
+  webauthn?: WebAuthnRequestOptions;
+  totp?: TOTPEnrollment;
+}
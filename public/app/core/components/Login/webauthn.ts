@@ -0,0 +1,59 @@
+import { WebAuthnRequestOptions } from './types';
+
+// The server encodes binary WebAuthn fields as unpadded base64url strings, both ways.
+function decode(value: string): ArrayBuffer {
+  const base64 = value.replace(/-/g, '+').replace(/_/g, '/');
+  const binary = atob(base64.padEnd(base64.length + ((4 - (base64.length % 4)) % 4), '='));
+  const bytes = new Uint8Array(binary.length);
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i);
+  }
+  return bytes.buffer;
+}
+
+function encode(value: ArrayBuffer | null): string | undefined {
+  if (!value) {
+    return undefined;
+  }
+  let binary = '';
+  new Uint8Array(value).forEach((b) => (binary += String.fromCharCode(b)));
+  return btoa(binary).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+export function isWebAuthnSupported(): boolean {
+  return typeof window.PublicKeyCredential !== 'undefined' && !!navigator.credentials;
+}
+
+// getAssertion asks the browser to sign the challenge with one of the security keys of the user and
+// returns the assertion in the format expected by the login endpoint.
+export async function getAssertion(options: WebAuthnRequestOptions) {
+  const credential = await navigator.credentials.get({
+    publicKey: {
+      challenge: decode(options.challenge),
+      timeout: options.timeout,
+      rpId: options.rpId,
+      userVerification: options.userVerification,
+      allowCredentials: options.allowCredentials?.map((c) => ({
+        type: c.type,
+        id: decode(c.id),
+        transports: c.transports,
+      })),
+    },
+  });
+  if (!(credential instanceof PublicKeyCredential)) {
+    throw new Error('no credential');
+  }
+
+  const response = credential.response as AuthenticatorAssertionResponse;
+  return {
+    id: credential.id,
+    rawId: encode(credential.rawId),
+    type: credential.type,
+    response: {
+      clientDataJSON: encode(response.clientDataJSON),
+      authenticatorData: encode(response.authenticatorData),
+      signature: encode(response.signature),
+      userHandle: encode(response.userHandle),
+    },
+  };
+}
@@ -13,6 +13,7 @@ export enum AccessControlAction {
   UsersAuthTokenList = 'users.authtoken:read',
   UsersAuthTokenUpdate = 'users.authtoken:write',
   UsersPasswordUpdate = 'users.password:write',
+  UsersMFAReset = 'users.mfa:reset',
   UsersDelete = 'users:delete',
   UsersCreate = 'users:create',
   UsersEnable = 'users:enable',